@@ -0,0 +1,32 @@
+// Команда shortener-backup создает резервную копию встроенного key-value хранилища коротких ссылок
+//
+// Копия создается при остановленном сервисе:
+//
+//	shortener-backup -kv-storage /var/lib/urlshortener/urlshortener.bolt -o /backup/urlshortener.bolt
+package main
+
+import (
+	"flag"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/repository"
+	"go.uber.org/zap"
+)
+
+var log = zap.Must(zap.NewDevelopment()).Sugar()
+
+func main() {
+	var kvStoragePath, backupPath string
+	flag.StringVar(&kvStoragePath, "kv-storage", "", "Short URL embedded key-value storage file")
+	flag.StringVar(&backupPath, "o", "", "Backup file")
+	flag.Parse()
+
+	if kvStoragePath == "" || backupPath == "" {
+		log.Fatalf("main: -kv-storage and -o flags are required")
+	}
+
+	if err := repository.BackupBoltFile(kvStoragePath, backupPath); err != nil {
+		log.Fatalf("main: failure to backup kv storage: %v", err)
+	}
+
+	log.Infow("main: kv storage backup created", "kvStoragePath", kvStoragePath, "backupPath", backupPath)
+}
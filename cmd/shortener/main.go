@@ -4,10 +4,19 @@ import (
 	"context"
 	"github.com/vkhrushchev/urlshortener/config"
 	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/fallback"
+	"github.com/vkhrushchev/urlshortener/internal/app/geoip"
 	"github.com/vkhrushchev/urlshortener/internal/app/grpc"
+	"github.com/vkhrushchev/urlshortener/internal/app/preview"
+	"github.com/vkhrushchev/urlshortener/internal/app/redisstate"
 	"github.com/vkhrushchev/urlshortener/internal/app/repository"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/throttle"
+	"github.com/vkhrushchev/urlshortener/internal/app/transfer"
 	"github.com/vkhrushchev/urlshortener/internal/app/usecase"
 	"net"
+	"os"
+	"time"
 
 	"github.com/vkhrushchev/urlshortener/internal/app"
 	"github.com/vkhrushchev/urlshortener/internal/app/controller"
@@ -17,6 +26,19 @@ import (
 
 var log = zap.Must(zap.NewDevelopment()).Sugar()
 
+// deleteJobsShutdownTimeout - время ожидания выполнения задач на удаление коротких ссылок при остановке сервиса
+// previewShutdownTimeout - время ожидания завершения обработчиков задач на получение превью страниц
+// trashPurgeInterval - интервал запуска окончательного удаления коротких ссылок из корзины
+// geoIPReloadInterval - интервал проверки изменения файла базы данных GeoIP
+// replicaCheckInterval - интервал проверки доступности реплик базы данных
+const (
+	deleteJobsShutdownTimeout = 10 * time.Second
+	previewShutdownTimeout    = 5 * time.Second
+	trashPurgeInterval        = time.Hour
+	geoIPReloadInterval       = time.Minute
+	replicaCheckInterval      = 5 * time.Second
+)
+
 // buildVersion = определяет версию приложения
 // buildDate = определяет дату сборки
 // buildCommit = определяет коммит сборки
@@ -31,49 +53,146 @@ type shortURLRepository interface {
 	SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error)
 
 	GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
+	GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
 	GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+	GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error)
+	GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+	ForEachShortURL(ctx context.Context, fn func(shortURLEntity entity.ShortURLEntity) error) error
+
+	UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error)
+	UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error
+	ConsumeShortURLClick(ctx context.Context, shortURI string) error
+
+	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error)
+	RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error)
+	PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error)
+
+	SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error
+	GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error)
+	GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error)
 
-	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) error
+	IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error
+	GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error)
+	IncrementCountryClicks(ctx context.Context, shortURI string, country string) error
+	GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error)
+
+	GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error)
+	SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error
 
 	GetStats(ctx context.Context) (urlCount int, userCount int, err error)
 }
 
 func main() {
+	if len(os.Args) > 1 && (os.Args[1] == exportCommand || os.Args[1] == importCommand) {
+		runTransferCommand()
+		return
+	}
+
 	log.Infof("Build version: %s\n", buildVersion)
 	log.Infof("Build date: %s\n", buildDate)
 	log.Infof("Build commit: %s\n", buildCommit)
 
 	shortenerConfig := config.ReadConfig()
+	if !usecase.IsRedirectStatus(shortenerConfig.RedirectStatus) {
+		log.Fatalf("main: unsupported redirect status: %d", shortenerConfig.RedirectStatus)
+	}
+	if shortenerConfig.FallbackURL != "" {
+		if err := fallback.CheckURL(shortenerConfig.FallbackURL); err != nil {
+			log.Fatalf("main: unsupported fallback url: %v", err)
+		}
+	}
+	for _, domain := range shortenerConfig.Domains {
+		if err := shortdomain.CheckDomain(domain); err != nil {
+			log.Fatalf("main: unsupported short url domain: %v", err)
+		}
+	}
+	shortDomains := shortdomain.NewDomains(shortenerConfig.BaseURL, shortenerConfig.Domains)
 	_, trustedSubnet, err := net.ParseCIDR(shortenerConfig.TrustedSubnet)
 	if err != nil {
 		log.Warnf("main: failed to parse trusted subnet: %v", err)
 	}
 
-	dbLookup, err := db.NewDBLookup(shortenerConfig.DatabaseDSN)
-	if err != nil {
-		log.Fatalf("main: error when init DBLookup: %v", err)
-	}
+	dbLookup := initDBLookup(shortenerConfig)
+
+	replicaCheckCtx, cancelReplicaCheck := context.WithCancel(context.Background())
+	defer cancelReplicaCheck()
+	go dbLookup.RunReplicaHealthCheck(replicaCheckCtx, replicaCheckInterval)
 
-	shortURLRepo := initShortURLRepository(dbLookup, shortenerConfig)
+	redisClient := initRedisClient(shortenerConfig)
 
-	createShortURLUseCase := usecase.NewCreateShortURLUseCase(shortURLRepo)
+	shortURLRepo := initShortURLRepository(dbLookup, redisClient, shortenerConfig)
+
+	var previewShortURLUseCase *usecase.PreviewShortURLUseCase
+	createShortURLUseCase := usecase.NewCreateShortURLUseCase(shortURLRepo, nil, shortDomains)
+	if !shortenerConfig.DisablePreviewFetch {
+		previewFetcher := preview.NewFetcher(preview.TimeoutDefault, preview.MaxBodySizeDefault)
+		previewShortURLUseCase = usecase.NewPreviewShortURLUseCase(shortURLRepo, previewFetcher)
+		previewShortURLUseCase.Start()
+		createShortURLUseCase = usecase.NewCreateShortURLUseCase(shortURLRepo, previewShortURLUseCase, shortDomains)
+	}
 	getShortURLUseCase := usecase.NewGetShortURLUseCase(shortURLRepo)
-	deleteShortURLUseCase := usecase.NewDeleteShortURLUseCase(shortURLRepo)
+	unlockShortURLUseCase := usecase.NewUnlockShortURLUseCase(shortURLRepo)
+	if redisClient != nil {
+		unlockShortURLUseCase = usecase.NewUnlockShortURLUseCaseWithLimiters(
+			shortURLRepo,
+			throttle.NewSharedLimiter(redisClient, "password_client", usecase.PasswordClientAttemptsMax, usecase.PasswordAttemptsWindow),
+			throttle.NewSharedLimiter(redisClient, "password_short_url", usecase.PasswordShortURLAttemptsMax, usecase.PasswordAttemptsWindow),
+		)
+	}
+	updateShortURLUseCase := usecase.NewUpdateShortURLUseCase(shortURLRepo)
+	deleteShortURLUseCase := usecase.NewDeleteShortURLUseCase(shortURLRepo, shortURLRepo)
+	if err := deleteShortURLUseCase.Start(context.Background()); err != nil {
+		log.Fatalf("main: failure to start DeleteShortURLUseCase: %v", err)
+	}
+	splitUseCase := usecase.NewSplitUseCase(shortURLRepo, shortURLRepo)
+	countryStatsUseCase := usecase.NewCountryStatsUseCase(shortURLRepo, shortURLRepo)
 	statsUseCase := usecase.NewStatsUseCase(shortURLRepo)
+	userSettingsUseCase := usecase.NewUserSettingsUseCase(shortURLRepo)
+	fallbackUseCase := usecase.NewFallbackUseCase(shortURLRepo, shortenerConfig.FallbackURL)
+
+	purgeCtx, cancelPurge := context.WithCancel(context.Background())
+	defer cancelPurge()
+	if shortenerConfig.TrashRetention.Duration > 0 {
+		purgeShortURLUseCase := usecase.NewPurgeShortURLUseCase(shortURLRepo, shortenerConfig.TrashRetention.Duration)
+		go purgeShortURLUseCase.Run(purgeCtx, trashPurgeInterval)
+	}
+
+	var countryResolver *geoip.Resolver
+	geoIPCtx, cancelGeoIP := context.WithCancel(context.Background())
+	defer cancelGeoIP()
+	appController := controller.NewAppController(
+		shortDomains, shortenerConfig.RedirectStatus, shortenerConfig.InactiveURL, createShortURLUseCase, getShortURLUseCase, unlockShortURLUseCase, splitUseCase, nil, countryStatsUseCase, fallbackUseCase)
+	if shortenerConfig.GeoIPDatabasePath != "" {
+		countryResolver, err = geoip.NewResolver(shortenerConfig.GeoIPDatabasePath)
+		if err != nil {
+			log.Fatalf("main: failure to init GeoIP resolver: %v", err)
+		}
+		go countryResolver.Run(geoIPCtx, geoIPReloadInterval)
 
-	appController := controller.NewAppController(shortenerConfig.BaseURL, createShortURLUseCase, getShortURLUseCase)
+		appController = controller.NewAppController(
+			shortDomains, shortenerConfig.RedirectStatus, shortenerConfig.InactiveURL, createShortURLUseCase, getShortURLUseCase, unlockShortURLUseCase, splitUseCase, countryResolver, countryStatsUseCase, fallbackUseCase)
+	}
+	thirdPartyImporter := transfer.NewThirdPartyImporter(createShortURLUseCase)
 	apiController := controller.NewAPIController(
-		shortenerConfig.BaseURL, createShortURLUseCase, getShortURLUseCase, deleteShortURLUseCase)
-	healthController := controller.NewHealthController(dbLookup)
-	internalController := controller.NewInternalController(statsUseCase)
+		shortDomains, createShortURLUseCase, getShortURLUseCase, updateShortURLUseCase, deleteShortURLUseCase, splitUseCase, countryStatsUseCase, userSettingsUseCase, thirdPartyImporter)
+	healthController := controller.NewHealthController(dbLookup, nil)
+	if cachedShortURLRepo, ok := shortURLRepo.(*repository.CachedShortURLRepository); ok {
+		healthController = controller.NewHealthController(dbLookup, cachedShortURLRepo)
+	}
+	internalController := controller.NewInternalController(
+		statsUseCase, transfer.NewExporter(shortURLRepo), transfer.NewImporter(shortURLRepo))
 
 	grpcShortenerServiceServer := grpc.NewShortenerServiceServer(
 		createShortURLUseCase,
 		getShortURLUseCase,
+		unlockShortURLUseCase,
+		updateShortURLUseCase,
 		deleteShortURLUseCase,
+		splitUseCase,
+		userSettingsUseCase,
 		statsUseCase,
 		dbLookup,
-		shortenerConfig.BaseURL,
+		shortDomains,
 	)
 
 	shortenerApp := app.NewURLShortenerApp(
@@ -100,13 +219,99 @@ func main() {
 	log.Infow("main: URLShortenerApp HTTP shutting down")
 	<-gracefulGRPCShutdownChan
 	log.Infow("main: URLShortenerApp GRPC shutting down")
+
+	cancelPurge()
+	cancelReplicaCheck()
+
+	if countryResolver != nil {
+		cancelGeoIP()
+		if err := countryResolver.Close(); err != nil {
+			log.Warnf("main: error when close GeoIP database: %v", err)
+		}
+	}
+
+	deleteJobsShutdownCtx, cancel := context.WithTimeout(context.Background(), deleteJobsShutdownTimeout)
+	defer cancel()
+	if err := deleteShortURLUseCase.Shutdown(deleteJobsShutdownCtx); err != nil {
+		log.Warnf("main: pending delete jobs are persisted and will be resumed on next start: %v", err)
+	}
+	log.Infow("main: DeleteShortURLUseCase shutting down")
+
+	if previewShortURLUseCase != nil {
+		previewShutdownCtx, cancel := context.WithTimeout(context.Background(), previewShutdownTimeout)
+		defer cancel()
+		if err := previewShortURLUseCase.Shutdown(previewShutdownCtx); err != nil {
+			log.Warnf("main: preview workers are not stopped: %v", err)
+		}
+		log.Infow("main: PreviewShortURLUseCase shutting down")
+	}
+
+	if cachedShortURLRepo, ok := shortURLRepo.(*repository.CachedShortURLRepository); ok {
+		hits, misses := cachedShortURLRepo.CacheStats()
+		log.Infow("main: CachedShortURLRepository stats", "hits", hits, "misses", misses)
+	}
+
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			log.Warnf("main: error when close redis client: %v", err)
+		}
+	}
+
+	if err := dbLookup.Close(); err != nil {
+		log.Warnf("main: error when close database: %v", err)
+	}
 }
 
-func initShortURLRepository(dbLookup *db.DBLookup, config config.Config) shortURLRepository {
+func initDBLookup(config config.Config) *db.DBLookup {
+	dbLookup, err := db.NewDBLookupWithReplicas(config.DatabaseDSN, db.PoolConfig{
+		MaxConns:         int32(config.DBMaxConns),
+		MinConns:         int32(config.DBMinConns),
+		MaxConnLifetime:  config.DBConnMaxLifetime.Duration,
+		MaxConnIdleTime:  config.DBConnMaxIdleTime.Duration,
+		StatementTimeout: config.DBStatementTimeout.Duration,
+		ReadRetries:      config.DBReadRetries,
+	}, db.ReplicaConfig{
+		DSNs:              config.DatabaseReplicaDSNs,
+		PrimaryReadWindow: config.DBReplicaReadWindow.Duration,
+	})
+	if err != nil {
+		log.Fatalf("main: error when init DBLookup: %v", err)
+	}
+
+	return dbLookup
+}
+
+func initRedisClient(config config.Config) *redisstate.Client {
+	if config.RedisURL == "" {
+		return nil
+	}
+
+	redisClient, err := redisstate.NewClient(config.RedisURL, config.RedisKeyPrefix)
+	if err != nil {
+		log.Fatalf("main: failure to init redis client: %v", err)
+	}
+	if err := redisClient.Ping(context.Background()); err != nil {
+		log.Warnf("main: redis is unavailable, shared state falls back to local: %v", err)
+	}
+
+	return redisClient
+}
+
+func initShortURLRepository(dbLookup *db.DBLookup, redisClient *redisstate.Client, config config.Config) shortURLRepository {
 	var repo shortURLRepository
 	var err error
 
-	if config.DatabaseDSN != "" {
+	if db.IsSQLiteDSN(config.DatabaseDSN) {
+		repo = repository.NewSQLiteShortURLRepository(dbLookup)
+		err = dbLookup.InitDB(context.Background())
+		if err != nil {
+			log.Fatalf("main: failure to init database scheme: %v", err)
+		}
+
+		log.Infow("main: success init of SQLiteShortURLRepository")
+	}
+
+	if repo == nil && config.DatabaseDSN != "" {
 		repo = repository.NewDBShortURLRepository(dbLookup)
 		err = dbLookup.InitDB(context.Background())
 		if err != nil {
@@ -116,6 +321,15 @@ func initShortURLRepository(dbLookup *db.DBLookup, config config.Config) shortUR
 		log.Infow("main: success init of DBShortURLRepository")
 	}
 
+	if repo == nil && config.KVStoragePath != "" {
+		repo, err = repository.NewBoltShortURLRepository(config.KVStoragePath)
+		if err != nil {
+			log.Fatalf("main: failure to init BoltShortURLRepository: %v", err)
+		}
+
+		log.Infow("main: success init of BoltShortURLRepository")
+	}
+
 	if repo == nil && config.FileStoragePath != "" {
 		repo, err = repository.NewJSONFileShortURLRepository(config.FileStoragePath)
 		if err != nil {
@@ -131,5 +345,21 @@ func initShortURLRepository(dbLookup *db.DBLookup, config config.Config) shortUR
 		log.Infow("main: success init of InMemoryShortURLRepository")
 	}
 
+	// кэш в памяти процесса не очищается при изменении коротких ссылок другими экземплярами сервиса,
+	// поэтому при общем кэше в Redis он не используется
+	if redisClient != nil {
+		repo = repository.NewRedisClickRepository(repo, redisClient)
+		repo = repository.NewRedisCachedShortURLRepository(repo, redisClient, config.CacheTTL.Duration)
+
+		log.Infow("main: success init of Redis shared state", "ttl", config.CacheTTL.Duration)
+		if config.CacheSize > 0 {
+			log.Warnw("main: in-process short url cache is disabled in favor of Redis shared cache", "size", config.CacheSize)
+		}
+	} else if config.CacheSize > 0 {
+		repo = repository.NewCachedShortURLRepository(repo, config.CacheSize, config.CacheTTL.Duration)
+
+		log.Infow("main: success init of CachedShortURLRepository", "size", config.CacheSize, "ttl", config.CacheTTL.Duration)
+	}
+
 	return repo
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/vkhrushchev/urlshortener/config"
+	"github.com/vkhrushchev/urlshortener/internal/app/transfer"
+)
+
+// exportCommand - команда выгрузки всех коротких ссылок хранилища
+// importCommand - команда загрузки коротких ссылок в хранилище
+//
+// Хранилище выбирается теми же флагами и переменными окружения, что и при запуске сервиса:
+//
+//	shortener export -d postgres://... -format csv -o short_urls.csv
+//	shortener import -kv-storage urlshortener.bolt -format csv -i short_urls.csv -dry-run
+//
+// Встроенное key-value хранилище открывается только одним процессом, поэтому для него сервис должен быть остановлен.
+const (
+	exportCommand = "export"
+	importCommand = "import"
+)
+
+func runTransferCommand() {
+	command := os.Args[1]
+	os.Args = append(os.Args[:1], os.Args[2:]...)
+
+	var format, outputPath, inputPath, conflict string
+	var dryRun bool
+	flag.StringVar(&format, "format", transfer.FormatJSONLines, "Transfer format: jsonl or csv")
+	if command == exportCommand {
+		flag.StringVar(&outputPath, "o", "", "Export file, stdout if empty")
+	} else {
+		flag.StringVar(&inputPath, "i", "", "Import file, stdin if empty")
+		flag.BoolVar(&dryRun, "dry-run", false, "Validate import without saving short URLs")
+		flag.StringVar(&conflict, "conflict", transfer.ConflictSkip, "Conflict policy: skip or fail")
+	}
+
+	shortenerConfig := config.ReadConfig()
+	if err := transfer.CheckFormat(format); err != nil {
+		log.Fatalf("main: %v", err)
+	}
+
+	dbLookup := initDBLookup(shortenerConfig)
+	redisClient := initRedisClient(shortenerConfig)
+	shortURLRepo := initShortURLRepository(dbLookup, redisClient, shortenerConfig)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	var err error
+	if command == exportCommand {
+		err = runExport(ctx, transfer.NewExporter(shortURLRepo), outputPath, format)
+	} else {
+		err = runImport(ctx, transfer.NewImporter(shortURLRepo), inputPath, transfer.ImportOptions{
+			Format:   format,
+			DryRun:   dryRun,
+			Conflict: conflict,
+		})
+	}
+
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			log.Warnf("main: error when close redis client: %v", err)
+		}
+	}
+	if err := dbLookup.Close(); err != nil {
+		log.Warnf("main: error when close database: %v", err)
+	}
+
+	if err != nil {
+		log.Fatalf("main: failure to %s short urls: %v", command, err)
+	}
+}
+
+func runExport(ctx context.Context, exporter *transfer.Exporter, outputPath string, format string) (err error) {
+	var w io.Writer = os.Stdout
+	if outputPath != "" {
+		file, createErr := os.Create(outputPath)
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+
+		w = file
+	}
+
+	exportedCount, err := exporter.Export(ctx, w, format)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("main: short urls exported", "exported", exportedCount, "output", outputPath)
+
+	return nil
+}
+
+func runImport(ctx context.Context, importer *transfer.Importer, inputPath string, options transfer.ImportOptions) error {
+	var r io.Reader = os.Stdin
+	if inputPath != "" {
+		file, err := os.Open(inputPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	result, err := importer.Import(ctx, r, options)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("main: short urls imported",
+		"read", result.Read,
+		"imported", result.Imported,
+		"existing", result.Existing,
+		"conflicts", result.Conflicts,
+		"dryRun", options.DryRun)
+
+	return nil
+}
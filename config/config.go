@@ -8,6 +8,8 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var log = zap.Must(zap.NewDevelopment()).Sugar()
@@ -17,8 +19,42 @@ const (
 	baseURLDefault  = "http://localhost:8080"
 	grpcAddrDefault = "localhost:18080"
 	saltDefault     = "ACKaRDistERI"
+
+	trashRetentionDefault      = 30 * 24 * time.Hour
+	redirectStatusDefault      = 307
+	cacheTTLDefault            = time.Minute
+	dbReadRetriesDefault       = 2
+	dbReplicaReadWindowDefault = 5 * time.Second
+	redisKeyPrefixDefault      = "urlshortener:"
 )
 
+// Duration - обертка над time.Duration, которая в конфигурационном файле задается строкой вида "720h"
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON разбирает Duration из json-строки в формате time.ParseDuration
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var durationString string
+	if err := json.Unmarshal(data, &durationString); err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(durationString)
+	if err != nil {
+		return err
+	}
+
+	d.Duration = duration
+
+	return nil
+}
+
+// MarshalJSON представляет Duration в виде json-строки в формате time.Duration.String
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Duration.String())
+}
+
 // Config - структура с описанием конфигурации
 type Config struct {
 	RunAddr         string `json:"server_address"`
@@ -29,6 +65,57 @@ type Config struct {
 	EnableHTTPS     bool   `json:"enable_https"`
 	GRPCAddr        string `json:"grpc_address"`
 	Salt            string `json:"salt"`
+	// TrashRetention - срок хранения удаленных коротких ссылок в корзине, 0 - хранить бессрочно
+	TrashRetention Duration `json:"trash_retention"`
+	// DisablePreviewFetch - отключает получение превью страниц исходных URL при создании коротких ссылок
+	DisablePreviewFetch bool `json:"disable_preview_fetch"`
+	// RedirectStatus - код ответа перенаправления по умолчанию для коротких ссылок без собственного кода
+	RedirectStatus int `json:"redirect_status"`
+	// GeoIPDatabasePath - путь до файла базы данных GeoIP в формате MaxMind DB для правил таргетинга по стране,
+	// пустая строка - страна клиента не определяется
+	GeoIPDatabasePath string `json:"geoip_database_path"`
+	// InactiveURL - адрес перенаправления при переходе по короткой ссылке вне периода ее действия,
+	// пустая строка - до начала периода возвращается страница "ссылка еще не активна", после окончания - 410
+	InactiveURL string `json:"inactive_url"`
+	// FallbackURL - адрес перенаправления по умолчанию для удаленной, истекшей, исчерпавшей лимит переходов
+	// или заблокированной короткой ссылки, если он не задан для ссылки и в настройках ее владельца
+	FallbackURL string `json:"fallback_url"`
+	// Domains - дополнительные домены коротких ссылок, обслуживаемые наряду с доменом из BaseURL
+	Domains []string `json:"domains"`
+	// KVStoragePath - путь до файла встроенного key-value хранилища коротких ссылок,
+	// используется, если не задан DatabaseDSN
+	KVStoragePath string `json:"kv_storage_path"`
+	// CacheSize - максимальное количество коротких ссылок в кэше поиска по короткому URI в памяти процесса,
+	// 0 - кэш отключен. Не используется, если задан RedisURL: кэш в памяти процесса не очищается при изменении
+	// коротких ссылок другими экземплярами сервиса, поэтому вместо него используется общий кэш в Redis
+	CacheSize int `json:"cache_size"`
+	// CacheTTL - время жизни записи кэша поиска коротких ссылок
+	CacheTTL Duration `json:"cache_ttl"`
+	// RedisURL - адрес Redis вида "redis://[[user]:password@]host:port[/db]" для кэша поиска коротких ссылок
+	// и счетчиков, общих для экземпляров сервиса, пустая строка - состояние хранится в памяти процесса
+	RedisURL string `json:"redis_url"`
+	// RedisKeyPrefix - префикс ключей Redis
+	RedisKeyPrefix string `json:"redis_key_prefix"`
+	// DBMaxConns - максимальное количество соединений пула с базой данных Postgres, 0 - значение по умолчанию
+	DBMaxConns int `json:"db_max_conns"`
+	// DBMinConns - количество соединений пула с базой данных Postgres, поддерживаемых открытыми
+	DBMinConns int `json:"db_min_conns"`
+	// DBConnMaxLifetime - время, после которого соединение с базой данных Postgres закрывается, 0 - значение по умолчанию
+	DBConnMaxLifetime Duration `json:"db_conn_max_lifetime"`
+	// DBConnMaxIdleTime - время простоя, после которого соединение с базой данных Postgres закрывается,
+	// 0 - значение по умолчанию
+	DBConnMaxIdleTime Duration `json:"db_conn_max_idle_time"`
+	// DBStatementTimeout - максимальное время выполнения запроса к базе данных Postgres, 0 - без ограничения
+	DBStatementTimeout Duration `json:"db_statement_timeout"`
+	// DBReadRetries - количество повторов чтения из базы данных Postgres при ошибках сериализации и соединения,
+	// 0 - без повторов
+	DBReadRetries int `json:"db_read_retries"`
+	// DatabaseReplicaDSNs - строки подключения к репликам базы данных Postgres, на которых выполняется поиск
+	// коротких ссылок и статистика
+	DatabaseReplicaDSNs []string `json:"database_replica_dsns"`
+	// DBReplicaReadWindow - время после изменения коротких ссылок пользователем, в течение которого его чтения
+	// через этот экземпляр сервиса выполняются на основной базе данных
+	DBReplicaReadWindow Duration `json:"db_replica_read_window"`
 }
 
 // ReadConfig - считывает конфигурацию из переменных окружения, параметров командной строки и конфигурационного файла
@@ -44,13 +131,14 @@ func ReadConfig() Config {
 	log.Debugw("config: ", "flagConfig", flagConfig)
 
 	var config Config
+	var jsonKeys map[string]bool
 	if configFilePath != "" {
-		parseJSONConfig(&config, configFilePath)
+		jsonKeys = parseJSONConfig(&config, configFilePath)
 	}
 
 	log.Debugw("config: ", "config", config)
 
-	overrideConfigByFlags(&config, &flagConfig)
+	overrideConfigByFlags(&config, &flagConfig, jsonKeys)
 	log.Debugw("overrideConfigByFlags: ", "config", config)
 
 	overrideConfigByEnv(&config)
@@ -63,18 +151,46 @@ func parseFlags(config *Config, configFilePath *string) {
 	flag.StringVar(&config.RunAddr, "a", runAddrDefault, "HTTP listen address")
 	flag.StringVar(&config.BaseURL, "b", baseURLDefault, "Base URL")
 	flag.StringVar(&config.FileStoragePath, "f", "", "Short URL JSON storage")
-	flag.StringVar(&config.DatabaseDSN, "d", "", "Database DSN")
+	flag.StringVar(&config.DatabaseDSN, "d", "", "Database DSN, \"sqlite://<path>\" - SQLite database file")
+	flag.StringVar(&config.KVStoragePath, "kv-storage", "", "Short URL embedded key-value storage file")
 	flag.BoolVar(&config.EnableHTTPS, "s", false, "Enable HTTPS")
 	flag.StringVar(&config.TrustedSubnet, "t", "", "Trusted Subnet")
 	flag.StringVar(configFilePath, "c", "", "Configuration file")
 	flag.StringVar(configFilePath, "config", "", "Configuration file")
 	flag.StringVar(&config.GRPCAddr, "grpc-addr", grpcAddrDefault, "gRPC listen address")
 	flag.StringVar(&config.Salt, "salt", saltDefault, "Salt used for authentication")
+	flag.DurationVar(&config.TrashRetention.Duration, "trash-retention", trashRetentionDefault, "Retention of deleted short URLs, 0 disables purge")
+	flag.BoolVar(&config.DisablePreviewFetch, "disable-preview-fetch", false, "Disable fetching of page previews for original URLs")
+	flag.StringVar(&config.GeoIPDatabasePath, "geoip-db", "", "GeoIP database file in MaxMind DB format")
+	flag.StringVar(&config.InactiveURL, "inactive-url", "", "Redirect URL for short URLs outside of their activity window")
+	flag.StringVar(&config.FallbackURL, "fallback-url", "", "Default fallback URL for deleted, expired, exhausted or disabled short URLs")
+	flag.Func("domains", "Comma-separated additional short URL domains", func(domains string) error {
+		config.Domains = splitDomains(domains)
+		return nil
+	})
+	flag.IntVar(&config.RedirectStatus, "redirect-status", redirectStatusDefault, "Default redirect status code: 301, 302, 307 or 308")
+	flag.IntVar(&config.CacheSize, "cache-size", 0, "Max number of cached short URL lookups, 0 disables cache")
+	flag.DurationVar(&config.CacheTTL.Duration, "cache-ttl", cacheTTLDefault, "Time to live of cached short URL lookups")
+	flag.StringVar(&config.RedisURL, "redis-url", "", "Redis URL for shared cache and counters")
+	flag.StringVar(&config.RedisKeyPrefix, "redis-key-prefix", redisKeyPrefixDefault, "Redis key prefix")
+	flag.IntVar(&config.DBMaxConns, "db-max-conns", 0, "Max number of Postgres pool connections, 0 uses default")
+	flag.IntVar(&config.DBMinConns, "db-min-conns", 0, "Min number of open Postgres pool connections")
+	flag.DurationVar(&config.DBConnMaxLifetime.Duration, "db-conn-max-lifetime", 0, "Max lifetime of Postgres connection, 0 uses default")
+	flag.DurationVar(&config.DBConnMaxIdleTime.Duration, "db-conn-max-idle-time", 0, "Max idle time of Postgres connection, 0 uses default")
+	flag.DurationVar(&config.DBStatementTimeout.Duration, "db-statement-timeout", 0, "Postgres statement timeout, 0 disables timeout")
+	flag.IntVar(&config.DBReadRetries, "db-read-retries", dbReadRetriesDefault, "Number of Postgres read retries on serialization and connection errors")
+	flag.Func("d-replica", "Postgres read replica DSN, may be repeated", func(replicaDSN string) error {
+		config.DatabaseReplicaDSNs = append(config.DatabaseReplicaDSNs, replicaDSN)
+		return nil
+	})
+	flag.DurationVar(&config.DBReplicaReadWindow.Duration, "db-replica-read-window", dbReplicaReadWindowDefault, "Time after user's write when user's reads go to Postgres primary")
 
 	flag.Parse()
 }
 
-func parseJSONConfig(config *Config, configFilePath string) {
+// parseJSONConfig считывает конфигурацию из файла configFilePath и возвращает ключи, заданные в файле,
+// чтобы отличить параметр, заданный нулевым значением, от незаданного
+func parseJSONConfig(config *Config, configFilePath string) map[string]bool {
 	f, err := os.Open(configFilePath)
 	if err != nil {
 		log.Fatalf("config: error opening config file: %v", err)
@@ -86,12 +202,32 @@ func parseJSONConfig(config *Config, configFilePath string) {
 		}
 	}(f)
 
-	if err := json.NewDecoder(f).Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+	var rawConfig map[string]json.RawMessage
+	if err := json.NewDecoder(f).Decode(&rawConfig); err != nil && !errors.Is(err, io.EOF) {
+		log.Fatalf("config: error parsing config file: %v", err)
+	}
+
+	jsonKeys := make(map[string]bool, len(rawConfig))
+	for key := range rawConfig {
+		jsonKeys[key] = true
+	}
+
+	rawConfigJSON, err := json.Marshal(rawConfig)
+	if err != nil {
 		log.Fatalf("config: error parsing config file: %v", err)
 	}
+	if err := json.Unmarshal(rawConfigJSON, config); err != nil {
+		log.Fatalf("config: error parsing config file: %v", err)
+	}
+
+	return jsonKeys
 }
 
-func overrideConfigByFlags(config *Config, flagConfig *Config) {
+// overrideConfigByFlags заполняет параметры, не заданные в конфигурационном файле, значениями параметров командной строки
+//
+// Параметры, для которых нулевое значение имеет смысл, например TrashRetention, заменяются, только если их ключа
+// нет в конфигурационном файле, ключи которого переданы в jsonKeys.
+func overrideConfigByFlags(config *Config, flagConfig *Config, jsonKeys map[string]bool) {
 	if config.RunAddr == "" {
 		config.RunAddr = flagConfig.RunAddr
 	}
@@ -108,6 +244,10 @@ func overrideConfigByFlags(config *Config, flagConfig *Config) {
 		config.DatabaseDSN = flagConfig.DatabaseDSN
 	}
 
+	if config.KVStoragePath == "" {
+		config.KVStoragePath = flagConfig.KVStoragePath
+	}
+
 	if config.TrustedSubnet == "" {
 		config.TrustedSubnet = flagConfig.TrustedSubnet
 	}
@@ -123,6 +263,82 @@ func overrideConfigByFlags(config *Config, flagConfig *Config) {
 	if config.Salt == "" {
 		config.Salt = flagConfig.Salt
 	}
+
+	if !jsonKeys["trash_retention"] {
+		config.TrashRetention = flagConfig.TrashRetention
+	}
+
+	if !config.DisablePreviewFetch {
+		config.DisablePreviewFetch = flagConfig.DisablePreviewFetch
+	}
+
+	if config.RedirectStatus == 0 {
+		config.RedirectStatus = flagConfig.RedirectStatus
+	}
+
+	if config.GeoIPDatabasePath == "" {
+		config.GeoIPDatabasePath = flagConfig.GeoIPDatabasePath
+	}
+
+	if config.InactiveURL == "" {
+		config.InactiveURL = flagConfig.InactiveURL
+	}
+
+	if config.FallbackURL == "" {
+		config.FallbackURL = flagConfig.FallbackURL
+	}
+
+	if len(config.Domains) == 0 {
+		config.Domains = flagConfig.Domains
+	}
+
+	if config.CacheSize == 0 {
+		config.CacheSize = flagConfig.CacheSize
+	}
+
+	if config.CacheTTL.Duration == 0 {
+		config.CacheTTL = flagConfig.CacheTTL
+	}
+
+	if config.RedisURL == "" {
+		config.RedisURL = flagConfig.RedisURL
+	}
+
+	if config.RedisKeyPrefix == "" {
+		config.RedisKeyPrefix = flagConfig.RedisKeyPrefix
+	}
+
+	if config.DBMaxConns == 0 {
+		config.DBMaxConns = flagConfig.DBMaxConns
+	}
+
+	if config.DBMinConns == 0 {
+		config.DBMinConns = flagConfig.DBMinConns
+	}
+
+	if config.DBConnMaxLifetime.Duration == 0 {
+		config.DBConnMaxLifetime = flagConfig.DBConnMaxLifetime
+	}
+
+	if config.DBConnMaxIdleTime.Duration == 0 {
+		config.DBConnMaxIdleTime = flagConfig.DBConnMaxIdleTime
+	}
+
+	if config.DBStatementTimeout.Duration == 0 {
+		config.DBStatementTimeout = flagConfig.DBStatementTimeout
+	}
+
+	if !jsonKeys["db_read_retries"] {
+		config.DBReadRetries = flagConfig.DBReadRetries
+	}
+
+	if len(config.DatabaseReplicaDSNs) == 0 {
+		config.DatabaseReplicaDSNs = flagConfig.DatabaseReplicaDSNs
+	}
+
+	if config.DBReplicaReadWindow.Duration == 0 {
+		config.DBReplicaReadWindow = flagConfig.DBReplicaReadWindow
+	}
 }
 
 func overrideConfigByEnv(config *Config) {
@@ -142,6 +358,10 @@ func overrideConfigByEnv(config *Config) {
 		config.DatabaseDSN = databaseDSNEnv
 	}
 
+	if kvStoragePathEnv, ok := os.LookupEnv("KV_STORAGE_PATH"); ok && kvStoragePathEnv != "" {
+		config.KVStoragePath = kvStoragePathEnv
+	}
+
 	if enableHTTPSEnv, ok := os.LookupEnv("ENABLE_HTTPS"); ok && enableHTTPSEnv != "" {
 		var err error
 		config.EnableHTTPS, err = strconv.ParseBool(enableHTTPSEnv)
@@ -161,4 +381,153 @@ func overrideConfigByEnv(config *Config) {
 	if saltEnv, ok := os.LookupEnv("SHORTENER_SALT"); ok && saltEnv != "" {
 		config.Salt = saltEnv
 	}
+
+	if trashRetentionEnv, ok := os.LookupEnv("TRASH_RETENTION"); ok && trashRetentionEnv != "" {
+		var err error
+		config.TrashRetention.Duration, err = time.ParseDuration(trashRetentionEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing TRASH_RETENTION env variable: %v", err)
+		}
+	}
+
+	if disablePreviewFetchEnv, ok := os.LookupEnv("DISABLE_PREVIEW_FETCH"); ok && disablePreviewFetchEnv != "" {
+		var err error
+		config.DisablePreviewFetch, err = strconv.ParseBool(disablePreviewFetchEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing DISABLE_PREVIEW_FETCH env variable: %v", err)
+		}
+	}
+
+	if redirectStatusEnv, ok := os.LookupEnv("REDIRECT_STATUS"); ok && redirectStatusEnv != "" {
+		var err error
+		config.RedirectStatus, err = strconv.Atoi(redirectStatusEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing REDIRECT_STATUS env variable: %v", err)
+		}
+	}
+
+	if geoIPDatabasePathEnv, ok := os.LookupEnv("GEOIP_DATABASE_PATH"); ok && geoIPDatabasePathEnv != "" {
+		config.GeoIPDatabasePath = geoIPDatabasePathEnv
+	}
+
+	if inactiveURLEnv, ok := os.LookupEnv("INACTIVE_URL"); ok && inactiveURLEnv != "" {
+		config.InactiveURL = inactiveURLEnv
+	}
+
+	if fallbackURLEnv, ok := os.LookupEnv("FALLBACK_URL"); ok && fallbackURLEnv != "" {
+		config.FallbackURL = fallbackURLEnv
+	}
+
+	if domainsEnv, ok := os.LookupEnv("SHORT_DOMAINS"); ok && domainsEnv != "" {
+		config.Domains = splitDomains(domainsEnv)
+	}
+
+	if cacheSizeEnv, ok := os.LookupEnv("CACHE_SIZE"); ok && cacheSizeEnv != "" {
+		var err error
+		config.CacheSize, err = strconv.Atoi(cacheSizeEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing CACHE_SIZE env variable: %v", err)
+		}
+	}
+
+	if cacheTTLEnv, ok := os.LookupEnv("CACHE_TTL"); ok && cacheTTLEnv != "" {
+		var err error
+		config.CacheTTL.Duration, err = time.ParseDuration(cacheTTLEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing CACHE_TTL env variable: %v", err)
+		}
+	}
+
+	if redisURLEnv, ok := os.LookupEnv("REDIS_URL"); ok && redisURLEnv != "" {
+		config.RedisURL = redisURLEnv
+	}
+
+	if redisKeyPrefixEnv, ok := os.LookupEnv("REDIS_KEY_PREFIX"); ok && redisKeyPrefixEnv != "" {
+		config.RedisKeyPrefix = redisKeyPrefixEnv
+	}
+
+	if dbMaxConnsEnv, ok := os.LookupEnv("DB_MAX_CONNS"); ok && dbMaxConnsEnv != "" {
+		var err error
+		config.DBMaxConns, err = strconv.Atoi(dbMaxConnsEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing DB_MAX_CONNS env variable: %v", err)
+		}
+	}
+
+	if dbMinConnsEnv, ok := os.LookupEnv("DB_MIN_CONNS"); ok && dbMinConnsEnv != "" {
+		var err error
+		config.DBMinConns, err = strconv.Atoi(dbMinConnsEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing DB_MIN_CONNS env variable: %v", err)
+		}
+	}
+
+	if dbConnMaxLifetimeEnv, ok := os.LookupEnv("DB_CONN_MAX_LIFETIME"); ok && dbConnMaxLifetimeEnv != "" {
+		var err error
+		config.DBConnMaxLifetime.Duration, err = time.ParseDuration(dbConnMaxLifetimeEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing DB_CONN_MAX_LIFETIME env variable: %v", err)
+		}
+	}
+
+	if dbConnMaxIdleTimeEnv, ok := os.LookupEnv("DB_CONN_MAX_IDLE_TIME"); ok && dbConnMaxIdleTimeEnv != "" {
+		var err error
+		config.DBConnMaxIdleTime.Duration, err = time.ParseDuration(dbConnMaxIdleTimeEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing DB_CONN_MAX_IDLE_TIME env variable: %v", err)
+		}
+	}
+
+	if dbStatementTimeoutEnv, ok := os.LookupEnv("DB_STATEMENT_TIMEOUT"); ok && dbStatementTimeoutEnv != "" {
+		var err error
+		config.DBStatementTimeout.Duration, err = time.ParseDuration(dbStatementTimeoutEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing DB_STATEMENT_TIMEOUT env variable: %v", err)
+		}
+	}
+
+	if dbReadRetriesEnv, ok := os.LookupEnv("DB_READ_RETRIES"); ok && dbReadRetriesEnv != "" {
+		var err error
+		config.DBReadRetries, err = strconv.Atoi(dbReadRetriesEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing DB_READ_RETRIES env variable: %v", err)
+		}
+	}
+
+	if databaseReplicaDSNsEnv, ok := os.LookupEnv("DATABASE_REPLICA_DSNS"); ok && databaseReplicaDSNsEnv != "" {
+		config.DatabaseReplicaDSNs = splitReplicaDSNs(databaseReplicaDSNsEnv)
+	}
+
+	if dbReplicaReadWindowEnv, ok := os.LookupEnv("DB_REPLICA_READ_WINDOW"); ok && dbReplicaReadWindowEnv != "" {
+		var err error
+		config.DBReplicaReadWindow.Duration, err = time.ParseDuration(dbReplicaReadWindowEnv)
+		if err != nil {
+			log.Fatalf("config: error parsing DB_REPLICA_READ_WINDOW env variable: %v", err)
+		}
+	}
+}
+
+// splitDomains разбирает список доменов, разделенных запятыми
+func splitDomains(domains string) []string {
+	result := make([]string, 0)
+	for _, domain := range strings.Split(domains, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			result = append(result, domain)
+		}
+	}
+
+	return result
+}
+
+// splitReplicaDSNs разбирает список строк подключения к репликам, разделенных точкой с запятой,
+// так как строки подключения могут содержать запятые и пробелы
+func splitReplicaDSNs(replicaDSNs string) []string {
+	result := make([]string, 0)
+	for _, replicaDSN := range strings.Split(replicaDSNs, ";") {
+		if replicaDSN = strings.TrimSpace(replicaDSN); replicaDSN != "" {
+			result = append(result, replicaDSN)
+		}
+	}
+
+	return result
 }
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrideConfigByFlags(t *testing.T) {
+	flagConfig := Config{
+		TrashRetention: Duration{Duration: trashRetentionDefault},
+		DBReadRetries:  dbReadRetriesDefault,
+	}
+
+	testCases := []struct {
+		name                   string
+		configJSON             string
+		expectedTrashRetention time.Duration
+		expectedDBReadRetries  int
+	}{
+		{
+			name:                   "zero values keep trash forever and disable read retries",
+			configJSON:             `{"trash_retention":"0s","db_read_retries":0}`,
+			expectedTrashRetention: 0,
+			expectedDBReadRetries:  0,
+		},
+		{
+			name:                   "values from config file",
+			configJSON:             `{"trash_retention":"24h","db_read_retries":5}`,
+			expectedTrashRetention: 24 * time.Hour,
+			expectedDBReadRetries:  5,
+		},
+		{
+			name:                   "missing keys use flags",
+			configJSON:             `{"base_url":"http://localhost:8080"}`,
+			expectedTrashRetention: trashRetentionDefault,
+			expectedDBReadRetries:  dbReadRetriesDefault,
+		},
+		{
+			name:                   "empty config file uses flags",
+			expectedTrashRetention: trashRetentionDefault,
+			expectedDBReadRetries:  dbReadRetriesDefault,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configFilePath := filepath.Join(t.TempDir(), "config.json")
+			require.NoError(t, os.WriteFile(configFilePath, []byte(tc.configJSON), 0o600))
+
+			var config Config
+			jsonKeys := parseJSONConfig(&config, configFilePath)
+			overrideConfigByFlags(&config, &flagConfig, jsonKeys)
+
+			assert.Equal(t, tc.expectedTrashRetention, config.TrashRetention.Duration)
+			assert.Equal(t, tc.expectedDBReadRetries, config.DBReadRetries)
+		})
+	}
+}
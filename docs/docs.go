@@ -54,6 +54,139 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/internal/db/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "статистика пула соединений с базой данных и кэша коротких ссылок",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIInternalGetDBStatsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/internal/export": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "summary": "выгрузка всех коротких ссылок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "jsonl",
+                        "description": "формат выгрузки: jsonl или csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "неподдерживаемый формат выгрузки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/internal/import": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "загрузка коротких ссылок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "jsonl",
+                        "description": "формат выгрузки: jsonl или csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "проверить выгрузку без сохранения коротких ссылок",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "skip",
+                        "description": "политика разрешения конфликтов: skip или fail",
+                        "name": "conflict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIInternalImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "некорректная выгрузка или параметры загрузки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "короткая ссылка конфликтует с существующей",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/internal/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "статистика по сервису",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/api/shorten": {
             "post": {
                 "produces": [
@@ -135,6 +268,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "409": {
+                        "description": "исходный URL уже сокращен или повторяется в пачке",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "внутренняя ошибка сервиса",
                         "schema": {
@@ -144,33 +283,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/user/urls": {
+        "/api/user/jobs/{jobID}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Получение коротких ссылок созданных пользователем",
+                "summary": "Получение статуса задачи на удаление коротких ссылок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор задачи на удаление",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.APIGetAllURLByUserIDResponseEntry"
-                            }
+                            "$ref": "#/definitions/dto.APIGetDeleteJobResponse"
                         }
                     },
-                    "204": {
-                        "description": "у пользователя нет коротких ссылок",
+                    "404": {
+                        "description": "задача не найдена",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "ошибка в формате запроса",
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/api/user/settings": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получение настроек пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIUserSettings"
+                        }
                     },
                     "500": {
                         "description": "внутренняя ошибка сервиса",
@@ -180,22 +341,19 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Удаление коротких ссылок",
+                "summary": "Изменение настроек пользователя",
                 "parameters": [
                     {
-                        "description": "список идентификаторов коротких ссылок",
+                        "description": "настройки пользователя",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dto.APIUserSettings"
                         }
                     }
                 ],
@@ -203,7 +361,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.APIUserSettings"
                         }
                     },
                     "400": {
@@ -221,15 +379,89 @@ const docTemplate = `{
                 }
             }
         },
-        "/ping": {
+        "/api/user/urls": {
             "get": {
                 "produces": [
-                    "text/plain"
+                    "application/json"
+                ],
+                "summary": "Получение коротких ссылок созданных пользователем",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "размер страницы, по умолчанию 100, не более 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "курсор следующей страницы из заголовка X-Next-Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "статус коротких ссылок: all (по умолчанию), active, deleted",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "минимальная дата создания в формате RFC3339",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "максимальная дата создания в формате RFC3339",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "домен исходного URL, включая поддомены",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "подстрока исходного URL или идентификатора короткой ссылки",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "тег короткой ссылки",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "поле сортировки: created_at (по умолчанию), original_url, short_url; для сортировки по убыванию - с префиксом '-'",
+                        "name": "sort",
+                        "in": "query"
+                    }
                 ],
-                "summary": "получить короткую ссылку",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIGetAllURLByUserIDResponseEntry"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "у пользователя нет коротких ссылок",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "ошибка в формате запроса",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
                     "500": {
                         "description": "внутренняя ошибка сервиса",
@@ -238,38 +470,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/{shortURI}": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
-                "summary": "получить короткую ссылку",
+                "summary": "Удаление коротких ссылок",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "идентификатор короткой ссылки",
-                        "name": "shortURI",
-                        "in": "path",
-                        "required": true
+                        "description": "список идентификаторов коротких ссылок",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
                     }
                 ],
                 "responses": {
-                    "307": {
-                        "description": "Temporary Redirect",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "короткая ссылка не найдена",
+                    "202": {
+                        "description": "задача на удаление принята",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.APIDeleteShortURLsResponse"
                         }
                     },
-                    "410": {
-                        "description": "короткая ссылка удалена",
+                    "400": {
+                        "description": "ошибка в формате запроса",
                         "schema": {
                             "type": "string"
                         }
@@ -282,36 +511,883 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.APICreateShortURLBatchRequestEntry": {
-            "type": "object",
-            "properties": {
-                "correlation_id": {
-                    "type": "string"
-                },
-                "original_url": {
-                    "type": "string"
-                }
-            }
         },
-        "dto.APICreateShortURLBatchResponseEntry": {
-            "type": "object",
-            "properties": {
-                "correlation_id": {
-                    "type": "string"
+        "/api/user/urls/import": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Загрузка коротких ссылок из выгрузки стороннего сервиса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "раскладка колонок выгрузки: generic (по умолчанию), bitly, rebrandly, yourls",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "переопределение колонок вида original_url=Destination,short_code=Slug; поля: original_url, short_code, title, description, notes, tags",
+                        "name": "map",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "домен создаваемых коротких ссылок",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "проверить выгрузку без создания коротких ссылок",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "политика для занятых идентификаторов: generate (по умолчанию), skip, fail",
+                        "name": "conflict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "результат проверки выгрузки при dry_run",
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIImportShortURLsResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIImportShortURLsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ошибка в формате запроса или выгрузки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "идентификатор короткой ссылки занят",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/urls/restore": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Восстановление удаленных коротких ссылок",
+                "parameters": [
+                    {
+                        "description": "список идентификаторов коротких ссылок",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIRestoreShortURLsResponseEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ошибка в формате запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/urls/trash": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получение удаленных коротких ссылок пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIGetTrashResponseEntry"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "у пользователя нет удаленных коротких ссылок",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/urls/{id}": {
+            "patch": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Изменение метаданных короткой ссылки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор короткой ссылки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "изменяемые поля короткой ссылки",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIUpdateShortURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIGetAllURLByUserIDResponseEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "ошибка в формате запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "короткая ссылка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/urls/{id}/countries": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получение статистики переходов по короткой ссылке по странам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор короткой ссылки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIGetCountryStatsResponseEntry"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "короткая ссылка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/urls/{id}/split": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получение статистики переходов по вариантам перенаправления короткой ссылки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор короткой ссылки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIGetSplitStatsResponseEntry"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "короткая ссылка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "проверка работоспособности сервера",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{shortURI}": {
+            "get": {
+                "produces": [
+                    "text/plain",
+                    "text/html"
+                ],
+                "summary": "получить короткую ссылку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор короткой ссылки",
+                        "name": "shortURI",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "форма ввода пароля короткой ссылки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "301": {
+                        "description": "постоянное перенаправление",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "302": {
+                        "description": "временное перенаправление или перенаправление на резервный адрес",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "307": {
+                        "description": "временное перенаправление",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "308": {
+                        "description": "постоянное перенаправление",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "период действия короткой ссылки еще не начался",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "короткая ссылка не найдена или не переносит путь",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "короткая ссылка удалена, заблокирована, переходы по ней исчерпаны или период действия закончился",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "ввести пароль короткой ссылки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор короткой ссылки",
+                        "name": "shortURI",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "пароль короткой ссылки",
+                        "name": "password",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "перенаправление на резервный адрес недоступной короткой ссылки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "303": {
+                        "description": "перенаправление на полную ссылку",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "ошибка в формате запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "неверный пароль или период действия короткой ссылки еще не начался",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "короткая ссылка не найдена или не переносит путь",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "короткая ссылка удалена, заблокирована, переходы по ней исчерпаны или период действия закончился",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "превышено количество попыток ввода пароля",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{shortURI}/qr": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "summary": "получить QR-код короткой ссылки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор короткой ссылки",
+                        "name": "shortURI",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "формат изображения: png или svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "размер изображения в пикселях",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ширина поля вокруг QR-кода в модулях",
+                        "name": "margin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "уровень коррекции ошибок: L, M, Q или H",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "цвет модулей в виде RRGGBB или RRGGBBAA",
+                        "name": "fg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "цвет фона в виде RRGGBB или RRGGBBAA",
+                        "name": "bg",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "изображение не изменилось",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "некорректные параметры изображения",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "короткая ссылка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "короткая ссылка удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{shortURI}/{path}": {
+            "get": {
+                "produces": [
+                    "text/plain",
+                    "text/html"
+                ],
+                "summary": "получить короткую ссылку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор короткой ссылки",
+                        "name": "shortURI",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "путь, переносимый в полную ссылку",
+                        "name": "path",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "форма ввода пароля короткой ссылки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "301": {
+                        "description": "постоянное перенаправление",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "302": {
+                        "description": "временное перенаправление или перенаправление на резервный адрес",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "307": {
+                        "description": "временное перенаправление",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "308": {
+                        "description": "постоянное перенаправление",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "период действия короткой ссылки еще не начался",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "короткая ссылка не найдена или не переносит путь",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "короткая ссылка удалена, заблокирована, переходы по ней исчерпаны или период действия закончился",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "ввести пароль короткой ссылки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "идентификатор короткой ссылки",
+                        "name": "shortURI",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "путь, переносимый в полную ссылку",
+                        "name": "path",
+                        "in": "path"
+                    },
+                    {
+                        "type": "string",
+                        "description": "пароль короткой ссылки",
+                        "name": "password",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "перенаправление на резервный адрес недоступной короткой ссылки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "303": {
+                        "description": "перенаправление на полную ссылку",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "ошибка в формате запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "неверный пароль или период действия короткой ссылки еще не начался",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "короткая ссылка не найдена или не переносит путь",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "короткая ссылка удалена, заблокирована, переходы по ней исчерпаны или период действия закончился",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "превышено количество попыток ввода пароля",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "внутренняя ошибка сервиса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.APICreateShortURLBatchRequestEntry": {
+            "type": "object",
+            "properties": {
+                "correlation_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "Disabled - короткая ссылка заблокирована, переходы по ней не выполняются",
+                    "type": "boolean"
+                },
+                "fallback_url": {
+                    "description": "FallbackURL - адрес перенаправления для удаленной, истекшей, исчерпавшей лимит переходов или заблокированной\nкороткой ссылки, причина передается в параметре reason, \"\" - адрес из настроек пользователя",
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "description": "MaxClicks - максимальное количество переходов по короткой ссылке, после которого она становится недоступной,\n0 - без ограничения",
+                    "type": "integer"
+                },
+                "not_after": {
+                    "description": "NotAfter - время окончания действия короткой ссылки, после него короткая ссылка считается удаленной",
+                    "type": "string"
+                },
+                "not_before": {
+                    "description": "NotBefore - время начала действия короткой ссылки, до него вместо перенаправления возвращается\nстраница \"ссылка еще не активна\"",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password - пароль для перехода по короткой ссылке, хранится в виде хэша и в ответах не возвращается",
+                    "type": "string"
+                },
+                "path_passthrough": {
+                    "description": "PathPassthrough - перенос пути после идентификатора короткой ссылки в исходный URL",
+                    "type": "boolean"
+                },
+                "query_passthrough": {
+                    "description": "QueryPassthrough - политика переноса параметров запроса перехода: keep_target, override или append,\nпустое значение - параметры не переносятся",
+                    "type": "string"
+                },
+                "redirect_status": {
+                    "description": "RedirectStatus - код ответа перенаправления: 301, 302, 307 или 308, 0 - код по умолчанию",
+                    "type": "integer"
+                },
+                "short_domain": {
+                    "description": "ShortDomain - домен короткой ссылки из настроенных в сервисе, задается только при создании,\n\"\" - домен по умолчанию",
+                    "type": "string"
+                },
+                "split_variants": {
+                    "description": "SplitVariants - варианты перенаправления A/B-теста с весами, посетитель закрепляется за вариантом\nс помощью cookie, правила таргетинга имеют приоритет над вариантами",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/split.Variant"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "targeting_rules": {
+                    "description": "TargetingRules - правила выбора адреса перенаправления по платформе, устройству и языку клиента,\nприменяется первое подходящее правило, иначе - исходный URL",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/targeting.Rule"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "utm_params": {
+                    "description": "UTMParams - UTM-параметры, добавляемые в исходный URL при перенаправлении",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.APICreateShortURLBatchResponseEntry": {
+            "type": "object",
+            "properties": {
+                "correlation_id": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.APICreateShortURLRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "Disabled - короткая ссылка заблокирована, переходы по ней не выполняются",
+                    "type": "boolean"
+                },
+                "fallback_url": {
+                    "description": "FallbackURL - адрес перенаправления для удаленной, истекшей, исчерпавшей лимит переходов или заблокированной\nкороткой ссылки, причина передается в параметре reason, \"\" - адрес из настроек пользователя",
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "description": "MaxClicks - максимальное количество переходов по короткой ссылке, после которого она становится недоступной,\n0 - без ограничения",
+                    "type": "integer"
+                },
+                "not_after": {
+                    "description": "NotAfter - время окончания действия короткой ссылки, после него короткая ссылка считается удаленной",
+                    "type": "string"
                 },
-                "short_url": {
+                "not_before": {
+                    "description": "NotBefore - время начала действия короткой ссылки, до него вместо перенаправления возвращается\nстраница \"ссылка еще не активна\"",
                     "type": "string"
-                }
-            }
-        },
-        "dto.APICreateShortURLRequest": {
-            "type": "object",
-            "properties": {
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password - пароль для перехода по короткой ссылке, хранится в виде хэша и в ответах не возвращается",
+                    "type": "string"
+                },
+                "path_passthrough": {
+                    "description": "PathPassthrough - перенос пути после идентификатора короткой ссылки в исходный URL",
+                    "type": "boolean"
+                },
+                "query_passthrough": {
+                    "description": "QueryPassthrough - политика переноса параметров запроса перехода: keep_target, override или append,\nпустое значение - параметры не переносятся",
+                    "type": "string"
+                },
+                "redirect_status": {
+                    "description": "RedirectStatus - код ответа перенаправления: 301, 302, 307 или 308, 0 - код по умолчанию",
+                    "type": "integer"
+                },
+                "short_domain": {
+                    "description": "ShortDomain - домен короткой ссылки из настроенных в сервисе, задается только при создании,\n\"\" - домен по умолчанию",
+                    "type": "string"
+                },
+                "split_variants": {
+                    "description": "SplitVariants - варианты перенаправления A/B-теста с весами, посетитель закрепляется за вариантом\nс помощью cookie, правила таргетинга имеют приоритет над вариантами",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/split.Variant"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "targeting_rules": {
+                    "description": "TargetingRules - правила выбора адреса перенаправления по платформе, устройству и языку клиента,\nприменяется первое подходящее правило, иначе - исходный URL",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/targeting.Rule"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
                 "url": {
                     "type": "string"
+                },
+                "utm_params": {
+                    "description": "UTMParams - UTM-параметры, добавляемые в исходный URL при перенаправлении",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -329,13 +1405,450 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.APIDeleteShortURLsResponse": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.APIGetAllURLByUserIDResponseEntry": {
             "type": "object",
             "properties": {
+                "click_count": {
+                    "description": "ClickCount - количество переходов по короткой ссылке с ограничением количества переходов",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "Disabled - короткая ссылка заблокирована, переходы по ней не выполняются",
+                    "type": "boolean"
+                },
+                "fallback_url": {
+                    "description": "FallbackURL - адрес перенаправления для удаленной, истекшей, исчерпавшей лимит переходов или заблокированной\nкороткой ссылки, причина передается в параметре reason, \"\" - адрес из настроек пользователя",
+                    "type": "string"
+                },
+                "is_deleted": {
+                    "type": "boolean"
+                },
+                "max_clicks": {
+                    "description": "MaxClicks - максимальное количество переходов по короткой ссылке, после которого она становится недоступной,\n0 - без ограничения",
+                    "type": "integer"
+                },
+                "not_after": {
+                    "description": "NotAfter - время окончания действия короткой ссылки, после него короткая ссылка считается удаленной",
+                    "type": "string"
+                },
+                "not_before": {
+                    "description": "NotBefore - время начала действия короткой ссылки, до него вместо перенаправления возвращается\nстраница \"ссылка еще не активна\"",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password - пароль для перехода по короткой ссылке, хранится в виде хэша и в ответах не возвращается",
+                    "type": "string"
+                },
+                "password_protected": {
+                    "description": "PasswordProtected - для перехода по короткой ссылке требуется пароль",
+                    "type": "boolean"
+                },
+                "path_passthrough": {
+                    "description": "PathPassthrough - перенос пути после идентификатора короткой ссылки в исходный URL",
+                    "type": "boolean"
+                },
+                "preview": {
+                    "$ref": "#/definitions/dto.APIShortURLPreview"
+                },
+                "query_passthrough": {
+                    "description": "QueryPassthrough - политика переноса параметров запроса перехода: keep_target, override или append,\nпустое значение - параметры не переносятся",
+                    "type": "string"
+                },
+                "redirect_status": {
+                    "description": "RedirectStatus - код ответа перенаправления: 301, 302, 307 или 308, 0 - код по умолчанию",
+                    "type": "integer"
+                },
+                "short_domain": {
+                    "description": "ShortDomain - домен короткой ссылки из настроенных в сервисе, задается только при создании,\n\"\" - домен по умолчанию",
+                    "type": "string"
+                },
+                "short_uri": {
+                    "description": "ShortURI - идентификатор короткой ссылки в запросах на изменение, удаление и восстановление,\nдля дополнительного домена имеет вид \"\u003cидентификатор\u003e@\u003cдомен\u003e\"",
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "split_variants": {
+                    "description": "SplitVariants - варианты перенаправления A/B-теста с весами, посетитель закрепляется за вариантом\nс помощью cookie, правила таргетинга имеют приоритет над вариантами",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/split.Variant"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "targeting_rules": {
+                    "description": "TargetingRules - правила выбора адреса перенаправления по платформе, устройству и языку клиента,\nприменяется первое подходящее правило, иначе - исходный URL",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/targeting.Rule"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "utm_params": {
+                    "description": "UTMParams - UTM-параметры, добавляемые в исходный URL при перенаправлении",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.APIGetCountryStatsResponseEntry": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "integer"
+                },
+                "country": {
+                    "description": "Country - код страны ISO 3166-1 alpha-2, \"ZZ\" - страна не определена",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.APIGetDeleteJobResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.APIGetDeleteJobResponseEntry"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.APIGetDeleteJobResponseEntry": {
+            "type": "object",
+            "properties": {
+                "short_uri": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.APIGetSplitStatsResponseEntry": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.APIGetTrashResponseEntry": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string"
+                },
                 "original_url": {
                     "type": "string"
                 },
+                "short_uri": {
+                    "description": "ShortURI - идентификатор короткой ссылки в запросе на восстановление",
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.APIImportShortURLsConflict": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "record": {
+                    "description": "Record - номер записи выгрузки, начиная с 1",
+                    "type": "integer"
+                },
+                "short_code": {
+                    "type": "string"
+                },
                 "short_url": {
+                    "description": "ShortURL - короткая ссылка с новым идентификатором, отсутствует, если короткая ссылка не создана",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.APIImportShortURLsResponse": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.APIImportShortURLsConflict"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "kept_aliases": {
+                    "type": "integer"
+                },
+                "read": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.APIInternalGetDBStatsResponse": {
+            "type": "object",
+            "properties": {
+                "cache_hit_count": {
+                    "type": "integer"
+                },
+                "cache_miss_count": {
+                    "type": "integer"
+                },
+                "canceled_acquire_count": {
+                    "type": "integer"
+                },
+                "healthy_replica_count": {
+                    "type": "integer"
+                },
+                "idle_conns": {
+                    "type": "integer"
+                },
+                "in_use_conns": {
+                    "type": "integer"
+                },
+                "max_conns": {
+                    "type": "integer"
+                },
+                "read_retry_count": {
+                    "type": "integer"
+                },
+                "replica_count": {
+                    "type": "integer"
+                },
+                "replica_fallback_count": {
+                    "type": "integer"
+                },
+                "total_conns": {
+                    "type": "integer"
+                },
+                "wait_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.APIInternalImportResponse": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "existing": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "read": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.APIRestoreShortURLsResponseEntry": {
+            "type": "object",
+            "properties": {
+                "short_uri": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.APIShortURLPreview": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.APIUpdateShortURLRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "Disabled - короткая ссылка заблокирована, переходы по ней не выполняются",
+                    "type": "boolean"
+                },
+                "fallback_url": {
+                    "description": "FallbackURL - адрес перенаправления для недоступной короткой ссылки, \"\" - адрес из настроек пользователя",
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "description": "MaxClicks - максимальное количество переходов по короткой ссылке, 0 - без ограничения",
+                    "type": "integer"
+                },
+                "not_after": {
+                    "description": "NotAfter - время окончания действия короткой ссылки, \"0001-01-01T00:00:00Z\" - снять ограничение",
+                    "type": "string"
+                },
+                "not_before": {
+                    "description": "NotBefore - время начала действия короткой ссылки, \"0001-01-01T00:00:00Z\" - снять ограничение",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password - пароль для перехода по короткой ссылке, \"\" - пароль не требуется",
+                    "type": "string"
+                },
+                "path_passthrough": {
+                    "description": "PathPassthrough - перенос пути после идентификатора короткой ссылки в исходный URL",
+                    "type": "boolean"
+                },
+                "query_passthrough": {
+                    "description": "QueryPassthrough - политика переноса параметров запроса перехода: keep_target, override или append,\nпустое значение - параметры не переносятся",
+                    "type": "string"
+                },
+                "redirect_status": {
+                    "description": "RedirectStatus - код ответа перенаправления: 301, 302, 307 или 308, 0 - код по умолчанию",
+                    "type": "integer"
+                },
+                "split_variants": {
+                    "description": "SplitVariants - варианты перенаправления A/B-теста с весами, посетитель закрепляется за вариантом\nс помощью cookie, правила таргетинга имеют приоритет над вариантами",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/split.Variant"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "targeting_rules": {
+                    "description": "TargetingRules - правила выбора адреса перенаправления по платформе, устройству и языку клиента,\nприменяется первое подходящее правило, иначе - исходный URL",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/targeting.Rule"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "utm_params": {
+                    "description": "UTMParams - UTM-параметры, добавляемые в исходный URL при перенаправлении",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.APIUserSettings": {
+            "type": "object",
+            "properties": {
+                "fallback_url": {
+                    "description": "FallbackURL - адрес перенаправления по умолчанию для недоступных коротких ссылок пользователя,\n\"\" - адрес из конфигурации сервиса",
+                    "type": "string"
+                }
+            }
+        },
+        "split.Variant": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "targeting.Rule": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
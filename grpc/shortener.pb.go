@@ -20,27 +20,31 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type CreateShortURLRequest struct {
+type TargetingRule struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OriginalUrl   string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	Platform      string                 `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Device        string                 `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	Locale        string                 `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Country       string                 `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateShortURLRequest) Reset() {
-	*x = CreateShortURLRequest{}
+func (x *TargetingRule) Reset() {
+	*x = TargetingRule{}
 	mi := &file_grpc_shortener_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateShortURLRequest) String() string {
+func (x *TargetingRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateShortURLRequest) ProtoMessage() {}
+func (*TargetingRule) ProtoMessage() {}
 
-func (x *CreateShortURLRequest) ProtoReflect() protoreflect.Message {
+func (x *TargetingRule) ProtoReflect() protoreflect.Message {
 	mi := &file_grpc_shortener_proto_msgTypes[0]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -52,40 +56,69 @@ func (x *CreateShortURLRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateShortURLRequest.ProtoReflect.Descriptor instead.
-func (*CreateShortURLRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TargetingRule.ProtoReflect.Descriptor instead.
+func (*TargetingRule) Descriptor() ([]byte, []int) {
 	return file_grpc_shortener_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *CreateShortURLRequest) GetOriginalUrl() string {
+func (x *TargetingRule) GetPlatform() string {
 	if x != nil {
-		return x.OriginalUrl
+		return x.Platform
 	}
 	return ""
 }
 
-type CreateShortURLResponse struct {
+func (x *TargetingRule) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *TargetingRule) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *TargetingRule) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *TargetingRule) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+type SplitVariant struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
-	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateShortURLResponse) Reset() {
-	*x = CreateShortURLResponse{}
+func (x *SplitVariant) Reset() {
+	*x = SplitVariant{}
 	mi := &file_grpc_shortener_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateShortURLResponse) String() string {
+func (x *SplitVariant) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateShortURLResponse) ProtoMessage() {}
+func (*SplitVariant) ProtoMessage() {}
 
-func (x *CreateShortURLResponse) ProtoReflect() protoreflect.Message {
+func (x *SplitVariant) ProtoReflect() protoreflect.Message {
 	mi := &file_grpc_shortener_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -97,46 +130,72 @@ func (x *CreateShortURLResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateShortURLResponse.ProtoReflect.Descriptor instead.
-func (*CreateShortURLResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SplitVariant.ProtoReflect.Descriptor instead.
+func (*SplitVariant) Descriptor() ([]byte, []int) {
 	return file_grpc_shortener_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *CreateShortURLResponse) GetShortUri() string {
+func (x *SplitVariant) GetName() string {
 	if x != nil {
-		return x.ShortUri
+		return x.Name
 	}
 	return ""
 }
 
-func (x *CreateShortURLResponse) GetShortUrl() string {
+func (x *SplitVariant) GetUrl() string {
 	if x != nil {
-		return x.ShortUrl
+		return x.Url
 	}
 	return ""
 }
 
-type GetShortURLRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+func (x *SplitVariant) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type CreateShortURLRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OriginalUrl      string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	Title            string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description      string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Notes            string                 `protobuf:"bytes,4,opt,name=notes,proto3" json:"notes,omitempty"`
+	Tags             []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	RedirectStatus   int32                  `protobuf:"varint,6,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	QueryPassthrough string                 `protobuf:"bytes,7,opt,name=query_passthrough,json=queryPassthrough,proto3" json:"query_passthrough,omitempty"`
+	PathPassthrough  bool                   `protobuf:"varint,8,opt,name=path_passthrough,json=pathPassthrough,proto3" json:"path_passthrough,omitempty"`
+	UtmParams        map[string]string      `protobuf:"bytes,9,rep,name=utm_params,json=utmParams,proto3" json:"utm_params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TargetingRules   []*TargetingRule       `protobuf:"bytes,10,rep,name=targeting_rules,json=targetingRules,proto3" json:"targeting_rules,omitempty"`
+	SplitVariants    []*SplitVariant        `protobuf:"bytes,11,rep,name=split_variants,json=splitVariants,proto3" json:"split_variants,omitempty"`
+	Password         string                 `protobuf:"bytes,12,opt,name=password,proto3" json:"password,omitempty"`
+	MaxClicks        int32                  `protobuf:"varint,13,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	// время начала и окончания действия короткой ссылки в секундах Unix, 0 - без ограничения
+	NotBefore int64 `protobuf:"varint,14,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter  int64 `protobuf:"varint,15,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	Disabled  bool  `protobuf:"varint,16,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// адрес перенаправления для недоступной короткой ссылки, "" - адрес из настроек пользователя
+	FallbackUrl   string `protobuf:"bytes,17,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	ShortDomain   string `protobuf:"bytes,18,opt,name=short_domain,json=shortDomain,proto3" json:"short_domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetShortURLRequest) Reset() {
-	*x = GetShortURLRequest{}
+func (x *CreateShortURLRequest) Reset() {
+	*x = CreateShortURLRequest{}
 	mi := &file_grpc_shortener_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetShortURLRequest) String() string {
+func (x *CreateShortURLRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetShortURLRequest) ProtoMessage() {}
+func (*CreateShortURLRequest) ProtoMessage() {}
 
-func (x *GetShortURLRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateShortURLRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_grpc_shortener_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -148,19 +207,138 @@ func (x *GetShortURLRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetShortURLRequest.ProtoReflect.Descriptor instead.
-func (*GetShortURLRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateShortURLRequest.ProtoReflect.Descriptor instead.
+func (*CreateShortURLRequest) Descriptor() ([]byte, []int) {
 	return file_grpc_shortener_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetShortURLRequest) GetShortUri() string {
+func (x *CreateShortURLRequest) GetOriginalUrl() string {
 	if x != nil {
-		return x.ShortUri
+		return x.OriginalUrl
 	}
 	return ""
 }
 
-type GetShortURLResponse struct {
+func (x *CreateShortURLRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateShortURLRequest) GetRedirectStatus() int32 {
+	if x != nil {
+		return x.RedirectStatus
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetQueryPassthrough() string {
+	if x != nil {
+		return x.QueryPassthrough
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetPathPassthrough() bool {
+	if x != nil {
+		return x.PathPassthrough
+	}
+	return false
+}
+
+func (x *CreateShortURLRequest) GetUtmParams() map[string]string {
+	if x != nil {
+		return x.UtmParams
+	}
+	return nil
+}
+
+func (x *CreateShortURLRequest) GetTargetingRules() []*TargetingRule {
+	if x != nil {
+		return x.TargetingRules
+	}
+	return nil
+}
+
+func (x *CreateShortURLRequest) GetSplitVariants() []*SplitVariant {
+	if x != nil {
+		return x.SplitVariants
+	}
+	return nil
+}
+
+func (x *CreateShortURLRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetNotBefore() int64 {
+	if x != nil {
+		return x.NotBefore
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetNotAfter() int64 {
+	if x != nil {
+		return x.NotAfter
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *CreateShortURLRequest) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetShortDomain() string {
+	if x != nil {
+		return x.ShortDomain
+	}
+	return ""
+}
+
+type CreateShortURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
 	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
@@ -168,20 +346,20 @@ type GetShortURLResponse struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetShortURLResponse) Reset() {
-	*x = GetShortURLResponse{}
+func (x *CreateShortURLResponse) Reset() {
+	*x = CreateShortURLResponse{}
 	mi := &file_grpc_shortener_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetShortURLResponse) String() string {
+func (x *CreateShortURLResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetShortURLResponse) ProtoMessage() {}
+func (*CreateShortURLResponse) ProtoMessage() {}
 
-func (x *GetShortURLResponse) ProtoReflect() protoreflect.Message {
+func (x *CreateShortURLResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_grpc_shortener_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -193,46 +371,47 @@ func (x *GetShortURLResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetShortURLResponse.ProtoReflect.Descriptor instead.
-func (*GetShortURLResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateShortURLResponse.ProtoReflect.Descriptor instead.
+func (*CreateShortURLResponse) Descriptor() ([]byte, []int) {
 	return file_grpc_shortener_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetShortURLResponse) GetShortUri() string {
+func (x *CreateShortURLResponse) GetShortUri() string {
 	if x != nil {
 		return x.ShortUri
 	}
 	return ""
 }
 
-func (x *GetShortURLResponse) GetShortUrl() string {
+func (x *CreateShortURLResponse) GetShortUrl() string {
 	if x != nil {
 		return x.ShortUrl
 	}
 	return ""
 }
 
-type CreateShortURLBatchRequest struct {
-	state         protoimpl.MessageState                                        `protogen:"open.v1"`
-	Entries       []*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+type GetShortURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateShortURLBatchRequest) Reset() {
-	*x = CreateShortURLBatchRequest{}
+func (x *GetShortURLRequest) Reset() {
+	*x = GetShortURLRequest{}
 	mi := &file_grpc_shortener_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateShortURLBatchRequest) String() string {
+func (x *GetShortURLRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateShortURLBatchRequest) ProtoMessage() {}
+func (*GetShortURLRequest) ProtoMessage() {}
 
-func (x *CreateShortURLBatchRequest) ProtoReflect() protoreflect.Message {
+func (x *GetShortURLRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_grpc_shortener_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -244,39 +423,48 @@ func (x *CreateShortURLBatchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateShortURLBatchRequest.ProtoReflect.Descriptor instead.
-func (*CreateShortURLBatchRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetShortURLRequest.ProtoReflect.Descriptor instead.
+func (*GetShortURLRequest) Descriptor() ([]byte, []int) {
 	return file_grpc_shortener_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateShortURLBatchRequest) GetEntries() []*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry {
+func (x *GetShortURLRequest) GetShortUri() string {
 	if x != nil {
-		return x.Entries
+		return x.ShortUri
 	}
-	return nil
+	return ""
 }
 
-type CreateShortURLBatchResponse struct {
-	state         protoimpl.MessageState                                          `protogen:"open.v1"`
-	Entries       []*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+func (x *GetShortURLRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type GetShortURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	OriginalUrl   string                 `protobuf:"bytes,3,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateShortURLBatchResponse) Reset() {
-	*x = CreateShortURLBatchResponse{}
+func (x *GetShortURLResponse) Reset() {
+	*x = GetShortURLResponse{}
 	mi := &file_grpc_shortener_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateShortURLBatchResponse) String() string {
+func (x *GetShortURLResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateShortURLBatchResponse) ProtoMessage() {}
+func (*GetShortURLResponse) ProtoMessage() {}
 
-func (x *CreateShortURLBatchResponse) ProtoReflect() protoreflect.Message {
+func (x *GetShortURLResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_grpc_shortener_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -288,39 +476,59 @@ func (x *CreateShortURLBatchResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateShortURLBatchResponse.ProtoReflect.Descriptor instead.
-func (*CreateShortURLBatchResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetShortURLResponse.ProtoReflect.Descriptor instead.
+func (*GetShortURLResponse) Descriptor() ([]byte, []int) {
 	return file_grpc_shortener_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *CreateShortURLBatchResponse) GetEntries() []*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry {
+func (x *GetShortURLResponse) GetShortUri() string {
 	if x != nil {
-		return x.Entries
+		return x.ShortUri
 	}
-	return nil
+	return ""
 }
 
-type GetShortURLsByUserIDRequest struct {
+func (x *GetShortURLResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *GetShortURLResponse) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+type GetShortURLQRCodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Margin        *int32                 `protobuf:"varint,4,opt,name=margin,proto3,oneof" json:"margin,omitempty"`
+	Level         string                 `protobuf:"bytes,5,opt,name=level,proto3" json:"level,omitempty"`
+	Foreground    string                 `protobuf:"bytes,6,opt,name=foreground,proto3" json:"foreground,omitempty"`
+	Background    string                 `protobuf:"bytes,7,opt,name=background,proto3" json:"background,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetShortURLsByUserIDRequest) Reset() {
-	*x = GetShortURLsByUserIDRequest{}
+func (x *GetShortURLQRCodeRequest) Reset() {
+	*x = GetShortURLQRCodeRequest{}
 	mi := &file_grpc_shortener_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetShortURLsByUserIDRequest) String() string {
+func (x *GetShortURLQRCodeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetShortURLsByUserIDRequest) ProtoMessage() {}
+func (*GetShortURLQRCodeRequest) ProtoMessage() {}
 
-func (x *GetShortURLsByUserIDRequest) ProtoReflect() protoreflect.Message {
+func (x *GetShortURLQRCodeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_grpc_shortener_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -332,128 +540,2163 @@ func (x *GetShortURLsByUserIDRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetShortURLsByUserIDRequest.ProtoReflect.Descriptor instead.
-func (*GetShortURLsByUserIDRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetShortURLQRCodeRequest.ProtoReflect.Descriptor instead.
+func (*GetShortURLQRCodeRequest) Descriptor() ([]byte, []int) {
 	return file_grpc_shortener_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetShortURLsByUserIDRequest) GetUserID() string {
+func (x *GetShortURLQRCodeRequest) GetShortUri() string {
 	if x != nil {
-		return x.UserID
+		return x.ShortUri
 	}
 	return ""
 }
 
-type GetShortURLsByUserIDResponse struct {
-	state         protoimpl.MessageState                                           `protogen:"open.v1"`
-	Entries       []*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+func (x *GetShortURLQRCodeRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *GetShortURLQRCodeRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetShortURLQRCodeRequest) GetMargin() int32 {
+	if x != nil && x.Margin != nil {
+		return *x.Margin
+	}
+	return 0
+}
+
+func (x *GetShortURLQRCodeRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *GetShortURLQRCodeRequest) GetForeground() string {
+	if x != nil {
+		return x.Foreground
+	}
+	return ""
+}
+
+func (x *GetShortURLQRCodeRequest) GetBackground() string {
+	if x != nil {
+		return x.Background
+	}
+	return ""
+}
+
+type GetShortURLQRCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Image         []byte                 `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetShortURLsByUserIDResponse) Reset() {
-	*x = GetShortURLsByUserIDResponse{}
+func (x *GetShortURLQRCodeResponse) Reset() {
+	*x = GetShortURLQRCodeResponse{}
 	mi := &file_grpc_shortener_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetShortURLsByUserIDResponse) String() string {
+func (x *GetShortURLQRCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShortURLQRCodeResponse) ProtoMessage() {}
+
+func (x *GetShortURLQRCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShortURLQRCodeResponse.ProtoReflect.Descriptor instead.
+func (*GetShortURLQRCodeResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetShortURLQRCodeResponse) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *GetShortURLQRCodeResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type CreateShortURLBatchRequest struct {
+	state         protoimpl.MessageState                                        `protogen:"open.v1"`
+	Entries       []*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShortURLBatchRequest) Reset() {
+	*x = CreateShortURLBatchRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLBatchRequest) ProtoMessage() {}
+
+func (x *CreateShortURLBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLBatchRequest.ProtoReflect.Descriptor instead.
+func (*CreateShortURLBatchRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateShortURLBatchRequest) GetEntries() []*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type CreateShortURLBatchResponse struct {
+	state         protoimpl.MessageState                                          `protogen:"open.v1"`
+	Entries       []*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShortURLBatchResponse) Reset() {
+	*x = CreateShortURLBatchResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLBatchResponse) ProtoMessage() {}
+
+func (x *CreateShortURLBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLBatchResponse.ProtoReflect.Descriptor instead.
+func (*CreateShortURLBatchResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateShortURLBatchResponse) GetEntries() []*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type CreateShortURLBatchStreamRequest struct {
+	state         protoimpl.MessageState                                        `protogen:"open.v1"`
+	Entries       []*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShortURLBatchStreamRequest) Reset() {
+	*x = CreateShortURLBatchStreamRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLBatchStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLBatchStreamRequest) ProtoMessage() {}
+
+func (x *CreateShortURLBatchStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLBatchStreamRequest.ProtoReflect.Descriptor instead.
+func (*CreateShortURLBatchStreamRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateShortURLBatchStreamRequest) GetEntries() []*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type CreateShortURLBatchStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*CreateShortURLBatchStreamResponse_Entry_
+	//	*CreateShortURLBatchStreamResponse_Summary_
+	Result        isCreateShortURLBatchStreamResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShortURLBatchStreamResponse) Reset() {
+	*x = CreateShortURLBatchStreamResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLBatchStreamResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetShortURLsByUserIDResponse) ProtoMessage() {}
+func (*CreateShortURLBatchStreamResponse) ProtoMessage() {}
+
+func (x *CreateShortURLBatchStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLBatchStreamResponse.ProtoReflect.Descriptor instead.
+func (*CreateShortURLBatchStreamResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateShortURLBatchStreamResponse) GetResult() isCreateShortURLBatchStreamResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *CreateShortURLBatchStreamResponse) GetEntry() *CreateShortURLBatchStreamResponse_Entry {
+	if x != nil {
+		if x, ok := x.Result.(*CreateShortURLBatchStreamResponse_Entry_); ok {
+			return x.Entry
+		}
+	}
+	return nil
+}
+
+func (x *CreateShortURLBatchStreamResponse) GetSummary() *CreateShortURLBatchStreamResponse_Summary {
+	if x != nil {
+		if x, ok := x.Result.(*CreateShortURLBatchStreamResponse_Summary_); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isCreateShortURLBatchStreamResponse_Result interface {
+	isCreateShortURLBatchStreamResponse_Result()
+}
+
+type CreateShortURLBatchStreamResponse_Entry_ struct {
+	Entry *CreateShortURLBatchStreamResponse_Entry `protobuf:"bytes,1,opt,name=entry,proto3,oneof"`
+}
+
+type CreateShortURLBatchStreamResponse_Summary_ struct {
+	Summary *CreateShortURLBatchStreamResponse_Summary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+func (*CreateShortURLBatchStreamResponse_Entry_) isCreateShortURLBatchStreamResponse_Result() {}
+
+func (*CreateShortURLBatchStreamResponse_Summary_) isCreateShortURLBatchStreamResponse_Result() {}
+
+type GetShortURLsByUserIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedFrom   int64                  `protobuf:"varint,5,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     int64                  `protobuf:"varint,6,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	Domain        string                 `protobuf:"bytes,7,opt,name=domain,proto3" json:"domain,omitempty"`
+	Search        string                 `protobuf:"bytes,8,opt,name=search,proto3" json:"search,omitempty"`
+	Sort          string                 `protobuf:"bytes,9,opt,name=sort,proto3" json:"sort,omitempty"`
+	Tag           string                 `protobuf:"bytes,10,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShortURLsByUserIDRequest) Reset() {
+	*x = GetShortURLsByUserIDRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShortURLsByUserIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShortURLsByUserIDRequest) ProtoMessage() {}
+
+func (x *GetShortURLsByUserIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShortURLsByUserIDRequest.ProtoReflect.Descriptor instead.
+func (*GetShortURLsByUserIDRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetShortURLsByUserIDRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetShortURLsByUserIDRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDRequest) GetCreatedFrom() int64 {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return 0
+}
+
+func (x *GetShortURLsByUserIDRequest) GetCreatedTo() int64 {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return 0
+}
+
+func (x *GetShortURLsByUserIDRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type GetShortURLsByUserIDResponse struct {
+	state         protoimpl.MessageState                                           `protogen:"open.v1"`
+	Entries       []*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	TotalCount    int64                                                            `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	NextCursor    string                                                           `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShortURLsByUserIDResponse) Reset() {
+	*x = GetShortURLsByUserIDResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShortURLsByUserIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShortURLsByUserIDResponse) ProtoMessage() {}
+
+func (x *GetShortURLsByUserIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShortURLsByUserIDResponse.ProtoReflect.Descriptor instead.
+func (*GetShortURLsByUserIDResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetShortURLsByUserIDResponse) GetEntries() []*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetShortURLsByUserIDResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GetShortURLsByUserIDResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type UpdateShortURLRequest struct {
+	state            protoimpl.MessageState                `protogen:"open.v1"`
+	ShortUri         string                                `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	Title            *string                               `protobuf:"bytes,2,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Description      *string                               `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Notes            *string                               `protobuf:"bytes,4,opt,name=notes,proto3,oneof" json:"notes,omitempty"`
+	Tags             *UpdateShortURLRequest_Tags           `protobuf:"bytes,5,opt,name=tags,proto3" json:"tags,omitempty"`
+	RedirectStatus   *int32                                `protobuf:"varint,6,opt,name=redirect_status,json=redirectStatus,proto3,oneof" json:"redirect_status,omitempty"`
+	QueryPassthrough *string                               `protobuf:"bytes,7,opt,name=query_passthrough,json=queryPassthrough,proto3,oneof" json:"query_passthrough,omitempty"`
+	PathPassthrough  *bool                                 `protobuf:"varint,8,opt,name=path_passthrough,json=pathPassthrough,proto3,oneof" json:"path_passthrough,omitempty"`
+	UtmParams        *UpdateShortURLRequest_UTMParams      `protobuf:"bytes,9,opt,name=utm_params,json=utmParams,proto3" json:"utm_params,omitempty"`
+	TargetingRules   *UpdateShortURLRequest_TargetingRules `protobuf:"bytes,10,opt,name=targeting_rules,json=targetingRules,proto3" json:"targeting_rules,omitempty"`
+	SplitVariants    *UpdateShortURLRequest_SplitVariants  `protobuf:"bytes,11,opt,name=split_variants,json=splitVariants,proto3" json:"split_variants,omitempty"`
+	Password         *string                               `protobuf:"bytes,12,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	MaxClicks        *int32                                `protobuf:"varint,13,opt,name=max_clicks,json=maxClicks,proto3,oneof" json:"max_clicks,omitempty"`
+	// 0 - снять ограничение
+	NotBefore     *int64  `protobuf:"varint,14,opt,name=not_before,json=notBefore,proto3,oneof" json:"not_before,omitempty"`
+	NotAfter      *int64  `protobuf:"varint,15,opt,name=not_after,json=notAfter,proto3,oneof" json:"not_after,omitempty"`
+	Disabled      *bool   `protobuf:"varint,16,opt,name=disabled,proto3,oneof" json:"disabled,omitempty"`
+	FallbackUrl   *string `protobuf:"bytes,17,opt,name=fallback_url,json=fallbackUrl,proto3,oneof" json:"fallback_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateShortURLRequest) Reset() {
+	*x = UpdateShortURLRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateShortURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateShortURLRequest) ProtoMessage() {}
+
+func (x *UpdateShortURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateShortURLRequest.ProtoReflect.Descriptor instead.
+func (*UpdateShortURLRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateShortURLRequest) GetShortUri() string {
+	if x != nil {
+		return x.ShortUri
+	}
+	return ""
+}
+
+func (x *UpdateShortURLRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateShortURLRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateShortURLRequest) GetNotes() string {
+	if x != nil && x.Notes != nil {
+		return *x.Notes
+	}
+	return ""
+}
+
+func (x *UpdateShortURLRequest) GetTags() *UpdateShortURLRequest_Tags {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UpdateShortURLRequest) GetRedirectStatus() int32 {
+	if x != nil && x.RedirectStatus != nil {
+		return *x.RedirectStatus
+	}
+	return 0
+}
+
+func (x *UpdateShortURLRequest) GetQueryPassthrough() string {
+	if x != nil && x.QueryPassthrough != nil {
+		return *x.QueryPassthrough
+	}
+	return ""
+}
+
+func (x *UpdateShortURLRequest) GetPathPassthrough() bool {
+	if x != nil && x.PathPassthrough != nil {
+		return *x.PathPassthrough
+	}
+	return false
+}
+
+func (x *UpdateShortURLRequest) GetUtmParams() *UpdateShortURLRequest_UTMParams {
+	if x != nil {
+		return x.UtmParams
+	}
+	return nil
+}
+
+func (x *UpdateShortURLRequest) GetTargetingRules() *UpdateShortURLRequest_TargetingRules {
+	if x != nil {
+		return x.TargetingRules
+	}
+	return nil
+}
+
+func (x *UpdateShortURLRequest) GetSplitVariants() *UpdateShortURLRequest_SplitVariants {
+	if x != nil {
+		return x.SplitVariants
+	}
+	return nil
+}
+
+func (x *UpdateShortURLRequest) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *UpdateShortURLRequest) GetMaxClicks() int32 {
+	if x != nil && x.MaxClicks != nil {
+		return *x.MaxClicks
+	}
+	return 0
+}
+
+func (x *UpdateShortURLRequest) GetNotBefore() int64 {
+	if x != nil && x.NotBefore != nil {
+		return *x.NotBefore
+	}
+	return 0
+}
+
+func (x *UpdateShortURLRequest) GetNotAfter() int64 {
+	if x != nil && x.NotAfter != nil {
+		return *x.NotAfter
+	}
+	return 0
+}
+
+func (x *UpdateShortURLRequest) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *UpdateShortURLRequest) GetFallbackUrl() string {
+	if x != nil && x.FallbackUrl != nil {
+		return *x.FallbackUrl
+	}
+	return ""
+}
+
+type UpdateShortURLResponse struct {
+	state         protoimpl.MessageState                                         `protogen:"open.v1"`
+	Entry         *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateShortURLResponse) Reset() {
+	*x = UpdateShortURLResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateShortURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateShortURLResponse) ProtoMessage() {}
+
+func (x *UpdateShortURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateShortURLResponse.ProtoReflect.Descriptor instead.
+func (*UpdateShortURLResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateShortURLResponse) GetEntry() *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type DeleteShortURLsByShortURIsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortURIs     []string               `protobuf:"bytes,1,rep,name=shortURIs,proto3" json:"shortURIs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteShortURLsByShortURIsRequest) Reset() {
+	*x = DeleteShortURLsByShortURIsRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteShortURLsByShortURIsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteShortURLsByShortURIsRequest) ProtoMessage() {}
+
+func (x *DeleteShortURLsByShortURIsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteShortURLsByShortURIsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteShortURLsByShortURIsRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteShortURLsByShortURIsRequest) GetShortURIs() []string {
+	if x != nil {
+		return x.ShortURIs
+	}
+	return nil
+}
+
+type DeleteShortURLsByShortURIsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteShortURLsByShortURIsResponse) Reset() {
+	*x = DeleteShortURLsByShortURIsResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteShortURLsByShortURIsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteShortURLsByShortURIsResponse) ProtoMessage() {}
+
+func (x *DeleteShortURLsByShortURIsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteShortURLsByShortURIsResponse.ProtoReflect.Descriptor instead.
+func (*DeleteShortURLsByShortURIsResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteShortURLsByShortURIsResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *DeleteShortURLsByShortURIsResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type GetDeleteJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeleteJobRequest) Reset() {
+	*x = GetDeleteJobRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeleteJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeleteJobRequest) ProtoMessage() {}
+
+func (x *GetDeleteJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeleteJobRequest.ProtoReflect.Descriptor instead.
+func (*GetDeleteJobRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetDeleteJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type GetDeleteJobResponse struct {
+	state         protoimpl.MessageState                            `protogen:"open.v1"`
+	JobId         string                                            `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status        string                                            `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Entries       []*GetDeleteJobResponse_GetDeleteJobResponseEntry `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeleteJobResponse) Reset() {
+	*x = GetDeleteJobResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeleteJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeleteJobResponse) ProtoMessage() {}
+
+func (x *GetDeleteJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeleteJobResponse.ProtoReflect.Descriptor instead.
+func (*GetDeleteJobResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetDeleteJobResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GetDeleteJobResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetDeleteJobResponse) GetEntries() []*GetDeleteJobResponse_GetDeleteJobResponseEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetSplitStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSplitStatsRequest) Reset() {
+	*x = GetSplitStatsRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSplitStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSplitStatsRequest) ProtoMessage() {}
+
+func (x *GetSplitStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSplitStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetSplitStatsRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetSplitStatsRequest) GetShortUri() string {
+	if x != nil {
+		return x.ShortUri
+	}
+	return ""
+}
+
+type GetSplitStatsResponse struct {
+	state         protoimpl.MessageState                              `protogen:"open.v1"`
+	Entries       []*GetSplitStatsResponse_GetSplitStatsResponseEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSplitStatsResponse) Reset() {
+	*x = GetSplitStatsResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSplitStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSplitStatsResponse) ProtoMessage() {}
+
+func (x *GetSplitStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSplitStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetSplitStatsResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetSplitStatsResponse) GetEntries() []*GetSplitStatsResponse_GetSplitStatsResponseEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetDeletedShortURLsByUserIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeletedShortURLsByUserIDRequest) Reset() {
+	*x = GetDeletedShortURLsByUserIDRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeletedShortURLsByUserIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeletedShortURLsByUserIDRequest) ProtoMessage() {}
+
+func (x *GetDeletedShortURLsByUserIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeletedShortURLsByUserIDRequest.ProtoReflect.Descriptor instead.
+func (*GetDeletedShortURLsByUserIDRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{22}
+}
+
+type GetDeletedShortURLsByUserIDResponse struct {
+	state         protoimpl.MessageState                                                          `protogen:"open.v1"`
+	Entries       []*GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeletedShortURLsByUserIDResponse) Reset() {
+	*x = GetDeletedShortURLsByUserIDResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeletedShortURLsByUserIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeletedShortURLsByUserIDResponse) ProtoMessage() {}
+
+func (x *GetDeletedShortURLsByUserIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeletedShortURLsByUserIDResponse.ProtoReflect.Descriptor instead.
+func (*GetDeletedShortURLsByUserIDResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetDeletedShortURLsByUserIDResponse) GetEntries() []*GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type RestoreShortURLsByShortURIsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortURIs     []string               `protobuf:"bytes,1,rep,name=shortURIs,proto3" json:"shortURIs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreShortURLsByShortURIsRequest) Reset() {
+	*x = RestoreShortURLsByShortURIsRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreShortURLsByShortURIsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreShortURLsByShortURIsRequest) ProtoMessage() {}
+
+func (x *RestoreShortURLsByShortURIsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreShortURLsByShortURIsRequest.ProtoReflect.Descriptor instead.
+func (*RestoreShortURLsByShortURIsRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RestoreShortURLsByShortURIsRequest) GetShortURIs() []string {
+	if x != nil {
+		return x.ShortURIs
+	}
+	return nil
+}
+
+type RestoreShortURLsByShortURIsResponse struct {
+	state         protoimpl.MessageState                                                          `protogen:"open.v1"`
+	Entries       []*RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreShortURLsByShortURIsResponse) Reset() {
+	*x = RestoreShortURLsByShortURIsResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreShortURLsByShortURIsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreShortURLsByShortURIsResponse) ProtoMessage() {}
+
+func (x *RestoreShortURLsByShortURIsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreShortURLsByShortURIsResponse.ProtoReflect.Descriptor instead.
+func (*RestoreShortURLsByShortURIsResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RestoreShortURLsByShortURIsResponse) GetEntries() []*RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetUserSettingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserSettingsRequest) Reset() {
+	*x = GetUserSettingsRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserSettingsRequest) ProtoMessage() {}
+
+func (x *GetUserSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserSettingsRequest.ProtoReflect.Descriptor instead.
+func (*GetUserSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{26}
+}
+
+type GetUserSettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FallbackUrl   string                 `protobuf:"bytes,1,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserSettingsResponse) Reset() {
+	*x = GetUserSettingsResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserSettingsResponse) ProtoMessage() {}
+
+func (x *GetUserSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserSettingsResponse.ProtoReflect.Descriptor instead.
+func (*GetUserSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetUserSettingsResponse) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
+	}
+	return ""
+}
+
+type UpdateUserSettingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// адрес перенаправления по умолчанию для недоступных коротких ссылок пользователя, "" - адрес из конфигурации
+	FallbackUrl   string `protobuf:"bytes,1,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserSettingsRequest) Reset() {
+	*x = UpdateUserSettingsRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserSettingsRequest) ProtoMessage() {}
+
+func (x *UpdateUserSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserSettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UpdateUserSettingsRequest) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
+	}
+	return ""
+}
+
+type UpdateUserSettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FallbackUrl   string                 `protobuf:"bytes,1,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserSettingsResponse) Reset() {
+	*x = UpdateUserSettingsResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserSettingsResponse) ProtoMessage() {}
+
+func (x *UpdateUserSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserSettingsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UpdateUserSettingsResponse) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
+	}
+	return ""
+}
+
+type PingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{30}
+}
+
+type PingResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseActive bool                   `protobuf:"varint,1,opt,name=databaseActive,proto3" json:"databaseActive,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingResponse) ProtoMessage() {}
+
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
+func (*PingResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *PingResponse) GetDatabaseActive() bool {
+	if x != nil {
+		return x.DatabaseActive
+	}
+	return false
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_grpc_shortener_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{32}
+}
+
+type GetStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UrlCount      int64                  `protobuf:"varint,1,opt,name=url_count,json=urlCount,proto3" json:"url_count,omitempty"`
+	UserCount     int64                  `protobuf:"varint,2,opt,name=user_count,json=userCount,proto3" json:"user_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_grpc_shortener_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetStatsResponse) GetUrlCount() int64 {
+	if x != nil {
+		return x.UrlCount
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetUserCount() int64 {
+	if x != nil {
+		return x.UserCount
+	}
+	return 0
+}
+
+type CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId    string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	OriginalUrl      string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	Title            string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description      string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Notes            string                 `protobuf:"bytes,5,opt,name=notes,proto3" json:"notes,omitempty"`
+	Tags             []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	RedirectStatus   int32                  `protobuf:"varint,7,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	QueryPassthrough string                 `protobuf:"bytes,8,opt,name=query_passthrough,json=queryPassthrough,proto3" json:"query_passthrough,omitempty"`
+	PathPassthrough  bool                   `protobuf:"varint,9,opt,name=path_passthrough,json=pathPassthrough,proto3" json:"path_passthrough,omitempty"`
+	UtmParams        map[string]string      `protobuf:"bytes,10,rep,name=utm_params,json=utmParams,proto3" json:"utm_params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TargetingRules   []*TargetingRule       `protobuf:"bytes,11,rep,name=targeting_rules,json=targetingRules,proto3" json:"targeting_rules,omitempty"`
+	SplitVariants    []*SplitVariant        `protobuf:"bytes,12,rep,name=split_variants,json=splitVariants,proto3" json:"split_variants,omitempty"`
+	Password         string                 `protobuf:"bytes,13,opt,name=password,proto3" json:"password,omitempty"`
+	MaxClicks        int32                  `protobuf:"varint,14,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	NotBefore        int64                  `protobuf:"varint,15,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter         int64                  `protobuf:"varint,16,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	Disabled         bool                   `protobuf:"varint,17,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	FallbackUrl      string                 `protobuf:"bytes,18,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	ShortDomain      string                 `protobuf:"bytes,19,opt,name=short_domain,json=shortDomain,proto3" json:"short_domain,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) Reset() {
+	*x = CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry{}
+	mi := &file_grpc_shortener_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) ProtoMessage() {}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry.ProtoReflect.Descriptor instead.
+func (*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetRedirectStatus() int32 {
+	if x != nil {
+		return x.RedirectStatus
+	}
+	return 0
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetQueryPassthrough() string {
+	if x != nil {
+		return x.QueryPassthrough
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetPathPassthrough() bool {
+	if x != nil {
+		return x.PathPassthrough
+	}
+	return false
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetUtmParams() map[string]string {
+	if x != nil {
+		return x.UtmParams
+	}
+	return nil
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetTargetingRules() []*TargetingRule {
+	if x != nil {
+		return x.TargetingRules
+	}
+	return nil
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetSplitVariants() []*SplitVariant {
+	if x != nil {
+		return x.SplitVariants
+	}
+	return nil
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetNotBefore() int64 {
+	if x != nil {
+		return x.NotBefore
+	}
+	return 0
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetNotAfter() int64 {
+	if x != nil {
+		return x.NotAfter
+	}
+	return 0
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetFallbackUrl() string {
+	if x != nil {
+		return x.FallbackUrl
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetShortDomain() string {
+	if x != nil {
+		return x.ShortDomain
+	}
+	return ""
+}
+
+type CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	ShortUri      string                 `protobuf:"bytes,3,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) Reset() {
+	*x = CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry{}
+	mi := &file_grpc_shortener_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) ProtoMessage() {}
+
+func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry.ProtoReflect.Descriptor instead.
+func (*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) GetShortUri() string {
+	if x != nil {
+		return x.ShortUri
+	}
+	return ""
+}
+
+type CreateShortURLBatchStreamResponse_Entry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	ShortUri      string                 `protobuf:"bytes,3,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	// код gRPC ошибки короткой ссылки, 0 - короткая ссылка создана
+	ErrorCode     int32  `protobuf:"varint,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShortURLBatchStreamResponse_Entry) Reset() {
+	*x = CreateShortURLBatchStreamResponse_Entry{}
+	mi := &file_grpc_shortener_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLBatchStreamResponse_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLBatchStreamResponse_Entry) ProtoMessage() {}
+
+func (x *CreateShortURLBatchStreamResponse_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLBatchStreamResponse_Entry.ProtoReflect.Descriptor instead.
+func (*CreateShortURLBatchStreamResponse_Entry) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{11, 0}
+}
+
+func (x *CreateShortURLBatchStreamResponse_Entry) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchStreamResponse_Entry) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchStreamResponse_Entry) GetShortUri() string {
+	if x != nil {
+		return x.ShortUri
+	}
+	return ""
+}
+
+func (x *CreateShortURLBatchStreamResponse_Entry) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *CreateShortURLBatchStreamResponse_Entry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CreateShortURLBatchStreamResponse_Summary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Received      int32                  `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"`
+	Created       int32                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShortURLBatchStreamResponse_Summary) Reset() {
+	*x = CreateShortURLBatchStreamResponse_Summary{}
+	mi := &file_grpc_shortener_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShortURLBatchStreamResponse_Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShortURLBatchStreamResponse_Summary) ProtoMessage() {}
+
+func (x *CreateShortURLBatchStreamResponse_Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShortURLBatchStreamResponse_Summary.ProtoReflect.Descriptor instead.
+func (*CreateShortURLBatchStreamResponse_Summary) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{11, 1}
+}
+
+func (x *CreateShortURLBatchStreamResponse_Summary) GetReceived() int32 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *CreateShortURLBatchStreamResponse_Summary) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *CreateShortURLBatchStreamResponse_Summary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl           string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	OriginalUrl        string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	CreatedAt          int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	IsDeleted          bool                   `protobuf:"varint,4,opt,name=is_deleted,json=isDeleted,proto3" json:"is_deleted,omitempty"`
+	Title              string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Description        string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Notes              string                 `protobuf:"bytes,7,opt,name=notes,proto3" json:"notes,omitempty"`
+	Tags               []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	PreviewTitle       string                 `protobuf:"bytes,9,opt,name=preview_title,json=previewTitle,proto3" json:"preview_title,omitempty"`
+	PreviewDescription string                 `protobuf:"bytes,10,opt,name=preview_description,json=previewDescription,proto3" json:"preview_description,omitempty"`
+	PreviewImageUrl    string                 `protobuf:"bytes,11,opt,name=preview_image_url,json=previewImageUrl,proto3" json:"preview_image_url,omitempty"`
+	RedirectStatus     int32                  `protobuf:"varint,12,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	QueryPassthrough   string                 `protobuf:"bytes,13,opt,name=query_passthrough,json=queryPassthrough,proto3" json:"query_passthrough,omitempty"`
+	PathPassthrough    bool                   `protobuf:"varint,14,opt,name=path_passthrough,json=pathPassthrough,proto3" json:"path_passthrough,omitempty"`
+	UtmParams          map[string]string      `protobuf:"bytes,15,rep,name=utm_params,json=utmParams,proto3" json:"utm_params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TargetingRules     []*TargetingRule       `protobuf:"bytes,16,rep,name=targeting_rules,json=targetingRules,proto3" json:"targeting_rules,omitempty"`
+	SplitVariants      []*SplitVariant        `protobuf:"bytes,17,rep,name=split_variants,json=splitVariants,proto3" json:"split_variants,omitempty"`
+	PasswordProtected  bool                   `protobuf:"varint,18,opt,name=password_protected,json=passwordProtected,proto3" json:"password_protected,omitempty"`
+	MaxClicks          int32                  `protobuf:"varint,19,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	ClickCount         int64                  `protobuf:"varint,20,opt,name=click_count,json=clickCount,proto3" json:"click_count,omitempty"`
+	NotBefore          int64                  `protobuf:"varint,21,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter           int64                  `protobuf:"varint,22,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	Disabled           bool                   `protobuf:"varint,23,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	FallbackUrl        string                 `protobuf:"bytes,24,opt,name=fallback_url,json=fallbackUrl,proto3" json:"fallback_url,omitempty"`
+	ShortUri           string                 `protobuf:"bytes,25,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	ShortDomain        string                 `protobuf:"bytes,26,opt,name=short_domain,json=shortDomain,proto3" json:"short_domain,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) Reset() {
+	*x = GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry{}
+	mi := &file_grpc_shortener_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) ProtoMessage() {}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry.ProtoReflect.Descriptor instead.
+func (*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{13, 0}
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetIsDeleted() bool {
+	if x != nil {
+		return x.IsDeleted
+	}
+	return false
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetPreviewTitle() string {
+	if x != nil {
+		return x.PreviewTitle
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetPreviewDescription() string {
+	if x != nil {
+		return x.PreviewDescription
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetPreviewImageUrl() string {
+	if x != nil {
+		return x.PreviewImageUrl
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetRedirectStatus() int32 {
+	if x != nil {
+		return x.RedirectStatus
+	}
+	return 0
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetQueryPassthrough() string {
+	if x != nil {
+		return x.QueryPassthrough
+	}
+	return ""
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetPathPassthrough() bool {
+	if x != nil {
+		return x.PathPassthrough
+	}
+	return false
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetUtmParams() map[string]string {
+	if x != nil {
+		return x.UtmParams
+	}
+	return nil
+}
+
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetTargetingRules() []*TargetingRule {
+	if x != nil {
+		return x.TargetingRules
+	}
+	return nil
+}
 
-func (x *GetShortURLsByUserIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[7]
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetSplitVariants() []*SplitVariant {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.SplitVariants
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use GetShortURLsByUserIDResponse.ProtoReflect.Descriptor instead.
-func (*GetShortURLsByUserIDResponse) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{7}
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetPasswordProtected() bool {
+	if x != nil {
+		return x.PasswordProtected
+	}
+	return false
 }
 
-func (x *GetShortURLsByUserIDResponse) GetEntries() []*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry {
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetMaxClicks() int32 {
 	if x != nil {
-		return x.Entries
+		return x.MaxClicks
 	}
-	return nil
+	return 0
 }
 
-type DeleteShortURLsByShortURIsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortURIs     []string               `protobuf:"bytes,1,rep,name=shortURIs,proto3" json:"shortURIs,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetClickCount() int64 {
+	if x != nil {
+		return x.ClickCount
+	}
+	return 0
 }
 
-func (x *DeleteShortURLsByShortURIsRequest) Reset() {
-	*x = DeleteShortURLsByShortURIsRequest{}
-	mi := &file_grpc_shortener_proto_msgTypes[8]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetNotBefore() int64 {
+	if x != nil {
+		return x.NotBefore
+	}
+	return 0
 }
 
-func (x *DeleteShortURLsByShortURIsRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetNotAfter() int64 {
+	if x != nil {
+		return x.NotAfter
+	}
+	return 0
 }
 
-func (*DeleteShortURLsByShortURIsRequest) ProtoMessage() {}
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
 
-func (x *DeleteShortURLsByShortURIsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[8]
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetFallbackUrl() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.FallbackUrl
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use DeleteShortURLsByShortURIsRequest.ProtoReflect.Descriptor instead.
-func (*DeleteShortURLsByShortURIsRequest) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{8}
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetShortUri() string {
+	if x != nil {
+		return x.ShortUri
+	}
+	return ""
 }
 
-func (x *DeleteShortURLsByShortURIsRequest) GetShortURIs() []string {
+func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetShortDomain() string {
 	if x != nil {
-		return x.ShortURIs
+		return x.ShortDomain
 	}
-	return nil
+	return ""
 }
 
-type DeleteShortURLsByShortURIsResponse struct {
+type UpdateShortURLRequest_Tags struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteShortURLsByShortURIsResponse) Reset() {
-	*x = DeleteShortURLsByShortURIsResponse{}
-	mi := &file_grpc_shortener_proto_msgTypes[9]
+func (x *UpdateShortURLRequest_Tags) Reset() {
+	*x = UpdateShortURLRequest_Tags{}
+	mi := &file_grpc_shortener_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteShortURLsByShortURIsResponse) String() string {
+func (x *UpdateShortURLRequest_Tags) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteShortURLsByShortURIsResponse) ProtoMessage() {}
+func (*UpdateShortURLRequest_Tags) ProtoMessage() {}
 
-func (x *DeleteShortURLsByShortURIsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[9]
+func (x *UpdateShortURLRequest_Tags) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -464,39 +2707,40 @@ func (x *DeleteShortURLsByShortURIsResponse) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteShortURLsByShortURIsResponse.ProtoReflect.Descriptor instead.
-func (*DeleteShortURLsByShortURIsResponse) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use UpdateShortURLRequest_Tags.ProtoReflect.Descriptor instead.
+func (*UpdateShortURLRequest_Tags) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{14, 0}
 }
 
-func (x *DeleteShortURLsByShortURIsResponse) GetAccepted() bool {
+func (x *UpdateShortURLRequest_Tags) GetValues() []string {
 	if x != nil {
-		return x.Accepted
+		return x.Values
 	}
-	return false
+	return nil
 }
 
-type PingRequest struct {
+type UpdateShortURLRequest_UTMParams struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        map[string]string      `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PingRequest) Reset() {
-	*x = PingRequest{}
-	mi := &file_grpc_shortener_proto_msgTypes[10]
+func (x *UpdateShortURLRequest_UTMParams) Reset() {
+	*x = UpdateShortURLRequest_UTMParams{}
+	mi := &file_grpc_shortener_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PingRequest) String() string {
+func (x *UpdateShortURLRequest_UTMParams) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PingRequest) ProtoMessage() {}
+func (*UpdateShortURLRequest_UTMParams) ProtoMessage() {}
 
-func (x *PingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[10]
+func (x *UpdateShortURLRequest_UTMParams) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -507,33 +2751,40 @@ func (x *PingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
-func (*PingRequest) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use UpdateShortURLRequest_UTMParams.ProtoReflect.Descriptor instead.
+func (*UpdateShortURLRequest_UTMParams) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{14, 1}
 }
 
-type PingResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	DatabaseActive bool                   `protobuf:"varint,1,opt,name=databaseActive,proto3" json:"databaseActive,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *UpdateShortURLRequest_UTMParams) GetValues() map[string]string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
 }
 
-func (x *PingResponse) Reset() {
-	*x = PingResponse{}
-	mi := &file_grpc_shortener_proto_msgTypes[11]
+type UpdateShortURLRequest_TargetingRules struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*TargetingRule       `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateShortURLRequest_TargetingRules) Reset() {
+	*x = UpdateShortURLRequest_TargetingRules{}
+	mi := &file_grpc_shortener_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PingResponse) String() string {
+func (x *UpdateShortURLRequest_TargetingRules) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PingResponse) ProtoMessage() {}
+func (*UpdateShortURLRequest_TargetingRules) ProtoMessage() {}
 
-func (x *PingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[11]
+func (x *UpdateShortURLRequest_TargetingRules) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,39 +2795,40 @@ func (x *PingResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
-func (*PingResponse) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use UpdateShortURLRequest_TargetingRules.ProtoReflect.Descriptor instead.
+func (*UpdateShortURLRequest_TargetingRules) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{14, 2}
 }
 
-func (x *PingResponse) GetDatabaseActive() bool {
+func (x *UpdateShortURLRequest_TargetingRules) GetValues() []*TargetingRule {
 	if x != nil {
-		return x.DatabaseActive
+		return x.Values
 	}
-	return false
+	return nil
 }
 
-type GetStatsRequest struct {
+type UpdateShortURLRequest_SplitVariants struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*SplitVariant        `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsRequest) Reset() {
-	*x = GetStatsRequest{}
-	mi := &file_grpc_shortener_proto_msgTypes[12]
+func (x *UpdateShortURLRequest_SplitVariants) Reset() {
+	*x = UpdateShortURLRequest_SplitVariants{}
+	mi := &file_grpc_shortener_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsRequest) String() string {
+func (x *UpdateShortURLRequest_SplitVariants) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsRequest) ProtoMessage() {}
+func (*UpdateShortURLRequest_SplitVariants) ProtoMessage() {}
 
-func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[12]
+func (x *UpdateShortURLRequest_SplitVariants) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -587,34 +2839,41 @@ func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetStatsRequest) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use UpdateShortURLRequest_SplitVariants.ProtoReflect.Descriptor instead.
+func (*UpdateShortURLRequest_SplitVariants) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{14, 3}
 }
 
-type GetStatsResponse struct {
+func (x *UpdateShortURLRequest_SplitVariants) GetValues() []*SplitVariant {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type GetDeleteJobResponse_GetDeleteJobResponseEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UrlCount      int64                  `protobuf:"varint,1,opt,name=url_count,json=urlCount,proto3" json:"url_count,omitempty"`
-	UserCount     int64                  `protobuf:"varint,2,opt,name=user_count,json=userCount,proto3" json:"user_count,omitempty"`
+	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsResponse) Reset() {
-	*x = GetStatsResponse{}
-	mi := &file_grpc_shortener_proto_msgTypes[13]
+func (x *GetDeleteJobResponse_GetDeleteJobResponseEntry) Reset() {
+	*x = GetDeleteJobResponse_GetDeleteJobResponseEntry{}
+	mi := &file_grpc_shortener_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsResponse) String() string {
+func (x *GetDeleteJobResponse_GetDeleteJobResponseEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsResponse) ProtoMessage() {}
+func (*GetDeleteJobResponse_GetDeleteJobResponseEntry) ProtoMessage() {}
 
-func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[13]
+func (x *GetDeleteJobResponse_GetDeleteJobResponseEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -625,48 +2884,50 @@ func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetStatsResponse) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use GetDeleteJobResponse_GetDeleteJobResponseEntry.ProtoReflect.Descriptor instead.
+func (*GetDeleteJobResponse_GetDeleteJobResponseEntry) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{19, 0}
 }
 
-func (x *GetStatsResponse) GetUrlCount() int64 {
+func (x *GetDeleteJobResponse_GetDeleteJobResponseEntry) GetShortUri() string {
 	if x != nil {
-		return x.UrlCount
+		return x.ShortUri
 	}
-	return 0
+	return ""
 }
 
-func (x *GetStatsResponse) GetUserCount() int64 {
+func (x *GetDeleteJobResponse_GetDeleteJobResponseEntry) GetStatus() string {
 	if x != nil {
-		return x.UserCount
+		return x.Status
 	}
-	return 0
+	return ""
 }
 
-type CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry struct {
+type GetSplitStatsResponse_GetSplitStatsResponseEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
-	OriginalUrl   string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Clicks        int64                  `protobuf:"varint,4,opt,name=clicks,proto3" json:"clicks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) Reset() {
-	*x = CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry{}
-	mi := &file_grpc_shortener_proto_msgTypes[14]
+func (x *GetSplitStatsResponse_GetSplitStatsResponseEntry) Reset() {
+	*x = GetSplitStatsResponse_GetSplitStatsResponseEntry{}
+	mi := &file_grpc_shortener_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) String() string {
+func (x *GetSplitStatsResponse_GetSplitStatsResponseEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) ProtoMessage() {}
+func (*GetSplitStatsResponse_GetSplitStatsResponseEntry) ProtoMessage() {}
 
-func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[14]
+func (x *GetSplitStatsResponse_GetSplitStatsResponseEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -677,48 +2938,64 @@ func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) ProtoReflec
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry.ProtoReflect.Descriptor instead.
-func (*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{4, 0}
+// Deprecated: Use GetSplitStatsResponse_GetSplitStatsResponseEntry.ProtoReflect.Descriptor instead.
+func (*GetSplitStatsResponse_GetSplitStatsResponseEntry) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{21, 0}
 }
 
-func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetCorrelationId() string {
+func (x *GetSplitStatsResponse_GetSplitStatsResponseEntry) GetName() string {
 	if x != nil {
-		return x.CorrelationId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) GetOriginalUrl() string {
+func (x *GetSplitStatsResponse_GetSplitStatsResponseEntry) GetUrl() string {
 	if x != nil {
-		return x.OriginalUrl
+		return x.Url
 	}
 	return ""
 }
 
-type CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry struct {
+func (x *GetSplitStatsResponse_GetSplitStatsResponseEntry) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *GetSplitStatsResponse_GetSplitStatsResponseEntry) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+type GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
-	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	OriginalUrl   string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	DeletedAt     int64                  `protobuf:"varint,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	ShortUri      string                 `protobuf:"bytes,4,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) Reset() {
-	*x = CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry{}
-	mi := &file_grpc_shortener_proto_msgTypes[15]
+func (x *GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) Reset() {
+	*x = GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry{}
+	mi := &file_grpc_shortener_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) String() string {
+func (x *GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) ProtoMessage() {}
+func (*GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) ProtoMessage() {}
 
-func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[15]
+func (x *GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -729,48 +3006,62 @@ func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) ProtoRefl
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry.ProtoReflect.Descriptor instead.
-func (*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{5, 0}
+// Deprecated: Use GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry.ProtoReflect.Descriptor instead.
+func (*GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{23, 0}
 }
 
-func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) GetCorrelationId() string {
+func (x *GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) GetShortUrl() string {
 	if x != nil {
-		return x.CorrelationId
+		return x.ShortUrl
 	}
 	return ""
 }
 
-func (x *CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry) GetShortUrl() string {
+func (x *GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) GetOriginalUrl() string {
 	if x != nil {
-		return x.ShortUrl
+		return x.OriginalUrl
 	}
 	return ""
 }
 
-type GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry struct {
+func (x *GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) GetDeletedAt() int64 {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return 0
+}
+
+func (x *GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry) GetShortUri() string {
+	if x != nil {
+		return x.ShortUri
+	}
+	return ""
+}
+
+type RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
-	OriginalUrl   string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	ShortUri      string                 `protobuf:"bytes,1,opt,name=short_uri,json=shortUri,proto3" json:"short_uri,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) Reset() {
-	*x = GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry{}
-	mi := &file_grpc_shortener_proto_msgTypes[16]
+func (x *RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry) Reset() {
+	*x = RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry{}
+	mi := &file_grpc_shortener_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) String() string {
+func (x *RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) ProtoMessage() {}
+func (*RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry) ProtoMessage() {}
 
-func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_shortener_proto_msgTypes[16]
+func (x *RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_shortener_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -781,21 +3072,21 @@ func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) ProtoRef
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry.ProtoReflect.Descriptor instead.
-func (*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) Descriptor() ([]byte, []int) {
-	return file_grpc_shortener_proto_rawDescGZIP(), []int{7, 0}
+// Deprecated: Use RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry.ProtoReflect.Descriptor instead.
+func (*RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry) Descriptor() ([]byte, []int) {
+	return file_grpc_shortener_proto_rawDescGZIP(), []int{25, 0}
 }
 
-func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetShortUrl() string {
+func (x *RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry) GetShortUri() string {
 	if x != nil {
-		return x.ShortUrl
+		return x.ShortUri
 	}
 	return ""
 }
 
-func (x *GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry) GetOriginalUrl() string {
+func (x *RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry) GetStatus() string {
 	if x != nil {
-		return x.OriginalUrl
+		return x.Status
 	}
 	return ""
 }
@@ -804,127 +3095,604 @@ var File_grpc_shortener_proto protoreflect.FileDescriptor
 
 var file_grpc_shortener_proto_rawDesc = []byte{
 	0x0a, 0x14, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x67, 0x72, 0x70, 0x63, 0x22, 0x3a, 0x0a, 0x15,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x67, 0x72, 0x70, 0x63, 0x22, 0x87, 0x01, 0x0a,
+	0x0d, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x22, 0x4c, 0x0a, 0x0c, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x56,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x22, 0xf8, 0x05, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72,
+	0x6c, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x72, 0x65,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x11,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67,
+	0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x71, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61,
+	0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61, 0x74,
+	0x68, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0f, 0x70, 0x61, 0x74, 0x68, 0x50, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72,
+	0x6f, 0x75, 0x67, 0x68, 0x12, 0x49, 0x0a, 0x0a, 0x75, 0x74, 0x6d, 0x5f, 0x70, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
 	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
-	0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69,
-	0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x22, 0x52, 0x0a, 0x16, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x12,
-	0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x22, 0x31, 0x0a, 0x12,
-	0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x22,
-	0x4f, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f,
-	0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74,
-	0x55, 0x72, 0x69, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c,
-	0x22, 0xe5, 0x01, 0x0a, 0x1a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x55, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x75, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12,
+	0x3c, 0x0a, 0x0f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x75, 0x6c,
+	0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0e, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x39, 0x0a,
+	0x0e, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18,
+	0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x70, 0x6c,
+	0x69, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x52, 0x0d, 0x73, 0x70, 0x6c, 0x69, 0x74,
+	0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x69, 0x63,
+	0x6b, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69,
+	0x63, 0x6b, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72,
+	0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f,
+	0x72, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12,
+	0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x66,
+	0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x12, 0x21,
+	0x0a, 0x0c, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x1a, 0x3c, 0x0a, 0x0e, 0x55, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x52, 0x0a, 0x16, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x72, 0x6c, 0x22, 0x4d, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x22, 0x72, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x72, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x22, 0xe1, 0x01, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69,
+	0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x1b, 0x0a, 0x06,
+	0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06,
+	0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76,
+	0x65, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12,
+	0x1e, 0x0a, 0x0a, 0x66, 0x6f, 0x72, 0x65, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x6f, 0x72, 0x65, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12,
+	0x1e, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x22, 0x54, 0x0a, 0x19, 0x47, 0x65,
+	0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x22, 0xc9, 0x07, 0x0a, 0x1a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74,
 	0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
 	0x5a, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
 	0x32, 0x40, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68,
 	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
 	0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x6b, 0x0a, 0x1f, 0x43,
+	0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0xce, 0x06, 0x0a, 0x1f,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e,
+	0x61, 0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74,
+	0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x72,
+	0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x70, 0x61,
+	0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x71, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67,
+	0x68, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68,
+	0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x70, 0x61, 0x74,
+	0x68, 0x50, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x12, 0x6e, 0x0a, 0x0a,
+	0x75, 0x74, 0x6d, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x4f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x2e, 0x55, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x09, 0x75, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x3c, 0x0a, 0x0f,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18,
+	0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0e, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0e, 0x73, 0x70,
+	0x6c, 0x69, 0x74, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x56,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x52, 0x0d, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x56, 0x61, 0x72,
+	0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08,
+	0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61, 0x6c, 0x6c,
+	0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x13, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x1a, 0x3c,
+	0x0a, 0x0e, 0x55, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x81, 0x02, 0x0a,
+	0x1b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x07,
+	0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x42, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x83, 0x01, 0x0a, 0x20, 0x43,
 	0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74,
-	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x25,
-	0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
-	0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69,
-	0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x22, 0xe3, 0x01, 0x0a, 0x1b, 0x43, 0x72, 0x65,
+	0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69,
+	0x22, 0x7e, 0x0a, 0x20, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x5a, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x40, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65,
 	0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72,
-	0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42,
-	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43, 0x72, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x22, 0xba, 0x03, 0x0a, 0x21, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65,
 	0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65,
-	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x66, 0x0a, 0x20, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f,
-	0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49,
-	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x22, 0x35,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x48, 0x00, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x4b, 0x0a,
+	0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x48,
+	0x00, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x1a, 0x9d, 0x01, 0x0a, 0x05, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f,
+	0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72,
+	0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x72, 0x69, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x1a, 0x57, 0x0a, 0x07, 0x53, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66,
+	0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x61, 0x69,
+	0x6c, 0x65, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x93, 0x02,
 	0x0a, 0x1b, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79,
 	0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
 	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
-	0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0xe1, 0x01, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f,
-	0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5d, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x43, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47,
-	0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65,
-	0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x53,
-	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e,
-	0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x62, 0x0a, 0x20, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72,
-	0x74, 0x55, 0x52, 0x4c, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f,
-	0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68,
-	0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e,
-	0x61, 0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72,
-	0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x22, 0x41, 0x0a, 0x21, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x53, 0x68,
-	0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
-	0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x22, 0x40, 0x0a, 0x22,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42,
-	0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x22, 0x0d,
-	0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x36, 0x0a,
-	0x0c, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a,
-	0x0e, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x41,
-	0x63, 0x74, 0x69, 0x76, 0x65, 0x22, 0x11, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4e, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53,
-	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09,
-	0x75, 0x72, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x08, 0x75, 0x72, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x73, 0x65,
-	0x72, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75,
-	0x73, 0x65, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x32, 0xb8, 0x04, 0x0a, 0x10, 0x53, 0x68, 0x6f,
-	0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a,
-	0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x12,
-	0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f,
-	0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x67,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63,
+	0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x75, 0x72,
+	0x73, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x54, 0x6f, 0x12, 0x16, 0x0a,
+	0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x12, 0x0a,
+	0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x74, 0x61, 0x67, 0x22, 0x9b, 0x0a, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5d, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x43, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72,
+	0x73, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43,
+	0x75, 0x72, 0x73, 0x6f, 0x72, 0x1a, 0xd9, 0x08, 0x0a, 0x20, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69,
+	0x73, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x5f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x12,
+	0x2f, 0x0a, 0x13, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x70, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x2a, 0x0a, 0x11, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x5f, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x12, 0x27, 0x0a, 0x0f,
+	0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x70,
+	0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x71, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75,
+	0x67, 0x68, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x74,
+	0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x70, 0x61,
+	0x74, 0x68, 0x50, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x12, 0x71, 0x0a,
+	0x0a, 0x75, 0x74, 0x6d, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x52, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x55, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x75, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x12, 0x3c, 0x0a, 0x0f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x75,
+	0x6c, 0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0e,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x39,
+	0x0a, 0x0e, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73,
+	0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x70,
+	0x6c, 0x69, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x52, 0x0d, 0x73, 0x70, 0x6c, 0x69,
+	0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x50,
+	0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f,
+	0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61,
+	0x78, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6c, 0x69, 0x63, 0x6b,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x63, 0x6c,
+	0x69, 0x63, 0x6b, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f,
+	0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x6f,
+	0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61,
+	0x66, 0x74, 0x65, 0x72, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41,
+	0x66, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x18, 0x17, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x55, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69,
+	0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69,
+	0x12, 0x21, 0x0a, 0x0c, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x18, 0x1a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x1a, 0x3c, 0x0a, 0x0e, 0x55, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x82, 0x0a, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x12, 0x19, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65,
+	0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6e, 0x6f,
+	0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x05, 0x6e, 0x6f, 0x74,
+	0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x54, 0x61, 0x67, 0x73, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x2c, 0x0a, 0x0f, 0x72,
+	0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x0e, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x11, 0x71, 0x75, 0x65,
+	0x72, 0x79, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x10, 0x71, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61, 0x73,
+	0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10, 0x70,
+	0x61, 0x74, 0x68, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x0f, 0x70, 0x61, 0x74, 0x68, 0x50, 0x61, 0x73,
+	0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x88, 0x01, 0x01, 0x12, 0x44, 0x0a, 0x0a, 0x75,
+	0x74, 0x6d, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x55, 0x54, 0x4d,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x09, 0x75, 0x74, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x12, 0x53, 0x0a, 0x0f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e,
+	0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x0e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e,
+	0x67, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x50, 0x0a, 0x0e, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f,
+	0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x70, 0x6c, 0x69,
+	0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x0d, 0x73, 0x70, 0x6c, 0x69, 0x74,
+	0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x48, 0x06, 0x52, 0x08, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x61, 0x78,
+	0x5f, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x48, 0x07, 0x52,
+	0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a,
+	0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x08, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x20, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72,
+	0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0a, 0x52, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x5f, 0x75, 0x72, 0x6c, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0b, 0x52, 0x0b, 0x66, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x88, 0x01, 0x01, 0x1a, 0x1e, 0x0a, 0x04,
+	0x54, 0x61, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x1a, 0x91, 0x01, 0x0a,
+	0x09, 0x55, 0x54, 0x4d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x49, 0x0a, 0x06, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x55, 0x54, 0x4d, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0x3d, 0x0a, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c,
+	0x65, 0x73, 0x12, 0x2b, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x1a,
+	0x3b, 0x0a, 0x0d, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73,
+	0x12, 0x2a, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x56, 0x61, 0x72,
+	0x69, 0x61, 0x6e, 0x74, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6e, 0x6f, 0x74, 0x65, 0x73,
+	0x42, 0x12, 0x0a, 0x10, 0x5f, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x70,
+	0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x70,
+	0x61, 0x74, 0x68, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x42,
+	0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
+	0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6e,
+	0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x22, 0x73, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x59, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x43, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42,
+	0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x22, 0x41, 0x0a, 0x21, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x22, 0x57,
+	0x0a, 0x22, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64,
+	0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x22, 0x2c, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15,
+	0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6a, 0x6f, 0x62, 0x49, 0x64, 0x22, 0xe7, 0x01, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x15,
+	0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x4e, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4a,
+	0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x50, 0x0a,
+	0x19, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
+	0x33, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x72, 0x69, 0x22, 0xdd, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50,
+	0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x36, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74,
+	0x53, 0x70, 0x6c, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x1a, 0x72, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x75, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6c,
+	0x69, 0x63, 0x6b, 0x73, 0x22, 0x24, 0x0a, 0x22, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xbc, 0x02, 0x0a, 0x23, 0x47,
+	0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x6c, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x52, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79,
+	0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47,
+	0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x1a, 0xa6, 0x01, 0x0a, 0x28, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a,
+	0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x12, 0x1d, 0x0a,
+	0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1b, 0x0a, 0x09,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x22, 0x42, 0x0a, 0x22, 0x52, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x22, 0xf4, 0x01,
+	0x0a, 0x23, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6c, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x52, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x1a, 0x5f, 0x0a, 0x28, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x49, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x69, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x18, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x53,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3c,
+	0x0a, 0x17, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x22, 0x3e, 0x0a, 0x19,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x22, 0x3f, 0x0a, 0x1a,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x22, 0x0d, 0x0a,
+	0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x36, 0x0a, 0x0c,
+	0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x0e,
+	0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x41, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x22, 0x11, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4e, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75,
+	0x72, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x75, 0x72, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75, 0x73,
+	0x65, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x32, 0xef, 0x0a, 0x0a, 0x10, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x65, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x12, 0x1b,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x47, 0x65, 0x74,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x12, 0x18, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a,
+	0x11, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x51, 0x52, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x1e, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5a, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x20, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x67,
 	0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
-	0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x47, 0x65,
-	0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x12, 0x18, 0x2e, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68,
-	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5a,
-	0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
-	0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x20, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74,
-	0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x13, 0x47, 0x65,
-	0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49,
-	0x44, 0x12, 0x21, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72,
-	0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53,
+	0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x70, 0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x26, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30,
+	0x01, 0x12, 0x5c, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x21, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73,
+	0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42,
+	0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4b, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x12, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x1a,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42,
+	0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x12, 0x27, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a,
+	0x0c, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x19, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4a, 0x6f,
+	0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74,
+	0x53, 0x70, 0x6c, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72,
+	0x0a, 0x1b, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x28, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x53,
 	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x1a, 0x44, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x53, 0x68, 0x6f,
-	0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x12, 0x27, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x53,
-	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x28, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x68, 0x6f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47,
+	0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x72, 0x0a, 0x1b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x68, 0x6f,
 	0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x50, 0x69, 0x6e,
-	0x67, 0x12, 0x11, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x69, 0x6e, 0x67,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53,
-	0x74, 0x61, 0x74, 0x73, 0x12, 0x15, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53,
-	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x07, 0x5a, 0x05, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x12, 0x28, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x49, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x73, 0x42, 0x79, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x49, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1c, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47,
+	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1f, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x53,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2d, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x11, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39,
+	0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x15, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x07, 0x5a, 0x05, 0x67, 0x72, 0x70,
+	0x63, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -939,49 +3707,125 @@ func file_grpc_shortener_proto_rawDescGZIP() []byte {
 	return file_grpc_shortener_proto_rawDescData
 }
 
-var file_grpc_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_grpc_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_grpc_shortener_proto_goTypes = []any{
-	(*CreateShortURLRequest)(nil),                                         // 0: grpc.CreateShortURLRequest
-	(*CreateShortURLResponse)(nil),                                        // 1: grpc.CreateShortURLResponse
-	(*GetShortURLRequest)(nil),                                            // 2: grpc.GetShortURLRequest
-	(*GetShortURLResponse)(nil),                                           // 3: grpc.GetShortURLResponse
-	(*CreateShortURLBatchRequest)(nil),                                    // 4: grpc.CreateShortURLBatchRequest
-	(*CreateShortURLBatchResponse)(nil),                                   // 5: grpc.CreateShortURLBatchResponse
-	(*GetShortURLsByUserIDRequest)(nil),                                   // 6: grpc.GetShortURLsByUserIDRequest
-	(*GetShortURLsByUserIDResponse)(nil),                                  // 7: grpc.GetShortURLsByUserIDResponse
-	(*DeleteShortURLsByShortURIsRequest)(nil),                             // 8: grpc.DeleteShortURLsByShortURIsRequest
-	(*DeleteShortURLsByShortURIsResponse)(nil),                            // 9: grpc.DeleteShortURLsByShortURIsResponse
-	(*PingRequest)(nil),                                                   // 10: grpc.PingRequest
-	(*PingResponse)(nil),                                                  // 11: grpc.PingResponse
-	(*GetStatsRequest)(nil),                                               // 12: grpc.GetStatsRequest
-	(*GetStatsResponse)(nil),                                              // 13: grpc.GetStatsResponse
-	(*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry)(nil),    // 14: grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry
-	(*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry)(nil),  // 15: grpc.CreateShortURLBatchResponse.CreateShortURLBatchResponseEntry
-	(*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry)(nil), // 16: grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry
+	(*TargetingRule)(nil),                                              // 0: grpc.TargetingRule
+	(*SplitVariant)(nil),                                               // 1: grpc.SplitVariant
+	(*CreateShortURLRequest)(nil),                                      // 2: grpc.CreateShortURLRequest
+	(*CreateShortURLResponse)(nil),                                     // 3: grpc.CreateShortURLResponse
+	(*GetShortURLRequest)(nil),                                         // 4: grpc.GetShortURLRequest
+	(*GetShortURLResponse)(nil),                                        // 5: grpc.GetShortURLResponse
+	(*GetShortURLQRCodeRequest)(nil),                                   // 6: grpc.GetShortURLQRCodeRequest
+	(*GetShortURLQRCodeResponse)(nil),                                  // 7: grpc.GetShortURLQRCodeResponse
+	(*CreateShortURLBatchRequest)(nil),                                 // 8: grpc.CreateShortURLBatchRequest
+	(*CreateShortURLBatchResponse)(nil),                                // 9: grpc.CreateShortURLBatchResponse
+	(*CreateShortURLBatchStreamRequest)(nil),                           // 10: grpc.CreateShortURLBatchStreamRequest
+	(*CreateShortURLBatchStreamResponse)(nil),                          // 11: grpc.CreateShortURLBatchStreamResponse
+	(*GetShortURLsByUserIDRequest)(nil),                                // 12: grpc.GetShortURLsByUserIDRequest
+	(*GetShortURLsByUserIDResponse)(nil),                               // 13: grpc.GetShortURLsByUserIDResponse
+	(*UpdateShortURLRequest)(nil),                                      // 14: grpc.UpdateShortURLRequest
+	(*UpdateShortURLResponse)(nil),                                     // 15: grpc.UpdateShortURLResponse
+	(*DeleteShortURLsByShortURIsRequest)(nil),                          // 16: grpc.DeleteShortURLsByShortURIsRequest
+	(*DeleteShortURLsByShortURIsResponse)(nil),                         // 17: grpc.DeleteShortURLsByShortURIsResponse
+	(*GetDeleteJobRequest)(nil),                                        // 18: grpc.GetDeleteJobRequest
+	(*GetDeleteJobResponse)(nil),                                       // 19: grpc.GetDeleteJobResponse
+	(*GetSplitStatsRequest)(nil),                                       // 20: grpc.GetSplitStatsRequest
+	(*GetSplitStatsResponse)(nil),                                      // 21: grpc.GetSplitStatsResponse
+	(*GetDeletedShortURLsByUserIDRequest)(nil),                         // 22: grpc.GetDeletedShortURLsByUserIDRequest
+	(*GetDeletedShortURLsByUserIDResponse)(nil),                        // 23: grpc.GetDeletedShortURLsByUserIDResponse
+	(*RestoreShortURLsByShortURIsRequest)(nil),                         // 24: grpc.RestoreShortURLsByShortURIsRequest
+	(*RestoreShortURLsByShortURIsResponse)(nil),                        // 25: grpc.RestoreShortURLsByShortURIsResponse
+	(*GetUserSettingsRequest)(nil),                                     // 26: grpc.GetUserSettingsRequest
+	(*GetUserSettingsResponse)(nil),                                    // 27: grpc.GetUserSettingsResponse
+	(*UpdateUserSettingsRequest)(nil),                                  // 28: grpc.UpdateUserSettingsRequest
+	(*UpdateUserSettingsResponse)(nil),                                 // 29: grpc.UpdateUserSettingsResponse
+	(*PingRequest)(nil),                                                // 30: grpc.PingRequest
+	(*PingResponse)(nil),                                               // 31: grpc.PingResponse
+	(*GetStatsRequest)(nil),                                            // 32: grpc.GetStatsRequest
+	(*GetStatsResponse)(nil),                                           // 33: grpc.GetStatsResponse
+	nil,                                                                // 34: grpc.CreateShortURLRequest.UtmParamsEntry
+	(*CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry)(nil), // 35: grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry
+	nil, // 36: grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry.UtmParamsEntry
+	(*CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry)(nil),  // 37: grpc.CreateShortURLBatchResponse.CreateShortURLBatchResponseEntry
+	(*CreateShortURLBatchStreamResponse_Entry)(nil),                       // 38: grpc.CreateShortURLBatchStreamResponse.Entry
+	(*CreateShortURLBatchStreamResponse_Summary)(nil),                     // 39: grpc.CreateShortURLBatchStreamResponse.Summary
+	(*GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry)(nil), // 40: grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry
+	nil,                                     // 41: grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry.UtmParamsEntry
+	(*UpdateShortURLRequest_Tags)(nil),      // 42: grpc.UpdateShortURLRequest.Tags
+	(*UpdateShortURLRequest_UTMParams)(nil), // 43: grpc.UpdateShortURLRequest.UTMParams
+	(*UpdateShortURLRequest_TargetingRules)(nil), // 44: grpc.UpdateShortURLRequest.TargetingRules
+	(*UpdateShortURLRequest_SplitVariants)(nil),  // 45: grpc.UpdateShortURLRequest.SplitVariants
+	nil, // 46: grpc.UpdateShortURLRequest.UTMParams.ValuesEntry
+	(*GetDeleteJobResponse_GetDeleteJobResponseEntry)(nil),                               // 47: grpc.GetDeleteJobResponse.GetDeleteJobResponseEntry
+	(*GetSplitStatsResponse_GetSplitStatsResponseEntry)(nil),                             // 48: grpc.GetSplitStatsResponse.GetSplitStatsResponseEntry
+	(*GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry)(nil), // 49: grpc.GetDeletedShortURLsByUserIDResponse.GetDeletedShortURLsByUserIDResponseEntry
+	(*RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry)(nil), // 50: grpc.RestoreShortURLsByShortURIsResponse.RestoreShortURLsByShortURIsResponseEntry
 }
 var file_grpc_shortener_proto_depIdxs = []int32{
-	14, // 0: grpc.CreateShortURLBatchRequest.entries:type_name -> grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry
-	15, // 1: grpc.CreateShortURLBatchResponse.entries:type_name -> grpc.CreateShortURLBatchResponse.CreateShortURLBatchResponseEntry
-	16, // 2: grpc.GetShortURLsByUserIDResponse.entries:type_name -> grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry
-	0,  // 3: grpc.ShortenerService.CreateShortURL:input_type -> grpc.CreateShortURLRequest
-	2,  // 4: grpc.ShortenerService.GetShortURL:input_type -> grpc.GetShortURLRequest
-	4,  // 5: grpc.ShortenerService.CreateShortURLBatch:input_type -> grpc.CreateShortURLBatchRequest
-	6,  // 6: grpc.ShortenerService.GetShortURLByUserID:input_type -> grpc.GetShortURLsByUserIDRequest
-	8,  // 7: grpc.ShortenerService.DeleteShortURLsByShortURIs:input_type -> grpc.DeleteShortURLsByShortURIsRequest
-	10, // 8: grpc.ShortenerService.Ping:input_type -> grpc.PingRequest
-	12, // 9: grpc.ShortenerService.GetStats:input_type -> grpc.GetStatsRequest
-	1,  // 10: grpc.ShortenerService.CreateShortURL:output_type -> grpc.CreateShortURLResponse
-	3,  // 11: grpc.ShortenerService.GetShortURL:output_type -> grpc.GetShortURLResponse
-	5,  // 12: grpc.ShortenerService.CreateShortURLBatch:output_type -> grpc.CreateShortURLBatchResponse
-	7,  // 13: grpc.ShortenerService.GetShortURLByUserID:output_type -> grpc.GetShortURLsByUserIDResponse
-	9,  // 14: grpc.ShortenerService.DeleteShortURLsByShortURIs:output_type -> grpc.DeleteShortURLsByShortURIsResponse
-	11, // 15: grpc.ShortenerService.Ping:output_type -> grpc.PingResponse
-	13, // 16: grpc.ShortenerService.GetStats:output_type -> grpc.GetStatsResponse
-	10, // [10:17] is the sub-list for method output_type
-	3,  // [3:10] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	34, // 0: grpc.CreateShortURLRequest.utm_params:type_name -> grpc.CreateShortURLRequest.UtmParamsEntry
+	0,  // 1: grpc.CreateShortURLRequest.targeting_rules:type_name -> grpc.TargetingRule
+	1,  // 2: grpc.CreateShortURLRequest.split_variants:type_name -> grpc.SplitVariant
+	35, // 3: grpc.CreateShortURLBatchRequest.entries:type_name -> grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry
+	37, // 4: grpc.CreateShortURLBatchResponse.entries:type_name -> grpc.CreateShortURLBatchResponse.CreateShortURLBatchResponseEntry
+	35, // 5: grpc.CreateShortURLBatchStreamRequest.entries:type_name -> grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry
+	38, // 6: grpc.CreateShortURLBatchStreamResponse.entry:type_name -> grpc.CreateShortURLBatchStreamResponse.Entry
+	39, // 7: grpc.CreateShortURLBatchStreamResponse.summary:type_name -> grpc.CreateShortURLBatchStreamResponse.Summary
+	40, // 8: grpc.GetShortURLsByUserIDResponse.entries:type_name -> grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry
+	42, // 9: grpc.UpdateShortURLRequest.tags:type_name -> grpc.UpdateShortURLRequest.Tags
+	43, // 10: grpc.UpdateShortURLRequest.utm_params:type_name -> grpc.UpdateShortURLRequest.UTMParams
+	44, // 11: grpc.UpdateShortURLRequest.targeting_rules:type_name -> grpc.UpdateShortURLRequest.TargetingRules
+	45, // 12: grpc.UpdateShortURLRequest.split_variants:type_name -> grpc.UpdateShortURLRequest.SplitVariants
+	40, // 13: grpc.UpdateShortURLResponse.entry:type_name -> grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry
+	47, // 14: grpc.GetDeleteJobResponse.entries:type_name -> grpc.GetDeleteJobResponse.GetDeleteJobResponseEntry
+	48, // 15: grpc.GetSplitStatsResponse.entries:type_name -> grpc.GetSplitStatsResponse.GetSplitStatsResponseEntry
+	49, // 16: grpc.GetDeletedShortURLsByUserIDResponse.entries:type_name -> grpc.GetDeletedShortURLsByUserIDResponse.GetDeletedShortURLsByUserIDResponseEntry
+	50, // 17: grpc.RestoreShortURLsByShortURIsResponse.entries:type_name -> grpc.RestoreShortURLsByShortURIsResponse.RestoreShortURLsByShortURIsResponseEntry
+	36, // 18: grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry.utm_params:type_name -> grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry.UtmParamsEntry
+	0,  // 19: grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry.targeting_rules:type_name -> grpc.TargetingRule
+	1,  // 20: grpc.CreateShortURLBatchRequest.CreateShortURLBatchRequestEntry.split_variants:type_name -> grpc.SplitVariant
+	41, // 21: grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry.utm_params:type_name -> grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry.UtmParamsEntry
+	0,  // 22: grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry.targeting_rules:type_name -> grpc.TargetingRule
+	1,  // 23: grpc.GetShortURLsByUserIDResponse.GetShortURLByUserIDResponseEntry.split_variants:type_name -> grpc.SplitVariant
+	46, // 24: grpc.UpdateShortURLRequest.UTMParams.values:type_name -> grpc.UpdateShortURLRequest.UTMParams.ValuesEntry
+	0,  // 25: grpc.UpdateShortURLRequest.TargetingRules.values:type_name -> grpc.TargetingRule
+	1,  // 26: grpc.UpdateShortURLRequest.SplitVariants.values:type_name -> grpc.SplitVariant
+	2,  // 27: grpc.ShortenerService.CreateShortURL:input_type -> grpc.CreateShortURLRequest
+	4,  // 28: grpc.ShortenerService.GetShortURL:input_type -> grpc.GetShortURLRequest
+	6,  // 29: grpc.ShortenerService.GetShortURLQRCode:input_type -> grpc.GetShortURLQRCodeRequest
+	8,  // 30: grpc.ShortenerService.CreateShortURLBatch:input_type -> grpc.CreateShortURLBatchRequest
+	10, // 31: grpc.ShortenerService.CreateShortURLBatchStream:input_type -> grpc.CreateShortURLBatchStreamRequest
+	12, // 32: grpc.ShortenerService.GetShortURLByUserID:input_type -> grpc.GetShortURLsByUserIDRequest
+	14, // 33: grpc.ShortenerService.UpdateShortURL:input_type -> grpc.UpdateShortURLRequest
+	16, // 34: grpc.ShortenerService.DeleteShortURLsByShortURIs:input_type -> grpc.DeleteShortURLsByShortURIsRequest
+	18, // 35: grpc.ShortenerService.GetDeleteJob:input_type -> grpc.GetDeleteJobRequest
+	20, // 36: grpc.ShortenerService.GetSplitStats:input_type -> grpc.GetSplitStatsRequest
+	22, // 37: grpc.ShortenerService.GetDeletedShortURLsByUserID:input_type -> grpc.GetDeletedShortURLsByUserIDRequest
+	24, // 38: grpc.ShortenerService.RestoreShortURLsByShortURIs:input_type -> grpc.RestoreShortURLsByShortURIsRequest
+	26, // 39: grpc.ShortenerService.GetUserSettings:input_type -> grpc.GetUserSettingsRequest
+	28, // 40: grpc.ShortenerService.UpdateUserSettings:input_type -> grpc.UpdateUserSettingsRequest
+	30, // 41: grpc.ShortenerService.Ping:input_type -> grpc.PingRequest
+	32, // 42: grpc.ShortenerService.GetStats:input_type -> grpc.GetStatsRequest
+	3,  // 43: grpc.ShortenerService.CreateShortURL:output_type -> grpc.CreateShortURLResponse
+	5,  // 44: grpc.ShortenerService.GetShortURL:output_type -> grpc.GetShortURLResponse
+	7,  // 45: grpc.ShortenerService.GetShortURLQRCode:output_type -> grpc.GetShortURLQRCodeResponse
+	9,  // 46: grpc.ShortenerService.CreateShortURLBatch:output_type -> grpc.CreateShortURLBatchResponse
+	11, // 47: grpc.ShortenerService.CreateShortURLBatchStream:output_type -> grpc.CreateShortURLBatchStreamResponse
+	13, // 48: grpc.ShortenerService.GetShortURLByUserID:output_type -> grpc.GetShortURLsByUserIDResponse
+	15, // 49: grpc.ShortenerService.UpdateShortURL:output_type -> grpc.UpdateShortURLResponse
+	17, // 50: grpc.ShortenerService.DeleteShortURLsByShortURIs:output_type -> grpc.DeleteShortURLsByShortURIsResponse
+	19, // 51: grpc.ShortenerService.GetDeleteJob:output_type -> grpc.GetDeleteJobResponse
+	21, // 52: grpc.ShortenerService.GetSplitStats:output_type -> grpc.GetSplitStatsResponse
+	23, // 53: grpc.ShortenerService.GetDeletedShortURLsByUserID:output_type -> grpc.GetDeletedShortURLsByUserIDResponse
+	25, // 54: grpc.ShortenerService.RestoreShortURLsByShortURIs:output_type -> grpc.RestoreShortURLsByShortURIsResponse
+	27, // 55: grpc.ShortenerService.GetUserSettings:output_type -> grpc.GetUserSettingsResponse
+	29, // 56: grpc.ShortenerService.UpdateUserSettings:output_type -> grpc.UpdateUserSettingsResponse
+	31, // 57: grpc.ShortenerService.Ping:output_type -> grpc.PingResponse
+	33, // 58: grpc.ShortenerService.GetStats:output_type -> grpc.GetStatsResponse
+	43, // [43:59] is the sub-list for method output_type
+	27, // [27:43] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_grpc_shortener_proto_init() }
@@ -989,13 +3833,19 @@ func file_grpc_shortener_proto_init() {
 	if File_grpc_shortener_proto != nil {
 		return
 	}
+	file_grpc_shortener_proto_msgTypes[6].OneofWrappers = []any{}
+	file_grpc_shortener_proto_msgTypes[11].OneofWrappers = []any{
+		(*CreateShortURLBatchStreamResponse_Entry_)(nil),
+		(*CreateShortURLBatchStreamResponse_Summary_)(nil),
+	}
+	file_grpc_shortener_proto_msgTypes[14].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_grpc_shortener_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   51,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
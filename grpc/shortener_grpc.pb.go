@@ -19,13 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ShortenerService_CreateShortURL_FullMethodName             = "/grpc.ShortenerService/CreateShortURL"
-	ShortenerService_GetShortURL_FullMethodName                = "/grpc.ShortenerService/GetShortURL"
-	ShortenerService_CreateShortURLBatch_FullMethodName        = "/grpc.ShortenerService/CreateShortURLBatch"
-	ShortenerService_GetShortURLByUserID_FullMethodName        = "/grpc.ShortenerService/GetShortURLByUserID"
-	ShortenerService_DeleteShortURLsByShortURIs_FullMethodName = "/grpc.ShortenerService/DeleteShortURLsByShortURIs"
-	ShortenerService_Ping_FullMethodName                       = "/grpc.ShortenerService/Ping"
-	ShortenerService_GetStats_FullMethodName                   = "/grpc.ShortenerService/GetStats"
+	ShortenerService_CreateShortURL_FullMethodName              = "/grpc.ShortenerService/CreateShortURL"
+	ShortenerService_GetShortURL_FullMethodName                 = "/grpc.ShortenerService/GetShortURL"
+	ShortenerService_GetShortURLQRCode_FullMethodName           = "/grpc.ShortenerService/GetShortURLQRCode"
+	ShortenerService_CreateShortURLBatch_FullMethodName         = "/grpc.ShortenerService/CreateShortURLBatch"
+	ShortenerService_CreateShortURLBatchStream_FullMethodName   = "/grpc.ShortenerService/CreateShortURLBatchStream"
+	ShortenerService_GetShortURLByUserID_FullMethodName         = "/grpc.ShortenerService/GetShortURLByUserID"
+	ShortenerService_UpdateShortURL_FullMethodName              = "/grpc.ShortenerService/UpdateShortURL"
+	ShortenerService_DeleteShortURLsByShortURIs_FullMethodName  = "/grpc.ShortenerService/DeleteShortURLsByShortURIs"
+	ShortenerService_GetDeleteJob_FullMethodName                = "/grpc.ShortenerService/GetDeleteJob"
+	ShortenerService_GetSplitStats_FullMethodName               = "/grpc.ShortenerService/GetSplitStats"
+	ShortenerService_GetDeletedShortURLsByUserID_FullMethodName = "/grpc.ShortenerService/GetDeletedShortURLsByUserID"
+	ShortenerService_RestoreShortURLsByShortURIs_FullMethodName = "/grpc.ShortenerService/RestoreShortURLsByShortURIs"
+	ShortenerService_GetUserSettings_FullMethodName             = "/grpc.ShortenerService/GetUserSettings"
+	ShortenerService_UpdateUserSettings_FullMethodName          = "/grpc.ShortenerService/UpdateUserSettings"
+	ShortenerService_Ping_FullMethodName                        = "/grpc.ShortenerService/Ping"
+	ShortenerService_GetStats_FullMethodName                    = "/grpc.ShortenerService/GetStats"
 )
 
 // ShortenerServiceClient is the client API for ShortenerService service.
@@ -34,9 +43,18 @@ const (
 type ShortenerServiceClient interface {
 	CreateShortURL(ctx context.Context, in *CreateShortURLRequest, opts ...grpc.CallOption) (*CreateShortURLResponse, error)
 	GetShortURL(ctx context.Context, in *GetShortURLRequest, opts ...grpc.CallOption) (*GetShortURLResponse, error)
+	GetShortURLQRCode(ctx context.Context, in *GetShortURLQRCodeRequest, opts ...grpc.CallOption) (*GetShortURLQRCodeResponse, error)
 	CreateShortURLBatch(ctx context.Context, in *CreateShortURLBatchRequest, opts ...grpc.CallOption) (*CreateShortURLBatchResponse, error)
+	CreateShortURLBatchStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreateShortURLBatchStreamRequest, CreateShortURLBatchStreamResponse], error)
 	GetShortURLByUserID(ctx context.Context, in *GetShortURLsByUserIDRequest, opts ...grpc.CallOption) (*GetShortURLsByUserIDResponse, error)
+	UpdateShortURL(ctx context.Context, in *UpdateShortURLRequest, opts ...grpc.CallOption) (*UpdateShortURLResponse, error)
 	DeleteShortURLsByShortURIs(ctx context.Context, in *DeleteShortURLsByShortURIsRequest, opts ...grpc.CallOption) (*DeleteShortURLsByShortURIsResponse, error)
+	GetDeleteJob(ctx context.Context, in *GetDeleteJobRequest, opts ...grpc.CallOption) (*GetDeleteJobResponse, error)
+	GetSplitStats(ctx context.Context, in *GetSplitStatsRequest, opts ...grpc.CallOption) (*GetSplitStatsResponse, error)
+	GetDeletedShortURLsByUserID(ctx context.Context, in *GetDeletedShortURLsByUserIDRequest, opts ...grpc.CallOption) (*GetDeletedShortURLsByUserIDResponse, error)
+	RestoreShortURLsByShortURIs(ctx context.Context, in *RestoreShortURLsByShortURIsRequest, opts ...grpc.CallOption) (*RestoreShortURLsByShortURIsResponse, error)
+	GetUserSettings(ctx context.Context, in *GetUserSettingsRequest, opts ...grpc.CallOption) (*GetUserSettingsResponse, error)
+	UpdateUserSettings(ctx context.Context, in *UpdateUserSettingsRequest, opts ...grpc.CallOption) (*UpdateUserSettingsResponse, error)
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 }
@@ -69,6 +87,16 @@ func (c *shortenerServiceClient) GetShortURL(ctx context.Context, in *GetShortUR
 	return out, nil
 }
 
+func (c *shortenerServiceClient) GetShortURLQRCode(ctx context.Context, in *GetShortURLQRCodeRequest, opts ...grpc.CallOption) (*GetShortURLQRCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetShortURLQRCodeResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetShortURLQRCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *shortenerServiceClient) CreateShortURLBatch(ctx context.Context, in *CreateShortURLBatchRequest, opts ...grpc.CallOption) (*CreateShortURLBatchResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateShortURLBatchResponse)
@@ -79,6 +107,19 @@ func (c *shortenerServiceClient) CreateShortURLBatch(ctx context.Context, in *Cr
 	return out, nil
 }
 
+func (c *shortenerServiceClient) CreateShortURLBatchStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreateShortURLBatchStreamRequest, CreateShortURLBatchStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ShortenerService_ServiceDesc.Streams[0], ShortenerService_CreateShortURLBatchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreateShortURLBatchStreamRequest, CreateShortURLBatchStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ShortenerService_CreateShortURLBatchStreamClient = grpc.BidiStreamingClient[CreateShortURLBatchStreamRequest, CreateShortURLBatchStreamResponse]
+
 func (c *shortenerServiceClient) GetShortURLByUserID(ctx context.Context, in *GetShortURLsByUserIDRequest, opts ...grpc.CallOption) (*GetShortURLsByUserIDResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetShortURLsByUserIDResponse)
@@ -89,6 +130,16 @@ func (c *shortenerServiceClient) GetShortURLByUserID(ctx context.Context, in *Ge
 	return out, nil
 }
 
+func (c *shortenerServiceClient) UpdateShortURL(ctx context.Context, in *UpdateShortURLRequest, opts ...grpc.CallOption) (*UpdateShortURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateShortURLResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_UpdateShortURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *shortenerServiceClient) DeleteShortURLsByShortURIs(ctx context.Context, in *DeleteShortURLsByShortURIsRequest, opts ...grpc.CallOption) (*DeleteShortURLsByShortURIsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteShortURLsByShortURIsResponse)
@@ -99,6 +150,66 @@ func (c *shortenerServiceClient) DeleteShortURLsByShortURIs(ctx context.Context,
 	return out, nil
 }
 
+func (c *shortenerServiceClient) GetDeleteJob(ctx context.Context, in *GetDeleteJobRequest, opts ...grpc.CallOption) (*GetDeleteJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeleteJobResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetDeleteJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetSplitStats(ctx context.Context, in *GetSplitStatsRequest, opts ...grpc.CallOption) (*GetSplitStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSplitStatsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetSplitStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetDeletedShortURLsByUserID(ctx context.Context, in *GetDeletedShortURLsByUserIDRequest, opts ...grpc.CallOption) (*GetDeletedShortURLsByUserIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeletedShortURLsByUserIDResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetDeletedShortURLsByUserID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) RestoreShortURLsByShortURIs(ctx context.Context, in *RestoreShortURLsByShortURIsRequest, opts ...grpc.CallOption) (*RestoreShortURLsByShortURIsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreShortURLsByShortURIsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_RestoreShortURLsByShortURIs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetUserSettings(ctx context.Context, in *GetUserSettingsRequest, opts ...grpc.CallOption) (*GetUserSettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserSettingsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetUserSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) UpdateUserSettings(ctx context.Context, in *UpdateUserSettingsRequest, opts ...grpc.CallOption) (*UpdateUserSettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateUserSettingsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_UpdateUserSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *shortenerServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(PingResponse)
@@ -125,9 +236,18 @@ func (c *shortenerServiceClient) GetStats(ctx context.Context, in *GetStatsReque
 type ShortenerServiceServer interface {
 	CreateShortURL(context.Context, *CreateShortURLRequest) (*CreateShortURLResponse, error)
 	GetShortURL(context.Context, *GetShortURLRequest) (*GetShortURLResponse, error)
+	GetShortURLQRCode(context.Context, *GetShortURLQRCodeRequest) (*GetShortURLQRCodeResponse, error)
 	CreateShortURLBatch(context.Context, *CreateShortURLBatchRequest) (*CreateShortURLBatchResponse, error)
+	CreateShortURLBatchStream(grpc.BidiStreamingServer[CreateShortURLBatchStreamRequest, CreateShortURLBatchStreamResponse]) error
 	GetShortURLByUserID(context.Context, *GetShortURLsByUserIDRequest) (*GetShortURLsByUserIDResponse, error)
+	UpdateShortURL(context.Context, *UpdateShortURLRequest) (*UpdateShortURLResponse, error)
 	DeleteShortURLsByShortURIs(context.Context, *DeleteShortURLsByShortURIsRequest) (*DeleteShortURLsByShortURIsResponse, error)
+	GetDeleteJob(context.Context, *GetDeleteJobRequest) (*GetDeleteJobResponse, error)
+	GetSplitStats(context.Context, *GetSplitStatsRequest) (*GetSplitStatsResponse, error)
+	GetDeletedShortURLsByUserID(context.Context, *GetDeletedShortURLsByUserIDRequest) (*GetDeletedShortURLsByUserIDResponse, error)
+	RestoreShortURLsByShortURIs(context.Context, *RestoreShortURLsByShortURIsRequest) (*RestoreShortURLsByShortURIsResponse, error)
+	GetUserSettings(context.Context, *GetUserSettingsRequest) (*GetUserSettingsResponse, error)
+	UpdateUserSettings(context.Context, *UpdateUserSettingsRequest) (*UpdateUserSettingsResponse, error)
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	mustEmbedUnimplementedShortenerServiceServer()
@@ -146,15 +266,42 @@ func (UnimplementedShortenerServiceServer) CreateShortURL(context.Context, *Crea
 func (UnimplementedShortenerServiceServer) GetShortURL(context.Context, *GetShortURLRequest) (*GetShortURLResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetShortURL not implemented")
 }
+func (UnimplementedShortenerServiceServer) GetShortURLQRCode(context.Context, *GetShortURLQRCodeRequest) (*GetShortURLQRCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShortURLQRCode not implemented")
+}
 func (UnimplementedShortenerServiceServer) CreateShortURLBatch(context.Context, *CreateShortURLBatchRequest) (*CreateShortURLBatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateShortURLBatch not implemented")
 }
+func (UnimplementedShortenerServiceServer) CreateShortURLBatchStream(grpc.BidiStreamingServer[CreateShortURLBatchStreamRequest, CreateShortURLBatchStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method CreateShortURLBatchStream not implemented")
+}
 func (UnimplementedShortenerServiceServer) GetShortURLByUserID(context.Context, *GetShortURLsByUserIDRequest) (*GetShortURLsByUserIDResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetShortURLByUserID not implemented")
 }
+func (UnimplementedShortenerServiceServer) UpdateShortURL(context.Context, *UpdateShortURLRequest) (*UpdateShortURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateShortURL not implemented")
+}
 func (UnimplementedShortenerServiceServer) DeleteShortURLsByShortURIs(context.Context, *DeleteShortURLsByShortURIsRequest) (*DeleteShortURLsByShortURIsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteShortURLsByShortURIs not implemented")
 }
+func (UnimplementedShortenerServiceServer) GetDeleteJob(context.Context, *GetDeleteJobRequest) (*GetDeleteJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeleteJob not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetSplitStats(context.Context, *GetSplitStatsRequest) (*GetSplitStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSplitStats not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetDeletedShortURLsByUserID(context.Context, *GetDeletedShortURLsByUserIDRequest) (*GetDeletedShortURLsByUserIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeletedShortURLsByUserID not implemented")
+}
+func (UnimplementedShortenerServiceServer) RestoreShortURLsByShortURIs(context.Context, *RestoreShortURLsByShortURIsRequest) (*RestoreShortURLsByShortURIsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreShortURLsByShortURIs not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetUserSettings(context.Context, *GetUserSettingsRequest) (*GetUserSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserSettings not implemented")
+}
+func (UnimplementedShortenerServiceServer) UpdateUserSettings(context.Context, *UpdateUserSettingsRequest) (*UpdateUserSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserSettings not implemented")
+}
 func (UnimplementedShortenerServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
@@ -218,6 +365,24 @@ func _ShortenerService_GetShortURL_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_GetShortURLQRCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShortURLQRCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetShortURLQRCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetShortURLQRCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetShortURLQRCode(ctx, req.(*GetShortURLQRCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ShortenerService_CreateShortURLBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateShortURLBatchRequest)
 	if err := dec(in); err != nil {
@@ -236,6 +401,13 @@ func _ShortenerService_CreateShortURLBatch_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_CreateShortURLBatchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ShortenerServiceServer).CreateShortURLBatchStream(&grpc.GenericServerStream[CreateShortURLBatchStreamRequest, CreateShortURLBatchStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ShortenerService_CreateShortURLBatchStreamServer = grpc.BidiStreamingServer[CreateShortURLBatchStreamRequest, CreateShortURLBatchStreamResponse]
+
 func _ShortenerService_GetShortURLByUserID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetShortURLsByUserIDRequest)
 	if err := dec(in); err != nil {
@@ -254,6 +426,24 @@ func _ShortenerService_GetShortURLByUserID_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_UpdateShortURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateShortURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).UpdateShortURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_UpdateShortURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).UpdateShortURL(ctx, req.(*UpdateShortURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ShortenerService_DeleteShortURLsByShortURIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteShortURLsByShortURIsRequest)
 	if err := dec(in); err != nil {
@@ -272,6 +462,114 @@ func _ShortenerService_DeleteShortURLsByShortURIs_Handler(srv interface{}, ctx c
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_GetDeleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeleteJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetDeleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetDeleteJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetDeleteJob(ctx, req.(*GetDeleteJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetSplitStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSplitStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetSplitStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetSplitStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetSplitStats(ctx, req.(*GetSplitStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetDeletedShortURLsByUserID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeletedShortURLsByUserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetDeletedShortURLsByUserID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetDeletedShortURLsByUserID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetDeletedShortURLsByUserID(ctx, req.(*GetDeletedShortURLsByUserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_RestoreShortURLsByShortURIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreShortURLsByShortURIsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).RestoreShortURLsByShortURIs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_RestoreShortURLsByShortURIs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).RestoreShortURLsByShortURIs(ctx, req.(*RestoreShortURLsByShortURIsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetUserSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetUserSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetUserSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetUserSettings(ctx, req.(*GetUserSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_UpdateUserSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).UpdateUserSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_UpdateUserSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).UpdateUserSettings(ctx, req.(*UpdateUserSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ShortenerService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PingRequest)
 	if err := dec(in); err != nil {
@@ -323,6 +621,10 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetShortURL",
 			Handler:    _ShortenerService_GetShortURL_Handler,
 		},
+		{
+			MethodName: "GetShortURLQRCode",
+			Handler:    _ShortenerService_GetShortURLQRCode_Handler,
+		},
 		{
 			MethodName: "CreateShortURLBatch",
 			Handler:    _ShortenerService_CreateShortURLBatch_Handler,
@@ -331,10 +633,38 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetShortURLByUserID",
 			Handler:    _ShortenerService_GetShortURLByUserID_Handler,
 		},
+		{
+			MethodName: "UpdateShortURL",
+			Handler:    _ShortenerService_UpdateShortURL_Handler,
+		},
 		{
 			MethodName: "DeleteShortURLsByShortURIs",
 			Handler:    _ShortenerService_DeleteShortURLsByShortURIs_Handler,
 		},
+		{
+			MethodName: "GetDeleteJob",
+			Handler:    _ShortenerService_GetDeleteJob_Handler,
+		},
+		{
+			MethodName: "GetSplitStats",
+			Handler:    _ShortenerService_GetSplitStats_Handler,
+		},
+		{
+			MethodName: "GetDeletedShortURLsByUserID",
+			Handler:    _ShortenerService_GetDeletedShortURLsByUserID_Handler,
+		},
+		{
+			MethodName: "RestoreShortURLsByShortURIs",
+			Handler:    _ShortenerService_RestoreShortURLsByShortURIs_Handler,
+		},
+		{
+			MethodName: "GetUserSettings",
+			Handler:    _ShortenerService_GetUserSettings_Handler,
+		},
+		{
+			MethodName: "UpdateUserSettings",
+			Handler:    _ShortenerService_UpdateUserSettings_Handler,
+		},
 		{
 			MethodName: "Ping",
 			Handler:    _ShortenerService_Ping_Handler,
@@ -344,6 +674,13 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ShortenerService_GetStats_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CreateShortURLBatchStream",
+			Handler:       _ShortenerService_CreateShortURLBatchStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "grpc/shortener.proto",
 }
@@ -77,7 +77,32 @@ func (a *URLShortenerApp) RegisterHTTPHandlers() {
 				middleware.GzipMiddleware(a.appController.CreateShortURLHandler))))
 	a.router.Get(
 		"/{id}",
-		middleware.LogRequestMiddleware(middleware.GzipMiddleware(a.appController.GetURLHandler)))
+		middleware.LogRequestMiddleware(
+			middleware.ClientAddrMiddleware(
+				a.trustedSubnet,
+				middleware.GzipMiddleware(a.appController.GetURLHandler))))
+	// путь /{id}/qr занят изображением QR-кода и не переносится в полную ссылку
+	a.router.Get(
+		"/{id}/*",
+		middleware.LogRequestMiddleware(
+			middleware.ClientAddrMiddleware(
+				a.trustedSubnet,
+				middleware.GzipMiddleware(a.appController.GetURLHandler))))
+	a.router.Post(
+		"/{id}",
+		middleware.LogRequestMiddleware(
+			middleware.ClientAddrMiddleware(
+				a.trustedSubnet,
+				middleware.GzipMiddleware(a.appController.UnlockURLHandler))))
+	a.router.Post(
+		"/{id}/*",
+		middleware.LogRequestMiddleware(
+			middleware.ClientAddrMiddleware(
+				a.trustedSubnet,
+				middleware.GzipMiddleware(a.appController.UnlockURLHandler))))
+	a.router.Get(
+		"/{id}/qr",
+		middleware.LogRequestMiddleware(middleware.GzipMiddleware(a.appController.GetQRCodeHandler)))
 	a.router.Post(
 		"/api/shorten",
 		middleware.LogRequestMiddleware(
@@ -90,6 +115,12 @@ func (a *URLShortenerApp) RegisterHTTPHandlers() {
 			middleware.UserIDCookieMiddleware(
 				a.salt,
 				middleware.GzipMiddleware(a.apiController.CreateShortURLBatchHandler))))
+	a.router.Post(
+		"/api/user/urls/import",
+		middleware.LogRequestMiddleware(
+			middleware.UserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.ImportShortURLsHandler))))
 	a.router.Get(
 		"/api/user/urls",
 		middleware.LogRequestMiddleware(
@@ -102,6 +133,54 @@ func (a *URLShortenerApp) RegisterHTTPHandlers() {
 			middleware.AuthByUserIDCookieMiddleware(
 				a.salt,
 				middleware.GzipMiddleware(a.apiController.DeleteShortURLs))))
+	a.router.Get(
+		"/api/user/urls/trash",
+		middleware.LogRequestMiddleware(
+			middleware.AuthByUserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.GetDeletedShortURLsByUserID))))
+	a.router.Post(
+		"/api/user/urls/restore",
+		middleware.LogRequestMiddleware(
+			middleware.AuthByUserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.RestoreShortURLs))))
+	a.router.Patch(
+		"/api/user/urls/{id}",
+		middleware.LogRequestMiddleware(
+			middleware.AuthByUserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.UpdateShortURL))))
+	a.router.Get(
+		"/api/user/urls/{id}/split",
+		middleware.LogRequestMiddleware(
+			middleware.AuthByUserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.GetSplitStats))))
+	a.router.Get(
+		"/api/user/urls/{id}/countries",
+		middleware.LogRequestMiddleware(
+			middleware.AuthByUserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.GetCountryStats))))
+	a.router.Get(
+		"/api/user/jobs/{id}",
+		middleware.LogRequestMiddleware(
+			middleware.AuthByUserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.GetDeleteJob))))
+	a.router.Get(
+		"/api/user/settings",
+		middleware.LogRequestMiddleware(
+			middleware.AuthByUserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.GetUserSettings))))
+	a.router.Put(
+		"/api/user/settings",
+		middleware.LogRequestMiddleware(
+			middleware.AuthByUserIDCookieMiddleware(
+				a.salt,
+				middleware.GzipMiddleware(a.apiController.UpdateUserSettings))))
 	a.router.Get(
 		"/ping",
 		a.healthController.Ping)
@@ -110,6 +189,21 @@ func (a *URLShortenerApp) RegisterHTTPHandlers() {
 		middleware.CheckSubnetMiddleware(
 			a.trustedSubnet,
 			a.internalController.GetStats))
+	a.router.Get(
+		"/api/internal/db/stats",
+		middleware.CheckSubnetMiddleware(
+			a.trustedSubnet,
+			a.healthController.GetDBStats))
+	a.router.Get(
+		"/api/internal/export",
+		middleware.CheckSubnetMiddleware(
+			a.trustedSubnet,
+			a.internalController.Export))
+	a.router.Post(
+		"/api/internal/import",
+		middleware.CheckSubnetMiddleware(
+			a.trustedSubnet,
+			a.internalController.Import))
 }
 
 // RunHTTPServer запускает http-сервер с приложением
@@ -166,21 +260,47 @@ func (a *URLShortenerApp) RunGRPCServer(gracefulShutdownCh chan struct{}) {
 				"GetStats",
 			},
 		),
+		interceptor.ClientAddrInterceptor(
+			a.trustedSubnet,
+			[]string{
+				"GetShortURL",
+			},
+		),
 		interceptor.UserIDInterceptor(
 			a.salt,
 			[]string{
 				"CreateShortURL",
 				"GetShortURL",
+				"GetShortURLQRCode",
 				"CreateShortURLBatch",
 				"GetShortURLByUserID",
+				"UpdateShortURL",
 				"DeleteShortURLsByShortURIs",
+				"GetDeleteJob",
+				"GetDeletedShortURLsByUserID",
+				"RestoreShortURLsByShortURIs",
+				"GetUserSettings",
+				"UpdateUserSettings",
 			},
 		),
 		interceptor.AuthByUserIDInterceptor(
 			a.salt,
 			[]string{
 				"GetShortURLByUserID",
+				"UpdateShortURL",
 				"DeleteShortURLsByShortURIs",
+				"GetDeleteJob",
+				"GetDeletedShortURLsByUserID",
+				"RestoreShortURLsByShortURIs",
+				"GetUserSettings",
+				"UpdateUserSettings",
+			},
+		),
+	), grpc.ChainStreamInterceptor(
+		interceptor.UserIDStreamInterceptor(
+			a.salt,
+			[]string{
+				"CreateShortURLBatchStream",
 			},
 		),
 	))
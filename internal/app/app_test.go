@@ -2,13 +2,22 @@ package app
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"github.com/vkhrushchev/urlshortener/internal/common"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/vkhrushchev/urlshortener/internal/app/repository"
 	"github.com/vkhrushchev/urlshortener/internal/app/usecase"
@@ -17,28 +26,23 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vkhrushchev/urlshortener/internal/app/controller"
+	"github.com/vkhrushchev/urlshortener/internal/app/db"
+	"github.com/vkhrushchev/urlshortener/internal/app/domain"
 	"github.com/vkhrushchev/urlshortener/internal/app/dto"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/geoip"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
+	"github.com/vkhrushchev/urlshortener/internal/app/transfer"
 )
 
 func TestURLShortenerApp_createShortURLHandler(t *testing.T) {
 	shortURLRepo := repository.NewInMemoryShortURLRepository()
 
-	createShortURLUseCase := usecase.NewCreateShortURLUseCase(shortURLRepo)
-	getShortURLUseCase := usecase.NewGetShortURLUseCase(shortURLRepo)
-	deleteShortURLUseCase := usecase.NewDeleteShortURLUseCase(shortURLRepo)
-
-	appController := controller.NewAppController("", createShortURLUseCase, getShortURLUseCase)
-	apiController := controller.NewAPIController("", createShortURLUseCase, getShortURLUseCase, deleteShortURLUseCase)
-	// TODO mock healthController
-	healthController := controller.NewHealthController(nil)
-	// TODO mock internalController
-	internalController := controller.NewInternalController(nil)
-
-	app := NewURLShortenerApp("", false, nil, "", "salt", appController, apiController, healthController, internalController, nil)
-	app.RegisterHTTPHandlers()
+	app := newTestApp(t, shortURLRepo)
 
-	ts := httptest.NewServer(app.router)
-	defer ts.Close()
+	ts := app.server
 
 	tests := []struct {
 		name        string
@@ -73,41 +77,80 @@ func TestURLShortenerApp_createShortURLHandler(t *testing.T) {
 func TestURLShortenerApp_getURLHandler(t *testing.T) {
 	shortURLRepo := repository.NewInMemoryShortURLRepository()
 
-	createShortURLUseCase := usecase.NewCreateShortURLUseCase(shortURLRepo)
-	getShortURLUseCase := usecase.NewGetShortURLUseCase(shortURLRepo)
-	deleteShortURLUseCase := usecase.NewDeleteShortURLUseCase(shortURLRepo)
-
-	appController := controller.NewAppController("", createShortURLUseCase, getShortURLUseCase)
-	apiController := controller.NewAPIController("", createShortURLUseCase, getShortURLUseCase, deleteShortURLUseCase)
-	// TODO mock healthController
-	healthController := controller.NewHealthController(nil)
-	// TODO mock internalController
-	internalController := controller.NewInternalController(nil)
-
-	app := NewURLShortenerApp("", false, nil, "", "salt", appController, apiController, healthController, internalController, nil)
-	app.RegisterHTTPHandlers()
+	app := newTestApp(t, shortURLRepo)
 
 	// добавляем подготовленные данные для тестов
-	shortURLEntry, err := createShortURLUseCase.CreateShortURL(
+	shortURLEntry, err := app.createShortURLUseCase.CreateShortURL(
 		context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString()),
 		"https://google.com",
+		domain.ShortURLMetadataDomain{},
+	)
+	require.NoError(t, err, "unexpected error when save URL")
+	permanentShortURLEntry, err := app.createShortURLUseCase.CreateShortURL(
+		context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString()),
+		"https://ya.ru",
+		domain.ShortURLMetadataDomain{RedirectStatus: http.StatusMovedPermanently},
+	)
+	require.NoError(t, err, "unexpected error when save URL")
+	passthroughShortURLEntry, err := app.createShortURLUseCase.CreateShortURL(
+		context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString()),
+		"https://ya.ru/docs?lang=ru",
+		domain.ShortURLMetadataDomain{
+			QueryPassthrough: "override",
+			PathPassthrough:  true,
+			UTMParams:        map[string]string{"utm_source": "shortener"},
+		},
 	)
 	require.NoError(t, err, "unexpected error when save URL")
 
-	ts := httptest.NewServer(app.router)
-	defer ts.Close()
+	ts := app.server
 
 	tests := []struct {
-		name     string
-		path     string
-		location string
-		status   int
+		name         string
+		path         string
+		location     string
+		status       int
+		cacheControl string
 	}{
 		{
-			name:     "get success",
-			path:     "/" + shortURLEntry.ShortURI,
-			location: "https://google.com",
-			status:   http.StatusTemporaryRedirect,
+			name:         "get success",
+			path:         "/" + shortURLEntry.ShortURI,
+			location:     "https://google.com",
+			status:       http.StatusTemporaryRedirect,
+			cacheControl: "no-store",
+		},
+		{
+			name:         "get success with own redirect status",
+			path:         "/" + permanentShortURLEntry.ShortURI,
+			location:     "https://ya.ru",
+			status:       http.StatusMovedPermanently,
+			cacheControl: "public, max-age=86400",
+		},
+		{
+			name:         "get success with query passthrough disabled",
+			path:         "/" + shortURLEntry.ShortURI + "?utm_source=mail",
+			location:     "https://google.com",
+			status:       http.StatusTemporaryRedirect,
+			cacheControl: "no-store",
+		},
+		{
+			name:         "get success with utm params",
+			path:         "/" + passthroughShortURLEntry.ShortURI,
+			location:     "https://ya.ru/docs?lang=ru&utm_source=shortener",
+			status:       http.StatusTemporaryRedirect,
+			cacheControl: "no-store",
+		},
+		{
+			name:         "get success with path and query passthrough",
+			path:         "/" + passthroughShortURLEntry.ShortURI + "/extra/path?lang=en&utm_source=mail",
+			location:     "https://ya.ru/docs/extra/path?lang=en&utm_source=mail",
+			status:       http.StatusTemporaryRedirect,
+			cacheControl: "no-store",
+		},
+		{
+			name:   "path passthrough disabled",
+			path:   "/" + shortURLEntry.ShortURI + "/extra",
+			status: http.StatusNotFound,
 		},
 		{
 			name:   "not found",
@@ -128,32 +171,531 @@ func TestURLShortenerApp_getURLHandler(t *testing.T) {
 
 			assert.Equal(t, tt.status, statusCode)
 			assert.Empty(t, responseBody)
-			if statusCode == http.StatusTemporaryRedirect {
+			if tt.location != "" {
 				assert.Equal(t, tt.location, headers.Get("Location"))
+				assert.Equal(t, tt.cacheControl, headers.Get("Cache-Control"))
 			}
 		})
 	}
 }
 
-func TestURLShortenerApp_createShortURLHandlerAPI(t *testing.T) {
+func TestURLShortenerApp_getURLHandler_targeting(t *testing.T) {
 	shortURLRepo := repository.NewInMemoryShortURLRepository()
 
-	createShortURLUseCase := usecase.NewCreateShortURLUseCase(shortURLRepo)
-	getShortURLUseCase := usecase.NewGetShortURLUseCase(shortURLRepo)
-	deleteShortURLUseCase := usecase.NewDeleteShortURLUseCase(shortURLRepo)
+	countryResolver, err := geoip.NewResolver("geoip/testdata/GeoIP2-Country-Test.mmdb")
+	require.NoError(t, err, "unexpected error when open GeoIP database")
+	defer countryResolver.Close()
 
-	appController := controller.NewAppController("", createShortURLUseCase, getShortURLUseCase)
-	apiController := controller.NewAPIController("", createShortURLUseCase, getShortURLUseCase, deleteShortURLUseCase)
-	// TODO mock healthController
-	healthController := controller.NewHealthController(nil)
-	// TODO mock internalController
-	internalController := controller.NewInternalController(nil)
+	// заголовок X-Real-IP выставляет прокси из доверенной подсети
+	_, trustedSubnet, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
 
-	app := NewURLShortenerApp("", false, nil, "", "salt", appController, apiController, healthController, internalController, nil)
-	app.RegisterHTTPHandlers()
+	app := newTestApp(t, shortURLRepo, withCountryResolver(countryResolver), withTrustedSubnet(trustedSubnet))
+
+	// добавляем подготовленные данные для тестов
+	shortURLEntry, err := app.createShortURLUseCase.CreateShortURL(
+		context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString()),
+		"https://example.com",
+		domain.ShortURLMetadataDomain{
+			TargetingRules: []targeting.Rule{
+				{Platform: targeting.PlatformIOS, URL: "https://apps.apple.com/app/id1"},
+				{Platform: targeting.PlatformAndroid, URL: "https://play.google.com/store/apps/details?id=app"},
+				{Locale: "ru", URL: "https://example.ru"},
+				{Country: "SE", URL: "https://example.se"},
+			},
+		},
+	)
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	tests := []struct {
+		name           string
+		userAgent      string
+		acceptLanguage string
+		realIP         string
+		location       string
+	}{
+		{
+			name:      "ios",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) Mobile/15E148",
+			location:  "https://apps.apple.com/app/id1",
+		},
+		{
+			name:      "android",
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) Mobile Safari/537.36",
+			location:  "https://play.google.com/store/apps/details?id=app",
+		},
+		{
+			name:           "locale",
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64)",
+			acceptLanguage: "ru-RU,ru;q=0.9",
+			location:       "https://example.ru",
+		},
+		{
+			name:           "country",
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64)",
+			acceptLanguage: "en-US",
+			realIP:         "89.160.20.112",
+			location:       "https://example.se",
+		},
+		{
+			name:           "country without rule",
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64)",
+			acceptLanguage: "en-US",
+			realIP:         "81.2.69.142",
+			location:       "https://example.com",
+		},
+		{
+			name:           "fallback",
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64)",
+			acceptLanguage: "en-US",
+			location:       "https://example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, ts.URL+"/"+shortURLEntry.ShortURI, nil)
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", tt.userAgent)
+			request.Header.Set("Accept-Language", tt.acceptLanguage)
+			if tt.realIP != "" {
+				request.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			response, err := ts.Client().Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			assert.Equal(t, http.StatusTemporaryRedirect, response.StatusCode)
+			assert.Equal(t, tt.location, response.Header.Get("Location"))
+			assert.Equal(t, "User-Agent, Accept-Language", response.Header.Get("Vary"))
+		})
+	}
+}
+
+func TestURLShortenerApp_getCountryStats(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+	countryResolver, err := geoip.NewResolver("geoip/testdata/GeoIP2-Country-Test.mmdb")
+	require.NoError(t, err, "unexpected error when open GeoIP database")
+	defer countryResolver.Close()
+
+	_, trustedSubnet, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	app := newTestApp(t, shortURLRepo, withCountryResolver(countryResolver), withTrustedSubnet(trustedSubnet))
+
+	// добавляем подготовленные данные для тестов
+	testUserID := uuid.NewString()
+	shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(
+		context.WithValue(context.Background(), common.UserIDContextKey, testUserID),
+		"https://example.com",
+		domain.ShortURLMetadataDomain{},
+	)
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	// переходы без правил таргетинга тоже учитываются по странам, адрес без страны - под кодом ZZ
+	for _, realIP := range []string{"89.160.20.112", "81.2.69.142", "89.160.20.112", ""} {
+		request, err := http.NewRequest(http.MethodGet, ts.URL+"/"+shortURLDomain.ShortURI, nil)
+		require.NoError(t, err)
+		if realIP != "" {
+			request.Header.Set("X-Real-IP", realIP)
+		}
+
+		response, err := ts.Client().Do(request)
+		require.NoError(t, err)
+		response.Body.Close()
+		require.Equal(t, http.StatusTemporaryRedirect, response.StatusCode)
+	}
+
+	userIDSignatureBytes := md5.Sum([]byte(testUserID + "salt"))
+	getCountryStats := func(shortURI string) *http.Response {
+		request, err := http.NewRequest(http.MethodGet, ts.URL+"/api/user/urls/"+shortURI+"/countries", nil)
+		require.NoError(t, err)
+		request.AddCookie(&http.Cookie{Name: "userID", Value: testUserID})
+		request.AddCookie(&http.Cookie{Name: "userIDSignature", Value: hex.EncodeToString(userIDSignatureBytes[:])})
+
+		response, err := ts.Client().Do(request)
+		require.NoError(t, err)
+		return response
+	}
+
+	response := getCountryStats(shortURLDomain.ShortURI)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var apiResponse dto.APIGetCountryStatsResponse
+	err = json.NewDecoder(response.Body).Decode(&apiResponse)
+	require.NoError(t, err, "app_test: error when unmarshall dto.APIGetCountryStatsResponse: %v", err)
+	assert.Equal(
+		t,
+		dto.APIGetCountryStatsResponse{
+			{Country: "SE", Clicks: 2},
+			{Country: "GB", Clicks: 1},
+			{Country: usecase.UnknownCountry, Clicks: 1},
+		},
+		apiResponse,
+	)
+
+	response = getCountryStats("not_existed_shortURL")
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestURLShortenerApp_getURLHandler_split(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+	splitUseCase := usecase.NewSplitUseCase(shortURLRepo, shortURLRepo)
+
+	app := newTestApp(t, shortURLRepo, withSplitUseCase(splitUseCase))
+
+	// добавляем подготовленные данные для тестов
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(
+		testCtx,
+		"https://example.com",
+		domain.ShortURLMetadataDomain{
+			SplitVariants: []split.Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: 50},
+				{Name: "b", URL: "https://example.com/b", Weight: 50},
+			},
+		},
+	)
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	userIDSignatureBytes := md5.Sum([]byte(testUserID + "salt"))
+	doRequest := func(method string, path string, body string, cookies ...*http.Cookie) *http.Response {
+		request, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		if body != "" {
+			request.Header.Add("Content-Type", "application/json")
+		}
+		for _, cookie := range cookies {
+			request.AddCookie(cookie)
+		}
+
+		response, err := ts.Client().Do(request)
+		require.NoError(t, err)
+		return response
+	}
+	userCookies := []*http.Cookie{
+		{Name: "userID", Value: testUserID},
+		{Name: "userIDSignature", Value: hex.EncodeToString(userIDSignatureBytes[:])},
+	}
+
+	// первый переход назначает вариант и закрепляет его в cookie
+	response := doRequest(http.MethodGet, "/"+shortURLDomain.ShortURI, "")
+	response.Body.Close()
+	require.Equal(t, http.StatusTemporaryRedirect, response.StatusCode)
+	assert.Equal(t, "no-store", response.Header.Get("Cache-Control"))
+	assert.Equal(t, "Cookie", response.Header.Get("Vary"))
+
+	var variantCookie *http.Cookie
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == split.CookieName {
+			variantCookie = cookie
+		}
+	}
+	require.NotNil(t, variantCookie, "variant cookie must be set")
+	assert.Equal(t, "/"+shortURLDomain.ShortURI, variantCookie.Path)
+	assignedLocation := "https://example.com/" + variantCookie.Value
+	otherVariant := map[string]string{"a": "b", "b": "a"}[variantCookie.Value]
+	assert.Equal(t, assignedLocation, response.Header.Get("Location"))
+
+	// повторные переходы ведут на закрепленный вариант
+	for i := 0; i < 2; i++ {
+		response = doRequest(http.MethodGet, "/"+shortURLDomain.ShortURI, "", &http.Cookie{Name: split.CookieName, Value: variantCookie.Value})
+		response.Body.Close()
+		assert.Equal(t, assignedLocation, response.Header.Get("Location"))
+		assert.Empty(t, response.Header.Get("Set-Cookie"), "assigned variant cookie must not be reset")
+	}
+
+	// изменение весов переназначает посетителей варианта с нулевым весом, короткая ссылка не меняется
+	response = doRequest(
+		http.MethodPatch,
+		"/api/user/urls/"+shortURLDomain.ShortURI,
+		fmt.Sprintf(
+			`{"split_variants": [{"name": %q, "url": "https://example.com/%[1]s", "weight": 0}, {"name": %q, "url": "https://example.com/%[2]s", "weight": 100}]}`,
+			variantCookie.Value,
+			otherVariant,
+		),
+		userCookies...,
+	)
+	response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = doRequest(http.MethodGet, "/"+shortURLDomain.ShortURI, "", &http.Cookie{Name: split.CookieName, Value: variantCookie.Value})
+	response.Body.Close()
+	assert.Equal(t, "https://example.com/"+otherVariant, response.Header.Get("Location"))
+
+	// статистика переходов по вариантам
+	response = doRequest(http.MethodGet, "/api/user/urls/"+shortURLDomain.ShortURI+"/split", "", userCookies...)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var apiResponse dto.APIGetSplitStatsResponse
+	err = json.NewDecoder(response.Body).Decode(&apiResponse)
+	require.NoError(t, err, "app_test: error when unmarshall dto.APIGetSplitStatsResponse: %v", err)
+
+	clicks := make(map[string]int64, len(apiResponse))
+	for _, entry := range apiResponse {
+		clicks[entry.Name] = entry.Clicks
+	}
+	assert.Equal(t, map[string]int64{variantCookie.Value: 3, otherVariant: 1}, clicks)
+
+	response = doRequest(http.MethodGet, "/api/user/urls/not_existed_shortURL/split", "", userCookies...)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestURLShortenerApp_getURLHandler_password(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+	unlockShortURLUseCase := usecase.NewUnlockShortURLUseCase(shortURLRepo)
+
+	app := newTestApp(t, shortURLRepo, withRedirectStatus(http.StatusPermanentRedirect), withUnlocker(unlockShortURLUseCase))
+
+	// добавляем подготовленные данные для тестов
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(
+		testCtx,
+		"https://example.com",
+		domain.ShortURLMetadataDomain{Password: "secret", QueryPassthrough: "override"},
+	)
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	response, err := ts.Client().Get(ts.URL + "/" + shortURLDomain.ShortURI)
+	require.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", response.Header.Get("Content-Type"))
+	assert.Equal(t, "no-store", response.Header.Get("Cache-Control"))
+	assert.Contains(t, string(body), `name="password"`)
+	assert.NotContains(t, string(body), "https://example.com", "original url must not be revealed")
 
-	ts := httptest.NewServer(app.router)
-	defer ts.Close()
+	tests := []struct {
+		name       string
+		password   string
+		statusCode int
+		location   string
+	}{
+		{name: "invalid password", password: "wrong", statusCode: http.StatusForbidden},
+		{name: "empty password", password: "", statusCode: http.StatusForbidden},
+		{name: "valid password", password: "secret", statusCode: http.StatusSeeOther, location: "https://example.com?utm_source=qr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := ts.Client().PostForm(
+				ts.URL+"/"+shortURLDomain.ShortURI+"?utm_source=qr",
+				url.Values{"password": {tt.password}},
+			)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			assert.Equal(t, tt.statusCode, response.StatusCode)
+			assert.Equal(t, tt.location, response.Header.Get("Location"))
+		})
+	}
+
+	for i := 0; i < usecase.PasswordClientAttemptsMax; i++ {
+		response, err = ts.Client().PostForm(ts.URL+"/"+shortURLDomain.ShortURI, url.Values{"password": {"wrong"}})
+		require.NoError(t, err)
+		response.Body.Close()
+	}
+	response, err = ts.Client().PostForm(ts.URL+"/"+shortURLDomain.ShortURI, url.Values{"password": {"secret"}})
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get("Retry-After"))
+}
+
+func TestURLShortenerApp_getURLHandler_password_realIP(t *testing.T) {
+	_, proxySubnet, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+	_, otherSubnet, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		trustedSubnet *net.IPNet
+		statusCode    int
+	}{
+		{name: "header without trusted subnet is ignored", statusCode: http.StatusTooManyRequests},
+		{name: "header from untrusted subnet is ignored", trustedSubnet: otherSubnet, statusCode: http.StatusTooManyRequests},
+		{name: "header from trusted proxy", trustedSubnet: proxySubnet, statusCode: http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shortURLRepo := repository.NewInMemoryShortURLRepository()
+			app := newTestApp(t, shortURLRepo, withTrustedSubnet(tt.trustedSubnet))
+
+			testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+			shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(
+				testCtx, "https://example.com", domain.ShortURLMetadataDomain{Password: "secret"})
+			require.NoError(t, err, "unexpected error when save URL")
+
+			ts := app.server
+			doUnlock := func(password string, realIP string) *http.Response {
+				request, err := http.NewRequest(
+					http.MethodPost, ts.URL+"/"+shortURLDomain.ShortURI, strings.NewReader(url.Values{"password": {password}}.Encode()))
+				require.NoError(t, err)
+				request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				request.Header.Set("X-Real-IP", realIP)
+
+				response, err := ts.Client().Do(request)
+				require.NoError(t, err)
+				response.Body.Close()
+
+				return response
+			}
+
+			// каждая попытка подписана новым адресом в X-Real-IP
+			for i := 0; i < usecase.PasswordClientAttemptsMax; i++ {
+				response := doUnlock("wrong", fmt.Sprintf("203.0.113.%d", i+1))
+				assert.Equal(t, http.StatusForbidden, response.StatusCode)
+			}
+
+			response := doUnlock("secret", "203.0.113.100")
+			assert.Equal(t, tt.statusCode, response.StatusCode)
+		})
+	}
+}
+
+func TestURLShortenerApp_getURLHandler_maxClicks(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+	app := newTestApp(t, shortURLRepo, withRedirectStatus(http.StatusPermanentRedirect))
+
+	// добавляем подготовленные данные для тестов
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(
+		testCtx,
+		"https://example.com",
+		domain.ShortURLMetadataDomain{MaxClicks: 5},
+	)
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	statusCodes := make(map[int]int)
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			response, err := ts.Client().Get(ts.URL + "/" + shortURLDomain.ShortURI)
+			if !assert.NoError(t, err) {
+				return
+			}
+			response.Body.Close()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			statusCodes[response.StatusCode]++
+			if response.StatusCode == http.StatusPermanentRedirect {
+				assert.Equal(t, "no-store", response.Header.Get("Cache-Control"), "redirect of link with max clicks must not be cached")
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[int]int{http.StatusPermanentRedirect: 5, http.StatusGone: 25}, statusCodes)
+
+	response, err := ts.Client().Get(ts.URL + "/" + shortURLDomain.ShortURI)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusGone, response.StatusCode, "exhausted link must behave as gone")
+}
+
+func TestURLShortenerApp_getURLHandler_activityWindow(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		inactiveURL  string
+		metadata     domain.ShortURLMetadataDomain
+		statusCode   int
+		location     string
+		bodyContains string
+	}{
+		{
+			name:       "inside window",
+			metadata:   domain.ShortURLMetadataDomain{NotBefore: &past, NotAfter: &future},
+			statusCode: http.StatusTemporaryRedirect,
+			location:   "https://example.com",
+		},
+		{
+			name:         "not yet active",
+			metadata:     domain.ShortURLMetadataDomain{NotBefore: &future},
+			statusCode:   http.StatusForbidden,
+			bodyContains: "Ссылка еще не активна",
+		},
+		{
+			name:       "expired",
+			metadata:   domain.ShortURLMetadataDomain{NotAfter: &past},
+			statusCode: http.StatusGone,
+		},
+		{
+			name:        "not yet active with inactive url",
+			inactiveURL: "https://example.com/soon",
+			metadata:    domain.ShortURLMetadataDomain{NotBefore: &future},
+			statusCode:  http.StatusFound,
+			location:    "https://example.com/soon",
+		},
+		{
+			name:        "expired with inactive url",
+			inactiveURL: "https://example.com/soon",
+			metadata:    domain.ShortURLMetadataDomain{NotAfter: &past},
+			statusCode:  http.StatusFound,
+			location:    "https://example.com/soon",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+			app := newTestApp(t, shortURLRepo, withInactiveURL(tt.inactiveURL))
+
+			testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+			shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(testCtx, "https://example.com", tt.metadata)
+			require.NoError(t, err, "unexpected error when save URL")
+
+			ts := app.server
+
+			response, err := ts.Client().Get(ts.URL + "/" + shortURLDomain.ShortURI)
+			require.NoError(t, err)
+			body, err := io.ReadAll(response.Body)
+			response.Body.Close()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.statusCode, response.StatusCode)
+			assert.Equal(t, tt.location, response.Header.Get("Location"))
+			assert.Contains(t, string(body), tt.bodyContains)
+		})
+	}
+}
+
+func TestURLShortenerApp_createShortURLHandlerAPI(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+	app := newTestApp(t, shortURLRepo)
+
+	ts := app.server
 
 	testCases := []struct {
 		apiRequest         *dto.APICreateShortURLRequest
@@ -233,22 +775,9 @@ func TestURLShortenerApp_createShortURLHandlerAPI(t *testing.T) {
 func TestURLShortenerApp_createShortURLBatchHandlerAPI(t *testing.T) {
 	shortURLRepo := repository.NewInMemoryShortURLRepository()
 
-	createShortURLUseCase := usecase.NewCreateShortURLUseCase(shortURLRepo)
-	getShortURLUseCase := usecase.NewGetShortURLUseCase(shortURLRepo)
-	deleteShortURLUseCase := usecase.NewDeleteShortURLUseCase(shortURLRepo)
-
-	appController := controller.NewAppController("", createShortURLUseCase, getShortURLUseCase)
-	apiController := controller.NewAPIController("", createShortURLUseCase, getShortURLUseCase, deleteShortURLUseCase)
-	// TODO mock healthController
-	healthController := controller.NewHealthController(nil)
-	// TODO mock internalController
-	internalController := controller.NewInternalController(nil)
-
-	app := NewURLShortenerApp("", false, nil, "", "salt", appController, apiController, healthController, internalController, nil)
-	app.RegisterHTTPHandlers()
+	app := newTestApp(t, shortURLRepo)
 
-	ts := httptest.NewServer(app.router)
-	defer ts.Close()
+	ts := app.server
 
 	testCases := []struct {
 		name               string
@@ -331,30 +860,809 @@ func TestURLShortenerApp_createShortURLBatchHandlerAPI(t *testing.T) {
 	}
 }
 
-func executeRequest(
-	t *testing.T,
-	ts *httptest.Server,
-	method string,
-	path string,
-	requestBody string,
-	contentType string,
-) (int, http.Header, string) {
-	request, err := http.NewRequest(method, ts.URL+path, strings.NewReader(requestBody))
-	require.NoError(t, err)
+func TestURLShortenerApp_getShortURLsByUserIDHandlerAPI(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
 
-	request.Header.Add("Content-Type", contentType)
+	app := newTestApp(t, shortURLRepo)
 
-	// отключаем редирект
-	ts.Client().CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		return http.ErrUseLastResponse
+	// добавляем подготовленные данные для тестов
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	for _, longURL := range []string{"https://ya.ru", "https://mail.ya.ru", "https://google.com"} {
+		_, err := app.createShortURLUseCase.CreateShortURL(testCtx, longURL, domain.ShortURLMetadataDomain{})
+		require.NoError(t, err, "unexpected error when save URL")
 	}
 
-	response, err := ts.Client().Do(request)
-	require.NoError(t, err)
+	ts := app.server
 
-	responseBody, err := io.ReadAll(response.Body)
-	require.NoError(t, err)
-	defer response.Body.Close()
+	testCases := []struct {
+		name               string
+		query              string
+		expectedStatusCode int
+		expectedTotalCount string
+		expectedCount      int
+		expectedNextCursor bool
+	}{
+		{
+			name:               "first page",
+			query:              "?limit=2",
+			expectedStatusCode: http.StatusOK,
+			expectedTotalCount: "3",
+			expectedCount:      2,
+			expectedNextCursor: true,
+		},
+		{
+			name:               "filter by domain",
+			query:              "?domain=ya.ru&sort=-original_url",
+			expectedStatusCode: http.StatusOK,
+			expectedTotalCount: "2",
+			expectedCount:      2,
+		},
+		{
+			name:               "nothing found",
+			query:              "?status=deleted",
+			expectedStatusCode: http.StatusNoContent,
+			expectedTotalCount: "0",
+		},
+		{
+			name:               "bad limit",
+			query:              "?limit=abc",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "bad created_from",
+			query:              "?created_from=yesterday",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "bad sort",
+			query:              "?sort=user_id",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
 
-	return response.StatusCode, response.Header, string(responseBody)
+	userIDSignatureBytes := md5.Sum([]byte(testUserID + "salt"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, ts.URL+"/api/user/urls"+tc.query, nil)
+			require.NoError(t, err)
+			request.AddCookie(&http.Cookie{Name: "userID", Value: testUserID})
+			request.AddCookie(&http.Cookie{Name: "userIDSignature", Value: hex.EncodeToString(userIDSignatureBytes[:])})
+
+			response, err := ts.Client().Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedTotalCount, response.Header.Get("X-Total-Count"))
+			assert.Equal(t, tc.expectedNextCursor, response.Header.Get("X-Next-Cursor") != "")
+
+			if response.StatusCode == http.StatusOK {
+				var apiResponse dto.APIGetAllURLByUserIDResponse
+				err = json.NewDecoder(response.Body).Decode(&apiResponse)
+				require.NoError(t, err, "app_test: error when unmarshall dto.APIGetAllURLByUserIDResponse: %v", err)
+
+				assert.Equal(t, tc.expectedCount, len(apiResponse))
+			}
+		})
+	}
+}
+
+func TestURLShortenerApp_updateShortURLHandlerAPI(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+	app := newTestApp(t, shortURLRepo)
+
+	// добавляем подготовленные данные для тестов
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{Title: "Яндекс"})
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	testCases := []struct {
+		name               string
+		shortURI           string
+		requestBody        string
+		expectedStatusCode int
+		expectedTitle      string
+		expectedTags       []string
+	}{
+		{
+			name:               "update tags",
+			shortURI:           shortURLDomain.ShortURI,
+			requestBody:        `{"tags": ["Search", "work"]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedTitle:      "Яндекс",
+			expectedTags:       []string{"search", "work"},
+		},
+		{
+			name:               "clear title",
+			shortURI:           shortURLDomain.ShortURI,
+			requestBody:        `{"title": ""}`,
+			expectedStatusCode: http.StatusOK,
+			expectedTitle:      "",
+			expectedTags:       []string{"search", "work"},
+		},
+		{
+			name:               "too long title",
+			shortURI:           shortURLDomain.ShortURI,
+			requestBody:        `{"title": "` + strings.Repeat("a", usecase.ShortURLTitleMaxLength+1) + `"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "not found",
+			shortURI:           "not_existed_shortURL",
+			requestBody:        `{"title": "Яндекс"}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	userIDSignatureBytes := md5.Sum([]byte(testUserID + "salt"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPatch, ts.URL+"/api/user/urls/"+tc.shortURI, strings.NewReader(tc.requestBody))
+			require.NoError(t, err)
+			request.Header.Add("Content-Type", "application/json")
+			request.AddCookie(&http.Cookie{Name: "userID", Value: testUserID})
+			request.AddCookie(&http.Cookie{Name: "userIDSignature", Value: hex.EncodeToString(userIDSignatureBytes[:])})
+
+			response, err := ts.Client().Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+
+			if response.StatusCode == http.StatusOK {
+				var apiResponse dto.APIGetAllURLByUserIDResponseEntry
+				err = json.NewDecoder(response.Body).Decode(&apiResponse)
+				require.NoError(t, err, "app_test: error when unmarshall dto.APIGetAllURLByUserIDResponseEntry: %v", err)
+
+				assert.Equal(t, tc.expectedTitle, apiResponse.Title)
+				assert.Equal(t, tc.expectedTags, apiResponse.Tags)
+			}
+		})
+	}
+}
+
+func TestURLShortenerApp_getQRCodeHandler(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+	app := newTestApp(t, shortURLRepo, withShortDomains(shortdomain.NewDomains("http://localhost:8080", nil)))
+
+	// добавляем подготовленные данные для тестов
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	testCases := []struct {
+		name                string
+		path                string
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{
+			name:                "png",
+			path:                "/" + shortURLDomain.ShortURI + "/qr",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/png",
+		},
+		{
+			name:                "svg",
+			path:                "/" + shortURLDomain.ShortURI + "/qr?format=svg&size=512&margin=1&level=H&fg=1a2b3c&bg=ffffff00",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/svg+xml",
+		},
+		{
+			name:               "bad size",
+			path:               "/" + shortURLDomain.ShortURI + "/qr?size=big",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "bad color",
+			path:               "/" + shortURLDomain.ShortURI + "/qr?fg=black",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "not found",
+			path:               "/not_existed_shortURL/qr",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statusCode, headers, _ := executeRequest(t, ts, http.MethodGet, tc.path, "", "")
+
+			assert.Equal(t, tc.expectedStatusCode, statusCode)
+			if statusCode != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tc.expectedContentType, headers.Get("Content-Type"))
+			assert.Equal(t, "public, max-age=86400", headers.Get("Cache-Control"))
+			require.NotEmpty(t, headers.Get("ETag"))
+
+			request, err := http.NewRequest(http.MethodGet, ts.URL+tc.path, nil)
+			require.NoError(t, err)
+			request.Header.Set("If-None-Match", headers.Get("ETag"))
+
+			response, err := ts.Client().Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			assert.Equal(t, http.StatusNotModified, response.StatusCode)
+		})
+	}
+}
+
+func TestURLShortenerApp_getURLHandler_fallback(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		defaultURL   string
+		userFallback string
+		metadata     domain.ShortURLMetadataDomain
+		deleted      bool
+		statusCode   int
+		location     string
+	}{
+		{
+			name:       "deleted with default fallback",
+			defaultURL: "https://example.com/default",
+			deleted:    true,
+			statusCode: http.StatusFound,
+			location:   "https://example.com/default?reason=deleted",
+		},
+		{
+			name:       "disabled with link fallback",
+			defaultURL: "https://example.com/default",
+			metadata:   domain.ShortURLMetadataDomain{Disabled: true, FallbackURL: "https://example.com/link"},
+			statusCode: http.StatusFound,
+			location:   "https://example.com/link?reason=disabled",
+		},
+		{
+			name:         "expired with user fallback",
+			defaultURL:   "https://example.com/default",
+			userFallback: "https://example.com/user",
+			metadata:     domain.ShortURLMetadataDomain{NotAfter: &past},
+			statusCode:   http.StatusFound,
+			location:     "https://example.com/user?reason=expired",
+		},
+		{
+			name:       "disabled without fallback",
+			metadata:   domain.ShortURLMetadataDomain{Disabled: true},
+			statusCode: http.StatusGone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shortURLRepo := repository.NewInMemoryShortURLRepository()
+
+			fallbackUseCase := usecase.NewFallbackUseCase(shortURLRepo, tt.defaultURL)
+
+			app := newTestApp(t, shortURLRepo, withFallbackUseCase(fallbackUseCase))
+
+			testUserID := uuid.NewString()
+			testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+			shortURLDomain, err := app.createShortURLUseCase.CreateShortURL(testCtx, "https://example.com", tt.metadata)
+			require.NoError(t, err, "unexpected error when save URL")
+			if tt.deleted {
+				_, err = shortURLRepo.DeleteShortURLsByShortURIs(testCtx, []string{shortURLDomain.ShortURI})
+				require.NoError(t, err, "unexpected error when delete URL")
+			}
+			if tt.userFallback != "" {
+				err = shortURLRepo.SaveUserSettings(testCtx, entity.UserSettingsEntity{UserID: testUserID, FallbackURL: tt.userFallback})
+				require.NoError(t, err, "unexpected error when save user settings")
+			}
+
+			ts := app.server
+
+			response, err := ts.Client().Get(ts.URL + "/" + shortURLDomain.ShortURI)
+			require.NoError(t, err)
+			response.Body.Close()
+
+			assert.Equal(t, tt.statusCode, response.StatusCode)
+			assert.Equal(t, tt.location, response.Header.Get("Location"))
+			assert.Equal(t, "no-store", response.Header.Get("Cache-Control"), "fallback response must not be cached")
+		})
+	}
+}
+
+func TestURLShortenerApp_shortDomains(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+	shortDomains := shortdomain.NewDomains("http://localhost:8080", []string{"go.brand-a.com", "brand-b.link"})
+
+	app := newTestApp(t, shortURLRepo, withShortDomains(shortDomains))
+
+	// один и тот же идентификатор короткой ссылки на домене по умолчанию и на дополнительном домене
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := shortURLRepo.SaveShortURLs(testCtx, []entity.ShortURLEntity{
+		{UUID: uuid.NewString(), ShortURI: "abc", LongURL: "https://example.com/default"},
+		{UUID: uuid.NewString(), ShortURI: shortdomain.Key("brand-b.link", "abc"), LongURL: "https://example.com/brand-b"},
+	})
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	redirectTests := []struct {
+		name       string
+		host       string
+		path       string
+		statusCode int
+		location   string
+	}{
+		{
+			name:       "default domain",
+			path:       "/abc",
+			statusCode: http.StatusTemporaryRedirect,
+			location:   "https://example.com/default",
+		},
+		{
+			name:       "additional domain",
+			host:       "brand-b.link",
+			path:       "/abc",
+			statusCode: http.StatusTemporaryRedirect,
+			location:   "https://example.com/brand-b",
+		},
+		{
+			name:       "other additional domain",
+			host:       "go.brand-a.com",
+			path:       "/abc",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "key of additional domain on default domain",
+			path:       "/abc@brand-b.link",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range redirectTests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, ts.URL+tt.path, nil)
+			require.NoError(t, err)
+			if tt.host != "" {
+				request.Host = tt.host
+			}
+
+			response, err := ts.Client().Do(request)
+			require.NoError(t, err)
+			response.Body.Close()
+
+			assert.Equal(t, tt.statusCode, response.StatusCode)
+			assert.Equal(t, tt.location, response.Header.Get("Location"))
+		})
+	}
+
+	t.Run("create on additional domain", func(t *testing.T) {
+		statusCode, _, responseBody := executeRequest(
+			t,
+			ts,
+			http.MethodPost,
+			"/api/shorten",
+			`{"url": "https://example.com/new", "short_domain": "go.brand-a.com"}`,
+			"application/json",
+		)
+		require.Equal(t, http.StatusCreated, statusCode)
+
+		var apiResponse dto.APICreateShortURLResponse
+		require.NoError(t, json.Unmarshal([]byte(responseBody), &apiResponse))
+		assert.True(t, strings.HasPrefix(apiResponse.Result, "http://go.brand-a.com/"), "short url must be on chosen domain")
+	})
+
+	t.Run("create on unknown domain", func(t *testing.T) {
+		statusCode, _, _ := executeRequest(
+			t,
+			ts,
+			http.MethodPost,
+			"/api/shorten",
+			`{"url": "https://example.com/new", "short_domain": "brand-c.io"}`,
+			"application/json",
+		)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+	})
+}
+
+// testApp - приложение, поднятое на тестовом HTTP-сервере
+type testApp struct {
+	server                *httptest.Server
+	createShortURLUseCase *usecase.CreateShortURLUseCase
+}
+
+// testAppOptions - зависимости приложения, которые тесты могут подменить;
+// use case'ы, не заданные тестом, создаются поверх того же репозитория, как в cmd/shortener
+type testAppOptions struct {
+	shortDomains    *shortdomain.Domains
+	redirectStatus  int
+	inactiveURL     string
+	unlocker        *usecase.UnlockShortURLUseCase
+	splitUseCase    *usecase.SplitUseCase
+	countryResolver interface{ Country(addr netip.Addr) string }
+	fallbackUseCase *usecase.FallbackUseCase
+	trustedSubnet   *net.IPNet
+	dbLookup        *db.DBLookup
+	cacheStats      interface {
+		CacheStats() (hits int64, misses int64)
+	}
+}
+
+type testAppOption func(*testAppOptions)
+
+func withShortDomains(shortDomains *shortdomain.Domains) testAppOption {
+	return func(o *testAppOptions) { o.shortDomains = shortDomains }
+}
+
+func withRedirectStatus(redirectStatus int) testAppOption {
+	return func(o *testAppOptions) { o.redirectStatus = redirectStatus }
+}
+
+func withInactiveURL(inactiveURL string) testAppOption {
+	return func(o *testAppOptions) { o.inactiveURL = inactiveURL }
+}
+
+func withUnlocker(unlocker *usecase.UnlockShortURLUseCase) testAppOption {
+	return func(o *testAppOptions) { o.unlocker = unlocker }
+}
+
+func withSplitUseCase(splitUseCase *usecase.SplitUseCase) testAppOption {
+	return func(o *testAppOptions) { o.splitUseCase = splitUseCase }
+}
+
+func withCountryResolver(countryResolver *geoip.Resolver) testAppOption {
+	return func(o *testAppOptions) { o.countryResolver = countryResolver }
+}
+
+func withFallbackUseCase(fallbackUseCase *usecase.FallbackUseCase) testAppOption {
+	return func(o *testAppOptions) { o.fallbackUseCase = fallbackUseCase }
+}
+
+func withTrustedSubnet(trustedSubnet *net.IPNet) testAppOption {
+	return func(o *testAppOptions) { o.trustedSubnet = trustedSubnet }
+}
+
+func withDBLookup(dbLookup *db.DBLookup) testAppOption {
+	return func(o *testAppOptions) { o.dbLookup = dbLookup }
+}
+
+func withCacheStats(cachedShortURLRepo *repository.CachedShortURLRepository) testAppOption {
+	return func(o *testAppOptions) { o.cacheStats = cachedShortURLRepo }
+}
+
+// newTestApp собирает приложение поверх shortURLRepo и запускает тестовый сервер,
+// который не следует за редиректами и закрывается по завершении теста
+func newTestApp(t *testing.T, shortURLRepo *repository.InMemoryShortURLRepository, opts ...testAppOption) *testApp {
+	t.Helper()
+
+	options := testAppOptions{
+		shortDomains:   shortdomain.NewDomains("", nil),
+		redirectStatus: http.StatusTemporaryRedirect,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.unlocker == nil {
+		options.unlocker = usecase.NewUnlockShortURLUseCase(shortURLRepo)
+	}
+	if options.splitUseCase == nil {
+		options.splitUseCase = usecase.NewSplitUseCase(shortURLRepo, shortURLRepo)
+	}
+	if options.fallbackUseCase == nil {
+		options.fallbackUseCase = usecase.NewFallbackUseCase(shortURLRepo, "")
+	}
+
+	createShortURLUseCase := usecase.NewCreateShortURLUseCase(shortURLRepo, nil, options.shortDomains)
+	getShortURLUseCase := usecase.NewGetShortURLUseCase(shortURLRepo)
+	deleteShortURLUseCase := usecase.NewDeleteShortURLUseCase(shortURLRepo, shortURLRepo)
+	countryStatsUseCase := usecase.NewCountryStatsUseCase(shortURLRepo, shortURLRepo)
+
+	appController := controller.NewAppController(
+		options.shortDomains,
+		options.redirectStatus,
+		options.inactiveURL,
+		createShortURLUseCase,
+		getShortURLUseCase,
+		options.unlocker,
+		options.splitUseCase,
+		options.countryResolver,
+		countryStatsUseCase,
+		options.fallbackUseCase,
+	)
+	apiController := controller.NewAPIController(
+		options.shortDomains,
+		createShortURLUseCase,
+		getShortURLUseCase,
+		usecase.NewUpdateShortURLUseCase(shortURLRepo),
+		deleteShortURLUseCase,
+		options.splitUseCase,
+		countryStatsUseCase,
+		nil,
+		transfer.NewThirdPartyImporter(createShortURLUseCase),
+	)
+	healthController := controller.NewHealthController(options.dbLookup, options.cacheStats)
+	internalController := controller.NewInternalController(nil, transfer.NewExporter(shortURLRepo), transfer.NewImporter(shortURLRepo))
+
+	app := NewURLShortenerApp("", false, options.trustedSubnet, "", "salt", appController, apiController, healthController, internalController, nil)
+	app.RegisterHTTPHandlers()
+
+	server := httptest.NewServer(app.router)
+	t.Cleanup(server.Close)
+	// отключаем редирект
+	server.Client().CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &testApp{server: server, createShortURLUseCase: createShortURLUseCase}
+}
+
+func executeRequest(
+	t *testing.T,
+	ts *httptest.Server,
+	method string,
+	path string,
+	requestBody string,
+	contentType string,
+) (int, http.Header, string) {
+	request, err := http.NewRequest(method, ts.URL+path, strings.NewReader(requestBody))
+	require.NoError(t, err)
+
+	request.Header.Add("Content-Type", contentType)
+
+	response, err := ts.Client().Do(request)
+	require.NoError(t, err)
+
+	responseBody, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	return response.StatusCode, response.Header, string(responseBody)
+}
+
+func TestURLShortenerApp_exportImportHandlers(t *testing.T) {
+	sourceRepo := repository.NewInMemoryShortURLRepository()
+	targetRepo := repository.NewInMemoryShortURLRepository()
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := sourceRepo.SaveShortURLs(testCtx, []entity.ShortURLEntity{
+		{UUID: uuid.NewString(), ShortURI: "abc", LongURL: "https://ya.ru"},
+		{UUID: uuid.NewString(), ShortURI: "cde", LongURL: "https://google.com", Deleted: true},
+	})
+	require.NoError(t, err, "unexpected error when save URL")
+
+	_, trustedSubnet, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	sourceServer := newTestApp(t, sourceRepo, withTrustedSubnet(trustedSubnet)).server
+	targetServer := newTestApp(t, targetRepo, withTrustedSubnet(trustedSubnet)).server
+
+	doRequest := func(method string, requestURL string, body io.Reader) (int, string) {
+		request, err := http.NewRequest(method, requestURL, body)
+		require.NoError(t, err)
+		request.Header.Set("X-Real-IP", "127.0.0.1")
+
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		return response.StatusCode, string(responseBody)
+	}
+
+	statusCode, exported := doRequest(http.MethodGet, sourceServer.URL+"/api/internal/export?format=csv", nil)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	importTests := []struct {
+		name       string
+		query      string
+		body       string
+		statusCode int
+		response   dto.APIInternalImportResponse
+	}{
+		{
+			name:       "unsupported format",
+			query:      "format=xml",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid record",
+			query:      "format=csv",
+			body:       "uuid\n1\n",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "dry run",
+			query:      "format=csv&dry_run=true",
+			body:       exported,
+			statusCode: http.StatusOK,
+			response:   dto.APIInternalImportResponse{Read: 2, Imported: 2, DryRun: true},
+		},
+		{
+			name:       "import",
+			query:      "format=csv",
+			body:       exported,
+			statusCode: http.StatusOK,
+			response:   dto.APIInternalImportResponse{Read: 2, Imported: 2},
+		},
+		{
+			name:       "repeated import",
+			query:      "format=csv&conflict=fail",
+			body:       exported,
+			statusCode: http.StatusOK,
+			response:   dto.APIInternalImportResponse{Read: 2, Existing: 2},
+		},
+	}
+	for _, tt := range importTests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusCode, responseBody := doRequest(http.MethodPost, targetServer.URL+"/api/internal/import?"+tt.query, strings.NewReader(tt.body))
+			require.Equal(t, tt.statusCode, statusCode, responseBody)
+			if statusCode != http.StatusOK {
+				return
+			}
+
+			var response dto.APIInternalImportResponse
+			require.NoError(t, json.Unmarshal([]byte(responseBody), &response))
+			assert.Equal(t, tt.response, response)
+		})
+	}
+
+	deletedShortURL, err := targetRepo.GetShortURLByShortURI(context.Background(), "cde")
+	require.NoError(t, err)
+	assert.True(t, deletedShortURL.Deleted, "deleted flag must be preserved")
+
+	statusCode, _ = doRequest(http.MethodGet, targetServer.URL+"/api/internal/export", nil)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	request, err := http.NewRequest(http.MethodGet, targetServer.URL+"/api/internal/export", nil)
+	require.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode, "export must be available only from trusted subnet")
+}
+
+func TestURLShortenerApp_importShortURLsHandlerAPI(t *testing.T) {
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+	app := newTestApp(t, shortURLRepo, withShortDomains(shortdomain.NewDomains("http://localhost:8080", nil)))
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := shortURLRepo.SaveShortURL(testCtx, &entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: "taken", LongURL: "https://taken.ru"})
+	require.NoError(t, err, "unexpected error when save URL")
+
+	ts := app.server
+
+	testExport := "slashtag,destination,title\n" +
+		"free,https://ya.ru,Yandex\n" +
+		"taken,https://google.com,Google\n"
+
+	tests := []struct {
+		name       string
+		query      string
+		body       string
+		statusCode int
+		response   dto.APIImportShortURLsResponse
+	}{
+		{
+			name:       "unsupported layout",
+			query:      "layout=tinyurl",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid column mapping",
+			query:      "layout=rebrandly&map=clicks=Clicks",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "conflict policy fail",
+			query:      "layout=rebrandly&conflict=fail",
+			body:       testExport,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "dry run",
+			query:      "layout=rebrandly&dry_run=true",
+			body:       testExport,
+			statusCode: http.StatusOK,
+			response: dto.APIImportShortURLsResponse{
+				Read: 2, Imported: 2, KeptAliases: 1, DryRun: true,
+				Conflicts: []dto.APIImportShortURLsConflict{{Record: 2, ShortCode: "taken", Reason: "short code is already taken"}},
+			},
+		},
+		{
+			name:       "import",
+			query:      "layout=rebrandly",
+			body:       testExport,
+			statusCode: http.StatusCreated,
+			response: dto.APIImportShortURLsResponse{
+				Read: 2, Imported: 2, KeptAliases: 1,
+				Conflicts: []dto.APIImportShortURLsConflict{{Record: 2, ShortCode: "taken", Reason: "short code is already taken"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := ts.Client().Post(ts.URL+"/api/user/urls/import?"+tt.query, "text/csv", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			require.Equal(t, tt.statusCode, response.StatusCode)
+			if tt.statusCode != http.StatusOK && tt.statusCode != http.StatusCreated {
+				return
+			}
+
+			var apiResponse dto.APIImportShortURLsResponse
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&apiResponse))
+			if tt.response.DryRun {
+				assert.Equal(t, tt.response, apiResponse)
+				return
+			}
+
+			require.Len(t, apiResponse.Conflicts, 1)
+			assert.True(t, strings.HasPrefix(apiResponse.Conflicts[0].ShortURL, "http://localhost:8080/"), "conflict must contain new short url")
+			apiResponse.Conflicts[0].ShortURL = ""
+			assert.Equal(t, tt.response, apiResponse)
+		})
+	}
+
+	shortURLEntity, err := shortURLRepo.GetShortURLByShortURI(context.Background(), "free")
+	require.NoError(t, err)
+	assert.Equal(t, "https://ya.ru", shortURLEntity.LongURL)
+	assert.Equal(t, "Yandex", shortURLEntity.Title)
+}
+
+func TestURLShortenerApp_getDBStatsHandler(t *testing.T) {
+	dbLookup, err := db.NewDBLookup(db.SQLiteDSNScheme + filepath.Join(t.TempDir(), "urlshortener.db"))
+	require.NoError(t, err)
+	defer dbLookup.Close()
+
+	shortURLRepo := repository.NewInMemoryShortURLRepository()
+	cachedShortURLRepo := repository.NewCachedShortURLRepository(shortURLRepo, 10, time.Minute)
+
+	// один промах и два попадания в кэш
+	for i := 0; i < 3; i++ {
+		_, err := cachedShortURLRepo.GetShortURLByShortURI(context.Background(), "abc")
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	}
+
+	_, trustedSubnet, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		opts           []testAppOption
+		cacheHitCount  int64
+		cacheMissCount int64
+	}{
+		{name: "without cache", opts: []testAppOption{withDBLookup(dbLookup), withTrustedSubnet(trustedSubnet)}},
+		{
+			name:           "with cache",
+			opts:           []testAppOption{withDBLookup(dbLookup), withTrustedSubnet(trustedSubnet), withCacheStats(cachedShortURLRepo)},
+			cacheHitCount:  2,
+			cacheMissCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestApp(t, shortURLRepo, tt.opts...).server
+
+			request, err := http.NewRequest(http.MethodGet, ts.URL+"/api/internal/db/stats", nil)
+			require.NoError(t, err)
+			request.Header.Set("X-Real-IP", "127.0.0.1")
+
+			response, err := ts.Client().Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+			require.Equal(t, http.StatusOK, response.StatusCode)
+
+			var apiResponse dto.APIInternalGetDBStatsResponse
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&apiResponse))
+			assert.Equal(t, tt.cacheHitCount, apiResponse.CacheHitCount)
+			assert.Equal(t, tt.cacheMissCount, apiResponse.CacheMissCount)
+		})
+	}
 }
@@ -7,22 +7,73 @@ package controller
 
 import (
 	"context"
+	"io"
+	"net/netip"
+
 	"github.com/vkhrushchev/urlshortener/internal/app/domain"
+	"github.com/vkhrushchev/urlshortener/internal/app/transfer"
 	"go.uber.org/zap"
 )
 
 var log = zap.Must(zap.NewDevelopment()).Sugar()
 
 type shortURLCreator interface {
-	CreateShortURL(ctx context.Context, url string) (domain.ShortURLDomain, error)
+	CreateShortURL(ctx context.Context, url string, metadata domain.ShortURLMetadataDomain) (domain.ShortURLDomain, error)
 	CreateShortURLBatch(ctx context.Context, createShortURLBatchDomains []domain.CreateShortURLBatchDomain) ([]domain.CreateShortURLBatchResultDomain, error)
 }
 
+type thirdPartyImporter interface {
+	Import(ctx context.Context, r io.Reader, options transfer.ThirdPartyImportOptions) (transfer.ThirdPartyImportResult, error)
+}
+
 type shortURLProvider interface {
 	GetShortURLByShortURI(ctx context.Context, shortURI string) (domain.ShortURLDomain, error)
+	GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (domain.ShortURLDomain, error)
 	GetShortURLsByUserID(ctx context.Context, userID string) ([]domain.ShortURLDomain, error)
+	GetShortURLsByQuery(ctx context.Context, query domain.ShortURLQueryDomain) (domain.ShortURLPageDomain, error)
+	GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]domain.ShortURLDomain, error)
+	ConsumeShortURLClick(ctx context.Context, shortURI string) error
+}
+
+type shortURLUnlocker interface {
+	UnlockShortURL(ctx context.Context, shortURI string, password string, client string) (domain.ShortURLDomain, error)
+}
+
+type splitClickRecorder interface {
+	RecordSplitClick(ctx context.Context, shortURI string, variant string) error
+}
+
+type splitStatsProvider interface {
+	GetSplitStats(ctx context.Context, shortURI string) ([]domain.SplitVariantStatsDomain, error)
+}
+
+type countryClickRecorder interface {
+	RecordCountryClick(ctx context.Context, shortURI string, country string) error
+}
+
+type countryStatsProvider interface {
+	GetCountryStats(ctx context.Context, shortURI string) ([]domain.CountryStatsDomain, error)
+}
+
+type countryResolver interface {
+	Country(addr netip.Addr) string
+}
+
+type fallbackProvider interface {
+	GetFallbackURL(ctx context.Context, shortURLDomain domain.ShortURLDomain, reason string) (string, error)
+}
+
+type userSettingsProvider interface {
+	GetUserSettings(ctx context.Context) (domain.UserSettingsDomain, error)
+	UpdateUserSettings(ctx context.Context, userSettings domain.UserSettingsDomain) (domain.UserSettingsDomain, error)
+}
+
+type shortURLUpdater interface {
+	UpdateShortURL(ctx context.Context, shortURI string, patch domain.ShortURLPatchDomain) (domain.ShortURLDomain, error)
 }
 
 type shortURLDeleter interface {
-	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) error
+	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) (domain.DeleteJobDomain, error)
+	GetDeleteJob(ctx context.Context, jobID string) (domain.DeleteJobDomain, error)
+	RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]domain.RestoreShortURLResultDomain, error)
 }
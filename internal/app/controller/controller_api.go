@@ -6,39 +6,64 @@ import (
 	"errors"
 	"fmt"
 	"github.com/vkhrushchev/urlshortener/internal/app/domain"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/transfer"
 	"github.com/vkhrushchev/urlshortener/internal/app/usecase"
 	"github.com/vkhrushchev/urlshortener/internal/common"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/vkhrushchev/urlshortener/internal/app/dto"
-	"github.com/vkhrushchev/urlshortener/internal/util"
 )
 
 // APIController используется для обработки API-запросов приложения
 type APIController struct {
-	shortURLCreator  shortURLCreator  // Сценарий создания короткой ссылки
-	shortURLProvider shortURLProvider // Сценарий получения короткой ссылки
-	shortURLDeleter  shortURLDeleter  // Сценарий удаления короткой ссылки
-	baseURL          string           // URL до сервера с развернутым приложением
+	shortURLCreator  shortURLCreator      // Сценарий создания короткой ссылки
+	shortURLProvider shortURLProvider     // Сценарий получения короткой ссылки
+	shortURLUpdater  shortURLUpdater      // Сценарий изменения короткой ссылки
+	shortURLDeleter  shortURLDeleter      // Сценарий удаления короткой ссылки
+	splitStats       splitStatsProvider   // Сценарий получения статистики переходов по вариантам перенаправления
+	countryStats     countryStatsProvider // Сценарий получения статистики переходов по странам
+	userSettings     userSettingsProvider // Сценарий получения и изменения настроек пользователя
+	importer         thirdPartyImporter   // Загрузка коротких ссылок из выгрузок сторонних сервисов
+	domains          *shortdomain.Domains // Домены коротких ссылок
 }
 
 // NewAPIController создает новый экземпляр структуры APIController
 //
-//	baseURL - URL до сервера с развернутым приложением
+//	domains - домен по умолчанию и дополнительные домены коротких ссылок
 //	createShortURLUseCase - use case создания короткой ссылки
 //	getShortURLUseCase - use case получения короткой ссылки
+//	shortURLUpdater - use case изменения короткой ссылки
 //	getShortURLUseCase - use case получения короткой ссылки
+//	splitStats - use case получения статистики переходов по вариантам перенаправления A/B-теста
+//	countryStats - use case получения статистики переходов по странам
+//	userSettings - use case получения и изменения настроек пользователя
+//	importer - загрузка коротких ссылок из выгрузок сторонних сервисов
 func NewAPIController(
-	baseURL string,
+	domains *shortdomain.Domains,
 	createShortURLUseCase shortURLCreator,
 	shortURLProvider shortURLProvider,
+	shortURLUpdater shortURLUpdater,
 	shortURLDeleter shortURLDeleter,
+	splitStats splitStatsProvider,
+	countryStats countryStatsProvider,
+	userSettings userSettingsProvider,
+	importer thirdPartyImporter,
 ) *APIController {
 	return &APIController{
-		baseURL:          baseURL,
+		domains:          domains,
 		shortURLCreator:  createShortURLUseCase,
 		shortURLProvider: shortURLProvider,
+		shortURLUpdater:  shortURLUpdater,
 		shortURLDeleter:  shortURLDeleter,
+		splitStats:       splitStats,
+		countryStats:     countryStats,
+		userSettings:     userSettings,
+		importer:         importer,
 	}
 }
 
@@ -83,8 +108,17 @@ func (c *APIController) CreateShortURLHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	longURL := apiRequest.URL
-	shortURLDomain, err := c.shortURLCreator.CreateShortURL(r.Context(), longURL)
-	if err != nil && !errors.Is(err, usecase.ErrConflict) {
+	shortURLDomain, err := c.shortURLCreator.CreateShortURL(r.Context(), longURL, toShortURLMetadataDomain(apiRequest.APIShortURLMetadata))
+	if err != nil && errors.Is(err, usecase.ErrInvalidMetadata) {
+		apiResponse.ErrorStatus = fmt.Sprintf("%d", http.StatusBadRequest)
+		apiResponse.ErrorDescription = fmt.Sprintf("Invalid short URL metadata: %s", err.Error())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(apiResponse)
+
+		return
+	} else if err != nil && !errors.Is(err, usecase.ErrConflict) {
 		apiResponse.ErrorStatus = fmt.Sprintf("%d", http.StatusInternalServerError)
 		apiResponse.ErrorDescription = fmt.Sprintf("Error when saving short URL: %s", err.Error())
 
@@ -95,7 +129,7 @@ func (c *APIController) CreateShortURLHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	apiResponse.Result = util.GetShortURL(c.baseURL, shortURLDomain.ShortURI)
+	apiResponse.Result = c.domains.ShortURL(shortURLDomain.ShortURI)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err != nil && errors.Is(err, usecase.ErrConflict) {
@@ -114,6 +148,7 @@ func (c *APIController) CreateShortURLHandler(w http.ResponseWriter, r *http.Req
 //	@Produce	json
 //	@Success	200	{object}	dto.APICreateShortURLBatchResponse
 //	@Failure	400	{string}	string	"ошибка в формате запроса"
+//	@Failure	409	{string}	string	"исходный URL уже сокращен или повторяется в пачке"
 //	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
 //	@Router		/api/shorten/batch [post]
 //	@Param		body	body	dto.APICreateShortURLBatchRequest	true "запрос на создание коротких ссылок пачкой"
@@ -137,12 +172,19 @@ func (c *APIController) CreateShortURLBatchHandler(w http.ResponseWriter, r *htt
 		shortURLBatchDomain := domain.CreateShortURLBatchDomain{
 			CorrelationUUID: apiShortURLEntry.CorrelationID,
 			LongURL:         apiShortURLEntry.OriginalURL,
+			Metadata:        toShortURLMetadataDomain(apiShortURLEntry.APIShortURLMetadata),
 		}
 		createShortURLBatchDomains = append(createShortURLBatchDomains, shortURLBatchDomain)
 	}
 
 	createShortURLBatchResultDomains, err := c.shortURLCreator.CreateShortURLBatch(r.Context(), createShortURLBatchDomains)
-	if err != nil {
+	if err != nil && errors.Is(err, usecase.ErrInvalidMetadata) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil && errors.Is(err, usecase.ErrConflict) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	} else if err != nil {
 		log.Errorw("app: error when store batch of URLs", "err", err)
 
 		w.WriteHeader(http.StatusInternalServerError)
@@ -153,7 +195,7 @@ func (c *APIController) CreateShortURLBatchHandler(w http.ResponseWriter, r *htt
 	for _, createShortURLBatchResultDomain := range createShortURLBatchResultDomains {
 		apiResponseEntry := dto.APICreateShortURLBatchResponseEntry{
 			CorrelationID: createShortURLBatchResultDomain.CorrelationUUID,
-			ShortURL:      util.GetShortURL(c.baseURL, createShortURLBatchResultDomain.ShortURI),
+			ShortURL:      c.domains.ShortURL(createShortURLBatchResultDomain.ShortURI),
 		}
 		apiResponse = append(apiResponse, apiResponseEntry)
 	}
@@ -163,36 +205,344 @@ func (c *APIController) CreateShortURLBatchHandler(w http.ResponseWriter, r *htt
 	json.NewEncoder(w).Encode(apiResponse)
 }
 
-// GetShortURLByUserID обрабатывает запрос на получение коротких ссылок созданных пользователем
+// ImportShortURLsHandler обрабатывает запрос на загрузку коротких ссылок пользователя из выгрузки CSV стороннего сервиса
+//
+// Прежние идентификаторы коротких ссылок сохраняются, если они свободны, остальные короткие ссылки
+// возвращаются в списке конфликтов.
+//
+//	@Summary	Загрузка коротких ссылок из выгрузки стороннего сервиса
+//	@Accepts	text/csv
+//	@Produce	json
+//	@Param		layout		query		string	false	"раскладка колонок выгрузки: generic (по умолчанию), bitly, rebrandly, yourls"
+//	@Param		map			query		string	false	"переопределение колонок вида original_url=Destination,short_code=Slug; поля: original_url, short_code, title, description, notes, tags"
+//	@Param		domain		query		string	false	"домен создаваемых коротких ссылок"
+//	@Param		dry_run		query		bool	false	"проверить выгрузку без создания коротких ссылок"
+//	@Param		conflict	query		string	false	"политика для занятых идентификаторов: generate (по умолчанию), skip, fail"
+//	@Success	200			{object}	dto.APIImportShortURLsResponse	"результат проверки выгрузки при dry_run"
+//	@Success	201			{object}	dto.APIImportShortURLsResponse
+//	@Failure	400			{string}	string	"ошибка в формате запроса или выгрузки"
+//	@Failure	409			{string}	string	"идентификатор короткой ссылки занят"
+//	@Failure	500			{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/urls/import [post]
+func (c *APIController) ImportShortURLsHandler(w http.ResponseWriter, r *http.Request) {
+	mapping, err := transfer.ParseColumnMapping(r.URL.Query().Get("layout"), r.URL.Query().Get("map"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	options := transfer.ThirdPartyImportOptions{
+		Mapping:     mapping,
+		ShortDomain: r.URL.Query().Get("domain"),
+		Conflict:    r.URL.Query().Get("conflict"),
+	}
+	if dryRun := r.URL.Query().Get("dry_run"); dryRun != "" {
+		if options.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			http.Error(w, "dry_run must be boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := c.importer.Import(r.Context(), r.Body, options)
+	if err != nil {
+		userID := r.Context().Value(common.UserIDContextKey).(string)
+		log.Errorw("app: error when import short urls", "userID", userID, "read", result.Read, "imported", result.Imported, "err", err)
+		switch {
+		case errors.Is(err, transfer.ErrInvalidColumnMapping),
+			errors.Is(err, transfer.ErrUnsupportedConflictPolicy),
+			errors.Is(err, transfer.ErrInvalidRecord):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrInvalidMetadata):
+			http.Error(w, "invalid short url domain", http.StatusBadRequest)
+		case errors.Is(err, transfer.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	apiResponse := dto.APIImportShortURLsResponse{
+		Read:        result.Read,
+		Imported:    result.Imported,
+		KeptAliases: result.KeptAliases,
+		DryRun:      options.DryRun,
+		Conflicts:   make([]dto.APIImportShortURLsConflict, 0, len(result.Conflicts)),
+	}
+	for _, conflict := range result.Conflicts {
+		apiConflict := dto.APIImportShortURLsConflict{
+			Record:    conflict.Record,
+			ShortCode: conflict.ShortCode,
+			Reason:    conflict.Reason,
+		}
+		if conflict.ShortURI != "" {
+			apiConflict.ShortURL = c.domains.ShortURL(conflict.ShortURI)
+		}
+		apiResponse.Conflicts = append(apiResponse.Conflicts, apiConflict)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if options.DryRun {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(apiResponse)
+}
+
+// GetShortURLByUserID обрабатывает запрос на получение страницы коротких ссылок созданных пользователем
+//
+// Общее количество коротких ссылок, удовлетворяющих фильтрам, возвращается в заголовке X-Total-Count,
+// курсор для получения следующей страницы - в заголовке X-Next-Cursor.
 //
 //	@Summary	Получение коротких ссылок созданных пользователем
 //	@Accepts	json
 //	@Produce	json
-//	@Success	200	{object}	dto.APIGetAllURLByUserIDResponse
-//	@Success	204	{string}	string	"у пользователя нет коротких ссылок"
-//	@Failure	400	{string}	string	"ошибка в формате запроса"
-//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Param		limit			query		int		false	"размер страницы, по умолчанию 100, не более 1000"
+//	@Param		cursor			query		string	false	"курсор следующей страницы из заголовка X-Next-Cursor"
+//	@Param		status			query		string	false	"статус коротких ссылок: all (по умолчанию), active, deleted"
+//	@Param		created_from	query		string	false	"минимальная дата создания в формате RFC3339"
+//	@Param		created_to		query		string	false	"максимальная дата создания в формате RFC3339"
+//	@Param		domain			query		string	false	"домен исходного URL, включая поддомены"
+//	@Param		q				query		string	false	"подстрока исходного URL или идентификатора короткой ссылки"
+//	@Param		tag				query		string	false	"тег короткой ссылки"
+//	@Param		sort			query		string	false	"поле сортировки: created_at (по умолчанию), original_url, short_url; для сортировки по убыванию - с префиксом '-'"
+//	@Success	200				{object}	dto.APIGetAllURLByUserIDResponse
+//	@Success	204				{string}	string	"у пользователя нет коротких ссылок"
+//	@Failure	400				{string}	string	"ошибка в формате запроса"
+//	@Failure	500				{string}	string	"внутренняя ошибка сервиса"
 //	@Router		/api/user/urls [get]
 func (c *APIController) GetShortURLByUserID(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value(common.UserIDContextKey).(string)
-	shortURLDomains, err := c.shortURLProvider.GetShortURLsByUserID(r.Context(), userID)
+	query, err := parseShortURLQuery(r, userID)
 	if err != nil {
+		log.Infow("app: error when parse shortURL query", "userID", userID, "err", err)
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	shortURLPageDomain, err := c.shortURLProvider.GetShortURLsByQuery(r.Context(), query)
+	if err != nil && errors.Is(err, usecase.ErrInvalidQuery) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil {
 		log.Errorw("app: error when get shortURL by userID", "userID", userID, "err", err)
 
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("X-Total-Count", strconv.Itoa(shortURLPageDomain.TotalCount))
+	if shortURLPageDomain.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", shortURLPageDomain.NextCursor)
+	}
+
+	if len(shortURLPageDomain.ShortURLs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	apiResponse := make(dto.APIGetAllURLByUserIDResponse, 0, len(shortURLPageDomain.ShortURLs))
+	for _, storageEntry := range shortURLPageDomain.ShortURLs {
+		apiResponse = append(apiResponse, c.toAPIGetAllURLByUserIDResponseEntry(storageEntry))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(apiResponse)
+}
+
+// UpdateShortURL обрабатывает запрос на изменение метаданных короткой ссылки пользователя
+//
+//	@Summary	Изменение метаданных короткой ссылки
+//	@Accepts	json
+//	@Produce	json
+//	@Success	200	{object}	dto.APIGetAllURLByUserIDResponseEntry
+//	@Failure	400	{string}	string	"ошибка в формате запроса"
+//	@Failure	404	{string}	string	"короткая ссылка не найдена"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/urls/{id} [patch]
+//	@Param		id		path	string							true	"идентификатор короткой ссылки"
+//	@Param		body	body	dto.APIUpdateShortURLRequest	true	"изменяемые поля короткой ссылки"
+func (c *APIController) UpdateShortURL(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var apiRequest dto.APIUpdateShortURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&apiRequest); err != nil {
+		log.Errorw("app: error when decode request body from json", "err", err)
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	shortURI := chi.URLParam(r, "id")
+	patch := domain.ShortURLPatchDomain{
+		Title:       apiRequest.Title,
+		Description: apiRequest.Description,
+		Notes:       apiRequest.Notes,
+		Tags:        apiRequest.Tags,
+
+		RedirectStatus:   apiRequest.RedirectStatus,
+		QueryPassthrough: apiRequest.QueryPassthrough,
+		PathPassthrough:  apiRequest.PathPassthrough,
+		UTMParams:        apiRequest.UTMParams,
+		TargetingRules:   apiRequest.TargetingRules,
+		SplitVariants:    apiRequest.SplitVariants,
+		Password:         apiRequest.Password,
+		MaxClicks:        apiRequest.MaxClicks,
+		NotBefore:        apiRequest.NotBefore,
+		NotAfter:         apiRequest.NotAfter,
+		Disabled:         apiRequest.Disabled,
+		FallbackURL:      apiRequest.FallbackURL,
+	}
+	shortURLDomain, err := c.shortURLUpdater.UpdateShortURL(r.Context(), shortURI, patch)
+	if err != nil && errors.Is(err, usecase.ErrInvalidMetadata) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Errorw("app: error when update shortURL", "shortURI", shortURI, "err", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(c.toAPIGetAllURLByUserIDResponseEntry(shortURLDomain))
+}
+
+func (c *APIController) toAPIGetAllURLByUserIDResponseEntry(shortURLDomain domain.ShortURLDomain) dto.APIGetAllURLByUserIDResponseEntry {
+	shortDomain, _ := shortdomain.Split(shortURLDomain.ShortURI)
+	apiResponseEntry := dto.APIGetAllURLByUserIDResponseEntry{
+		ShortURI:    shortURLDomain.ShortURI,
+		ShortURL:    c.domains.ShortURL(shortURLDomain.ShortURI),
+		OriginalURL: shortURLDomain.LongURL,
+		CreatedAt:   shortURLDomain.CreatedAt,
+		Deleted:     shortURLDomain.Deleted,
+
+		PasswordProtected: shortURLDomain.PasswordHash != "",
+		ClickCount:        shortURLDomain.ClickCount,
+		APIShortURLMetadata: dto.APIShortURLMetadata{
+			Title:       shortURLDomain.Title,
+			Description: shortURLDomain.Description,
+			Notes:       shortURLDomain.Notes,
+			Tags:        shortURLDomain.Tags,
+
+			RedirectStatus:   shortURLDomain.RedirectStatus,
+			QueryPassthrough: shortURLDomain.QueryPassthrough,
+			PathPassthrough:  shortURLDomain.PathPassthrough,
+			UTMParams:        shortURLDomain.UTMParams,
+			TargetingRules:   shortURLDomain.TargetingRules,
+			SplitVariants:    shortURLDomain.SplitVariants,
+			MaxClicks:        shortURLDomain.MaxClicks,
+			NotBefore:        shortURLDomain.NotBefore,
+			NotAfter:         shortURLDomain.NotAfter,
+			Disabled:         shortURLDomain.Disabled,
+			FallbackURL:      shortURLDomain.FallbackURL,
+			ShortDomain:      shortDomain,
+		},
+	}
+	if shortURLDomain.PreviewFetchedAt != nil {
+		apiResponseEntry.Preview = &dto.APIShortURLPreview{
+			Title:       shortURLDomain.PreviewTitle,
+			Description: shortURLDomain.PreviewDescription,
+			ImageURL:    shortURLDomain.PreviewImageURL,
+			FetchedAt:   *shortURLDomain.PreviewFetchedAt,
+		}
+	}
+
+	return apiResponseEntry
+}
+
+func toShortURLMetadataDomain(apiShortURLMetadata dto.APIShortURLMetadata) domain.ShortURLMetadataDomain {
+	return domain.ShortURLMetadataDomain(apiShortURLMetadata)
+}
+
+// parseShortURLQuery разбирает параметры запроса на получение страницы коротких ссылок пользователя
+func parseShortURLQuery(r *http.Request, userID string) (domain.ShortURLQueryDomain, error) {
+	values := r.URL.Query()
+	query := domain.ShortURLQueryDomain{
+		UserID: userID,
+		Status: values.Get("status"),
+		Domain: values.Get("domain"),
+		Search: values.Get("q"),
+		Tag:    values.Get("tag"),
+		Sort:   values.Get("sort"),
+		Cursor: values.Get("cursor"),
+	}
+
+	var err error
+	if limit := values.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return domain.ShortURLQueryDomain{}, fmt.Errorf("invalid limit: %w", err)
+		}
+	}
+
+	if query.CreatedFrom, err = parseTimeQueryParam(values, "created_from"); err != nil {
+		return domain.ShortURLQueryDomain{}, err
+	}
+	if query.CreatedTo, err = parseTimeQueryParam(values, "created_to"); err != nil {
+		return domain.ShortURLQueryDomain{}, err
+	}
+
+	return query, nil
+}
+
+// parseTimeQueryParam разбирает необязательный параметр запроса name в формате RFC3339
+func parseTimeQueryParam(values url.Values, name string) (*time.Time, error) {
+	value := values.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsedValue, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return &parsedValue, nil
+}
+
+// GetDeletedShortURLsByUserID обрабатывает запрос на получение удаленных коротких ссылок пользователя (корзины)
+//
+//	@Summary	Получение удаленных коротких ссылок пользователя
+//	@Accepts	plain
+//	@Produce	json
+//	@Success	200	{object}	dto.APIGetTrashResponse
+//	@Success	204	{string}	string	"у пользователя нет удаленных коротких ссылок"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/urls/trash [get]
+func (c *APIController) GetDeletedShortURLsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(common.UserIDContextKey).(string)
+	shortURLDomains, err := c.shortURLProvider.GetDeletedShortURLsByUserID(r.Context(), userID)
+	if err != nil {
+		log.Errorw("app: error when get deleted shortURL by userID", "userID", userID, "err", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	if len(shortURLDomains) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	apiResponse := make(dto.APIGetAllURLByUserIDResponse, 0, len(shortURLDomains))
-	for _, storageEntry := range shortURLDomains {
-		apiResponseEntry := dto.APIGetAllURLByUserIDResponseEntry{
-			ShortURL:    util.GetShortURL(c.baseURL, storageEntry.ShortURI),
-			OriginalURL: storageEntry.LongURL,
+	apiResponse := make(dto.APIGetTrashResponse, 0, len(shortURLDomains))
+	for _, shortURLDomain := range shortURLDomains {
+		apiResponseEntry := dto.APIGetTrashResponseEntry{
+			ShortURI:    shortURLDomain.ShortURI,
+			ShortURL:    c.domains.ShortURL(shortURLDomain.ShortURI),
+			OriginalURL: shortURLDomain.LongURL,
+			DeletedAt:   shortURLDomain.DeletedAt,
 		}
 		apiResponse = append(apiResponse, apiResponseEntry)
 	}
@@ -202,16 +552,62 @@ func (c *APIController) GetShortURLByUserID(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(apiResponse)
 }
 
-// DeleteShortURLs обрабатывает запрос на удаление коротких ссылок
+// RestoreShortURLs обрабатывает запрос на восстановление удаленных коротких ссылок
 //
-//	@Summary	Удаление коротких ссылок
+//	@Summary	Восстановление удаленных коротких ссылок
 //	@Accepts	json
 //	@Produce	json
-//	@Success	200	{string}	string
+//	@Success	200	{object}	dto.APIRestoreShortURLsResponse
 //	@Failure	400	{string}	string	"ошибка в формате запроса"
 //	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/urls/restore [post]
+//	@Param		body	body	[]string	true	"список идентификаторов коротких ссылок"
+func (c *APIController) RestoreShortURLs(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var apiRequest []string
+	if err := json.NewDecoder(r.Body).Decode(&apiRequest); err != nil {
+		log.Errorw("app: error when decode request body from json", "err", err)
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	restoreShortURLResultDomains, err := c.shortURLDeleter.RestoreShortURLsByShortURIs(r.Context(), apiRequest)
+	if err != nil {
+		log.Errorw("app: error when restore by shortURIs", "err", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	apiResponse := make(dto.APIRestoreShortURLsResponse, 0, len(restoreShortURLResultDomains))
+	for _, restoreShortURLResultDomain := range restoreShortURLResultDomains {
+		apiResponse = append(apiResponse, dto.APIRestoreShortURLsResponseEntry{
+			ShortURI: restoreShortURLResultDomain.ShortURI,
+			Status:   restoreShortURLResultDomain.Status,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(apiResponse)
+}
+
+// DeleteShortURLs обрабатывает запрос на удаление коротких ссылок
+//
+//	@Summary	Удаление коротких ссылок
+//	@Accepts	json
+//	@Produce	json
+//	@Success	202	{object}	dto.APIDeleteShortURLsResponse	"задача на удаление принята"
+//	@Failure	400	{string}	string							"ошибка в формате запроса"
+//	@Failure	500	{string}	string							"внутренняя ошибка сервиса"
 //	@Router		/api/user/urls [delete]
-//	@Param		body	body	[]string	true "список идентификаторов коротких ссылок"
+//	@Param		body	body	[]string	true	"список идентификаторов коротких ссылок"
 func (c *APIController) DeleteShortURLs(w http.ResponseWriter, r *http.Request) {
 	contentType := r.Header.Get("Content-Type")
 	if contentType != "application/json" {
@@ -227,7 +623,7 @@ func (c *APIController) DeleteShortURLs(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := c.shortURLDeleter.DeleteShortURLsByShortURIs(context.WithoutCancel(r.Context()), apiRequest)
+	deleteJobDomain, err := c.shortURLDeleter.DeleteShortURLsByShortURIs(context.WithoutCancel(r.Context()), apiRequest)
 	if err != nil {
 		log.Errorw("app: error when delete by shortURIs", "err", err)
 
@@ -235,5 +631,195 @@ func (c *APIController) DeleteShortURLs(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	apiResponse := dto.APIDeleteShortURLsResponse{
+		JobID: deleteJobDomain.ID,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/user/jobs/"+deleteJobDomain.ID)
 	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(apiResponse)
+}
+
+// GetDeleteJob обрабатывает запрос на получение статуса задачи на удаление коротких ссылок
+//
+//	@Summary	Получение статуса задачи на удаление коротких ссылок
+//	@Accepts	plain
+//	@Produce	json
+//	@Success	200	{object}	dto.APIGetDeleteJobResponse
+//	@Failure	404	{string}	string	"задача не найдена"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/jobs/{jobID} [get]
+//	@Param		jobID	path	string	true	"идентификатор задачи на удаление"
+func (c *APIController) GetDeleteJob(w http.ResponseWriter, r *http.Request) {
+	jobID := chi.URLParam(r, "id")
+
+	deleteJobDomain, err := c.shortURLDeleter.GetDeleteJob(r.Context(), jobID)
+	if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Errorw("app: error when get delete job", "jobID", jobID, "err", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	apiResponse := dto.APIGetDeleteJobResponse{
+		JobID:     deleteJobDomain.ID,
+		Status:    deleteJobDomain.Status,
+		Results:   make([]dto.APIGetDeleteJobResponseEntry, 0, len(deleteJobDomain.Results)),
+		CreatedAt: deleteJobDomain.CreatedAt,
+		UpdatedAt: deleteJobDomain.UpdatedAt,
+	}
+	for _, deleteShortURLResultDomain := range deleteJobDomain.Results {
+		apiResponse.Results = append(apiResponse.Results, dto.APIGetDeleteJobResponseEntry{
+			ShortURI: deleteShortURLResultDomain.ShortURI,
+			Status:   deleteShortURLResultDomain.Status,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(apiResponse)
+}
+
+// GetSplitStats обрабатывает запрос на получение количества переходов по вариантам перенаправления A/B-теста
+// короткой ссылки пользователя
+//
+//	@Summary	Получение статистики переходов по вариантам перенаправления короткой ссылки
+//	@Accepts	plain
+//	@Produce	json
+//	@Success	200	{object}	dto.APIGetSplitStatsResponse
+//	@Failure	404	{string}	string	"короткая ссылка не найдена"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/urls/{id}/split [get]
+//	@Param		id	path	string	true	"идентификатор короткой ссылки"
+func (c *APIController) GetSplitStats(w http.ResponseWriter, r *http.Request) {
+	shortURI := chi.URLParam(r, "id")
+
+	splitStats, err := c.splitStats.GetSplitStats(r.Context(), shortURI)
+	if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Errorw("app: error when get split stats", "shortURI", shortURI, "err", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	apiResponse := make(dto.APIGetSplitStatsResponse, 0, len(splitStats))
+	for _, variantStats := range splitStats {
+		apiResponse = append(apiResponse, dto.APIGetSplitStatsResponseEntry{
+			Name:   variantStats.Name,
+			URL:    variantStats.URL,
+			Weight: variantStats.Weight,
+			Clicks: variantStats.Clicks,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(apiResponse)
+}
+
+// GetCountryStats обрабатывает запрос на получение количества переходов по короткой ссылке пользователя по странам
+//
+//	@Summary	Получение статистики переходов по короткой ссылке по странам
+//	@Accepts	plain
+//	@Produce	json
+//	@Success	200	{object}	dto.APIGetCountryStatsResponse
+//	@Failure	404	{string}	string	"короткая ссылка не найдена"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/urls/{id}/countries [get]
+//	@Param		id	path	string	true	"идентификатор короткой ссылки"
+func (c *APIController) GetCountryStats(w http.ResponseWriter, r *http.Request) {
+	shortURI := chi.URLParam(r, "id")
+
+	countryStats, err := c.countryStats.GetCountryStats(r.Context(), shortURI)
+	if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Errorw("app: error when get country stats", "shortURI", shortURI, "err", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	apiResponse := make(dto.APIGetCountryStatsResponse, 0, len(countryStats))
+	for _, stats := range countryStats {
+		apiResponse = append(apiResponse, dto.APIGetCountryStatsResponseEntry{
+			Country: stats.Country,
+			Clicks:  stats.Clicks,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(apiResponse)
+}
+
+// GetUserSettings обрабатывает запрос на получение настроек пользователя
+//
+//	@Summary	Получение настроек пользователя
+//	@Accepts	plain
+//	@Produce	json
+//	@Success	200	{object}	dto.APIUserSettings
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/settings [get]
+func (c *APIController) GetUserSettings(w http.ResponseWriter, r *http.Request) {
+	userSettings, err := c.userSettings.GetUserSettings(r.Context())
+	if err != nil {
+		log.Errorw("app: error when get user settings", "err", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.APIUserSettings{FallbackURL: userSettings.FallbackURL})
+}
+
+// UpdateUserSettings обрабатывает запрос на изменение настроек пользователя
+//
+//	@Summary	Изменение настроек пользователя
+//	@Accepts	json
+//	@Produce	json
+//	@Success	200	{object}	dto.APIUserSettings
+//	@Failure	400	{string}	string	"ошибка в формате запроса"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/user/settings [put]
+//	@Param		body	body	dto.APIUserSettings	true	"настройки пользователя"
+func (c *APIController) UpdateUserSettings(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var apiRequest dto.APIUserSettings
+	if err := json.NewDecoder(r.Body).Decode(&apiRequest); err != nil {
+		log.Errorw("app: error when decode request body from json", "err", err)
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	userSettings, err := c.userSettings.UpdateUserSettings(r.Context(), domain.UserSettingsDomain{FallbackURL: apiRequest.FallbackURL})
+	if err != nil && errors.Is(err, usecase.ErrInvalidSettings) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil {
+		log.Errorw("app: error when update user settings", "err", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.APIUserSettings{FallbackURL: userSettings.FallbackURL})
 }
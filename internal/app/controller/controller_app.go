@@ -2,39 +2,83 @@ package controller
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"html/template"
 	"io"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/vkhrushchev/urlshortener/internal/app/domain"
+	"github.com/vkhrushchev/urlshortener/internal/app/fallback"
+	"github.com/vkhrushchev/urlshortener/internal/app/qr"
+	"github.com/vkhrushchev/urlshortener/internal/app/redirect"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
 	"github.com/vkhrushchev/urlshortener/internal/app/usecase"
+	"github.com/vkhrushchev/urlshortener/internal/common"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/vkhrushchev/urlshortener/internal/util"
 )
 
 // AppController используется для обработки не API-запросов приложения
 type AppController struct {
-	shortURLCreator  shortURLCreator  // Сценарий создания короткой ссылки
-	shortURLProvider shortURLProvider // Сценарий получения короткой ссылки
-	baseURL          string           // URL до сервера с развернутым приложением
+	shortURLCreator  shortURLCreator      // Сценарий создания короткой ссылки
+	shortURLProvider shortURLProvider     // Сценарий получения короткой ссылки
+	shortURLUnlocker shortURLUnlocker     // Сценарий перехода по короткой ссылке, защищенной паролем
+	domains          *shortdomain.Domains // Домены коротких ссылок
+	redirectStatus   int                  // Код ответа перенаправления для коротких ссылок без собственного кода
+	inactiveURL      string               // Адрес перенаправления вне периода действия короткой ссылки, "" - не перенаправлять
+	splitRecorder    splitClickRecorder   // Учет переходов по вариантам перенаправления, nil - переходы не учитываются
+	countryResolver  countryResolver      // Определение страны клиента по IP-адресу, nil - страна не определяется
+	countryRecorder  countryClickRecorder // Учет переходов по странам клиентов, nil - переходы не учитываются
+	fallbackProvider fallbackProvider     // Выбор резервного адреса для недоступной короткой ссылки, nil - адрес не выбирается
 }
 
 // NewAppController создает новый экземпляр структуры AppController
 //
-//	baseURL - URL до сервера с развернутым приложением
+//	domains - домен по умолчанию и дополнительные домены коротких ссылок
+//	redirectStatus - код ответа перенаправления для коротких ссылок без собственного кода
+//	inactiveURL - адрес перенаправления при переходе по короткой ссылке вне периода ее действия, "" - до начала периода
+//	возвращается страница "ссылка еще не активна", после окончания - 410
 //	createShortURLUseCase - use case создания короткой ссылки
 //	getShortURLUseCase - use case получения короткой ссылки
+//	shortURLUnlocker - use case перехода по короткой ссылке, защищенной паролем
+//	splitRecorder - use case учета переходов по вариантам перенаправления A/B-теста, nil - переходы не учитываются
+//	countryResolver - определение страны клиента по IP-адресу, nil - правила таргетинга по стране не применяются
+//	countryRecorder - use case учета переходов по странам клиентов, nil или countryResolver равный nil - переходы
+//	не учитываются
+//	fallbackProvider - use case выбора резервного адреса перенаправления для удаленной, истекшей, исчерпавшей лимит
+//	переходов или заблокированной короткой ссылки, nil - для таких ссылок возвращается 410
 func NewAppController(
-	baseURL string,
+	domains *shortdomain.Domains,
+	redirectStatus int,
+	inactiveURL string,
 	shortURLCreator shortURLCreator,
 	shortURLProvider shortURLProvider,
+	shortURLUnlocker shortURLUnlocker,
+	splitRecorder splitClickRecorder,
+	countryResolver countryResolver,
+	countryRecorder countryClickRecorder,
+	fallbackProvider fallbackProvider,
 ) *AppController {
 	return &AppController{
-		baseURL:          baseURL,
+		domains:          domains,
+		redirectStatus:   redirectStatus,
+		inactiveURL:      inactiveURL,
 		shortURLCreator:  shortURLCreator,
 		shortURLProvider: shortURLProvider,
+		shortURLUnlocker: shortURLUnlocker,
+		splitRecorder:    splitRecorder,
+		countryResolver:  countryResolver,
+		countryRecorder:  countryRecorder,
+		fallbackProvider: fallbackProvider,
 	}
 }
 
@@ -67,7 +111,8 @@ func (c *AppController) CreateShortURLHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	longURL := strings.TrimSpace(bodyBuffer.String())
-	shortURLDomain, err := c.shortURLCreator.CreateShortURL(r.Context(), longURL)
+	metadata := domain.ShortURLMetadataDomain{ShortDomain: c.domains.HostDomain(r.Host)}
+	shortURLDomain, err := c.shortURLCreator.CreateShortURL(r.Context(), longURL, metadata)
 	if err != nil && !errors.Is(err, usecase.ErrConflict) {
 		w.WriteHeader(http.StatusInternalServerError)
 		log.Errorw(err.Error())
@@ -86,7 +131,7 @@ func (c *AppController) CreateShortURLHandler(w http.ResponseWriter, r *http.Req
 		w.WriteHeader(http.StatusCreated)
 	}
 
-	shortURL := util.GetShortURL(c.baseURL, shortURLDomain.ShortURI)
+	shortURL := c.domains.ShortURL(shortURLDomain.ShortURI)
 
 	_, err = w.Write([]byte(shortURL))
 	if err != nil {
@@ -97,19 +142,45 @@ func (c *AppController) CreateShortURLHandler(w http.ResponseWriter, r *http.Req
 
 // GetURLHandler возвращает полную ссылку по короткой ссылке
 //
+// Полная ссылка выбирается по правилам таргетинга короткой ссылки в зависимости от заголовков User-Agent
+// и Accept-Language и страны, определяемой по IP-адресу клиента. Если ни одно правило не подошло, а у короткой ссылки
+// заданы варианты перенаправления A/B-теста, полная ссылка выбирается среди вариантов пропорционально весам,
+// выбранный вариант закрепляется за посетителем в cookie. Иначе используется исходный URL. В зависимости от параметров
+// перенаправления короткой ссылки в полную ссылку переносятся параметры запроса и путь после идентификатора
+// короткой ссылки, добавляются UTM-параметры. Для короткой ссылки, защищенной паролем, вместо перенаправления
+// возвращается форма ввода пароля. Переходы по короткой ссылке с ограничением количества переходов учитываются,
+// после исчерпания переходов короткая ссылка считается удаленной. До начала периода действия короткой ссылки
+// выполняется перенаправление на адрес из конфигурации, а если он не задан, возвращается страница
+// "ссылка еще не активна". Для удаленной, истекшей, исчерпавшей лимит переходов или заблокированной короткой ссылки
+// выполняется перенаправление на резервный адрес с причиной в параметре reason, а если он не задан, возвращается 410.
+//
 //	@Summary	получить короткую ссылку
 //	@Accepts	plain
 //	@Produce	plain
-//	@Success	307	{string}	string
-//	@Failure	404	{string}	string	"короткая ссылка не найдена"
-//	@Failure	410	{string}	string	"короткая ссылка удалена"
+//	@Produce	html
+//	@Success	200	{string}	string	"форма ввода пароля короткой ссылки"
+//	@Success	301	{string}	string	"постоянное перенаправление"
+//	@Success	302	{string}	string	"временное перенаправление или перенаправление на резервный адрес"
+//	@Success	307	{string}	string	"временное перенаправление"
+//	@Success	308	{string}	string	"постоянное перенаправление"
+//	@Failure	403	{string}	string	"период действия короткой ссылки еще не начался"
+//	@Failure	404	{string}	string	"короткая ссылка не найдена или не переносит путь"
+//	@Failure	410	{string}	string	"короткая ссылка удалена, заблокирована, переходы по ней исчерпаны или период действия закончился"
 //	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
 //	@Router		/{shortURI} [get]
+//	@Router		/{shortURI}/{path} [get]
 //	@Param		shortURI	path	string	true	"идентификатор короткой ссылки"
+//	@Param		path		path	string	false	"путь, переносимый в полную ссылку"
 func (c *AppController) GetURLHandler(w http.ResponseWriter, r *http.Request) {
 	shortURI := chi.URLParam(r, "id")
+	key, ok := c.domains.KeyByHost(r.Host, shortURI)
+	if !ok {
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
 
-	shortURLEntry, err := c.shortURLProvider.GetShortURLByShortURI(r.Context(), shortURI)
+	shortURLEntry, err := c.shortURLProvider.GetRedirectShortURLByShortURI(r.Context(), key)
 	if err != nil && !errors.Is(err, usecase.ErrNotFound) {
 		log.Errorw("app: error when get original url from storage", "err", err)
 
@@ -124,13 +195,515 @@ func (c *AppController) GetURLHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if shortURLEntry.Deleted {
+	if reason := unavailableReason(shortURLEntry); reason != "" {
+		c.writeUnavailable(w, r, shortURLEntry, reason)
+		return
+	}
+
+	if err := usecase.CheckActivityWindow(shortURLEntry, time.Now()); err != nil {
+		c.writeInactive(w, r, shortURLEntry, err)
+		return
+	}
+
+	if shortURLEntry.PasswordHash != "" {
+		writePasswordForm(w, http.StatusOK, "")
+		return
+	}
+
+	c.redirect(w, r, shortURI, shortURLEntry)
+}
+
+// passwordFormMaxSize - максимальный размер тела запроса с формой ввода пароля
+const passwordFormMaxSize = 4 << 10
+
+// UnlockURLHandler обрабатывает отправку формы ввода пароля короткой ссылки и при верном пароле
+// перенаправляет на полную ссылку так же, как GetURLHandler
+//
+// Перенаправление выполняется с кодом 303, чтобы браузер перешел по полной ссылке методом GET
+// и не передал в нее форму с паролем. Неудачные попытки ввода пароля ограничиваются.
+//
+//	@Summary	ввести пароль короткой ссылки
+//	@Accepts	x-www-form-urlencoded
+//	@Produce	html
+//	@Success	302	{string}	string	"перенаправление на резервный адрес недоступной короткой ссылки"
+//	@Success	303	{string}	string	"перенаправление на полную ссылку"
+//	@Failure	400	{string}	string	"ошибка в формате запроса"
+//	@Failure	403	{string}	string	"неверный пароль или период действия короткой ссылки еще не начался"
+//	@Failure	404	{string}	string	"короткая ссылка не найдена или не переносит путь"
+//	@Failure	410	{string}	string	"короткая ссылка удалена, заблокирована, переходы по ней исчерпаны или период действия закончился"
+//	@Failure	429	{string}	string	"превышено количество попыток ввода пароля"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/{shortURI} [post]
+//	@Router		/{shortURI}/{path} [post]
+//	@Param		shortURI	path		string	true	"идентификатор короткой ссылки"
+//	@Param		path		path		string	false	"путь, переносимый в полную ссылку"
+//	@Param		password	formData	string	true	"пароль короткой ссылки"
+func (c *AppController) UnlockURLHandler(w http.ResponseWriter, r *http.Request) {
+	shortURI := chi.URLParam(r, "id")
+
+	r.Body = http.MaxBytesReader(w, r.Body, passwordFormMaxSize)
+	if err := r.ParseForm(); err != nil {
+		log.Infow("app: error when parse password form", "shortURI", shortURI, "err", err)
+
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	key, ok := c.domains.KeyByHost(r.Host, shortURI)
+	if !ok {
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	shortURLEntry, err := c.shortURLUnlocker.UnlockShortURL(r.Context(), key, r.PostForm.Get("password"), clientAddr(r).String())
+	if err != nil && errors.Is(err, usecase.ErrNotFound) {
 		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil && errors.Is(err, usecase.ErrPasswordRequired) {
+		writePasswordForm(w, http.StatusForbidden, "Введите пароль")
+		return
+	} else if err != nil && errors.Is(err, usecase.ErrInvalidPassword) {
+		writePasswordForm(w, http.StatusForbidden, "Неверный пароль")
+		return
+	} else if err != nil && errors.Is(err, usecase.ErrTooManyAttempts) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(usecase.PasswordAttemptsWindow.Seconds())))
+		writePasswordForm(w, http.StatusTooManyRequests, "Слишком много попыток, повторите позже")
+		return
+	} else if err != nil {
+		log.Errorw("app: error when unlock short url", "shortURI", shortURI, "err", err)
+
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if reason := unavailableReason(shortURLEntry); reason != "" {
+		c.writeUnavailable(w, r, shortURLEntry, reason)
+		return
+	}
+
+	if err := usecase.CheckActivityWindow(shortURLEntry, time.Now()); err != nil {
+		c.writeInactive(w, r, shortURLEntry, err)
+		return
+	}
+
+	c.redirect(w, r, shortURI, shortURLEntry)
+}
+
+// unavailableReason возвращает причину недоступности короткой ссылки shortURLEntry: удаление, блокировку
+// или исчерпание переходов, "" - короткая ссылка доступна
+func unavailableReason(shortURLEntry domain.ShortURLDomain) string {
+	switch {
+	case shortURLEntry.Deleted:
+		return fallback.ReasonDeleted
+	case shortURLEntry.Disabled:
+		return fallback.ReasonDisabled
+	case clicksExhausted(shortURLEntry):
+		return fallback.ReasonExhausted
+	default:
+		return ""
+	}
+}
+
+// clicksExhausted проверяет, что переходы по короткой ссылке с ограничением количества переходов исчерпаны
+func clicksExhausted(shortURLEntry domain.ShortURLDomain) bool {
+	return shortURLEntry.MaxClicks > 0 && shortURLEntry.ClickCount >= int64(shortURLEntry.MaxClicks)
+}
+
+// writeUnavailable отвечает на переход по недоступной по причине reason короткой ссылке
+//
+// Выполняется временное перенаправление на резервный адрес короткой ссылки, ее владельца или из конфигурации
+// с причиной в параметре запроса fallback.ReasonParam. Если резервный адрес не задан, для истекшей короткой ссылки
+// выполняется перенаправление на inactiveURL, в остальных случаях возвращается 410.
+func (c *AppController) writeUnavailable(w http.ResponseWriter, r *http.Request, shortURLEntry domain.ShortURLDomain, reason string) {
+	// причина недоступности может быть снята владельцем, поэтому ответ не кэшируется
+	w.Header().Set("Cache-Control", "no-store")
+
+	location := ""
+	if c.fallbackProvider != nil {
+		// при ошибке выбора резервного адреса отвечаем так же, как без него, ошибка уже залогирована
+		if fallbackURL, err := c.fallbackProvider.GetFallbackURL(r.Context(), shortURLEntry, reason); err == nil {
+			location = fallbackURL
+		}
+	}
+	if location == "" && reason == fallback.ReasonExpired {
+		location = c.inactiveURL
+	}
+
+	w.Header().Add("Content-Type", "plain/text")
+	if location == "" {
 		w.WriteHeader(http.StatusGone)
 		return
 	}
 
+	w.Header().Add("Location", location)
+	w.WriteHeader(http.StatusFound)
+}
+
+var passwordFormTemplate = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Ссылка защищена паролем</title>
+</head>
+<body>
+<form method="post">
+<p>Для перехода по ссылке введите пароль</p>
+{{if .}}<p role="alert">{{.}}</p>{{end}}
+<input type="password" name="password" autocomplete="current-password" required autofocus>
+<button type="submit">Перейти</button>
+</form>
+</body>
+</html>
+`))
+
+// writePasswordForm возвращает форму ввода пароля короткой ссылки с сообщением message
+func writePasswordForm(w http.ResponseWriter, statusCode int, message string) {
+	writePage(w, statusCode, passwordFormTemplate, message)
+}
+
+var notYetActiveTemplate = template.Must(template.New("not_yet_active").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Ссылка еще не активна</title>
+</head>
+<body>
+<p>Ссылка еще не активна, переход по ней станет доступен {{.}}</p>
+</body>
+</html>
+`))
+
+// writeInactive отвечает на переход по короткой ссылке вне периода ее действия, err - результат
+// usecase.CheckActivityWindow
+//
+// После окончания периода действия короткая ссылка считается недоступной, ответ формируется writeUnavailable.
+// До начала периода действия выполняется временное перенаправление на адрес inactiveURL, а если он не задан,
+// возвращается страница "ссылка еще не активна".
+func (c *AppController) writeInactive(w http.ResponseWriter, r *http.Request, shortURLEntry domain.ShortURLDomain, err error) {
+	if errors.Is(err, usecase.ErrExpired) {
+		c.writeUnavailable(w, r, shortURLEntry, fallback.ReasonExpired)
+		return
+	}
+
+	// период действия может быть изменен владельцем, поэтому ответ не кэшируется
+	w.Header().Set("Cache-Control", "no-store")
+
+	if c.inactiveURL != "" {
+		w.Header().Add("Content-Type", "plain/text")
+		w.Header().Add("Location", c.inactiveURL)
+		w.WriteHeader(http.StatusFound)
+		return
+	}
+
+	writePage(w, http.StatusForbidden, notYetActiveTemplate, shortURLEntry.NotBefore.UTC().Format("02.01.2006 15:04 MST"))
+}
+
+// writePage возвращает HTML-страницу по шаблону tmpl с данными data
+func writePage(w http.ResponseWriter, statusCode int, tmpl *template.Template, data any) {
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		log.Errorw("app: error when render page", "template", tmpl.Name(), "err", err)
+
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(buffer.Bytes()); err != nil {
+		log.Errorw("app: error writing response", "err", err)
+	}
+}
+
+// redirect перенаправляет на полную ссылку короткой ссылки shortURLEntry, shortURI - идентификатор короткой ссылки
+// в пути запроса
+func (c *AppController) redirect(w http.ResponseWriter, r *http.Request, shortURI string, shortURLEntry domain.ShortURLDomain) {
+	target := strings.TrimSpace(shortURLEntry.LongURL)
+	targeted := false
+
+	// страна определяется один раз для правил таргетинга и для учета перехода
+	country := ""
+	recordCountry := c.countryResolver != nil && c.countryRecorder != nil
+	if recordCountry || (c.countryResolver != nil && targeting.HasCountryRules(shortURLEntry.TargetingRules)) {
+		country = c.countryResolver.Country(clientAddr(r))
+	}
+
+	if len(shortURLEntry.TargetingRules) > 0 {
+		w.Header().Set("Vary", "User-Agent, Accept-Language")
+
+		client := targeting.ParseClient(r.UserAgent(), r.Header.Get("Accept-Language"))
+		client.Country = country
+		if ruleURL, ok := targeting.Match(shortURLEntry.TargetingRules, client); ok {
+			target = ruleURL
+			targeted = true
+		}
+
+		log.Infow(
+			"app: redirect by targeting rules",
+			"shortURI", shortURI,
+			"platform", client.Platform,
+			"device", client.Device,
+			"locale", client.Locale,
+			"country", client.Country,
+		)
+	}
+
+	splitVariant := ""
+	if !targeted && len(shortURLEntry.SplitVariants) > 0 {
+		w.Header().Add("Vary", "Cookie")
+
+		assigned := ""
+		if cookie, err := r.Cookie(split.CookieName); err == nil {
+			assigned = cookie.Value
+		}
+
+		if variant, ok := split.Choose(shortURLEntry.SplitVariants, assigned); ok {
+			target = variant.URL
+			splitVariant = variant.Name
+		}
+		if splitVariant != "" && splitVariant != assigned {
+			http.SetCookie(w, &http.Cookie{
+				Name:     split.CookieName,
+				Value:    splitVariant,
+				Path:     "/" + shortURI,
+				MaxAge:   split.CookieMaxAge,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+	}
+
+	redirectPath := strings.TrimPrefix(strings.TrimPrefix(r.URL.EscapedPath(), "/"+shortURI), "/")
+	redirectOptions := redirect.Options{
+		QueryPassthrough: shortURLEntry.QueryPassthrough,
+		PathPassthrough:  shortURLEntry.PathPassthrough,
+		UTMParams:        shortURLEntry.UTMParams,
+	}
+	location, err := redirect.BuildURL(target, redirectOptions, redirectPath, r.URL.Query())
+	if err != nil && errors.Is(err, redirect.ErrInvalidPath) {
+		log.Infow("app: path can not be passed to original url", "shortURI", shortURI, "err", err)
+
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Errorw("app: error when build redirect url", "shortURI", shortURI, "err", err)
+
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if splitVariant != "" && c.splitRecorder != nil {
+		// ошибка учета перехода не должна мешать перенаправлению, она уже залогирована
+		_ = c.splitRecorder.RecordSplitClick(r.Context(), shortURLEntry.ShortURI, splitVariant)
+	}
+
+	redirectStatus := shortURLEntry.RedirectStatus
+	if redirectStatus == 0 {
+		redirectStatus = c.redirectStatus
+	}
+	if r.Method == http.MethodPost {
+		redirectStatus = http.StatusSeeOther
+	}
+
+	// переход учитывается последним, чтобы ошибки построения полной ссылки не расходовали переходы;
+	// при одновременных переходах по последнему доступному переходу перенаправляет только один запрос
+	if shortURLEntry.MaxClicks > 0 {
+		err := c.shortURLProvider.ConsumeShortURLClick(r.Context(), shortURLEntry.ShortURI)
+		if err != nil && errors.Is(err, usecase.ErrClicksExhausted) {
+			c.writeUnavailable(w, r, shortURLEntry, fallback.ReasonExhausted)
+			return
+		} else if err != nil && errors.Is(err, usecase.ErrNotFound) {
+			c.writeUnavailable(w, r, shortURLEntry, fallback.ReasonDeleted)
+			return
+		} else if err != nil {
+			log.Errorw("app: error when consume short url click", "shortURI", shortURI, "err", err)
+
+			w.Header().Add("Content-Type", "plain/text")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if recordCountry {
+		// ошибка учета перехода не должна мешать перенаправлению, она уже залогирована
+		_ = c.countryRecorder.RecordCountryClick(r.Context(), shortURLEntry.ShortURI, country)
+	}
+
+	// постоянное перенаправление кэшируется браузером, временное должно доходить до сервиса при каждом переходе;
+	// результат правил таргетинга зависит от IP-адреса клиента, поэтому не кэшируется общими кэшами;
+	// переходы по вариантам A/B-теста и по ссылкам с ограничением количества переходов учитываются сервисом,
+	// поэтому не кэшируются
+	permanent := redirectStatus == http.StatusMovedPermanently || redirectStatus == http.StatusPermanentRedirect
+	if len(shortURLEntry.SplitVariants) > 0 || shortURLEntry.MaxClicks > 0 {
+		w.Header().Set("Cache-Control", "no-store")
+	} else if permanent && len(shortURLEntry.TargetingRules) > 0 {
+		w.Header().Set("Cache-Control", "private, max-age=86400")
+	} else if permanent {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+
 	w.Header().Add("Content-Type", "plain/text")
-	w.Header().Add("Location", strings.TrimSpace(shortURLEntry.LongURL))
-	w.WriteHeader(http.StatusTemporaryRedirect)
+	w.Header().Add("Location", location)
+	w.WriteHeader(redirectStatus)
+}
+
+// qrCodeMaxAge - время кеширования изображения QR-кода клиентами в секундах
+const qrCodeMaxAge = 24 * 60 * 60
+
+// GetQRCodeHandler возвращает изображение QR-кода с полной короткой ссылкой
+//
+//	@Summary	получить QR-код короткой ссылки
+//	@Produce	png
+//	@Produce	image/svg+xml
+//	@Success	200	{file}		binary
+//	@Success	304	{string}	string	"изображение не изменилось"
+//	@Failure	400	{string}	string	"некорректные параметры изображения"
+//	@Failure	404	{string}	string	"короткая ссылка не найдена"
+//	@Failure	410	{string}	string	"короткая ссылка удалена"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/{shortURI}/qr [get]
+//	@Param		shortURI	path	string	true	"идентификатор короткой ссылки"
+//	@Param		format		query	string	false	"формат изображения: png или svg"
+//	@Param		size		query	int		false	"размер изображения в пикселях"
+//	@Param		margin		query	int		false	"ширина поля вокруг QR-кода в модулях"
+//	@Param		level		query	string	false	"уровень коррекции ошибок: L, M, Q или H"
+//	@Param		fg			query	string	false	"цвет модулей в виде RRGGBB или RRGGBBAA"
+//	@Param		bg			query	string	false	"цвет фона в виде RRGGBB или RRGGBBAA"
+func (c *AppController) GetQRCodeHandler(w http.ResponseWriter, r *http.Request) {
+	shortURI := chi.URLParam(r, "id")
+
+	qrOptions, err := parseQROptions(r)
+	if err != nil {
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errorw(err.Error())
+		}
+
+		return
+	}
+
+	key, ok := c.domains.KeyByHost(r.Host, shortURI)
+	if !ok {
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	shortURLEntry, err := c.shortURLProvider.GetShortURLByShortURI(r.Context(), key)
+	if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Errorw("app: error when get original url from storage", "err", err)
+
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if shortURLEntry.Deleted {
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+
+	shortURL := c.domains.ShortURL(shortURLEntry.ShortURI)
+	// изображение однозначно определяется содержимым и параметрами, поэтому ETag вычисляется до его формирования
+	etagSum := sha256.Sum256([]byte(fmt.Sprintf("%s|%+v", shortURL, qrOptions)))
+	etag := `"` + hex.EncodeToString(etagSum[:16]) + `"`
+
+	if r.Header.Get("If-None-Match") == etag {
+		setQRCodeCacheHeaders(w, etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	qrImage, err := qr.Render(shortURL, qrOptions)
+	if err != nil && errors.Is(err, qr.ErrInvalidOptions) {
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errorw(err.Error())
+		}
+
+		return
+	} else if err != nil {
+		log.Errorw("app: error when render qr code", "shortURI", shortURI, "err", err)
+
+		w.Header().Add("Content-Type", "plain/text")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	setQRCodeCacheHeaders(w, etag)
+	w.Header().Set("Content-Type", qrOptions.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(qrImage)))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(qrImage)
+	if err != nil {
+		err = fmt.Errorf("app: error writing response: %v", err)
+		log.Errorw(err.Error())
+	}
+}
+
+func setQRCodeCacheHeaders(w http.ResponseWriter, etag string) {
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", qrCodeMaxAge))
+	w.Header().Set("ETag", etag)
+}
+
+// parseQROptions разбирает параметры изображения QR-кода из query-параметров запроса
+func parseQROptions(r *http.Request) (qr.Options, error) {
+	values := r.URL.Query()
+
+	var size int
+	if sizeParam := values.Get("size"); sizeParam != "" {
+		var err error
+		size, err = strconv.Atoi(sizeParam)
+		if err != nil {
+			return qr.Options{}, fmt.Errorf("invalid size: %w", err)
+		}
+	}
+
+	var margin *int
+	if marginParam := values.Get("margin"); marginParam != "" {
+		marginValue, err := strconv.Atoi(marginParam)
+		if err != nil {
+			return qr.Options{}, fmt.Errorf("invalid margin: %w", err)
+		}
+		margin = &marginValue
+	}
+
+	return qr.NewOptions(values.Get("format"), size, margin, values.Get("level"), values.Get("fg"), values.Get("bg"))
+}
+
+// clientAddr возвращает IP-адрес клиента, определенный middleware.ClientAddrMiddleware, иначе - адрес соединения
+func clientAddr(r *http.Request) netip.Addr {
+	if addr, ok := r.Context().Value(common.ClientAddrContextKey).(netip.Addr); ok {
+		return addr
+	}
+
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	return addrPort.Addr()
 }
@@ -1,22 +1,31 @@
 package controller
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/vkhrushchev/urlshortener/internal/app/db"
+	"github.com/vkhrushchev/urlshortener/internal/app/dto"
 )
 
+type cacheStatsProvider interface {
+	CacheStats() (hits int64, misses int64)
+}
+
 // HealthController обрабатывает запросы helhtcheck-а
 type HealthController struct {
-	dbLookup *db.DBLookup // dbLookup - обертка для доступа к БД
+	dbLookup   *db.DBLookup       // dbLookup - обертка для доступа к БД
+	cacheStats cacheStatsProvider // cacheStats - статистика кэша коротких ссылок, nil - кэш не используется
 }
 
 // NewHealthController создает новый экземпляр структуры HealthController
 //
 //	dbLookup - обертка для доступа к БД
-func NewHealthController(dbLookup *db.DBLookup) *HealthController {
+//	cacheStats - статистика кэша коротких ссылок, nil - кэш не используется
+func NewHealthController(dbLookup *db.DBLookup, cacheStats cacheStatsProvider) *HealthController {
 	return &HealthController{
-		dbLookup: dbLookup,
+		dbLookup:   dbLookup,
+		cacheStats: cacheStats,
 	}
 }
 
@@ -37,3 +46,39 @@ func (c *HealthController) Ping(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// GetDBStats возвращает статистику пула соединений с базой данных, доступность реплик
+// и попадания в кэш коротких ссылок
+//
+//	@Summary	статистика пула соединений с базой данных и кэша коротких ссылок
+//	@Accepts	plain
+//	@Produce	json
+//	@Success	200	{object}	dto.APIInternalGetDBStatsResponse
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/internal/db/stats [get]
+func (c *HealthController) GetDBStats(w http.ResponseWriter, r *http.Request) {
+	stats := c.dbLookup.Stats()
+	apiResponse := dto.APIInternalGetDBStatsResponse{
+		MaxConns:             stats.MaxConns,
+		TotalConns:           stats.TotalConns,
+		InUseConns:           stats.InUseConns,
+		IdleConns:            stats.IdleConns,
+		WaitCount:            stats.WaitCount,
+		CanceledAcquireCount: stats.CanceledAcquireCount,
+		ReadRetryCount:       stats.ReadRetryCount,
+		ReplicaCount:         stats.ReplicaCount,
+		HealthyReplicaCount:  stats.HealthyReplicaCount,
+		ReplicaFallbackCount: stats.ReplicaFallbackCount,
+	}
+	if c.cacheStats != nil {
+		apiResponse.CacheHitCount, apiResponse.CacheMissCount = c.cacheStats.CacheStats()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(apiResponse); err != nil {
+		log.Errorw("controller: failed to encode response", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -3,22 +3,40 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/vkhrushchev/urlshortener/internal/app/dto"
+	"github.com/vkhrushchev/urlshortener/internal/app/transfer"
+	"io"
 	"net/http"
+	"strconv"
 )
 
 type statsProvider interface {
 	GetStats(ctx context.Context) (urlCount int, userCount int, err error)
 }
 
+type shortURLExporter interface {
+	Export(ctx context.Context, w io.Writer, format string) (int, error)
+}
+
+type shortURLImporter interface {
+	Import(ctx context.Context, r io.Reader, options transfer.ImportOptions) (transfer.ImportResult, error)
+}
+
 // InternalController используется для обработки запросов приложения из доверенной сети
 type InternalController struct {
 	statsProvider statsProvider
+	exporter      shortURLExporter
+	importer      shortURLImporter
 }
 
 // NewInternalController создает новый экземпляр структуры InternalController
-func NewInternalController(statsProvider statsProvider) *InternalController {
-	return &InternalController{statsProvider: statsProvider}
+func NewInternalController(statsProvider statsProvider, exporter shortURLExporter, importer shortURLImporter) *InternalController {
+	return &InternalController{
+		statsProvider: statsProvider,
+		exporter:      exporter,
+		importer:      importer,
+	}
 }
 
 // GetStats возвращает статистику по сервису
@@ -50,3 +68,97 @@ func (c *InternalController) GetStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// Export выгружает все короткие ссылки, включая удаленные, в формате JSON Lines или CSV
+//
+//	@Summary	выгрузка всех коротких ссылок
+//	@Accepts	plain
+//	@Produce	json
+//	@Produce	text/csv
+//	@Param		format	query		string	false	"формат выгрузки: jsonl или csv"	default(jsonl)
+//	@Success	200
+//	@Failure	400	{string}	string	"неподдерживаемый формат выгрузки"
+//	@Failure	500	{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/internal/export [get]
+func (c *InternalController) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = transfer.FormatJSONLines
+	}
+	if err := transfer.CheckFormat(format); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", transfer.ContentType(format))
+	w.Header().Set("Content-Disposition", "attachment; filename=\"short_urls."+format+"\"")
+	w.WriteHeader(http.StatusOK)
+
+	// заголовки и часть выгрузки уже отправлены, поэтому об ошибке можно только записать в лог
+	if _, err := c.exporter.Export(r.Context(), w, format); err != nil {
+		log.Errorw("controller: failed to export short urls", "error", err)
+	}
+}
+
+// Import загружает короткие ссылки из выгрузки в формате JSON Lines или CSV с сохранением ключей коротких ссылок
+//
+//	@Summary	загрузка коротких ссылок
+//	@Accepts	json
+//	@Accepts	text/csv
+//	@Produce	json
+//	@Param		format		query		string	false	"формат выгрузки: jsonl или csv"								default(jsonl)
+//	@Param		dry_run		query		bool	false	"проверить выгрузку без сохранения коротких ссылок"			default(false)
+//	@Param		conflict	query		string	false	"политика разрешения конфликтов: skip или fail"				default(skip)
+//	@Success	200			{object}	dto.APIInternalImportResponse
+//	@Failure	400			{string}	string	"некорректная выгрузка или параметры загрузки"
+//	@Failure	409			{string}	string	"короткая ссылка конфликтует с существующей"
+//	@Failure	500			{string}	string	"внутренняя ошибка сервиса"
+//	@Router		/api/internal/import [post]
+func (c *InternalController) Import(w http.ResponseWriter, r *http.Request) {
+	options := transfer.ImportOptions{
+		Format:   r.URL.Query().Get("format"),
+		Conflict: r.URL.Query().Get("conflict"),
+	}
+	if options.Format == "" {
+		options.Format = transfer.FormatJSONLines
+	}
+	if dryRun := r.URL.Query().Get("dry_run"); dryRun != "" {
+		var err error
+		if options.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			http.Error(w, "dry_run must be boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := c.importer.Import(r.Context(), r.Body, options)
+	if err != nil {
+		log.Errorw("controller: failed to import short urls", "error", err, "read", result.Read, "imported", result.Imported)
+		switch {
+		case errors.Is(err, transfer.ErrUnsupportedFormat),
+			errors.Is(err, transfer.ErrUnsupportedConflictPolicy),
+			errors.Is(err, transfer.ErrInvalidRecord):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, transfer.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	apiResponse := dto.APIInternalImportResponse{
+		Read:      result.Read,
+		Imported:  result.Imported,
+		Existing:  result.Existing,
+		Conflicts: result.Conflicts,
+		DryRun:    options.DryRun,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(apiResponse); err != nil {
+		log.Errorw("controller: failed to encode response", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
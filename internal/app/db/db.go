@@ -4,9 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync/atomic"
 	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
@@ -18,29 +19,122 @@ const createShortURLTableSQL = `create table if not exists short_url
 	short_url varchar(20) not null,
 	original_url text not null
 );`
-const createUniqueIndexOnOriginalURLSQL = `create unique index if not exists short_url_original_url_uindex on short_url (original_url);`
 const addUserIDColumnSQL = `alter table short_url add if not exists user_id varchar(36) not null;`
 const addIsDeletedColumnSQL = `alter table short_url add if not exists is_deleted boolean not null;`
+const addDeletedAtColumnSQL = `alter table short_url add if not exists deleted_at timestamptz;`
+const fillDeletedAtColumnSQL = `update short_url set deleted_at = now() where is_deleted = true and deleted_at is null;`
+const addCreatedAtColumnSQL = `alter table short_url add if not exists created_at timestamptz not null default now();`
+const createIndexOnUserIDSQL = `create index if not exists short_url_user_id_index on short_url (user_id, created_at);`
+const addMetadataColumnsSQL = `alter table short_url
+	add if not exists title text not null default '',
+	add if not exists description text not null default '',
+	add if not exists notes text not null default '',
+	add if not exists tags jsonb not null default '[]';`
+const createIndexOnTagsSQL = `create index if not exists short_url_tags_index on short_url using gin (tags);`
+const addRedirectStatusColumnSQL = `alter table short_url add if not exists redirect_status smallint not null default 0;`
+const addRedirectOptionsColumnsSQL = `alter table short_url
+	add if not exists query_passthrough text not null default '',
+	add if not exists path_passthrough boolean not null default false,
+	add if not exists utm_params jsonb not null default '{}';`
+const addTargetingRulesColumnSQL = `alter table short_url add if not exists targeting_rules jsonb not null default '[]';`
+const addPreviewColumnsSQL = `alter table short_url
+	add if not exists preview_title text not null default '',
+	add if not exists preview_description text not null default '',
+	add if not exists preview_image_url text not null default '',
+	add if not exists preview_fetched_at timestamptz;`
+const addSplitVariantsColumnSQL = `alter table short_url add if not exists split_variants jsonb not null default '[]';`
+const addPasswordHashColumnSQL = `alter table short_url add if not exists password_hash text not null default '';`
+const addClickLimitColumnsSQL = `alter table short_url
+	add if not exists max_clicks integer not null default 0,
+	add if not exists click_count bigint not null default 0;`
+const addActivityWindowColumnsSQL = `alter table short_url
+	add if not exists not_before timestamptz,
+	add if not exists not_after timestamptz;`
+const addFallbackColumnsSQL = `alter table short_url
+	add if not exists is_disabled boolean not null default false,
+	add if not exists fallback_url text not null default '';`
+const createDeleteJobTableSQL = `create table if not exists delete_job
+(
+	id varchar(36) not null constraint delete_job_pk primary key,
+	user_id varchar(36) not null,
+	status varchar(16) not null,
+	short_urls text not null,
+	results text not null,
+	created_at timestamptz not null,
+	updated_at timestamptz not null
+);`
+const createIndexOnDeleteJobStatusSQL = `create index if not exists delete_job_status_index on delete_job (status);`
+const createSplitClickTableSQL = `create table if not exists split_click
+(
+	short_url varchar(20) not null,
+	variant varchar(32) not null,
+	clicks bigint not null,
+	constraint split_click_pk primary key (short_url, variant)
+);`
+const createCountryClickTableSQL = `create table if not exists country_click
+(
+	short_url varchar(300) not null,
+	country varchar(2) not null,
+	clicks bigint not null,
+	constraint country_click_pk primary key (short_url, country)
+);`
+const createUserSettingsTableSQL = `create table if not exists user_settings
+(
+	user_id varchar(36) not null constraint user_settings_pk primary key,
+	fallback_url text not null default ''
+);`
+
+// ключ короткой ссылки на дополнительном домене содержит имя домена, поэтому колонки short_url расширяются,
+// а уникальность исходного URL проверяется в пределах домена
+const widenShortURLColumnSQL = `alter table short_url alter column short_url type varchar(300);`
+const widenSplitClickShortURLColumnSQL = `alter table split_click alter column short_url type varchar(300);`
+const addShortDomainColumnSQL = `alter table short_url add if not exists short_domain varchar(253) not null default '';`
+const dropUniqueIndexOnOriginalURLSQL = `drop index if exists short_url_original_url_uindex;`
+const createUniqueIndexOnShortDomainAndOriginalURLSQL = `create unique index if not exists short_url_short_domain_original_url_uindex
+	on short_url (short_domain, original_url);`
+
+// ключ короткой ссылки проверяется перед созданием, поэтому уникальность ключа при одновременном создании
+// обеспечивается индексом
+const createUniqueIndexOnShortURLSQL = `create unique index if not exists short_url_short_url_uindex on short_url (short_url);`
 
 // DBLookup - структура для хранения ссылки на sql.DB
 type DBLookup struct {
-	db *sql.DB
+	db             *sql.DB
+	sqlite         bool
+	pool           *pgxpool.Pool
+	readRetries    int
+	readRetryCount atomic.Int64
+
+	replicas             []*replica
+	replicaCursor        atomic.Uint64
+	replicaFallbackCount atomic.Int64
+	writers              *recentWriters
 }
 
-// NewDBLookup создает экземпляр структуры DBLookup
+// NewDBLookup создает экземпляр структуры DBLookup с настройками пула соединений по умолчанию
+//
+// DSN со схемой "sqlite://" открывает файл базы данных SQLite, остальные DSN - базу данных Postgres.
 func NewDBLookup(databaseDSN string) (*DBLookup, error) {
-	db, err := sql.Open("pgx", databaseDSN)
-	if err != nil {
-		return nil, fmt.Errorf("db: error when open database: %s", err.Error())
+	return NewDBLookupWithPoolConfig(databaseDSN, PoolConfig{})
+}
+
+// NewDBLookupWithPoolConfig создает экземпляр структуры DBLookup с настройками пула соединений poolConfig
+//
+// Настройки пула соединений применяются только к базе данных Postgres.
+func NewDBLookupWithPoolConfig(databaseDSN string, poolConfig PoolConfig) (*DBLookup, error) {
+	if IsSQLiteDSN(databaseDSN) {
+		return newSQLiteDBLookup(databaseDSN)
 	}
 
-	return &DBLookup{
-		db: db,
-	}, nil
+	return newPostgresDBLookup(databaseDSN, poolConfig)
 }
 
 // InitDB инициализирует схему БД
 func (d *DBLookup) InitDB(ctx context.Context) error {
+	if d.sqlite {
+		return d.initSQLiteDB(ctx)
+	}
+
 	log.Infow("db: run createShortUrlTableSQL...")
 	_, err := d.db.ExecContext(ctx, createShortURLTableSQL)
 	if err != nil {
@@ -48,13 +142,6 @@ func (d *DBLookup) InitDB(ctx context.Context) error {
 	}
 	log.Infow("db: run createShortUrlTableSQL... success")
 
-	log.Infow("db: run createUniqueIndexOnOriginalURLSQL...")
-	_, err = d.db.ExecContext(ctx, createUniqueIndexOnOriginalURLSQL)
-	if err != nil {
-		return fmt.Errorf("db: error when execute createUniqueIndexOnOriginalURLSQL: %v", err)
-	}
-	log.Infow("db: run createUniqueIndexOnOriginalURLSQL... success")
-
 	log.Infow("db: run addUserIDColumnSQL...")
 	_, err = d.db.ExecContext(ctx, addUserIDColumnSQL)
 	if err != nil {
@@ -69,6 +156,188 @@ func (d *DBLookup) InitDB(ctx context.Context) error {
 	}
 	log.Infow("db: run addIsDeletedColumnSQL... success")
 
+	log.Infow("db: run addDeletedAtColumnSQL...")
+	_, err = d.db.ExecContext(ctx, addDeletedAtColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addDeletedAtColumnSQL: %v", err)
+	}
+	log.Infow("db: run addDeletedAtColumnSQL... success")
+
+	log.Infow("db: run fillDeletedAtColumnSQL...")
+	_, err = d.db.ExecContext(ctx, fillDeletedAtColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute fillDeletedAtColumnSQL: %v", err)
+	}
+	log.Infow("db: run fillDeletedAtColumnSQL... success")
+
+	log.Infow("db: run addCreatedAtColumnSQL...")
+	_, err = d.db.ExecContext(ctx, addCreatedAtColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addCreatedAtColumnSQL: %v", err)
+	}
+	log.Infow("db: run addCreatedAtColumnSQL... success")
+
+	log.Infow("db: run createIndexOnUserIDSQL...")
+	_, err = d.db.ExecContext(ctx, createIndexOnUserIDSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createIndexOnUserIDSQL: %v", err)
+	}
+	log.Infow("db: run createIndexOnUserIDSQL... success")
+
+	log.Infow("db: run addMetadataColumnsSQL...")
+	_, err = d.db.ExecContext(ctx, addMetadataColumnsSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addMetadataColumnsSQL: %v", err)
+	}
+	log.Infow("db: run addMetadataColumnsSQL... success")
+
+	log.Infow("db: run createIndexOnTagsSQL...")
+	_, err = d.db.ExecContext(ctx, createIndexOnTagsSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createIndexOnTagsSQL: %v", err)
+	}
+	log.Infow("db: run createIndexOnTagsSQL... success")
+
+	log.Infow("db: run addRedirectStatusColumnSQL...")
+	_, err = d.db.ExecContext(ctx, addRedirectStatusColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addRedirectStatusColumnSQL: %v", err)
+	}
+	log.Infow("db: run addRedirectStatusColumnSQL... success")
+
+	log.Infow("db: run addRedirectOptionsColumnsSQL...")
+	_, err = d.db.ExecContext(ctx, addRedirectOptionsColumnsSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addRedirectOptionsColumnsSQL: %v", err)
+	}
+	log.Infow("db: run addRedirectOptionsColumnsSQL... success")
+
+	log.Infow("db: run addTargetingRulesColumnSQL...")
+	_, err = d.db.ExecContext(ctx, addTargetingRulesColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addTargetingRulesColumnSQL: %v", err)
+	}
+	log.Infow("db: run addTargetingRulesColumnSQL... success")
+
+	log.Infow("db: run addPreviewColumnsSQL...")
+	_, err = d.db.ExecContext(ctx, addPreviewColumnsSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addPreviewColumnsSQL: %v", err)
+	}
+	log.Infow("db: run addPreviewColumnsSQL... success")
+
+	log.Infow("db: run addSplitVariantsColumnSQL...")
+	_, err = d.db.ExecContext(ctx, addSplitVariantsColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addSplitVariantsColumnSQL: %v", err)
+	}
+	log.Infow("db: run addSplitVariantsColumnSQL... success")
+
+	log.Infow("db: run addPasswordHashColumnSQL...")
+	_, err = d.db.ExecContext(ctx, addPasswordHashColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addPasswordHashColumnSQL: %v", err)
+	}
+	log.Infow("db: run addPasswordHashColumnSQL... success")
+
+	log.Infow("db: run addClickLimitColumnsSQL...")
+	_, err = d.db.ExecContext(ctx, addClickLimitColumnsSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addClickLimitColumnsSQL: %v", err)
+	}
+	log.Infow("db: run addClickLimitColumnsSQL... success")
+
+	log.Infow("db: run addActivityWindowColumnsSQL...")
+	_, err = d.db.ExecContext(ctx, addActivityWindowColumnsSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addActivityWindowColumnsSQL: %v", err)
+	}
+	log.Infow("db: run addActivityWindowColumnsSQL... success")
+
+	log.Infow("db: run addFallbackColumnsSQL...")
+	_, err = d.db.ExecContext(ctx, addFallbackColumnsSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addFallbackColumnsSQL: %v", err)
+	}
+	log.Infow("db: run addFallbackColumnsSQL... success")
+
+	log.Infow("db: run createDeleteJobTableSQL...")
+	_, err = d.db.ExecContext(ctx, createDeleteJobTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createDeleteJobTableSQL: %v", err)
+	}
+	log.Infow("db: run createDeleteJobTableSQL... success")
+
+	log.Infow("db: run createIndexOnDeleteJobStatusSQL...")
+	_, err = d.db.ExecContext(ctx, createIndexOnDeleteJobStatusSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createIndexOnDeleteJobStatusSQL: %v", err)
+	}
+	log.Infow("db: run createIndexOnDeleteJobStatusSQL... success")
+
+	log.Infow("db: run createSplitClickTableSQL...")
+	_, err = d.db.ExecContext(ctx, createSplitClickTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createSplitClickTableSQL: %v", err)
+	}
+	log.Infow("db: run createSplitClickTableSQL... success")
+
+	log.Infow("db: run createUserSettingsTableSQL...")
+	_, err = d.db.ExecContext(ctx, createUserSettingsTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createUserSettingsTableSQL: %v", err)
+	}
+	log.Infow("db: run createUserSettingsTableSQL... success")
+
+	log.Infow("db: run widenShortURLColumnSQL...")
+	_, err = d.db.ExecContext(ctx, widenShortURLColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute widenShortURLColumnSQL: %v", err)
+	}
+	log.Infow("db: run widenShortURLColumnSQL... success")
+
+	log.Infow("db: run widenSplitClickShortURLColumnSQL...")
+	_, err = d.db.ExecContext(ctx, widenSplitClickShortURLColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute widenSplitClickShortURLColumnSQL: %v", err)
+	}
+	log.Infow("db: run widenSplitClickShortURLColumnSQL... success")
+
+	log.Infow("db: run addShortDomainColumnSQL...")
+	_, err = d.db.ExecContext(ctx, addShortDomainColumnSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute addShortDomainColumnSQL: %v", err)
+	}
+	log.Infow("db: run addShortDomainColumnSQL... success")
+
+	log.Infow("db: run dropUniqueIndexOnOriginalURLSQL...")
+	_, err = d.db.ExecContext(ctx, dropUniqueIndexOnOriginalURLSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute dropUniqueIndexOnOriginalURLSQL: %v", err)
+	}
+	log.Infow("db: run dropUniqueIndexOnOriginalURLSQL... success")
+
+	log.Infow("db: run createUniqueIndexOnShortDomainAndOriginalURLSQL...")
+	_, err = d.db.ExecContext(ctx, createUniqueIndexOnShortDomainAndOriginalURLSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createUniqueIndexOnShortDomainAndOriginalURLSQL: %v", err)
+	}
+	log.Infow("db: run createUniqueIndexOnShortDomainAndOriginalURLSQL... success")
+
+	log.Infow("db: run createUniqueIndexOnShortURLSQL...")
+	_, err = d.db.ExecContext(ctx, createUniqueIndexOnShortURLSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createUniqueIndexOnShortURLSQL: %v", err)
+	}
+	log.Infow("db: run createUniqueIndexOnShortURLSQL... success")
+
+	log.Infow("db: run createCountryClickTableSQL...")
+	_, err = d.db.ExecContext(ctx, createCountryClickTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createCountryClickTableSQL: %v", err)
+	}
+	log.Infow("db: run createCountryClickTableSQL... success")
+
 	return nil
 }
 
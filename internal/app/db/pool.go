@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// readRetryBackoff - задержка перед повторным чтением, увеличивается с каждой попыткой
+const readRetryBackoff = 50 * time.Millisecond
+
+// PoolConfig - настройки пула соединений с базой данных Postgres, нулевое значение - значение по умолчанию pgxpool
+type PoolConfig struct {
+	// MaxConns - максимальное количество соединений
+	MaxConns int32
+	// MinConns - количество соединений, поддерживаемых открытыми
+	MinConns int32
+	// MaxConnLifetime - время, после которого соединение закрывается
+	MaxConnLifetime time.Duration
+	// MaxConnIdleTime - время простоя, после которого соединение закрывается
+	MaxConnIdleTime time.Duration
+	// StatementTimeout - максимальное время выполнения запроса на сервере, 0 - без ограничения
+	StatementTimeout time.Duration
+	// ReadRetries - количество повторов идемпотентного чтения при ошибках сериализации и соединения
+	ReadRetries int
+}
+
+// PoolStats - статистика пула соединений с базой данных
+type PoolStats struct {
+	// MaxConns - максимальное количество соединений
+	MaxConns int64
+	// TotalConns - количество открытых соединений
+	TotalConns int64
+	// InUseConns - количество используемых соединений
+	InUseConns int64
+	// IdleConns - количество простаивающих соединений
+	IdleConns int64
+	// WaitCount - количество ожиданий свободного соединения
+	WaitCount int64
+	// CanceledAcquireCount - количество отмененных запросов соединения
+	CanceledAcquireCount int64
+	// ReadRetryCount - количество повторов идемпотентного чтения
+	ReadRetryCount int64
+	// ReplicaCount - количество реплик для чтения
+	ReplicaCount int64
+	// HealthyReplicaCount - количество доступных реплик для чтения
+	HealthyReplicaCount int64
+	// ReplicaFallbackCount - количество чтений, повторенных на основной базе данных после чтения на реплике
+	ReplicaFallbackCount int64
+}
+
+// newPostgresDBLookup создает экземпляр структуры DBLookup для базы данных Postgres с пулом соединений pgxpool
+func newPostgresDBLookup(databaseDSN string, poolConfig PoolConfig) (*DBLookup, error) {
+	pool, err := newPostgresPool(databaseDSN, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DBLookup{
+		db:          stdlib.OpenDBFromPool(pool),
+		pool:        pool,
+		readRetries: poolConfig.ReadRetries,
+	}, nil
+}
+
+// newPostgresPool создает пул соединений pgxpool с базой данных Postgres с настройками poolConfig
+func newPostgresPool(databaseDSN string, poolConfig PoolConfig) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(databaseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("db: error when parse database dsn: %s", err.Error())
+	}
+
+	if poolConfig.MaxConns > 0 {
+		config.MaxConns = poolConfig.MaxConns
+	}
+	if poolConfig.MinConns > 0 {
+		config.MinConns = poolConfig.MinConns
+	}
+	if poolConfig.MaxConnLifetime > 0 {
+		config.MaxConnLifetime = poolConfig.MaxConnLifetime
+	}
+	if poolConfig.MaxConnIdleTime > 0 {
+		config.MaxConnIdleTime = poolConfig.MaxConnIdleTime
+	}
+	if poolConfig.StatementTimeout > 0 {
+		// statement_timeout ограничивает каждый запрос соединения, в том числе запросы в транзакциях
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(poolConfig.StatementTimeout.Milliseconds(), 10)
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		return nil, fmt.Errorf("db: error when open database: %s", err.Error())
+	}
+
+	return pool, nil
+}
+
+// Stats возвращает статистику пула соединений с базой данных
+func (d *DBLookup) Stats() PoolStats {
+	stats := PoolStats{
+		ReadRetryCount:       d.readRetryCount.Load(),
+		ReplicaCount:         int64(len(d.replicas)),
+		ReplicaFallbackCount: d.replicaFallbackCount.Load(),
+	}
+	for _, dbReplica := range d.replicas {
+		if dbReplica.healthy.Load() {
+			stats.HealthyReplicaCount++
+		}
+	}
+	if d.pool != nil {
+		poolStat := d.pool.Stat()
+		stats.MaxConns = int64(poolStat.MaxConns())
+		stats.TotalConns = int64(poolStat.TotalConns())
+		stats.InUseConns = int64(poolStat.AcquiredConns())
+		stats.IdleConns = int64(poolStat.IdleConns())
+		stats.WaitCount = poolStat.EmptyAcquireCount()
+		stats.CanceledAcquireCount = poolStat.CanceledAcquireCount()
+
+		return stats
+	}
+
+	dbStats := d.db.Stats()
+	stats.MaxConns = int64(dbStats.MaxOpenConnections)
+	stats.TotalConns = int64(dbStats.OpenConnections)
+	stats.InUseConns = int64(dbStats.InUse)
+	stats.IdleConns = int64(dbStats.Idle)
+	stats.WaitCount = dbStats.WaitCount
+
+	return stats
+}
+
+// RetryRead выполняет идемпотентное чтение read, повторяя его не более ReadRetries раз
+// при ошибках сериализации и соединения
+func (d *DBLookup) RetryRead(ctx context.Context, read func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := read()
+		if err == nil || attempt > d.readRetries || ctx.Err() != nil || !isRetryableReadError(err) {
+			return err
+		}
+
+		d.readRetryCount.Add(1)
+		log.Warnw("db: retry read", "attempt", attempt, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(readRetryBackoff * time.Duration(attempt)):
+		}
+	}
+}
+
+// Close закрывает соединения с базой данных и репликами
+func (d *DBLookup) Close() error {
+	err := d.db.Close()
+	if d.pool != nil {
+		d.pool.Close()
+	}
+	for _, dbReplica := range d.replicas {
+		err = errors.Join(err, dbReplica.db.Close())
+		if dbReplica.pool != nil {
+			dbReplica.pool.Close()
+		}
+	}
+
+	return err
+}
+
+// isRetryableReadError проверяет, что чтение, завершившееся ошибкой err, можно повторить
+func isRetryableReadError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == pgerrcode.SerializationFailure || pgErr.Code == pgerrcode.DeadlockDetected) {
+		return true
+	}
+
+	return isConnectionError(err)
+}
+
+// isConnectionError проверяет, что err - ошибка соединения с базой данных
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgerrcode.IsConnectionException(pgErr.Code) || pgErr.Code == pgerrcode.AdminShutdown
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr) ||
+		pgconn.SafeToRetry(err)
+}
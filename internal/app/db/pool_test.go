@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBLookup_RetryRead(t *testing.T) {
+	serializationErr := &pgconn.PgError{Code: pgerrcode.SerializationFailure}
+
+	testCases := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedErr      error
+	}{
+		{name: "success", errs: []error{nil}, expectedAttempts: 1},
+		{name: "serialization failure", errs: []error{serializationErr, nil}, expectedAttempts: 2},
+		{name: "bad connection", errs: []error{fmt.Errorf("read: %w", driver.ErrBadConn), nil}, expectedAttempts: 2},
+		{
+			name:             "retries exhausted",
+			errs:             []error{serializationErr, serializationErr, serializationErr, nil},
+			expectedAttempts: 3,
+			expectedErr:      serializationErr,
+		},
+		{name: "no rows", errs: []error{sql.ErrNoRows, nil}, expectedAttempts: 1, expectedErr: sql.ErrNoRows},
+		{
+			name:             "query canceled",
+			errs:             []error{&pgconn.PgError{Code: pgerrcode.QueryCanceled}, nil},
+			expectedAttempts: 1,
+			expectedErr:      &pgconn.PgError{Code: pgerrcode.QueryCanceled},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbLookup := &DBLookup{readRetries: 2}
+
+			attempts := 0
+			err := dbLookup.RetryRead(context.Background(), func() error {
+				attempts++
+				return tc.errs[attempts-1]
+			})
+
+			assert.Equal(t, tc.expectedAttempts, attempts)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, int64(tc.expectedAttempts-1), dbLookup.readRetryCount.Load())
+		})
+	}
+}
+
+func TestDBLookup_RetryRead_canceled(t *testing.T) {
+	dbLookup := &DBLookup{readRetries: 2}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := dbLookup.RetryRead(ctx, func() error {
+		attempts++
+		return errors.Join(context.Canceled, driver.ErrBadConn)
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
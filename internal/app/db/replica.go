@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// ErrSQLiteReplica - ошибка, возвращаемая при указании реплики для базы данных SQLite
+var ErrSQLiteReplica = errors.New("db: replicas are not supported by sqlite")
+
+// replicaPingTimeout - максимальное время проверки доступности реплики
+// recentWritersSweepSize - количество пользователей, при превышении которого удаляются устаревшие записи об изменениях
+const (
+	replicaPingTimeout     = 1 * time.Second
+	recentWritersSweepSize = 1024
+)
+
+// ReplicaConfig - настройки реплик базы данных Postgres, используемых для чтения
+type ReplicaConfig struct {
+	// DSNs - строки подключения к репликам
+	DSNs []string
+	// PrimaryReadWindow - время после изменения данных пользователем, в течение которого его чтения
+	// выполняются на основной базе данных, должно превышать отставание реплик
+	PrimaryReadWindow time.Duration
+}
+
+// replica - реплика базы данных, доступная только для чтения
+type replica struct {
+	index   int
+	db      *sql.DB
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// recentWriters - время последнего изменения данных пользователями
+type recentWriters struct {
+	mu      sync.Mutex
+	window  time.Duration
+	writeAt map[string]time.Time
+	now     func() time.Time
+}
+
+func newRecentWriters(window time.Duration) *recentWriters {
+	return &recentWriters{
+		window:  window,
+		writeAt: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (w *recentWriters) mark(userID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	if len(w.writeAt) >= recentWritersSweepSize {
+		for writerID, writeAt := range w.writeAt {
+			if now.Sub(writeAt) >= w.window {
+				delete(w.writeAt, writerID)
+			}
+		}
+	}
+
+	w.writeAt[userID] = now
+}
+
+func (w *recentWriters) contains(userID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	writeAt, ok := w.writeAt[userID]
+	return ok && w.now().Sub(writeAt) < w.window
+}
+
+// NewDBLookupWithReplicas создает экземпляр структуры DBLookup для базы данных Postgres с основной базой данных
+// databaseDSN и репликами для чтения replicaConfig
+//
+// Настройки пула соединений poolConfig применяются к основной базе данных и к каждой из реплик.
+func NewDBLookupWithReplicas(databaseDSN string, poolConfig PoolConfig, replicaConfig ReplicaConfig) (*DBLookup, error) {
+	if len(replicaConfig.DSNs) == 0 {
+		return NewDBLookupWithPoolConfig(databaseDSN, poolConfig)
+	}
+	if IsSQLiteDSN(databaseDSN) {
+		return nil, ErrSQLiteReplica
+	}
+
+	dbLookup, err := newPostgresDBLookup(databaseDSN, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	dbLookup.writers = newRecentWriters(replicaConfig.PrimaryReadWindow)
+	for i, replicaDSN := range replicaConfig.DSNs {
+		if IsSQLiteDSN(replicaDSN) {
+			_ = dbLookup.Close()
+			return nil, ErrSQLiteReplica
+		}
+
+		pool, err := newPostgresPool(replicaDSN, poolConfig)
+		if err != nil {
+			_ = dbLookup.Close()
+			return nil, fmt.Errorf("db: error when open replica %d: %w", i, err)
+		}
+
+		dbReplica := &replica{index: i, db: stdlib.OpenDBFromPool(pool), pool: pool}
+		dbReplica.healthy.Store(true)
+		dbLookup.replicas = append(dbLookup.replicas, dbReplica)
+	}
+
+	return dbLookup, nil
+}
+
+// MarkWrite отмечает изменение данных пользователем userID, после которого его чтения выполняются
+// на основной базе данных, пока изменения не попадут на реплики
+//
+// Изменения учитываются только в памяти этого экземпляра сервиса: чтения того же пользователя через другой
+// экземпляр, а также чтения других пользователей и перенаправления без пользователя выполняются на реплике
+// и в пределах ее отставания могут не видеть изменение (например, удаление, блокировку короткой ссылки
+// или изменение ограничения количества переходов).
+func (d *DBLookup) MarkWrite(userID string) {
+	if d.writers == nil || userID == "" {
+		return
+	}
+
+	d.writers.mark(userID)
+}
+
+// ReadReplica выполняет идемпотентное чтение read на реплике, повторяя его по правилам RetryRead
+//
+// Чтение выполняется на основной базе данных, если реплики не настроены или недоступны, а также
+// если пользователь userID недавно изменял данные. Если реплика недоступна, не нашла записи
+// или прервала запрос из-за конфликта с репликацией, чтение повторяется на основной базе данных.
+func (d *DBLookup) ReadReplica(ctx context.Context, userID string, read func(db *sql.DB) error) error {
+	return d.RetryRead(ctx, func() error {
+		dbReplica := d.nextReplica(userID)
+		if dbReplica == nil {
+			return read(d.db)
+		}
+
+		err := read(dbReplica.db)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if isConnectionError(err) {
+			if dbReplica.healthy.CompareAndSwap(true, false) {
+				log.Warnw("db: replica is unavailable", "replica", dbReplica.index, "err", err)
+			}
+		} else if !errors.Is(err, sql.ErrNoRows) && !isRetryableReadError(err) {
+			return err
+		}
+
+		// запись могла еще не попасть на реплику, поэтому отсутствие записи проверяется на основной базе данных
+		d.replicaFallbackCount.Add(1)
+		return read(d.db)
+	})
+}
+
+// nextReplica возвращает доступную реплику для чтения пользователем userID по очереди
+// или nil, если чтение нужно выполнить на основной базе данных
+func (d *DBLookup) nextReplica(userID string) *replica {
+	if len(d.replicas) == 0 || (userID != "" && d.writers.contains(userID)) {
+		return nil
+	}
+
+	start := d.replicaCursor.Add(1)
+	for i := range d.replicas {
+		dbReplica := d.replicas[(int(start)+i)%len(d.replicas)]
+		if dbReplica.healthy.Load() {
+			return dbReplica
+		}
+	}
+
+	return nil
+}
+
+// CheckReplicas проверяет доступность реплик и возвращает количество доступных реплик
+func (d *DBLookup) CheckReplicas(ctx context.Context) int {
+	healthyCount := 0
+	for _, dbReplica := range d.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		err := dbReplica.db.PingContext(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if dbReplica.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Infow("db: replica is available", "replica", dbReplica.index)
+			} else {
+				log.Warnw("db: replica is unavailable", "replica", dbReplica.index, "err", err)
+			}
+		}
+		if healthy {
+			healthyCount++
+		}
+	}
+
+	return healthyCount
+}
+
+// RunReplicaHealthCheck проверяет доступность реплик с интервалом interval до отмены контекста
+func (d *DBLookup) RunReplicaHealthCheck(ctx context.Context, interval time.Duration) {
+	if len(d.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.CheckReplicas(ctx)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDB(t *testing.T, rows map[int]string) *sql.DB {
+	testDB, err := sql.Open(sqliteDriverName, ":memory:")
+	require.NoError(t, err)
+	testDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = testDB.Close() })
+
+	_, err = testDB.Exec("create table item (id integer primary key, name text not null)")
+	require.NoError(t, err)
+	for id, name := range rows {
+		_, err = testDB.Exec("insert into item (id, name) values (?, ?)", id, name)
+		require.NoError(t, err)
+	}
+
+	return testDB
+}
+
+func newTestReplicaDBLookup(t *testing.T) *DBLookup {
+	dbReplica := &replica{db: openTestDB(t, map[int]string{1: "replica"})}
+	dbReplica.healthy.Store(true)
+
+	return &DBLookup{
+		db:       openTestDB(t, map[int]string{1: "primary", 2: "primary"}),
+		replicas: []*replica{dbReplica},
+		writers:  newRecentWriters(time.Minute),
+	}
+}
+
+func TestDBLookup_ReadReplica(t *testing.T) {
+	testCases := []struct {
+		name             string
+		id               int
+		userID           string
+		writerID         string
+		unhealthy        bool
+		expectedName     string
+		expectedFallback int64
+	}{
+		{name: "replica", id: 1, expectedName: "replica"},
+		{name: "not replicated yet", id: 2, expectedName: "primary", expectedFallback: 1},
+		{name: "recent writer", id: 1, userID: "user", writerID: "user", expectedName: "primary"},
+		{name: "other writer", id: 1, userID: "user", writerID: "other_user", expectedName: "replica"},
+		{name: "unhealthy replica", id: 1, unhealthy: true, expectedName: "primary"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbLookup := newTestReplicaDBLookup(t)
+			if tc.writerID != "" {
+				dbLookup.MarkWrite(tc.writerID)
+			}
+			if tc.unhealthy {
+				dbLookup.replicas[0].healthy.Store(false)
+			}
+
+			var name string
+			err := dbLookup.ReadReplica(context.Background(), tc.userID, func(db *sql.DB) error {
+				return db.QueryRow("select name from item where id = ?", tc.id).Scan(&name)
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedFallback, dbLookup.Stats().ReplicaFallbackCount)
+		})
+	}
+}
+
+func TestDBLookup_ReadReplica_not_found(t *testing.T) {
+	dbLookup := newTestReplicaDBLookup(t)
+
+	err := dbLookup.ReadReplica(context.Background(), "", func(db *sql.DB) error {
+		var name string
+		return db.QueryRow("select name from item where id = ?", 3).Scan(&name)
+	})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestDBLookup_CheckReplicas(t *testing.T) {
+	dbLookup := newTestReplicaDBLookup(t)
+	assert.Equal(t, 1, dbLookup.CheckReplicas(context.Background()))
+
+	require.NoError(t, dbLookup.replicas[0].db.Close())
+	assert.Equal(t, 0, dbLookup.CheckReplicas(context.Background()))
+	assert.Nil(t, dbLookup.nextReplica(""), "unavailable replica must not be used")
+
+	stats := dbLookup.Stats()
+	assert.Equal(t, int64(1), stats.ReplicaCount)
+	assert.Equal(t, int64(0), stats.HealthyReplicaCount)
+}
+
+func TestRecentWriters(t *testing.T) {
+	now := time.Now()
+	writers := newRecentWriters(time.Second)
+	writers.now = func() time.Time { return now }
+
+	writers.mark("user")
+	assert.True(t, writers.contains("user"))
+	assert.False(t, writers.contains("other_user"))
+
+	now = now.Add(time.Second)
+	assert.False(t, writers.contains("user"), "write must expire after window")
+
+	for i := 0; i < recentWritersSweepSize; i++ {
+		writers.mark(fmt.Sprintf("user_%d", i))
+	}
+	assert.Len(t, writers.writeAt, recentWritersSweepSize, "expired writes must be swept")
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDSNScheme - схема DSN базы данных SQLite, например "sqlite:///var/lib/urlshortener/urlshortener.db"
+const SQLiteDSNScheme = "sqlite://"
+
+// sqliteDriverName - имя драйвера SQLite с зарегистрированной функцией regexp
+const sqliteDriverName = "sqlite3_urlshortener"
+
+func init() {
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			// SQLite не содержит реализации оператора REGEXP, он вызывает пользовательскую функцию regexp
+			return conn.RegisterFunc("regexp", regexp.MatchString, true)
+		},
+	})
+}
+
+// схема SQLite создается сразу в актуальном виде, поэтому в отличие от Postgres не содержит миграций колонок
+const createSQLiteShortURLTableSQL = `create table if not exists short_url
+(
+	uuid varchar(36) not null constraint short_url_pk primary key,
+	short_url varchar(300) not null,
+	original_url text not null,
+	user_id varchar(36) not null,
+	is_deleted boolean not null default false,
+	deleted_at timestamp,
+	created_at timestamp not null,
+	title text not null default '',
+	description text not null default '',
+	notes text not null default '',
+	tags text not null default '[]',
+	redirect_status smallint not null default 0,
+	query_passthrough text not null default '',
+	path_passthrough boolean not null default false,
+	utm_params text not null default '{}',
+	targeting_rules text not null default '[]',
+	split_variants text not null default '[]',
+	password_hash text not null default '',
+	max_clicks integer not null default 0,
+	click_count bigint not null default 0,
+	not_before timestamp,
+	not_after timestamp,
+	is_disabled boolean not null default false,
+	fallback_url text not null default '',
+	preview_title text not null default '',
+	preview_description text not null default '',
+	preview_image_url text not null default '',
+	preview_fetched_at timestamp,
+	short_domain varchar(253) not null default ''
+);`
+const dropSQLiteIndexOnShortURLSQL = `drop index if exists short_url_short_url_index;`
+const createSQLiteUniqueIndexOnShortURLSQL = `create unique index if not exists short_url_short_url_uindex on short_url (short_url);`
+const createSQLiteIndexOnUserIDSQL = `create index if not exists short_url_user_id_index on short_url (user_id, created_at);`
+const createSQLiteUniqueIndexOnShortDomainAndOriginalURLSQL = `create unique index if not exists short_url_short_domain_original_url_uindex
+	on short_url (short_domain, original_url);`
+const createSQLiteDeleteJobTableSQL = `create table if not exists delete_job
+(
+	id varchar(36) not null constraint delete_job_pk primary key,
+	user_id varchar(36) not null,
+	status varchar(16) not null,
+	short_urls text not null,
+	results text not null,
+	created_at timestamp not null,
+	updated_at timestamp not null
+);`
+const createSQLiteSplitClickTableSQL = `create table if not exists split_click
+(
+	short_url varchar(300) not null,
+	variant varchar(32) not null,
+	clicks bigint not null,
+	constraint split_click_pk primary key (short_url, variant)
+);`
+const createSQLiteCountryClickTableSQL = `create table if not exists country_click
+(
+	short_url varchar(300) not null,
+	country varchar(2) not null,
+	clicks bigint not null,
+	constraint country_click_pk primary key (short_url, country)
+);`
+
+// IsSQLiteDSN проверяет, что databaseDSN - DSN базы данных SQLite
+func IsSQLiteDSN(databaseDSN string) bool {
+	return strings.HasPrefix(databaseDSN, SQLiteDSNScheme)
+}
+
+// newSQLiteDBLookup создает экземпляр структуры DBLookup для базы данных SQLite
+func newSQLiteDBLookup(databaseDSN string) (*DBLookup, error) {
+	db, err := sql.Open(sqliteDriverName, strings.TrimPrefix(databaseDSN, SQLiteDSNScheme))
+	if err != nil {
+		return nil, fmt.Errorf("db: error when open database: %s", err.Error())
+	}
+
+	// SQLite допускает только одну пишущую транзакцию, поэтому запросы выполняются через одно соединение,
+	// а не завершаются ошибкой "database is locked" при одновременной записи
+	db.SetMaxOpenConns(1)
+
+	return &DBLookup{
+		db:     db,
+		sqlite: true,
+	}, nil
+}
+
+// initSQLiteDB инициализирует схему БД SQLite
+func (d *DBLookup) initSQLiteDB(ctx context.Context) error {
+	log.Infow("db: run createSQLiteShortURLTableSQL...")
+	_, err := d.db.ExecContext(ctx, createSQLiteShortURLTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createSQLiteShortURLTableSQL: %v", err)
+	}
+	log.Infow("db: run createSQLiteShortURLTableSQL... success")
+
+	// неуникальный индекс ключа короткой ссылки заменяется уникальным, который не допускает повторного ключа
+	// при одновременном создании коротких ссылок
+	log.Infow("db: run dropSQLiteIndexOnShortURLSQL...")
+	_, err = d.db.ExecContext(ctx, dropSQLiteIndexOnShortURLSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute dropSQLiteIndexOnShortURLSQL: %v", err)
+	}
+	log.Infow("db: run dropSQLiteIndexOnShortURLSQL... success")
+
+	log.Infow("db: run createSQLiteUniqueIndexOnShortURLSQL...")
+	_, err = d.db.ExecContext(ctx, createSQLiteUniqueIndexOnShortURLSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createSQLiteUniqueIndexOnShortURLSQL: %v", err)
+	}
+	log.Infow("db: run createSQLiteUniqueIndexOnShortURLSQL... success")
+
+	log.Infow("db: run createSQLiteIndexOnUserIDSQL...")
+	_, err = d.db.ExecContext(ctx, createSQLiteIndexOnUserIDSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createSQLiteIndexOnUserIDSQL: %v", err)
+	}
+	log.Infow("db: run createSQLiteIndexOnUserIDSQL... success")
+
+	log.Infow("db: run createSQLiteUniqueIndexOnShortDomainAndOriginalURLSQL...")
+	_, err = d.db.ExecContext(ctx, createSQLiteUniqueIndexOnShortDomainAndOriginalURLSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createSQLiteUniqueIndexOnShortDomainAndOriginalURLSQL: %v", err)
+	}
+	log.Infow("db: run createSQLiteUniqueIndexOnShortDomainAndOriginalURLSQL... success")
+
+	log.Infow("db: run createSQLiteDeleteJobTableSQL...")
+	_, err = d.db.ExecContext(ctx, createSQLiteDeleteJobTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createSQLiteDeleteJobTableSQL: %v", err)
+	}
+	log.Infow("db: run createSQLiteDeleteJobTableSQL... success")
+
+	log.Infow("db: run createIndexOnDeleteJobStatusSQL...")
+	_, err = d.db.ExecContext(ctx, createIndexOnDeleteJobStatusSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createIndexOnDeleteJobStatusSQL: %v", err)
+	}
+	log.Infow("db: run createIndexOnDeleteJobStatusSQL... success")
+
+	log.Infow("db: run createSQLiteSplitClickTableSQL...")
+	_, err = d.db.ExecContext(ctx, createSQLiteSplitClickTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createSQLiteSplitClickTableSQL: %v", err)
+	}
+	log.Infow("db: run createSQLiteSplitClickTableSQL... success")
+
+	log.Infow("db: run createSQLiteCountryClickTableSQL...")
+	_, err = d.db.ExecContext(ctx, createSQLiteCountryClickTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createSQLiteCountryClickTableSQL: %v", err)
+	}
+	log.Infow("db: run createSQLiteCountryClickTableSQL... success")
+
+	log.Infow("db: run createUserSettingsTableSQL...")
+	_, err = d.db.ExecContext(ctx, createUserSettingsTableSQL)
+	if err != nil {
+		return fmt.Errorf("db: error when execute createUserSettingsTableSQL: %v", err)
+	}
+	log.Infow("db: run createUserSettingsTableSQL... success")
+
+	return nil
+}
@@ -1,18 +1,133 @@
 package domain
 
+import (
+	"time"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
+)
+
 // ShortURLDomain структура с описанием доменной сущности ShortURL
 type ShortURLDomain struct {
-	UUID     string
-	ShortURI string
-	LongURL  string
-	UserID   string
-	Deleted  bool
+	UUID      string
+	ShortURI  string
+	LongURL   string
+	UserID    string
+	Deleted   bool
+	DeletedAt *time.Time
+	CreatedAt time.Time
+
+	Title       string
+	Description string
+	Notes       string
+	Tags        []string
+	// RedirectStatus - код ответа перенаправления по короткой ссылке, 0 - код по умолчанию из конфигурации
+	RedirectStatus int
+	// QueryPassthrough - политика переноса параметров запроса перехода в исходный URL, "" - параметры не переносятся
+	QueryPassthrough string
+	// PathPassthrough - перенос пути после идентификатора короткой ссылки в исходный URL
+	PathPassthrough bool
+	// UTMParams - UTM-параметры, добавляемые в исходный URL при перенаправлении
+	UTMParams map[string]string
+	// TargetingRules - правила выбора адреса перенаправления по платформе, устройству и языку клиента
+	TargetingRules []targeting.Rule
+	// SplitVariants - варианты перенаправления A/B-теста, пустой список - перенаправление на исходный URL
+	SplitVariants []split.Variant
+	// PasswordHash - bcrypt-хэш пароля для перехода по короткой ссылке, "" - пароль не требуется
+	PasswordHash string
+	// MaxClicks - максимальное количество переходов по короткой ссылке, 0 - без ограничения
+	MaxClicks int
+	// ClickCount - количество учтенных переходов по короткой ссылке с ограничением количества переходов
+	ClickCount int64
+	// NotBefore - время начала действия короткой ссылки, nil - ссылка действует с момента создания
+	NotBefore *time.Time
+	// NotAfter - время окончания действия короткой ссылки, nil - ссылка действует бессрочно
+	NotAfter *time.Time
+	// Disabled - короткая ссылка заблокирована владельцем, переходы по ней не выполняются
+	Disabled bool
+	// FallbackURL - резервный адрес перенаправления для недоступной короткой ссылки, "" - адрес из настроек пользователя
+	FallbackURL string
+
+	PreviewTitle       string
+	PreviewDescription string
+	PreviewImageURL    string
+	PreviewFetchedAt   *time.Time
+}
+
+// ShortURLMetadataDomain структура с описанием задаваемых владельцем метаданных короткой ссылки
+type ShortURLMetadataDomain struct {
+	Title            string
+	Description      string
+	Notes            string
+	Tags             []string
+	RedirectStatus   int
+	QueryPassthrough string
+	PathPassthrough  bool
+	UTMParams        map[string]string
+	TargetingRules   []targeting.Rule
+	SplitVariants    []split.Variant
+	Password         string
+	MaxClicks        int
+	NotBefore        *time.Time
+	NotAfter         *time.Time
+	Disabled         bool
+	FallbackURL      string
+	// ShortDomain - домен короткой ссылки, выбираемый при создании, "" - домен по умолчанию
+	ShortDomain string
+}
+
+// ShortURLPatchDomain структура с описанием изменения короткой ссылки, nil-поля не изменяются
+type ShortURLPatchDomain struct {
+	Title            *string
+	Description      *string
+	Notes            *string
+	Tags             *[]string
+	RedirectStatus   *int
+	QueryPassthrough *string
+	PathPassthrough  *bool
+	UTMParams        *map[string]string
+	TargetingRules   *[]targeting.Rule
+	SplitVariants    *[]split.Variant
+	Password         *string
+	MaxClicks        *int
+	// NotBefore и NotAfter - нулевое время снимает ограничение
+	NotBefore   *time.Time
+	NotAfter    *time.Time
+	Disabled    *bool
+	FallbackURL *string
+}
+
+// ShortURLQueryDomain структура с описанием запроса на получение страницы коротких ссылок пользователя
+//
+// Sort задается именем поля сортировки, для сортировки по убыванию перед именем указывается "-".
+// Cursor - значение ShortURLPageDomain.NextCursor предыдущей страницы.
+type ShortURLQueryDomain struct {
+	UserID      string
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Domain      string
+	Search      string
+	Tag         string
+	Sort        string
+	Cursor      string
+	Limit       int
+}
+
+// ShortURLPageDomain структура с описанием страницы коротких ссылок пользователя
+type ShortURLPageDomain struct {
+	ShortURLs  []ShortURLDomain
+	TotalCount int
+	NextCursor string
 }
 
 // CreateShortURLBatchDomain структура с описанием доменной сущности CreateShortURLBatch
 type CreateShortURLBatchDomain struct {
 	CorrelationUUID string
 	LongURL         string
+	Metadata        ShortURLMetadataDomain
+	// Alias - идентификатор короткой ссылки, выбранный пользователем, "" - случайный идентификатор
+	Alias string
 }
 
 // CreateShortURLBatchResultDomain структура с описанием доменной сущности CreateShortURLBatchResult
@@ -20,3 +135,49 @@ type CreateShortURLBatchResultDomain struct {
 	CorrelationUUID string
 	ShortURI        string
 }
+
+// DeleteShortURLResultDomain структура с описанием результата удаления короткой ссылки
+type DeleteShortURLResultDomain struct {
+	ShortURI string
+	Status   string
+}
+
+// RestoreShortURLResultDomain структура с описанием результата восстановления короткой ссылки
+type RestoreShortURLResultDomain struct {
+	ShortURI string
+	Status   string
+}
+
+// DeleteJobDomain структура с описанием доменной сущности задачи на удаление коротких ссылок
+type DeleteJobDomain struct {
+	ID        string
+	UserID    string
+	Status    string
+	ShortURIs []string
+	Results   []DeleteShortURLResultDomain
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserSettingsDomain структура с описанием настроек пользователя
+type UserSettingsDomain struct {
+	UserID string
+	// FallbackURL - резервный адрес перенаправления для недоступных коротких ссылок пользователя по умолчанию
+	FallbackURL string
+}
+
+// SplitVariantStatsDomain структура с описанием количества переходов на вариант перенаправления A/B-теста
+//
+// Для вариантов, удаленных из короткой ссылки после переходов на них, URL пустой, а вес равен 0.
+type SplitVariantStatsDomain struct {
+	Name   string
+	URL    string
+	Weight int
+	Clicks int64
+}
+
+// CountryStatsDomain структура с описанием количества переходов по короткой ссылке из страны
+type CountryStatsDomain struct {
+	Country string
+	Clicks  int64
+}
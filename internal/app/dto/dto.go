@@ -1,8 +1,57 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
+)
+
+// APIShortURLMetadata структура с описанием задаваемых владельцем метаданных короткой ссылки
+type APIShortURLMetadata struct {
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Notes       string   `json:"notes,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// RedirectStatus - код ответа перенаправления: 301, 302, 307 или 308, 0 - код по умолчанию
+	RedirectStatus int `json:"redirect_status,omitempty"`
+	// QueryPassthrough - политика переноса параметров запроса перехода: keep_target, override или append,
+	// пустое значение - параметры не переносятся
+	QueryPassthrough string `json:"query_passthrough,omitempty"`
+	// PathPassthrough - перенос пути после идентификатора короткой ссылки в исходный URL
+	PathPassthrough bool `json:"path_passthrough,omitempty"`
+	// UTMParams - UTM-параметры, добавляемые в исходный URL при перенаправлении
+	UTMParams map[string]string `json:"utm_params,omitempty"`
+	// TargetingRules - правила выбора адреса перенаправления по платформе, устройству и языку клиента,
+	// применяется первое подходящее правило, иначе - исходный URL
+	TargetingRules []targeting.Rule `json:"targeting_rules,omitempty"`
+	// SplitVariants - варианты перенаправления A/B-теста с весами, посетитель закрепляется за вариантом
+	// с помощью cookie, правила таргетинга имеют приоритет над вариантами
+	SplitVariants []split.Variant `json:"split_variants,omitempty"`
+	// Password - пароль для перехода по короткой ссылке, хранится в виде хэша и в ответах не возвращается
+	Password string `json:"password,omitempty"`
+	// MaxClicks - максимальное количество переходов по короткой ссылке, после которого она становится недоступной,
+	// 0 - без ограничения
+	MaxClicks int `json:"max_clicks,omitempty"`
+	// NotBefore - время начала действия короткой ссылки, до него вместо перенаправления возвращается
+	// страница "ссылка еще не активна"
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	// NotAfter - время окончания действия короткой ссылки, после него короткая ссылка считается удаленной
+	NotAfter *time.Time `json:"not_after,omitempty"`
+	// Disabled - короткая ссылка заблокирована, переходы по ней не выполняются
+	Disabled bool `json:"disabled,omitempty"`
+	// FallbackURL - адрес перенаправления для удаленной, истекшей, исчерпавшей лимит переходов или заблокированной
+	// короткой ссылки, причина передается в параметре reason, "" - адрес из настроек пользователя
+	FallbackURL string `json:"fallback_url,omitempty"`
+	// ShortDomain - домен короткой ссылки из настроенных в сервисе, задается только при создании,
+	// "" - домен по умолчанию
+	ShortDomain string `json:"short_domain,omitempty"`
+}
+
 // APICreateShortURLRequest структура с описанием запроса на создание короткой ссылки
 type APICreateShortURLRequest struct {
 	URL string `json:"url"`
+	APIShortURLMetadata
 }
 
 // APICreateShortURLResponse структура с описанием ответа на запрос на создание короткой ссылки
@@ -19,6 +68,7 @@ type APICreateShortURLBatchRequest []APICreateShortURLBatchRequestEntry
 type APICreateShortURLBatchRequestEntry struct {
 	CorrelationID string `json:"correlation_id"`
 	OriginalURL   string `json:"original_url"`
+	APIShortURLMetadata
 }
 
 // APICreateShortURLBatchResponse слайс ответа на запрос на создание коротких ссылок пачкой
@@ -30,13 +80,128 @@ type APICreateShortURLBatchResponseEntry struct {
 	ShortURL      string `json:"short_url"`
 }
 
+// APIImportShortURLsResponse ответ на запрос загрузки коротких ссылок из выгрузки стороннего сервиса
+type APIImportShortURLsResponse struct {
+	Read        int                          `json:"read"`
+	Imported    int                          `json:"imported"`
+	KeptAliases int                          `json:"kept_aliases"`
+	DryRun      bool                         `json:"dry_run"`
+	Conflicts   []APIImportShortURLsConflict `json:"conflicts"`
+}
+
+// APIImportShortURLsConflict короткая ссылка, прежний идентификатор которой не сохранен
+type APIImportShortURLsConflict struct {
+	// Record - номер записи выгрузки, начиная с 1
+	Record    int    `json:"record"`
+	ShortCode string `json:"short_code"`
+	Reason    string `json:"reason"`
+	// ShortURL - короткая ссылка с новым идентификатором, отсутствует, если короткая ссылка не создана
+	ShortURL string `json:"short_url,omitempty"`
+}
+
 // APIGetAllURLByUserIDResponse слайс ответа на запрос на получение коротких ссылок пользователя
 type APIGetAllURLByUserIDResponse []APIGetAllURLByUserIDResponseEntry
 
 // APIGetAllURLByUserIDResponseEntry вхождение в слайс APIGetAllURLByUserIDResponse
 type APIGetAllURLByUserIDResponseEntry struct {
-	ShortURL    string `json:"short_url"`
-	OriginalURL string `json:"original_url"`
+	// ShortURI - идентификатор короткой ссылки в запросах на изменение, удаление и восстановление,
+	// для дополнительного домена имеет вид "<идентификатор>@<домен>"
+	ShortURI    string    `json:"short_uri"`
+	ShortURL    string    `json:"short_url"`
+	OriginalURL string    `json:"original_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	Deleted     bool      `json:"is_deleted"`
+	// PasswordProtected - для перехода по короткой ссылке требуется пароль
+	PasswordProtected bool `json:"password_protected"`
+	// ClickCount - количество переходов по короткой ссылке с ограничением количества переходов
+	ClickCount int64 `json:"click_count,omitempty"`
+	APIShortURLMetadata
+	Preview *APIShortURLPreview `json:"preview,omitempty"`
+}
+
+// APIShortURLPreview структура с описанием превью страницы исходного URL короткой ссылки
+type APIShortURLPreview struct {
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// APIUpdateShortURLRequest структура с описанием запроса на изменение короткой ссылки,
+// не переданные поля не изменяются
+type APIUpdateShortURLRequest struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Notes       *string   `json:"notes"`
+	Tags        *[]string `json:"tags"`
+	// RedirectStatus - код ответа перенаправления: 301, 302, 307 или 308, 0 - код по умолчанию
+	RedirectStatus *int `json:"redirect_status"`
+	// QueryPassthrough - политика переноса параметров запроса перехода: keep_target, override или append,
+	// пустое значение - параметры не переносятся
+	QueryPassthrough *string `json:"query_passthrough"`
+	// PathPassthrough - перенос пути после идентификатора короткой ссылки в исходный URL
+	PathPassthrough *bool `json:"path_passthrough"`
+	// UTMParams - UTM-параметры, добавляемые в исходный URL при перенаправлении
+	UTMParams *map[string]string `json:"utm_params"`
+	// TargetingRules - правила выбора адреса перенаправления по платформе, устройству и языку клиента,
+	// применяется первое подходящее правило, иначе - исходный URL
+	TargetingRules *[]targeting.Rule `json:"targeting_rules"`
+	// SplitVariants - варианты перенаправления A/B-теста с весами, посетитель закрепляется за вариантом
+	// с помощью cookie, правила таргетинга имеют приоритет над вариантами
+	SplitVariants *[]split.Variant `json:"split_variants"`
+	// Password - пароль для перехода по короткой ссылке, "" - пароль не требуется
+	Password *string `json:"password"`
+	// MaxClicks - максимальное количество переходов по короткой ссылке, 0 - без ограничения
+	MaxClicks *int `json:"max_clicks"`
+	// NotBefore - время начала действия короткой ссылки, "0001-01-01T00:00:00Z" - снять ограничение
+	NotBefore *time.Time `json:"not_before"`
+	// NotAfter - время окончания действия короткой ссылки, "0001-01-01T00:00:00Z" - снять ограничение
+	NotAfter *time.Time `json:"not_after"`
+	// Disabled - короткая ссылка заблокирована, переходы по ней не выполняются
+	Disabled *bool `json:"disabled"`
+	// FallbackURL - адрес перенаправления для недоступной короткой ссылки, "" - адрес из настроек пользователя
+	FallbackURL *string `json:"fallback_url"`
+}
+
+// APIGetTrashResponse слайс ответа на запрос на получение удаленных коротких ссылок пользователя
+type APIGetTrashResponse []APIGetTrashResponseEntry
+
+// APIGetTrashResponseEntry вхождение в слайс APIGetTrashResponse
+type APIGetTrashResponseEntry struct {
+	// ShortURI - идентификатор короткой ссылки в запросе на восстановление
+	ShortURI    string     `json:"short_uri"`
+	ShortURL    string     `json:"short_url"`
+	OriginalURL string     `json:"original_url"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+// APIRestoreShortURLsResponse слайс ответа на запрос на восстановление удаленных коротких ссылок
+type APIRestoreShortURLsResponse []APIRestoreShortURLsResponseEntry
+
+// APIRestoreShortURLsResponseEntry вхождение в слайс APIRestoreShortURLsResponse
+type APIRestoreShortURLsResponseEntry struct {
+	ShortURI string `json:"short_uri"`
+	Status   string `json:"status"`
+}
+
+// APIDeleteShortURLsResponse структура с описанием ответа на запрос на удаление коротких ссылок
+type APIDeleteShortURLsResponse struct {
+	JobID string `json:"job_id"`
+}
+
+// APIGetDeleteJobResponse структура с описанием ответа на запрос статуса задачи на удаление коротких ссылок
+type APIGetDeleteJobResponse struct {
+	JobID     string                         `json:"job_id"`
+	Status    string                         `json:"status"`
+	Results   []APIGetDeleteJobResponseEntry `json:"results"`
+	CreatedAt time.Time                      `json:"created_at"`
+	UpdatedAt time.Time                      `json:"updated_at"`
+}
+
+// APIGetDeleteJobResponseEntry вхождение в слайс APIGetDeleteJobResponse.Results
+type APIGetDeleteJobResponseEntry struct {
+	ShortURI string `json:"short_uri"`
+	Status   string `json:"status"`
 }
 
 // APIInternalGetStatsResponse ответ на запрос статистики
@@ -44,3 +209,58 @@ type APIInternalGetStatsResponse struct {
 	URLCount  int `json:"urls"`
 	UserCount int `json:"users"`
 }
+
+// APIInternalImportResponse ответ на запрос загрузки коротких ссылок
+type APIInternalImportResponse struct {
+	Read      int  `json:"read"`
+	Imported  int  `json:"imported"`
+	Existing  int  `json:"existing"`
+	Conflicts int  `json:"conflicts"`
+	DryRun    bool `json:"dry_run"`
+}
+
+// APIInternalGetDBStatsResponse ответ на запрос статистики пула соединений с базой данных и кэша коротких ссылок
+//
+// CacheHitCount и CacheMissCount равны 0, если кэш коротких ссылок не используется.
+type APIInternalGetDBStatsResponse struct {
+	MaxConns             int64 `json:"max_conns"`
+	TotalConns           int64 `json:"total_conns"`
+	InUseConns           int64 `json:"in_use_conns"`
+	IdleConns            int64 `json:"idle_conns"`
+	WaitCount            int64 `json:"wait_count"`
+	CanceledAcquireCount int64 `json:"canceled_acquire_count"`
+	ReadRetryCount       int64 `json:"read_retry_count"`
+	ReplicaCount         int64 `json:"replica_count"`
+	HealthyReplicaCount  int64 `json:"healthy_replica_count"`
+	ReplicaFallbackCount int64 `json:"replica_fallback_count"`
+	CacheHitCount        int64 `json:"cache_hit_count"`
+	CacheMissCount       int64 `json:"cache_miss_count"`
+}
+
+// APIUserSettings структура с описанием настроек пользователя
+type APIUserSettings struct {
+	// FallbackURL - адрес перенаправления по умолчанию для недоступных коротких ссылок пользователя,
+	// "" - адрес из конфигурации сервиса
+	FallbackURL string `json:"fallback_url"`
+}
+
+// APIGetSplitStatsResponse слайс ответа на запрос количества переходов по вариантам перенаправления A/B-теста
+type APIGetSplitStatsResponse []APIGetSplitStatsResponseEntry
+
+// APIGetSplitStatsResponseEntry вхождение в слайс APIGetSplitStatsResponse
+type APIGetSplitStatsResponseEntry struct {
+	Name   string `json:"name"`
+	URL    string `json:"url,omitempty"`
+	Weight int    `json:"weight"`
+	Clicks int64  `json:"clicks"`
+}
+
+// APIGetCountryStatsResponse слайс ответа на запрос количества переходов по короткой ссылке по странам
+type APIGetCountryStatsResponse []APIGetCountryStatsResponseEntry
+
+// APIGetCountryStatsResponseEntry вхождение в слайс APIGetCountryStatsResponse
+type APIGetCountryStatsResponseEntry struct {
+	// Country - код страны ISO 3166-1 alpha-2, "ZZ" - страна не определена
+	Country string `json:"country"`
+	Clicks  int64  `json:"clicks"`
+}
@@ -1,10 +1,176 @@
 package entity
 
+import (
+	"time"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
+)
+
+// DeleteStatusDeleted - короткая ссылка удалена
+// DeleteStatusNotFound - короткая ссылка не найдена
+// DeleteStatusNotOwner - короткая ссылка принадлежит другому пользователю
+const (
+	DeleteStatusDeleted  = "deleted"
+	DeleteStatusNotFound = "not_found"
+	DeleteStatusNotOwner = "not_owner"
+)
+
+// RestoreStatusRestored - короткая ссылка восстановлена
+// RestoreStatusNotFound - короткая ссылка не найдена
+// RestoreStatusNotOwner - короткая ссылка принадлежит другому пользователю
+// RestoreStatusNotDeleted - короткая ссылка не удалена
+const (
+	RestoreStatusRestored   = "restored"
+	RestoreStatusNotFound   = "not_found"
+	RestoreStatusNotOwner   = "not_owner"
+	RestoreStatusNotDeleted = "not_deleted"
+)
+
+// DeleteJobStatusPending - задача на удаление ожидает выполнения
+// DeleteJobStatusDone - задача на удаление выполнена
+// DeleteJobStatusFailed - задача на удаление завершилась ошибкой
+const (
+	DeleteJobStatusPending = "pending"
+	DeleteJobStatusDone    = "done"
+	DeleteJobStatusFailed  = "failed"
+)
+
+// ShortURLStatusAll - все короткие ссылки
+// ShortURLStatusActive - только не удаленные короткие ссылки
+// ShortURLStatusDeleted - только удаленные короткие ссылки
+const (
+	ShortURLStatusAll     = "all"
+	ShortURLStatusActive  = "active"
+	ShortURLStatusDeleted = "deleted"
+)
+
+// ShortURLSortByCreatedAt - сортировка коротких ссылок по дате создания
+// ShortURLSortByOriginalURL - сортировка коротких ссылок по исходному URL
+// ShortURLSortByShortURL - сортировка коротких ссылок по идентификатору короткой ссылки
+const (
+	ShortURLSortByCreatedAt   = "created_at"
+	ShortURLSortByOriginalURL = "original_url"
+	ShortURLSortByShortURL    = "short_url"
+)
+
 // ShortURLEntity структура с описанием сущности ShortURL для хранения в репозитории
 type ShortURLEntity struct {
-	UUID     string `json:"uuid"`
+	UUID      string     `json:"uuid"`
+	ShortURI  string     `json:"short_url"`
+	LongURL   string     `json:"original_url"`
+	UserID    string     `json:"user_id"`
+	Deleted   bool       `json:"is_deleted"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Notes       string   `json:"notes,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// RedirectStatus - код ответа перенаправления по короткой ссылке, 0 - код по умолчанию из конфигурации
+	RedirectStatus int `json:"redirect_status,omitempty"`
+	// QueryPassthrough - политика переноса параметров запроса перехода в исходный URL, "" - параметры не переносятся
+	QueryPassthrough string `json:"query_passthrough,omitempty"`
+	// PathPassthrough - перенос пути после идентификатора короткой ссылки в исходный URL
+	PathPassthrough bool `json:"path_passthrough,omitempty"`
+	// UTMParams - UTM-параметры, добавляемые в исходный URL при перенаправлении
+	UTMParams map[string]string `json:"utm_params,omitempty"`
+	// TargetingRules - правила выбора адреса перенаправления по платформе, устройству и языку клиента
+	TargetingRules []targeting.Rule `json:"targeting_rules,omitempty"`
+	// SplitVariants - варианты перенаправления A/B-теста, пустой список - перенаправление на исходный URL
+	SplitVariants []split.Variant `json:"split_variants,omitempty"`
+	// PasswordHash - bcrypt-хэш пароля для перехода по короткой ссылке, "" - пароль не требуется
+	PasswordHash string `json:"password_hash,omitempty"`
+	// MaxClicks - максимальное количество переходов по короткой ссылке, 0 - без ограничения
+	MaxClicks int `json:"max_clicks,omitempty"`
+	// ClickCount - количество учтенных переходов по короткой ссылке с ограничением количества переходов
+	ClickCount int64 `json:"click_count,omitempty"`
+	// NotBefore - время начала действия короткой ссылки, nil - ссылка действует с момента создания
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	// NotAfter - время окончания действия короткой ссылки, nil - ссылка действует бессрочно
+	NotAfter *time.Time `json:"not_after,omitempty"`
+	// Disabled - короткая ссылка заблокирована владельцем, переходы по ней не выполняются
+	Disabled bool `json:"is_disabled,omitempty"`
+	// FallbackURL - резервный адрес перенаправления для недоступной короткой ссылки, "" - адрес из настроек пользователя
+	FallbackURL string `json:"fallback_url,omitempty"`
+
+	PreviewTitle       string     `json:"preview_title,omitempty"`
+	PreviewDescription string     `json:"preview_description,omitempty"`
+	PreviewImageURL    string     `json:"preview_image_url,omitempty"`
+	PreviewFetchedAt   *time.Time `json:"preview_fetched_at,omitempty"`
+}
+
+// ShortURLPreviewEntity структура с описанием превью страницы исходного URL короткой ссылки
+type ShortURLPreviewEntity struct {
+	Title       string
+	Description string
+	ImageURL    string
+	FetchedAt   time.Time
+}
+
+// ShortURLQueryEntity структура с описанием запроса на получение страницы коротких ссылок пользователя
+type ShortURLQueryEntity struct {
+	UserID      string
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Domain      string
+	Search      string
+	Tag         string
+	SortBy      string
+	SortDesc    bool
+	After       *ShortURLCursorEntity
+	Limit       int
+}
+
+// ShortURLCursorEntity структура с описанием позиции в упорядоченном списке коротких ссылок:
+// значение поля сортировки и UUID последней полученной короткой ссылки
+type ShortURLCursorEntity struct {
+	SortValue string
+	UUID      string
+}
+
+// DeleteShortURLResultEntity структура с описанием результата удаления короткой ссылки
+type DeleteShortURLResultEntity struct {
+	ShortURI string `json:"short_url"`
+	Status   string `json:"status"`
+}
+
+// RestoreShortURLResultEntity структура с описанием результата восстановления короткой ссылки
+type RestoreShortURLResultEntity struct {
+	ShortURI string `json:"short_url"`
+	Status   string `json:"status"`
+}
+
+// DeleteJobEntity структура с описанием задачи на удаление коротких ссылок для хранения в репозитории
+type DeleteJobEntity struct {
+	ID        string                       `json:"id"`
+	UserID    string                       `json:"user_id"`
+	Status    string                       `json:"status"`
+	ShortURIs []string                     `json:"short_urls"`
+	Results   []DeleteShortURLResultEntity `json:"results"`
+	CreatedAt time.Time                    `json:"created_at"`
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
+
+// UserSettingsEntity структура с описанием настроек пользователя для хранения в репозитории
+type UserSettingsEntity struct {
+	UserID string `json:"user_id"`
+	// FallbackURL - резервный адрес перенаправления для недоступных коротких ссылок пользователя по умолчанию
+	FallbackURL string `json:"fallback_url,omitempty"`
+}
+
+// SplitClicksEntity структура с описанием количества переходов по короткой ссылке на вариант перенаправления
+type SplitClicksEntity struct {
+	ShortURI string `json:"short_url"`
+	Variant  string `json:"variant"`
+	Clicks   int64  `json:"clicks"`
+}
+
+// CountryClicksEntity структура с описанием количества переходов по короткой ссылке из страны
+type CountryClicksEntity struct {
 	ShortURI string `json:"short_url"`
-	LongURL  string `json:"original_url"`
-	UserID   string `json:"user_id"`
-	Deleted  bool   `json:"is_deleted"`
+	Country  string `json:"country"`
+	Clicks   int64  `json:"clicks"`
 }
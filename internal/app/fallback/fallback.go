@@ -0,0 +1,65 @@
+// Package fallback формирует резервный адрес перенаправления для короткой ссылки, которая недоступна:
+// удалена, истекла, исчерпала лимит переходов или заблокирована
+package fallback
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ErrInvalidURL - некорректный резервный адрес перенаправления
+var ErrInvalidURL = errors.New("invalid fallback url")
+
+// URLMaxLength - максимальная длина резервного адреса перенаправления
+const URLMaxLength = 2048
+
+// ReasonParam - имя параметра запроса резервного адреса, в котором передается причина недоступности ссылки
+const ReasonParam = "reason"
+
+// ReasonDeleted - короткая ссылка удалена
+// ReasonExpired - срок действия короткой ссылки истек
+// ReasonExhausted - исчерпан лимит переходов по короткой ссылке
+// ReasonDisabled - короткая ссылка заблокирована владельцем
+const (
+	ReasonDeleted   = "deleted"
+	ReasonExpired   = "expired"
+	ReasonExhausted = "exhausted"
+	ReasonDisabled  = "disabled"
+)
+
+// CheckURL проверяет, что резервный адрес является абсолютным http(s) адресом допустимой длины
+func CheckURL(rawURL string) error {
+	if len(rawURL) > URLMaxLength {
+		return fmt.Errorf("%w: url is longer than %d", ErrInvalidURL, URLMaxLength)
+	}
+
+	parsedURL, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return fmt.Errorf("%w: url %q must be absolute http(s) url", ErrInvalidURL, rawURL)
+	}
+
+	return nil
+}
+
+// BuildURL возвращает резервный адрес с причиной недоступности ссылки в параметре ReasonParam,
+// значение параметра с тем же именем в резервном адресе заменяется
+func BuildURL(fallbackURL string, reason string) (string, error) {
+	if err := CheckURL(fallbackURL); err != nil {
+		return "", err
+	}
+
+	parsedURL, err := url.Parse(fallbackURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	query := parsedURL.Query()
+	query.Set(ReasonParam, reason)
+	parsedURL.RawQuery = query.Encode()
+
+	return parsedURL.String(), nil
+}
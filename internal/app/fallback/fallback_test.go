@@ -0,0 +1,106 @@
+package fallback
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		url         string
+		expectedErr error
+	}{
+		{
+			name: "https url",
+			url:  "https://example.com/gone",
+		},
+		{
+			name: "http url with query",
+			url:  "http://example.com/gone?lang=ru",
+		},
+		{
+			name:        "empty url",
+			url:         "",
+			expectedErr: ErrInvalidURL,
+		},
+		{
+			name:        "relative url",
+			url:         "/gone",
+			expectedErr: ErrInvalidURL,
+		},
+		{
+			name:        "unsupported scheme",
+			url:         "ftp://example.com/gone",
+			expectedErr: ErrInvalidURL,
+		},
+		{
+			name:        "too long url",
+			url:         "https://example.com/" + strings.Repeat("a", URLMaxLength),
+			expectedErr: ErrInvalidURL,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckURL(tc.url)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		url         string
+		reason      string
+		expectedURL string
+		expectedErr error
+	}{
+		{
+			name:        "reason added",
+			url:         "https://example.com/gone",
+			reason:      ReasonDeleted,
+			expectedURL: "https://example.com/gone?reason=deleted",
+		},
+		{
+			name:        "query kept",
+			url:         "https://example.com/gone?lang=ru#top",
+			reason:      ReasonExpired,
+			expectedURL: "https://example.com/gone?lang=ru&reason=expired#top",
+		},
+		{
+			name:        "reason replaced",
+			url:         "https://example.com/gone?reason=other",
+			reason:      ReasonExhausted,
+			expectedURL: "https://example.com/gone?reason=exhausted",
+		},
+		{
+			name:        "invalid url",
+			url:         "example.com/gone",
+			reason:      ReasonDisabled,
+			expectedErr: ErrInvalidURL,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualURL, err := BuildURL(tc.url, tc.reason)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedURL, actualURL)
+		})
+	}
+}
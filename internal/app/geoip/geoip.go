@@ -0,0 +1,136 @@
+// Package geoip определяет страну клиента по IP-адресу с помощью локальной базы данных в формате MaxMind DB
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"go.uber.org/zap"
+)
+
+var log = zap.Must(zap.NewDevelopment()).Sugar()
+
+// countryRecord - часть записи баз GeoIP2/GeoLite2 Country и City, содержащая код страны
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// Resolver определяет страну по IP-адресу
+//
+// Файл базы данных перечитывается при изменении, если запущен Run. Файл следует заменять атомарно
+// (переименованием), так как открытая база данных отображается в память.
+type Resolver struct {
+	path string
+
+	mu      sync.RWMutex
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+}
+
+// NewResolver создает экземпляр Resolver и открывает файл базы данных по пути path
+func NewResolver(path string) (*Resolver, error) {
+	resolver := &Resolver{path: path}
+	if _, err := resolver.Reload(); err != nil {
+		return nil, err
+	}
+
+	return resolver, nil
+}
+
+// Country возвращает код страны ISO 3166-1 alpha-2 для IP-адреса addr,
+// пустая строка - страна не определена
+func (r *Resolver) Country(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var record countryRecord
+	if err := r.reader.Lookup(net.IP(addr.Unmap().AsSlice()), &record); err != nil {
+		log.Debugw("geoip: error when lookup address", "addr", addr, "err", err)
+		return ""
+	}
+	if record.Country.ISOCode != "" {
+		return record.Country.ISOCode
+	}
+
+	return record.RegisteredCountry.ISOCode
+}
+
+// Reload открывает файл базы данных заново, если он изменился с момента предыдущего открытия
+//
+// При ошибке продолжает использоваться ранее открытая база данных.
+func (r *Resolver) Reload() (bool, error) {
+	fileInfo, err := os.Stat(r.path)
+	if err != nil {
+		return false, fmt.Errorf("geoip: error when stat database file: %w", err)
+	}
+
+	r.mu.RLock()
+	changed := r.reader == nil || !fileInfo.ModTime().Equal(r.modTime) || fileInfo.Size() != r.size
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	reader, err := maxminddb.Open(r.path)
+	if err != nil {
+		return false, fmt.Errorf("geoip: error when open database file: %w", err)
+	}
+
+	r.mu.Lock()
+	previousReader := r.reader
+	r.reader = reader
+	r.modTime = fileInfo.ModTime()
+	r.size = fileInfo.Size()
+	r.mu.Unlock()
+
+	if previousReader != nil {
+		if err := previousReader.Close(); err != nil {
+			log.Warnw("geoip: error when close previous database", "err", err)
+		}
+	}
+
+	log.Infow("geoip: database loaded", "path", r.path, "type", reader.Metadata.DatabaseType)
+
+	return true, nil
+}
+
+// Run проверяет изменение файла базы данных с интервалом interval до отмены контекста
+func (r *Resolver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reload(); err != nil {
+				log.Errorw("geoip: failed to reload database", "err", err)
+			}
+		}
+	}
+}
+
+// Close закрывает файл базы данных
+func (r *Resolver) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reader.Close()
+}
@@ -0,0 +1,78 @@
+package geoip
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabase - база данных в формате MaxMind DB с сетями 81.2.69.0/24 и 2.125.160.0/24 (GB),
+// 89.160.20.0/24 (SE) и 175.16.199.0/24 (CN)
+const testDatabase = "testdata/GeoIP2-Country-Test.mmdb"
+
+func TestResolver_Country(t *testing.T) {
+	resolver, err := NewResolver(testDatabase)
+	require.NoError(t, err)
+	defer resolver.Close()
+
+	testCases := []struct {
+		addr    string
+		country string
+	}{
+		{addr: "81.2.69.142", country: "GB"},
+		{addr: "2.125.160.216", country: "GB"},
+		{addr: "89.160.20.112", country: "SE"},
+		{addr: "::ffff:175.16.199.10", country: "CN"},
+		{addr: "8.8.8.8", country: ""},
+		{addr: "2001:db8::1", country: ""},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.country, resolver.Country(netip.MustParseAddr(tc.addr)), "not expected country for %s", tc.addr)
+	}
+	assert.Empty(t, resolver.Country(netip.Addr{}))
+}
+
+func TestResolver_Reload(t *testing.T) {
+	database, err := os.ReadFile(testDatabase)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "GeoIP2-Country.mmdb")
+	require.NoError(t, os.WriteFile(path, database, 0o644))
+
+	resolver, err := NewResolver(path)
+	require.NoError(t, err)
+	defer resolver.Close()
+
+	reloaded, err := resolver.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded, "not changed database must not be reloaded")
+
+	// файл заменяется переименованием, как при обновлении базы данных
+	newPath := path + ".new"
+	require.NoError(t, os.WriteFile(newPath, database, 0o644))
+	require.NoError(t, os.Chtimes(newPath, time.Now(), time.Now().Add(time.Hour)))
+	require.NoError(t, os.Rename(newPath, path))
+
+	reloaded, err = resolver.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded, "changed database must be reloaded")
+	assert.Equal(t, "SE", resolver.Country(netip.MustParseAddr("89.160.20.112")))
+
+	require.NoError(t, os.WriteFile(newPath, []byte("not a database"), 0o644))
+	require.NoError(t, os.Rename(newPath, path))
+
+	_, err = resolver.Reload()
+	assert.Error(t, err, "invalid database must not be loaded")
+	assert.Equal(t, "SE", resolver.Country(netip.MustParseAddr("89.160.20.112")), "previous database must be used")
+}
+
+func TestNewResolver_not_exists(t *testing.T) {
+	_, err := NewResolver(filepath.Join(t.TempDir(), "not_exists.mmdb"))
+	assert.Error(t, err)
+}
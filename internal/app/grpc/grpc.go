@@ -3,31 +3,62 @@ package grpc
 import (
 	"context"
 	"errors"
+	"github.com/google/uuid"
 	pb "github.com/vkhrushchev/urlshortener/grpc"
 	"github.com/vkhrushchev/urlshortener/internal/app/db"
 	"github.com/vkhrushchev/urlshortener/internal/app/domain"
+	"github.com/vkhrushchev/urlshortener/internal/app/qr"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
 	"github.com/vkhrushchev/urlshortener/internal/app/usecase"
 	"github.com/vkhrushchev/urlshortener/internal/common"
-	"github.com/vkhrushchev/urlshortener/internal/util"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"net/netip"
+	"time"
 )
 
 var log = zap.Must(zap.NewDevelopment()).Sugar()
 
 type shortURLCreator interface {
-	CreateShortURL(ctx context.Context, url string) (domain.ShortURLDomain, error)
+	CreateShortURL(ctx context.Context, url string, metadata domain.ShortURLMetadataDomain) (domain.ShortURLDomain, error)
 	CreateShortURLBatch(ctx context.Context, createShortURLBatchDomains []domain.CreateShortURLBatchDomain) ([]domain.CreateShortURLBatchResultDomain, error)
 }
 
 type shortURLProvider interface {
 	GetShortURLByShortURI(ctx context.Context, shortURI string) (domain.ShortURLDomain, error)
 	GetShortURLsByUserID(ctx context.Context, userID string) ([]domain.ShortURLDomain, error)
+	GetShortURLsByQuery(ctx context.Context, query domain.ShortURLQueryDomain) (domain.ShortURLPageDomain, error)
+	GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]domain.ShortURLDomain, error)
+	ConsumeShortURLClick(ctx context.Context, shortURI string) error
+}
+
+type shortURLUnlocker interface {
+	UnlockShortURL(ctx context.Context, shortURI string, password string, client string) (domain.ShortURLDomain, error)
+}
+
+type shortURLUpdater interface {
+	UpdateShortURL(ctx context.Context, shortURI string, patch domain.ShortURLPatchDomain) (domain.ShortURLDomain, error)
 }
 
 type shortURLDeleter interface {
-	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) error
+	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) (domain.DeleteJobDomain, error)
+	GetDeleteJob(ctx context.Context, jobID string) (domain.DeleteJobDomain, error)
+	RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]domain.RestoreShortURLResultDomain, error)
+}
+
+type splitStatsProvider interface {
+	GetSplitStats(ctx context.Context, shortURI string) ([]domain.SplitVariantStatsDomain, error)
+}
+
+type userSettingsProvider interface {
+	GetUserSettings(ctx context.Context) (domain.UserSettingsDomain, error)
+	UpdateUserSettings(ctx context.Context, userSettings domain.UserSettingsDomain) (domain.UserSettingsDomain, error)
 }
 
 type statsProvider interface {
@@ -38,34 +69,68 @@ type ShortenerServiceServerImpl struct {
 	pb.UnimplementedShortenerServiceServer
 	shortURLCreator  shortURLCreator
 	shortURLProvider shortURLProvider
+	shortURLUnlocker shortURLUnlocker
+	shortURLUpdater  shortURLUpdater
 	shortURLDeleter  shortURLDeleter
+	splitStats       splitStatsProvider
+	userSettings     userSettingsProvider
 	statsProvider    statsProvider
 	dbLookup         *db.DBLookup
-	baseURL          string
+	domains          *shortdomain.Domains
 }
 
 func NewShortenerServiceServer(
 	shortURLCreator shortURLCreator,
 	shortURLProvider shortURLProvider,
+	shortURLUnlocker shortURLUnlocker,
+	shortURLUpdater shortURLUpdater,
 	shortURLDeleter shortURLDeleter,
+	splitStats splitStatsProvider,
+	userSettings userSettingsProvider,
 	statsProvider statsProvider,
 	dbLookup *db.DBLookup,
-	baseURL string) *ShortenerServiceServerImpl {
+	domains *shortdomain.Domains) *ShortenerServiceServerImpl {
 	return &ShortenerServiceServerImpl{
 		shortURLCreator:  shortURLCreator,
 		shortURLProvider: shortURLProvider,
+		shortURLUnlocker: shortURLUnlocker,
+		shortURLUpdater:  shortURLUpdater,
 		shortURLDeleter:  shortURLDeleter,
+		splitStats:       splitStats,
+		userSettings:     userSettings,
 		statsProvider:    statsProvider,
 		dbLookup:         dbLookup,
-		baseURL:          baseURL,
+		domains:          domains,
 	}
 }
 
 func (s *ShortenerServiceServerImpl) CreateShortURL(ctx context.Context, request *pb.CreateShortURLRequest) (*pb.CreateShortURLResponse, error) {
 	log.Infow("grpc: CreateShortURL", "original_url", request.OriginalUrl)
 
-	shortURLDomain, err := s.shortURLCreator.CreateShortURL(ctx, request.OriginalUrl)
-	if err != nil && errors.Is(err, usecase.ErrConflict) {
+	metadata := domain.ShortURLMetadataDomain{
+		Title:       request.Title,
+		Description: request.Description,
+		Notes:       request.Notes,
+		Tags:        request.Tags,
+
+		RedirectStatus:   int(request.RedirectStatus),
+		QueryPassthrough: request.QueryPassthrough,
+		PathPassthrough:  request.PathPassthrough,
+		UTMParams:        request.UtmParams,
+		TargetingRules:   toTargetingRules(request.TargetingRules),
+		SplitVariants:    toSplitVariants(request.SplitVariants),
+		Password:         request.Password,
+		MaxClicks:        int(request.MaxClicks),
+		NotBefore:        fromUnixTime(request.NotBefore),
+		NotAfter:         fromUnixTime(request.NotAfter),
+		Disabled:         request.Disabled,
+		FallbackURL:      request.FallbackUrl,
+		ShortDomain:      request.ShortDomain,
+	}
+	shortURLDomain, err := s.shortURLCreator.CreateShortURL(ctx, request.OriginalUrl, metadata)
+	if err != nil && errors.Is(err, usecase.ErrInvalidMetadata) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid short url metadata: %v", err)
+	} else if err != nil && errors.Is(err, usecase.ErrConflict) {
 		log.Infow("grpc: short URL already exists", "original_url", request.OriginalUrl)
 		return nil, status.Errorf(codes.AlreadyExists, "short url already exists: %v", err)
 	} else if err != nil {
@@ -75,7 +140,7 @@ func (s *ShortenerServiceServerImpl) CreateShortURL(ctx context.Context, request
 
 	response := &pb.CreateShortURLResponse{
 		ShortUri: shortURLDomain.ShortURI,
-		ShortUrl: util.GetShortURL(s.baseURL, shortURLDomain.ShortURI),
+		ShortUrl: s.domains.ShortURL(shortURLDomain.ShortURI),
 	}
 
 	return response, nil
@@ -84,18 +149,87 @@ func (s *ShortenerServiceServerImpl) CreateShortURL(ctx context.Context, request
 func (s *ShortenerServiceServerImpl) GetShortURL(ctx context.Context, request *pb.GetShortURLRequest) (*pb.GetShortURLResponse, error) {
 	log.Infow("grpc: GetShortURL", "short_uri", request.ShortUri)
 
-	shortURLDomain, err := s.shortURLProvider.GetShortURLByShortURI(ctx, request.ShortUri)
+	shortURLDomain, err := s.shortURLUnlocker.UnlockShortURL(ctx, request.ShortUri, request.Password, clientAddr(ctx))
 	if err != nil && errors.Is(err, usecase.ErrNotFound) {
 		log.Infow("grpc: short URL not found", "short_uri", request.ShortUri)
 		return nil, status.Errorf(codes.NotFound, "short url not found: %v", err)
+	} else if err != nil && (errors.Is(err, usecase.ErrPasswordRequired) || errors.Is(err, usecase.ErrInvalidPassword)) {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot get short url: %v", err)
+	} else if err != nil && errors.Is(err, usecase.ErrTooManyAttempts) {
+		return nil, status.Errorf(codes.ResourceExhausted, "cannot get short url: %v", err)
 	} else if err != nil {
 		log.Errorw("grpc: GetShortURL failed", "error", err)
 		return nil, status.Errorf(codes.Internal, "cannot get short url: %v", err)
 	}
 
+	// полная ссылка удаленной или заблокированной короткой ссылки не раскрывается
+	if shortURLDomain.Deleted || shortURLDomain.Disabled {
+		return nil, status.Errorf(codes.NotFound, "short url is deleted or disabled")
+	}
+
+	err = usecase.CheckActivityWindow(shortURLDomain, time.Now())
+	if err != nil && errors.Is(err, usecase.ErrNotYetActive) {
+		return nil, status.Errorf(codes.FailedPrecondition, "short url is not active yet: %v", err)
+	} else if err != nil {
+		return nil, status.Errorf(codes.NotFound, "short url expired: %v", err)
+	}
+
+	// получение полной ссылки расходует переход так же, как перенаправление
+	if shortURLDomain.MaxClicks > 0 {
+		err = s.shortURLProvider.ConsumeShortURLClick(ctx, request.ShortUri)
+		if err != nil && (errors.Is(err, usecase.ErrClicksExhausted) || errors.Is(err, usecase.ErrNotFound)) {
+			return nil, status.Errorf(codes.NotFound, "short url clicks exhausted: %v", err)
+		} else if err != nil {
+			log.Errorw("grpc: GetShortURL failed", "error", err)
+			return nil, status.Errorf(codes.Internal, "cannot get short url: %v", err)
+		}
+	}
+
 	response := &pb.GetShortURLResponse{
-		ShortUri: shortURLDomain.ShortURI,
-		ShortUrl: util.GetShortURL(s.baseURL, shortURLDomain.ShortURI),
+		ShortUri:    shortURLDomain.ShortURI,
+		ShortUrl:    s.domains.ShortURL(shortURLDomain.ShortURI),
+		OriginalUrl: shortURLDomain.LongURL,
+	}
+
+	return response, nil
+}
+
+func (s *ShortenerServiceServerImpl) GetShortURLQRCode(ctx context.Context, request *pb.GetShortURLQRCodeRequest) (*pb.GetShortURLQRCodeResponse, error) {
+	log.Infow("grpc: GetShortURLQRCode", "short_uri", request.ShortUri)
+
+	var margin *int
+	if request.Margin != nil {
+		marginValue := int(request.GetMargin())
+		margin = &marginValue
+	}
+	qrOptions, err := qr.NewOptions(request.Format, int(request.Size), margin, request.Level, request.Foreground, request.Background)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot GetShortURLQRCode: %v", err)
+	}
+
+	shortURLDomain, err := s.shortURLProvider.GetShortURLByShortURI(ctx, request.ShortUri)
+	if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		log.Infow("grpc: short URL not found", "short_uri", request.ShortUri)
+		return nil, status.Errorf(codes.NotFound, "short url not found: %v", err)
+	} else if err != nil {
+		log.Errorw("grpc: GetShortURLQRCode failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot get short url: %v", err)
+	}
+	if shortURLDomain.Deleted {
+		return nil, status.Errorf(codes.NotFound, "short url deleted")
+	}
+
+	qrImage, err := qr.Render(s.domains.ShortURL(shortURLDomain.ShortURI), qrOptions)
+	if err != nil && errors.Is(err, qr.ErrInvalidOptions) {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot GetShortURLQRCode: %v", err)
+	} else if err != nil {
+		log.Errorw("grpc: GetShortURLQRCode failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot GetShortURLQRCode: %v", err)
+	}
+
+	response := &pb.GetShortURLQRCodeResponse{
+		Image:       qrImage,
+		ContentType: qrOptions.ContentType(),
 	}
 
 	return response, nil
@@ -106,14 +240,15 @@ func (s *ShortenerServiceServerImpl) CreateShortURLBatch(ctx context.Context, re
 
 	createShortURLBatchDomains := make([]domain.CreateShortURLBatchDomain, 0, len(request.Entries))
 	for _, entry := range request.Entries {
-		createShortURLBatchDomains = append(createShortURLBatchDomains, domain.CreateShortURLBatchDomain{
-			CorrelationUUID: entry.CorrelationId,
-			LongURL:         entry.OriginalUrl,
-		})
+		createShortURLBatchDomains = append(createShortURLBatchDomains, toCreateShortURLBatchDomain(entry))
 	}
 
 	createShortURLBatchResultDomains, err := s.shortURLCreator.CreateShortURLBatch(ctx, createShortURLBatchDomains)
-	if err != nil {
+	if err != nil && errors.Is(err, usecase.ErrInvalidMetadata) {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot CreateShortURLBatch: %v", err)
+	} else if err != nil && errors.Is(err, usecase.ErrConflict) {
+		return nil, status.Errorf(codes.AlreadyExists, "cannot CreateShortURLBatch: %v", err)
+	} else if err != nil {
 		log.Errorw("grpc: CreateShortURLBatch failed", "error", err)
 		return nil, status.Errorf(codes.Internal, "cannot CreateShortURLBatch: %v", err)
 	}
@@ -122,7 +257,8 @@ func (s *ShortenerServiceServerImpl) CreateShortURLBatch(ctx context.Context, re
 	for _, createShortURLBatchResultDomain := range createShortURLBatchResultDomains {
 		createShortURLBatchResponseEntries = append(createShortURLBatchResponseEntries, &pb.CreateShortURLBatchResponse_CreateShortURLBatchResponseEntry{
 			CorrelationId: createShortURLBatchResultDomain.CorrelationUUID,
-			ShortUrl:      util.GetShortURL(s.baseURL, createShortURLBatchResultDomain.ShortURI),
+			ShortUrl:      s.domains.ShortURL(createShortURLBatchResultDomain.ShortURI),
+			ShortUri:      createShortURLBatchResultDomain.ShortURI,
 		})
 	}
 	createShortURLBatchResponse := &pb.CreateShortURLBatchResponse{
@@ -132,34 +268,518 @@ func (s *ShortenerServiceServerImpl) CreateShortURLBatch(ctx context.Context, re
 	return createShortURLBatchResponse, nil
 }
 
+// streamBatchChunkSize - максимальное количество коротких ссылок, сохраняемых CreateShortURLBatchStream в одной транзакции
+const streamBatchChunkSize = 100
+
+// streamBatchRequest - запрос потока CreateShortURLBatchStream или ошибка его чтения
+type streamBatchRequest struct {
+	request *pb.CreateShortURLBatchStreamRequest
+	err     error
+}
+
+// streamBatchEntry - короткая ссылка, ожидающая сохранения в потоке CreateShortURLBatchStream
+type streamBatchEntry struct {
+	createShortURLBatchDomain domain.CreateShortURLBatchDomain
+	// err - ошибка короткой ссылки, отклоненной до сохранения
+	err error
+}
+
+// CreateShortURLBatchStream создает короткие ссылки, получаемые из потока, и отправляет результат по каждой ссылке
+// по мере сохранения
+//
+// Короткие ссылки сохраняются пачками не более streamBatchChunkSize в одной транзакции. Пачка сохраняется, как только
+// накоплено streamBatchChunkSize ссылок или клиент перестал присылать запросы. Запросы читаются из потока заранее
+// не более чем на streamBatchChunkSize сообщений: пока пачка сохраняется, чтение останавливается и управление потоком
+// gRPC приостанавливает отправку у клиента.
+//
+// Ошибка отдельной короткой ссылки возвращается в ее результате и не прерывает сохранение остальных ссылок пачки.
+// correlation_id, повторяющий идентификатор предыдущей ссылки потока, отклоняется с ошибкой InvalidArgument.
+// После закрытия потока клиентом отправляется итог.
+func (s *ShortenerServiceServerImpl) CreateShortURLBatchStream(stream pb.ShortenerService_CreateShortURLBatchStreamServer) error {
+	ctx := stream.Context()
+	log.Infow("grpc: CreateShortURLBatchStream")
+
+	requests := make(chan streamBatchRequest, streamBatchChunkSize)
+	go func() {
+		defer close(requests)
+		for {
+			request, err := stream.Recv()
+			select {
+			case requests <- streamBatchRequest{request: request, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	summary := &pb.CreateShortURLBatchStreamResponse_Summary{}
+	pending := make([]streamBatchEntry, 0, streamBatchChunkSize)
+	correlationIDs := make(map[string]bool)
+	for {
+		received, ok, err := receiveStreamBatchRequest(ctx, requests, len(pending) == 0)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// клиент перестал присылать запросы, накопленные короткие ссылки сохраняются не дожидаясь заполнения пачки
+			if err := s.saveStreamBatchChunk(ctx, stream, pending, summary); err != nil {
+				return err
+			}
+			pending = pending[:0]
+			continue
+		}
+
+		if errors.Is(received.err, io.EOF) {
+			break
+		}
+		if received.err != nil {
+			return received.err
+		}
+
+		for _, entry := range received.request.Entries {
+			summary.Received++
+			streamEntry := toStreamBatchEntry(entry)
+			correlationID := streamEntry.createShortURLBatchDomain.CorrelationUUID
+			if correlationIDs[correlationID] {
+				streamEntry.err = status.Errorf(codes.InvalidArgument, "cannot CreateShortURLBatchStream: correlation_id %q is repeated", correlationID)
+			}
+			correlationIDs[correlationID] = true
+
+			pending = append(pending, streamEntry)
+			if len(pending) == streamBatchChunkSize {
+				if err := s.saveStreamBatchChunk(ctx, stream, pending, summary); err != nil {
+					return err
+				}
+				pending = pending[:0]
+			}
+		}
+	}
+
+	if err := s.saveStreamBatchChunk(ctx, stream, pending, summary); err != nil {
+		return err
+	}
+
+	log.Infow("grpc: CreateShortURLBatchStream finished", "received", summary.Received, "created", summary.Created, "failed", summary.Failed)
+
+	return stream.Send(&pb.CreateShortURLBatchStreamResponse{
+		Result: &pb.CreateShortURLBatchStreamResponse_Summary_{Summary: summary},
+	})
+}
+
+// receiveStreamBatchRequest возвращает следующий запрос потока, при wait=false - только уже прочитанный запрос,
+// ok=false - прочитанных запросов нет
+func receiveStreamBatchRequest(ctx context.Context, requests <-chan streamBatchRequest, wait bool) (received streamBatchRequest, ok bool, err error) {
+	if wait {
+		select {
+		case received, ok = <-requests:
+		case <-ctx.Done():
+		}
+	} else {
+		select {
+		case received, ok = <-requests:
+		default:
+			return streamBatchRequest{}, false, nil
+		}
+	}
+
+	// канал закрывается без ошибки чтения только при отмене контекста
+	if !ok {
+		return streamBatchRequest{}, false, status.FromContextError(ctx.Err()).Err()
+	}
+
+	return received, true, nil
+}
+
+// saveStreamBatchChunk сохраняет пачку коротких ссылок в одной транзакции и отправляет результат по каждой ссылке
+//
+// Если пачка не сохранена, короткие ссылки сохраняются по одной, чтобы вернуть ошибку только для ссылок,
+// которые не удалось сохранить
+func (s *ShortenerServiceServerImpl) saveStreamBatchChunk(
+	ctx context.Context,
+	stream pb.ShortenerService_CreateShortURLBatchStreamServer,
+	entries []streamBatchEntry,
+	summary *pb.CreateShortURLBatchStreamResponse_Summary) error {
+	createShortURLBatchDomains := make([]domain.CreateShortURLBatchDomain, 0, len(entries))
+	for _, entry := range entries {
+		if entry.err == nil {
+			createShortURLBatchDomains = append(createShortURLBatchDomains, entry.createShortURLBatchDomain)
+		}
+	}
+
+	shortURIs := make(map[string]string, len(createShortURLBatchDomains))
+	if len(createShortURLBatchDomains) > 0 {
+		createShortURLBatchResultDomains, err := s.shortURLCreator.CreateShortURLBatch(ctx, createShortURLBatchDomains)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+
+			log.Infow("grpc: CreateShortURLBatchStream chunk rejected, saving short urls one by one",
+				"chunk_size", len(createShortURLBatchDomains), "error", err)
+			createShortURLBatchResultDomains, err = s.saveStreamBatchEntries(ctx, entries)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, createShortURLBatchResultDomain := range createShortURLBatchResultDomains {
+			shortURIs[createShortURLBatchResultDomain.CorrelationUUID] = createShortURLBatchResultDomain.ShortURI
+		}
+	}
+
+	for _, entry := range entries {
+		responseEntry := &pb.CreateShortURLBatchStreamResponse_Entry{
+			CorrelationId: entry.createShortURLBatchDomain.CorrelationUUID,
+		}
+
+		if shortURI, ok := shortURIs[entry.createShortURLBatchDomain.CorrelationUUID]; ok && entry.err == nil {
+			responseEntry.ShortUrl = s.domains.ShortURL(shortURI)
+			responseEntry.ShortUri = shortURI
+			summary.Created++
+		} else {
+			if entry.err == nil {
+				entry.err = status.Errorf(codes.Internal, "cannot CreateShortURLBatchStream: short url is not saved")
+			}
+			entryStatus := status.Convert(entry.err)
+			responseEntry.ErrorCode = int32(entryStatus.Code())
+			responseEntry.Error = entryStatus.Message()
+			summary.Failed++
+		}
+
+		err := stream.Send(&pb.CreateShortURLBatchStreamResponse{
+			Result: &pb.CreateShortURLBatchStreamResponse_Entry_{Entry: responseEntry},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveStreamBatchEntries сохраняет короткие ссылки пачки по одной и отмечает ошибку у ссылок, которые не удалось сохранить
+//
+// Ошибка возвращается только при отмене контекста.
+func (s *ShortenerServiceServerImpl) saveStreamBatchEntries(ctx context.Context, entries []streamBatchEntry) ([]domain.CreateShortURLBatchResultDomain, error) {
+	result := make([]domain.CreateShortURLBatchResultDomain, 0, len(entries))
+	for i := range entries {
+		if entries[i].err != nil {
+			continue
+		}
+
+		createShortURLBatchResultDomains, err := s.shortURLCreator.CreateShortURLBatch(ctx, []domain.CreateShortURLBatchDomain{entries[i].createShortURLBatchDomain})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+
+			switch {
+			case errors.Is(err, usecase.ErrInvalidMetadata):
+				entries[i].err = status.Errorf(codes.InvalidArgument, "cannot CreateShortURLBatchStream: %v", err)
+			case errors.Is(err, usecase.ErrConflict):
+				entries[i].err = status.Errorf(codes.AlreadyExists, "cannot CreateShortURLBatchStream: %v", err)
+			default:
+				log.Errorw("grpc: CreateShortURLBatchStream failed to save short url", "correlation_id", entries[i].createShortURLBatchDomain.CorrelationUUID, "error", err)
+				entries[i].err = status.Errorf(codes.Internal, "cannot CreateShortURLBatchStream: %v", err)
+			}
+			continue
+		}
+
+		result = append(result, createShortURLBatchResultDomains...)
+	}
+
+	return result, nil
+}
+
+// toStreamBatchEntry проверяет запись потока CreateShortURLBatchStream, записи без идентификатора назначается новый
+func toStreamBatchEntry(entry *pb.CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) streamBatchEntry {
+	streamEntry := streamBatchEntry{createShortURLBatchDomain: toCreateShortURLBatchDomain(entry)}
+	if streamEntry.createShortURLBatchDomain.CorrelationUUID == "" {
+		streamEntry.createShortURLBatchDomain.CorrelationUUID = uuid.NewString()
+	}
+	if entry.OriginalUrl == "" {
+		streamEntry.err = status.Errorf(codes.InvalidArgument, "cannot CreateShortURLBatchStream: original_url is empty")
+	}
+
+	return streamEntry
+}
+
+func toCreateShortURLBatchDomain(entry *pb.CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry) domain.CreateShortURLBatchDomain {
+	return domain.CreateShortURLBatchDomain{
+		CorrelationUUID: entry.CorrelationId,
+		LongURL:         entry.OriginalUrl,
+		Metadata: domain.ShortURLMetadataDomain{
+			Title:       entry.Title,
+			Description: entry.Description,
+			Notes:       entry.Notes,
+			Tags:        entry.Tags,
+
+			RedirectStatus:   int(entry.RedirectStatus),
+			QueryPassthrough: entry.QueryPassthrough,
+			PathPassthrough:  entry.PathPassthrough,
+			UTMParams:        entry.UtmParams,
+			TargetingRules:   toTargetingRules(entry.TargetingRules),
+			SplitVariants:    toSplitVariants(entry.SplitVariants),
+			Password:         entry.Password,
+			MaxClicks:        int(entry.MaxClicks),
+			NotBefore:        fromUnixTime(entry.NotBefore),
+			NotAfter:         fromUnixTime(entry.NotAfter),
+			Disabled:         entry.Disabled,
+			FallbackURL:      entry.FallbackUrl,
+			ShortDomain:      entry.ShortDomain,
+		},
+	}
+}
+
 func (s *ShortenerServiceServerImpl) GetShortURLByUserID(ctx context.Context, request *pb.GetShortURLsByUserIDRequest) (*pb.GetShortURLsByUserIDResponse, error) {
 	userID := ctx.Value(common.UserIDContextKey).(string)
 	log.Infow("gprc: GetShortURLByUserID", "user_id", userID)
 
-	shortURLDomains, err := s.shortURLProvider.GetShortURLsByUserID(ctx, userID)
-	if err != nil {
+	query := domain.ShortURLQueryDomain{
+		UserID: userID,
+		Status: request.Status,
+		Domain: request.Domain,
+		Search: request.Search,
+		Tag:    request.Tag,
+		Sort:   request.Sort,
+		Cursor: request.Cursor,
+		Limit:  int(request.Limit),
+	}
+	if request.CreatedFrom != 0 {
+		createdFrom := time.Unix(request.CreatedFrom, 0)
+		query.CreatedFrom = &createdFrom
+	}
+	if request.CreatedTo != 0 {
+		createdTo := time.Unix(request.CreatedTo, 0)
+		query.CreatedTo = &createdTo
+	}
+
+	shortURLPageDomain, err := s.shortURLProvider.GetShortURLsByQuery(ctx, query)
+	if err != nil && errors.Is(err, usecase.ErrInvalidQuery) {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot GetShortURLByUserID: %v", err)
+	} else if err != nil {
 		log.Errorw("grpc: GetShortURLByUserID failed", "error", err)
 		return nil, status.Errorf(codes.Internal, "cannot GetShortURLByUserID: %v", err)
 	}
 
-	getShortURLsByUserIDResponseEntries := make([]*pb.GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry, 0, len(shortURLDomains))
-	for _, shortURLDomain := range shortURLDomains {
-		getShortURLsByUserIDResponseEntries = append(getShortURLsByUserIDResponseEntries, &pb.GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry{
-			ShortUrl:    util.GetShortURL(s.baseURL, shortURLDomain.ShortURI),
-			OriginalUrl: shortURLDomain.LongURL,
-		})
+	getShortURLsByUserIDResponseEntries := make([]*pb.GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry, 0, len(shortURLPageDomain.ShortURLs))
+	for _, shortURLDomain := range shortURLPageDomain.ShortURLs {
+		getShortURLsByUserIDResponseEntries = append(getShortURLsByUserIDResponseEntries, s.toGetShortURLByUserIDResponseEntry(shortURLDomain))
 	}
 	getShortURLsByUserIDResponse := &pb.GetShortURLsByUserIDResponse{
-		Entries: getShortURLsByUserIDResponseEntries,
+		Entries:    getShortURLsByUserIDResponseEntries,
+		TotalCount: int64(shortURLPageDomain.TotalCount),
+		NextCursor: shortURLPageDomain.NextCursor,
 	}
 
 	return getShortURLsByUserIDResponse, nil
 }
 
+func (s *ShortenerServiceServerImpl) UpdateShortURL(ctx context.Context, request *pb.UpdateShortURLRequest) (*pb.UpdateShortURLResponse, error) {
+	log.Infow("gprc: UpdateShortURL", "short_uri", request.ShortUri)
+
+	patch := domain.ShortURLPatchDomain{
+		Title:       request.Title,
+		Description: request.Description,
+		Notes:       request.Notes,
+
+		QueryPassthrough: request.QueryPassthrough,
+		PathPassthrough:  request.PathPassthrough,
+		Password:         request.Password,
+		Disabled:         request.Disabled,
+		FallbackURL:      request.FallbackUrl,
+	}
+	if request.Tags != nil {
+		patch.Tags = &request.Tags.Values
+	}
+	if request.RedirectStatus != nil {
+		redirectStatus := int(*request.RedirectStatus)
+		patch.RedirectStatus = &redirectStatus
+	}
+	if request.UtmParams != nil {
+		patch.UTMParams = &request.UtmParams.Values
+	}
+	if request.TargetingRules != nil {
+		targetingRules := toTargetingRules(request.TargetingRules.Values)
+		patch.TargetingRules = &targetingRules
+	}
+	if request.SplitVariants != nil {
+		splitVariants := toSplitVariants(request.SplitVariants.Values)
+		patch.SplitVariants = &splitVariants
+	}
+	if request.MaxClicks != nil {
+		maxClicks := int(*request.MaxClicks)
+		patch.MaxClicks = &maxClicks
+	}
+	if request.NotBefore != nil {
+		patch.NotBefore = patchUnixTime(*request.NotBefore)
+	}
+	if request.NotAfter != nil {
+		patch.NotAfter = patchUnixTime(*request.NotAfter)
+	}
+
+	shortURLDomain, err := s.shortURLUpdater.UpdateShortURL(ctx, request.ShortUri, patch)
+	if err != nil && errors.Is(err, usecase.ErrInvalidMetadata) {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot UpdateShortURL: %v", err)
+	} else if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		log.Infow("grpc: short URL not found", "short_uri", request.ShortUri)
+		return nil, status.Errorf(codes.NotFound, "short url not found: %v", err)
+	} else if err != nil {
+		log.Errorw("grpc: UpdateShortURL failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot UpdateShortURL: %v", err)
+	}
+
+	updateShortURLResponse := &pb.UpdateShortURLResponse{
+		Entry: s.toGetShortURLByUserIDResponseEntry(shortURLDomain),
+	}
+
+	return updateShortURLResponse, nil
+}
+
+func (s *ShortenerServiceServerImpl) toGetShortURLByUserIDResponseEntry(shortURLDomain domain.ShortURLDomain) *pb.GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry {
+	shortDomain, _ := shortdomain.Split(shortURLDomain.ShortURI)
+	return &pb.GetShortURLsByUserIDResponse_GetShortURLByUserIDResponseEntry{
+		ShortUrl:    s.domains.ShortURL(shortURLDomain.ShortURI),
+		OriginalUrl: shortURLDomain.LongURL,
+		CreatedAt:   shortURLDomain.CreatedAt.Unix(),
+		IsDeleted:   shortURLDomain.Deleted,
+		Title:       shortURLDomain.Title,
+		Description: shortURLDomain.Description,
+		Notes:       shortURLDomain.Notes,
+		Tags:        shortURLDomain.Tags,
+
+		PreviewTitle:       shortURLDomain.PreviewTitle,
+		PreviewDescription: shortURLDomain.PreviewDescription,
+		PreviewImageUrl:    shortURLDomain.PreviewImageURL,
+
+		RedirectStatus:   int32(shortURLDomain.RedirectStatus),
+		QueryPassthrough: shortURLDomain.QueryPassthrough,
+		PathPassthrough:  shortURLDomain.PathPassthrough,
+		UtmParams:        shortURLDomain.UTMParams,
+		TargetingRules:   toPBTargetingRules(shortURLDomain.TargetingRules),
+		SplitVariants:    toPBSplitVariants(shortURLDomain.SplitVariants),
+
+		PasswordProtected: shortURLDomain.PasswordHash != "",
+		MaxClicks:         int32(shortURLDomain.MaxClicks),
+		ClickCount:        shortURLDomain.ClickCount,
+		NotBefore:         toUnixTime(shortURLDomain.NotBefore),
+		NotAfter:          toUnixTime(shortURLDomain.NotAfter),
+		Disabled:          shortURLDomain.Disabled,
+		FallbackUrl:       shortURLDomain.FallbackURL,
+		ShortUri:          shortURLDomain.ShortURI,
+		ShortDomain:       shortDomain,
+	}
+}
+
+// fromUnixTime возвращает время по количеству секунд Unix, для 0 - nil
+func fromUnixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+
+	t := time.Unix(seconds, 0)
+	return &t
+}
+
+// patchUnixTime возвращает изменение времени по количеству секунд Unix, для 0 - нулевое время, снимающее ограничение
+func patchUnixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return &time.Time{}
+	}
+
+	return fromUnixTime(seconds)
+}
+
+// toUnixTime возвращает количество секунд Unix для времени t, для nil - 0
+func toUnixTime(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+// clientAddr возвращает IP-адрес клиента, определенный interceptor.ClientAddrInterceptor, иначе - адрес соединения
+func clientAddr(ctx context.Context) string {
+	if addr, ok := ctx.Value(common.ClientAddrContextKey).(netip.Addr); ok && addr.IsValid() {
+		return addr.String()
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+func toTargetingRules(pbTargetingRules []*pb.TargetingRule) []targeting.Rule {
+	var targetingRules []targeting.Rule
+	for _, pbTargetingRule := range pbTargetingRules {
+		targetingRules = append(targetingRules, targeting.Rule{
+			Platform: pbTargetingRule.Platform,
+			Device:   pbTargetingRule.Device,
+			Locale:   pbTargetingRule.Locale,
+			Country:  pbTargetingRule.Country,
+			URL:      pbTargetingRule.Url,
+		})
+	}
+
+	return targetingRules
+}
+
+func toPBTargetingRules(targetingRules []targeting.Rule) []*pb.TargetingRule {
+	var pbTargetingRules []*pb.TargetingRule
+	for _, targetingRule := range targetingRules {
+		pbTargetingRules = append(pbTargetingRules, &pb.TargetingRule{
+			Platform: targetingRule.Platform,
+			Device:   targetingRule.Device,
+			Locale:   targetingRule.Locale,
+			Country:  targetingRule.Country,
+			Url:      targetingRule.URL,
+		})
+	}
+
+	return pbTargetingRules
+}
+
+func toSplitVariants(pbSplitVariants []*pb.SplitVariant) []split.Variant {
+	var splitVariants []split.Variant
+	for _, pbSplitVariant := range pbSplitVariants {
+		splitVariants = append(splitVariants, split.Variant{
+			Name:   pbSplitVariant.Name,
+			URL:    pbSplitVariant.Url,
+			Weight: int(pbSplitVariant.Weight),
+		})
+	}
+
+	return splitVariants
+}
+
+func toPBSplitVariants(splitVariants []split.Variant) []*pb.SplitVariant {
+	var pbSplitVariants []*pb.SplitVariant
+	for _, splitVariant := range splitVariants {
+		pbSplitVariants = append(pbSplitVariants, &pb.SplitVariant{
+			Name:   splitVariant.Name,
+			Url:    splitVariant.URL,
+			Weight: int32(splitVariant.Weight),
+		})
+	}
+
+	return pbSplitVariants
+}
+
 func (s *ShortenerServiceServerImpl) DeleteShortURLsByShortURIs(ctx context.Context, request *pb.DeleteShortURLsByShortURIsRequest) (*pb.DeleteShortURLsByShortURIsResponse, error) {
 	log.Infow("gprc: DeleteShortURLsByShortURIs", "batch_size", len(request.ShortURIs))
 
-	err := s.shortURLDeleter.DeleteShortURLsByShortURIs(ctx, request.ShortURIs)
+	deleteJobDomain, err := s.shortURLDeleter.DeleteShortURLsByShortURIs(context.WithoutCancel(ctx), request.ShortURIs)
 	if err != nil {
 		log.Errorw("grpc: DeleteShortURLsByShortURIs failed", "error", err)
 		return nil, status.Errorf(codes.Internal, "cannot DeleteShortURLsByShortURIs: %v", err)
@@ -167,11 +787,153 @@ func (s *ShortenerServiceServerImpl) DeleteShortURLsByShortURIs(ctx context.Cont
 
 	deleteShortURLsByShortURIsResponse := &pb.DeleteShortURLsByShortURIsResponse{
 		Accepted: true,
+		JobId:    deleteJobDomain.ID,
 	}
 
 	return deleteShortURLsByShortURIsResponse, nil
 }
 
+func (s *ShortenerServiceServerImpl) GetDeleteJob(ctx context.Context, request *pb.GetDeleteJobRequest) (*pb.GetDeleteJobResponse, error) {
+	log.Infow("gprc: GetDeleteJob", "job_id", request.JobId)
+
+	deleteJobDomain, err := s.shortURLDeleter.GetDeleteJob(ctx, request.JobId)
+	if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		log.Infow("grpc: delete job not found", "job_id", request.JobId)
+		return nil, status.Errorf(codes.NotFound, "delete job not found: %v", err)
+	} else if err != nil {
+		log.Errorw("grpc: GetDeleteJob failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot GetDeleteJob: %v", err)
+	}
+
+	getDeleteJobResponseEntries := make([]*pb.GetDeleteJobResponse_GetDeleteJobResponseEntry, 0, len(deleteJobDomain.Results))
+	for _, deleteShortURLResultDomain := range deleteJobDomain.Results {
+		getDeleteJobResponseEntries = append(getDeleteJobResponseEntries, &pb.GetDeleteJobResponse_GetDeleteJobResponseEntry{
+			ShortUri: deleteShortURLResultDomain.ShortURI,
+			Status:   deleteShortURLResultDomain.Status,
+		})
+	}
+	getDeleteJobResponse := &pb.GetDeleteJobResponse{
+		JobId:   deleteJobDomain.ID,
+		Status:  deleteJobDomain.Status,
+		Entries: getDeleteJobResponseEntries,
+	}
+
+	return getDeleteJobResponse, nil
+}
+
+func (s *ShortenerServiceServerImpl) GetSplitStats(ctx context.Context, request *pb.GetSplitStatsRequest) (*pb.GetSplitStatsResponse, error) {
+	log.Infow("gprc: GetSplitStats", "short_uri", request.ShortUri)
+
+	splitStats, err := s.splitStats.GetSplitStats(ctx, request.ShortUri)
+	if err != nil && errors.Is(err, usecase.ErrNotFound) {
+		log.Infow("grpc: short URL not found", "short_uri", request.ShortUri)
+		return nil, status.Errorf(codes.NotFound, "short url not found: %v", err)
+	} else if err != nil {
+		log.Errorw("grpc: GetSplitStats failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot GetSplitStats: %v", err)
+	}
+
+	getSplitStatsResponseEntries := make([]*pb.GetSplitStatsResponse_GetSplitStatsResponseEntry, 0, len(splitStats))
+	for _, variantStats := range splitStats {
+		getSplitStatsResponseEntries = append(getSplitStatsResponseEntries, &pb.GetSplitStatsResponse_GetSplitStatsResponseEntry{
+			Name:   variantStats.Name,
+			Url:    variantStats.URL,
+			Weight: int32(variantStats.Weight),
+			Clicks: variantStats.Clicks,
+		})
+	}
+
+	return &pb.GetSplitStatsResponse{Entries: getSplitStatsResponseEntries}, nil
+}
+
+func (s *ShortenerServiceServerImpl) GetDeletedShortURLsByUserID(ctx context.Context, request *pb.GetDeletedShortURLsByUserIDRequest) (*pb.GetDeletedShortURLsByUserIDResponse, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	log.Infow("gprc: GetDeletedShortURLsByUserID", "user_id", userID)
+
+	shortURLDomains, err := s.shortURLProvider.GetDeletedShortURLsByUserID(ctx, userID)
+	if err != nil {
+		log.Errorw("grpc: GetDeletedShortURLsByUserID failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot GetDeletedShortURLsByUserID: %v", err)
+	}
+
+	getDeletedShortURLsByUserIDResponseEntries := make([]*pb.GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry, 0, len(shortURLDomains))
+	for _, shortURLDomain := range shortURLDomains {
+		var deletedAt int64
+		if shortURLDomain.DeletedAt != nil {
+			deletedAt = shortURLDomain.DeletedAt.Unix()
+		}
+
+		getDeletedShortURLsByUserIDResponseEntries = append(getDeletedShortURLsByUserIDResponseEntries, &pb.GetDeletedShortURLsByUserIDResponse_GetDeletedShortURLsByUserIDResponseEntry{
+			ShortUrl:    s.domains.ShortURL(shortURLDomain.ShortURI),
+			OriginalUrl: shortURLDomain.LongURL,
+			DeletedAt:   deletedAt,
+			ShortUri:    shortURLDomain.ShortURI,
+		})
+	}
+	getDeletedShortURLsByUserIDResponse := &pb.GetDeletedShortURLsByUserIDResponse{
+		Entries: getDeletedShortURLsByUserIDResponseEntries,
+	}
+
+	return getDeletedShortURLsByUserIDResponse, nil
+}
+
+func (s *ShortenerServiceServerImpl) RestoreShortURLsByShortURIs(ctx context.Context, request *pb.RestoreShortURLsByShortURIsRequest) (*pb.RestoreShortURLsByShortURIsResponse, error) {
+	log.Infow("gprc: RestoreShortURLsByShortURIs", "batch_size", len(request.ShortURIs))
+
+	restoreShortURLResultDomains, err := s.shortURLDeleter.RestoreShortURLsByShortURIs(ctx, request.ShortURIs)
+	if err != nil {
+		log.Errorw("grpc: RestoreShortURLsByShortURIs failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot RestoreShortURLsByShortURIs: %v", err)
+	}
+
+	restoreShortURLsByShortURIsResponseEntries := make([]*pb.RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry, 0, len(restoreShortURLResultDomains))
+	for _, restoreShortURLResultDomain := range restoreShortURLResultDomains {
+		restoreShortURLsByShortURIsResponseEntries = append(restoreShortURLsByShortURIsResponseEntries, &pb.RestoreShortURLsByShortURIsResponse_RestoreShortURLsByShortURIsResponseEntry{
+			ShortUri: restoreShortURLResultDomain.ShortURI,
+			Status:   restoreShortURLResultDomain.Status,
+		})
+	}
+	restoreShortURLsByShortURIsResponse := &pb.RestoreShortURLsByShortURIsResponse{
+		Entries: restoreShortURLsByShortURIsResponseEntries,
+	}
+
+	return restoreShortURLsByShortURIsResponse, nil
+}
+
+func (s *ShortenerServiceServerImpl) GetUserSettings(ctx context.Context, request *pb.GetUserSettingsRequest) (*pb.GetUserSettingsResponse, error) {
+	log.Infow("gprc: GetUserSettings")
+
+	userSettings, err := s.userSettings.GetUserSettings(ctx)
+	if err != nil {
+		log.Errorw("grpc: GetUserSettings failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot GetUserSettings: %v", err)
+	}
+
+	getUserSettingsResponse := &pb.GetUserSettingsResponse{
+		FallbackUrl: userSettings.FallbackURL,
+	}
+
+	return getUserSettingsResponse, nil
+}
+
+func (s *ShortenerServiceServerImpl) UpdateUserSettings(ctx context.Context, request *pb.UpdateUserSettingsRequest) (*pb.UpdateUserSettingsResponse, error) {
+	log.Infow("gprc: UpdateUserSettings")
+
+	userSettings, err := s.userSettings.UpdateUserSettings(ctx, domain.UserSettingsDomain{FallbackURL: request.FallbackUrl})
+	if err != nil && errors.Is(err, usecase.ErrInvalidSettings) {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot UpdateUserSettings: %v", err)
+	} else if err != nil {
+		log.Errorw("grpc: UpdateUserSettings failed", "error", err)
+		return nil, status.Errorf(codes.Internal, "cannot UpdateUserSettings: %v", err)
+	}
+
+	updateUserSettingsResponse := &pb.UpdateUserSettingsResponse{
+		FallbackUrl: userSettings.FallbackURL,
+	}
+
+	return updateUserSettingsResponse, nil
+}
+
 func (s *ShortenerServiceServerImpl) Ping(ctx context.Context, request *pb.PingRequest) (*pb.PingResponse, error) {
 	log.Infow("gprc: Ping")
 	isDBConnectionAlive := s.dbLookup.Ping(ctx)
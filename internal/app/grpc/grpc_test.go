@@ -0,0 +1,302 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	pb "github.com/vkhrushchev/urlshortener/grpc"
+	"github.com/vkhrushchev/urlshortener/internal/app/domain"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/repository"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/usecase"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+	"github.com/vkhrushchev/urlshortener/internal/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testConflictURL, testFailURL - исходные URL, при сохранении которых rejectingShortURLRepository
+// отклоняет пачку с ErrConflict и ErrUnexpected
+const (
+	testConflictURL = "https://conflict.ru"
+	testFailURL     = "https://fail.ru"
+)
+
+// rejectingShortURLRepository отклоняет пачки коротких ссылок с исходными URL testConflictURL и testFailURL
+type rejectingShortURLRepository struct {
+	*repository.InMemoryShortURLRepository
+}
+
+func (r *rejectingShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+	for _, shortURLEntity := range shortURLEntities {
+		switch shortURLEntity.LongURL {
+		case testConflictURL:
+			return nil, repository.ErrConflict
+		case testFailURL:
+			return nil, repository.ErrUnexpected
+		}
+	}
+
+	return r.InMemoryShortURLRepository.SaveShortURLs(ctx, shortURLEntities)
+}
+
+type CreateShortURLBatchStreamTestSuite struct {
+	suite.Suite
+	repo   *repository.InMemoryShortURLRepository
+	server *grpc.Server
+	conn   *grpc.ClientConn
+	client pb.ShortenerServiceClient
+}
+
+func (suite *CreateShortURLBatchStreamTestSuite) SetupTest() {
+	suite.repo = repository.NewInMemoryShortURLRepository()
+	shortenerServiceServer := NewShortenerServiceServer(
+		usecase.NewCreateShortURLUseCase(&rejectingShortURLRepository{InMemoryShortURLRepository: suite.repo}, nil, nil),
+		nil, nil, nil, nil, nil, nil, nil, nil,
+		shortdomain.NewDomains("http://localhost:8080", nil),
+	)
+
+	listener := bufconn.Listen(1024 * 1024)
+	suite.server = grpc.NewServer(grpc.ChainStreamInterceptor(
+		interceptor.UserIDStreamInterceptor("salt", []string{"CreateShortURLBatchStream"}),
+	))
+	pb.RegisterShortenerServiceServer(suite.server, shortenerServiceServer)
+	go suite.server.Serve(listener)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	suite.Require().NoError(err)
+
+	suite.conn = conn
+	suite.client = pb.NewShortenerServiceClient(conn)
+}
+
+func (suite *CreateShortURLBatchStreamTestSuite) TearDownTest() {
+	suite.conn.Close()
+	suite.server.Stop()
+}
+
+func (suite *CreateShortURLBatchStreamTestSuite) TestCreateShortURLBatchStream() {
+	stream, err := suite.client.CreateShortURLBatchStream(context.Background())
+	suite.Require().NoError(err)
+
+	const entryCount = 250
+	invalidEntries := map[string]codes.Code{"entry-120": codes.InvalidArgument, "entry-200": codes.InvalidArgument}
+	go func() {
+		for i := 0; i < entryCount; i++ {
+			entry := &pb.CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry{
+				CorrelationId: fmt.Sprintf("entry-%d", i),
+				OriginalUrl:   fmt.Sprintf("https://ya.ru/%d", i),
+			}
+			switch i {
+			case 120:
+				entry.Title = strings.Repeat("a", usecase.ShortURLTitleMaxLength+1)
+			case 200:
+				entry.OriginalUrl = ""
+			}
+
+			if err := stream.Send(&pb.CreateShortURLBatchStreamRequest{Entries: []*pb.CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry{entry}}); err != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	header, err := stream.Header()
+	suite.Require().NoError(err)
+	suite.Len(header.Get("user-id"), 1)
+	suite.Len(header.Get("user-id-signature"), 1)
+
+	var entries []*pb.CreateShortURLBatchStreamResponse_Entry
+	var summary *pb.CreateShortURLBatchStreamResponse_Summary
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		suite.Require().NoError(err)
+
+		if response.GetSummary() != nil {
+			summary = response.GetSummary()
+			continue
+		}
+		entries = append(entries, response.GetEntry())
+	}
+
+	suite.Require().Len(entries, entryCount)
+	for _, entry := range entries {
+		if code, ok := invalidEntries[entry.CorrelationId]; ok {
+			suite.Equal(int32(code), entry.ErrorCode, entry.CorrelationId)
+			suite.NotEmpty(entry.Error, entry.CorrelationId)
+			suite.Empty(entry.ShortUri, entry.CorrelationId)
+			continue
+		}
+
+		suite.Zero(entry.ErrorCode, entry.CorrelationId)
+		suite.Equal("http://localhost:8080/"+entry.ShortUri, entry.ShortUrl)
+	}
+
+	suite.Require().NotNil(summary)
+	suite.Equal(int32(entryCount), summary.Received)
+	suite.Equal(int32(entryCount-len(invalidEntries)), summary.Created)
+	suite.Equal(int32(len(invalidEntries)), summary.Failed)
+
+	userShortURLs, err := suite.repo.GetShortURLsByUserID(context.Background(), header.Get("user-id")[0])
+	suite.Require().NoError(err)
+	suite.Len(userShortURLs, entryCount-len(invalidEntries))
+}
+
+func (suite *CreateShortURLBatchStreamTestSuite) TestCreateShortURLBatchStream_incomplete_chunk() {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "user-id", "user", "user-id-signature", "invalid")
+	stream, err := suite.client.CreateShortURLBatchStream(ctx)
+	suite.Require().NoError(err)
+
+	err = stream.Send(&pb.CreateShortURLBatchStreamRequest{Entries: []*pb.CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry{
+		{OriginalUrl: "https://ya.ru"},
+	}})
+	suite.Require().NoError(err)
+
+	response, err := stream.Recv()
+	suite.Require().NoError(err)
+	suite.Require().NotNil(response.GetEntry(), "incomplete chunk must be saved when client waits for results")
+	suite.NotEmpty(response.GetEntry().CorrelationId, "entry without correlation id must get generated one")
+	suite.NotEmpty(response.GetEntry().ShortUri)
+
+	header, err := stream.Header()
+	suite.Require().NoError(err)
+	suite.NotEqual([]string{"user"}, header.Get("user-id"), "user with invalid signature must be replaced")
+
+	suite.Require().NoError(stream.CloseSend())
+	response, err = stream.Recv()
+	suite.Require().NoError(err)
+	suite.Equal(int32(1), response.GetSummary().GetCreated())
+
+	_, err = stream.Recv()
+	suite.Equal(io.EOF, err)
+}
+
+func (suite *CreateShortURLBatchStreamTestSuite) TestCreateShortURLBatchStream_rejected_entries() {
+	stream, err := suite.client.CreateShortURLBatchStream(context.Background())
+	suite.Require().NoError(err)
+
+	err = stream.Send(&pb.CreateShortURLBatchStreamRequest{Entries: []*pb.CreateShortURLBatchRequest_CreateShortURLBatchRequestEntry{
+		{CorrelationId: "first", OriginalUrl: "https://ya.ru"},
+		{CorrelationId: "first", OriginalUrl: "https://mail.ru"},
+		{CorrelationId: "conflict", OriginalUrl: testConflictURL},
+		{CorrelationId: "fail", OriginalUrl: testFailURL},
+		{CorrelationId: "second", OriginalUrl: "https://go.dev"},
+	}})
+	suite.Require().NoError(err)
+	suite.Require().NoError(stream.CloseSend())
+
+	var entries []*pb.CreateShortURLBatchStreamResponse_Entry
+	var summary *pb.CreateShortURLBatchStreamResponse_Summary
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		suite.Require().NoError(err, "rejected entries must not abort stream")
+
+		if response.GetSummary() != nil {
+			summary = response.GetSummary()
+			continue
+		}
+		entries = append(entries, response.GetEntry())
+	}
+
+	expectedCodes := []codes.Code{codes.OK, codes.InvalidArgument, codes.AlreadyExists, codes.Internal, codes.OK}
+	suite.Require().Len(entries, len(expectedCodes))
+	for i, entry := range entries {
+		suite.Equal(int32(expectedCodes[i]), entry.ErrorCode, "entry %d %s", i, entry.CorrelationId)
+		if expectedCodes[i] == codes.OK {
+			suite.NotEmpty(entry.ShortUri, "entry %d %s", i, entry.CorrelationId)
+		} else {
+			suite.NotEmpty(entry.Error, "entry %d %s", i, entry.CorrelationId)
+			suite.Empty(entry.ShortUri, "entry %d %s", i, entry.CorrelationId)
+		}
+	}
+
+	suite.Require().NotNil(summary)
+	suite.Equal(int32(5), summary.Received)
+	suite.Equal(int32(2), summary.Created)
+	suite.Equal(int32(3), summary.Failed)
+
+	header, err := stream.Header()
+	suite.Require().NoError(err)
+	userShortURLs, err := suite.repo.GetShortURLsByUserID(context.Background(), header.Get("user-id")[0])
+	suite.Require().NoError(err)
+	suite.Len(userShortURLs, 2, "repeated correlation id must not be saved")
+}
+
+func TestCreateShortURLBatchStreamTestSuite(t *testing.T) {
+	suite.Run(t, new(CreateShortURLBatchStreamTestSuite))
+}
+
+func TestGetShortURL_spoofed_x_real_ip(t *testing.T) {
+	repo := repository.NewInMemoryShortURLRepository()
+	createShortURLUseCase := usecase.NewCreateShortURLUseCase(repo, nil, nil)
+	shortenerServiceServer := NewShortenerServiceServer(
+		createShortURLUseCase, nil, usecase.NewUnlockShortURLUseCase(repo), nil, nil, nil, nil, nil, nil,
+		shortdomain.NewDomains("http://localhost:8080", nil),
+	)
+
+	// соединение bufconn не из доверенной подсети, поэтому x-real-ip не учитывается
+	_, trustedSubnet, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		interceptor.ClientAddrInterceptor(trustedSubnet, []string{"GetShortURL"}),
+	))
+	pb.RegisterShortenerServiceServer(server, shortenerServiceServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewShortenerServiceClient(conn)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	shortURLDomain, err := createShortURLUseCase.CreateShortURL(testCtx, "https://example.com", domain.ShortURLMetadataDomain{Password: "secret"})
+	require.NoError(t, err)
+
+	getShortURL := func(password string, realIP string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-real-ip", realIP)
+		_, err := client.GetShortURL(ctx, &pb.GetShortURLRequest{ShortUri: shortURLDomain.ShortURI, Password: password})
+		return err
+	}
+
+	for i := 0; i < usecase.PasswordClientAttemptsMax; i++ {
+		err := getShortURL("wrong", fmt.Sprintf("203.0.113.%d", i+1))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+
+	err = getShortURL("secret", "203.0.113.100")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
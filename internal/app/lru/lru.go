@@ -0,0 +1,119 @@
+// Package lru реализует ограниченный по размеру кэш с вытеснением давно не использованных записей
+// и ограниченным временем жизни записей
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache структура с описанием кэша значений типа V по строковому ключу
+//
+// При превышении размера вытесняется запись, к которой дольше всего не обращались.
+// Запись, время жизни которой истекло, не возвращается и удаляется при обращении к ней.
+type Cache[V any] struct {
+	mutex   sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type entry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// NewCache создает новый экземпляр структуры Cache
+//
+//	size - максимальное количество записей в кэше
+//	ttl - время жизни записи, 0 - записи не истекают
+func NewCache[V any](size int, ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// Get возвращает значение по ключу key и признак его наличия в кэше
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	cacheEntry := element.Value.(*entry[V])
+	if c.ttl > 0 && !c.now().Before(cacheEntry.expiresAt) {
+		c.removeElement(element)
+
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return cacheEntry.value, true
+}
+
+// Add сохраняет значение value по ключу key
+func (c *Cache[V]) Add(key string, value V) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		cacheEntry := element.Value.(*entry[V])
+		cacheEntry.value = value
+		cacheEntry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry[V]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Remove удаляет значение по ключу key
+func (c *Cache[V]) Remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// Purge удаляет все значения
+func (c *Cache[V]) Purge() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = make(map[string]*list.Element, c.size)
+	c.order.Init()
+}
+
+// Len возвращает количество записей в кэше, включая истекшие, но еще не удаленные
+func (c *Cache[V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache[V]) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry[V]).key)
+}
@@ -0,0 +1,104 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	testCases := []struct {
+		name          string
+		keys          []string
+		touch         string
+		remove        string
+		elapsed       time.Duration
+		expectedFound map[string]bool
+	}{
+		{
+			name:          "values within size",
+			keys:          []string{"a", "b"},
+			expectedFound: map[string]bool{"a": true, "b": true, "c": false},
+		},
+		{
+			name:          "least recently added value evicted",
+			keys:          []string{"a", "b", "c"},
+			expectedFound: map[string]bool{"a": false, "b": true, "c": true},
+		},
+		{
+			name:          "recently used value kept",
+			keys:          []string{"a", "b"},
+			touch:         "a",
+			expectedFound: map[string]bool{"a": true, "b": false, "c": true},
+		},
+		{
+			name:          "value removed",
+			keys:          []string{"a", "b"},
+			remove:        "a",
+			expectedFound: map[string]bool{"a": false, "b": true},
+		},
+		{
+			name:          "values expired",
+			keys:          []string{"a", "b"},
+			elapsed:       time.Minute,
+			expectedFound: map[string]bool{"a": false, "b": false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+			cache := NewCache[string](2, time.Minute)
+			cache.now = func() time.Time { return now }
+
+			for _, key := range tc.keys {
+				cache.Add(key, "value_"+key)
+			}
+			if tc.touch != "" {
+				cache.Get(tc.touch)
+				cache.Add("c", "value_c")
+			}
+			if tc.remove != "" {
+				cache.Remove(tc.remove)
+			}
+			now = now.Add(tc.elapsed)
+
+			for key, expectedFound := range tc.expectedFound {
+				value, found := cache.Get(key)
+				assert.Equal(t, expectedFound, found, "key %q", key)
+				if expectedFound {
+					assert.Equal(t, "value_"+key, value)
+				}
+			}
+		})
+	}
+}
+
+func TestCache_Add_update(t *testing.T) {
+	now := time.Now()
+	cache := NewCache[int](2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.Add("a", 1)
+	now = now.Add(30 * time.Second)
+	cache.Add("a", 2)
+	now = now.Add(45 * time.Second)
+
+	value, found := cache.Get("a")
+	assert.True(t, found, "updated value must not expire before its own ttl")
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestCache_Purge(t *testing.T) {
+	cache := NewCache[int](2, 0)
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+
+	cache.Purge()
+
+	_, found := cache.Get("a")
+	assert.False(t, found)
+	assert.Equal(t, 0, cache.Len())
+}
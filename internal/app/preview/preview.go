@@ -0,0 +1,273 @@
+// Package preview получает превью страниц по их URL: заголовок, описание и изображение из разметки Open Graph
+package preview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var log = zap.Must(zap.NewDevelopment()).Sugar()
+
+// ErrForbiddenAddress - адрес страницы запрещен для запроса (не http(s) или частная сеть)
+// ErrNotHTML - ответ не является html-страницей
+// ErrUnexpectedStatus - сервер вернул код ответа, отличный от 200
+// ErrTemporary - временная ошибка, запрос можно повторить
+var (
+	ErrForbiddenAddress = errors.New("forbidden address")
+	ErrNotHTML          = errors.New("not html")
+	ErrUnexpectedStatus = errors.New("unexpected status")
+	ErrTemporary        = errors.New("temporary error")
+)
+
+// TimeoutDefault - время ожидания получения страницы по умолчанию
+// MaxBodySizeDefault - максимальный размер читаемой части страницы по умолчанию
+// maxRedirects - максимальное количество перенаправлений
+const (
+	TimeoutDefault     = 5 * time.Second
+	MaxBodySizeDefault = 512 * 1024
+	maxRedirects       = 5
+)
+
+// forbiddenPrefixes - сети, не попадающие под netip.Addr.IsPrivate и подобные проверки, но недоступные извне
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// Preview структура с описанием превью страницы
+type Preview struct {
+	Title       string
+	Description string
+	ImageURL    string
+}
+
+// Fetcher получает превью страниц
+//
+// Запросы выполняются только по http(s) к публичным адресам: проверка выполняется для каждого соединения
+// после разрешения имени, поэтому распространяется и на перенаправления.
+type Fetcher struct {
+	client      *http.Client
+	maxBodySize int64
+}
+
+// NewFetcher создает экземпляр Fetcher
+//
+//	timeout - время ожидания получения страницы, включая перенаправления
+//	maxBodySize - максимальный размер читаемой части страницы в байтах
+func NewFetcher(timeout time.Duration, maxBodySize int64) *Fetcher {
+	return newFetcher(timeout, maxBodySize, checkPublicAddress)
+}
+
+func newFetcher(timeout time.Duration, maxBodySize int64, checkAddress func(addr netip.Addr) error) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrForbiddenAddress, err)
+			}
+
+			return checkAddress(addrPort.Addr().Unmap())
+		},
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+
+			return checkScheme(req.URL)
+		},
+	}
+
+	return &Fetcher{client: client, maxBodySize: maxBodySize}
+}
+
+// Fetch получает превью страницы по адресу rawURL
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Preview, error) {
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return Preview{}, fmt.Errorf("%w: %v", ErrForbiddenAddress, err)
+	}
+	if err := checkScheme(pageURL); err != nil {
+		return Preview{}, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return Preview{}, fmt.Errorf("%w: %v", ErrForbiddenAddress, err)
+	}
+	request.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	response, err := f.client.Do(request)
+	if err != nil && errors.Is(err, ErrForbiddenAddress) {
+		return Preview{}, err
+	} else if err != nil {
+		return Preview{}, fmt.Errorf("%w: %v", ErrTemporary, err)
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			log.Warnw("preview: error when close response body", "err", err)
+		}
+	}()
+
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError {
+		return Preview{}, fmt.Errorf("%w: %w: %d", ErrTemporary, ErrUnexpectedStatus, response.StatusCode)
+	} else if response.StatusCode != http.StatusOK {
+		return Preview{}, fmt.Errorf("%w: %d", ErrUnexpectedStatus, response.StatusCode)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return Preview{}, fmt.Errorf("%w: %q", ErrNotHTML, response.Header.Get("Content-Type"))
+	}
+
+	preview, err := parsePreview(io.LimitReader(response.Body, f.maxBodySize))
+	if err != nil {
+		return Preview{}, fmt.Errorf("%w: %v", ErrTemporary, err)
+	}
+	preview.ImageURL = resolveImageURL(response.Request.URL, preview.ImageURL)
+
+	return preview, nil
+}
+
+// parsePreview разбирает заголовок страницы и разметку Open Graph из секции head,
+// значения Open Graph имеют приоритет над <title> и <meta name="description">
+func parsePreview(r io.Reader) (Preview, error) {
+	var preview Preview
+	var title, description string
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			if errors.Is(tokenizer.Err(), io.EOF) {
+				return mergePreview(preview, title, description), nil
+			}
+			return Preview{}, tokenizer.Err()
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			if atom.Lookup(name) == atom.Head {
+				return mergePreview(preview, title, description), nil
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Body:
+				return mergePreview(preview, title, description), nil
+			case atom.Title:
+				if tokenType == html.StartTagToken && title == "" && tokenizer.Next() == html.TextToken {
+					title = string(tokenizer.Text())
+				}
+			case atom.Meta:
+				attrs := make(map[string]string)
+				for hasAttr {
+					var key, value []byte
+					key, value, hasAttr = tokenizer.TagAttr()
+					attrs[string(key)] = string(value)
+				}
+
+				switch strings.ToLower(attrs["property"]) {
+				case "og:title":
+					preview.Title = attrs["content"]
+				case "og:description":
+					preview.Description = attrs["content"]
+				case "og:image":
+					preview.ImageURL = attrs["content"]
+				}
+				if strings.ToLower(attrs["name"]) == "description" {
+					description = attrs["content"]
+				}
+			}
+		}
+	}
+}
+
+func mergePreview(preview Preview, title string, description string) Preview {
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	if preview.Description == "" {
+		preview.Description = description
+	}
+
+	preview.Title = strings.Join(strings.Fields(preview.Title), " ")
+	preview.Description = strings.Join(strings.Fields(preview.Description), " ")
+	preview.ImageURL = strings.TrimSpace(preview.ImageURL)
+
+	return preview
+}
+
+// resolveImageURL приводит адрес изображения к абсолютному относительно адреса страницы,
+// адреса с недопустимой схемой отбрасываются
+func resolveImageURL(pageURL *url.URL, imageURL string) string {
+	if imageURL == "" {
+		return ""
+	}
+
+	parsedImageURL, err := url.Parse(imageURL)
+	if err != nil {
+		return ""
+	}
+
+	resolvedImageURL := pageURL.ResolveReference(parsedImageURL)
+	if checkScheme(resolvedImageURL) != nil {
+		return ""
+	}
+
+	return resolvedImageURL.String()
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q", ErrForbiddenAddress, u.Scheme)
+	}
+
+	return nil
+}
+
+// checkPublicAddress запрещает соединения с адресами локальных, частных и служебных сетей
+func checkPublicAddress(addr netip.Addr) error {
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+
+	for _, prefix := range forbiddenPrefixes {
+		if prefix.Contains(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package preview
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>  Заголовок
+		страницы </title>
+	<meta name="description" content="Описание страницы">
+	<meta property="og:title" content="Заголовок &amp; Open Graph">
+	<meta property="og:image" content="/images/cover.png">
+</head>
+<body>
+	<meta property="og:description" content="описание вне head не учитывается">
+</body>
+</html>`
+
+func allowAllAddresses(netip.Addr) error {
+	return nil
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(testPage))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/not_found", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	fetcher := newFetcher(200*time.Millisecond, MaxBodySizeDefault, allowAllAddresses)
+
+	testCases := []struct {
+		name            string
+		path            string
+		expectedPreview Preview
+		expectedErr     error
+	}{
+		{
+			name: "html page",
+			path: "/page",
+			expectedPreview: Preview{
+				Title:       "Заголовок & Open Graph",
+				Description: "Описание страницы",
+				ImageURL:    ts.URL + "/images/cover.png",
+			},
+		},
+		{
+			name: "redirect",
+			path: "/redirect",
+			expectedPreview: Preview{
+				Title:       "Заголовок & Open Graph",
+				Description: "Описание страницы",
+				ImageURL:    ts.URL + "/images/cover.png",
+			},
+		},
+		{
+			name:        "not html",
+			path:        "/json",
+			expectedErr: ErrNotHTML,
+		},
+		{
+			name:        "server unavailable",
+			path:        "/unavailable",
+			expectedErr: ErrTemporary,
+		},
+		{
+			name:        "not found",
+			path:        "/not_found",
+			expectedErr: ErrUnexpectedStatus,
+		},
+		{
+			name:        "timeout",
+			path:        "/slow",
+			expectedErr: ErrTemporary,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			preview, err := fetcher.Fetch(context.Background(), ts.URL+tc.path)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPreview, preview)
+		})
+	}
+}
+
+func TestFetcher_Fetch_forbidden(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(testPage))
+	}))
+	defer ts.Close()
+
+	fetcher := NewFetcher(time.Second, MaxBodySizeDefault)
+
+	for _, rawURL := range []string{ts.URL, "http://localhost/", "http://[::1]/", "ftp://example.com/", "file:///etc/passwd"} {
+		_, err := fetcher.Fetch(context.Background(), rawURL)
+		assert.ErrorIs(t, err, ErrForbiddenAddress, "request to %s must be forbidden", rawURL)
+	}
+}
+
+func TestFetcher_Fetch_body_size_limit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head>" + strings.Repeat("<!-- padding -->", 1024) + "<title>Заголовок</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	fetcher := newFetcher(time.Second, 1024, allowAllAddresses)
+
+	preview, err := fetcher.Fetch(context.Background(), ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, Preview{}, preview, "title after size limit must not be parsed")
+}
+
+func TestCheckPublicAddress(t *testing.T) {
+	testCases := []struct {
+		addr      string
+		forbidden bool
+	}{
+		{addr: "93.184.216.34", forbidden: false},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", forbidden: false},
+		{addr: "127.0.0.1", forbidden: true},
+		{addr: "10.1.2.3", forbidden: true},
+		{addr: "172.16.0.1", forbidden: true},
+		{addr: "192.168.1.1", forbidden: true},
+		{addr: "169.254.169.254", forbidden: true},
+		{addr: "100.64.0.1", forbidden: true},
+		{addr: "0.0.0.0", forbidden: true},
+		{addr: "::1", forbidden: true},
+		{addr: "fd00::1", forbidden: true},
+		{addr: "fe80::1", forbidden: true},
+	}
+
+	for _, tc := range testCases {
+		err := checkPublicAddress(netip.MustParseAddr(tc.addr))
+		assert.Equal(t, tc.forbidden, err != nil, "not expected result for %s", tc.addr)
+	}
+}
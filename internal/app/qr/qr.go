@@ -0,0 +1,228 @@
+// Package qr формирует изображения QR-кодов в форматах PNG и SVG
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// ErrInvalidOptions - некорректные параметры изображения QR-кода
+var ErrInvalidOptions = errors.New("invalid qr options")
+
+// FormatPNG - изображение QR-кода в формате PNG
+// FormatSVG - изображение QR-кода в формате SVG
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// SizeDefault - размер изображения по умолчанию в пикселях
+// SizeMin - минимальный размер изображения в пикселях
+// SizeMax - максимальный размер изображения в пикселях
+// MarginDefault - ширина поля вокруг QR-кода по умолчанию в модулях
+// MarginMax - максимальная ширина поля вокруг QR-кода в модулях
+// LevelDefault - уровень коррекции ошибок по умолчанию
+const (
+	SizeDefault   = 256
+	SizeMin       = 32
+	SizeMax       = 4096
+	MarginDefault = 4
+	MarginMax     = 32
+	LevelDefault  = "M"
+)
+
+// levels - соответствие обозначения уровня коррекции ошибок уровню go-qrcode
+var levels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// Options структура с описанием параметров изображения QR-кода
+type Options struct {
+	Format     string
+	Size       int
+	Margin     int
+	Level      string
+	Foreground color.NRGBA
+	Background color.NRGBA
+}
+
+// NewOptions проверяет параметры изображения QR-кода и подставляет значения по умолчанию для пустых параметров
+//
+//	format - FormatPNG или FormatSVG
+//	size - размер изображения в пикселях
+//	margin - ширина поля вокруг QR-кода в модулях, nil - MarginDefault
+//	level - уровень коррекции ошибок: L, M, Q или H
+//	foreground, background - цвета модулей и фона в виде RRGGBB или RRGGBBAA, допускается префикс "#"
+func NewOptions(format string, size int, margin *int, level string, foreground string, background string) (Options, error) {
+	options := Options{
+		Format:     strings.ToLower(format),
+		Size:       size,
+		Margin:     MarginDefault,
+		Level:      strings.ToUpper(level),
+		Foreground: color.NRGBA{A: 0xff},
+		Background: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+
+	if options.Format == "" {
+		options.Format = FormatPNG
+	} else if options.Format != FormatPNG && options.Format != FormatSVG {
+		return Options{}, fmt.Errorf("%w: unknown format %q", ErrInvalidOptions, format)
+	}
+
+	if options.Size == 0 {
+		options.Size = SizeDefault
+	} else if options.Size < SizeMin || options.Size > SizeMax {
+		return Options{}, fmt.Errorf("%w: size must be between %d and %d", ErrInvalidOptions, SizeMin, SizeMax)
+	}
+
+	if margin != nil {
+		if *margin < 0 || *margin > MarginMax {
+			return Options{}, fmt.Errorf("%w: margin must be between 0 and %d", ErrInvalidOptions, MarginMax)
+		}
+		options.Margin = *margin
+	}
+
+	if options.Level == "" {
+		options.Level = LevelDefault
+	} else if _, ok := levels[options.Level]; !ok {
+		return Options{}, fmt.Errorf("%w: unknown error correction level %q", ErrInvalidOptions, level)
+	}
+
+	var err error
+	if foreground != "" {
+		if options.Foreground, err = parseColor(foreground); err != nil {
+			return Options{}, err
+		}
+	}
+	if background != "" {
+		if options.Background, err = parseColor(background); err != nil {
+			return Options{}, err
+		}
+	}
+
+	return options, nil
+}
+
+// ContentType возвращает MIME-тип изображения QR-кода
+func (o Options) ContentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+
+	return "image/png"
+}
+
+// Render формирует изображение QR-кода с содержимым content
+func Render(content string, options Options) ([]byte, error) {
+	code, err := qrcode.New(content, levels[options.Level])
+	if err != nil {
+		return nil, fmt.Errorf("qr: error when encode content: %w", err)
+	}
+	code.DisableBorder = true
+	bitmap := code.Bitmap()
+
+	modules := len(bitmap) + 2*options.Margin
+	scale := options.Size / modules
+	if scale == 0 {
+		return nil, fmt.Errorf("%w: size %d is less than %d modules", ErrInvalidOptions, options.Size, modules)
+	}
+	// остаток, не кратный размеру модуля, распределяется по краям изображения
+	offset := (options.Size-scale*modules)/2 + scale*options.Margin
+
+	if options.Format == FormatSVG {
+		return renderSVG(bitmap, options, scale, offset), nil
+	}
+
+	return renderPNG(bitmap, options, scale, offset)
+}
+
+func renderPNG(bitmap [][]bool, options Options, scale int, offset int) ([]byte, error) {
+	img := image.NewPaletted(
+		image.Rect(0, 0, options.Size, options.Size),
+		color.Palette{options.Background, options.Foreground},
+	)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				start := img.PixOffset(offset+x*scale, offset+y*scale+dy)
+				for dx := 0; dx < scale; dx++ {
+					img.Pix[start+dx] = 1
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("qr: error when encode png: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderSVG формирует SVG-изображение, в котором соседние модули строки объединяются в один прямоугольник пути
+func renderSVG(bitmap [][]bool, options Options, scale int, offset int) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		options.Size, options.Size, options.Size, options.Size)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" %s/>`, svgFill(options.Background))
+
+	buf.WriteString(`<path d="`)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+
+			runStart := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz", offset+runStart*scale, offset+y*scale, (x-runStart)*scale, scale, (x-runStart)*scale)
+		}
+	}
+	fmt.Fprintf(&buf, `" %s/></svg>`, svgFill(options.Foreground))
+
+	return buf.Bytes()
+}
+
+func svgFill(c color.NRGBA) string {
+	fill := fmt.Sprintf(`fill="#%02x%02x%02x"`, c.R, c.G, c.B)
+	if c.A != 0xff {
+		fill += fmt.Sprintf(` fill-opacity="%s"`, strconv.FormatFloat(float64(c.A)/0xff, 'f', 3, 64))
+	}
+
+	return fill
+}
+
+// parseColor разбирает цвет в виде RRGGBB или RRGGBBAA с необязательным префиксом "#"
+func parseColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("%w: invalid color %q", ErrInvalidOptions, s)
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("%w: invalid color %q", ErrInvalidOptions, s)
+	}
+	if len(hex) == 6 {
+		value = value<<8 | 0xff
+	}
+
+	return color.NRGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}, nil
+}
@@ -0,0 +1,117 @@
+package qr
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOptions(t *testing.T) {
+	testMargin := 2
+	testNegativeMargin := -1
+
+	testCases := []struct {
+		name            string
+		format          string
+		size            int
+		margin          *int
+		level           string
+		foreground      string
+		background      string
+		expectedOptions Options
+		expectedErr     bool
+	}{
+		{
+			name: "defaults",
+			expectedOptions: Options{
+				Format:     FormatPNG,
+				Size:       SizeDefault,
+				Margin:     MarginDefault,
+				Level:      LevelDefault,
+				Foreground: color.NRGBA{A: 0xff},
+				Background: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			},
+		},
+		{
+			name:       "custom",
+			format:     "SVG",
+			size:       512,
+			margin:     &testMargin,
+			level:      "h",
+			foreground: "#1a2b3c",
+			background: "ffffff00",
+			expectedOptions: Options{
+				Format:     FormatSVG,
+				Size:       512,
+				Margin:     2,
+				Level:      "H",
+				Foreground: color.NRGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff},
+				Background: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x00},
+			},
+		},
+		{name: "unknown format", format: "gif", expectedErr: true},
+		{name: "too small size", size: SizeMin - 1, expectedErr: true},
+		{name: "too large size", size: SizeMax + 1, expectedErr: true},
+		{name: "negative margin", margin: &testNegativeMargin, expectedErr: true},
+		{name: "unknown level", level: "X", expectedErr: true},
+		{name: "invalid foreground", foreground: "black", expectedErr: true},
+		{name: "invalid background", background: "#fff", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := NewOptions(tc.format, tc.size, tc.margin, tc.level, tc.foreground, tc.background)
+			if tc.expectedErr {
+				assert.ErrorIs(t, err, ErrInvalidOptions)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOptions, options)
+		})
+	}
+}
+
+func TestRender_png(t *testing.T) {
+	margin := 0
+	options, err := NewOptions(FormatPNG, 100, &margin, "L", "ff0000", "")
+	require.NoError(t, err)
+
+	qrImage, err := Render("http://localhost:8080/abcdefghij", options)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(qrImage))
+	require.NoError(t, err)
+	assert.Equal(t, 100, img.Bounds().Dx())
+	assert.Equal(t, 100, img.Bounds().Dy())
+
+	// версия 2 QR-кода содержит 25 модулей, модуль занимает 4 пикселя, левый верхний угол - поисковый узор
+	assert.Equal(t, color.NRGBA{R: 0xff, A: 0xff}, color.NRGBAModel.Convert(img.At(0, 0)))
+	assert.Equal(t, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, color.NRGBAModel.Convert(img.At(4, 4)))
+}
+
+func TestRender_svg(t *testing.T) {
+	options, err := NewOptions(FormatSVG, 256, nil, "", "", "ffffff80")
+	require.NoError(t, err)
+
+	qrImage, err := Render("http://localhost:8080/abcdefghij", options)
+	require.NoError(t, err)
+
+	svg := string(qrImage)
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="256" height="256"`))
+	assert.Contains(t, svg, `<rect width="100%" height="100%" fill="#ffffff" fill-opacity="0.502"/>`)
+	assert.Contains(t, svg, `fill="#000000"/></svg>`)
+}
+
+func TestRender_size_too_small(t *testing.T) {
+	options, err := NewOptions(FormatPNG, SizeMin, nil, "H", "", "")
+	require.NoError(t, err)
+
+	_, err = Render("http://localhost:8080/"+strings.Repeat("a", 100), options)
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+}
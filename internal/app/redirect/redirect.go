@@ -0,0 +1,157 @@
+// Package redirect формирует адрес перенаправления по короткой ссылке: переносит в исходный URL
+// параметры запроса и путь перехода, добавляет UTM-параметры
+package redirect
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidOptions - некорректные параметры перенаправления короткой ссылки
+// ErrInvalidPath - путь перехода не может быть перенесен в исходный URL
+// ErrInvalidTarget - исходный URL не может быть разобран
+var (
+	ErrInvalidOptions = errors.New("invalid redirect options")
+	ErrInvalidPath    = errors.New("invalid redirect path")
+	ErrInvalidTarget  = errors.New("invalid redirect target")
+)
+
+// QueryPassthroughDisabled - параметры запроса перехода не переносятся
+// QueryPassthroughKeepTarget - параметры переносятся, при совпадении имен сохраняются значения исходного URL
+// QueryPassthroughOverride - параметры переносятся, при совпадении имен значения исходного URL заменяются
+// QueryPassthroughAppend - параметры переносятся, при совпадении имен сохраняются значения из обоих источников
+const (
+	QueryPassthroughDisabled   = ""
+	QueryPassthroughKeepTarget = "keep_target"
+	QueryPassthroughOverride   = "override"
+	QueryPassthroughAppend     = "append"
+)
+
+// UTMValueMaxLength - максимальная длина значения UTM-параметра
+const UTMValueMaxLength = 256
+
+// utmParams - допустимые имена UTM-параметров
+var utmParams = []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "utm_id"}
+
+// Options структура с описанием параметров перенаправления короткой ссылки
+type Options struct {
+	QueryPassthrough string
+	PathPassthrough  bool
+	UTMParams        map[string]string
+}
+
+// NormalizeOptions проверяет параметры перенаправления, приводит имена UTM-параметров к нижнему регистру
+// и удаляет UTM-параметры с пустыми значениями
+func NormalizeOptions(options Options) (Options, error) {
+	switch options.QueryPassthrough {
+	case QueryPassthroughDisabled, QueryPassthroughKeepTarget, QueryPassthroughOverride, QueryPassthroughAppend:
+	default:
+		return Options{}, fmt.Errorf("%w: unknown query passthrough policy %q", ErrInvalidOptions, options.QueryPassthrough)
+	}
+
+	var params map[string]string
+	for name, value := range options.UTMParams {
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if !slices.Contains(utmParams, name) {
+			return Options{}, fmt.Errorf("%w: unknown utm parameter %q", ErrInvalidOptions, name)
+		}
+		if utf8.RuneCountInString(value) > UTMValueMaxLength {
+			return Options{}, fmt.Errorf("%w: value of %q is longer than %d", ErrInvalidOptions, name, UTMValueMaxLength)
+		}
+		if value == "" {
+			continue
+		}
+
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[name] = value
+	}
+	options.UTMParams = params
+
+	return options, nil
+}
+
+// BuildURL формирует адрес перенаправления
+//
+//	target - исходный URL короткой ссылки
+//	options - параметры перенаправления короткой ссылки
+//	path - экранированный путь перехода после идентификатора короткой ссылки без начального "/"
+//	query - параметры запроса перехода
+//
+// UTM-параметры считаются частью исходного URL и не заменяют уже указанные в нем значения.
+// Если переносить нечего, исходный URL возвращается без изменений.
+func BuildURL(target string, options Options, path string, query url.Values) (string, error) {
+	if path != "" && !options.PathPassthrough {
+		return "", fmt.Errorf("%w: path passthrough is disabled", ErrInvalidPath)
+	}
+	if options.QueryPassthrough == QueryPassthroughDisabled {
+		query = nil
+	}
+	if path == "" && len(query) == 0 && len(options.UTMParams) == 0 {
+		return target, nil
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+
+	if path != "" {
+		targetURL, err = appendPath(targetURL, path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	targetQuery, err := url.ParseQuery(targetURL.RawQuery)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+	queryChanged := false
+	for name, value := range options.UTMParams {
+		if !targetQuery.Has(name) {
+			targetQuery.Set(name, value)
+			queryChanged = true
+		}
+	}
+	for name, values := range query {
+		switch {
+		case !targetQuery.Has(name):
+			targetQuery[name] = values
+		case options.QueryPassthrough == QueryPassthroughKeepTarget:
+			continue
+		case options.QueryPassthrough == QueryPassthroughOverride:
+			targetQuery[name] = values
+		case options.QueryPassthrough == QueryPassthroughAppend:
+			targetQuery[name] = append(targetQuery[name], values...)
+		}
+		queryChanged = true
+	}
+	if queryChanged {
+		targetURL.RawQuery = targetQuery.Encode()
+	}
+
+	return targetURL.String(), nil
+}
+
+// appendPath добавляет путь перехода к пути исходного URL, пути с сегментами "." и ".." отклоняются,
+// чтобы переход не мог выйти за пределы пути исходного URL
+func appendPath(targetURL *url.URL, path string) (*url.URL, error) {
+	unescapedPath, err := url.PathUnescape(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+	for _, segment := range strings.Split(unescapedPath, "/") {
+		if segment == "." || segment == ".." {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+		}
+	}
+
+	return targetURL.JoinPath(path), nil
+}
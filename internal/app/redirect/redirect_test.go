@@ -0,0 +1,231 @@
+package redirect
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		target      string
+		options     Options
+		path        string
+		query       string
+		expectedURL string
+		expectedErr error
+	}{
+		{
+			name:        "nothing to pass",
+			target:      "https://ya.ru/search?b=2&a=1",
+			expectedURL: "https://ya.ru/search?b=2&a=1",
+		},
+		{
+			name:        "query passthrough disabled",
+			target:      "https://ya.ru/search?q=go",
+			query:       "utm_source=mail",
+			expectedURL: "https://ya.ru/search?q=go",
+		},
+		{
+			name:        "query merged",
+			target:      "https://ya.ru/search?q=go",
+			options:     Options{QueryPassthrough: QueryPassthroughKeepTarget},
+			query:       "utm_source=mail&utm_medium=email",
+			expectedURL: "https://ya.ru/search?q=go&utm_medium=email&utm_source=mail",
+		},
+		{
+			name:        "query merged into target without query",
+			target:      "https://ya.ru",
+			options:     Options{QueryPassthrough: QueryPassthroughKeepTarget},
+			query:       "q=go",
+			expectedURL: "https://ya.ru?q=go",
+		},
+		{
+			name:        "conflict keeps target value",
+			target:      "https://ya.ru/search?q=go",
+			options:     Options{QueryPassthrough: QueryPassthroughKeepTarget},
+			query:       "q=rust",
+			expectedURL: "https://ya.ru/search?q=go",
+		},
+		{
+			name:        "conflict overrides target value",
+			target:      "https://ya.ru/search?q=go&lang=ru",
+			options:     Options{QueryPassthrough: QueryPassthroughOverride},
+			query:       "q=rust&q=zig",
+			expectedURL: "https://ya.ru/search?lang=ru&q=rust&q=zig",
+		},
+		{
+			name:        "conflict appends values",
+			target:      "https://ya.ru/search?q=go",
+			options:     Options{QueryPassthrough: QueryPassthroughAppend},
+			query:       "q=rust",
+			expectedURL: "https://ya.ru/search?q=go&q=rust",
+		},
+		{
+			name:        "query values are escaped",
+			target:      "https://ya.ru/search",
+			options:     Options{QueryPassthrough: QueryPassthroughOverride},
+			query:       "q=%D0%B3%D0%BE+%26+rust",
+			expectedURL: "https://ya.ru/search?q=%D0%B3%D0%BE+%26+rust",
+		},
+		{
+			name:        "fragment is kept",
+			target:      "https://ya.ru/docs#intro",
+			options:     Options{QueryPassthrough: QueryPassthroughKeepTarget},
+			query:       "v=2",
+			expectedURL: "https://ya.ru/docs?v=2#intro",
+		},
+		{
+			name:        "utm params injected",
+			target:      "https://ya.ru/",
+			options:     Options{UTMParams: map[string]string{"utm_source": "shortener", "utm_campaign": "autumn sale"}},
+			expectedURL: "https://ya.ru/?utm_campaign=autumn+sale&utm_source=shortener",
+		},
+		{
+			name:        "utm params do not replace target params",
+			target:      "https://ya.ru/?utm_source=site",
+			options:     Options{UTMParams: map[string]string{"utm_source": "shortener"}},
+			expectedURL: "https://ya.ru/?utm_source=site",
+		},
+		{
+			name:   "incoming query overrides utm params",
+			target: "https://ya.ru/",
+			options: Options{
+				QueryPassthrough: QueryPassthroughOverride,
+				UTMParams:        map[string]string{"utm_source": "shortener"},
+			},
+			query:       "utm_source=mail",
+			expectedURL: "https://ya.ru/?utm_source=mail",
+		},
+		{
+			name:        "path appended",
+			target:      "https://ya.ru/docs",
+			options:     Options{PathPassthrough: true},
+			path:        "extra/path",
+			expectedURL: "https://ya.ru/docs/extra/path",
+		},
+		{
+			name:        "path appended to target with trailing slash and query",
+			target:      "https://ya.ru/docs/?v=2",
+			options:     Options{PathPassthrough: true},
+			path:        "extra/",
+			expectedURL: "https://ya.ru/docs/extra/?v=2",
+		},
+		{
+			name:        "path appended to target without path",
+			target:      "https://ya.ru",
+			options:     Options{PathPassthrough: true},
+			path:        "extra",
+			expectedURL: "https://ya.ru/extra",
+		},
+		{
+			name:        "escaped path kept",
+			target:      "https://ya.ru/docs",
+			options:     Options{PathPassthrough: true},
+			path:        "a%20b/%D1%8F",
+			expectedURL: "https://ya.ru/docs/a%20b/%D1%8F",
+		},
+		{
+			name:        "path and query",
+			target:      "https://ya.ru/docs",
+			options:     Options{PathPassthrough: true, QueryPassthrough: QueryPassthroughKeepTarget},
+			path:        "extra",
+			query:       "v=2",
+			expectedURL: "https://ya.ru/docs/extra?v=2",
+		},
+		{
+			name:        "path passthrough disabled",
+			target:      "https://ya.ru/docs",
+			path:        "extra",
+			expectedErr: ErrInvalidPath,
+		},
+		{
+			name:        "dot segments rejected",
+			target:      "https://ya.ru/docs",
+			options:     Options{PathPassthrough: true},
+			path:        "extra/../../admin",
+			expectedErr: ErrInvalidPath,
+		},
+		{
+			name:        "escaped dot segments rejected",
+			target:      "https://ya.ru/docs",
+			options:     Options{PathPassthrough: true},
+			path:        "%2e%2e%2fadmin",
+			expectedErr: ErrInvalidPath,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tc.query)
+			require.NoError(t, err)
+
+			redirectURL, err := BuildURL(tc.target, tc.options, tc.path, query)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedURL, redirectURL)
+		})
+	}
+}
+
+func TestNormalizeOptions(t *testing.T) {
+	testCases := []struct {
+		name            string
+		options         Options
+		expectedOptions Options
+		expectedErr     error
+	}{
+		{
+			name:            "empty options",
+			options:         Options{},
+			expectedOptions: Options{},
+		},
+		{
+			name: "utm params normalized",
+			options: Options{
+				QueryPassthrough: QueryPassthroughAppend,
+				UTMParams:        map[string]string{" UTM_Source ": " mail ", "utm_term": " "},
+			},
+			expectedOptions: Options{
+				QueryPassthrough: QueryPassthroughAppend,
+				UTMParams:        map[string]string{"utm_source": "mail"},
+			},
+		},
+		{
+			name:        "unknown query passthrough policy",
+			options:     Options{QueryPassthrough: "merge"},
+			expectedErr: ErrInvalidOptions,
+		},
+		{
+			name:        "unknown utm param",
+			options:     Options{UTMParams: map[string]string{"ref": "mail"}},
+			expectedErr: ErrInvalidOptions,
+		},
+		{
+			name:        "too long utm value",
+			options:     Options{UTMParams: map[string]string{"utm_source": strings.Repeat("a", UTMValueMaxLength+1)}},
+			expectedErr: ErrInvalidOptions,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := NormalizeOptions(tc.options)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOptions, options)
+		})
+	}
+}
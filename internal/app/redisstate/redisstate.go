@@ -0,0 +1,266 @@
+// Package redisstate хранит общее для нескольких экземпляров сервиса состояние (кэш, счетчики) в Redis
+// или совместимом с ним по протоколу хранилище
+//
+// Все ключи получают настраиваемый префикс. Если Redis недоступен, операции сразу завершаются ошибкой
+// ErrUnavailable в течение retryInterval, а не ждут таймаута на каждом запросе, поэтому вызывающий код
+// может быстро перейти на локальное состояние или основное хранилище.
+package redisstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var log = zap.Must(zap.NewDevelopment()).Sugar()
+
+// ErrUnavailable - Redis недоступен
+var ErrUnavailable = errors.New("redisstate: redis is unavailable")
+
+// operationTimeout - таймаут операции с Redis
+// retryInterval - время, в течение которого после ошибки Redis считается недоступным
+// scanBatchSize - количество ключей, запрашиваемых за одну итерацию SCAN
+const (
+	operationTimeout = 500 * time.Millisecond
+	retryInterval    = 5 * time.Second
+	scanBatchSize    = 100
+)
+
+// Client структура с описанием клиента Redis
+type Client struct {
+	client    *redis.Client
+	prefix    string
+	downUntil atomic.Int64
+	now       func() time.Time
+}
+
+// NewClient создает экземпляр структуры Client
+//
+//	redisURL - адрес Redis вида "redis://[[user]:password@]host:port[/db]"
+//	prefix - префикс ключей, позволяет нескольким сервисам использовать один Redis
+func NewClient(redisURL string, prefix string) (*Client, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("redisstate: error when parse redis url: %w", err)
+	}
+	options.DialTimeout = operationTimeout
+	options.ReadTimeout = operationTimeout
+	options.WriteTimeout = operationTimeout
+	// повторы при ошибке увеличивают задержку запроса, а Redis не является основным хранилищем
+	options.MaxRetries = -1
+
+	return &Client{
+		client: redis.NewClient(options),
+		prefix: prefix,
+		now:    time.Now,
+	}, nil
+}
+
+// Ping проверяет доступность Redis
+func (c *Client) Ping(ctx context.Context) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Ping(ctx).Err()
+	})
+}
+
+// Get возвращает значение по ключу key и признак его наличия
+func (c *Client) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	var found bool
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = c.client.Get(ctx, c.prefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		found = err == nil
+
+		return err
+	})
+
+	return value, found, err
+}
+
+// Set сохраняет значение value по ключу key со временем жизни ttl, 0 - ключ не истекает
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+	})
+}
+
+// Del удаляет ключи keys
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixedKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixedKeys = append(prefixedKeys, c.prefix+key)
+	}
+
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Del(ctx, prefixedKeys...).Err()
+	})
+}
+
+// Keys возвращает все ключи, начинающиеся с keyPrefix, без префикса клиента
+func (c *Client) Keys(ctx context.Context, keyPrefix string) ([]string, error) {
+	keys := make([]string, 0)
+	err := c.do(ctx, func(ctx context.Context) error {
+		iter := c.client.Scan(ctx, 0, c.prefix+keyPrefix+"*", scanBatchSize).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val()[len(c.prefix):])
+		}
+
+		return iter.Err()
+	})
+
+	return keys, err
+}
+
+// DelByPrefix удаляет все ключи, начинающиеся с keyPrefix
+func (c *Client) DelByPrefix(ctx context.Context, keyPrefix string) error {
+	keys, err := c.Keys(ctx, keyPrefix)
+	if err != nil {
+		return err
+	}
+
+	return c.Del(ctx, keys...)
+}
+
+// incrScript увеличивает счетчик и задает время жизни созданного счетчика атомарно
+var incrScript = redis.NewScript(`
+local value = redis.call("INCR", KEYS[1])
+if value == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return value
+`)
+
+// decrScript уменьшает существующий положительный счетчик, не создавая новый счетчик без времени жизни
+var decrScript = redis.NewScript(`
+local value = tonumber(redis.call("GET", KEYS[1]))
+if value == nil or value <= 0 then
+	return 0
+end
+return redis.call("DECR", KEYS[1])
+`)
+
+// Incr увеличивает счетчик key на единицу и возвращает новое значение
+//
+// ttl - время жизни счетчика, отсчитывается от его создания, 0 - счетчик не истекает
+func (c *Client) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var value int64
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = incrScript.Run(ctx, c.client, []string{c.prefix + key}, ttl.Milliseconds()).Int64()
+
+		return err
+	})
+
+	return value, err
+}
+
+// Decr уменьшает существующий положительный счетчик key на единицу и возвращает новое значение
+func (c *Client) Decr(ctx context.Context, key string) (int64, error) {
+	var value int64
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = decrScript.Run(ctx, c.client, []string{c.prefix + key}).Int64()
+
+		return err
+	})
+
+	return value, err
+}
+
+// GetInt возвращает значение счетчика key, 0 - счетчик не существует
+func (c *Client) GetInt(ctx context.Context, key string) (int64, error) {
+	var value int64
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = c.client.Get(ctx, c.prefix+key).Int64()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+
+		return err
+	})
+
+	return value, err
+}
+
+// HIncr увеличивает счетчик field хеша key на единицу
+func (c *Client) HIncr(ctx context.Context, key string, field string) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.HIncrBy(ctx, c.prefix+key, field, 1).Err()
+	})
+}
+
+// HGetAllInt возвращает все счетчики хеша key
+func (c *Client) HGetAllInt(ctx context.Context, key string) (map[string]int64, error) {
+	var values map[string]string
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		values, err = c.client.HGetAll(ctx, c.prefix+key).Result()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(values))
+	for field, value := range values {
+		result[field], err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redisstate: unexpected value of %s in %s: %w", field, key, err)
+		}
+	}
+
+	return result, nil
+}
+
+// Close закрывает соединения с Redis
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// do выполняет операцию fn с таймаутом, если Redis не был признан недоступным
+func (c *Client) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	downUntil := c.downUntil.Load()
+	if c.now().UnixNano() < downUntil {
+		return ErrUnavailable
+	}
+
+	operationCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	err := fn(operationCtx)
+	if err != nil && ctx.Err() != nil {
+		// запрос отменен вызывающим кодом, доступность Redis не изменилась
+		return err
+	}
+
+	var redisErr redis.Error
+	// ошибка, которую вернул сам Redis (например, неверный тип ключа), не означает его недоступность
+	if err == nil || errors.As(err, &redisErr) {
+		if downUntil != 0 && c.downUntil.CompareAndSwap(downUntil, 0) {
+			log.Infow("redisstate: redis is available again")
+		}
+
+		return err
+	}
+
+	if c.downUntil.Swap(c.now().Add(retryInterval).UnixNano()) == 0 {
+		log.Warnw("redisstate: redis is unavailable, falling back to local state", "error", err)
+	}
+
+	return fmt.Errorf("%w: %v", ErrUnavailable, err)
+}
@@ -0,0 +1,151 @@
+package redisstate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) (*Client, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client, err := NewClient("redis://"+server.Addr(), "test:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, server
+}
+
+func TestClient_Get(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	_, found, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, client.Set(ctx, "key", []byte("value"), time.Minute))
+	value, found, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "value", string(value))
+	assert.True(t, server.Exists("test:key"), "key must be prefixed")
+
+	server.FastForward(time.Minute)
+	_, found, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, found, "key must expire")
+}
+
+func TestClient_DelByPrefix(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	require.NoError(t, server.Set("other:a:1", "value"))
+
+	for _, key := range []string{"a:1", "a:2", "b:1"} {
+		require.NoError(t, client.Set(ctx, key, []byte("value"), 0))
+	}
+
+	keys, err := client.Keys(ctx, "a:")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a:1", "a:2"}, keys)
+
+	require.NoError(t, client.DelByPrefix(ctx, "a:"))
+	assert.ElementsMatch(t, []string{"other:a:1", "test:b:1"}, server.Keys())
+}
+
+func TestClient_Incr(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		value, err := client.Incr(ctx, "counter", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, i, value)
+	}
+
+	value, err := client.GetInt(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), value)
+
+	server.FastForward(time.Minute)
+	value, err = client.GetInt(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), value, "counter must expire after ttl from its creation")
+}
+
+func TestClient_Decr(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.Incr(ctx, "counter", time.Minute)
+	require.NoError(t, err)
+	value, err := client.Decr(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+
+	value, err = client.Decr(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), value, "counter must not become negative")
+
+	value, err = client.Decr(ctx, "missing")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+	assert.False(t, server.Exists("test:missing"), "missing counter must not be created")
+}
+
+func TestClient_HIncr(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, client.HIncr(ctx, "clicks", "a"))
+	require.NoError(t, client.HIncr(ctx, "clicks", "a"))
+	require.NoError(t, client.HIncr(ctx, "clicks", "b"))
+
+	clicks, err := client.HGetAllInt(ctx, "clicks")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 2, "b": 1}, clicks)
+
+	clicks, err = client.HGetAllInt(ctx, "not_existed")
+	require.NoError(t, err)
+	assert.Empty(t, clicks)
+}
+
+func TestClient_unavailable(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	require.NoError(t, client.Set(ctx, "key", []byte("value"), 0))
+
+	server.Close()
+	_, _, err := client.Get(ctx, "key")
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	require.NoError(t, server.Restart())
+	_, _, err = client.Get(ctx, "key")
+	assert.ErrorIs(t, err, ErrUnavailable, "redis must be considered unavailable until retry interval passes")
+
+	now = now.Add(retryInterval)
+	value, found, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "value", string(value))
+}
+
+func TestClient_redis_error(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	require.NoError(t, server.Set("test:key", "value"))
+
+	err := client.HIncr(ctx, "key", "field")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnavailable, "error returned by redis must not make it unavailable")
+
+	_, _, err = client.Get(ctx, "key")
+	assert.NoError(t, err)
+}
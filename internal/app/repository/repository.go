@@ -2,7 +2,13 @@ package repository
 
 import (
 	"errors"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
 	"go.uber.org/zap"
 )
 
@@ -10,9 +16,176 @@ var log = zap.Must(zap.NewDevelopment()).Sugar()
 
 // ErrConflict - короткая ссылка уже существует
 // ErrNotFound - короткая ссылка не найдена
+// ErrClicksExhausted - переходы по короткой ссылке исчерпаны
 // ErrUnexpected - непредвиденная ошибка
 var (
-	ErrConflict   = errors.New("conflict")
-	ErrNotFound   = errors.New("entity not found")
-	ErrUnexpected = errors.New("unexpected error")
+	ErrConflict        = errors.New("conflict")
+	ErrNotFound        = errors.New("entity not found")
+	ErrClicksExhausted = errors.New("clicks exhausted")
+	ErrUnexpected      = errors.New("unexpected error")
 )
+
+// shortURLPageSize - количество коротких ссылок, читаемых за один запрос при обходе всех коротких ссылок
+const shortURLPageSize = 1000
+
+// forEachShortURLPage вызывает fn для каждой короткой ссылки страниц, возвращаемых readPage
+//
+// readPage возвращает не более shortURLPageSize коротких ссылок, следующих за короткой ссылкой after,
+// nil - первую страницу. Соединение с хранилищем не удерживается, пока fn обрабатывает страницу.
+func forEachShortURLPage(
+	readPage func(after *entity.ShortURLEntity) ([]entity.ShortURLEntity, error),
+	fn func(shortURLEntity entity.ShortURLEntity) error,
+) error {
+	var after *entity.ShortURLEntity
+	for {
+		shortURLEntities, err := readPage(after)
+		if err != nil {
+			return err
+		}
+
+		for _, shortURLEntity := range shortURLEntities {
+			if err := fn(shortURLEntity); err != nil {
+				return err
+			}
+		}
+
+		if len(shortURLEntities) < shortURLPageSize {
+			return nil
+		}
+		after = &shortURLEntities[len(shortURLEntities)-1]
+	}
+}
+
+// deleteStatus возвращает результат удаления короткой ссылки shortURI пользователем userID
+// по отображению shortURI на идентификатор владельца короткой ссылки
+func deleteStatus(ownerByShortURI map[string]string, shortURI string, userID string) string {
+	ownerID, ok := ownerByShortURI[shortURI]
+	if !ok {
+		return entity.DeleteStatusNotFound
+	}
+
+	if ownerID != userID {
+		return entity.DeleteStatusNotOwner
+	}
+
+	return entity.DeleteStatusDeleted
+}
+
+// restoreStatus возвращает результат восстановления короткой ссылки пользователем userID
+// по признаку ее существования, идентификатору владельца и признаку удаления
+func restoreStatus(exists bool, ownerID string, deleted bool, userID string) string {
+	if !exists {
+		return entity.RestoreStatusNotFound
+	}
+
+	if ownerID != userID {
+		return entity.RestoreStatusNotOwner
+	}
+
+	if !deleted {
+		return entity.RestoreStatusNotDeleted
+	}
+
+	return entity.RestoreStatusRestored
+}
+
+// matchShortURLQuery проверяет, что короткая ссылка удовлетворяет фильтрам запроса query
+func matchShortURLQuery(shortURLEntity *entity.ShortURLEntity, query entity.ShortURLQueryEntity) bool {
+	switch query.Status {
+	case entity.ShortURLStatusActive:
+		if shortURLEntity.Deleted {
+			return false
+		}
+	case entity.ShortURLStatusDeleted:
+		if !shortURLEntity.Deleted {
+			return false
+		}
+	}
+
+	if query.CreatedFrom != nil && shortURLEntity.CreatedAt.Before(*query.CreatedFrom) {
+		return false
+	}
+
+	if query.CreatedTo != nil && shortURLEntity.CreatedAt.After(*query.CreatedTo) {
+		return false
+	}
+
+	if query.Domain != "" && !matchDomain(shortURLEntity.LongURL, query.Domain) {
+		return false
+	}
+
+	if query.Tag != "" && !slices.Contains(shortURLEntity.Tags, query.Tag) {
+		return false
+	}
+
+	if query.Search != "" {
+		search := strings.ToLower(query.Search)
+		if !strings.Contains(strings.ToLower(shortURLEntity.LongURL), search) &&
+			!strings.Contains(strings.ToLower(shortURLEntity.ShortURI), search) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchDomain проверяет, что хост URL longURL совпадает с domain или является его поддоменом
+func matchDomain(longURL string, domain string) bool {
+	parsedURL, err := url.Parse(longURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(parsedURL.Hostname())
+	domain = strings.ToLower(domain)
+
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// domainRegexp возвращает регулярное выражение, аналогичное проверке matchDomain, для фильтрации в БД
+func domainRegexp(domain string) string {
+	return `^[a-z][a-z0-9+.-]*://([^/?#@]*@)?([^/?#@:]*\.)?` + regexp.QuoteMeta(domain) + `(:[0-9]*)?([/?#]|$)`
+}
+
+// escapeLike экранирует специальные символы шаблона LIKE
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// compareShortURLs сравнивает короткие ссылки по полю сортировки sortBy, а при равенстве - по UUID
+func compareShortURLs(a *entity.ShortURLEntity, b *entity.ShortURLEntity, sortBy string) int {
+	var result int
+	switch sortBy {
+	case entity.ShortURLSortByCreatedAt:
+		result = a.CreatedAt.Compare(b.CreatedAt)
+	case entity.ShortURLSortByOriginalURL:
+		result = strings.Compare(a.LongURL, b.LongURL)
+	case entity.ShortURLSortByShortURL:
+		result = strings.Compare(a.ShortURI, b.ShortURI)
+	}
+
+	if result != 0 {
+		return result
+	}
+
+	return strings.Compare(a.UUID, b.UUID)
+}
+
+// cursorShortURL возвращает короткую ссылку, занимающую позицию cursor в списке, упорядоченном по полю sortBy
+func cursorShortURL(cursor entity.ShortURLCursorEntity, sortBy string) (*entity.ShortURLEntity, error) {
+	shortURLEntity := &entity.ShortURLEntity{UUID: cursor.UUID}
+	switch sortBy {
+	case entity.ShortURLSortByCreatedAt:
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.SortValue)
+		if err != nil {
+			return nil, err
+		}
+		shortURLEntity.CreatedAt = createdAt
+	case entity.ShortURLSortByOriginalURL:
+		shortURLEntity.LongURL = cursor.SortValue
+	case entity.ShortURLSortByShortURL:
+		shortURLEntity.ShortURI = cursor.SortValue
+	}
+
+	return shortURLEntity, nil
+}
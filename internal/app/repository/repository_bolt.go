@@ -0,0 +1,880 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+	"go.etcd.io/bbolt"
+)
+
+// boltOpenTimeout - время ожидания блокировки файла хранилища, занятого другим процессом
+const boltOpenTimeout = time.Second
+
+// Бакеты хранилища BoltShortURLRepository
+//
+//	shortURLsBucket - короткие ссылки по ключу короткой ссылки
+//	originalURLsBucket - ключ короткой ссылки по домену и исходному URL для проверки уникальности исходного URL
+//	userShortURLsBucket - вложенные бакеты пользователей с ключами их коротких ссылок
+//	deleteJobsBucket - задачи на удаление коротких ссылок по идентификатору
+//	splitClicksBucket - вложенные бакеты коротких ссылок с количеством переходов по вариантам перенаправления
+//	countryClicksBucket - вложенные бакеты коротких ссылок с количеством переходов по странам
+//	userSettingsBucket - настройки пользователей по идентификатору пользователя
+var (
+	shortURLsBucket     = []byte("short_urls")
+	originalURLsBucket  = []byte("original_urls")
+	userShortURLsBucket = []byte("user_short_urls")
+	deleteJobsBucket    = []byte("delete_jobs")
+	splitClicksBucket   = []byte("split_clicks")
+	countryClicksBucket = []byte("country_clicks")
+	userSettingsBucket  = []byte("user_settings")
+)
+
+// errShortURIExists - короткая ссылка с таким ключом уже существует
+var errShortURIExists = errors.New("short uri already exists")
+
+// BoltShortURLRepository реализует интерфейс IShortURLRepository для хранения коротких ссылок
+// во встроенном key-value хранилище bbolt
+//
+// Хранилище - один файл, который открывается только одним процессом. Запись выполняется в транзакциях,
+// поэтому пачка коротких ссылок сохраняется целиком или не сохраняется совсем.
+type BoltShortURLRepository struct {
+	db *bbolt.DB
+}
+
+// NewBoltShortURLRepository открывает или создает файл хранилища path и создает экземпляр структуры BoltShortURLRepository
+func NewBoltShortURLRepository(path string) (*BoltShortURLRepository, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("repository: error when open kv storage: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, bucket := range [][]byte{
+			shortURLsBucket, originalURLsBucket, userShortURLsBucket, deleteJobsBucket, splitClicksBucket, countryClicksBucket, userSettingsBucket,
+		} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("repository: error when create kv storage buckets: %w", err), db.Close())
+	}
+
+	return &BoltShortURLRepository{db: db}, nil
+}
+
+// Close закрывает файл хранилища
+func (r *BoltShortURLRepository) Close() error {
+	return r.db.Close()
+}
+
+// BackupBoltFile копирует согласованный снимок файла хранилища path в файл backupPath
+//
+// Хранилище открывается только для чтения, поэтому копирование выполняется при остановленном сервисе:
+// пока файл хранилища открыт сервисом, BackupBoltFile завершается ошибкой по истечении boltOpenTimeout.
+func BackupBoltFile(path string, backupPath string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("repository: kv storage is not available: %w", err)
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: boltOpenTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("repository: error when open kv storage: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Errorw("repository: error when close kv storage", "err", err)
+		}
+	}()
+
+	return db.View(func(tx *bbolt.Tx) error {
+		return tx.CopyFile(backupPath, 0600)
+	})
+}
+
+// GetRedirectShortURLByShortURI возвращает короткую ссылку по shortURI для перенаправления,
+// хранилище возвращает короткую ссылку полностью
+func (r *BoltShortURLRepository) GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	return r.GetShortURLByShortURI(ctx, shortURI)
+}
+
+// GetShortURLByShortURI возвращает короткую ссылку по shortURI
+func (r *BoltShortURLRepository) GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	var shortURLEntity entity.ShortURLEntity
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		shortURLEntity, err = getBoltShortURL(tx, shortURI)
+
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return entity.ShortURLEntity{}, ErrNotFound
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	return shortURLEntity, nil
+}
+
+// SaveShortURL сохраняет короткую ссылку
+//
+// Если исходный URL уже сокращен на домене короткой ссылки, возвращает существующую короткую ссылку и ErrConflict,
+// если занят ключ короткой ссылки - nil и ErrConflict.
+func (r *BoltShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+	if shortURLEntity.CreatedAt.IsZero() {
+		shortURLEntity.CreatedAt = time.Now()
+	}
+
+	var existedShortURLEntity entity.ShortURLEntity
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		existedShortURI := tx.Bucket(originalURLsBucket).Get(boltOriginalURLKey(shortURLEntity))
+		if existedShortURI != nil {
+			var err error
+			existedShortURLEntity, err = getBoltShortURL(tx, string(existedShortURI))
+			if err != nil {
+				return err
+			}
+
+			return ErrConflict
+		}
+
+		return putBoltShortURL(tx, shortURLEntity)
+	})
+	if err != nil {
+		if errors.Is(err, ErrConflict) {
+			return &existedShortURLEntity, ErrConflict
+		}
+		if errors.Is(err, errShortURIExists) {
+			return nil, ErrConflict
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return shortURLEntity, nil
+}
+
+// SaveShortURLs сохраняет короткие ссылки пачкой в одной транзакции
+//
+// Если ключ или исходный URL одной из коротких ссылок уже заняты или повторяются в пачке, не сохраняется ни одна
+// короткая ссылка пачки и возвращается ErrConflict.
+func (r *BoltShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+	now := time.Now()
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		for i := range shortURLEntities {
+			shortURLEntity := &shortURLEntities[i]
+			if shortURLEntity.CreatedAt.IsZero() {
+				shortURLEntity.CreatedAt = now
+			}
+
+			if tx.Bucket(originalURLsBucket).Get(boltOriginalURLKey(shortURLEntity)) != nil {
+				return fmt.Errorf("%w: original url %q", ErrConflict, shortURLEntity.LongURL)
+			}
+
+			if err := putBoltShortURL(tx, shortURLEntity); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrConflict) || errors.Is(err, errShortURIExists) {
+			return nil, ErrConflict
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return shortURLEntities, nil
+}
+
+// GetShortURLsByUserID возвращает список коротких ссылок по userID
+func (r *BoltShortURLRepository) GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
+	shortURLEntities, err := r.getShortURLsByUserID(userID)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return shortURLEntities, nil
+}
+
+func (r *BoltShortURLRepository) getShortURLsByUserID(userID string) ([]entity.ShortURLEntity, error) {
+	result := make([]entity.ShortURLEntity, 0)
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		userBucket := tx.Bucket(userShortURLsBucket).Bucket([]byte(userID))
+		if userBucket == nil {
+			return nil
+		}
+
+		return userBucket.ForEach(func(shortURI []byte, _ []byte) error {
+			shortURLEntity, err := getBoltShortURL(tx, string(shortURI))
+			if err != nil {
+				return err
+			}
+
+			result = append(result, shortURLEntity)
+
+			return nil
+		})
+	})
+
+	return result, err
+}
+
+// ForEachShortURL вызывает fn для каждой короткой ссылки, включая удаленные, в порядке ключей коротких ссылок
+//
+// Обход прекращается при первой ошибке fn, которая возвращается без изменений.
+func (r *BoltShortURLRepository) ForEachShortURL(ctx context.Context, fn func(shortURLEntity entity.ShortURLEntity) error) error {
+	return forEachShortURLPage(func(after *entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+		shortURLEntities, err := r.getShortURLsPage(after)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
+		return shortURLEntities, nil
+	}, fn)
+}
+
+// getShortURLsPage возвращает не более shortURLPageSize коротких ссылок с ключами, следующими за ключом
+// короткой ссылки after, nil - с первого ключа
+func (r *BoltShortURLRepository) getShortURLsPage(after *entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+	result := make([]entity.ShortURLEntity, 0, shortURLPageSize)
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(shortURLsBucket).Cursor()
+
+		shortURI, shortURLJSON := cursor.First()
+		if after != nil {
+			shortURI, shortURLJSON = cursor.Seek([]byte(after.ShortURI))
+			if shortURI != nil && string(shortURI) == after.ShortURI {
+				shortURI, shortURLJSON = cursor.Next()
+			}
+		}
+
+		for ; shortURI != nil && len(result) < shortURLPageSize; shortURI, shortURLJSON = cursor.Next() {
+			var shortURLEntity entity.ShortURLEntity
+			if err := json.Unmarshal(shortURLJSON, &shortURLEntity); err != nil {
+				return err
+			}
+
+			result = append(result, shortURLEntity)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// GetShortURLsByQuery возвращает страницу коротких ссылок пользователя, удовлетворяющих запросу query,
+// и общее количество удовлетворяющих фильтрам запроса коротких ссылок
+func (r *BoltShortURLRepository) GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error) {
+	shortURLEntities, err := r.getShortURLsByUserID(query.UserID)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, 0, ErrUnexpected
+	}
+
+	matchedShortURLEntities := make([]*entity.ShortURLEntity, 0, len(shortURLEntities))
+	for i := range shortURLEntities {
+		if matchShortURLQuery(&shortURLEntities[i], query) {
+			matchedShortURLEntities = append(matchedShortURLEntities, &shortURLEntities[i])
+		}
+	}
+
+	compare := func(a *entity.ShortURLEntity, b *entity.ShortURLEntity) int {
+		if query.SortDesc {
+			return compareShortURLs(b, a, query.SortBy)
+		}
+		return compareShortURLs(a, b, query.SortBy)
+	}
+	slices.SortFunc(matchedShortURLEntities, compare)
+
+	start := 0
+	if query.After != nil {
+		afterShortURLEntity, err := cursorShortURL(*query.After, query.SortBy)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, 0, ErrUnexpected
+		}
+
+		start = sort.Search(len(matchedShortURLEntities), func(i int) bool {
+			return compare(matchedShortURLEntities[i], afterShortURLEntity) > 0
+		})
+	}
+
+	end := min(start+query.Limit, len(matchedShortURLEntities))
+	result := make([]entity.ShortURLEntity, 0, end-start)
+	for _, shortURLEntity := range matchedShortURLEntities[start:end] {
+		result = append(result, *shortURLEntity)
+	}
+
+	return result, len(matchedShortURLEntities), nil
+}
+
+// GetDeletedShortURLsByUserID возвращает список удаленных коротких ссылок пользователя userID
+func (r *BoltShortURLRepository) GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
+	shortURLEntities, err := r.getShortURLsByUserID(userID)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	result := make([]entity.ShortURLEntity, 0)
+	for _, shortURLEntity := range shortURLEntities {
+		if shortURLEntity.Deleted {
+			result = append(result, shortURLEntity)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return deletedAt(result[i]).After(deletedAt(result[j]))
+	})
+
+	return result, nil
+}
+
+// UpdateShortURL обновляет изменяемые поля короткой ссылки пользователя: название, описание, заметки, теги
+// и параметры перенаправления
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена или принадлежит другому пользователю.
+func (r *BoltShortURLRepository) UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error) {
+	var storedShortURLEntity entity.ShortURLEntity
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		storedShortURLEntity, err = getBoltShortURL(tx, shortURLEntity.ShortURI)
+		if err != nil {
+			return err
+		}
+		if storedShortURLEntity.UserID != shortURLEntity.UserID {
+			return ErrNotFound
+		}
+
+		storedShortURLEntity.Title = shortURLEntity.Title
+		storedShortURLEntity.Description = shortURLEntity.Description
+		storedShortURLEntity.Notes = shortURLEntity.Notes
+		storedShortURLEntity.Tags = shortURLEntity.Tags
+		storedShortURLEntity.RedirectStatus = shortURLEntity.RedirectStatus
+		storedShortURLEntity.QueryPassthrough = shortURLEntity.QueryPassthrough
+		storedShortURLEntity.PathPassthrough = shortURLEntity.PathPassthrough
+		storedShortURLEntity.UTMParams = shortURLEntity.UTMParams
+		storedShortURLEntity.TargetingRules = shortURLEntity.TargetingRules
+		storedShortURLEntity.SplitVariants = shortURLEntity.SplitVariants
+		storedShortURLEntity.PasswordHash = shortURLEntity.PasswordHash
+		storedShortURLEntity.MaxClicks = shortURLEntity.MaxClicks
+		storedShortURLEntity.NotBefore = shortURLEntity.NotBefore
+		storedShortURLEntity.NotAfter = shortURLEntity.NotAfter
+		storedShortURLEntity.Disabled = shortURLEntity.Disabled
+		storedShortURLEntity.FallbackURL = shortURLEntity.FallbackURL
+
+		return updateBoltShortURL(tx, &storedShortURLEntity)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return entity.ShortURLEntity{}, ErrNotFound
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	return storedShortURLEntity, nil
+}
+
+// UpdateShortURLPreview сохраняет превью страницы исходного URL короткой ссылки
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена.
+func (r *BoltShortURLRepository) UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error {
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		storedShortURLEntity, err := getBoltShortURL(tx, shortURI)
+		if err != nil {
+			return err
+		}
+
+		fetchedAt := preview.FetchedAt
+		storedShortURLEntity.PreviewTitle = preview.Title
+		storedShortURLEntity.PreviewDescription = preview.Description
+		storedShortURLEntity.PreviewImageURL = preview.ImageURL
+		storedShortURLEntity.PreviewFetchedAt = &fetchedAt
+
+		return updateBoltShortURL(tx, &storedShortURLEntity)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrNotFound
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// DeleteShortURLsByShortURIs удаляет короткие ссылки пользователя по списку shortURI
+// и возвращает результат удаления по каждой из них
+func (r *BoltShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	now := time.Now()
+
+	ownerByShortURI := make(map[string]string, len(shortURIs))
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		for _, shortURI := range shortURIs {
+			shortURLEntity, err := getBoltShortURL(tx, shortURI)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			ownerByShortURI[shortURI] = shortURLEntity.UserID
+			if shortURLEntity.UserID == userID && !shortURLEntity.Deleted {
+				shortURLEntity.Deleted = true
+				shortURLEntity.DeletedAt = &now
+				if err := updateBoltShortURL(tx, &shortURLEntity); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	result := make([]entity.DeleteShortURLResultEntity, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		result = append(result, entity.DeleteShortURLResultEntity{
+			ShortURI: shortURI,
+			Status:   deleteStatus(ownerByShortURI, shortURI, userID),
+		})
+	}
+
+	return result, nil
+}
+
+// RestoreShortURLsByShortURIs восстанавливает удаленные короткие ссылки пользователя по списку shortURI
+// и возвращает результат восстановления по каждой из них
+func (r *BoltShortURLRepository) RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+
+	result := make([]entity.RestoreShortURLResultEntity, 0, len(shortURIs))
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		for _, shortURI := range shortURIs {
+			shortURLEntity, err := getBoltShortURL(tx, shortURI)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+
+			status := restoreStatus(err == nil, shortURLEntity.UserID, shortURLEntity.Deleted, userID)
+			if status == entity.RestoreStatusRestored {
+				shortURLEntity.Deleted = false
+				shortURLEntity.DeletedAt = nil
+				if err := updateBoltShortURL(tx, &shortURLEntity); err != nil {
+					return err
+				}
+			}
+
+			result = append(result, entity.RestoreShortURLResultEntity{
+				ShortURI: shortURI,
+				Status:   status,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return result, nil
+}
+
+// PurgeDeletedShortURLs окончательно удаляет короткие ссылки, удаленные раньше deletedBefore,
+// и возвращает количество удаленных ссылок
+func (r *BoltShortURLRepository) PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	purgedCount := 0
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		purgedShortURLEntities := make([]entity.ShortURLEntity, 0)
+		err := tx.Bucket(shortURLsBucket).ForEach(func(_ []byte, value []byte) error {
+			var shortURLEntity entity.ShortURLEntity
+			if err := json.Unmarshal(value, &shortURLEntity); err != nil {
+				return err
+			}
+
+			if shortURLEntity.Deleted && deletedAt(shortURLEntity).Before(deletedBefore) {
+				purgedShortURLEntities = append(purgedShortURLEntities, shortURLEntity)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// ключи удаляются после обхода бакета: изменение бакета во время ForEach не допускается
+		for _, shortURLEntity := range purgedShortURLEntities {
+			if err := deleteBoltShortURL(tx, &shortURLEntity); err != nil {
+				return err
+			}
+		}
+		purgedCount = len(purgedShortURLEntities)
+
+		return nil
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return 0, ErrUnexpected
+	}
+
+	return purgedCount, nil
+}
+
+// SaveDeleteJob сохраняет задачу на удаление коротких ссылок
+func (r *BoltShortURLRepository) SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error {
+	deleteJobJSON, err := json.Marshal(deleteJobEntity)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(deleteJobsBucket).Put([]byte(deleteJobEntity.ID), deleteJobJSON)
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// GetDeleteJobByID возвращает задачу на удаление коротких ссылок по ее идентификатору
+func (r *BoltShortURLRepository) GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error) {
+	var deleteJobEntity entity.DeleteJobEntity
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		deleteJobJSON := tx.Bucket(deleteJobsBucket).Get([]byte(id))
+		if deleteJobJSON == nil {
+			return ErrNotFound
+		}
+
+		return json.Unmarshal(deleteJobJSON, &deleteJobEntity)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return entity.DeleteJobEntity{}, ErrNotFound
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.DeleteJobEntity{}, ErrUnexpected
+	}
+
+	return deleteJobEntity, nil
+}
+
+// GetDeleteJobsByStatus возвращает список задач на удаление коротких ссылок в статусе status
+func (r *BoltShortURLRepository) GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error) {
+	result := make([]entity.DeleteJobEntity, 0)
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(deleteJobsBucket).ForEach(func(_ []byte, value []byte) error {
+			var deleteJobEntity entity.DeleteJobEntity
+			if err := json.Unmarshal(value, &deleteJobEntity); err != nil {
+				return err
+			}
+
+			if deleteJobEntity.Status == status {
+				result = append(result, deleteJobEntity)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// IncrementSplitClicks увеличивает на единицу количество переходов по короткой ссылке shortURI на вариант variant
+func (r *BoltShortURLRepository) IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error {
+	return r.incrementClicks(splitClicksBucket, shortURI, variant)
+}
+
+// IncrementCountryClicks увеличивает на единицу количество переходов по короткой ссылке shortURI из страны country
+func (r *BoltShortURLRepository) IncrementCountryClicks(ctx context.Context, shortURI string, country string) error {
+	return r.incrementClicks(countryClicksBucket, shortURI, country)
+}
+
+// incrementClicks увеличивает на единицу счетчик key короткой ссылки shortURI в бакете счетчиков bucket
+func (r *BoltShortURLRepository) incrementClicks(bucket []byte, shortURI string, key string) error {
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		clicksBucket, err := tx.Bucket(bucket).CreateBucketIfNotExists([]byte(shortURI))
+		if err != nil {
+			return err
+		}
+
+		clicks := make([]byte, 8)
+		if storedClicks := clicksBucket.Get([]byte(key)); storedClicks != nil {
+			copy(clicks, storedClicks)
+		}
+		binary.BigEndian.PutUint64(clicks, binary.BigEndian.Uint64(clicks)+1)
+
+		return clicksBucket.Put([]byte(key), clicks)
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// ConsumeShortURLClick учитывает переход по короткой ссылке shortURI с ограничением количества переходов,
+// для коротких ссылок без ограничения переход не учитывается
+//
+// Возвращает ErrClicksExhausted, если переходы по короткой ссылке исчерпаны, ErrNotFound - если короткая ссылка не найдена.
+func (r *BoltShortURLRepository) ConsumeShortURLClick(ctx context.Context, shortURI string) error {
+	// пишущие транзакции bbolt выполняются последовательно, поэтому проверка и учет перехода атомарны
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		shortURLEntity, err := getBoltShortURL(tx, shortURI)
+		if err != nil {
+			return err
+		}
+
+		if shortURLEntity.MaxClicks == 0 {
+			return nil
+		}
+
+		if shortURLEntity.ClickCount >= int64(shortURLEntity.MaxClicks) {
+			return ErrClicksExhausted
+		}
+		shortURLEntity.ClickCount++
+
+		return updateBoltShortURL(tx, &shortURLEntity)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrClicksExhausted) {
+			return err
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// GetSplitClicks возвращает количество переходов по короткой ссылке shortURI по вариантам перенаправления
+func (r *BoltShortURLRepository) GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	return r.getClicks(splitClicksBucket, shortURI)
+}
+
+// GetCountryClicks возвращает количество переходов по короткой ссылке shortURI по странам
+func (r *BoltShortURLRepository) GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	return r.getClicks(countryClicksBucket, shortURI)
+}
+
+// getClicks возвращает счетчики короткой ссылки shortURI из бакета счетчиков bucket
+func (r *BoltShortURLRepository) getClicks(bucket []byte, shortURI string) (map[string]int64, error) {
+	result := make(map[string]int64)
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		clicksBucket := tx.Bucket(bucket).Bucket([]byte(shortURI))
+		if clicksBucket == nil {
+			return nil
+		}
+
+		return clicksBucket.ForEach(func(key []byte, clicks []byte) error {
+			result[string(key)] = int64(binary.BigEndian.Uint64(clicks))
+			return nil
+		})
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return result, nil
+}
+
+// GetUserSettings возвращает настройки пользователя userID, для пользователя без сохраненных настроек
+// возвращаются настройки по умолчанию
+func (r *BoltShortURLRepository) GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error) {
+	userSettingsEntity := entity.UserSettingsEntity{UserID: userID}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		userSettingsJSON := tx.Bucket(userSettingsBucket).Get([]byte(userID))
+		if userSettingsJSON == nil {
+			return nil
+		}
+
+		return json.Unmarshal(userSettingsJSON, &userSettingsEntity)
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.UserSettingsEntity{}, ErrUnexpected
+	}
+
+	return userSettingsEntity, nil
+}
+
+// SaveUserSettings сохраняет настройки пользователя
+func (r *BoltShortURLRepository) SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error {
+	userSettingsJSON, err := json.Marshal(userSettingsEntity)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(userSettingsBucket).Put([]byte(userSettingsEntity.UserID), userSettingsJSON)
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// GetStats возвращает статистику по сервису
+// urlCount - количество коротких ссылок в сервисе
+// userCount - количество пользователей в сервисе
+func (r *BoltShortURLRepository) GetStats(ctx context.Context) (urlCount int, userCount int, err error) {
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		err := tx.Bucket(shortURLsBucket).ForEach(func(_ []byte, _ []byte) error {
+			urlCount++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// бакет пользователя удаляется вместе с последней его короткой ссылкой
+		return tx.Bucket(userShortURLsBucket).ForEach(func(_ []byte, _ []byte) error {
+			userCount++
+			return nil
+		})
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return 0, 0, ErrUnexpected
+	}
+
+	return urlCount, userCount, nil
+}
+
+// boltOriginalURLKey возвращает ключ бакета originalURLsBucket: исходный URL уникален в пределах домена короткой ссылки
+func boltOriginalURLKey(shortURLEntity *entity.ShortURLEntity) []byte {
+	return []byte(shortDomainOf(shortURLEntity.ShortURI) + "\n" + shortURLEntity.LongURL)
+}
+
+// getBoltShortURL возвращает короткую ссылку по ключу shortURI в транзакции tx
+func getBoltShortURL(tx *bbolt.Tx, shortURI string) (entity.ShortURLEntity, error) {
+	var shortURLEntity entity.ShortURLEntity
+
+	shortURLJSON := tx.Bucket(shortURLsBucket).Get([]byte(shortURI))
+	if shortURLJSON == nil {
+		return shortURLEntity, ErrNotFound
+	}
+
+	err := json.Unmarshal(shortURLJSON, &shortURLEntity)
+
+	return shortURLEntity, err
+}
+
+// putBoltShortURL сохраняет новую короткую ссылку и ее индексы по исходному URL и пользователю в транзакции tx
+func putBoltShortURL(tx *bbolt.Tx, shortURLEntity *entity.ShortURLEntity) error {
+	shortURI := []byte(shortURLEntity.ShortURI)
+	if tx.Bucket(shortURLsBucket).Get(shortURI) != nil {
+		return fmt.Errorf("%w: %q", errShortURIExists, shortURLEntity.ShortURI)
+	}
+
+	if err := updateBoltShortURL(tx, shortURLEntity); err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(originalURLsBucket).Put(boltOriginalURLKey(shortURLEntity), shortURI); err != nil {
+		return err
+	}
+
+	userBucket, err := tx.Bucket(userShortURLsBucket).CreateBucketIfNotExists([]byte(shortURLEntity.UserID))
+	if err != nil {
+		return err
+	}
+
+	return userBucket.Put(shortURI, []byte{})
+}
+
+// updateBoltShortURL сохраняет актуальное состояние короткой ссылки в транзакции tx
+func updateBoltShortURL(tx *bbolt.Tx, shortURLEntity *entity.ShortURLEntity) error {
+	shortURLJSON, err := json.Marshal(shortURLEntity)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(shortURLsBucket).Put([]byte(shortURLEntity.ShortURI), shortURLJSON)
+}
+
+// deleteBoltShortURL удаляет короткую ссылку, ее индексы и количество переходов по вариантам перенаправления в транзакции tx
+func deleteBoltShortURL(tx *bbolt.Tx, shortURLEntity *entity.ShortURLEntity) error {
+	shortURI := []byte(shortURLEntity.ShortURI)
+	if err := tx.Bucket(shortURLsBucket).Delete(shortURI); err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(originalURLsBucket).Delete(boltOriginalURLKey(shortURLEntity)); err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(splitClicksBucket).DeleteBucket(shortURI); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+		return err
+	}
+
+	if err := tx.Bucket(countryClicksBucket).DeleteBucket(shortURI); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+		return err
+	}
+
+	userBuckets := tx.Bucket(userShortURLsBucket)
+	userBucket := userBuckets.Bucket([]byte(shortURLEntity.UserID))
+	if userBucket == nil {
+		return nil
+	}
+
+	if err := userBucket.Delete(shortURI); err != nil {
+		return err
+	}
+
+	if firstShortURI, _ := userBucket.Cursor().First(); firstShortURI == nil {
+		return userBuckets.DeleteBucket([]byte(shortURLEntity.UserID))
+	}
+
+	return nil
+}
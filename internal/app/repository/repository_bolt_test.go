@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+	"github.com/vkhrushchev/urlshortener/internal/util"
+)
+
+// BoltShortURLRepositoryTestSuite выполняет тесты DBShortURLRepositoryTestSuite для BoltShortURLRepository
+type BoltShortURLRepositoryTestSuite struct {
+	DBShortURLRepositoryTestSuite
+	path           string
+	boltRepository *BoltShortURLRepository
+}
+
+func (s *BoltShortURLRepositoryTestSuite) SetupSuite() {
+	s.path = filepath.Join(s.T().TempDir(), "urlshortener.bolt")
+	boltRepository, err := NewBoltShortURLRepository(s.path)
+	if err != nil {
+		s.Fail("repository: failed to create BoltShortURLRepository: %v", err)
+	}
+
+	s.boltRepository = boltRepository
+	s.repository = boltRepository
+}
+
+func (s *BoltShortURLRepositoryTestSuite) TearDownSuite() {
+	if err := s.boltRepository.Close(); err != nil {
+		s.Fail("repository: failed to close kv storage: %v", err)
+	}
+}
+
+func (s *BoltShortURLRepositoryTestSuite) TestSaveShortURLs_conflict_rollback() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testLongURL := "https://mail.ru/" + util.RandStringRunes(10)
+	testShortURLs := []entity.ShortURLEntity{
+		{UUID: uuid.NewString(), ShortURI: util.RandStringRunes(10), LongURL: "https://vk.com/" + util.RandStringRunes(10), UserID: testUserID},
+		{UUID: uuid.NewString(), ShortURI: util.RandStringRunes(10), LongURL: testLongURL, UserID: testUserID},
+	}
+
+	_, err := s.repository.SaveShortURL(testCtx, &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: util.RandStringRunes(10),
+		LongURL:  testLongURL,
+		UserID:   testUserID,
+	})
+	s.NoError(err, "failed to save shortURL")
+
+	_, err = s.repository.SaveShortURLs(testCtx, testShortURLs)
+	s.ErrorIs(err, ErrConflict, "batch with already shortened original url must not be saved")
+
+	_, err = s.repository.GetShortURLByShortURI(testCtx, testShortURLs[0].ShortURI)
+	s.ErrorIs(err, ErrNotFound, "batch must be saved in one transaction")
+
+	testShortURLs[1].LongURL = testShortURLs[0].LongURL
+	_, err = s.repository.SaveShortURLs(testCtx, testShortURLs)
+	s.ErrorIs(err, ErrConflict, "batch with repeated original url must not be saved")
+}
+
+func TestBoltShortURLRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(BoltShortURLRepositoryTestSuite))
+}
+
+func TestBackupBoltFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "urlshortener.bolt")
+	backupPath := filepath.Join(t.TempDir(), "urlshortener.bolt.bak")
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURL := &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: util.RandStringRunes(10),
+		LongURL:  "https://mail.ru/" + util.RandStringRunes(10),
+		UserID:   testUserID,
+	}
+
+	repository, err := NewBoltShortURLRepository(path)
+	require.NoError(t, err)
+	_, err = repository.SaveShortURL(testCtx, testShortURL)
+	require.NoError(t, err)
+
+	assert.Error(t, BackupBoltFile(path, backupPath), "backup of kv storage opened by service must fail")
+
+	require.NoError(t, repository.Close())
+	require.NoError(t, BackupBoltFile(path, backupPath))
+
+	backupRepository, err := NewBoltShortURLRepository(backupPath)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, backupRepository.Close())
+	}()
+
+	shortURLEntity, err := backupRepository.GetShortURLByShortURI(testCtx, testShortURL.ShortURI)
+	require.NoError(t, err)
+	assert.Equal(t, testShortURL.LongURL, shortURLEntity.LongURL)
+
+	assert.Error(t, BackupBoltFile(filepath.Join(t.TempDir(), "not_existed.bolt"), backupPath), "backup of not existed kv storage must fail")
+}
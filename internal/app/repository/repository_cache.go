@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/lru"
+)
+
+// ShortURLRepository - репозиторий коротких ссылок, оборачиваемый CachedShortURLRepository
+type ShortURLRepository interface {
+	SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error)
+	SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error)
+
+	GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
+	GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
+	GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+	GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error)
+	GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+	ForEachShortURL(ctx context.Context, fn func(shortURLEntity entity.ShortURLEntity) error) error
+
+	UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error)
+	UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error
+	ConsumeShortURLClick(ctx context.Context, shortURI string) error
+
+	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error)
+	RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error)
+	PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error)
+
+	SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error
+	GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error)
+	GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error)
+
+	IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error
+	GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error)
+	IncrementCountryClicks(ctx context.Context, shortURI string, country string) error
+	GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error)
+
+	GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error)
+	SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error
+
+	GetStats(ctx context.Context) (urlCount int, userCount int, err error)
+}
+
+// cachedShortURL - результат поиска короткой ссылки в репозитории, found = false - короткая ссылка не найдена
+type cachedShortURL struct {
+	shortURLEntity entity.ShortURLEntity
+	found          bool
+	// redirectOnly - shortURLEntity содержит только поля, необходимые для перенаправления
+	redirectOnly bool
+}
+
+// shortURLCache - кэш результатов поиска коротких ссылок по shortURI
+type shortURLCache interface {
+	get(ctx context.Context, shortURI string) (cachedShortURL, bool)
+	add(ctx context.Context, shortURI string, cached cachedShortURL)
+	remove(ctx context.Context, shortURIs ...string)
+	purge(ctx context.Context)
+}
+
+// lruShortURLCache - кэш результатов поиска коротких ссылок в памяти процесса
+type lruShortURLCache struct {
+	cache *lru.Cache[cachedShortURL]
+}
+
+func (c lruShortURLCache) get(_ context.Context, shortURI string) (cachedShortURL, bool) {
+	return c.cache.Get(shortURI)
+}
+
+func (c lruShortURLCache) add(_ context.Context, shortURI string, cached cachedShortURL) {
+	c.cache.Add(shortURI, cached)
+}
+
+func (c lruShortURLCache) remove(_ context.Context, shortURIs ...string) {
+	for _, shortURI := range shortURIs {
+		c.cache.Remove(shortURI)
+	}
+}
+
+func (c lruShortURLCache) purge(_ context.Context) {
+	c.cache.Purge()
+}
+
+// generationStripes - количество счетчиков изменений коротких ссылок, короткие ссылки распределяются
+// по счетчикам по хэшу shortURI
+const generationStripes = 256
+
+// CachedShortURLRepository реализует интерфейс IShortURLRepository, кэшируя поиск коротких ссылок по shortURI
+// в репозитории ShortURLRepository
+//
+// Кэшируется и отсутствие короткой ссылки. Запись кэша удаляется после изменения короткой ссылки через
+// CachedShortURLRepository, изменения в обход него (например, другим экземпляром сервиса с кэшем
+// в памяти процесса) становятся видны по истечении времени жизни записи.
+type CachedShortURLRepository struct {
+	ShortURLRepository
+	cache  shortURLCache
+	hits   atomic.Int64
+	misses atomic.Int64
+	// generations - счетчики изменений коротких ссылок, по которым чтение, прочитавшее короткую ссылку
+	// до ее изменения, не оставляет устаревшую запись в кэше
+	generations [generationStripes]atomic.Uint64
+}
+
+// NewCachedShortURLRepository создает экземпляр структуры CachedShortURLRepository
+//
+//	repo - репозиторий, поиск коротких ссылок в котором кэшируется
+//	size - максимальное количество коротких ссылок в кэше
+//	ttl - время жизни записи кэша, 0 - записи не истекают
+func NewCachedShortURLRepository(repo ShortURLRepository, size int, ttl time.Duration) *CachedShortURLRepository {
+	return &CachedShortURLRepository{
+		ShortURLRepository: repo,
+		cache:              lruShortURLCache{cache: lru.NewCache[cachedShortURL](size, ttl)},
+	}
+}
+
+// CacheStats возвращает количество попаданий и промахов кэша
+func (r *CachedShortURLRepository) CacheStats() (hits int64, misses int64) {
+	return r.hits.Load(), r.misses.Load()
+}
+
+// GetShortURLByShortURI возвращает короткую ссылку по shortURI из кэша или из репозитория
+//
+// Запись кэша, содержащая только поля для перенаправления, не используется.
+func (r *CachedShortURLRepository) GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	return r.getShortURLByShortURI(ctx, shortURI, false)
+}
+
+// GetRedirectShortURLByShortURI возвращает короткую ссылку по shortURI для перенаправления из кэша или из репозитория
+//
+// Возвращенная из кэша короткая ссылка может содержать только поля, необходимые для перенаправления.
+func (r *CachedShortURLRepository) GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	return r.getShortURLByShortURI(ctx, shortURI, true)
+}
+
+func (r *CachedShortURLRepository) getShortURLByShortURI(ctx context.Context, shortURI string, redirect bool) (entity.ShortURLEntity, error) {
+	if cached, ok := r.cache.get(ctx, shortURI); ok && (redirect || !cached.redirectOnly) {
+		r.hits.Add(1)
+		if !cached.found {
+			return entity.ShortURLEntity{}, ErrNotFound
+		}
+
+		return cached.shortURLEntity, nil
+	}
+	r.misses.Add(1)
+
+	generation := r.generation(shortURI)
+	readGeneration := generation.Load()
+	shortURLEntity, err := r.ShortURLRepository.GetShortURLByShortURI(ctx, shortURI)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			r.addIfUnchanged(ctx, shortURI, cachedShortURL{}, generation, readGeneration)
+		}
+
+		return entity.ShortURLEntity{}, err
+	}
+
+	r.addIfUnchanged(ctx, shortURI, cachedShortURL{shortURLEntity: shortURLEntity, found: true}, generation, readGeneration)
+
+	return shortURLEntity, nil
+}
+
+// generation возвращает счетчик изменений короткой ссылки shortURI
+func (r *CachedShortURLRepository) generation(shortURI string) *atomic.Uint64 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(shortURI))
+
+	return &r.generations[hash.Sum32()%generationStripes]
+}
+
+// addIfUnchanged добавляет в кэш короткую ссылку shortURI, прочитанную при значении счетчика изменений readGeneration
+//
+// Если короткая ссылка изменилась во время чтения, добавленная запись удаляется: изменение могло удалить
+// запись кэша раньше, чем она была добавлена. Если изменение увеличило счетчик после проверки,
+// добавленную запись удалит само изменение.
+func (r *CachedShortURLRepository) addIfUnchanged(
+	ctx context.Context,
+	shortURI string,
+	cached cachedShortURL,
+	generation *atomic.Uint64,
+	readGeneration uint64,
+) {
+	if generation.Load() != readGeneration {
+		return
+	}
+
+	r.cache.add(ctx, shortURI, cached)
+	if generation.Load() != readGeneration {
+		r.cache.remove(ctx, shortURI)
+	}
+}
+
+// invalidate удаляет из кэша записи измененных коротких ссылок shortURIs
+func (r *CachedShortURLRepository) invalidate(ctx context.Context, shortURIs ...string) {
+	for _, shortURI := range shortURIs {
+		r.generation(shortURI).Add(1)
+	}
+	r.cache.remove(ctx, shortURIs...)
+}
+
+// invalidateAll очищает кэш
+func (r *CachedShortURLRepository) invalidateAll(ctx context.Context) {
+	for i := range r.generations {
+		r.generations[i].Add(1)
+	}
+	r.cache.purge(ctx)
+}
+
+// SaveShortURL сохраняет короткую ссылку
+func (r *CachedShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+	savedShortURLEntity, err := r.ShortURLRepository.SaveShortURL(ctx, shortURLEntity)
+	r.invalidate(ctx, shortURLEntity.ShortURI)
+
+	return savedShortURLEntity, err
+}
+
+// SaveShortURLs сохраняет короткие ссылки пачкой
+func (r *CachedShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+	savedShortURLEntities, err := r.ShortURLRepository.SaveShortURLs(ctx, shortURLEntities)
+	shortURIs := make([]string, 0, len(shortURLEntities))
+	for _, shortURLEntity := range shortURLEntities {
+		shortURIs = append(shortURIs, shortURLEntity.ShortURI)
+	}
+	r.invalidate(ctx, shortURIs...)
+
+	return savedShortURLEntities, err
+}
+
+// UpdateShortURL обновляет изменяемые поля короткой ссылки пользователя
+func (r *CachedShortURLRepository) UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error) {
+	updatedShortURLEntity, err := r.ShortURLRepository.UpdateShortURL(ctx, shortURLEntity)
+	r.invalidate(ctx, shortURLEntity.ShortURI)
+
+	return updatedShortURLEntity, err
+}
+
+// UpdateShortURLPreview сохраняет превью страницы исходного URL короткой ссылки
+func (r *CachedShortURLRepository) UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error {
+	err := r.ShortURLRepository.UpdateShortURLPreview(ctx, shortURI, preview)
+	r.invalidate(ctx, shortURI)
+
+	return err
+}
+
+// ConsumeShortURLClick учитывает переход по короткой ссылке shortURI с ограничением количества переходов
+func (r *CachedShortURLRepository) ConsumeShortURLClick(ctx context.Context, shortURI string) error {
+	err := r.ShortURLRepository.ConsumeShortURLClick(ctx, shortURI)
+	r.invalidate(ctx, shortURI)
+
+	return err
+}
+
+// DeleteShortURLsByShortURIs удаляет короткие ссылки пользователя по списку shortURI
+func (r *CachedShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error) {
+	result, err := r.ShortURLRepository.DeleteShortURLsByShortURIs(ctx, shortURIs)
+	r.invalidate(ctx, shortURIs...)
+
+	return result, err
+}
+
+// RestoreShortURLsByShortURIs восстанавливает удаленные короткие ссылки пользователя по списку shortURI
+func (r *CachedShortURLRepository) RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error) {
+	result, err := r.ShortURLRepository.RestoreShortURLsByShortURIs(ctx, shortURIs)
+	r.invalidate(ctx, shortURIs...)
+
+	return result, err
+}
+
+// PurgeDeletedShortURLs окончательно удаляет короткие ссылки, удаленные раньше deletedBefore
+//
+// Репозиторий не возвращает удаленные короткие ссылки, поэтому при их наличии очищается весь кэш.
+func (r *CachedShortURLRepository) PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	purgedCount, err := r.ShortURLRepository.PurgeDeletedShortURLs(ctx, deletedBefore)
+	if err != nil || purgedCount > 0 {
+		r.invalidateAll(ctx)
+	}
+
+	return purgedCount, err
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+)
+
+// pausingShortURLRepository останавливает поиск короткой ссылки после чтения, пока не закрыт канал proceed
+type pausingShortURLRepository struct {
+	*InMemoryShortURLRepository
+	read    chan struct{}
+	proceed chan struct{}
+}
+
+func (r *pausingShortURLRepository) GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	shortURLEntity, err := r.InMemoryShortURLRepository.GetShortURLByShortURI(ctx, shortURI)
+	close(r.read)
+	<-r.proceed
+
+	return shortURLEntity, err
+}
+
+type CachedRepositoryTestSuite struct {
+	suite.Suite
+	backend      *InMemoryShortURLRepository
+	repository   *CachedShortURLRepository
+	ctx          context.Context
+	testUserID   string
+	testShortURL entity.ShortURLEntity
+}
+
+func (suite *CachedRepositoryTestSuite) SetupTest() {
+	suite.testUserID = uuid.NewString()
+	suite.testShortURL = entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   suite.testUserID,
+	}
+
+	suite.ctx = context.WithValue(context.Background(), common.UserIDContextKey, suite.testUserID)
+
+	suite.backend = NewInMemoryShortURLRepository()
+	suite.repository = NewCachedShortURLRepository(suite.backend, 10, time.Minute)
+}
+
+func (suite *CachedRepositoryTestSuite) TestGetShortURLByShortURI_hit() {
+	_, err := suite.repository.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+
+	for i := 0; i < 3; i++ {
+		shortURLEntity, err := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+		suite.Require().NoError(err)
+		suite.Equal(suite.testShortURL.LongURL, shortURLEntity.LongURL)
+	}
+
+	hits, misses := suite.repository.CacheStats()
+	suite.Equal(int64(2), hits)
+	suite.Equal(int64(1), misses)
+}
+
+func (suite *CachedRepositoryTestSuite) TestGetShortURLByShortURI_not_found_cached() {
+	_, err := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.ErrorIs(err, ErrNotFound)
+
+	// запись в обход кэша не видна до истечения времени жизни отсутствия короткой ссылки в кэше
+	_, err = suite.backend.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+	_, err = suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.ErrorIs(err, ErrNotFound)
+
+	hits, misses := suite.repository.CacheStats()
+	suite.Equal(int64(1), hits)
+	suite.Equal(int64(1), misses)
+}
+
+func (suite *CachedRepositoryTestSuite) TestSaveShortURL_invalidates_not_found() {
+	_, err := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.ErrorIs(err, ErrNotFound)
+
+	_, err = suite.repository.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+
+	shortURLEntity, err := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal(suite.testShortURL.LongURL, shortURLEntity.LongURL)
+}
+
+func (suite *CachedRepositoryTestSuite) TestUpdateShortURL_invalidates() {
+	_, err := suite.repository.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+	_, err = suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+
+	updatedShortURL := suite.testShortURL
+	updatedShortURL.Title = "title"
+	_, err = suite.repository.UpdateShortURL(suite.ctx, updatedShortURL)
+	suite.Require().NoError(err)
+
+	shortURLEntity, err := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal("title", shortURLEntity.Title)
+}
+
+func (suite *CachedRepositoryTestSuite) TestGetShortURLByShortURI_stale_read_not_cached() {
+	_, err := suite.backend.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+
+	pausingBackend := &pausingShortURLRepository{
+		InMemoryShortURLRepository: suite.backend,
+		read:                       make(chan struct{}),
+		proceed:                    make(chan struct{}),
+	}
+	suite.repository = NewCachedShortURLRepository(pausingBackend, 10, time.Minute)
+
+	// чтение получает короткую ссылку до ее изменения, а добавляет в кэш после
+	staleShortURLEntityCh := make(chan entity.ShortURLEntity)
+	go func() {
+		shortURLEntity, _ := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+		staleShortURLEntityCh <- shortURLEntity
+	}()
+	<-pausingBackend.read
+
+	updatedShortURL := suite.testShortURL
+	updatedShortURL.Title = "title"
+	_, err = suite.repository.UpdateShortURL(suite.ctx, updatedShortURL)
+	suite.Require().NoError(err)
+
+	close(pausingBackend.proceed)
+	suite.Empty((<-staleShortURLEntityCh).Title)
+
+	pausingBackend.read = make(chan struct{})
+	shortURLEntity, err := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal("title", shortURLEntity.Title)
+}
+
+func (suite *CachedRepositoryTestSuite) TestDeleteShortURLsByShortURIs_invalidates() {
+	_, err := suite.repository.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+	_, err = suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+
+	_, err = suite.repository.DeleteShortURLsByShortURIs(suite.ctx, []string{"abc"})
+	suite.Require().NoError(err)
+
+	shortURLEntity, err := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.True(shortURLEntity.Deleted)
+}
+
+func (suite *CachedRepositoryTestSuite) TestGetShortURLByShortURI_expired() {
+	suite.repository = NewCachedShortURLRepository(suite.backend, 10, time.Nanosecond)
+
+	_, err := suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.ErrorIs(err, ErrNotFound)
+
+	time.Sleep(time.Millisecond)
+	_, err = suite.backend.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+
+	_, err = suite.repository.GetShortURLByShortURI(context.Background(), "abc")
+	suite.NoError(err)
+
+	hits, misses := suite.repository.CacheStats()
+	suite.Equal(int64(0), hits)
+	suite.Equal(int64(2), misses)
+}
+
+func TestCachedRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CachedRepositoryTestSuite))
+}
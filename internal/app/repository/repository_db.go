@@ -3,23 +3,83 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/vkhrushchev/urlshortener/internal/common"
-	"sync"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/vkhrushchev/urlshortener/internal/app/db"
 	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
 )
 
+// sqlSortColumns - соответствие поля сортировки коротких ссылок колонке таблицы short_url
+var sqlSortColumns = map[string]string{
+	entity.ShortURLSortByCreatedAt:   "created_at",
+	entity.ShortURLSortByOriginalURL: "original_url",
+	entity.ShortURLSortByShortURL:    "short_url",
+}
+
 const (
-	sqlInsertRow           = "INSERT INTO short_url(uuid, short_url, original_url, user_id, is_deleted) VALUES($1, $2, $3, $4, $5)"
-	sqlSelectByShortURL    = "SELECT su.uuid, su.short_url, su.original_url, su.user_id, su.is_deleted FROM short_url su WHERE su.short_url = $1"
-	sqlSelectByOriginalURL = "SELECT su.uuid, su.short_url, su.original_url, su.user_id, su.is_deleted FROM short_url su WHERE su.original_url = $1"
-	sqlSelectByUserID      = "SELECT su.uuid, su.short_url, su.original_url, su.user_id, su.is_deleted FROM short_url su WHERE su.user_id = $1"
-	sqlUpdateIsDeleted     = "UPDATE short_url SET is_deleted = true WHERE is_deleted = false AND short_url = $1 AND user_id = $2"
+	sqlShortURLColumns = "su.uuid, su.short_url, su.original_url, su.user_id, su.is_deleted, su.deleted_at, su.created_at, " +
+		"su.title, su.description, su.notes, su.tags, su.redirect_status, su.query_passthrough, su.path_passthrough, su.utm_params, " +
+		"su.targeting_rules, su.split_variants, su.password_hash, su.max_clicks, su.click_count, su.not_before, su.not_after, " +
+		"su.is_disabled, su.fallback_url, su.preview_title, su.preview_description, su.preview_image_url, su.preview_fetched_at"
+	sqlShortURLInsertColumns = "uuid, short_url, original_url, user_id, is_deleted, deleted_at, created_at, title, description, notes, tags, " +
+		"redirect_status, query_passthrough, path_passthrough, utm_params, targeting_rules, split_variants, password_hash, " +
+		"max_clicks, not_before, not_after, is_disabled, fallback_url, short_domain, " +
+		"click_count, preview_title, preview_description, preview_image_url, preview_fetched_at"
+
+	sqlInsertRow               = "INSERT INTO short_url(" + sqlShortURLInsertColumns + ") VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)"
+	sqlSelectByShortURL        = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE su.short_url = $1"
+	sqlSelectByOriginalURL     = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE su.original_url = $1 AND su.short_domain = $2"
+	sqlSelectByUserID          = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE su.user_id = $1"
+	sqlSelectDeletedByUserID   = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE su.user_id = $1 AND su.is_deleted = true ORDER BY su.deleted_at DESC"
+	sqlSelectOwnersByShortURLs = "SELECT su.short_url, su.user_id, su.is_deleted FROM short_url su WHERE su.short_url = ANY($1) FOR UPDATE"
+	sqlUpdateIsDeleted         = "UPDATE short_url SET is_deleted = true, deleted_at = now() WHERE is_deleted = false AND short_url = ANY($1) AND user_id = $2"
+	sqlUpdateIsNotDeleted      = "UPDATE short_url SET is_deleted = false, deleted_at = NULL WHERE is_deleted = true AND short_url = ANY($1) AND user_id = $2"
+	sqlUpdateMetadata          = "UPDATE short_url SET title = $1, description = $2, notes = $3, tags = $4, " +
+		"redirect_status = $5, query_passthrough = $6, path_passthrough = $7, utm_params = $8, targeting_rules = $9, split_variants = $10, " +
+		"password_hash = $11, max_clicks = $12, not_before = $13, not_after = $14, " +
+		"is_disabled = $15, fallback_url = $16 WHERE short_url = $17 AND user_id = $18"
+	sqlUpdatePreview = "UPDATE short_url SET preview_title = $1, preview_description = $2, preview_image_url = $3, preview_fetched_at = $4 WHERE short_url = $5"
+	// переход учитывается одним UPDATE: при одновременных переходах строка блокируется, и условие
+	// click_count < max_clicks перепроверяется для актуальной версии строки
+	sqlConsumeClick        = "UPDATE short_url SET click_count = click_count + 1 WHERE short_url = $1 AND click_count < max_clicks"
+	sqlSelectMaxClicks     = "SELECT su.max_clicks FROM short_url su WHERE su.short_url = $1"
+	sqlDeleteDeletedBefore = "DELETE FROM short_url WHERE is_deleted = true AND deleted_at < $1"
+	sqlDeleteOrphanClicks  = "DELETE FROM split_click sc WHERE NOT EXISTS (SELECT 1 FROM short_url su WHERE su.short_url = sc.short_url)"
+	sqlDeleteOrphanCountry = "DELETE FROM country_click cc WHERE NOT EXISTS (SELECT 1 FROM short_url su WHERE su.short_url = cc.short_url)"
+	sqlSelectPageByQuery   = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE %s ORDER BY su.%s %s, su.uuid %s LIMIT %s"
+	sqlCountByQuery        = "SELECT count(*) FROM short_url su WHERE %s"
+	sqlSelectFirstPage     = "SELECT " + sqlShortURLColumns + " FROM short_url su ORDER BY su.created_at, su.uuid LIMIT $1"
+	sqlSelectPageAfter     = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE (su.created_at, su.uuid) > ($1, $2) ORDER BY su.created_at, su.uuid LIMIT $3"
 	sqlStats               = "SELECT (SELECT count(*) FROM short_url) AS url_count, (SELECT count(*) FROM (SELECT DISTINCT user_id FROM short_url)) AS user_count"
+
+	sqlUpsertDeleteJob = "INSERT INTO delete_job(id, user_id, status, short_urls, results, created_at, updated_at) VALUES($1, $2, $3, $4, $5, $6, $7) " +
+		"ON CONFLICT (id) DO UPDATE SET status = excluded.status, results = excluded.results, updated_at = excluded.updated_at"
+	sqlSelectDeleteJobByID      = "SELECT dj.id, dj.user_id, dj.status, dj.short_urls, dj.results, dj.created_at, dj.updated_at FROM delete_job dj WHERE dj.id = $1"
+	sqlSelectDeleteJobsByStatus = "SELECT dj.id, dj.user_id, dj.status, dj.short_urls, dj.results, dj.created_at, dj.updated_at FROM delete_job dj WHERE dj.status = $1 ORDER BY dj.created_at"
+
+	sqlIncrementSplitClicks = "INSERT INTO split_click(short_url, variant, clicks) VALUES($1, $2, 1) " +
+		"ON CONFLICT (short_url, variant) DO UPDATE SET clicks = split_click.clicks + 1"
+	sqlSelectSplitClicks = "SELECT sc.variant, sc.clicks FROM split_click sc WHERE sc.short_url = $1"
+
+	sqlIncrementCountryClicks = "INSERT INTO country_click(short_url, country, clicks) VALUES($1, $2, 1) " +
+		"ON CONFLICT (short_url, country) DO UPDATE SET clicks = country_click.clicks + 1"
+	sqlSelectCountryClicks = "SELECT cc.country, cc.clicks FROM country_click cc WHERE cc.short_url = $1"
+
+	sqlUpsertUserSettings = "INSERT INTO user_settings(user_id, fallback_url) VALUES($1, $2) " +
+		"ON CONFLICT (user_id) DO UPDATE SET fallback_url = excluded.fallback_url"
+	sqlSelectUserSettings = "SELECT us.user_id, us.fallback_url FROM user_settings us WHERE us.user_id = $1"
 )
 
 // DBShortURLRepository структура для хранения ссылки на db.DBLookup.
@@ -34,24 +94,30 @@ func NewDBShortURLRepository(dbLookup *db.DBLookup) *DBShortURLRepository {
 	return &DBShortURLRepository{dbLookup: dbLookup}
 }
 
+// GetRedirectShortURLByShortURI возвращает короткую ссылку по shortURI для перенаправления,
+// хранилище возвращает короткую ссылку полностью
+func (r *DBShortURLRepository) GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	return r.GetShortURLByShortURI(ctx, shortURI)
+}
+
 // GetShortURLByShortURI возвращает короткую ссылку по shortURI
+//
+// Поиск выполняется на реплике, если пользователь из контекста недавно не изменял короткие ссылки в этом
+// экземпляре сервиса. Перенаправление выполняется без пользователя, поэтому в пределах отставания реплик
+// оно может использовать короткую ссылку до удаления, блокировки или исчерпания переходов.
 func (r *DBShortURLRepository) GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
-	dbLookup := r.dbLookup.GetDB()
-
-	sqlRow := dbLookup.QueryRowContext(
-		ctx,
-		sqlSelectByShortURL,
-		shortURI,
-	)
+	userID, _ := ctx.Value(common.UserIDContextKey).(string)
+	return r.getShortURLByShortURI(ctx, userID, shortURI)
+}
 
-	shortURLEntity := entity.ShortURLEntity{}
-	err := sqlRow.Scan(
-		&shortURLEntity.UUID,
-		&shortURLEntity.ShortURI,
-		&shortURLEntity.LongURL,
-		&shortURLEntity.UserID,
-		&shortURLEntity.Deleted,
-	)
+// getShortURLByShortURI возвращает короткую ссылку по shortURI, читая ее с учетом изменений пользователя userID
+func (r *DBShortURLRepository) getShortURLByShortURI(ctx context.Context, userID string, shortURI string) (entity.ShortURLEntity, error) {
+	var shortURLEntity entity.ShortURLEntity
+	err := r.dbLookup.ReadReplica(ctx, userID, func(dbLookup *sql.DB) error {
+		var err error
+		shortURLEntity, err = scanShortURL(dbLookup.QueryRowContext(ctx, sqlSelectByShortURL, shortURI))
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return entity.ShortURLEntity{}, ErrNotFound
@@ -65,10 +131,41 @@ func (r *DBShortURLRepository) GetShortURLByShortURI(ctx context.Context, shortU
 }
 
 // SaveShortURL сохраняет короткую ссылку
+//
+// Если исходный URL уже сокращен на домене короткой ссылки, возвращает существующую короткую ссылку и ErrConflict,
+// если занят ключ короткой ссылки - nil и ErrConflict.
 func (r *DBShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
 	dbLookup := r.dbLookup.GetDB()
 
-	_, err := dbLookup.ExecContext(
+	if shortURLEntity.CreatedAt.IsZero() {
+		shortURLEntity.CreatedAt = time.Now()
+	}
+
+	tagsJSON, err := marshalTags(shortURLEntity.Tags)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	utmParamsJSON, err := marshalUTMParams(shortURLEntity.UTMParams)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	targetingRulesJSON, err := marshalTargetingRules(shortURLEntity.TargetingRules)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	splitVariantsJSON, err := marshalSplitVariants(shortURLEntity.SplitVariants)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	_, err = dbLookup.ExecContext(
 		ctx,
 		sqlInsertRow,
 		shortURLEntity.UUID,
@@ -76,31 +173,53 @@ func (r *DBShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity
 		shortURLEntity.LongURL,
 		shortURLEntity.UserID,
 		shortURLEntity.Deleted,
+		shortURLEntity.DeletedAt,
+		shortURLEntity.CreatedAt,
+		shortURLEntity.Title,
+		shortURLEntity.Description,
+		shortURLEntity.Notes,
+		tagsJSON,
+		shortURLEntity.RedirectStatus,
+		shortURLEntity.QueryPassthrough,
+		shortURLEntity.PathPassthrough,
+		utmParamsJSON,
+		targetingRulesJSON,
+		splitVariantsJSON,
+		shortURLEntity.PasswordHash,
+		shortURLEntity.MaxClicks,
+		shortURLEntity.NotBefore,
+		shortURLEntity.NotAfter,
+		shortURLEntity.Disabled,
+		shortURLEntity.FallbackURL,
+		shortDomainOf(shortURLEntity.ShortURI),
+		shortURLEntity.ClickCount,
+		shortURLEntity.PreviewTitle,
+		shortURLEntity.PreviewDescription,
+		shortURLEntity.PreviewImageURL,
+		shortURLEntity.PreviewFetchedAt,
 	)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == pgerrcode.UniqueViolation {
-				sqlRow := dbLookup.QueryRowContext(ctx, sqlSelectByOriginalURL, shortURLEntity.LongURL)
+				sqlRow := dbLookup.QueryRowContext(ctx, sqlSelectByOriginalURL, shortURLEntity.LongURL, shortDomainOf(shortURLEntity.ShortURI))
 				if sqlRow.Err() != nil {
 					log.Errorw("repository: unexpected error", "err", err)
 					return nil, ErrUnexpected
 				}
 
-				err = sqlRow.Scan(
-					&shortURLEntity.UUID,
-					&shortURLEntity.ShortURI,
-					&shortURLEntity.LongURL,
-					&shortURLEntity.UserID,
-					&shortURLEntity.Deleted,
-				)
+				existedShortURLEntity, err := scanShortURL(sqlRow)
+				if errors.Is(err, sql.ErrNoRows) {
+					// исходный URL не сокращен, значит занят ключ короткой ссылки
+					return nil, ErrConflict
+				}
 				if err != nil {
 					log.Errorw("repository: unexpected error", "err", err)
 					return nil, ErrUnexpected
 				}
 
-				return shortURLEntity, ErrConflict
+				return &existedShortURLEntity, ErrConflict
 			}
 		}
 
@@ -108,10 +227,15 @@ func (r *DBShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity
 		return nil, ErrUnexpected
 	}
 
+	r.dbLookup.MarkWrite(shortURLEntity.UserID)
+
 	return shortURLEntity, nil
 }
 
-// SaveShortURLs сохраняет короткие ссылки пачкой
+// SaveShortURLs сохраняет короткие ссылки пачкой в одной транзакции
+//
+// Если ключ или исходный URL одной из коротких ссылок уже заняты или повторяются в пачке, не сохраняется ни одна
+// короткая ссылка пачки и возвращается ErrConflict.
 func (r *DBShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
 	dbLookup := r.dbLookup.GetDB()
 
@@ -132,7 +256,37 @@ func (r *DBShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntiti
 		return nil, ErrUnexpected
 	}
 
-	for _, shortURLEntity := range shortURLEntities {
+	now := time.Now()
+	for i := range shortURLEntities {
+		shortURLEntity := &shortURLEntities[i]
+		if shortURLEntity.CreatedAt.IsZero() {
+			shortURLEntity.CreatedAt = now
+		}
+
+		tagsJSON, err := marshalTags(shortURLEntity.Tags)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
+		utmParamsJSON, err := marshalUTMParams(shortURLEntity.UTMParams)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
+		targetingRulesJSON, err := marshalTargetingRules(shortURLEntity.TargetingRules)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
+		splitVariantsJSON, err := marshalSplitVariants(shortURLEntity.SplitVariants)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
 		_, err = stmt.ExecContext(
 			ctx,
 			shortURLEntity.UUID,
@@ -140,8 +294,37 @@ func (r *DBShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntiti
 			shortURLEntity.LongURL,
 			shortURLEntity.UserID,
 			shortURLEntity.Deleted,
+			shortURLEntity.DeletedAt,
+			shortURLEntity.CreatedAt,
+			shortURLEntity.Title,
+			shortURLEntity.Description,
+			shortURLEntity.Notes,
+			tagsJSON,
+			shortURLEntity.RedirectStatus,
+			shortURLEntity.QueryPassthrough,
+			shortURLEntity.PathPassthrough,
+			utmParamsJSON,
+			targetingRulesJSON,
+			splitVariantsJSON,
+			shortURLEntity.PasswordHash,
+			shortURLEntity.MaxClicks,
+			shortURLEntity.NotBefore,
+			shortURLEntity.NotAfter,
+			shortURLEntity.Disabled,
+			shortURLEntity.FallbackURL,
+			shortDomainOf(shortURLEntity.ShortURI),
+			shortURLEntity.ClickCount,
+			shortURLEntity.PreviewTitle,
+			shortURLEntity.PreviewDescription,
+			shortURLEntity.PreviewImageURL,
+			shortURLEntity.PreviewFetchedAt,
 		)
 		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+				return nil, ErrConflict
+			}
+
 			log.Errorw("repository: unexpected error", "err", err)
 			return nil, ErrUnexpected
 		}
@@ -152,18 +335,160 @@ func (r *DBShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntiti
 		return nil, ErrUnexpected
 	}
 
+	for _, shortURLEntity := range shortURLEntities {
+		r.dbLookup.MarkWrite(shortURLEntity.UserID)
+	}
+
 	return shortURLEntities, nil
 }
 
 // GetShortURLsByUserID возвращает список коротких ссылок по userID
+//
+// Поиск выполняется на реплике, если пользователь userID недавно не изменял короткие ссылки.
 func (r *DBShortURLRepository) GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
-	dbLookup := r.dbLookup.GetDB()
+	var result []entity.ShortURLEntity
+	err := r.dbLookup.ReadReplica(ctx, userID, func(dbLookup *sql.DB) error {
+		var err error
+		result, err = r.readShortURLs(ctx, dbLookup, sqlSelectByUserID, userID)
+		return err
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return result, nil
+}
+
+// GetDeletedShortURLsByUserID возвращает список удаленных коротких ссылок пользователя userID
+func (r *DBShortURLRepository) GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
+	return r.queryShortURLs(ctx, sqlSelectDeletedByUserID, userID)
+}
+
+// GetShortURLsByQuery возвращает страницу коротких ссылок пользователя, удовлетворяющих запросу query,
+// и общее количество удовлетворяющих фильтрам запроса коротких ссылок
+//
+// Поиск выполняется на реплике, если пользователь query.UserID недавно не изменял короткие ссылки.
+// Количество и страница читаются из одной базы данных.
+func (r *DBShortURLRepository) GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error) {
+	conditions := []string{"su.user_id = $1"}
+	args := []any{query.UserID}
+	addArg := func(arg any) string {
+		args = append(args, arg)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	switch query.Status {
+	case entity.ShortURLStatusActive:
+		conditions = append(conditions, "su.is_deleted = false")
+	case entity.ShortURLStatusDeleted:
+		conditions = append(conditions, "su.is_deleted = true")
+	}
+	if query.CreatedFrom != nil {
+		conditions = append(conditions, "su.created_at >= "+addArg(*query.CreatedFrom))
+	}
+	if query.CreatedTo != nil {
+		conditions = append(conditions, "su.created_at <= "+addArg(*query.CreatedTo))
+	}
+	if query.Domain != "" {
+		conditions = append(conditions, "su.original_url ~* "+addArg(domainRegexp(query.Domain)))
+	}
+	if query.Tag != "" {
+		conditions = append(conditions, "su.tags @> jsonb_build_array("+addArg(query.Tag)+"::text)")
+	}
+	if query.Search != "" {
+		searchArg := addArg("%" + escapeLike(query.Search) + "%")
+		conditions = append(conditions, "(su.original_url ILIKE "+searchArg+" OR su.short_url ILIKE "+searchArg+")")
+	}
+
+	countQuery := fmt.Sprintf(sqlCountByQuery, strings.Join(conditions, " AND "))
+	countArgs := slices.Clone(args)
+
+	sortColumn, ok := sqlSortColumns[query.SortBy]
+	if !ok {
+		log.Errorw("repository: unexpected sort column", "sortBy", query.SortBy)
+		return nil, 0, ErrUnexpected
+	}
+
+	sortDirection, cursorOperator := "ASC", ">"
+	if query.SortDesc {
+		sortDirection, cursorOperator = "DESC", "<"
+	}
+
+	if query.After != nil {
+		var sortValue any = query.After.SortValue
+		if query.SortBy == entity.ShortURLSortByCreatedAt {
+			var err error
+			sortValue, err = time.Parse(time.RFC3339Nano, query.After.SortValue)
+			if err != nil {
+				log.Errorw("repository: unexpected error", "err", err)
+				return nil, 0, ErrUnexpected
+			}
+		}
+
+		conditions = append(conditions, fmt.Sprintf(
+			"(su.%s, su.uuid) %s (%s, %s)",
+			sortColumn, cursorOperator, addArg(sortValue), addArg(query.After.UUID),
+		))
+	}
+
+	pageQuery := fmt.Sprintf(
+		sqlSelectPageByQuery,
+		strings.Join(conditions, " AND "), sortColumn, sortDirection, sortDirection, addArg(query.Limit),
+	)
+
+	var shortURLEntities []entity.ShortURLEntity
+	var totalCount int
+	err := r.dbLookup.ReadReplica(ctx, query.UserID, func(dbLookup *sql.DB) error {
+		if err := dbLookup.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount); err != nil {
+			return err
+		}
+
+		var err error
+		shortURLEntities, err = r.readShortURLs(ctx, dbLookup, pageQuery, args...)
+		return err
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, 0, ErrUnexpected
+	}
 
-	rows, err := dbLookup.QueryContext(ctx, sqlSelectByUserID, userID)
+	return shortURLEntities, totalCount, nil
+}
+
+// ForEachShortURL вызывает fn для каждой короткой ссылки, включая удаленные, в порядке создания
+//
+// Обход прекращается при первой ошибке fn, которая возвращается без изменений.
+func (r *DBShortURLRepository) ForEachShortURL(ctx context.Context, fn func(shortURLEntity entity.ShortURLEntity) error) error {
+	return forEachShortURLPage(func(after *entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+		if after == nil {
+			return r.queryShortURLs(ctx, sqlSelectFirstPage, shortURLPageSize)
+		}
+
+		return r.queryShortURLs(ctx, sqlSelectPageAfter, after.CreatedAt, after.UUID, shortURLPageSize)
+	}, fn)
+}
+
+func (r *DBShortURLRepository) queryShortURLs(ctx context.Context, query string, args ...any) ([]entity.ShortURLEntity, error) {
+	var result []entity.ShortURLEntity
+	err := r.dbLookup.RetryRead(ctx, func() error {
+		var err error
+		result, err = r.readShortURLs(ctx, r.dbLookup.GetDB(), query, args...)
+		return err
+	})
 	if err != nil {
 		log.Errorw("repository: unexpected error", "err", err)
 		return nil, ErrUnexpected
 	}
+
+	return result, nil
+}
+
+func (r *DBShortURLRepository) readShortURLs(ctx context.Context, dbLookup *sql.DB, query string, args ...any) ([]entity.ShortURLEntity, error) {
+	rows, err := dbLookup.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer func() {
 		if err := rows.Close(); err != nil {
 			log.Errorw("repository: unexpected error", "err", err)
@@ -172,19 +497,251 @@ func (r *DBShortURLRepository) GetShortURLsByUserID(ctx context.Context, userID
 
 	result := make([]entity.ShortURLEntity, 0)
 	for rows.Next() {
-		resultEntry := entity.ShortURLEntity{}
-		if err := rows.Scan(
-			&resultEntry.UUID,
-			&resultEntry.ShortURI,
-			&resultEntry.LongURL,
-			&resultEntry.UserID,
-			&resultEntry.Deleted,
-		); err != nil {
+		resultEntry, err := scanShortURL(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, resultEntry)
+	}
+
+	return result, rows.Err()
+}
+
+// UpdateShortURL обновляет изменяемые поля короткой ссылки пользователя: название, описание, заметки, теги
+// и параметры перенаправления
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена или принадлежит другому пользователю.
+func (r *DBShortURLRepository) UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	tagsJSON, err := marshalTags(shortURLEntity.Tags)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	utmParamsJSON, err := marshalUTMParams(shortURLEntity.UTMParams)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	targetingRulesJSON, err := marshalTargetingRules(shortURLEntity.TargetingRules)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	splitVariantsJSON, err := marshalSplitVariants(shortURLEntity.SplitVariants)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	res, err := dbLookup.ExecContext(
+		ctx,
+		sqlUpdateMetadata,
+		shortURLEntity.Title,
+		shortURLEntity.Description,
+		shortURLEntity.Notes,
+		tagsJSON,
+		shortURLEntity.RedirectStatus,
+		shortURLEntity.QueryPassthrough,
+		shortURLEntity.PathPassthrough,
+		utmParamsJSON,
+		targetingRulesJSON,
+		splitVariantsJSON,
+		shortURLEntity.PasswordHash,
+		shortURLEntity.MaxClicks,
+		shortURLEntity.NotBefore,
+		shortURLEntity.NotAfter,
+		shortURLEntity.Disabled,
+		shortURLEntity.FallbackURL,
+		shortURLEntity.ShortURI,
+		shortURLEntity.UserID,
+	)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	updatedCount, err := res.RowsAffected()
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+	if updatedCount == 0 {
+		return entity.ShortURLEntity{}, ErrNotFound
+	}
+
+	r.dbLookup.MarkWrite(shortURLEntity.UserID)
+
+	return r.getShortURLByShortURI(ctx, shortURLEntity.UserID, shortURLEntity.ShortURI)
+}
+
+// UpdateShortURLPreview сохраняет превью страницы исходного URL короткой ссылки
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена.
+func (r *DBShortURLRepository) UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	res, err := dbLookup.ExecContext(
+		ctx,
+		sqlUpdatePreview,
+		preview.Title,
+		preview.Description,
+		preview.ImageURL,
+		preview.FetchedAt,
+		shortURI,
+	)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	updatedCount, err := res.RowsAffected()
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+	if updatedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteShortURLsByShortURIs удаляет короткие ссылки пользователя по списку shortURI
+// и возвращает результат удаления по каждой из них
+func (r *DBShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+
+	shortURLStates, err := r.updateShortURLsByShortURIs(ctx, sqlUpdateIsDeleted, shortURIs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerByShortURI := make(map[string]string, len(shortURLStates))
+	for shortURI, shortURLState := range shortURLStates {
+		ownerByShortURI[shortURI] = shortURLState.userID
+	}
+
+	result := make([]entity.DeleteShortURLResultEntity, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		result = append(result, entity.DeleteShortURLResultEntity{
+			ShortURI: shortURI,
+			Status:   deleteStatus(ownerByShortURI, shortURI, userID),
+		})
+	}
+
+	log.Infow("repository: rows marked as deleted", "shortURIs", shortURIs, "userID", userID)
+
+	return result, nil
+}
+
+// RestoreShortURLsByShortURIs восстанавливает удаленные короткие ссылки пользователя по списку shortURI
+// и возвращает результат восстановления по каждой из них
+func (r *DBShortURLRepository) RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+
+	shortURLStates, err := r.updateShortURLsByShortURIs(ctx, sqlUpdateIsNotDeleted, shortURIs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.RestoreShortURLResultEntity, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		shortURLState, ok := shortURLStates[shortURI]
+		result = append(result, entity.RestoreShortURLResultEntity{
+			ShortURI: shortURI,
+			Status:   restoreStatus(ok, shortURLState.userID, shortURLState.deleted, userID),
+		})
+	}
+
+	log.Infow("repository: rows marked as not deleted", "shortURIs", shortURIs, "userID", userID)
+
+	return result, nil
+}
+
+// PurgeDeletedShortURLs окончательно удаляет короткие ссылки, удаленные раньше deletedBefore,
+// и возвращает количество удаленных ссылок
+func (r *DBShortURLRepository) PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	res, err := dbLookup.ExecContext(ctx, sqlDeleteDeletedBefore, deletedBefore)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return 0, ErrUnexpected
+	}
+
+	purgedCount, err := res.RowsAffected()
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return 0, ErrUnexpected
+	}
+
+	if purgedCount > 0 {
+		if _, err := dbLookup.ExecContext(ctx, sqlDeleteOrphanClicks); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return 0, ErrUnexpected
+		}
+		if _, err := dbLookup.ExecContext(ctx, sqlDeleteOrphanCountry); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return 0, ErrUnexpected
+		}
+	}
+
+	return int(purgedCount), nil
+}
+
+type shortURLState struct {
+	userID  string
+	deleted bool
+}
+
+// updateShortURLsByShortURIs блокирует короткие ссылки по списку shortURIs, выполняет для них запрос updateQuery
+// с параметрами shortURIs и userID и возвращает состояние коротких ссылок до изменения
+func (r *DBShortURLRepository) updateShortURLsByShortURIs(
+	ctx context.Context,
+	updateQuery string,
+	shortURIs []string,
+	userID string,
+) (map[string]shortURLState, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	tx, err := dbLookup.BeginTx(ctx, nil)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+			log.Errorw("repository: error when rollback transaction", "rollbackErr", rollbackErr)
+		}
+	}()
+
+	rows, err := tx.QueryContext(ctx, sqlSelectOwnersByShortURLs, shortURIs)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+		}
+	}()
+
+	shortURLStates := make(map[string]shortURLState, len(shortURIs))
+	for rows.Next() {
+		var shortURI string
+		var state shortURLState
+		if err := rows.Scan(&shortURI, &state.userID, &state.deleted); err != nil {
 			log.Errorw("repository: unexpected error", "err", err)
 			return nil, ErrUnexpected
 		}
 
-		result = append(result, resultEntry)
+		shortURLStates[shortURI] = state
 	}
 
 	if err := rows.Err(); err != nil {
@@ -192,121 +749,440 @@ func (r *DBShortURLRepository) GetShortURLsByUserID(ctx context.Context, userID
 		return nil, ErrUnexpected
 	}
 
-	return result, nil
+	if _, err := tx.ExecContext(ctx, updateQuery, shortURIs, userID); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	r.dbLookup.MarkWrite(userID)
+
+	return shortURLStates, nil
 }
 
-// DeleteShortURLsByShortURIs удаляет короткие ссылки по списку shortURI
-func (r *DBShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) error {
+// SaveDeleteJob сохраняет задачу на удаление коротких ссылок
+func (r *DBShortURLRepository) SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error {
 	dbLookup := r.dbLookup.GetDB()
 
-	tx, err := dbLookup.Begin()
+	shortURIsJSON, err := json.Marshal(deleteJobEntity.ShortURIs)
 	if err != nil {
 		log.Errorw("repository: unexpected error", "err", err)
 		return ErrUnexpected
 	}
 
-	shortURIsCh := deleteByShortURIsGenerator(shortURIs)
+	resultsJSON, err := json.Marshal(deleteJobEntity.Results)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
 
-	stmt, err := tx.PrepareContext(ctx, sqlUpdateIsDeleted)
+	_, err = dbLookup.ExecContext(
+		ctx,
+		sqlUpsertDeleteJob,
+		deleteJobEntity.ID,
+		deleteJobEntity.UserID,
+		deleteJobEntity.Status,
+		string(shortURIsJSON),
+		string(resultsJSON),
+		deleteJobEntity.CreatedAt,
+		deleteJobEntity.UpdatedAt,
+	)
 	if err != nil {
 		log.Errorw("repository: unexpected error", "err", err)
 		return ErrUnexpected
 	}
-	userID := ctx.Value(common.UserIDContextKey).(string)
-	deleteByShortURIsTaskResultChannels := deleteByShortURIsTaskFanOut(ctx, stmt, &userID, shortURIsCh)
-	deleteByShortURIsTaskFanIn(tx, deleteByShortURIsTaskResultChannels)
 
 	return nil
 }
 
-func deleteByShortURIsGenerator(shortURIs []string) chan string {
-	inputCh := make(chan string)
+// GetDeleteJobByID возвращает задачу на удаление коротких ссылок по ее идентификатору
+func (r *DBShortURLRepository) GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	var deleteJobEntity entity.DeleteJobEntity
+	err := r.dbLookup.RetryRead(ctx, func() error {
+		var err error
+		deleteJobEntity, err = scanDeleteJob(dbLookup.QueryRowContext(ctx, sqlSelectDeleteJobByID, id))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.DeleteJobEntity{}, ErrNotFound
+		}
 
-	go func() {
-		defer close(inputCh)
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.DeleteJobEntity{}, ErrUnexpected
+	}
 
-		for _, data := range shortURIs {
-			inputCh <- data
+	return deleteJobEntity, nil
+}
+
+// GetDeleteJobsByStatus возвращает список задач на удаление коротких ссылок в статусе status
+func (r *DBShortURLRepository) GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error) {
+	var result []entity.DeleteJobEntity
+	err := r.dbLookup.RetryRead(ctx, func() error {
+		var err error
+		result, err = r.readDeleteJobsByStatus(ctx, status)
+		return err
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return result, nil
+}
+
+func (r *DBShortURLRepository) readDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	rows, err := dbLookup.QueryContext(ctx, sqlSelectDeleteJobsByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
 		}
 	}()
 
-	return inputCh
+	result := make([]entity.DeleteJobEntity, 0)
+	for rows.Next() {
+		deleteJobEntity, err := scanDeleteJob(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, deleteJobEntity)
+	}
+
+	return result, rows.Err()
 }
 
-func deleteByShortURIsTask(ctx context.Context, stmt *sql.Stmt, userID *string, shortURIsCh chan string) chan int {
-	deleteByShortURIsTaskResultCh := make(chan int)
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
-	go func() {
-		defer close(deleteByShortURIsTaskResultCh)
+func scanShortURL(row rowScanner) (entity.ShortURLEntity, error) {
+	var shortURLEntity entity.ShortURLEntity
+	var tagsJSON, utmParamsJSON, targetingRulesJSON, splitVariantsJSON string
 
-		for shortURI := range shortURIsCh {
-			res, err := stmt.ExecContext(ctx, shortURI, userID)
-			if err != nil {
-				log.Errorw("repository: unexpected error", "err", err)
-				continue
-			}
+	err := row.Scan(
+		&shortURLEntity.UUID,
+		&shortURLEntity.ShortURI,
+		&shortURLEntity.LongURL,
+		&shortURLEntity.UserID,
+		&shortURLEntity.Deleted,
+		&shortURLEntity.DeletedAt,
+		&shortURLEntity.CreatedAt,
+		&shortURLEntity.Title,
+		&shortURLEntity.Description,
+		&shortURLEntity.Notes,
+		&tagsJSON,
+		&shortURLEntity.RedirectStatus,
+		&shortURLEntity.QueryPassthrough,
+		&shortURLEntity.PathPassthrough,
+		&utmParamsJSON,
+		&targetingRulesJSON,
+		&splitVariantsJSON,
+		&shortURLEntity.PasswordHash,
+		&shortURLEntity.MaxClicks,
+		&shortURLEntity.ClickCount,
+		&shortURLEntity.NotBefore,
+		&shortURLEntity.NotAfter,
+		&shortURLEntity.Disabled,
+		&shortURLEntity.FallbackURL,
+		&shortURLEntity.PreviewTitle,
+		&shortURLEntity.PreviewDescription,
+		&shortURLEntity.PreviewImageURL,
+		&shortURLEntity.PreviewFetchedAt,
+	)
+	if err != nil {
+		return shortURLEntity, err
+	}
 
-			deletedCount, err := res.RowsAffected()
-			if err != nil {
-				log.Errorw("repository: unexpected error", "err", err)
-			}
+	err = json.Unmarshal([]byte(tagsJSON), &shortURLEntity.Tags)
+	if err != nil {
+		return shortURLEntity, err
+	}
+	if len(shortURLEntity.Tags) == 0 {
+		shortURLEntity.Tags = nil
+	}
 
-			log.Infow("repository: row marked as deleted", "shortURI", shortURI, "userID", userID)
+	err = json.Unmarshal([]byte(utmParamsJSON), &shortURLEntity.UTMParams)
+	if err != nil {
+		return shortURLEntity, err
+	}
+	if len(shortURLEntity.UTMParams) == 0 {
+		shortURLEntity.UTMParams = nil
+	}
 
-			deleteByShortURIsTaskResultCh <- int(deletedCount)
-		}
-	}()
+	err = json.Unmarshal([]byte(targetingRulesJSON), &shortURLEntity.TargetingRules)
+	if err != nil {
+		return shortURLEntity, err
+	}
+	if len(shortURLEntity.TargetingRules) == 0 {
+		shortURLEntity.TargetingRules = nil
+	}
+
+	err = json.Unmarshal([]byte(splitVariantsJSON), &shortURLEntity.SplitVariants)
+	if len(shortURLEntity.SplitVariants) == 0 {
+		shortURLEntity.SplitVariants = nil
+	}
+
+	return shortURLEntity, err
+}
+
+// marshalTags представляет теги короткой ссылки в виде json-массива для хранения в колонке tags
+func marshalTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	tagsJSON, err := json.Marshal(tags)
 
-	return deleteByShortURIsTaskResultCh
+	return string(tagsJSON), err
 }
 
-func deleteByShortURIsTaskFanOut(ctx context.Context, stmt *sql.Stmt, userID *string, shortURIsCh chan string) []chan int {
-	workerCount := 10
-	deleteByShortURIsTaskResultChannels := make([]chan int, workerCount)
-	for i := 0; i < workerCount; i++ {
-		deleteByShortURIsTaskResultCh := deleteByShortURIsTask(ctx, stmt, userID, shortURIsCh)
-		deleteByShortURIsTaskResultChannels[i] = deleteByShortURIsTaskResultCh
+// marshalUTMParams представляет UTM-параметры короткой ссылки в виде json-объекта для хранения в колонке utm_params
+func marshalUTMParams(utmParams map[string]string) (string, error) {
+	if utmParams == nil {
+		utmParams = map[string]string{}
 	}
 
-	return deleteByShortURIsTaskResultChannels
+	utmParamsJSON, err := json.Marshal(utmParams)
+
+	return string(utmParamsJSON), err
 }
 
-func deleteByShortURIsTaskFanIn(tx *sql.Tx, deleteByShortURIsTaskResultChannels []chan int) {
-	go func() {
-		var wg sync.WaitGroup
-		for _, deleteByShortURIsTaskResultCh := range deleteByShortURIsTaskResultChannels {
-			deleteByShortURIsTaskResultChClosure := deleteByShortURIsTaskResultCh
-			wg.Add(1)
+// marshalTargetingRules представляет правила таргетинга короткой ссылки в виде json-массива для хранения в колонке targeting_rules
+func marshalTargetingRules(targetingRules []targeting.Rule) (string, error) {
+	if targetingRules == nil {
+		targetingRules = []targeting.Rule{}
+	}
 
-			go func() {
-				defer wg.Done()
+	targetingRulesJSON, err := json.Marshal(targetingRules)
 
-				for range deleteByShortURIsTaskResultChClosure {
-					// просто считываем данные из канала
-				}
-			}()
+	return string(targetingRulesJSON), err
+}
+
+// marshalSplitVariants представляет варианты перенаправления короткой ссылки в виде json-массива для хранения в колонке split_variants
+func marshalSplitVariants(splitVariants []split.Variant) (string, error) {
+	if splitVariants == nil {
+		splitVariants = []split.Variant{}
+	}
+
+	splitVariantsJSON, err := json.Marshal(splitVariants)
+
+	return string(splitVariantsJSON), err
+}
+
+// shortDomainOf возвращает домен короткой ссылки по ее ключу shortURI, "" - домен по умолчанию
+func shortDomainOf(shortURI string) string {
+	shortDomain, _ := shortdomain.Split(shortURI)
+	return shortDomain
+}
+
+func scanDeleteJob(row rowScanner) (entity.DeleteJobEntity, error) {
+	var deleteJobEntity entity.DeleteJobEntity
+	var shortURIsJSON, resultsJSON string
+
+	err := row.Scan(
+		&deleteJobEntity.ID,
+		&deleteJobEntity.UserID,
+		&deleteJobEntity.Status,
+		&shortURIsJSON,
+		&resultsJSON,
+		&deleteJobEntity.CreatedAt,
+		&deleteJobEntity.UpdatedAt,
+	)
+	if err != nil {
+		return entity.DeleteJobEntity{}, err
+	}
+
+	if err := json.Unmarshal([]byte(shortURIsJSON), &deleteJobEntity.ShortURIs); err != nil {
+		return entity.DeleteJobEntity{}, err
+	}
+
+	if err := json.Unmarshal([]byte(resultsJSON), &deleteJobEntity.Results); err != nil {
+		return entity.DeleteJobEntity{}, err
+	}
+
+	return deleteJobEntity, nil
+}
+
+// IncrementSplitClicks увеличивает на единицу количество переходов по короткой ссылке shortURI на вариант variant
+func (r *DBShortURLRepository) IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	if _, err := dbLookup.ExecContext(ctx, sqlIncrementSplitClicks, shortURI, variant); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// IncrementCountryClicks увеличивает на единицу количество переходов по короткой ссылке shortURI из страны country
+func (r *DBShortURLRepository) IncrementCountryClicks(ctx context.Context, shortURI string, country string) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	if _, err := dbLookup.ExecContext(ctx, sqlIncrementCountryClicks, shortURI, country); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// ConsumeShortURLClick учитывает переход по короткой ссылке shortURI с ограничением количества переходов,
+// для коротких ссылок без ограничения переход не учитывается
+//
+// Возвращает ErrClicksExhausted, если переходы по короткой ссылке исчерпаны, ErrNotFound - если короткая ссылка не найдена.
+func (r *DBShortURLRepository) ConsumeShortURLClick(ctx context.Context, shortURI string) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	res, err := dbLookup.ExecContext(ctx, sqlConsumeClick, shortURI)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	consumedCount, err := res.RowsAffected()
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+	if consumedCount > 0 {
+		return nil
+	}
+
+	var maxClicks int
+	err = dbLookup.QueryRowContext(ctx, sqlSelectMaxClicks, shortURI).Scan(&maxClicks)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
 		}
 
-		wg.Wait()
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+	if maxClicks == 0 {
+		return nil
+	}
+
+	return ErrClicksExhausted
+}
+
+// GetSplitClicks возвращает количество переходов по короткой ссылке shortURI по вариантам перенаправления
+func (r *DBShortURLRepository) GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	var result map[string]int64
+	err := r.dbLookup.RetryRead(ctx, func() error {
+		var err error
+		result, err = r.readClicks(ctx, sqlSelectSplitClicks, shortURI)
+		return err
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return result, nil
+}
+
+// GetCountryClicks возвращает количество переходов по короткой ссылке shortURI по странам
+func (r *DBShortURLRepository) GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	var result map[string]int64
+	err := r.dbLookup.RetryRead(ctx, func() error {
+		var err error
+		result, err = r.readClicks(ctx, sqlSelectCountryClicks, shortURI)
+		return err
+	})
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
 
-		if err := tx.Commit(); err != nil {
+	return result, nil
+}
+
+// readClicks возвращает счетчики переходов по короткой ссылке shortURI, выбранные запросом query
+func (r *DBShortURLRepository) readClicks(ctx context.Context, query string, shortURI string) (map[string]int64, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	rows, err := dbLookup.QueryContext(ctx, query, shortURI)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
 			log.Errorw("repository: unexpected error", "err", err)
 		}
 	}()
+
+	result := make(map[string]int64)
+	for rows.Next() {
+		var key string
+		var clicks int64
+		if err := rows.Scan(&key, &clicks); err != nil {
+			return nil, err
+		}
+
+		result[key] = clicks
+	}
+
+	return result, rows.Err()
 }
 
-// GetStats возвращает статистику по сервису
-// urlCount - количество коротких ссылок в сервисе
-// userCount - количество пользователей в сервисе
-func (r *DBShortURLRepository) GetStats(ctx context.Context) (urlCount int, userCount int, err error) {
+// GetUserSettings возвращает настройки пользователя userID, для пользователя без сохраненных настроек
+// возвращаются настройки по умолчанию
+func (r *DBShortURLRepository) GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error) {
 	dbLookup := r.dbLookup.GetDB()
-	sqlRow := dbLookup.QueryRowContext(ctx, sqlStats)
-	if err := sqlRow.Err(); err != nil {
+
+	var userSettingsEntity entity.UserSettingsEntity
+	err := r.dbLookup.RetryRead(ctx, func() error {
+		return dbLookup.QueryRowContext(ctx, sqlSelectUserSettings, userID).Scan(
+			&userSettingsEntity.UserID,
+			&userSettingsEntity.FallbackURL,
+		)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.UserSettingsEntity{UserID: userID}, nil
+		}
+
 		log.Errorw("repository: unexpected error", "err", err)
-		return 0, 0, ErrUnexpected
+		return entity.UserSettingsEntity{}, ErrUnexpected
 	}
 
-	if err := sqlRow.Scan(&urlCount, &userCount); err != nil {
+	return userSettingsEntity, nil
+}
+
+// SaveUserSettings сохраняет настройки пользователя
+func (r *DBShortURLRepository) SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	_, err := dbLookup.ExecContext(ctx, sqlUpsertUserSettings, userSettingsEntity.UserID, userSettingsEntity.FallbackURL)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// GetStats возвращает статистику по сервису
+// urlCount - количество коротких ссылок в сервисе
+// userCount - количество пользователей в сервисе
+func (r *DBShortURLRepository) GetStats(ctx context.Context) (urlCount int, userCount int, err error) {
+	err = r.dbLookup.ReadReplica(ctx, "", func(dbLookup *sql.DB) error {
+		return dbLookup.QueryRowContext(ctx, sqlStats).Scan(&urlCount, &userCount)
+	})
+	if err != nil {
 		log.Errorw("repository: unexpected error", "err", err)
 		return 0, 0, ErrUnexpected
 	}
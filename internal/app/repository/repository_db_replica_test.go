@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/vkhrushchev/urlshortener/internal/app/db"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+	"github.com/vkhrushchev/urlshortener/internal/util"
+)
+
+// DBReplicaShortURLRepositoryTestSuite проверяет чтение с реплики на двух независимых базах данных:
+// изменения основной базы данных не попадают на реплику, что соответствует отставанию репликации
+type DBReplicaShortURLRepositoryTestSuite struct {
+	suite.Suite
+	primaryContainer *postgres.PostgresContainer
+	replicaContainer *postgres.PostgresContainer
+	repository       *DBShortURLRepository
+	replicaDBLookup  *db.DBLookup
+	replicaRepo      *DBShortURLRepository
+}
+
+func runTestPostgresContainer(ctx context.Context) (*postgres.PostgresContainer, error) {
+	return postgres.Run(
+		ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("urlshortener"),
+		postgres.WithUsername("urlshortener"),
+		postgres.WithPassword("urlshortener"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(5*time.Second)),
+	)
+}
+
+func (s *DBReplicaShortURLRepositoryTestSuite) SetupSuite() {
+	ctx := context.Background()
+
+	var err error
+	s.primaryContainer, err = runTestPostgresContainer(ctx)
+	s.Require().NoError(err, "repository: failed to start primary postgres container")
+	s.replicaContainer, err = runTestPostgresContainer(ctx)
+	s.Require().NoError(err, "repository: failed to start replica postgres container")
+
+	primaryDSN := s.primaryContainer.MustConnectionString(ctx, "sslmode=disable")
+	replicaDSN := s.replicaContainer.MustConnectionString(ctx, "sslmode=disable")
+
+	s.replicaDBLookup, err = db.NewDBLookup(replicaDSN)
+	s.Require().NoError(err, "repository: failed to create replica dbLookup")
+	s.Require().NoError(s.replicaDBLookup.InitDB(ctx), "repository: failed to init replica dbLookup")
+	s.replicaRepo = NewDBShortURLRepository(s.replicaDBLookup)
+
+	dbLookup, err := db.NewDBLookupWithReplicas(primaryDSN, db.PoolConfig{}, db.ReplicaConfig{
+		DSNs:              []string{replicaDSN},
+		PrimaryReadWindow: time.Minute,
+	})
+	s.Require().NoError(err, "repository: failed to create dbLookup")
+	s.Require().NoError(dbLookup.InitDB(ctx), "repository: failed to init dbLookup")
+	s.repository = NewDBShortURLRepository(dbLookup)
+}
+
+func (s *DBReplicaShortURLRepositoryTestSuite) TearDownSuite() {
+	s.NoError(s.replicaDBLookup.Close())
+	s.NoError(s.primaryContainer.Terminate(context.Background()))
+	s.NoError(s.replicaContainer.Terminate(context.Background()))
+}
+
+func (s *DBReplicaShortURLRepositoryTestSuite) newShortURL(userID string) *entity.ShortURLEntity {
+	return &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: util.RandStringRunes(10),
+		LongURL:  "https://mail.ru/" + util.RandStringRunes(10),
+		UserID:   userID,
+	}
+}
+
+func (s *DBReplicaShortURLRepositoryTestSuite) TestGetShortURLByShortURI_not_replicated() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURL, err := s.repository.SaveShortURL(testCtx, s.newShortURL(testUserID))
+	s.Require().NoError(err)
+
+	shortURLEntity, err := s.repository.GetShortURLByShortURI(context.Background(), testShortURL.ShortURI)
+	s.Require().NoError(err, "short url missing on replica must be found on primary")
+	s.Equal(testShortURL.LongURL, shortURLEntity.LongURL)
+
+	_, err = s.repository.GetShortURLByShortURI(context.Background(), "not_existed_shortURL")
+	s.ErrorIs(err, ErrNotFound)
+}
+
+func (s *DBReplicaShortURLRepositoryTestSuite) TestGetShortURLByShortURI_replica() {
+	testUserID := uuid.NewString()
+	testShortURL := s.newShortURL(testUserID)
+	_, err := s.replicaRepo.SaveShortURL(context.Background(), testShortURL)
+	s.Require().NoError(err)
+
+	shortURLEntity, err := s.repository.GetShortURLByShortURI(context.Background(), testShortURL.ShortURI)
+	s.Require().NoError(err, "short url must be found on replica")
+	s.Equal(testShortURL.LongURL, shortURLEntity.LongURL)
+}
+
+func (s *DBReplicaShortURLRepositoryTestSuite) TestGetShortURLsByUserID_read_your_writes() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURL, err := s.repository.SaveShortURL(testCtx, s.newShortURL(testUserID))
+	s.Require().NoError(err)
+
+	shortURLEntities, err := s.repository.GetShortURLsByUserID(testCtx, testUserID)
+	s.Require().NoError(err)
+	s.Require().Len(shortURLEntities, 1, "user must see own short url right after creation")
+	s.Equal(testShortURL.ShortURI, shortURLEntities[0].ShortURI)
+
+	otherUserID := uuid.NewString()
+	_, err = s.replicaRepo.SaveShortURL(context.Background(), s.newShortURL(otherUserID))
+	s.Require().NoError(err)
+	shortURLEntities, err = s.repository.GetShortURLsByUserID(context.Background(), otherUserID)
+	s.Require().NoError(err)
+	s.Len(shortURLEntities, 1, "short urls of user without recent writes must be read from replica")
+}
+
+func (s *DBReplicaShortURLRepositoryTestSuite) TestGetShortURLsByQuery_read_your_writes() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURL, err := s.repository.SaveShortURL(testCtx, s.newShortURL(testUserID))
+	s.Require().NoError(err)
+
+	shortURLEntities, totalCount, err := s.repository.GetShortURLsByQuery(testCtx, entity.ShortURLQueryEntity{
+		UserID: testUserID,
+		SortBy: entity.ShortURLSortByCreatedAt,
+		Limit:  10,
+	})
+	s.Require().NoError(err)
+	s.Equal(1, totalCount, "user must see own short url right after creation")
+	s.Require().Len(shortURLEntities, 1)
+	s.Equal(testShortURL.ShortURI, shortURLEntities[0].ShortURI)
+
+	otherUserID := uuid.NewString()
+	_, err = s.replicaRepo.SaveShortURL(context.Background(), s.newShortURL(otherUserID))
+	s.Require().NoError(err)
+	shortURLEntities, totalCount, err = s.repository.GetShortURLsByQuery(context.Background(), entity.ShortURLQueryEntity{
+		UserID: otherUserID,
+		SortBy: entity.ShortURLSortByCreatedAt,
+		Limit:  10,
+	})
+	s.Require().NoError(err)
+	s.Equal(1, totalCount, "short urls of user without recent writes must be counted on replica")
+	s.Len(shortURLEntities, 1, "short urls of user without recent writes must be read from replica")
+}
+
+func (s *DBReplicaShortURLRepositoryTestSuite) TestGetStats_replica_fallback() {
+	ctx := context.Background()
+	testUserID := uuid.NewString()
+	_, err := s.repository.SaveShortURL(context.WithValue(ctx, common.UserIDContextKey, testUserID), s.newShortURL(testUserID))
+	s.Require().NoError(err)
+
+	replicaURLCount, replicaUserCount, err := s.replicaRepo.GetStats(ctx)
+	s.Require().NoError(err)
+	urlCount, userCount, err := s.repository.GetStats(ctx)
+	s.Require().NoError(err)
+	s.Equal(replicaURLCount, urlCount, "stats must be read from replica")
+	s.Equal(replicaUserCount, userCount, "stats must be read from replica")
+
+	stopTimeout := 5 * time.Second
+	s.Require().NoError(s.replicaContainer.Stop(ctx, &stopTimeout))
+
+	urlCount, _, err = s.repository.GetStats(ctx)
+	s.Require().NoError(err, "stats must be read from primary when replica is unavailable")
+	s.GreaterOrEqual(urlCount, 1)
+	s.Equal(int64(0), s.repository.dbLookup.Stats().HealthyReplicaCount)
+
+	shortURLEntity, err := s.repository.GetShortURLByShortURI(ctx, "not_existed_shortURL")
+	s.ErrorIs(err, ErrNotFound, "unexpected short url: %v", shortURLEntity)
+}
+
+func TestDBReplicaShortURLRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(DBReplicaShortURLRepositoryTestSuite))
+}
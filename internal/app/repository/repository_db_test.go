@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"github.com/vkhrushchev/urlshortener/internal/common"
+	"net/http"
 	"testing"
 	"time"
 
@@ -13,13 +15,50 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/vkhrushchev/urlshortener/internal/app/db"
 	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
 	"github.com/vkhrushchev/urlshortener/internal/util"
 )
 
+// dbShortURLRepository - репозиторий коротких ссылок в БД, проверяемый DBShortURLRepositoryTestSuite
+type dbShortURLRepository interface {
+	SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error)
+	SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error)
+
+	GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
+	GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+	GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error)
+	GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+	ForEachShortURL(ctx context.Context, fn func(shortURLEntity entity.ShortURLEntity) error) error
+
+	UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error)
+	UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error
+	ConsumeShortURLClick(ctx context.Context, shortURI string) error
+
+	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error)
+	RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error)
+	PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error)
+
+	SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error
+	GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error)
+	GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error)
+
+	IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error
+	GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error)
+	IncrementCountryClicks(ctx context.Context, shortURI string, country string) error
+	GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error)
+
+	GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error)
+	SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error
+
+	GetStats(ctx context.Context) (urlCount int, userCount int, err error)
+}
+
 type DBShortURLRepositoryTestSuite struct {
 	suite.Suite
 	postgresContainer *postgres.PostgresContainer
-	repository        *DBShortURLRepository
+	repository        dbShortURLRepository
 }
 
 func (s *DBShortURLRepositoryTestSuite) SetupSuite() {
@@ -90,6 +129,92 @@ func (s *DBShortURLRepositoryTestSuite) TestSaveShortURL_conflict() {
 	s.NotNil(savedShortURL, "savedShortURL should not be nil")
 }
 
+func (s *DBShortURLRepositoryTestSuite) TestSaveShortURL_same_original_url_on_other_domain() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURI := util.RandStringRunes(10)
+	testLongURL := "https://mail.ru/" + util.RandStringRunes(10)
+
+	_, err := s.repository.SaveShortURL(testCtx, &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: testShortURI,
+		LongURL:  testLongURL,
+		UserID:   testUserID,
+	})
+	s.NoError(err, "failed to save shortURL on default domain")
+
+	_, err = s.repository.SaveShortURL(testCtx, &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: shortdomain.Key("brand-b.link", testShortURI),
+		LongURL:  testLongURL,
+		UserID:   testUserID,
+	})
+	s.NoError(err, "same original url and shortURI must be saved on other domain")
+
+	shortURLEntity, err := s.repository.GetShortURLByShortURI(testCtx, shortdomain.Key("brand-b.link", testShortURI))
+	s.NoError(err, "failed to get shortURL on other domain")
+	s.Equal(testLongURL, shortURLEntity.LongURL)
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestSaveShortURL_short_uri_race() {
+	assertSaveShortURLShortURIRace(s.T(), s.repository)
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestSaveShortURLs_conflict() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	existedShortURL := entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: util.RandStringRunes(10),
+		LongURL:  "https://mail.ru/" + util.RandStringRunes(10),
+		UserID:   testUserID,
+	}
+	_, err := s.repository.SaveShortURL(testCtx, &existedShortURL)
+	s.Require().NoError(err)
+
+	newShortURL := func(shortURI string, longURL string) entity.ShortURLEntity {
+		return entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: shortURI, LongURL: longURL, UserID: testUserID}
+	}
+	freeShortURI := util.RandStringRunes(10)
+	freeLongURL := "https://mail.ru/" + util.RandStringRunes(10)
+	testCases := []struct {
+		name  string
+		batch []entity.ShortURLEntity
+	}{
+		{
+			name: "short uri is taken",
+			batch: []entity.ShortURLEntity{
+				newShortURL(freeShortURI, freeLongURL),
+				newShortURL(existedShortURL.ShortURI, "https://mail.ru/"+util.RandStringRunes(10)),
+			},
+		},
+		{
+			name: "short uri is repeated in batch",
+			batch: []entity.ShortURLEntity{
+				newShortURL(freeShortURI, freeLongURL),
+				newShortURL(freeShortURI, "https://mail.ru/"+util.RandStringRunes(10)),
+			},
+		},
+		{
+			name: "original url is already shortened",
+			batch: []entity.ShortURLEntity{
+				newShortURL(freeShortURI, freeLongURL),
+				newShortURL(util.RandStringRunes(10), existedShortURL.LongURL),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			_, err := s.repository.SaveShortURLs(testCtx, testCase.batch)
+			s.ErrorIs(err, ErrConflict, "expected ErrConflict, got %v", err)
+
+			_, err = s.repository.GetShortURLByShortURI(testCtx, freeShortURI)
+			s.ErrorIs(err, ErrNotFound, "batch with conflict must not be saved")
+		})
+	}
+}
+
 func (s *DBShortURLRepositoryTestSuite) TestSaveShortURLs_success() {
 	testUserID := uuid.NewString()
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
@@ -150,20 +275,493 @@ func (s *DBShortURLRepositoryTestSuite) TestFull() {
 	s.NotNil(savedShortURLsByUserID, "savedShortURLsByUserID should not be nil")
 	s.Equal(1, len(savedShortURLsByUserID), "savedShortURLsByUserID len mast equal 1")
 
-	s.repository.DeleteShortURLsByShortURIs(testCtx, []string{shortURL.ShortURI, "not_existed_shortURL"})
+	deleteShortURLResultEntities, err := s.repository.DeleteShortURLsByShortURIs(testCtx, []string{shortURL.ShortURI, "not_existed_shortURL"})
+	if err != nil {
+		s.Fail("unexpected error when delete ShortURLEntities by shortURIs: %v", err)
+	}
+	s.Equal(
+		[]entity.DeleteShortURLResultEntity{
+			{ShortURI: shortURL.ShortURI, Status: entity.DeleteStatusDeleted},
+			{ShortURI: "not_existed_shortURL", Status: entity.DeleteStatusNotFound},
+		},
+		deleteShortURLResultEntities,
+	)
+
+	deletedShortURL, err := s.repository.GetShortURLByShortURI(testCtx, shortURL.ShortURI)
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntity by shortURI: %v", err)
+	}
+	s.True(deletedShortURL.Deleted, "deletedShortURL must be deleted")
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestQueryShortURLs() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testCreatedAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	testShortURLs := []entity.ShortURLEntity{
+		{UUID: uuid.NewString(), ShortURI: util.RandStringRunes(10), LongURL: "https://ya.ru/" + util.RandStringRunes(10), UserID: testUserID, CreatedAt: testCreatedAt},
+		{UUID: uuid.NewString(), ShortURI: util.RandStringRunes(10), LongURL: "https://mail.ya.ru/" + util.RandStringRunes(10), UserID: testUserID, CreatedAt: testCreatedAt.Add(time.Hour)},
+		{UUID: uuid.NewString(), ShortURI: util.RandStringRunes(10), LongURL: "https://notya.ru/100%_" + util.RandStringRunes(10), UserID: testUserID, CreatedAt: testCreatedAt.Add(2 * time.Hour)},
+	}
+
+	_, err := s.repository.SaveShortURLs(testCtx, testShortURLs)
+	if err != nil {
+		s.Fail("unexpected error when save ShortURLEntities: %v", err)
+	}
+
+	firstPage, totalCount, err := s.repository.GetShortURLsByQuery(testCtx, entity.ShortURLQueryEntity{
+		UserID:   testUserID,
+		Status:   entity.ShortURLStatusActive,
+		SortBy:   entity.ShortURLSortByCreatedAt,
+		SortDesc: true,
+		Limit:    2,
+	})
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntities by query: %v", err)
+	}
+	s.Equal(3, totalCount, "totalCount must equal 3")
+	s.Equal(2, len(firstPage), "firstPage len must equal 2")
+	s.Equal(testShortURLs[2].UUID, firstPage[0].UUID)
+	s.Equal(testShortURLs[1].UUID, firstPage[1].UUID)
+
+	secondPage, _, err := s.repository.GetShortURLsByQuery(testCtx, entity.ShortURLQueryEntity{
+		UserID:   testUserID,
+		Status:   entity.ShortURLStatusActive,
+		SortBy:   entity.ShortURLSortByCreatedAt,
+		SortDesc: true,
+		After: &entity.ShortURLCursorEntity{
+			SortValue: firstPage[1].CreatedAt.UTC().Format(time.RFC3339Nano),
+			UUID:      firstPage[1].UUID,
+		},
+		Limit: 2,
+	})
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntities by query: %v", err)
+	}
+	s.Equal(1, len(secondPage), "secondPage len must equal 1")
+	s.Equal(testShortURLs[0].UUID, secondPage[0].UUID)
+
+	domainPage, totalCount, err := s.repository.GetShortURLsByQuery(testCtx, entity.ShortURLQueryEntity{
+		UserID: testUserID,
+		Domain: "ya.ru",
+		SortBy: entity.ShortURLSortByOriginalURL,
+		Limit:  10,
+	})
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntities by query: %v", err)
+	}
+	s.Equal(2, totalCount, "totalCount must equal 2")
+	s.Equal(testShortURLs[1].UUID, domainPage[0].UUID)
+
+	searchPage, totalCount, err := s.repository.GetShortURLsByQuery(testCtx, entity.ShortURLQueryEntity{
+		UserID: testUserID,
+		Search: "100%_",
+		SortBy: entity.ShortURLSortByShortURL,
+		Limit:  10,
+	})
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntities by query: %v", err)
+	}
+	s.Equal(1, totalCount, "totalCount must equal 1")
+	s.Equal(testShortURLs[2].UUID, searchPage[0].UUID)
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestRestoreAndPurgeDeletedShortURLs() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURLs := []entity.ShortURLEntity{
+		{
+			UUID:     uuid.NewString(),
+			ShortURI: util.RandStringRunes(10),
+			LongURL:  "https://mail.ru/" + util.RandStringRunes(10),
+			UserID:   testUserID,
+		},
+		{
+			UUID:     uuid.NewString(),
+			ShortURI: util.RandStringRunes(10),
+			LongURL:  "https://vk.com/" + util.RandStringRunes(10),
+			UserID:   testUserID,
+		},
+	}
+
+	_, err := s.repository.SaveShortURLs(testCtx, testShortURLs)
+	if err != nil {
+		s.Fail("unexpected error when save ShortURLEntities: %v", err)
+	}
+
+	_, err = s.repository.DeleteShortURLsByShortURIs(testCtx, []string{testShortURLs[0].ShortURI, testShortURLs[1].ShortURI})
 	if err != nil {
 		s.Fail("unexpected error when delete ShortURLEntities by shortURIs: %v", err)
 	}
+
+	deletedShortURLs, err := s.repository.GetDeletedShortURLsByUserID(testCtx, testUserID)
+	if err != nil {
+		s.Fail("unexpected error when get deleted ShortURLEntities by userID: %v", err)
+	}
+	s.Equal(2, len(deletedShortURLs), "deletedShortURLs len must equal 2")
+	s.NotNil(deletedShortURLs[0].DeletedAt, "deletedAt must be set")
+
+	restoreShortURLResultEntities, err := s.repository.RestoreShortURLsByShortURIs(testCtx, []string{testShortURLs[0].ShortURI, "not_existed_shortURL"})
+	if err != nil {
+		s.Fail("unexpected error when restore ShortURLEntities by shortURIs: %v", err)
+	}
+	s.Equal(
+		[]entity.RestoreShortURLResultEntity{
+			{ShortURI: testShortURLs[0].ShortURI, Status: entity.RestoreStatusRestored},
+			{ShortURI: "not_existed_shortURL", Status: entity.RestoreStatusNotFound},
+		},
+		restoreShortURLResultEntities,
+	)
+
+	purgedCount, err := s.repository.PurgeDeletedShortURLs(testCtx, time.Now().Add(time.Minute))
+	if err != nil {
+		s.Fail("unexpected error when purge deleted ShortURLEntities: %v", err)
+	}
+	s.LessOrEqual(1, purgedCount, "at least one ShortURLEntity must be purged")
+
+	_, err = s.repository.GetShortURLByShortURI(testCtx, testShortURLs[1].ShortURI)
+	s.ErrorIs(err, ErrNotFound, "purged ShortURLEntity must not be found")
+
+	restoredShortURL, err := s.repository.GetShortURLByShortURI(testCtx, testShortURLs[0].ShortURI)
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntity by shortURI: %v", err)
+	}
+	s.False(restoredShortURL.Deleted, "restoredShortURL must not be deleted")
+	s.Nil(restoredShortURL.DeletedAt, "restoredShortURL deletedAt must be reset")
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestSaveDeleteJob() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testDeleteJob := &entity.DeleteJobEntity{
+		ID:        uuid.NewString(),
+		UserID:    testUserID,
+		Status:    entity.DeleteJobStatusPending,
+		ShortURIs: []string{"not_existed_shortURL"},
+		Results:   []entity.DeleteShortURLResultEntity{},
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+		UpdatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	err := s.repository.SaveDeleteJob(testCtx, testDeleteJob)
+	if err != nil {
+		s.Fail("unexpected error when save DeleteJobEntity: %v", err)
+	}
+
+	testDeleteJob.Status = entity.DeleteJobStatusDone
+	testDeleteJob.Results = []entity.DeleteShortURLResultEntity{
+		{ShortURI: "not_existed_shortURL", Status: entity.DeleteStatusNotFound},
+	}
+	err = s.repository.SaveDeleteJob(testCtx, testDeleteJob)
+	if err != nil {
+		s.Fail("unexpected error when update DeleteJobEntity: %v", err)
+	}
+
+	savedDeleteJob, err := s.repository.GetDeleteJobByID(testCtx, testDeleteJob.ID)
+	if err != nil {
+		s.Fail("unexpected error when get DeleteJobEntity by ID: %v", err)
+	}
+	s.Equal(testDeleteJob.Status, savedDeleteJob.Status)
+	s.Equal(testDeleteJob.Results, savedDeleteJob.Results)
+
+	pendingDeleteJobs, err := s.repository.GetDeleteJobsByStatus(testCtx, entity.DeleteJobStatusPending)
+	if err != nil {
+		s.Fail("unexpected error when get DeleteJobEntities by status: %v", err)
+	}
+	s.Equal(0, len(pendingDeleteJobs), "pendingDeleteJobs should be empty")
 }
 
 func (s *DBShortURLRepositoryTestSuite) TestGetStats() {
+	urlCountBefore, userCountBefore, err := s.repository.GetStats(context.Background())
+	if err != nil {
+		s.Fail("unexpected error when get stats", "error: %v", err)
+	}
+
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	_, err = s.repository.SaveShortURL(testCtx, &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: util.RandStringRunes(10),
+		LongURL:  "https://mail.ru/" + util.RandStringRunes(10),
+		UserID:   testUserID,
+	})
+	if err != nil {
+		s.Fail("unexpected error when save ShortURLEntity: %v", err)
+	}
+
 	urlCount, userCount, err := s.repository.GetStats(context.Background())
 	if err != nil {
 		s.Fail("unexpected error when get stats", "error: %v", err)
 	}
 
-	s.Equal(1, urlCount, "urlCount should be 1")
-	s.Equal(1, userCount, "userCount should be 1")
+	s.Equal(urlCountBefore+1, urlCount, "urlCount should be increased by 1")
+	s.Equal(userCountBefore+1, userCount, "userCount should be increased by 1")
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestUpdateShortURL() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURL := &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: util.RandStringRunes(10),
+		LongURL:  "https://mail.ru/" + util.RandStringRunes(10),
+		UserID:   testUserID,
+		Tags:     []string{"mail"},
+	}
+
+	savedShortURL, err := s.repository.SaveShortURL(testCtx, testShortURL)
+	if err != nil {
+		s.Fail("unexpected error when save ShortURLEntity: %v", err)
+	}
+
+	savedShortURL.Title = "Почта"
+	savedShortURL.Description = "почтовый сервис"
+	savedShortURL.Tags = []string{"mail", "work"}
+	savedShortURL.RedirectStatus = http.StatusPermanentRedirect
+	savedShortURL.QueryPassthrough = "keep_target"
+	savedShortURL.UTMParams = map[string]string{"utm_source": "shortener"}
+	savedShortURL.TargetingRules = []targeting.Rule{{Device: targeting.DeviceMobile, Locale: "ru", URL: "https://m.mail.ru"}}
+	savedShortURL.SplitVariants = []split.Variant{
+		{Name: "a", URL: "https://mail.ru/a", Weight: 70},
+		{Name: "b", URL: "https://mail.ru/b", Weight: 30},
+	}
+	savedShortURL.PasswordHash = "$2a$10$hash"
+	testNotBefore := time.Now().Add(time.Hour).Truncate(time.Second)
+	savedShortURL.NotBefore = &testNotBefore
+	savedShortURL.Disabled = true
+	savedShortURL.FallbackURL = "https://example.com/gone"
+	updatedShortURL, err := s.repository.UpdateShortURL(testCtx, *savedShortURL)
+	if err != nil {
+		s.Fail("unexpected error when update ShortURLEntity: %v", err)
+	}
+	s.Equal("Почта", updatedShortURL.Title)
+	s.Equal("почтовый сервис", updatedShortURL.Description)
+	s.Equal([]string{"mail", "work"}, updatedShortURL.Tags)
+	s.Equal(http.StatusPermanentRedirect, updatedShortURL.RedirectStatus)
+	s.Equal("keep_target", updatedShortURL.QueryPassthrough)
+	s.Equal(map[string]string{"utm_source": "shortener"}, updatedShortURL.UTMParams)
+	s.Equal(savedShortURL.TargetingRules, updatedShortURL.TargetingRules)
+	s.Equal(savedShortURL.SplitVariants, updatedShortURL.SplitVariants)
+	s.Equal("$2a$10$hash", updatedShortURL.PasswordHash)
+	if s.NotNil(updatedShortURL.NotBefore) {
+		s.True(testNotBefore.Equal(*updatedShortURL.NotBefore), "not before must be saved")
+	}
+	s.Nil(updatedShortURL.NotAfter)
+	s.True(updatedShortURL.Disabled)
+	s.Equal("https://example.com/gone", updatedShortURL.FallbackURL)
+
+	shortURLEntities, totalCount, err := s.repository.GetShortURLsByQuery(
+		testCtx,
+		entity.ShortURLQueryEntity{UserID: testUserID, Tag: "work", SortBy: entity.ShortURLSortByCreatedAt, Limit: 10},
+	)
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntities by query: %v", err)
+	}
+	s.Equal(1, totalCount, "ShortURLEntity must be found by tag")
+	s.Equal(testShortURL.ShortURI, shortURLEntities[0].ShortURI)
+
+	savedShortURL.UserID = uuid.NewString()
+	_, err = s.repository.UpdateShortURL(testCtx, *savedShortURL)
+	s.ErrorIs(err, ErrNotFound, "ShortURLEntity of another user must not be updated")
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestUpdateShortURLPreview() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURL := &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: util.RandStringRunes(10),
+		LongURL:  "https://ya.ru/" + util.RandStringRunes(10),
+		UserID:   testUserID,
+	}
+
+	_, err := s.repository.SaveShortURL(testCtx, testShortURL)
+	if err != nil {
+		s.Fail("unexpected error when save ShortURLEntity: %v", err)
+	}
+
+	err = s.repository.UpdateShortURLPreview(
+		testCtx,
+		testShortURL.ShortURI,
+		entity.ShortURLPreviewEntity{Title: "Яндекс", Description: "Поиск", ImageURL: "https://ya.ru/logo.png", FetchedAt: time.Now()},
+	)
+	if err != nil {
+		s.Fail("unexpected error when update ShortURLEntity preview: %v", err)
+	}
+
+	updatedShortURL, err := s.repository.GetShortURLByShortURI(testCtx, testShortURL.ShortURI)
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntity by shortURI: %v", err)
+	}
+	s.Equal("Яндекс", updatedShortURL.PreviewTitle)
+	s.Equal("Поиск", updatedShortURL.PreviewDescription)
+	s.Equal("https://ya.ru/logo.png", updatedShortURL.PreviewImageURL)
+	s.NotNil(updatedShortURL.PreviewFetchedAt, "previewFetchedAt must be set")
+
+	err = s.repository.UpdateShortURLPreview(testCtx, "not_existed_shortURL", entity.ShortURLPreviewEntity{})
+	s.ErrorIs(err, ErrNotFound)
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestIncrementSplitClicks() {
+	testShortURI := util.RandStringRunes(10)
+	for _, variant := range []string{"a", "b", "a"} {
+		err := s.repository.IncrementSplitClicks(context.Background(), testShortURI, variant)
+		if err != nil {
+			s.Fail("unexpected error when increment split clicks: %v", err)
+		}
+	}
+
+	splitClicks, err := s.repository.GetSplitClicks(context.Background(), testShortURI)
+	if err != nil {
+		s.Fail("unexpected error when get split clicks: %v", err)
+	}
+	s.Equal(map[string]int64{"a": 2, "b": 1}, splitClicks)
+
+	splitClicks, err = s.repository.GetSplitClicks(context.Background(), "not_existed_shortURL")
+	if err != nil {
+		s.Fail("unexpected error when get split clicks: %v", err)
+	}
+	s.Empty(splitClicks, "not existed shortURL must not have split clicks")
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestIncrementCountryClicks() {
+	testShortURI := util.RandStringRunes(10)
+	for _, country := range []string{"SE", "GB", "SE"} {
+		err := s.repository.IncrementCountryClicks(context.Background(), testShortURI, country)
+		if err != nil {
+			s.Fail("unexpected error when increment country clicks: %v", err)
+		}
+	}
+
+	countryClicks, err := s.repository.GetCountryClicks(context.Background(), testShortURI)
+	if err != nil {
+		s.Fail("unexpected error when get country clicks: %v", err)
+	}
+	s.Equal(map[string]int64{"SE": 2, "GB": 1}, countryClicks)
+
+	countryClicks, err = s.repository.GetCountryClicks(context.Background(), "not_existed_shortURL")
+	if err != nil {
+		s.Fail("unexpected error when get country clicks: %v", err)
+	}
+	s.Empty(countryClicks, "not existed shortURL must not have country clicks")
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestConsumeShortURLClick() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURL := &entity.ShortURLEntity{
+		UUID:      uuid.NewString(),
+		ShortURI:  util.RandStringRunes(10),
+		LongURL:   "https://mail.ru/" + util.RandStringRunes(10),
+		UserID:    testUserID,
+		MaxClicks: 10,
+	}
+	if _, err := s.repository.SaveShortURL(testCtx, testShortURL); err != nil {
+		s.Fail("unexpected error when save ShortURLEntity: %v", err)
+	}
+
+	consumed, exhausted, err := consumeShortURLClickConcurrently(s.repository, testShortURL.ShortURI, 50)
+	if err != nil {
+		s.Fail("unexpected error when consume short url click: %v", err)
+	}
+	s.Equal(int64(10), consumed, "only MaxClicks clicks must be consumed")
+	s.Equal(int64(40), exhausted)
+
+	savedShortURL, err := s.repository.GetShortURLByShortURI(testCtx, testShortURL.ShortURI)
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntity: %v", err)
+	}
+	s.Equal(10, savedShortURL.MaxClicks)
+	s.Equal(int64(10), savedShortURL.ClickCount)
+
+	unlimitedShortURL := &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: util.RandStringRunes(10),
+		LongURL:  "https://mail.ru/" + util.RandStringRunes(10),
+		UserID:   testUserID,
+	}
+	if _, err := s.repository.SaveShortURL(testCtx, unlimitedShortURL); err != nil {
+		s.Fail("unexpected error when save ShortURLEntity: %v", err)
+	}
+	s.NoError(s.repository.ConsumeShortURLClick(testCtx, unlimitedShortURL.ShortURI), "click on short url without limit must be allowed")
+	s.ErrorIs(s.repository.ConsumeShortURLClick(testCtx, "not_existed_shortURL"), ErrNotFound)
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestSaveUserSettings() {
+	testCtx := context.Background()
+	testUserID := uuid.NewString()
+
+	userSettings, err := s.repository.GetUserSettings(testCtx, testUserID)
+	if err != nil {
+		s.Fail("unexpected error when get UserSettingsEntity: %v", err)
+	}
+	s.Equal(entity.UserSettingsEntity{UserID: testUserID}, userSettings, "default settings must be returned")
+
+	for _, fallbackURL := range []string{"https://example.com/old", "https://example.com/new"} {
+		err = s.repository.SaveUserSettings(testCtx, entity.UserSettingsEntity{UserID: testUserID, FallbackURL: fallbackURL})
+		if err != nil {
+			s.Fail("unexpected error when save UserSettingsEntity: %v", err)
+		}
+	}
+
+	userSettings, err = s.repository.GetUserSettings(testCtx, testUserID)
+	if err != nil {
+		s.Fail("unexpected error when get UserSettingsEntity: %v", err)
+	}
+	s.Equal("https://example.com/new", userSettings.FallbackURL, "last saved settings must be returned")
+}
+
+func (s *DBShortURLRepositoryTestSuite) TestForEachShortURL() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testDeletedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	testPreviewFetchedAt := time.Now().UTC().Truncate(time.Second)
+
+	// ссылок больше, чем помещается на одну страницу обхода
+	testShortURLs := make([]entity.ShortURLEntity, 0, shortURLPageSize+1)
+	for i := 0; i < shortURLPageSize+1; i++ {
+		testShortURLs = append(testShortURLs, entity.ShortURLEntity{
+			UUID:     uuid.NewString(),
+			ShortURI: util.RandStringRunes(10),
+			LongURL:  "https://mail.ru/" + util.RandStringRunes(10),
+			UserID:   testUserID,
+		})
+	}
+	testShortURLs[0].Deleted = true
+	testShortURLs[0].DeletedAt = &testDeletedAt
+	testShortURLs[0].ClickCount = 3
+	testShortURLs[0].PreviewTitle = "preview"
+	testShortURLs[0].PreviewFetchedAt = &testPreviewFetchedAt
+
+	_, err := s.repository.SaveShortURLs(testCtx, testShortURLs)
+	s.Require().NoError(err, "failed to save shortURLs")
+
+	visitedShortURLs := make(map[string]entity.ShortURLEntity)
+	err = s.repository.ForEachShortURL(context.Background(), func(shortURLEntity entity.ShortURLEntity) error {
+		_, visited := visitedShortURLs[shortURLEntity.UUID]
+		s.False(visited, "short url must be visited once: %s", shortURLEntity.ShortURI)
+		visitedShortURLs[shortURLEntity.UUID] = shortURLEntity
+
+		return nil
+	})
+	s.Require().NoError(err)
+
+	for _, testShortURL := range testShortURLs {
+		s.Contains(visitedShortURLs, testShortURL.UUID, "short url must be visited: %s", testShortURL.ShortURI)
+	}
+	deletedShortURL := visitedShortURLs[testShortURLs[0].UUID]
+	s.True(deletedShortURL.Deleted, "deleted short url must be visited")
+	s.Equal(int64(3), deletedShortURL.ClickCount, "click count must be saved")
+	s.Equal("preview", deletedShortURL.PreviewTitle, "preview must be saved")
+
+	errStop := errors.New("stop")
+	visitedCount := 0
+	err = s.repository.ForEachShortURL(context.Background(), func(shortURLEntity entity.ShortURLEntity) error {
+		visitedCount++
+		return errStop
+	})
+	s.ErrorIs(err, errStop)
+	s.Equal(1, visitedCount, "iteration must stop on first error")
 }
 
 func TestDBShortURLRepositoryTestSuite(t *testing.T) {
@@ -3,14 +3,24 @@ package repository
 import (
 	"context"
 	"github.com/vkhrushchev/urlshortener/internal/common"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/vkhrushchev/urlshortener/internal/app/entity"
 )
 
 // InMemoryShortURLRepository реализует интерфейс IShortURLRepository для хранения коротких ссылок в памяти
 type InMemoryShortURLRepository struct {
+	mutex           sync.RWMutex
 	storage         map[string]*entity.ShortURLEntity
 	storageByUserID map[string][]*entity.ShortURLEntity
+	deleteJobs      map[string]*entity.DeleteJobEntity
+	splitClicks     map[string]map[string]int64
+	countryClicks   map[string]map[string]int64
+	userSettings    map[string]*entity.UserSettingsEntity
 }
 
 // NewInMemoryShortURLRepository создает экземпляр структуры InMemoryShortURLRepository
@@ -18,11 +28,24 @@ func NewInMemoryShortURLRepository() *InMemoryShortURLRepository {
 	return &InMemoryShortURLRepository{
 		storage:         make(map[string]*entity.ShortURLEntity),
 		storageByUserID: make(map[string][]*entity.ShortURLEntity),
+		deleteJobs:      make(map[string]*entity.DeleteJobEntity),
+		splitClicks:     make(map[string]map[string]int64),
+		countryClicks:   make(map[string]map[string]int64),
+		userSettings:    make(map[string]*entity.UserSettingsEntity),
 	}
 }
 
+// GetRedirectShortURLByShortURI возвращает короткую ссылку по shortURI для перенаправления,
+// хранилище возвращает короткую ссылку полностью
+func (r *InMemoryShortURLRepository) GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	return r.GetShortURLByShortURI(ctx, shortURI)
+}
+
 // GetShortURLByShortURI возвращает короткую ссылку по shortURI
 func (r *InMemoryShortURLRepository) GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	shortURLEntry := r.storage[shortURI]
 	if shortURLEntry == nil {
 		return entity.ShortURLEntity{}, ErrNotFound
@@ -32,7 +55,25 @@ func (r *InMemoryShortURLRepository) GetShortURLByShortURI(ctx context.Context,
 }
 
 // SaveShortURL сохраняет короткую ссылку
+//
+// Возвращает ErrConflict без короткой ссылки, если короткая ссылка с таким ключом уже существует.
 func (r *InMemoryShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.storage[shortURLEntity.ShortURI]; ok {
+		return nil, ErrConflict
+	}
+
+	return r.saveShortURL(ctx, shortURLEntity), nil
+}
+
+// saveShortURL сохраняет короткую ссылку, ключ которой предварительно проверен на уникальность
+func (r *InMemoryShortURLRepository) saveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) *entity.ShortURLEntity {
+	if shortURLEntity.CreatedAt.IsZero() {
+		shortURLEntity.CreatedAt = time.Now()
+	}
+
 	r.storage[shortURLEntity.ShortURI] = shortURLEntity
 
 	userID := ctx.Value(common.UserIDContextKey).(string)
@@ -44,18 +85,31 @@ func (r *InMemoryShortURLRepository) SaveShortURL(ctx context.Context, shortURLE
 	shortURLEntitiesByUserID = append(shortURLEntitiesByUserID, r.storage[shortURLEntity.ShortURI])
 	r.storageByUserID[userID] = shortURLEntitiesByUserID
 
-	return r.storage[shortURLEntity.ShortURI], nil
+	return r.storage[shortURLEntity.ShortURI]
 }
 
 // SaveShortURLs сохраняет короткие ссылки пачкой
+//
+// Возвращает ErrConflict, если ключ одной из коротких ссылок уже существует или повторяется в пачке,
+// в этом случае ни одна короткая ссылка не сохраняется.
 func (r *InMemoryShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
-	result := make([]entity.ShortURLEntity, 0, len(shortURLEntities))
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	batchShortURIs := make(map[string]struct{}, len(shortURLEntities))
 	for _, shortURLEntity := range shortURLEntities {
-		savedShortURLEntity, err := r.SaveShortURL(ctx, &shortURLEntity)
-		if err != nil {
-			return nil, err
+		if _, ok := r.storage[shortURLEntity.ShortURI]; ok {
+			return nil, ErrConflict
+		}
+		if _, ok := batchShortURIs[shortURLEntity.ShortURI]; ok {
+			return nil, ErrConflict
 		}
+		batchShortURIs[shortURLEntity.ShortURI] = struct{}{}
+	}
 
+	result := make([]entity.ShortURLEntity, 0, len(shortURLEntities))
+	for _, shortURLEntity := range shortURLEntities {
+		savedShortURLEntity := r.saveShortURL(ctx, &shortURLEntity)
 		result = append(result, *savedShortURLEntity)
 	}
 
@@ -64,6 +118,9 @@ func (r *InMemoryShortURLRepository) SaveShortURLs(ctx context.Context, shortURL
 
 // GetShortURLsByUserID возвращает список коротких ссылок по userID
 func (r *InMemoryShortURLRepository) GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	shortURLEntitiesByUserID := r.storageByUserID[userID]
 	result := make([]entity.ShortURLEntity, 0, len(shortURLEntitiesByUserID))
 
@@ -74,16 +131,417 @@ func (r *InMemoryShortURLRepository) GetShortURLsByUserID(ctx context.Context, u
 	return result, nil
 }
 
-// DeleteShortURLsByShortURIs удаляет короткие ссылки по списку shortURI
-func (r *InMemoryShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) error {
+// ForEachShortURL вызывает fn для каждой короткой ссылки, включая удаленные, в порядке создания
+//
+// fn вызывается для снимка коротких ссылок без блокировки репозитория.
+// Обход прекращается при первой ошибке fn, которая возвращается без изменений.
+func (r *InMemoryShortURLRepository) ForEachShortURL(ctx context.Context, fn func(shortURLEntity entity.ShortURLEntity) error) error {
+	r.mutex.RLock()
+	shortURLEntities := make([]entity.ShortURLEntity, 0, len(r.storage))
+	for _, shortURLEntity := range r.storage {
+		shortURLEntities = append(shortURLEntities, *shortURLEntity)
+	}
+	r.mutex.RUnlock()
+
+	slices.SortFunc(shortURLEntities, func(a entity.ShortURLEntity, b entity.ShortURLEntity) int {
+		return compareShortURLs(&a, &b, entity.ShortURLSortByCreatedAt)
+	})
+
+	for _, shortURLEntity := range shortURLEntities {
+		if err := fn(shortURLEntity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetShortURLsByQuery возвращает страницу коротких ссылок пользователя, удовлетворяющих запросу query,
+// и общее количество удовлетворяющих фильтрам запроса коротких ссылок
+func (r *InMemoryShortURLRepository) GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	matchedShortURLEntities := make([]*entity.ShortURLEntity, 0)
+	for _, shortURLEntity := range r.storageByUserID[query.UserID] {
+		if matchShortURLQuery(shortURLEntity, query) {
+			matchedShortURLEntities = append(matchedShortURLEntities, shortURLEntity)
+		}
+	}
+
+	compare := func(a *entity.ShortURLEntity, b *entity.ShortURLEntity) int {
+		if query.SortDesc {
+			return compareShortURLs(b, a, query.SortBy)
+		}
+		return compareShortURLs(a, b, query.SortBy)
+	}
+	slices.SortFunc(matchedShortURLEntities, compare)
+
+	start := 0
+	if query.After != nil {
+		afterShortURLEntity, err := cursorShortURL(*query.After, query.SortBy)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, 0, ErrUnexpected
+		}
+
+		start = sort.Search(len(matchedShortURLEntities), func(i int) bool {
+			return compare(matchedShortURLEntities[i], afterShortURLEntity) > 0
+		})
+	}
+
+	end := min(start+query.Limit, len(matchedShortURLEntities))
+	result := make([]entity.ShortURLEntity, 0, end-start)
+	for _, shortURLEntity := range matchedShortURLEntities[start:end] {
+		result = append(result, *shortURLEntity)
+	}
+
+	return result, len(matchedShortURLEntities), nil
+}
+
+// UpdateShortURL обновляет изменяемые поля короткой ссылки пользователя: название, описание, заметки, теги
+// и параметры перенаправления
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена или принадлежит другому пользователю.
+func (r *InMemoryShortURLRepository) UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	storedShortURLEntity := r.storage[shortURLEntity.ShortURI]
+	if storedShortURLEntity == nil || storedShortURLEntity.UserID != shortURLEntity.UserID {
+		return entity.ShortURLEntity{}, ErrNotFound
+	}
+
+	storedShortURLEntity.Title = shortURLEntity.Title
+	storedShortURLEntity.Description = shortURLEntity.Description
+	storedShortURLEntity.Notes = shortURLEntity.Notes
+	storedShortURLEntity.Tags = shortURLEntity.Tags
+	storedShortURLEntity.RedirectStatus = shortURLEntity.RedirectStatus
+	storedShortURLEntity.QueryPassthrough = shortURLEntity.QueryPassthrough
+	storedShortURLEntity.PathPassthrough = shortURLEntity.PathPassthrough
+	storedShortURLEntity.UTMParams = shortURLEntity.UTMParams
+	storedShortURLEntity.TargetingRules = shortURLEntity.TargetingRules
+	storedShortURLEntity.SplitVariants = shortURLEntity.SplitVariants
+	storedShortURLEntity.PasswordHash = shortURLEntity.PasswordHash
+	storedShortURLEntity.MaxClicks = shortURLEntity.MaxClicks
+	storedShortURLEntity.NotBefore = shortURLEntity.NotBefore
+	storedShortURLEntity.NotAfter = shortURLEntity.NotAfter
+	storedShortURLEntity.Disabled = shortURLEntity.Disabled
+	storedShortURLEntity.FallbackURL = shortURLEntity.FallbackURL
+
+	return *storedShortURLEntity, nil
+}
+
+// UpdateShortURLPreview сохраняет превью страницы исходного URL короткой ссылки
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена.
+func (r *InMemoryShortURLRepository) UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error {
+	_, err := r.updateShortURLPreview(shortURI, preview)
+
+	return err
+}
+
+func (r *InMemoryShortURLRepository) updateShortURLPreview(shortURI string, preview entity.ShortURLPreviewEntity) (entity.ShortURLEntity, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	storedShortURLEntity := r.storage[shortURI]
+	if storedShortURLEntity == nil {
+		return entity.ShortURLEntity{}, ErrNotFound
+	}
+
+	fetchedAt := preview.FetchedAt
+	storedShortURLEntity.PreviewTitle = preview.Title
+	storedShortURLEntity.PreviewDescription = preview.Description
+	storedShortURLEntity.PreviewImageURL = preview.ImageURL
+	storedShortURLEntity.PreviewFetchedAt = &fetchedAt
+
+	return *storedShortURLEntity, nil
+}
+
+// DeleteShortURLsByShortURIs удаляет короткие ссылки пользователя по списку shortURI
+// и возвращает результат удаления по каждой из них
+func (r *InMemoryShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	userID := ctx.Value(common.UserIDContextKey).(string)
+	now := time.Now()
+	ownerByShortURI := make(map[string]string, len(shortURIs))
 	for _, shortURI := range shortURIs {
 		shortURLEntry := r.storage[shortURI]
-		if shortURLEntry != nil && shortURLEntry.UserID == userID {
+		if shortURLEntry == nil {
+			continue
+		}
+
+		ownerByShortURI[shortURI] = shortURLEntry.UserID
+		if shortURLEntry.UserID == userID && !shortURLEntry.Deleted {
 			shortURLEntry.Deleted = true
+			shortURLEntry.DeletedAt = &now
+		}
+	}
+
+	result := make([]entity.DeleteShortURLResultEntity, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		result = append(result, entity.DeleteShortURLResultEntity{
+			ShortURI: shortURI,
+			Status:   deleteStatus(ownerByShortURI, shortURI, userID),
+		})
+	}
+
+	return result, nil
+}
+
+// GetDeletedShortURLsByUserID возвращает список удаленных коротких ссылок пользователя userID
+func (r *InMemoryShortURLRepository) GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]entity.ShortURLEntity, 0)
+	for _, shortURLEntity := range r.storageByUserID[userID] {
+		if shortURLEntity.Deleted {
+			result = append(result, *shortURLEntity)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return deletedAt(result[i]).After(deletedAt(result[j]))
+	})
+
+	return result, nil
+}
+
+// RestoreShortURLsByShortURIs восстанавливает удаленные короткие ссылки пользователя по списку shortURI
+// и возвращает результат восстановления по каждой из них
+func (r *InMemoryShortURLRepository) RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	result := make([]entity.RestoreShortURLResultEntity, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		shortURLEntry := r.storage[shortURI]
+
+		var status string
+		if shortURLEntry == nil {
+			status = restoreStatus(false, "", false, userID)
+		} else {
+			status = restoreStatus(true, shortURLEntry.UserID, shortURLEntry.Deleted, userID)
+		}
+
+		if status == entity.RestoreStatusRestored {
+			shortURLEntry.Deleted = false
+			shortURLEntry.DeletedAt = nil
+		}
+
+		result = append(result, entity.RestoreShortURLResultEntity{
+			ShortURI: shortURI,
+			Status:   status,
+		})
+	}
+
+	return result, nil
+}
+
+// PurgeDeletedShortURLs окончательно удаляет короткие ссылки, удаленные раньше deletedBefore,
+// и возвращает количество удаленных ссылок
+func (r *InMemoryShortURLRepository) PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	purgedCount := 0
+	for userID, shortURLEntities := range r.storageByUserID {
+		keptShortURLEntities := make([]*entity.ShortURLEntity, 0, len(shortURLEntities))
+		for _, shortURLEntity := range shortURLEntities {
+			if shortURLEntity.Deleted && deletedAt(*shortURLEntity).Before(deletedBefore) {
+				delete(r.storage, shortURLEntity.ShortURI)
+				delete(r.splitClicks, shortURLEntity.ShortURI)
+				delete(r.countryClicks, shortURLEntity.ShortURI)
+				purgedCount++
+				continue
+			}
+
+			keptShortURLEntities = append(keptShortURLEntities, shortURLEntity)
+		}
+
+		if len(keptShortURLEntities) == 0 {
+			delete(r.storageByUserID, userID)
+		} else {
+			r.storageByUserID[userID] = keptShortURLEntities
 		}
 	}
 
+	return purgedCount, nil
+}
+
+func deletedAt(shortURLEntity entity.ShortURLEntity) time.Time {
+	if shortURLEntity.DeletedAt == nil {
+		return time.Time{}
+	}
+
+	return *shortURLEntity.DeletedAt
+}
+
+// SaveDeleteJob сохраняет задачу на удаление коротких ссылок
+func (r *InMemoryShortURLRepository) SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deleteJobEntityCopy := *deleteJobEntity
+	r.deleteJobs[deleteJobEntity.ID] = &deleteJobEntityCopy
+
+	return nil
+}
+
+// GetDeleteJobByID возвращает задачу на удаление коротких ссылок по ее идентификатору
+func (r *InMemoryShortURLRepository) GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	deleteJobEntity := r.deleteJobs[id]
+	if deleteJobEntity == nil {
+		return entity.DeleteJobEntity{}, ErrNotFound
+	}
+
+	return *deleteJobEntity, nil
+}
+
+// GetDeleteJobsByStatus возвращает список задач на удаление коротких ссылок в статусе status
+func (r *InMemoryShortURLRepository) GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]entity.DeleteJobEntity, 0)
+	for _, deleteJobEntity := range r.deleteJobs {
+		if deleteJobEntity.Status == status {
+			result = append(result, *deleteJobEntity)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// IncrementSplitClicks увеличивает на единицу количество переходов по короткой ссылке shortURI на вариант variant
+func (r *InMemoryShortURLRepository) IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error {
+	r.incrementSplitClicks(shortURI, variant)
+
+	return nil
+}
+
+func (r *InMemoryShortURLRepository) incrementSplitClicks(shortURI string, variant string) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return incrementClicks(r.splitClicks, shortURI, variant)
+}
+
+// IncrementCountryClicks увеличивает на единицу количество переходов по короткой ссылке shortURI из страны country
+func (r *InMemoryShortURLRepository) IncrementCountryClicks(ctx context.Context, shortURI string, country string) error {
+	r.incrementCountryClicks(shortURI, country)
+
+	return nil
+}
+
+func (r *InMemoryShortURLRepository) incrementCountryClicks(shortURI string, country string) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return incrementClicks(r.countryClicks, shortURI, country)
+}
+
+// copyClicks возвращает копию счетчиков переходов clicks, для nil - пустые счетчики
+func copyClicks(clicks map[string]int64) map[string]int64 {
+	result := make(map[string]int64, len(clicks))
+	maps.Copy(result, clicks)
+
+	return result
+}
+
+// incrementClicks увеличивает на единицу счетчик key короткой ссылки shortURI в clicksByShortURI
+// и возвращает его новое значение
+func incrementClicks(clicksByShortURI map[string]map[string]int64, shortURI string, key string) int64 {
+	clicks := clicksByShortURI[shortURI]
+	if clicks == nil {
+		clicks = make(map[string]int64)
+		clicksByShortURI[shortURI] = clicks
+	}
+	clicks[key]++
+
+	return clicks[key]
+}
+
+// ConsumeShortURLClick учитывает переход по короткой ссылке shortURI с ограничением количества переходов,
+// для коротких ссылок без ограничения переход не учитывается
+//
+// Возвращает ErrClicksExhausted, если переходы по короткой ссылке исчерпаны, ErrNotFound - если короткая ссылка не найдена.
+func (r *InMemoryShortURLRepository) ConsumeShortURLClick(ctx context.Context, shortURI string) error {
+	_, err := r.consumeShortURLClick(shortURI)
+
+	return err
+}
+
+func (r *InMemoryShortURLRepository) consumeShortURLClick(shortURI string) (entity.ShortURLEntity, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	storedShortURLEntity := r.storage[shortURI]
+	if storedShortURLEntity == nil {
+		return entity.ShortURLEntity{}, ErrNotFound
+	}
+
+	if storedShortURLEntity.MaxClicks == 0 {
+		return *storedShortURLEntity, nil
+	}
+
+	if storedShortURLEntity.ClickCount >= int64(storedShortURLEntity.MaxClicks) {
+		return entity.ShortURLEntity{}, ErrClicksExhausted
+	}
+	storedShortURLEntity.ClickCount++
+
+	return *storedShortURLEntity, nil
+}
+
+// GetSplitClicks возвращает количество переходов по короткой ссылке shortURI по вариантам перенаправления
+func (r *InMemoryShortURLRepository) GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return copyClicks(r.splitClicks[shortURI]), nil
+}
+
+// GetCountryClicks возвращает количество переходов по короткой ссылке shortURI по странам
+func (r *InMemoryShortURLRepository) GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return copyClicks(r.countryClicks[shortURI]), nil
+}
+
+// GetUserSettings возвращает настройки пользователя userID, для пользователя без сохраненных настроек
+// возвращаются настройки по умолчанию
+func (r *InMemoryShortURLRepository) GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	userSettingsEntity := r.userSettings[userID]
+	if userSettingsEntity == nil {
+		return entity.UserSettingsEntity{UserID: userID}, nil
+	}
+
+	return *userSettingsEntity, nil
+}
+
+// SaveUserSettings сохраняет настройки пользователя
+func (r *InMemoryShortURLRepository) SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.userSettings[userSettingsEntity.UserID] = &userSettingsEntity
+
 	return nil
 }
 
@@ -91,5 +549,8 @@ func (r *InMemoryShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Cont
 // urlCount - количество коротких ссылок в сервисе
 // userCount - количество пользователей в сервисе
 func (r *InMemoryShortURLRepository) GetStats(ctx context.Context) (urlCount int, userCount int, err error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	return len(r.storageByUserID), len(r.storage), nil
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"github.com/vkhrushchev/urlshortener/internal/common"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
@@ -109,6 +111,10 @@ func (suite *InMemoryRepositoryTestSuite) TestSaveShortURL_existed_user() {
 	suite.Equalf(2, len(suite.repository.storageByUserID[suite.testUserIDFirst]), "testShortURL must be saved in storageByUserID")
 }
 
+func (suite *InMemoryRepositoryTestSuite) TestSaveShortURL_short_uri_race() {
+	assertSaveShortURLShortURIRace(suite.T(), suite.repository)
+}
+
 func (suite *InMemoryRepositoryTestSuite) TestSaveShortURLs_success() {
 	testShortURLEntities := []entity.ShortURLEntity{
 		{
@@ -139,6 +145,39 @@ func (suite *InMemoryRepositoryTestSuite) TestSaveShortURLs_success() {
 	suite.Equal(1, len(suite.repository.storageByUserID[suite.testUserIDSecond]), "not expected count of shortURLEntities for testUserIDSecond")
 }
 
+func (suite *InMemoryRepositoryTestSuite) TestSaveShortURLs_conflict() {
+	newShortURL := func(shortURI string) entity.ShortURLEntity {
+		return entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: shortURI, LongURL: "https://mail.ru", UserID: suite.testUserIDFirst}
+	}
+	freeShortURI := util.RandStringRunes(10)
+	testCases := []struct {
+		name  string
+		batch []entity.ShortURLEntity
+	}{
+		{
+			name:  "short uri is taken",
+			batch: []entity.ShortURLEntity{newShortURL(freeShortURI), newShortURL(suite.testShortURLSecond.ShortURI)},
+		},
+		{
+			name:  "short uri is repeated in batch",
+			batch: []entity.ShortURLEntity{newShortURL(freeShortURI), newShortURL(freeShortURI)},
+		},
+	}
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, suite.testUserIDFirst)
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			savedShortURLEntities, err := suite.repository.SaveShortURLs(testCtx, testCase.batch)
+			suite.ErrorIs(err, ErrConflict, "expected ErrConflict, got %v", err)
+			suite.Nil(savedShortURLEntities, "conflicting batch must not return short urls")
+
+			suite.Equal(2, len(suite.repository.storage), "conflicting batch must not be saved")
+			suite.Equal(1, len(suite.repository.storageByUserID[suite.testUserIDFirst]), "conflicting batch must not be saved for user")
+			suite.Equal("https://google.com", suite.repository.storage[suite.testShortURLSecond.ShortURI].LongURL, "saved short url must not be overwritten")
+		})
+	}
+}
+
 func (suite *InMemoryRepositoryTestSuite) TestGetShortURLsByUserID_success() {
 	shortURLEntities, err := suite.repository.GetShortURLsByUserID(context.Background(), suite.testUserIDFirst)
 	if err != nil {
@@ -157,24 +196,393 @@ func (suite *InMemoryRepositoryTestSuite) TestGetShortURLsByUserID_not_existed_u
 	suite.Equal(0, len(shortURLEntities), "not expected count of shortURLEntities")
 }
 
+func (suite *InMemoryRepositoryTestSuite) TestGetShortURLsByQuery() {
+	testUserID := uuid.NewString()
+	testCreatedAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	testShortURLEntities := []entity.ShortURLEntity{
+		{UUID: uuid.NewString(), ShortURI: "a1", LongURL: "https://ya.ru/search", UserID: testUserID, CreatedAt: testCreatedAt},
+		{UUID: uuid.NewString(), ShortURI: "a2", LongURL: "https://mail.ya.ru", UserID: testUserID, CreatedAt: testCreatedAt.Add(time.Hour)},
+		{UUID: uuid.NewString(), ShortURI: "a3", LongURL: "https://notya.ru", UserID: testUserID, CreatedAt: testCreatedAt.Add(2 * time.Hour)},
+		{UUID: uuid.NewString(), ShortURI: "a4", LongURL: "https://google.com", UserID: testUserID, CreatedAt: testCreatedAt.Add(3 * time.Hour), Deleted: true},
+	}
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	_, err := suite.repository.SaveShortURLs(testCtx, testShortURLEntities)
+	if err != nil {
+		suite.Error(err, "unexpected error when save ShortURLEntities")
+	}
+
+	createdTo := testCreatedAt.Add(2 * time.Hour)
+	testCases := []struct {
+		name              string
+		query             entity.ShortURLQueryEntity
+		expectedShortURIs []string
+		expectedTotal     int
+	}{
+		{
+			name:              "all sorted by created_at desc",
+			query:             entity.ShortURLQueryEntity{Status: entity.ShortURLStatusAll, SortBy: entity.ShortURLSortByCreatedAt, SortDesc: true, Limit: 10},
+			expectedShortURIs: []string{"a4", "a3", "a2", "a1"},
+			expectedTotal:     4,
+		},
+		{
+			name:              "active only with limit",
+			query:             entity.ShortURLQueryEntity{Status: entity.ShortURLStatusActive, SortBy: entity.ShortURLSortByCreatedAt, Limit: 2},
+			expectedShortURIs: []string{"a1", "a2"},
+			expectedTotal:     3,
+		},
+		{
+			name:              "deleted only",
+			query:             entity.ShortURLQueryEntity{Status: entity.ShortURLStatusDeleted, SortBy: entity.ShortURLSortByCreatedAt, Limit: 10},
+			expectedShortURIs: []string{"a4"},
+			expectedTotal:     1,
+		},
+		{
+			name:              "domain with subdomains",
+			query:             entity.ShortURLQueryEntity{Domain: "YA.RU", SortBy: entity.ShortURLSortByOriginalURL, Limit: 10},
+			expectedShortURIs: []string{"a2", "a1"},
+			expectedTotal:     2,
+		},
+		{
+			name:              "search and created range",
+			query:             entity.ShortURLQueryEntity{Search: "YA", CreatedFrom: &testCreatedAt, CreatedTo: &createdTo, SortBy: entity.ShortURLSortByShortURL, SortDesc: true, Limit: 10},
+			expectedShortURIs: []string{"a3", "a2", "a1"},
+			expectedTotal:     3,
+		},
+		{
+			name: "after cursor",
+			query: entity.ShortURLQueryEntity{
+				SortBy: entity.ShortURLSortByCreatedAt,
+				After: &entity.ShortURLCursorEntity{
+					SortValue: testShortURLEntities[1].CreatedAt.Format(time.RFC3339Nano),
+					UUID:      testShortURLEntities[1].UUID,
+				},
+				Limit: 10,
+			},
+			expectedShortURIs: []string{"a3", "a4"},
+			expectedTotal:     4,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.query.UserID = testUserID
+			shortURLEntities, totalCount, err := suite.repository.GetShortURLsByQuery(testCtx, tc.query)
+			if err != nil {
+				suite.Error(err, "unexpected error when get ShortURLEntities by query")
+			}
+
+			shortURIs := make([]string, 0, len(shortURLEntities))
+			for _, shortURLEntity := range shortURLEntities {
+				shortURIs = append(shortURIs, shortURLEntity.ShortURI)
+			}
+
+			suite.Equal(tc.expectedShortURIs, shortURIs)
+			suite.Equal(tc.expectedTotal, totalCount)
+		})
+	}
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestUpdateShortURL_success() {
+	testShortURL := suite.testShortURLFirst
+	testShortURL.Title = "Яндекс"
+	testShortURL.Notes = "поисковик"
+	testShortURL.Tags = []string{"search"}
+	testShortURL.RedirectStatus = http.StatusFound
+	testShortURL.QueryPassthrough = "append"
+	testShortURL.PasswordHash = "$2a$10$hash"
+	testNotAfter := time.Now().Add(time.Hour)
+	testShortURL.NotAfter = &testNotAfter
+	testShortURL.Disabled = true
+	testShortURL.FallbackURL = "https://example.com/gone"
+
+	updatedShortURL, err := suite.repository.UpdateShortURL(context.Background(), testShortURL)
+	if err != nil {
+		suite.Error(err, "unexpected error when update shortURL")
+	}
+
+	suite.Equal(testShortURL, updatedShortURL)
+	suite.Equal([]string{"search"}, suite.testShortURLFirst.Tags, "tags must be saved in storage")
+	suite.Equal(http.StatusFound, suite.testShortURLFirst.RedirectStatus, "redirect status must be saved in storage")
+	suite.Equal("append", suite.testShortURLFirst.QueryPassthrough, "query passthrough must be saved in storage")
+	suite.Equal("$2a$10$hash", suite.testShortURLFirst.PasswordHash, "password hash must be saved in storage")
+	suite.Equal(&testNotAfter, suite.testShortURLFirst.NotAfter, "not after must be saved in storage")
+	suite.True(suite.testShortURLFirst.Disabled, "disabled flag must be saved in storage")
+	suite.Equal("https://example.com/gone", suite.testShortURLFirst.FallbackURL, "fallback url must be saved in storage")
+
+	shortURLEntities, totalCount, err := suite.repository.GetShortURLsByQuery(
+		context.Background(),
+		entity.ShortURLQueryEntity{UserID: suite.testUserIDFirst, Tag: "search", Limit: 10},
+	)
+	if err != nil {
+		suite.Error(err, "unexpected error when get shortURLs by query")
+	}
+	suite.Equal(1, totalCount, "shortURL must be found by tag")
+	suite.Equal(suite.testShortURLFirst.ShortURI, shortURLEntities[0].ShortURI)
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestUpdateShortURL_not_expected_user() {
+	testShortURL := suite.testShortURLFirst
+	testShortURL.UserID = suite.testUserIDSecond
+	testShortURL.Title = "Яндекс"
+
+	_, err := suite.repository.UpdateShortURL(context.Background(), testShortURL)
+
+	suite.ErrorIs(err, ErrNotFound)
+	suite.Equal("", suite.testShortURLFirst.Title, "title must not be changed")
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestUpdateShortURLPreview() {
+	testFetchedAt := time.Now()
+	err := suite.repository.UpdateShortURLPreview(
+		context.Background(),
+		suite.testShortURLFirst.ShortURI,
+		entity.ShortURLPreviewEntity{Title: "Яндекс", ImageURL: "https://ya.ru/logo.png", FetchedAt: testFetchedAt},
+	)
+	if err != nil {
+		suite.Error(err, "unexpected error when update shortURL preview")
+	}
+
+	suite.Equal("Яндекс", suite.testShortURLFirst.PreviewTitle)
+	suite.Equal("https://ya.ru/logo.png", suite.testShortURLFirst.PreviewImageURL)
+	suite.Equal(&testFetchedAt, suite.testShortURLFirst.PreviewFetchedAt)
+
+	err = suite.repository.UpdateShortURLPreview(context.Background(), "not_existed_shortURL", entity.ShortURLPreviewEntity{})
+	suite.ErrorIs(err, ErrNotFound)
+}
+
 func (suite *InMemoryRepositoryTestSuite) TestDeleteShortURLsByShortURIs_success() {
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, suite.testUserIDFirst)
-	err := suite.repository.DeleteShortURLsByShortURIs(testCtx, []string{suite.testShortURLFirst.ShortURI})
+	deleteShortURLResultEntities, err := suite.repository.DeleteShortURLsByShortURIs(testCtx, []string{suite.testShortURLFirst.ShortURI})
 	if err != nil {
 		suite.Error(err, "unexpected error when delete shortURLs by shortURIs")
 	}
 
 	suite.Equal(true, suite.testShortURLFirst.Deleted, "testShortURLFirst must be deleted")
+	suite.Equal(
+		[]entity.DeleteShortURLResultEntity{
+			{ShortURI: suite.testShortURLFirst.ShortURI, Status: entity.DeleteStatusDeleted},
+		},
+		deleteShortURLResultEntities,
+	)
 }
 
 func (suite *InMemoryRepositoryTestSuite) TestDeleteShortURLsByShortURIs_not_expected_user() {
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, suite.testUserIDSecond)
-	err := suite.repository.DeleteShortURLsByShortURIs(testCtx, []string{suite.testShortURLFirst.ShortURI})
+	deleteShortURLResultEntities, err := suite.repository.DeleteShortURLsByShortURIs(
+		testCtx,
+		[]string{suite.testShortURLFirst.ShortURI, "not_existed_shortURL"},
+	)
 	if err != nil {
 		suite.Error(err, "unexpected error when delete shortURLs by shortURIs")
 	}
 
-	suite.Equal(false, suite.testShortURLFirst.Deleted, "testShortURLFirst must be deleted")
+	suite.Equal(false, suite.testShortURLFirst.Deleted, "testShortURLFirst must not be deleted")
+	suite.Equal(
+		[]entity.DeleteShortURLResultEntity{
+			{ShortURI: suite.testShortURLFirst.ShortURI, Status: entity.DeleteStatusNotOwner},
+			{ShortURI: "not_existed_shortURL", Status: entity.DeleteStatusNotFound},
+		},
+		deleteShortURLResultEntities,
+	)
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestRestoreShortURLsByShortURIs_success() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, suite.testUserIDFirst)
+	_, err := suite.repository.DeleteShortURLsByShortURIs(testCtx, []string{suite.testShortURLFirst.ShortURI})
+	if err != nil {
+		suite.Error(err, "unexpected error when delete shortURLs by shortURIs")
+	}
+
+	deletedShortURLEntities, err := suite.repository.GetDeletedShortURLsByUserID(testCtx, suite.testUserIDFirst)
+	if err != nil {
+		suite.Error(err, "unexpected error when get deleted shortURLs by userID")
+	}
+	suite.Equal(1, len(deletedShortURLEntities), "not expected count of deleted shortURLEntities")
+	suite.NotNil(deletedShortURLEntities[0].DeletedAt, "deletedAt must be set")
+
+	restoreShortURLResultEntities, err := suite.repository.RestoreShortURLsByShortURIs(
+		testCtx,
+		[]string{suite.testShortURLFirst.ShortURI, suite.testShortURLSecond.ShortURI, "not_existed_shortURL"},
+	)
+	if err != nil {
+		suite.Error(err, "unexpected error when restore shortURLs by shortURIs")
+	}
+
+	suite.Equal(false, suite.testShortURLFirst.Deleted, "testShortURLFirst must be restored")
+	suite.Nil(suite.testShortURLFirst.DeletedAt, "deletedAt must be reset")
+	suite.Equal(
+		[]entity.RestoreShortURLResultEntity{
+			{ShortURI: suite.testShortURLFirst.ShortURI, Status: entity.RestoreStatusRestored},
+			{ShortURI: suite.testShortURLSecond.ShortURI, Status: entity.RestoreStatusNotOwner},
+			{ShortURI: "not_existed_shortURL", Status: entity.RestoreStatusNotFound},
+		},
+		restoreShortURLResultEntities,
+	)
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestRestoreShortURLsByShortURIs_not_deleted() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, suite.testUserIDFirst)
+	restoreShortURLResultEntities, err := suite.repository.RestoreShortURLsByShortURIs(testCtx, []string{suite.testShortURLFirst.ShortURI})
+	if err != nil {
+		suite.Error(err, "unexpected error when restore shortURLs by shortURIs")
+	}
+
+	suite.Equal(
+		[]entity.RestoreShortURLResultEntity{
+			{ShortURI: suite.testShortURLFirst.ShortURI, Status: entity.RestoreStatusNotDeleted},
+		},
+		restoreShortURLResultEntities,
+	)
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestPurgeDeletedShortURLs_success() {
+	testDeletedAt := time.Now().Add(-time.Hour)
+	suite.testShortURLFirst.Deleted = true
+	suite.testShortURLFirst.DeletedAt = &testDeletedAt
+
+	purgedCount, err := suite.repository.PurgeDeletedShortURLs(context.Background(), time.Now().Add(-2*time.Hour))
+	if err != nil {
+		suite.Error(err, "unexpected error when purge deleted shortURLs")
+	}
+	suite.Equal(0, purgedCount, "shortURL deleted after deletedBefore must not be purged")
+
+	purgedCount, err = suite.repository.PurgeDeletedShortURLs(context.Background(), time.Now())
+	if err != nil {
+		suite.Error(err, "unexpected error when purge deleted shortURLs")
+	}
+	suite.Equal(1, purgedCount, "not expected count of purged shortURLs")
+	suite.Nil(suite.repository.storage[suite.testShortURLFirst.ShortURI], "testShortURLFirst must be purged")
+	suite.Nil(suite.repository.storageByUserID[suite.testUserIDFirst], "testUserIDFirst must not have shortURLs")
+	suite.NotNil(suite.repository.storage[suite.testShortURLSecond.ShortURI], "testShortURLSecond must not be purged")
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestSaveDeleteJob_success() {
+	testDeleteJob := &entity.DeleteJobEntity{
+		ID:        uuid.NewString(),
+		UserID:    suite.testUserIDFirst,
+		Status:    entity.DeleteJobStatusPending,
+		ShortURIs: []string{suite.testShortURLFirst.ShortURI},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := suite.repository.SaveDeleteJob(context.Background(), testDeleteJob)
+	if err != nil {
+		suite.Error(err, "unexpected error when save delete job")
+	}
+
+	savedDeleteJob, err := suite.repository.GetDeleteJobByID(context.Background(), testDeleteJob.ID)
+	if err != nil {
+		suite.Error(err, "unexpected error when get delete job by id")
+	}
+	suite.Equal(*testDeleteJob, savedDeleteJob)
+
+	pendingDeleteJobs, err := suite.repository.GetDeleteJobsByStatus(context.Background(), entity.DeleteJobStatusPending)
+	if err != nil {
+		suite.Error(err, "unexpected error when get delete jobs by status")
+	}
+	suite.Equal(1, len(pendingDeleteJobs), "not expected count of pending delete jobs")
+
+	doneDeleteJobs, err := suite.repository.GetDeleteJobsByStatus(context.Background(), entity.DeleteJobStatusDone)
+	if err != nil {
+		suite.Error(err, "unexpected error when get delete jobs by status")
+	}
+	suite.Equal(0, len(doneDeleteJobs), "not expected count of done delete jobs")
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestGetDeleteJobByID_not_found() {
+	_, err := suite.repository.GetDeleteJobByID(context.Background(), uuid.NewString())
+	suite.ErrorIs(err, ErrNotFound, "expected ErrNotFound, got %v", err)
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestIncrementSplitClicks_success() {
+	for _, variant := range []string{"a", "b", "a"} {
+		err := suite.repository.IncrementSplitClicks(context.Background(), suite.testShortURLFirst.ShortURI, variant)
+		if err != nil {
+			suite.Fail("unexpected error when increment split clicks: %v", err)
+		}
+	}
+
+	splitClicks, err := suite.repository.GetSplitClicks(context.Background(), suite.testShortURLFirst.ShortURI)
+	if err != nil {
+		suite.Fail("unexpected error when get split clicks: %v", err)
+	}
+	suite.Equal(map[string]int64{"a": 2, "b": 1}, splitClicks)
+
+	splitClicks, err = suite.repository.GetSplitClicks(context.Background(), suite.testShortURLSecond.ShortURI)
+	if err != nil {
+		suite.Fail("unexpected error when get split clicks: %v", err)
+	}
+	suite.Empty(splitClicks, "testShortURLSecond must not have split clicks")
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestIncrementCountryClicks_success() {
+	for _, country := range []string{"SE", "GB", "SE"} {
+		err := suite.repository.IncrementCountryClicks(context.Background(), suite.testShortURLFirst.ShortURI, country)
+		if err != nil {
+			suite.Fail("unexpected error when increment country clicks: %v", err)
+		}
+	}
+
+	countryClicks, err := suite.repository.GetCountryClicks(context.Background(), suite.testShortURLFirst.ShortURI)
+	if err != nil {
+		suite.Fail("unexpected error when get country clicks: %v", err)
+	}
+	suite.Equal(map[string]int64{"SE": 2, "GB": 1}, countryClicks)
+
+	countryClicks, err = suite.repository.GetCountryClicks(context.Background(), suite.testShortURLSecond.ShortURI)
+	if err != nil {
+		suite.Fail("unexpected error when get country clicks: %v", err)
+	}
+	suite.Empty(countryClicks, "testShortURLSecond must not have country clicks")
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestConsumeShortURLClick_concurrent() {
+	suite.testShortURLFirst.MaxClicks = 10
+
+	consumed, exhausted, err := consumeShortURLClickConcurrently(suite.repository, suite.testShortURLFirst.ShortURI, 50)
+	if err != nil {
+		suite.Fail("unexpected error when consume short url click: %v", err)
+	}
+	suite.Equal(int64(10), consumed, "only MaxClicks clicks must be consumed")
+	suite.Equal(int64(40), exhausted)
+	suite.Equal(int64(10), suite.testShortURLFirst.ClickCount)
+
+	err = suite.repository.ConsumeShortURLClick(context.Background(), suite.testShortURLSecond.ShortURI)
+	suite.NoError(err, "click on short url without limit must be allowed")
+	suite.Zero(suite.testShortURLSecond.ClickCount, "click on short url without limit must not be counted")
+
+	err = suite.repository.ConsumeShortURLClick(context.Background(), "not_existed")
+	suite.ErrorIs(err, ErrNotFound)
+}
+
+func (suite *InMemoryRepositoryTestSuite) TestSaveUserSettings_success() {
+	userSettings, err := suite.repository.GetUserSettings(context.Background(), suite.testUserIDFirst)
+	if err != nil {
+		suite.Fail("unexpected error when get user settings: %v", err)
+	}
+	suite.Equal(entity.UserSettingsEntity{UserID: suite.testUserIDFirst}, userSettings, "default settings must be returned")
+
+	err = suite.repository.SaveUserSettings(
+		context.Background(),
+		entity.UserSettingsEntity{UserID: suite.testUserIDFirst, FallbackURL: "https://example.com/gone"},
+	)
+	if err != nil {
+		suite.Fail("unexpected error when save user settings: %v", err)
+	}
+
+	userSettings, err = suite.repository.GetUserSettings(context.Background(), suite.testUserIDFirst)
+	if err != nil {
+		suite.Fail("unexpected error when get user settings: %v", err)
+	}
+	suite.Equal("https://example.com/gone", userSettings.FallbackURL)
+
+	userSettings, err = suite.repository.GetUserSettings(context.Background(), suite.testUserIDSecond)
+	if err != nil {
+		suite.Fail("unexpected error when get user settings: %v", err)
+	}
+	suite.Empty(userSettings.FallbackURL, "settings of another user must not be changed")
 }
 
 func (suite *InMemoryRepositoryTestSuite) TestGetStats() {
@@ -187,6 +595,20 @@ func (suite *InMemoryRepositoryTestSuite) TestGetStats() {
 	suite.Equal(2, userCount, "userCount should be 2")
 }
 
+func (suite *InMemoryRepositoryTestSuite) TestForEachShortURL() {
+	suite.testShortURLFirst.CreatedAt = time.Now()
+	suite.testShortURLSecond.CreatedAt = suite.testShortURLFirst.CreatedAt.Add(-time.Second)
+
+	visitedShortURIs := make([]string, 0)
+	err := suite.repository.ForEachShortURL(context.Background(), func(shortURLEntity entity.ShortURLEntity) error {
+		visitedShortURIs = append(visitedShortURIs, shortURLEntity.ShortURI)
+		return nil
+	})
+
+	suite.Require().NoError(err)
+	suite.Equal([]string{"cde", "abc"}, visitedShortURIs, "short urls must be visited in order of creation")
+}
+
 func TestInMemoryRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(InMemoryRepositoryTestSuite))
 }
@@ -7,18 +7,467 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/vkhrushchev/urlshortener/internal/app/entity"
 )
 
 // JSONFileShortURLRepository реализует интерфейс IShortURLRepository для хранения коротких ссылок в json-файле
+//
+// Файл хранится в формате "одна json-строка на сущность": при изменении короткой ссылки в конец файла
+// дописывается ее актуальное состояние, при чтении файла более поздняя строка перекрывает более раннюю.
+// Задачи на удаление коротких ссылок хранятся аналогичным образом в файле с суффиксом ".jobs",
+// количество переходов по вариантам перенаправления - в файле с суффиксом ".clicks",
+// количество переходов по странам - в файле с суффиксом ".countries",
+// настройки пользователей - в файле с суффиксом ".users".
 type JSONFileShortURLRepository struct {
-	InMemoryShortURLRepository
-	path string
+	*InMemoryShortURLRepository
+	fileMutex         sync.Mutex
+	path              string
+	deleteJobsPath    string
+	splitClicksPath   string
+	countryClicksPath string
+	userSettingsPath  string
 }
 
 // NewJSONFileShortURLRepository создает экземпляр структуры JSONFileShortURLRepository
 func NewJSONFileShortURLRepository(path string) (*JSONFileShortURLRepository, error) {
+	jsonFileShortURLRepository := &JSONFileShortURLRepository{
+		InMemoryShortURLRepository: NewInMemoryShortURLRepository(),
+		path:                       path,
+		deleteJobsPath:             path + ".jobs",
+		splitClicksPath:            path + ".clicks",
+		countryClicksPath:          path + ".countries",
+		userSettingsPath:           path + ".users",
+	}
+
+	// считываем json-строки из файла path
+	loadedAt := time.Now()
+	shortURIs := make([]string, 0)
+	err := readJSONLines(path, func(line []byte) error {
+		var shortURLEntity entity.ShortURLEntity
+		if err := json.Unmarshal(line, &shortURLEntity); err != nil {
+			return err
+		}
+
+		if _, ok := jsonFileShortURLRepository.storage[shortURLEntity.ShortURI]; !ok {
+			shortURIs = append(shortURIs, shortURLEntity.ShortURI)
+		}
+		// для ссылок, удаленных до появления поля deleted_at, срок хранения в корзине отсчитывается с момента запуска
+		if shortURLEntity.Deleted && shortURLEntity.DeletedAt == nil {
+			shortURLEntity.DeletedAt = &loadedAt
+		}
+		jsonFileShortURLRepository.storage[shortURLEntity.ShortURI] = &shortURLEntity
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, shortURI := range shortURIs {
+		shortURLEntity := jsonFileShortURLRepository.storage[shortURI]
+		jsonFileShortURLRepository.storageByUserID[shortURLEntity.UserID] = append(
+			jsonFileShortURLRepository.storageByUserID[shortURLEntity.UserID],
+			shortURLEntity,
+		)
+	}
+
+	// считываем json-строки из файла deleteJobsPath
+	err = readJSONLines(jsonFileShortURLRepository.deleteJobsPath, func(line []byte) error {
+		var deleteJobEntity entity.DeleteJobEntity
+		if err := json.Unmarshal(line, &deleteJobEntity); err != nil {
+			return err
+		}
+
+		jsonFileShortURLRepository.deleteJobs[deleteJobEntity.ID] = &deleteJobEntity
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// считываем json-строки из файла splitClicksPath
+	err = readJSONLines(jsonFileShortURLRepository.splitClicksPath, func(line []byte) error {
+		var splitClicksEntity entity.SplitClicksEntity
+		if err := json.Unmarshal(line, &splitClicksEntity); err != nil {
+			return err
+		}
+
+		clicks := jsonFileShortURLRepository.splitClicks[splitClicksEntity.ShortURI]
+		if clicks == nil {
+			clicks = make(map[string]int64)
+			jsonFileShortURLRepository.splitClicks[splitClicksEntity.ShortURI] = clicks
+		}
+		clicks[splitClicksEntity.Variant] = splitClicksEntity.Clicks
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// считываем json-строки из файла countryClicksPath
+	err = readJSONLines(jsonFileShortURLRepository.countryClicksPath, func(line []byte) error {
+		var countryClicksEntity entity.CountryClicksEntity
+		if err := json.Unmarshal(line, &countryClicksEntity); err != nil {
+			return err
+		}
+
+		clicks := jsonFileShortURLRepository.countryClicks[countryClicksEntity.ShortURI]
+		if clicks == nil {
+			clicks = make(map[string]int64)
+			jsonFileShortURLRepository.countryClicks[countryClicksEntity.ShortURI] = clicks
+		}
+		clicks[countryClicksEntity.Country] = countryClicksEntity.Clicks
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// считываем json-строки из файла userSettingsPath
+	err = readJSONLines(jsonFileShortURLRepository.userSettingsPath, func(line []byte) error {
+		var userSettingsEntity entity.UserSettingsEntity
+		if err := json.Unmarshal(line, &userSettingsEntity); err != nil {
+			return err
+		}
+
+		jsonFileShortURLRepository.userSettings[userSettingsEntity.UserID] = &userSettingsEntity
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonFileShortURLRepository, nil
+}
+
+// SaveShortURL сохраняет короткую ссылку
+func (r *JSONFileShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+	shortURLEntity, err := r.InMemoryShortURLRepository.SaveShortURL(ctx, shortURLEntity)
+	if err != nil && errors.Is(err, ErrConflict) {
+		return nil, err
+	} else if err != nil {
+		log.Errorw("repository: error when save short url", "err", err)
+		return nil, err
+	}
+
+	if err := r.appendJSONLines(r.path, shortURLEntity); err != nil {
+		return nil, err
+	}
+
+	return shortURLEntity, nil
+}
+
+// SaveShortURLs сохраняет короткие ссылки пачкой
+func (r *JSONFileShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+	shortURLEntities, err := r.InMemoryShortURLRepository.SaveShortURLs(ctx, shortURLEntities)
+	if err != nil && errors.Is(err, ErrConflict) {
+		return nil, err
+	} else if err != nil {
+		log.Errorw("repository: error when save short urls", "err", err)
+		return nil, err
+	}
+
+	values := make([]any, 0, len(shortURLEntities))
+	for _, shortURLEntity := range shortURLEntities {
+		values = append(values, shortURLEntity)
+	}
+
+	if err := r.appendJSONLines(r.path, values...); err != nil {
+		return nil, err
+	}
+
+	return shortURLEntities, nil
+}
+
+// UpdateShortURL обновляет изменяемые поля короткой ссылки пользователя: название, описание, заметки, теги
+// и параметры перенаправления
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена или принадлежит другому пользователю.
+func (r *JSONFileShortURLRepository) UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error) {
+	// изменение и запись выполняются под одной блокировкой, чтобы последней в файле оказалась актуальная версия
+	// короткой ссылки при одновременном учете переходов
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	updatedShortURLEntity, err := r.InMemoryShortURLRepository.UpdateShortURL(ctx, shortURLEntity)
+	if err != nil {
+		return entity.ShortURLEntity{}, err
+	}
+
+	if err := appendJSONLines(r.path, updatedShortURLEntity); err != nil {
+		return entity.ShortURLEntity{}, err
+	}
+
+	return updatedShortURLEntity, nil
+}
+
+// UpdateShortURLPreview сохраняет превью страницы исходного URL короткой ссылки
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена.
+func (r *JSONFileShortURLRepository) UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	updatedShortURLEntity, err := r.InMemoryShortURLRepository.updateShortURLPreview(shortURI, preview)
+	if err != nil {
+		return err
+	}
+
+	return appendJSONLines(r.path, updatedShortURLEntity)
+}
+
+// DeleteShortURLsByShortURIs удаляет короткие ссылки пользователя по списку shortURI
+// и возвращает результат удаления по каждой из них
+func (r *JSONFileShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error) {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	deleteShortURLResultEntities, err := r.InMemoryShortURLRepository.DeleteShortURLsByShortURIs(ctx, shortURIs)
+	if err != nil {
+		log.Errorw("repository: error when delete short urls", "err", err)
+		return nil, err
+	}
+
+	shortURIsToSave := make([]string, 0, len(deleteShortURLResultEntities))
+	for _, deleteShortURLResultEntity := range deleteShortURLResultEntities {
+		if deleteShortURLResultEntity.Status == entity.DeleteStatusDeleted {
+			shortURIsToSave = append(shortURIsToSave, deleteShortURLResultEntity.ShortURI)
+		}
+	}
+
+	if err := r.appendShortURLs(ctx, shortURIsToSave); err != nil {
+		return nil, err
+	}
+
+	return deleteShortURLResultEntities, nil
+}
+
+// RestoreShortURLsByShortURIs восстанавливает удаленные короткие ссылки пользователя по списку shortURI
+// и возвращает результат восстановления по каждой из них
+func (r *JSONFileShortURLRepository) RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error) {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	restoreShortURLResultEntities, err := r.InMemoryShortURLRepository.RestoreShortURLsByShortURIs(ctx, shortURIs)
+	if err != nil {
+		log.Errorw("repository: error when restore short urls", "err", err)
+		return nil, err
+	}
+
+	shortURIsToSave := make([]string, 0, len(restoreShortURLResultEntities))
+	for _, restoreShortURLResultEntity := range restoreShortURLResultEntities {
+		if restoreShortURLResultEntity.Status == entity.RestoreStatusRestored {
+			shortURIsToSave = append(shortURIsToSave, restoreShortURLResultEntity.ShortURI)
+		}
+	}
+
+	if err := r.appendShortURLs(ctx, shortURIsToSave); err != nil {
+		return nil, err
+	}
+
+	return restoreShortURLResultEntities, nil
+}
+
+// PurgeDeletedShortURLs окончательно удаляет короткие ссылки, удаленные раньше deletedBefore,
+// и возвращает количество удаленных ссылок. После удаления файл перезаписывается без удаленных ссылок
+func (r *JSONFileShortURLRepository) PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	purgedCount, err := r.InMemoryShortURLRepository.PurgeDeletedShortURLs(ctx, deletedBefore)
+	if err != nil {
+		log.Errorw("repository: error when purge short urls", "err", err)
+		return 0, err
+	}
+
+	if purgedCount == 0 {
+		return 0, nil
+	}
+
+	if err := r.compact(); err != nil {
+		return 0, err
+	}
+
+	return purgedCount, nil
+}
+
+// appendShortURLs дописывает в файл актуальное состояние коротких ссылок по списку shortURIs,
+// вызывается под блокировкой fileMutex
+func (r *JSONFileShortURLRepository) appendShortURLs(ctx context.Context, shortURIs []string) error {
+	values := make([]any, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		shortURLEntity, err := r.InMemoryShortURLRepository.GetShortURLByShortURI(ctx, shortURI)
+		if err != nil {
+			return err
+		}
+
+		values = append(values, shortURLEntity)
+	}
+
+	return appendJSONLines(r.path, values...)
+}
+
+// compact перезаписывает файлы текущим состоянием коротких ссылок и количества переходов по вариантам перенаправления
+// и по странам, вызывается под блокировкой fileMutex
+func (r *JSONFileShortURLRepository) compact() error {
+	r.mutex.RLock()
+	userIDs := make([]string, 0, len(r.storageByUserID))
+	for userID := range r.storageByUserID {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	values := make([]any, 0, len(r.storage))
+	for _, userID := range userIDs {
+		for _, shortURLEntity := range r.storageByUserID[userID] {
+			values = append(values, *shortURLEntity)
+		}
+	}
+
+	splitClicksValues := make([]any, 0, len(r.splitClicks))
+	for shortURI, clicks := range r.splitClicks {
+		for variant, variantClicks := range clicks {
+			splitClicksValues = append(splitClicksValues, entity.SplitClicksEntity{ShortURI: shortURI, Variant: variant, Clicks: variantClicks})
+		}
+	}
+
+	countryClicksValues := make([]any, 0, len(r.countryClicks))
+	for shortURI, clicks := range r.countryClicks {
+		for country, countryClicks := range clicks {
+			countryClicksValues = append(countryClicksValues, entity.CountryClicksEntity{ShortURI: shortURI, Country: country, Clicks: countryClicks})
+		}
+	}
+	r.mutex.RUnlock()
+
+	if err := rewriteJSONLines(r.path, values...); err != nil {
+		return err
+	}
+
+	if err := rewriteJSONLines(r.splitClicksPath, splitClicksValues...); err != nil {
+		return err
+	}
+
+	if err := rewriteJSONLines(r.countryClicksPath, countryClicksValues...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// rewriteJSONLines атомарно заменяет содержимое файла path json-строками values
+func rewriteJSONLines(path string, values ...any) error {
+	compactPath := path + ".compact"
+	file, err := os.OpenFile(compactPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		log.Errorw("repository: error when create file", "path", compactPath, "err", err)
+		return ErrUnexpected
+	}
+	if err := file.Close(); err != nil {
+		log.Errorw("repository: error when close file", "path", compactPath, "err", err)
+		return ErrUnexpected
+	}
+
+	if err := appendJSONLines(compactPath, values...); err != nil {
+		return err
+	}
+
+	if err := os.Rename(compactPath, path); err != nil {
+		log.Errorw("repository: error when rename file", "path", compactPath, "err", err)
+		return ErrUnexpected
+	}
+
+	log.Infow("repository: file compacted", "path", path, "count", len(values))
+
+	return nil
+}
+
+// SaveDeleteJob сохраняет задачу на удаление коротких ссылок
+func (r *JSONFileShortURLRepository) SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error {
+	if err := r.InMemoryShortURLRepository.SaveDeleteJob(ctx, deleteJobEntity); err != nil {
+		log.Errorw("repository: error when save delete job", "err", err)
+		return err
+	}
+
+	return r.appendJSONLines(r.deleteJobsPath, deleteJobEntity)
+}
+
+// IncrementSplitClicks увеличивает на единицу количество переходов по короткой ссылке shortURI на вариант variant
+// и дописывает новое значение в файл
+func (r *JSONFileShortURLRepository) IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error {
+	// значения дописываются в порядке увеличения, чтобы при чтении файла последним оказалось наибольшее
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	clicks := r.InMemoryShortURLRepository.incrementSplitClicks(shortURI, variant)
+
+	return appendJSONLines(r.splitClicksPath, entity.SplitClicksEntity{ShortURI: shortURI, Variant: variant, Clicks: clicks})
+}
+
+// IncrementCountryClicks увеличивает на единицу количество переходов по короткой ссылке shortURI из страны country
+// и дописывает новое значение в файл
+func (r *JSONFileShortURLRepository) IncrementCountryClicks(ctx context.Context, shortURI string, country string) error {
+	// значения дописываются в порядке увеличения, чтобы при чтении файла последним оказалось наибольшее
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	clicks := r.InMemoryShortURLRepository.incrementCountryClicks(shortURI, country)
+
+	return appendJSONLines(r.countryClicksPath, entity.CountryClicksEntity{ShortURI: shortURI, Country: country, Clicks: clicks})
+}
+
+// ConsumeShortURLClick учитывает переход по короткой ссылке shortURI с ограничением количества переходов
+// и дописывает новое состояние короткой ссылки в файл
+//
+// Возвращает ErrClicksExhausted, если переходы по короткой ссылке исчерпаны, ErrNotFound - если короткая ссылка не найдена.
+func (r *JSONFileShortURLRepository) ConsumeShortURLClick(ctx context.Context, shortURI string) error {
+	// учет перехода и запись выполняются под одной блокировкой, чтобы при чтении файла последним оказалось
+	// наибольшее количество переходов
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	shortURLEntity, err := r.InMemoryShortURLRepository.consumeShortURLClick(shortURI)
+	if err != nil {
+		return err
+	}
+
+	if shortURLEntity.MaxClicks == 0 {
+		return nil
+	}
+
+	return appendJSONLines(r.path, shortURLEntity)
+}
+
+// SaveUserSettings сохраняет настройки пользователя и дописывает их в файл
+func (r *JSONFileShortURLRepository) SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	if err := r.InMemoryShortURLRepository.SaveUserSettings(ctx, userSettingsEntity); err != nil {
+		log.Errorw("repository: error when save user settings", "err", err)
+		return err
+	}
+
+	return appendJSONLines(r.userSettingsPath, userSettingsEntity)
+}
+
+// appendJSONLines дописывает values в конец файла path под блокировкой fileMutex
+func (r *JSONFileShortURLRepository) appendJSONLines(path string, values ...any) error {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	return appendJSONLines(path, values...)
+}
+
+// readJSONLines вызывает handler для каждой строки файла path, отсутствующий файл создается
+func readJSONLines(path string, handler func(line []byte) error) error {
 	var file *os.File
 	var fileInfo os.FileInfo
 	var err error
@@ -26,18 +475,15 @@ func NewJSONFileShortURLRepository(path string) (*JSONFileShortURLRepository, er
 	if fileInfo, err = os.Stat(path); errors.Is(err, os.ErrNotExist) {
 		file, err = os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0644)
 		if err != nil {
-			err = fmt.Errorf("repository: error when create and open file: %v", err)
-			return nil, err
+			return fmt.Errorf("repository: error when create and open file: %v", err)
 		}
 	} else {
 		if fileInfo.IsDir() {
-			err = fmt.Errorf("repository: path[%s] is dir", path)
-			return nil, err
+			return fmt.Errorf("repository: path[%s] is dir", path)
 		}
 		file, err = os.OpenFile(path, os.O_RDONLY, 0644)
 		if err != nil {
-			err = fmt.Errorf("repository: error when open file: %v", err)
-			return nil, err
+			return fmt.Errorf("repository: error when open file: %v", err)
 		}
 	}
 
@@ -47,40 +493,27 @@ func NewJSONFileShortURLRepository(path string) (*JSONFileShortURLRepository, er
 		}
 	}(file)
 
-	jsonFileShortURLRepository := &JSONFileShortURLRepository{
-		InMemoryShortURLRepository: *NewInMemoryShortURLRepository(),
-		path:                       path,
-	}
-
-	// считываем json-строки из файла path
 	fileScanner := bufio.NewScanner(file)
 	fileScanner.Split(bufio.ScanLines)
 	for fileScanner.Scan() {
-		var shortURLEntity entity.ShortURLEntity
-		err = json.Unmarshal(fileScanner.Bytes(), &shortURLEntity)
-		if err != nil {
-			err = fmt.Errorf("repository: error when read json from file[%s]: %v", path, err)
-			return nil, err
+		if err := handler(fileScanner.Bytes()); err != nil {
+			return fmt.Errorf("repository: error when read json from file[%s]: %v", path, err)
 		}
-
-		jsonFileShortURLRepository.storage[shortURLEntity.ShortURI] = &shortURLEntity
 	}
 
-	return jsonFileShortURLRepository, nil
+	return nil
 }
 
-// SaveShortURL сохраняет короткую ссылку
-func (r *JSONFileShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
-	shortURLEntity, err := r.InMemoryShortURLRepository.SaveShortURL(ctx, shortURLEntity)
-	if err != nil {
-		log.Errorw("repository: error when save short url", "err", err)
-		return nil, err
+// appendJSONLines дописывает values в конец файла path в виде json-строк
+func appendJSONLines(path string, values ...any) error {
+	if len(values) == 0 {
+		return nil
 	}
 
-	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND, 0644)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		log.Errorw("repository: error when open file", "path", r.path, "err", err)
-		return nil, ErrUnexpected
+		log.Errorw("repository: error when open file", "path", path, "err", err)
+		return ErrUnexpected
 	}
 
 	defer func(file *os.File) {
@@ -89,30 +522,33 @@ func (r *JSONFileShortURLRepository) SaveShortURL(ctx context.Context, shortURLE
 		}
 	}(file)
 
-	shortURLEntityJSONBytes, err := json.Marshal(shortURLEntity)
-	if err != nil {
-		log.Errorw(
-			"repository: error when marshal shortURLEntity to JSON",
-			"path", r.path,
-			"error", err.Error(),
-		)
-		err = fmt.Errorf("storage: error when marshal storageJSON to JSON: %v", err)
+	jsonLinesBytes := make([]byte, 0)
+	for _, value := range values {
+		valueJSONBytes, err := json.Marshal(value)
+		if err != nil {
+			log.Errorw(
+				"repository: error when marshal value to JSON",
+				"path", path,
+				"error", err.Error(),
+			)
 
-		return nil, err
+			return fmt.Errorf("storage: error when marshal value to JSON: %v", err)
+		}
+
+		jsonLinesBytes = append(jsonLinesBytes, valueJSONBytes...)
+		jsonLinesBytes = append(jsonLinesBytes, '\n')
 	}
 
-	shortURLEntityJSONBytes = append(shortURLEntityJSONBytes, '\n')
-	_, err = file.Write(shortURLEntityJSONBytes)
+	_, err = file.Write(jsonLinesBytes)
 	if err != nil {
 		log.Errorw(
-			"storage: error when write storageJSON to file",
-			"path", r.path,
+			"storage: error when write json lines to file",
+			"path", path,
 			"error", err.Error(),
 		)
-		err = fmt.Errorf("storage: error when write storageJSON to file: %v", err)
 
-		return nil, err
+		return fmt.Errorf("storage: error when write json lines to file: %v", err)
 	}
 
-	return shortURLEntity, nil
+	return nil
 }
@@ -3,12 +3,16 @@ package repository
 import (
 	"context"
 	"github.com/vkhrushchev/urlshortener/internal/common"
+	"net/http"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
 )
 
 const TestDataFile = "json_short_url_test_data.json"
@@ -28,9 +32,11 @@ func (s *JSONFileShortURLRepositoryTestSuite) SetupTest() {
 }
 
 func (s *JSONFileShortURLRepositoryTestSuite) TearDownTest() {
-	err := os.Remove(TestDataFile)
-	if err != nil {
-		s.Fail("repository: unexpected error when remove test data file for JSONFileShortURLRepository: %v", err)
+	for _, path := range []string{TestDataFile, TestDataFile + ".jobs", TestDataFile + ".clicks", TestDataFile + ".countries", TestDataFile + ".users"} {
+		err := os.Remove(path)
+		if err != nil {
+			s.Fail("repository: unexpected error when remove test data file for JSONFileShortURLRepository: %v", err)
+		}
 	}
 }
 
@@ -56,6 +62,284 @@ func (s *JSONFileShortURLRepositoryTestSuite) TestSaveShortURL() {
 	s.Equal(1, len(s.repository.storageByUserID[testUserID]), "testShortURL must be saved in storageByUserID")
 }
 
+func (s *JSONFileShortURLRepositoryTestSuite) TestSaveShortURL_short_uri_race() {
+	assertSaveShortURLShortURIRace(s.T(), s.repository)
+}
+
+func (s *JSONFileShortURLRepositoryTestSuite) TestSaveShortURL_conflict_not_persisted() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	_, err := s.repository.SaveShortURL(testCtx, &entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: "ghi", LongURL: "https://mail.ru", UserID: testUserID})
+	s.Require().NoError(err)
+
+	savedShortURL, err := s.repository.SaveShortURL(testCtx, &entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: "ghi", LongURL: "https://vk.com", UserID: testUserID})
+	s.ErrorIs(err, ErrConflict, "expected ErrConflict, got %v", err)
+	s.Nil(savedShortURL, "short uri conflict must not return other short url")
+
+	reloadedRepository, err := NewJSONFileShortURLRepository(TestDataFile)
+	s.Require().NoError(err)
+
+	shortURLEntity, err := reloadedRepository.GetShortURLByShortURI(testCtx, "ghi")
+	s.Require().NoError(err)
+	s.Equal("https://mail.ru", shortURLEntity.LongURL, "conflicting short url must not be persisted")
+}
+
+func (s *JSONFileShortURLRepositoryTestSuite) TestDeleteShortURLsByShortURIs_persisted() {
+	testUserID := uuid.NewString()
+	testShortURL := &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "ghi",
+		LongURL:  "https://mail.ru",
+		UserID:   testUserID,
+		Deleted:  false,
+	}
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	_, err := s.repository.SaveShortURL(testCtx, testShortURL)
+	if err != nil {
+		s.Fail("unexpected error when save ShortURLEntity")
+	}
+
+	_, err = s.repository.DeleteShortURLsByShortURIs(testCtx, []string{testShortURL.ShortURI})
+	if err != nil {
+		s.Fail("unexpected error when delete ShortURLEntity")
+	}
+
+	testDeleteJob := &entity.DeleteJobEntity{
+		ID:        uuid.NewString(),
+		UserID:    testUserID,
+		Status:    entity.DeleteJobStatusPending,
+		ShortURIs: []string{testShortURL.ShortURI},
+	}
+	err = s.repository.SaveDeleteJob(testCtx, testDeleteJob)
+	if err != nil {
+		s.Fail("unexpected error when save DeleteJobEntity")
+	}
+
+	reloadedRepository, err := NewJSONFileShortURLRepository(TestDataFile)
+	if err != nil {
+		s.Fail("repository: unexpected error when create JSONFileShortURLRepository: %v", err)
+	}
+
+	reloadedShortURL, err := reloadedRepository.GetShortURLByShortURI(testCtx, testShortURL.ShortURI)
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntity")
+	}
+	s.True(reloadedShortURL.Deleted, "reloadedShortURL must be deleted")
+	s.Equal(1, len(reloadedRepository.storageByUserID[testUserID]), "reloadedShortURL must be saved in storageByUserID once")
+
+	pendingDeleteJobs, err := reloadedRepository.GetDeleteJobsByStatus(testCtx, entity.DeleteJobStatusPending)
+	if err != nil {
+		s.Fail("unexpected error when get DeleteJobEntities")
+	}
+	s.Equal(1, len(pendingDeleteJobs), "pending delete job must be reloaded")
+}
+
+func (s *JSONFileShortURLRepositoryTestSuite) TestUpdateShortURL_persisted() {
+	testUserID := uuid.NewString()
+	testShortURL := &entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "ghi",
+		LongURL:  "https://mail.ru",
+		UserID:   testUserID,
+	}
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	savedShortURL, err := s.repository.SaveShortURL(testCtx, testShortURL)
+	if err != nil {
+		s.Fail("unexpected error when save ShortURLEntity")
+	}
+
+	savedShortURL.Title = "Почта"
+	savedShortURL.Tags = []string{"mail"}
+	savedShortURL.RedirectStatus = http.StatusMovedPermanently
+	savedShortURL.PathPassthrough = true
+	savedShortURL.UTMParams = map[string]string{"utm_source": "shortener"}
+	savedShortURL.TargetingRules = []targeting.Rule{{Platform: targeting.PlatformIOS, URL: "https://apps.apple.com/app/id1"}}
+	_, err = s.repository.UpdateShortURL(testCtx, *savedShortURL)
+	if err != nil {
+		s.Fail("unexpected error when update ShortURLEntity")
+	}
+
+	reloadedRepository, err := NewJSONFileShortURLRepository(TestDataFile)
+	if err != nil {
+		s.Fail("repository: unexpected error when create JSONFileShortURLRepository: %v", err)
+	}
+
+	reloadedShortURL, err := reloadedRepository.GetShortURLByShortURI(testCtx, testShortURL.ShortURI)
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntity")
+	}
+	s.Equal("Почта", reloadedShortURL.Title)
+	s.Equal([]string{"mail"}, reloadedShortURL.Tags)
+	s.Equal(http.StatusMovedPermanently, reloadedShortURL.RedirectStatus)
+	s.True(reloadedShortURL.PathPassthrough)
+	s.Equal(map[string]string{"utm_source": "shortener"}, reloadedShortURL.UTMParams)
+	s.Equal(savedShortURL.TargetingRules, reloadedShortURL.TargetingRules)
+	s.Equal(1, len(reloadedRepository.storageByUserID[testUserID]), "reloadedShortURL must be saved in storageByUserID once")
+}
+
+func (s *JSONFileShortURLRepositoryTestSuite) TestPurgeDeletedShortURLs_compacted() {
+	testUserID := uuid.NewString()
+	testShortURLs := []entity.ShortURLEntity{
+		{
+			UUID:     uuid.NewString(),
+			ShortURI: "ghi",
+			LongURL:  "https://mail.ru",
+			UserID:   testUserID,
+		},
+		{
+			UUID:     uuid.NewString(),
+			ShortURI: "jkl",
+			LongURL:  "https://vk.com",
+			UserID:   testUserID,
+		},
+	}
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	_, err := s.repository.SaveShortURLs(testCtx, testShortURLs)
+	if err != nil {
+		s.Fail("unexpected error when save ShortURLEntities")
+	}
+
+	_, err = s.repository.DeleteShortURLsByShortURIs(testCtx, []string{"ghi"})
+	if err != nil {
+		s.Fail("unexpected error when delete ShortURLEntity")
+	}
+
+	purgedCount, err := s.repository.PurgeDeletedShortURLs(testCtx, time.Now().Add(time.Second))
+	if err != nil {
+		s.Fail("unexpected error when purge deleted ShortURLEntities")
+	}
+	s.Equal(1, purgedCount, "not expected count of purged ShortURLEntities")
+
+	reloadedRepository, err := NewJSONFileShortURLRepository(TestDataFile)
+	if err != nil {
+		s.Fail("repository: unexpected error when create JSONFileShortURLRepository: %v", err)
+	}
+
+	_, err = reloadedRepository.GetShortURLByShortURI(testCtx, "ghi")
+	s.ErrorIs(err, ErrNotFound, "purged ShortURLEntity must not be reloaded")
+	reloadedShortURLs, err := reloadedRepository.GetShortURLsByUserID(testCtx, testUserID)
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntities")
+	}
+	s.Equal(1, len(reloadedShortURLs), "not expected count of reloaded ShortURLEntities")
+}
+
+func (s *JSONFileShortURLRepositoryTestSuite) TestIncrementSplitClicks_persisted() {
+	testCtx := context.Background()
+	for _, variant := range []string{"a", "b", "a"} {
+		err := s.repository.IncrementSplitClicks(testCtx, "abc", variant)
+		if err != nil {
+			s.Fail("unexpected error when increment split clicks: %v", err)
+		}
+	}
+
+	reloadedRepository, err := NewJSONFileShortURLRepository(TestDataFile)
+	if err != nil {
+		s.Fail("repository: unexpected error when create JSONFileShortURLRepository: %v", err)
+	}
+
+	splitClicks, err := reloadedRepository.GetSplitClicks(testCtx, "abc")
+	if err != nil {
+		s.Fail("unexpected error when get split clicks: %v", err)
+	}
+	s.Equal(map[string]int64{"a": 2, "b": 1}, splitClicks, "split clicks must be reloaded")
+}
+
+func (s *JSONFileShortURLRepositoryTestSuite) TestIncrementCountryClicks_persisted() {
+	testCtx := context.Background()
+	for _, country := range []string{"SE", "GB", "SE"} {
+		err := s.repository.IncrementCountryClicks(testCtx, "abc", country)
+		if err != nil {
+			s.Fail("unexpected error when increment country clicks: %v", err)
+		}
+	}
+
+	reloadedRepository, err := NewJSONFileShortURLRepository(TestDataFile)
+	if err != nil {
+		s.Fail("repository: unexpected error when create JSONFileShortURLRepository: %v", err)
+	}
+
+	countryClicks, err := reloadedRepository.GetCountryClicks(testCtx, "abc")
+	if err != nil {
+		s.Fail("unexpected error when get country clicks: %v", err)
+	}
+	s.Equal(map[string]int64{"SE": 2, "GB": 1}, countryClicks, "country clicks must be reloaded")
+}
+
+func (s *JSONFileShortURLRepositoryTestSuite) TestConsumeShortURLClick_persisted() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURL := &entity.ShortURLEntity{
+		UUID:      uuid.NewString(),
+		ShortURI:  "abc",
+		LongURL:   "https://ya.ru",
+		UserID:    testUserID,
+		MaxClicks: 10,
+	}
+	if _, err := s.repository.SaveShortURL(testCtx, testShortURL); err != nil {
+		s.Fail("unexpected error when save ShortURLEntity: %v", err)
+	}
+
+	updatedShortURL := *testShortURL
+	updatedShortURL.Title = "Яндекс"
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		// изменение короткой ссылки во время переходов не должно откатывать количество переходов в файле
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			if _, err := s.repository.UpdateShortURL(testCtx, updatedShortURL); err != nil {
+				s.Fail("unexpected error when update ShortURLEntity: %v", err)
+			}
+		}
+	}()
+	consumed, exhausted, err := consumeShortURLClickConcurrently(s.repository, "abc", 50)
+	wg.Wait()
+	if err != nil {
+		s.Fail("unexpected error when consume short url click: %v", err)
+	}
+	s.Equal(int64(10), consumed, "only MaxClicks clicks must be consumed")
+	s.Equal(int64(40), exhausted)
+
+	reloadedRepository, err := NewJSONFileShortURLRepository(TestDataFile)
+	if err != nil {
+		s.Fail("repository: unexpected error when create JSONFileShortURLRepository: %v", err)
+	}
+
+	reloadedShortURL, err := reloadedRepository.GetShortURLByShortURI(testCtx, "abc")
+	if err != nil {
+		s.Fail("unexpected error when get ShortURLEntity: %v", err)
+	}
+	s.Equal(int64(10), reloadedShortURL.ClickCount, "click count must be reloaded")
+	s.ErrorIs(reloadedRepository.ConsumeShortURLClick(testCtx, "abc"), ErrClicksExhausted)
+}
+
+func (s *JSONFileShortURLRepositoryTestSuite) TestSaveUserSettings_persisted() {
+	testCtx := context.Background()
+	testUserID := uuid.NewString()
+	for _, fallbackURL := range []string{"https://example.com/old", "https://example.com/new"} {
+		err := s.repository.SaveUserSettings(testCtx, entity.UserSettingsEntity{UserID: testUserID, FallbackURL: fallbackURL})
+		if err != nil {
+			s.Fail("unexpected error when save UserSettingsEntity: %v", err)
+		}
+	}
+
+	reloadedRepository, err := NewJSONFileShortURLRepository(TestDataFile)
+	if err != nil {
+		s.Fail("repository: unexpected error when create JSONFileShortURLRepository: %v", err)
+	}
+
+	userSettings, err := reloadedRepository.GetUserSettings(testCtx, testUserID)
+	if err != nil {
+		s.Fail("unexpected error when get UserSettingsEntity: %v", err)
+	}
+	s.Equal("https://example.com/new", userSettings.FallbackURL, "last saved settings must be reloaded")
+}
+
 func TestJSONFileShortURLRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(JSONFileShortURLRepositoryTestSuite))
 }
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/redisstate"
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
+)
+
+// redisShortURLKeyPrefix - префикс ключей Redis с результатами поиска коротких ссылок
+// redisSplitClicksKeyPrefix - префикс ключей Redis со счетчиками переходов по вариантам A/B-теста
+// redisCountryClicksKeyPrefix - префикс ключей Redis со счетчиками переходов по странам
+const (
+	redisShortURLKeyPrefix      = "short_url:"
+	redisSplitClicksKeyPrefix   = "split_clicks:"
+	redisCountryClicksKeyPrefix = "country_clicks:"
+)
+
+// redisCachedShortURL - представление cachedShortURL в Redis
+type redisCachedShortURL struct {
+	Found    bool                   `json:"found"`
+	ShortURL *redisRedirectShortURL `json:"short_url,omitempty"`
+}
+
+// redisRedirectShortURL - поля короткой ссылки, необходимые для перенаправления
+//
+// Метаданные и хэш пароля в Redis, общий для экземпляров сервиса, не сохраняются.
+type redisRedirectShortURL struct {
+	UUID             string            `json:"uuid"`
+	ShortURI         string            `json:"short_url"`
+	LongURL          string            `json:"original_url"`
+	UserID           string            `json:"user_id"`
+	Deleted          bool              `json:"is_deleted,omitempty"`
+	DeletedAt        *time.Time        `json:"deleted_at,omitempty"`
+	RedirectStatus   int               `json:"redirect_status,omitempty"`
+	QueryPassthrough string            `json:"query_passthrough,omitempty"`
+	PathPassthrough  bool              `json:"path_passthrough,omitempty"`
+	UTMParams        map[string]string `json:"utm_params,omitempty"`
+	TargetingRules   []targeting.Rule  `json:"targeting_rules,omitempty"`
+	SplitVariants    []split.Variant   `json:"split_variants,omitempty"`
+	MaxClicks        int               `json:"max_clicks,omitempty"`
+	ClickCount       int64             `json:"click_count,omitempty"`
+	NotBefore        *time.Time        `json:"not_before,omitempty"`
+	NotAfter         *time.Time        `json:"not_after,omitempty"`
+	Disabled         bool              `json:"is_disabled,omitempty"`
+	FallbackURL      string            `json:"fallback_url,omitempty"`
+}
+
+func toRedisRedirectShortURL(shortURLEntity entity.ShortURLEntity) *redisRedirectShortURL {
+	return &redisRedirectShortURL{
+		UUID:             shortURLEntity.UUID,
+		ShortURI:         shortURLEntity.ShortURI,
+		LongURL:          shortURLEntity.LongURL,
+		UserID:           shortURLEntity.UserID,
+		Deleted:          shortURLEntity.Deleted,
+		DeletedAt:        shortURLEntity.DeletedAt,
+		RedirectStatus:   shortURLEntity.RedirectStatus,
+		QueryPassthrough: shortURLEntity.QueryPassthrough,
+		PathPassthrough:  shortURLEntity.PathPassthrough,
+		UTMParams:        shortURLEntity.UTMParams,
+		TargetingRules:   shortURLEntity.TargetingRules,
+		SplitVariants:    shortURLEntity.SplitVariants,
+		MaxClicks:        shortURLEntity.MaxClicks,
+		ClickCount:       shortURLEntity.ClickCount,
+		NotBefore:        shortURLEntity.NotBefore,
+		NotAfter:         shortURLEntity.NotAfter,
+		Disabled:         shortURLEntity.Disabled,
+		FallbackURL:      shortURLEntity.FallbackURL,
+	}
+}
+
+func (s *redisRedirectShortURL) toEntity() entity.ShortURLEntity {
+	return entity.ShortURLEntity{
+		UUID:             s.UUID,
+		ShortURI:         s.ShortURI,
+		LongURL:          s.LongURL,
+		UserID:           s.UserID,
+		Deleted:          s.Deleted,
+		DeletedAt:        s.DeletedAt,
+		RedirectStatus:   s.RedirectStatus,
+		QueryPassthrough: s.QueryPassthrough,
+		PathPassthrough:  s.PathPassthrough,
+		UTMParams:        s.UTMParams,
+		TargetingRules:   s.TargetingRules,
+		SplitVariants:    s.SplitVariants,
+		MaxClicks:        s.MaxClicks,
+		ClickCount:       s.ClickCount,
+		NotBefore:        s.NotBefore,
+		NotAfter:         s.NotAfter,
+		Disabled:         s.Disabled,
+		FallbackURL:      s.FallbackURL,
+	}
+}
+
+// redisShortURLCache - кэш результатов поиска коротких ссылок в Redis, общий для экземпляров сервиса
+//
+// Кэшируются только поля короткой ссылки, необходимые для перенаправления, поэтому найденная в кэше короткая ссылка
+// используется только при перенаправлении. Короткие ссылки с паролем не кэшируются.
+// Недоступность Redis не является ошибкой: поиск считается промахом кэша, а запись и удаление пропускаются.
+type redisShortURLCache struct {
+	client *redisstate.Client
+	ttl    time.Duration
+}
+
+func (c redisShortURLCache) get(ctx context.Context, shortURI string) (cachedShortURL, bool) {
+	value, found, err := c.client.Get(ctx, redisShortURLKeyPrefix+shortURI)
+	if err != nil || !found {
+		return cachedShortURL{}, false
+	}
+
+	var cached redisCachedShortURL
+	if err := json.Unmarshal(value, &cached); err != nil {
+		log.Errorw("repository: unexpected cached short url", "shortURI", shortURI, "err", err)
+		return cachedShortURL{}, false
+	}
+	if !cached.Found || cached.ShortURL == nil {
+		return cachedShortURL{}, true
+	}
+
+	return cachedShortURL{shortURLEntity: cached.ShortURL.toEntity(), found: true, redirectOnly: true}, true
+}
+
+func (c redisShortURLCache) add(ctx context.Context, shortURI string, cached cachedShortURL) {
+	redisCached := redisCachedShortURL{Found: cached.found}
+	if cached.found {
+		// для перенаправления по короткой ссылке с паролем нужен хэш пароля, который не должен покидать хранилище
+		if cached.shortURLEntity.PasswordHash != "" {
+			return
+		}
+
+		redisCached.ShortURL = toRedisRedirectShortURL(cached.shortURLEntity)
+	}
+
+	value, err := json.Marshal(redisCached)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return
+	}
+
+	_ = c.client.Set(ctx, redisShortURLKeyPrefix+shortURI, value, c.ttl)
+}
+
+func (c redisShortURLCache) remove(ctx context.Context, shortURIs ...string) {
+	keys := make([]string, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		keys = append(keys, redisShortURLKeyPrefix+shortURI)
+	}
+
+	// запись кэша удаляется и при отмене запроса, изменившего короткую ссылку
+	if err := c.client.Del(context.WithoutCancel(ctx), keys...); err != nil {
+		log.Warnw("repository: failed to invalidate cached short urls", "shortURIs", shortURIs, "err", err)
+	}
+}
+
+func (c redisShortURLCache) purge(ctx context.Context) {
+	if err := c.client.DelByPrefix(context.WithoutCancel(ctx), redisShortURLKeyPrefix); err != nil {
+		log.Warnw("repository: failed to purge cached short urls", "err", err)
+	}
+}
+
+// NewRedisCachedShortURLRepository создает экземпляр структуры CachedShortURLRepository,
+// кэширующий поиск коротких ссылок в Redis
+//
+//	repo - репозиторий, поиск коротких ссылок в котором кэшируется
+//	client - клиент Redis
+//	ttl - время жизни записи кэша, ограничивает время, в течение которого видна запись кэша,
+//	не удаленная из-за недоступности Redis, 0 - записи не истекают
+func NewRedisCachedShortURLRepository(repo ShortURLRepository, client *redisstate.Client, ttl time.Duration) *CachedShortURLRepository {
+	return &CachedShortURLRepository{
+		ShortURLRepository: repo,
+		cache:              redisShortURLCache{client: client, ttl: ttl},
+	}
+}
+
+// RedisClickRepository реализует интерфейс IShortURLRepository, ведя счетчики переходов по вариантам
+// A/B-теста и по странам в Redis, общем для экземпляров сервиса, вместо репозитория ShortURLRepository
+//
+// Если Redis недоступен, переходы учитываются в репозитории, поэтому количество переходов - сумма счетчиков
+// в Redis и в репозитории.
+type RedisClickRepository struct {
+	ShortURLRepository
+	client *redisstate.Client
+}
+
+// NewRedisClickRepository создает экземпляр структуры RedisClickRepository
+func NewRedisClickRepository(repo ShortURLRepository, client *redisstate.Client) *RedisClickRepository {
+	return &RedisClickRepository{
+		ShortURLRepository: repo,
+		client:             client,
+	}
+}
+
+// IncrementSplitClicks увеличивает счетчик переходов по варианту variant короткой ссылки shortURI
+func (r *RedisClickRepository) IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error {
+	err := r.client.HIncr(ctx, redisSplitClicksKeyPrefix+shortURI, variant)
+	if err != nil {
+		log.Warnw("repository: failed to increment split clicks in redis, falling back to repository",
+			"shortURI", shortURI, "variant", variant, "err", err)
+		return r.ShortURLRepository.IncrementSplitClicks(ctx, shortURI, variant)
+	}
+
+	return nil
+}
+
+// IncrementCountryClicks увеличивает счетчик переходов из страны country по короткой ссылке shortURI
+func (r *RedisClickRepository) IncrementCountryClicks(ctx context.Context, shortURI string, country string) error {
+	err := r.client.HIncr(ctx, redisCountryClicksKeyPrefix+shortURI, country)
+	if err != nil {
+		log.Warnw("repository: failed to increment country clicks in redis, falling back to repository",
+			"shortURI", shortURI, "country", country, "err", err)
+		return r.ShortURLRepository.IncrementCountryClicks(ctx, shortURI, country)
+	}
+
+	return nil
+}
+
+// GetSplitClicks возвращает количество переходов по вариантам короткой ссылки shortURI
+//
+// Если Redis недоступен, возвращаются только переходы, учтенные в репозитории.
+func (r *RedisClickRepository) GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	clicks, err := r.ShortURLRepository.GetSplitClicks(ctx, shortURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.addRedisClicks(ctx, redisSplitClicksKeyPrefix+shortURI, clicks), nil
+}
+
+// GetCountryClicks возвращает количество переходов по короткой ссылке shortURI по странам
+//
+// Если Redis недоступен, возвращаются только переходы, учтенные в репозитории.
+func (r *RedisClickRepository) GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	clicks, err := r.ShortURLRepository.GetCountryClicks(ctx, shortURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.addRedisClicks(ctx, redisCountryClicksKeyPrefix+shortURI, clicks), nil
+}
+
+// addRedisClicks добавляет к счетчикам clicks из репозитория счетчики из Redis по ключу key
+func (r *RedisClickRepository) addRedisClicks(ctx context.Context, key string, clicks map[string]int64) map[string]int64 {
+	if clicks == nil {
+		clicks = make(map[string]int64)
+	}
+
+	redisClicks, err := r.client.HGetAllInt(ctx, key)
+	if err != nil {
+		log.Warnw("repository: failed to get clicks from redis", "key", key, "err", err)
+		return clicks
+	}
+
+	for clicksKey, keyClicks := range redisClicks {
+		clicks[clicksKey] += keyClicks
+	}
+
+	return clicks
+}
+
+// PurgeDeletedShortURLs окончательно удаляет короткие ссылки, удаленные раньше deletedBefore,
+// вместе со счетчиками переходов в Redis
+func (r *RedisClickRepository) PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	purgedCount, err := r.ShortURLRepository.PurgeDeletedShortURLs(ctx, deletedBefore)
+	if err != nil || purgedCount == 0 {
+		return purgedCount, err
+	}
+
+	// если Redis недоступен, счетчики будут удалены при следующем окончательном удалении коротких ссылок
+	orphanKeys := make([]string, 0)
+	for _, prefix := range []string{redisSplitClicksKeyPrefix, redisCountryClicksKeyPrefix} {
+		keys, err := r.client.Keys(ctx, prefix)
+		if err != nil {
+			log.Warnw("repository: failed to get clicks keys from redis", "prefix", prefix, "err", err)
+			return purgedCount, nil
+		}
+
+		for _, key := range keys {
+			_, err := r.ShortURLRepository.GetShortURLByShortURI(ctx, strings.TrimPrefix(key, prefix))
+			if err != nil && errors.Is(err, ErrNotFound) {
+				orphanKeys = append(orphanKeys, key)
+			} else if err != nil {
+				log.Errorw("repository: unexpected error", "err", err)
+				return purgedCount, nil
+			}
+		}
+	}
+
+	if err := r.client.Del(ctx, orphanKeys...); err != nil {
+		log.Warnw("repository: failed to delete clicks from redis", "err", err)
+	}
+
+	return purgedCount, nil
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/redisstate"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+)
+
+type RedisRepositoryTestSuite struct {
+	suite.Suite
+	server       *miniredis.Miniredis
+	client       *redisstate.Client
+	backend      *InMemoryShortURLRepository
+	ctx          context.Context
+	testShortURL entity.ShortURLEntity
+}
+
+func (suite *RedisRepositoryTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+
+	var err error
+	suite.client, err = redisstate.NewClient("redis://"+suite.server.Addr(), "test:")
+	suite.Require().NoError(err)
+
+	testUserID := uuid.NewString()
+	suite.testShortURL = entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   testUserID,
+	}
+	suite.ctx = context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+
+	suite.backend = NewInMemoryShortURLRepository()
+}
+
+func (suite *RedisRepositoryTestSuite) TearDownTest() {
+	suite.NoError(suite.client.Close())
+}
+
+func (suite *RedisRepositoryTestSuite) TestGetShortURLByShortURI_shared() {
+	// экземпляры сервиса с общим хранилищем и общим кэшем в Redis
+	first := NewRedisCachedShortURLRepository(suite.backend, suite.client, time.Minute)
+	second := NewRedisCachedShortURLRepository(suite.backend, suite.client, time.Minute)
+
+	_, err := first.GetShortURLByShortURI(context.Background(), "abc")
+	suite.ErrorIs(err, ErrNotFound)
+	_, err = second.GetShortURLByShortURI(context.Background(), "abc")
+	suite.ErrorIs(err, ErrNotFound, "not found short url must be cached")
+	hits, misses := second.CacheStats()
+	suite.Equal(int64(1), hits)
+	suite.Equal(int64(0), misses)
+
+	_, err = first.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+	shortURLEntity, err := second.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err, "save must invalidate shared cache")
+	suite.Equal(suite.testShortURL.LongURL, shortURLEntity.LongURL)
+
+	updatedShortURL := suite.testShortURL
+	updatedShortURL.Title = "title"
+	_, err = second.UpdateShortURL(suite.ctx, updatedShortURL)
+	suite.Require().NoError(err)
+	shortURLEntity, err = first.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal("title", shortURLEntity.Title, "update must invalidate shared cache")
+	suite.True(suite.server.Exists("test:short_url:abc"))
+}
+
+func (suite *RedisRepositoryTestSuite) TestGetRedirectShortURLByShortURI_projection() {
+	repo := NewRedisCachedShortURLRepository(suite.backend, suite.client, time.Minute)
+	suite.testShortURL.Title = "title"
+	suite.testShortURL.Notes = "private notes"
+	protectedShortURL := entity.ShortURLEntity{
+		UUID:         uuid.NewString(),
+		ShortURI:     "protected",
+		LongURL:      "https://mail.ru",
+		UserID:       suite.testShortURL.UserID,
+		PasswordHash: "$2a$10$hash",
+	}
+	for _, shortURLEntity := range []*entity.ShortURLEntity{&suite.testShortURL, &protectedShortURL} {
+		_, err := repo.SaveShortURL(suite.ctx, shortURLEntity)
+		suite.Require().NoError(err)
+	}
+
+	shortURLEntity, err := repo.GetRedirectShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal(suite.testShortURL.LongURL, shortURLEntity.LongURL)
+
+	cachedValue, err := suite.server.Get("test:short_url:abc")
+	suite.Require().NoError(err)
+	suite.Contains(cachedValue, suite.testShortURL.LongURL)
+	suite.NotContains(cachedValue, "private notes", "only redirect fields must be cached in redis")
+
+	shortURLEntity, err = repo.GetRedirectShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Empty(shortURLEntity.Notes, "redirect must be served from redis")
+	shortURLEntity, err = repo.GetShortURLByShortURI(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal("private notes", shortURLEntity.Notes, "full short url must not be served from redirect projection")
+
+	for i := 0; i < 2; i++ {
+		shortURLEntity, err = repo.GetRedirectShortURLByShortURI(context.Background(), "protected")
+		suite.Require().NoError(err)
+		suite.Equal(protectedShortURL.PasswordHash, shortURLEntity.PasswordHash)
+	}
+	suite.False(suite.server.Exists("test:short_url:protected"), "password protected short url must not be cached in redis")
+}
+
+func (suite *RedisRepositoryTestSuite) TestGetShortURLByShortURI_redis_unavailable() {
+	repo := NewRedisCachedShortURLRepository(suite.backend, suite.client, time.Minute)
+	_, err := repo.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+
+	suite.server.Close()
+	for i := 0; i < 2; i++ {
+		shortURLEntity, err := repo.GetShortURLByShortURI(context.Background(), "abc")
+		suite.Require().NoError(err)
+		suite.Equal(suite.testShortURL.LongURL, shortURLEntity.LongURL)
+	}
+
+	hits, misses := repo.CacheStats()
+	suite.Equal(int64(0), hits)
+	suite.Equal(int64(2), misses)
+}
+
+func (suite *RedisRepositoryTestSuite) TestSplitClicks() {
+	repo := NewRedisClickRepository(suite.backend, suite.client)
+	_, err := repo.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(repo.IncrementSplitClicks(context.Background(), "abc", "a"))
+	suite.Require().NoError(repo.IncrementSplitClicks(context.Background(), "abc", "b"))
+	backendClicks, err := suite.backend.GetSplitClicks(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Empty(backendClicks, "clicks must be counted in redis")
+
+	suite.server.Close()
+	suite.Require().NoError(repo.IncrementSplitClicks(context.Background(), "abc", "a"))
+	clicks, err := repo.GetSplitClicks(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal(map[string]int64{"a": 1}, clicks, "only repository clicks are available without redis")
+
+	suite.Require().NoError(suite.server.Restart())
+	suite.NoError(suite.client.Close())
+	suite.client, err = redisstate.NewClient("redis://"+suite.server.Addr(), "test:")
+	suite.Require().NoError(err)
+	repo = NewRedisClickRepository(suite.backend, suite.client)
+	clicks, err = repo.GetSplitClicks(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal(map[string]int64{"a": 2, "b": 1}, clicks)
+}
+
+func (suite *RedisRepositoryTestSuite) TestCountryClicks() {
+	repo := NewRedisClickRepository(suite.backend, suite.client)
+	_, err := repo.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(repo.IncrementCountryClicks(context.Background(), "abc", "SE"))
+	suite.Require().NoError(repo.IncrementCountryClicks(context.Background(), "abc", "GB"))
+	backendClicks, err := suite.backend.GetCountryClicks(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Empty(backendClicks, "clicks must be counted in redis")
+
+	suite.server.Close()
+	suite.Require().NoError(repo.IncrementCountryClicks(context.Background(), "abc", "SE"))
+	clicks, err := repo.GetCountryClicks(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal(map[string]int64{"SE": 1}, clicks, "only repository clicks are available without redis")
+
+	suite.Require().NoError(suite.server.Restart())
+	suite.NoError(suite.client.Close())
+	suite.client, err = redisstate.NewClient("redis://"+suite.server.Addr(), "test:")
+	suite.Require().NoError(err)
+	repo = NewRedisClickRepository(suite.backend, suite.client)
+	clicks, err = repo.GetCountryClicks(context.Background(), "abc")
+	suite.Require().NoError(err)
+	suite.Equal(map[string]int64{"SE": 2, "GB": 1}, clicks)
+}
+
+func (suite *RedisRepositoryTestSuite) TestPurgeDeletedShortURLs_split_clicks() {
+	repo := NewRedisClickRepository(suite.backend, suite.client)
+	_, err := repo.SaveShortURL(suite.ctx, &suite.testShortURL)
+	suite.Require().NoError(err)
+	suite.Require().NoError(repo.IncrementSplitClicks(context.Background(), "abc", "a"))
+	suite.Require().NoError(repo.IncrementSplitClicks(context.Background(), "cde", "a"))
+	suite.Require().NoError(repo.IncrementCountryClicks(context.Background(), "abc", "SE"))
+
+	_, err = repo.DeleteShortURLsByShortURIs(suite.ctx, []string{"abc"})
+	suite.Require().NoError(err)
+	purgedCount, err := repo.PurgeDeletedShortURLs(context.Background(), time.Now().Add(time.Minute))
+	suite.Require().NoError(err)
+	suite.Equal(1, purgedCount)
+
+	suite.Empty(suite.server.Keys())
+}
+
+func TestRedisRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RedisRepositoryTestSuite))
+}
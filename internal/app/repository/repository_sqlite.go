@@ -0,0 +1,913 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/vkhrushchev/urlshortener/internal/app/db"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+)
+
+const (
+	sqliteInsertRow = "INSERT INTO short_url(" + sqlShortURLInsertColumns + ") " +
+		"VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	sqliteSelectByShortURL        = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE su.short_url = ?"
+	sqliteSelectByOriginalURL     = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE su.original_url = ? AND su.short_domain = ?"
+	sqliteSelectByUserID          = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE su.user_id = ?"
+	sqliteSelectDeletedByUserID   = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE su.user_id = ? AND su.is_deleted = true ORDER BY su.deleted_at DESC"
+	sqliteSelectOwnersByShortURLs = "SELECT su.short_url, su.user_id, su.is_deleted FROM short_url su WHERE su.short_url IN (%s)"
+	sqliteUpdateIsDeleted         = "UPDATE short_url SET is_deleted = true, deleted_at = ? WHERE is_deleted = false AND user_id = ? AND short_url IN (%s)"
+	sqliteUpdateIsNotDeleted      = "UPDATE short_url SET is_deleted = false, deleted_at = NULL WHERE is_deleted = true AND user_id = ? AND short_url IN (%s)"
+	sqliteUpdateMetadata          = "UPDATE short_url SET title = ?, description = ?, notes = ?, tags = ?, " +
+		"redirect_status = ?, query_passthrough = ?, path_passthrough = ?, utm_params = ?, targeting_rules = ?, split_variants = ?, " +
+		"password_hash = ?, max_clicks = ?, not_before = ?, not_after = ?, " +
+		"is_disabled = ?, fallback_url = ? WHERE short_url = ? AND user_id = ?"
+	sqliteUpdatePreview = "UPDATE short_url SET preview_title = ?, preview_description = ?, preview_image_url = ?, preview_fetched_at = ? WHERE short_url = ?"
+	// SQLite выполняет пишущие транзакции последовательно, поэтому условие click_count < max_clicks
+	// проверяется для актуальной версии строки
+	sqliteConsumeClick        = "UPDATE short_url SET click_count = click_count + 1 WHERE short_url = ? AND click_count < max_clicks"
+	sqliteSelectMaxClicks     = "SELECT su.max_clicks FROM short_url su WHERE su.short_url = ?"
+	sqliteDeleteDeletedBefore = "DELETE FROM short_url WHERE is_deleted = true AND deleted_at < ?"
+	sqliteDeleteOrphanClicks  = "DELETE FROM split_click WHERE NOT EXISTS (SELECT 1 FROM short_url su WHERE su.short_url = split_click.short_url)"
+	sqliteDeleteOrphanCountry = "DELETE FROM country_click WHERE NOT EXISTS (SELECT 1 FROM short_url su WHERE su.short_url = country_click.short_url)"
+	sqliteSelectPageByQuery   = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE %s ORDER BY su.%s %s, su.uuid %s LIMIT ?"
+	sqliteCountByQuery        = "SELECT count(*) FROM short_url su WHERE %s"
+	sqliteSelectFirstPage     = "SELECT " + sqlShortURLColumns + " FROM short_url su ORDER BY su.created_at, su.uuid LIMIT ?"
+	sqliteSelectPageAfter     = "SELECT " + sqlShortURLColumns + " FROM short_url su WHERE (su.created_at, su.uuid) > (?, ?) ORDER BY su.created_at, su.uuid LIMIT ?"
+	sqliteStats               = "SELECT (SELECT count(*) FROM short_url) AS url_count, (SELECT count(DISTINCT user_id) FROM short_url) AS user_count"
+
+	sqliteUpsertDeleteJob = "INSERT INTO delete_job(id, user_id, status, short_urls, results, created_at, updated_at) VALUES(?, ?, ?, ?, ?, ?, ?) " +
+		"ON CONFLICT (id) DO UPDATE SET status = excluded.status, results = excluded.results, updated_at = excluded.updated_at"
+	sqliteSelectDeleteJobByID      = "SELECT dj.id, dj.user_id, dj.status, dj.short_urls, dj.results, dj.created_at, dj.updated_at FROM delete_job dj WHERE dj.id = ?"
+	sqliteSelectDeleteJobsByStatus = "SELECT dj.id, dj.user_id, dj.status, dj.short_urls, dj.results, dj.created_at, dj.updated_at FROM delete_job dj WHERE dj.status = ? ORDER BY dj.created_at"
+
+	sqliteIncrementSplitClicks = "INSERT INTO split_click(short_url, variant, clicks) VALUES(?, ?, 1) " +
+		"ON CONFLICT (short_url, variant) DO UPDATE SET clicks = split_click.clicks + 1"
+	sqliteSelectSplitClicks = "SELECT sc.variant, sc.clicks FROM split_click sc WHERE sc.short_url = ?"
+
+	sqliteIncrementCountryClicks = "INSERT INTO country_click(short_url, country, clicks) VALUES(?, ?, 1) " +
+		"ON CONFLICT (short_url, country) DO UPDATE SET clicks = country_click.clicks + 1"
+	sqliteSelectCountryClicks = "SELECT cc.country, cc.clicks FROM country_click cc WHERE cc.short_url = ?"
+
+	sqliteUpsertUserSettings = "INSERT INTO user_settings(user_id, fallback_url) VALUES(?, ?) " +
+		"ON CONFLICT (user_id) DO UPDATE SET fallback_url = excluded.fallback_url"
+	sqliteSelectUserSettings = "SELECT us.user_id, us.fallback_url FROM user_settings us WHERE us.user_id = ?"
+)
+
+// SQLiteShortURLRepository структура для хранения ссылки на db.DBLookup базы данных SQLite.
+//
+// Реализует интерфейс IShortURLRepository для хранения коротких ссылок в файле базы данных SQLite
+type SQLiteShortURLRepository struct {
+	dbLookup *db.DBLookup
+}
+
+// NewSQLiteShortURLRepository создает экземпляр структуры SQLiteShortURLRepository
+func NewSQLiteShortURLRepository(dbLookup *db.DBLookup) *SQLiteShortURLRepository {
+	return &SQLiteShortURLRepository{dbLookup: dbLookup}
+}
+
+// GetRedirectShortURLByShortURI возвращает короткую ссылку по shortURI для перенаправления,
+// хранилище возвращает короткую ссылку полностью
+func (r *SQLiteShortURLRepository) GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	return r.GetShortURLByShortURI(ctx, shortURI)
+}
+
+// GetShortURLByShortURI возвращает короткую ссылку по shortURI
+func (r *SQLiteShortURLRepository) GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	shortURLEntity, err := scanShortURL(dbLookup.QueryRowContext(ctx, sqliteSelectByShortURL, shortURI))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ShortURLEntity{}, ErrNotFound
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	return shortURLEntity, nil
+}
+
+// SaveShortURL сохраняет короткую ссылку
+//
+// Если исходный URL уже сокращен на домене короткой ссылки, возвращает существующую короткую ссылку и ErrConflict,
+// если занят ключ короткой ссылки - nil и ErrConflict.
+func (r *SQLiteShortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	if shortURLEntity.CreatedAt.IsZero() {
+		shortURLEntity.CreatedAt = time.Now()
+	}
+
+	args, err := sqliteInsertArgs(shortURLEntity)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	_, err = dbLookup.ExecContext(ctx, sqliteInsertRow, args...)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			existedShortURLEntity, err := scanShortURL(dbLookup.QueryRowContext(
+				ctx,
+				sqliteSelectByOriginalURL,
+				shortURLEntity.LongURL,
+				shortDomainOf(shortURLEntity.ShortURI),
+			))
+			if errors.Is(err, sql.ErrNoRows) {
+				// исходный URL не сокращен, значит занят ключ короткой ссылки
+				return nil, ErrConflict
+			}
+			if err != nil {
+				log.Errorw("repository: unexpected error", "err", err)
+				return nil, ErrUnexpected
+			}
+
+			return &existedShortURLEntity, ErrConflict
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return shortURLEntity, nil
+}
+
+// SaveShortURLs сохраняет короткие ссылки пачкой в одной транзакции
+//
+// Если ключ или исходный URL одной из коротких ссылок уже заняты или повторяются в пачке, не сохраняется ни одна
+// короткая ссылка пачки и возвращается ErrConflict.
+func (r *SQLiteShortURLRepository) SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	tx, err := dbLookup.BeginTx(ctx, nil)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+			log.Errorw("repository: error when rollback transaction", "rollbackErr", rollbackErr)
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, sqliteInsertRow)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	now := time.Now()
+	for i := range shortURLEntities {
+		shortURLEntity := &shortURLEntities[i]
+		if shortURLEntity.CreatedAt.IsZero() {
+			shortURLEntity.CreatedAt = now
+		}
+
+		args, err := sqliteInsertArgs(shortURLEntity)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			if isSQLiteUniqueViolation(err) {
+				return nil, ErrConflict
+			}
+
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return shortURLEntities, nil
+}
+
+// GetShortURLsByUserID возвращает список коротких ссылок по userID
+func (r *SQLiteShortURLRepository) GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
+	return r.queryShortURLs(ctx, sqliteSelectByUserID, userID)
+}
+
+// GetDeletedShortURLsByUserID возвращает список удаленных коротких ссылок пользователя userID
+func (r *SQLiteShortURLRepository) GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
+	return r.queryShortURLs(ctx, sqliteSelectDeletedByUserID, userID)
+}
+
+// GetShortURLsByQuery возвращает страницу коротких ссылок пользователя, удовлетворяющих запросу query,
+// и общее количество удовлетворяющих фильтрам запроса коротких ссылок
+func (r *SQLiteShortURLRepository) GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	conditions := []string{"su.user_id = ?"}
+	args := []any{query.UserID}
+
+	switch query.Status {
+	case entity.ShortURLStatusActive:
+		conditions = append(conditions, "su.is_deleted = false")
+	case entity.ShortURLStatusDeleted:
+		conditions = append(conditions, "su.is_deleted = true")
+	}
+	if query.CreatedFrom != nil {
+		conditions = append(conditions, "su.created_at >= ?")
+		args = append(args, *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		conditions = append(conditions, "su.created_at <= ?")
+		args = append(args, *query.CreatedTo)
+	}
+	if query.Domain != "" {
+		conditions = append(conditions, "su.original_url REGEXP ?")
+		args = append(args, "(?i)"+domainRegexp(query.Domain))
+	}
+	if query.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(su.tags) WHERE json_each.value = ?)")
+		args = append(args, query.Tag)
+	}
+	if query.Search != "" {
+		// LIKE в SQLite не учитывает регистр латинских букв, как ILIKE в Postgres
+		search := "%" + escapeLike(query.Search) + "%"
+		conditions = append(conditions, `(su.original_url LIKE ? ESCAPE '\' OR su.short_url LIKE ? ESCAPE '\')`)
+		args = append(args, search, search)
+	}
+
+	var totalCount int
+	err := dbLookup.QueryRowContext(ctx, fmt.Sprintf(sqliteCountByQuery, strings.Join(conditions, " AND ")), sqliteArgs(args...)...).
+		Scan(&totalCount)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, 0, ErrUnexpected
+	}
+
+	sortColumn, ok := sqlSortColumns[query.SortBy]
+	if !ok {
+		log.Errorw("repository: unexpected sort column", "sortBy", query.SortBy)
+		return nil, 0, ErrUnexpected
+	}
+
+	sortDirection, cursorOperator := "ASC", ">"
+	if query.SortDesc {
+		sortDirection, cursorOperator = "DESC", "<"
+	}
+
+	if query.After != nil {
+		var sortValue any = query.After.SortValue
+		if query.SortBy == entity.ShortURLSortByCreatedAt {
+			sortValue, err = time.Parse(time.RFC3339Nano, query.After.SortValue)
+			if err != nil {
+				log.Errorw("repository: unexpected error", "err", err)
+				return nil, 0, ErrUnexpected
+			}
+		}
+
+		conditions = append(conditions, fmt.Sprintf("(su.%s, su.uuid) %s (?, ?)", sortColumn, cursorOperator))
+		args = append(args, sortValue, query.After.UUID)
+	}
+
+	pageQuery := fmt.Sprintf(sqliteSelectPageByQuery, strings.Join(conditions, " AND "), sortColumn, sortDirection, sortDirection)
+	shortURLEntities, err := r.queryShortURLs(ctx, pageQuery, append(args, query.Limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return shortURLEntities, totalCount, nil
+}
+
+// ForEachShortURL вызывает fn для каждой короткой ссылки, включая удаленные, в порядке создания
+//
+// Обход прекращается при первой ошибке fn, которая возвращается без изменений.
+func (r *SQLiteShortURLRepository) ForEachShortURL(ctx context.Context, fn func(shortURLEntity entity.ShortURLEntity) error) error {
+	return forEachShortURLPage(func(after *entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+		if after == nil {
+			return r.queryShortURLs(ctx, sqliteSelectFirstPage, shortURLPageSize)
+		}
+
+		return r.queryShortURLs(ctx, sqliteSelectPageAfter, after.CreatedAt, after.UUID, shortURLPageSize)
+	}, fn)
+}
+
+func (r *SQLiteShortURLRepository) queryShortURLs(ctx context.Context, query string, args ...any) ([]entity.ShortURLEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	rows, err := dbLookup.QueryContext(ctx, query, sqliteArgs(args...)...)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+		}
+	}()
+
+	result := make([]entity.ShortURLEntity, 0)
+	for rows.Next() {
+		resultEntry, err := scanShortURL(rows)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
+		result = append(result, resultEntry)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return result, nil
+}
+
+// UpdateShortURL обновляет изменяемые поля короткой ссылки пользователя: название, описание, заметки, теги
+// и параметры перенаправления
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена или принадлежит другому пользователю.
+func (r *SQLiteShortURLRepository) UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	tagsJSON, err := marshalTags(shortURLEntity.Tags)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	utmParamsJSON, err := marshalUTMParams(shortURLEntity.UTMParams)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	targetingRulesJSON, err := marshalTargetingRules(shortURLEntity.TargetingRules)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	splitVariantsJSON, err := marshalSplitVariants(shortURLEntity.SplitVariants)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	res, err := dbLookup.ExecContext(
+		ctx,
+		sqliteUpdateMetadata,
+		sqliteArgs(
+			shortURLEntity.Title,
+			shortURLEntity.Description,
+			shortURLEntity.Notes,
+			tagsJSON,
+			shortURLEntity.RedirectStatus,
+			shortURLEntity.QueryPassthrough,
+			shortURLEntity.PathPassthrough,
+			utmParamsJSON,
+			targetingRulesJSON,
+			splitVariantsJSON,
+			shortURLEntity.PasswordHash,
+			shortURLEntity.MaxClicks,
+			shortURLEntity.NotBefore,
+			shortURLEntity.NotAfter,
+			shortURLEntity.Disabled,
+			shortURLEntity.FallbackURL,
+			shortURLEntity.ShortURI,
+			shortURLEntity.UserID,
+		)...,
+	)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+
+	updatedCount, err := res.RowsAffected()
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.ShortURLEntity{}, ErrUnexpected
+	}
+	if updatedCount == 0 {
+		return entity.ShortURLEntity{}, ErrNotFound
+	}
+
+	return r.GetShortURLByShortURI(ctx, shortURLEntity.ShortURI)
+}
+
+// UpdateShortURLPreview сохраняет превью страницы исходного URL короткой ссылки
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена.
+func (r *SQLiteShortURLRepository) UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	res, err := dbLookup.ExecContext(
+		ctx,
+		sqliteUpdatePreview,
+		sqliteArgs(preview.Title, preview.Description, preview.ImageURL, preview.FetchedAt, shortURI)...,
+	)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	updatedCount, err := res.RowsAffected()
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+	if updatedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteShortURLsByShortURIs удаляет короткие ссылки пользователя по списку shortURI
+// и возвращает результат удаления по каждой из них
+func (r *SQLiteShortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+
+	shortURLStates, err := r.updateShortURLsByShortURIs(ctx, sqliteUpdateIsDeleted, shortURIs, time.Now(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerByShortURI := make(map[string]string, len(shortURLStates))
+	for shortURI, shortURLState := range shortURLStates {
+		ownerByShortURI[shortURI] = shortURLState.userID
+	}
+
+	result := make([]entity.DeleteShortURLResultEntity, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		result = append(result, entity.DeleteShortURLResultEntity{
+			ShortURI: shortURI,
+			Status:   deleteStatus(ownerByShortURI, shortURI, userID),
+		})
+	}
+
+	log.Infow("repository: rows marked as deleted", "shortURIs", shortURIs, "userID", userID)
+
+	return result, nil
+}
+
+// RestoreShortURLsByShortURIs восстанавливает удаленные короткие ссылки пользователя по списку shortURI
+// и возвращает результат восстановления по каждой из них
+func (r *SQLiteShortURLRepository) RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+
+	shortURLStates, err := r.updateShortURLsByShortURIs(ctx, sqliteUpdateIsNotDeleted, shortURIs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.RestoreShortURLResultEntity, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		shortURLState, ok := shortURLStates[shortURI]
+		result = append(result, entity.RestoreShortURLResultEntity{
+			ShortURI: shortURI,
+			Status:   restoreStatus(ok, shortURLState.userID, shortURLState.deleted, userID),
+		})
+	}
+
+	log.Infow("repository: rows marked as not deleted", "shortURIs", shortURIs, "userID", userID)
+
+	return result, nil
+}
+
+// PurgeDeletedShortURLs окончательно удаляет короткие ссылки, удаленные раньше deletedBefore,
+// и возвращает количество удаленных ссылок
+func (r *SQLiteShortURLRepository) PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	res, err := dbLookup.ExecContext(ctx, sqliteDeleteDeletedBefore, sqliteArgs(deletedBefore)...)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return 0, ErrUnexpected
+	}
+
+	purgedCount, err := res.RowsAffected()
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return 0, ErrUnexpected
+	}
+
+	if purgedCount > 0 {
+		if _, err := dbLookup.ExecContext(ctx, sqliteDeleteOrphanClicks); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return 0, ErrUnexpected
+		}
+		if _, err := dbLookup.ExecContext(ctx, sqliteDeleteOrphanCountry); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return 0, ErrUnexpected
+		}
+	}
+
+	return int(purgedCount), nil
+}
+
+// updateShortURLsByShortURIs в одной транзакции выполняет для коротких ссылок по списку shortURIs запрос updateQuery
+// с параметрами args и shortURIs и возвращает состояние коротких ссылок до изменения
+func (r *SQLiteShortURLRepository) updateShortURLsByShortURIs(
+	ctx context.Context,
+	updateQuery string,
+	shortURIs []string,
+	args ...any,
+) (map[string]shortURLState, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(shortURIs)), ", ")
+	shortURIArgs := make([]any, 0, len(shortURIs))
+	for _, shortURI := range shortURIs {
+		shortURIArgs = append(shortURIArgs, shortURI)
+	}
+
+	tx, err := dbLookup.BeginTx(ctx, nil)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+			log.Errorw("repository: error when rollback transaction", "rollbackErr", rollbackErr)
+		}
+	}()
+
+	shortURLStates, err := selectShortURLStates(ctx, tx, fmt.Sprintf(sqliteSelectOwnersByShortURLs, placeholders), shortURIArgs)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(updateQuery, placeholders), sqliteArgs(append(args, shortURIArgs...)...)...); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return shortURLStates, nil
+}
+
+// selectShortURLStates возвращает состояние коротких ссылок, выбранных запросом query в транзакции tx
+//
+// Выборка читается полностью до возврата, чтобы следующий запрос транзакции не выполнялся при открытом курсоре.
+func selectShortURLStates(ctx context.Context, tx *sql.Tx, query string, args []any) (map[string]shortURLState, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+		}
+	}()
+
+	shortURLStates := make(map[string]shortURLState, len(args))
+	for rows.Next() {
+		var shortURI string
+		var state shortURLState
+		if err := rows.Scan(&shortURI, &state.userID, &state.deleted); err != nil {
+			return nil, err
+		}
+
+		shortURLStates[shortURI] = state
+	}
+
+	return shortURLStates, rows.Err()
+}
+
+// SaveDeleteJob сохраняет задачу на удаление коротких ссылок
+func (r *SQLiteShortURLRepository) SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	shortURIsJSON, err := json.Marshal(deleteJobEntity.ShortURIs)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	resultsJSON, err := json.Marshal(deleteJobEntity.Results)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	_, err = dbLookup.ExecContext(
+		ctx,
+		sqliteUpsertDeleteJob,
+		sqliteArgs(
+			deleteJobEntity.ID,
+			deleteJobEntity.UserID,
+			deleteJobEntity.Status,
+			string(shortURIsJSON),
+			string(resultsJSON),
+			deleteJobEntity.CreatedAt,
+			deleteJobEntity.UpdatedAt,
+		)...,
+	)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// GetDeleteJobByID возвращает задачу на удаление коротких ссылок по ее идентификатору
+func (r *SQLiteShortURLRepository) GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	deleteJobEntity, err := scanDeleteJob(dbLookup.QueryRowContext(ctx, sqliteSelectDeleteJobByID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.DeleteJobEntity{}, ErrNotFound
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.DeleteJobEntity{}, ErrUnexpected
+	}
+
+	return deleteJobEntity, nil
+}
+
+// GetDeleteJobsByStatus возвращает список задач на удаление коротких ссылок в статусе status
+func (r *SQLiteShortURLRepository) GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	rows, err := dbLookup.QueryContext(ctx, sqliteSelectDeleteJobsByStatus, status)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+		}
+	}()
+
+	result := make([]entity.DeleteJobEntity, 0)
+	for rows.Next() {
+		deleteJobEntity, err := scanDeleteJob(rows)
+		if err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
+		result = append(result, deleteJobEntity)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return result, nil
+}
+
+// IncrementSplitClicks увеличивает на единицу количество переходов по короткой ссылке shortURI на вариант variant
+func (r *SQLiteShortURLRepository) IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	if _, err := dbLookup.ExecContext(ctx, sqliteIncrementSplitClicks, shortURI, variant); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// IncrementCountryClicks увеличивает на единицу количество переходов по короткой ссылке shortURI из страны country
+func (r *SQLiteShortURLRepository) IncrementCountryClicks(ctx context.Context, shortURI string, country string) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	if _, err := dbLookup.ExecContext(ctx, sqliteIncrementCountryClicks, shortURI, country); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// ConsumeShortURLClick учитывает переход по короткой ссылке shortURI с ограничением количества переходов,
+// для коротких ссылок без ограничения переход не учитывается
+//
+// Возвращает ErrClicksExhausted, если переходы по короткой ссылке исчерпаны, ErrNotFound - если короткая ссылка не найдена.
+func (r *SQLiteShortURLRepository) ConsumeShortURLClick(ctx context.Context, shortURI string) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	res, err := dbLookup.ExecContext(ctx, sqliteConsumeClick, shortURI)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	consumedCount, err := res.RowsAffected()
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+	if consumedCount > 0 {
+		return nil
+	}
+
+	var maxClicks int
+	err = dbLookup.QueryRowContext(ctx, sqliteSelectMaxClicks, shortURI).Scan(&maxClicks)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+	if maxClicks == 0 {
+		return nil
+	}
+
+	return ErrClicksExhausted
+}
+
+// GetSplitClicks возвращает количество переходов по короткой ссылке shortURI по вариантам перенаправления
+func (r *SQLiteShortURLRepository) GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	return r.getClicks(ctx, sqliteSelectSplitClicks, shortURI)
+}
+
+// GetCountryClicks возвращает количество переходов по короткой ссылке shortURI по странам
+func (r *SQLiteShortURLRepository) GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	return r.getClicks(ctx, sqliteSelectCountryClicks, shortURI)
+}
+
+// getClicks возвращает счетчики переходов по короткой ссылке shortURI, выбранные запросом query
+func (r *SQLiteShortURLRepository) getClicks(ctx context.Context, query string, shortURI string) (map[string]int64, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	rows, err := dbLookup.QueryContext(ctx, query, shortURI)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+		}
+	}()
+
+	result := make(map[string]int64)
+	for rows.Next() {
+		var key string
+		var clicks int64
+		if err := rows.Scan(&key, &clicks); err != nil {
+			log.Errorw("repository: unexpected error", "err", err)
+			return nil, ErrUnexpected
+		}
+
+		result[key] = clicks
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return nil, ErrUnexpected
+	}
+
+	return result, nil
+}
+
+// GetUserSettings возвращает настройки пользователя userID, для пользователя без сохраненных настроек
+// возвращаются настройки по умолчанию
+func (r *SQLiteShortURLRepository) GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	var userSettingsEntity entity.UserSettingsEntity
+	err := dbLookup.QueryRowContext(ctx, sqliteSelectUserSettings, userID).Scan(
+		&userSettingsEntity.UserID,
+		&userSettingsEntity.FallbackURL,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.UserSettingsEntity{UserID: userID}, nil
+		}
+
+		log.Errorw("repository: unexpected error", "err", err)
+		return entity.UserSettingsEntity{}, ErrUnexpected
+	}
+
+	return userSettingsEntity, nil
+}
+
+// SaveUserSettings сохраняет настройки пользователя
+func (r *SQLiteShortURLRepository) SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error {
+	dbLookup := r.dbLookup.GetDB()
+
+	_, err := dbLookup.ExecContext(ctx, sqliteUpsertUserSettings, userSettingsEntity.UserID, userSettingsEntity.FallbackURL)
+	if err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// GetStats возвращает статистику по сервису
+// urlCount - количество коротких ссылок в сервисе
+// userCount - количество пользователей в сервисе
+func (r *SQLiteShortURLRepository) GetStats(ctx context.Context) (urlCount int, userCount int, err error) {
+	dbLookup := r.dbLookup.GetDB()
+
+	if err := dbLookup.QueryRowContext(ctx, sqliteStats).Scan(&urlCount, &userCount); err != nil {
+		log.Errorw("repository: unexpected error", "err", err)
+		return 0, 0, ErrUnexpected
+	}
+
+	return urlCount, userCount, nil
+}
+
+// sqliteInsertArgs возвращает параметры запроса sqliteInsertRow для короткой ссылки shortURLEntity
+func sqliteInsertArgs(shortURLEntity *entity.ShortURLEntity) ([]any, error) {
+	tagsJSON, err := marshalTags(shortURLEntity.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	utmParamsJSON, err := marshalUTMParams(shortURLEntity.UTMParams)
+	if err != nil {
+		return nil, err
+	}
+
+	targetingRulesJSON, err := marshalTargetingRules(shortURLEntity.TargetingRules)
+	if err != nil {
+		return nil, err
+	}
+
+	splitVariantsJSON, err := marshalSplitVariants(shortURLEntity.SplitVariants)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqliteArgs(
+		shortURLEntity.UUID,
+		shortURLEntity.ShortURI,
+		shortURLEntity.LongURL,
+		shortURLEntity.UserID,
+		shortURLEntity.Deleted,
+		shortURLEntity.DeletedAt,
+		shortURLEntity.CreatedAt,
+		shortURLEntity.Title,
+		shortURLEntity.Description,
+		shortURLEntity.Notes,
+		tagsJSON,
+		shortURLEntity.RedirectStatus,
+		shortURLEntity.QueryPassthrough,
+		shortURLEntity.PathPassthrough,
+		utmParamsJSON,
+		targetingRulesJSON,
+		splitVariantsJSON,
+		shortURLEntity.PasswordHash,
+		shortURLEntity.MaxClicks,
+		shortURLEntity.NotBefore,
+		shortURLEntity.NotAfter,
+		shortURLEntity.Disabled,
+		shortURLEntity.FallbackURL,
+		shortDomainOf(shortURLEntity.ShortURI),
+		shortURLEntity.ClickCount,
+		shortURLEntity.PreviewTitle,
+		shortURLEntity.PreviewDescription,
+		shortURLEntity.PreviewImageURL,
+		shortURLEntity.PreviewFetchedAt,
+	), nil
+}
+
+// sqliteArgs приводит время в параметрах запроса к UTC: SQLite хранит время строкой,
+// и сравнение строк совпадает со сравнением моментов времени только в одном часовом поясе
+func sqliteArgs(args ...any) []any {
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case time.Time:
+			args[i] = value.UTC()
+		case *time.Time:
+			if value != nil {
+				args[i] = value.UTC()
+			}
+		}
+	}
+
+	return args
+}
+
+// isSQLiteUniqueViolation проверяет, что ошибка err - нарушение уникальности ключа или индекса
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vkhrushchev/urlshortener/internal/app/db"
+)
+
+// SQLiteShortURLRepositoryTestSuite выполняет тесты DBShortURLRepositoryTestSuite для SQLiteShortURLRepository
+type SQLiteShortURLRepositoryTestSuite struct {
+	DBShortURLRepositoryTestSuite
+	dbLookup *db.DBLookup
+}
+
+func (s *SQLiteShortURLRepositoryTestSuite) SetupSuite() {
+	dsnString := db.SQLiteDSNScheme + filepath.Join(s.T().TempDir(), "urlshortener.db")
+	dbLookup, err := db.NewDBLookup(dsnString)
+	if err != nil {
+		s.Fail("repository: failed to create dbLookup: %v", err)
+	}
+
+	if err := dbLookup.InitDB(context.Background()); err != nil {
+		s.Fail("repository: failed to init dbLookup: %v", err)
+	}
+
+	s.dbLookup = dbLookup
+	s.repository = NewSQLiteShortURLRepository(dbLookup)
+}
+
+func (s *SQLiteShortURLRepositoryTestSuite) TearDownSuite() {
+	if err := s.dbLookup.GetDB().Close(); err != nil {
+		s.Fail("repository: failed to close database: %v", err)
+	}
+}
+
+func TestSQLiteShortURLRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLiteShortURLRepositoryTestSuite))
+}
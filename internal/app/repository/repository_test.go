@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+	"github.com/vkhrushchev/urlshortener/internal/util"
+)
+
+// consumeShortURLClickConcurrently одновременно выполняет attempts переходов по короткой ссылке shortURI
+// и возвращает количество учтенных переходов и количество переходов, отклоненных с ErrClicksExhausted
+func consumeShortURLClickConcurrently(
+	repository interface {
+		ConsumeShortURLClick(ctx context.Context, shortURI string) error
+	},
+	shortURI string,
+	attempts int,
+) (consumed int64, exhausted int64, err error) {
+	var wg sync.WaitGroup
+	var unexpectedErr atomic.Value
+
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			err := repository.ConsumeShortURLClick(context.Background(), shortURI)
+			if err == nil {
+				atomic.AddInt64(&consumed, 1)
+			} else if errors.Is(err, ErrClicksExhausted) {
+				atomic.AddInt64(&exhausted, 1)
+			} else {
+				unexpectedErr.Store(err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if storedErr, ok := unexpectedErr.Load().(error); ok {
+		return consumed, exhausted, storedErr
+	}
+
+	return consumed, exhausted, nil
+}
+
+// assertSaveShortURLShortURIRace проверяет, что из одновременно сохраняемых коротких ссылок с одинаковым ключом
+// сохраняется только одна, а остальные получают ErrConflict без короткой ссылки и не перезаписывают сохраненную
+func assertSaveShortURLShortURIRace(
+	t *testing.T,
+	repository interface {
+		SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error)
+		GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
+		GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+	},
+) {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	testShortURI := util.RandStringRunes(10)
+
+	const saveCount = 10
+	longURLs := make([]string, saveCount)
+	errs := make([]error, saveCount)
+	savedShortURLs := make([]*entity.ShortURLEntity, saveCount)
+	var wg sync.WaitGroup
+	for i := 0; i < saveCount; i++ {
+		longURLs[i] = "https://mail.ru/" + util.RandStringRunes(10)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			savedShortURLs[i], errs[i] = repository.SaveShortURL(testCtx, &entity.ShortURLEntity{
+				UUID:     uuid.NewString(),
+				ShortURI: testShortURI,
+				LongURL:  longURLs[i],
+				UserID:   testUserID,
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		if err == nil {
+			assert.Equal(t, -1, winner, "only one short url must be saved with the same short uri")
+			winner = i
+			continue
+		}
+
+		assert.ErrorIs(t, err, ErrConflict, "expected ErrConflict, got %v", err)
+		assert.Nil(t, savedShortURLs[i], "short uri conflict must not return other short url")
+	}
+	require.NotEqual(t, -1, winner, "one short url must be saved")
+
+	shortURLEntity, err := repository.GetShortURLByShortURI(testCtx, testShortURI)
+	require.NoError(t, err)
+	assert.Equal(t, longURLs[winner], shortURLEntity.LongURL, "saved short url must not be overwritten")
+
+	userShortURLs, err := repository.GetShortURLsByUserID(testCtx, testUserID)
+	require.NoError(t, err)
+	assert.Len(t, userShortURLs, 1, "user must have only saved short url")
+}
+
+func TestDomainRegexp(t *testing.T) {
+	testCases := []struct {
+		longURL  string
+		expected bool
+	}{
+		{longURL: "https://ya.ru", expected: true},
+		{longURL: "https://YA.RU/search?q=1", expected: true},
+		{longURL: "http://user@mail.ya.ru:8080/path", expected: true},
+		{longURL: "https://notya.ru", expected: false},
+		{longURL: "https://ya.ru.evil.com", expected: false},
+		{longURL: "https://yaxru", expected: false},
+		{longURL: "https://google.com/?r=ya.ru", expected: false},
+	}
+
+	// в БД выражение применяется оператором ~*, поэтому сравнение выполняется без учета регистра
+	domainRe := regexp.MustCompile("(?i)" + domainRegexp("ya.ru"))
+	for _, tc := range testCases {
+		t.Run(tc.longURL, func(t *testing.T) {
+			assert.Equal(t, tc.expected, matchDomain(tc.longURL, "ya.ru"))
+			assert.Equal(t, tc.expected, domainRe.MatchString(tc.longURL))
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_a\\b`, escapeLike(`100%_a\b`))
+}
@@ -0,0 +1,148 @@
+// Package shortdomain описывает домены, на которых обслуживаются короткие ссылки
+//
+// Помимо домена по умолчанию из базового URL сервиса могут быть настроены дополнительные домены.
+// Идентификаторы коротких ссылок уникальны в пределах домена, поэтому короткая ссылка хранится по ключу,
+// который для домена по умолчанию совпадает с идентификатором, а для дополнительного домена
+// имеет вид "<идентификатор>@<домен>".
+package shortdomain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/vkhrushchev/urlshortener/internal/util"
+)
+
+// ErrInvalidDomain - некорректное имя домена
+// ErrUnknownDomain - домен не настроен
+var (
+	ErrInvalidDomain = errors.New("invalid domain")
+	ErrUnknownDomain = errors.New("unknown domain")
+)
+
+// Separator - разделитель идентификатора короткой ссылки и домена в ключе короткой ссылки
+const Separator = "@"
+
+// DomainMaxLength - максимальная длина имени домена
+const DomainMaxLength = 253
+
+// CheckDomain проверяет, что domain - имя хоста с необязательным портом, без схемы и пути
+func CheckDomain(domain string) error {
+	if domain == "" || len(domain) > DomainMaxLength {
+		return fmt.Errorf("%w: domain length must be from 1 to %d", ErrInvalidDomain, DomainMaxLength)
+	}
+
+	parsedURL, err := url.Parse("//" + domain)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDomain, err)
+	}
+	if parsedURL.Host != domain || parsedURL.User != nil || parsedURL.Path != "" || strings.Contains(domain, Separator) {
+		return fmt.Errorf("%w: %q must be host name without scheme and path", ErrInvalidDomain, domain)
+	}
+
+	return nil
+}
+
+// Key возвращает ключ короткой ссылки shortURI на домене domain, "" - домен по умолчанию
+func Key(domain string, shortURI string) string {
+	if domain == "" {
+		return shortURI
+	}
+
+	return shortURI + Separator + domain
+}
+
+// Split разбирает ключ короткой ссылки на домен и идентификатор короткой ссылки, "" - домен по умолчанию
+func Split(key string) (domain string, shortURI string) {
+	shortURI, domain, _ = strings.Cut(key, Separator)
+	return domain, shortURI
+}
+
+// Domains - домен по умолчанию и дополнительные домены коротких ссылок
+type Domains struct {
+	baseURL     string          // Базовый URL коротких ссылок домена по умолчанию
+	scheme      string          // Схема коротких ссылок дополнительных доменов
+	defaultHost string          // Домен по умолчанию
+	domains     map[string]bool // Дополнительные домены
+}
+
+// NewDomains создает экземпляр Domains
+//
+//	baseURL - базовый URL коротких ссылок домена по умолчанию, его схема используется и для дополнительных доменов
+//	domains - дополнительные домены, имена проверяются CheckDomain при чтении конфигурации
+func NewDomains(baseURL string, domains []string) *Domains {
+	d := &Domains{
+		baseURL: baseURL,
+		scheme:  "http",
+		domains: make(map[string]bool, len(domains)),
+	}
+
+	if parsedURL, err := url.Parse(baseURL); err == nil {
+		if parsedURL.Scheme != "" {
+			d.scheme = parsedURL.Scheme
+		}
+		d.defaultHost = strings.ToLower(parsedURL.Host)
+	}
+
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" && domain != d.defaultHost {
+			d.domains[domain] = true
+		}
+	}
+
+	return d
+}
+
+// Resolve возвращает нормализованное имя домена, выбранного при создании короткой ссылки,
+// "" - домен по умолчанию, ErrUnknownDomain - домен не настроен
+func (d *Domains) Resolve(domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if domain == "" || domain == d.defaultHost {
+		return "", nil
+	}
+	if !d.domains[domain] {
+		return "", fmt.Errorf("%w: %q", ErrUnknownDomain, domain)
+	}
+
+	return domain, nil
+}
+
+// HostDomain возвращает дополнительный домен, на который отправлен запрос с заголовком Host host,
+// "" - запрос относится к домену по умолчанию
+func (d *Domains) HostDomain(host string) string {
+	host = strings.ToLower(host)
+	if d.domains[host] {
+		return host
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil && d.domains[hostname] {
+		return hostname
+	}
+
+	return ""
+}
+
+// KeyByHost возвращает ключ короткой ссылки shortURI для запроса с заголовком Host host
+//
+// Если shortURI сам является ключом короткой ссылки на дополнительном домене, возвращается false,
+// чтобы короткая ссылка не была доступна на чужом домене.
+func (d *Domains) KeyByHost(host string, shortURI string) (string, bool) {
+	if strings.Contains(shortURI, Separator) {
+		return "", false
+	}
+
+	return Key(d.HostDomain(host), shortURI), true
+}
+
+// ShortURL возвращает полную короткую ссылку по ключу короткой ссылки key
+func (d *Domains) ShortURL(key string) string {
+	domain, shortURI := Split(key)
+	if domain == "" {
+		return util.GetShortURL(d.baseURL, shortURI)
+	}
+
+	return d.scheme + "://" + domain + "/" + shortURI
+}
@@ -0,0 +1,203 @@
+package shortdomain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDomain(t *testing.T) {
+	testCases := []struct {
+		name        string
+		domain      string
+		expectedErr error
+	}{
+		{
+			name:   "host",
+			domain: "go.brand-a.com",
+		},
+		{
+			name:   "host with port",
+			domain: "localhost:8080",
+		},
+		{
+			name:        "empty domain",
+			domain:      "",
+			expectedErr: ErrInvalidDomain,
+		},
+		{
+			name:        "domain with scheme",
+			domain:      "https://brand-b.link",
+			expectedErr: ErrInvalidDomain,
+		},
+		{
+			name:        "domain with path",
+			domain:      "brand-b.link/s",
+			expectedErr: ErrInvalidDomain,
+		},
+		{
+			name:        "domain with user",
+			domain:      "user@brand-b.link",
+			expectedErr: ErrInvalidDomain,
+		},
+		{
+			name:        "too long domain",
+			domain:      strings.Repeat("a", DomainMaxLength+1),
+			expectedErr: ErrInvalidDomain,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckDomain(tc.domain)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestKeyAndSplit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		domain   string
+		shortURI string
+		key      string
+	}{
+		{
+			name:     "default domain",
+			shortURI: "abc",
+			key:      "abc",
+		},
+		{
+			name:     "additional domain",
+			domain:   "brand-b.link",
+			shortURI: "abc",
+			key:      "abc@brand-b.link",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := Key(tc.domain, tc.shortURI)
+			assert.Equal(t, tc.key, key)
+
+			domain, shortURI := Split(key)
+			assert.Equal(t, tc.domain, domain)
+			assert.Equal(t, tc.shortURI, shortURI)
+		})
+	}
+}
+
+func TestDomains_Resolve(t *testing.T) {
+	domains := NewDomains("https://sho.rt", []string{"go.brand-a.com", " Brand-B.link "})
+
+	testCases := []struct {
+		name        string
+		domain      string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "empty domain",
+			domain:   "",
+			expected: "",
+		},
+		{
+			name:     "default domain",
+			domain:   "sho.rt",
+			expected: "",
+		},
+		{
+			name:     "additional domain",
+			domain:   "BRAND-B.LINK",
+			expected: "brand-b.link",
+		},
+		{
+			name:        "unknown domain",
+			domain:      "brand-c.io",
+			expectedErr: ErrUnknownDomain,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			domain, err := domains.Resolve(tc.domain)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, domain)
+		})
+	}
+}
+
+func TestDomains_KeyByHost(t *testing.T) {
+	domains := NewDomains("https://sho.rt", []string{"go.brand-a.com", "brand-b.link"})
+
+	testCases := []struct {
+		name       string
+		host       string
+		shortURI   string
+		expected   string
+		expectedOk bool
+	}{
+		{
+			name:       "default host",
+			host:       "sho.rt",
+			shortURI:   "abc",
+			expected:   "abc",
+			expectedOk: true,
+		},
+		{
+			name:       "unknown host",
+			host:       "127.0.0.1:8080",
+			shortURI:   "abc",
+			expected:   "abc",
+			expectedOk: true,
+		},
+		{
+			name:       "additional domain",
+			host:       "Brand-B.link",
+			shortURI:   "abc",
+			expected:   "abc@brand-b.link",
+			expectedOk: true,
+		},
+		{
+			name:       "additional domain with port",
+			host:       "go.brand-a.com:443",
+			shortURI:   "abc",
+			expected:   "abc@go.brand-a.com",
+			expectedOk: true,
+		},
+		{
+			name:       "key in path",
+			host:       "sho.rt",
+			shortURI:   "abc@brand-b.link",
+			expectedOk: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, ok := domains.KeyByHost(tc.host, tc.shortURI)
+
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expected, key)
+		})
+	}
+}
+
+func TestDomains_ShortURL(t *testing.T) {
+	domains := NewDomains("https://sho.rt/s/", []string{"brand-b.link"})
+
+	assert.Equal(t, "https://sho.rt/s/abc", domains.ShortURL("abc"))
+	assert.Equal(t, "https://brand-b.link/abc", domains.ShortURL("abc@brand-b.link"))
+}
@@ -0,0 +1,138 @@
+// Package split распределяет переходы по короткой ссылке между несколькими адресами перенаправления
+// (вариантами A/B-теста) пропорционально их весам
+package split
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidVariants - некорректные варианты перенаправления
+var ErrInvalidVariants = errors.New("invalid split variants")
+
+// VariantsMinCount - минимальное количество вариантов перенаправления короткой ссылки
+// VariantsMaxCount - максимальное количество вариантов перенаправления короткой ссылки
+// WeightMax - максимальный вес варианта
+// URLMaxLength - максимальная длина адреса перенаправления варианта
+const (
+	VariantsMinCount = 2
+	VariantsMaxCount = 10
+	WeightMax        = 1000
+	URLMaxLength     = 2048
+)
+
+// CookieName - имя cookie с вариантом, назначенным посетителю, cookie ограничивается путем короткой ссылки
+// CookieMaxAge - срок хранения cookie с назначенным вариантом в секундах
+const (
+	CookieName   = "split_variant"
+	CookieMaxAge = 365 * 24 * 60 * 60
+)
+
+// nameRegexp - имя варианта: латинские буквы в нижнем регистре, цифры, "-" и "_"
+var nameRegexp = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// intN - источник случайных чисел для выбора варианта, подменяется в тестах
+var intN = rand.IntN
+
+// Variant структура с описанием варианта перенаправления
+//
+// Name идентифицирует вариант: по нему закрепляется выбор посетителя и считаются переходы, поэтому
+// при изменении весов имя сохраняется. Вариант с нулевым весом не назначается новым посетителям.
+type Variant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// NormalizeVariants проверяет варианты перенаправления и приводит их имена к нижнему регистру
+func NormalizeVariants(variants []Variant) ([]Variant, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	if len(variants) < VariantsMinCount || len(variants) > VariantsMaxCount {
+		return nil, fmt.Errorf("%w: number of variants must be from %d to %d", ErrInvalidVariants, VariantsMinCount, VariantsMaxCount)
+	}
+
+	result := make([]Variant, 0, len(variants))
+	totalWeight := 0
+	names := make(map[string]struct{}, len(variants))
+	for i, variant := range variants {
+		variant.Name = strings.ToLower(strings.TrimSpace(variant.Name))
+		variant.URL = strings.TrimSpace(variant.URL)
+
+		if !nameRegexp.MatchString(variant.Name) {
+			return nil, fmt.Errorf("%w: variant %d has invalid name %q", ErrInvalidVariants, i, variant.Name)
+		}
+		if _, ok := names[variant.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate variant name %q", ErrInvalidVariants, variant.Name)
+		}
+		if variant.Weight < 0 || variant.Weight > WeightMax {
+			return nil, fmt.Errorf("%w: variant %q weight must be from 0 to %d", ErrInvalidVariants, variant.Name, WeightMax)
+		}
+		if err := checkURL(variant.URL); err != nil {
+			return nil, fmt.Errorf("%w: variant %q: %v", ErrInvalidVariants, variant.Name, err)
+		}
+
+		names[variant.Name] = struct{}{}
+		totalWeight += variant.Weight
+		result = append(result, variant)
+	}
+	if totalWeight == 0 {
+		return nil, fmt.Errorf("%w: at least one variant must have positive weight", ErrInvalidVariants)
+	}
+
+	return result, nil
+}
+
+// Choose возвращает вариант с именем assigned, если он есть среди вариантов и имеет положительный вес,
+// иначе - случайный вариант с вероятностью, пропорциональной весу
+func Choose(variants []Variant, assigned string) (Variant, bool) {
+	totalWeight := 0
+	for _, variant := range variants {
+		if variant.Weight <= 0 {
+			continue
+		}
+		if variant.Name == assigned {
+			return variant, true
+		}
+
+		totalWeight += variant.Weight
+	}
+	if totalWeight == 0 {
+		return Variant{}, false
+	}
+
+	n := intN(totalWeight)
+	for _, variant := range variants {
+		if variant.Weight <= 0 {
+			continue
+		}
+		if n < variant.Weight {
+			return variant, true
+		}
+
+		n -= variant.Weight
+	}
+
+	return Variant{}, false
+}
+
+func checkURL(rawURL string) error {
+	if len(rawURL) > URLMaxLength {
+		return fmt.Errorf("url is longer than %d", URLMaxLength)
+	}
+
+	parsedURL, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return err
+	}
+	if (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return fmt.Errorf("url %q must be absolute http(s) url", rawURL)
+	}
+
+	return nil
+}
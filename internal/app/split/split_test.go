@@ -0,0 +1,145 @@
+package split
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeVariants(t *testing.T) {
+	testCases := []struct {
+		name             string
+		variants         []Variant
+		expectedVariants []Variant
+		expectedErr      error
+	}{
+		{
+			name: "variants normalized",
+			variants: []Variant{
+				{Name: " A ", URL: " https://example.com/a ", Weight: 70},
+				{Name: "b", URL: "https://example.com/b", Weight: 30},
+				{Name: "c", URL: "https://example.com/c", Weight: 0},
+			},
+			expectedVariants: []Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: 70},
+				{Name: "b", URL: "https://example.com/b", Weight: 30},
+				{Name: "c", URL: "https://example.com/c", Weight: 0},
+			},
+		},
+		{
+			name: "no variants",
+		},
+		{
+			name:        "single variant",
+			variants:    []Variant{{Name: "a", URL: "https://example.com/a", Weight: 1}},
+			expectedErr: ErrInvalidVariants,
+		},
+		{
+			name: "invalid name",
+			variants: []Variant{
+				{Name: "вариант", URL: "https://example.com/a", Weight: 1},
+				{Name: "b", URL: "https://example.com/b", Weight: 1},
+			},
+			expectedErr: ErrInvalidVariants,
+		},
+		{
+			name: "duplicate name",
+			variants: []Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: 1},
+				{Name: "A", URL: "https://example.com/b", Weight: 1},
+			},
+			expectedErr: ErrInvalidVariants,
+		},
+		{
+			name: "negative weight",
+			variants: []Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: -1},
+				{Name: "b", URL: "https://example.com/b", Weight: 1},
+			},
+			expectedErr: ErrInvalidVariants,
+		},
+		{
+			name: "too big weight",
+			variants: []Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: WeightMax + 1},
+				{Name: "b", URL: "https://example.com/b", Weight: 1},
+			},
+			expectedErr: ErrInvalidVariants,
+		},
+		{
+			name: "all weights are zero",
+			variants: []Variant{
+				{Name: "a", URL: "https://example.com/a"},
+				{Name: "b", URL: "https://example.com/b"},
+			},
+			expectedErr: ErrInvalidVariants,
+		},
+		{
+			name: "relative url",
+			variants: []Variant{
+				{Name: "a", URL: "/a", Weight: 1},
+				{Name: "b", URL: "https://example.com/b", Weight: 1},
+			},
+			expectedErr: ErrInvalidVariants,
+		},
+		{
+			name:        "too many variants",
+			variants:    make([]Variant, VariantsMaxCount+1),
+			expectedErr: ErrInvalidVariants,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			variants, err := NormalizeVariants(tc.variants)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVariants, variants)
+		})
+	}
+}
+
+func TestChoose(t *testing.T) {
+	variants := []Variant{
+		{Name: "a", URL: "https://example.com/a", Weight: 70},
+		{Name: "b", URL: "https://example.com/b", Weight: 30},
+		{Name: "c", URL: "https://example.com/c", Weight: 0},
+	}
+
+	testCases := []struct {
+		name         string
+		assigned     string
+		random       int
+		expectedName string
+	}{
+		{name: "first variant by weight", random: 0, expectedName: "a"},
+		{name: "last number of first variant", random: 69, expectedName: "a"},
+		{name: "second variant by weight", random: 70, expectedName: "b"},
+		{name: "assigned variant", assigned: "b", random: 0, expectedName: "b"},
+		{name: "assigned variant with zero weight", assigned: "c", random: 99, expectedName: "b"},
+		{name: "unknown assigned variant", assigned: "d", random: 10, expectedName: "a"},
+	}
+
+	defer func(originalIntN func(n int) int) { intN = originalIntN }(intN)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			intN = func(n int) int {
+				require.Equal(t, 100, n, "random number must be chosen from total weight")
+				return tc.random
+			}
+
+			variant, ok := Choose(variants, tc.assigned)
+
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedName, variant.Name)
+		})
+	}
+
+	_, ok := Choose(nil, "")
+	assert.False(t, ok, "variant must not be chosen without variants")
+}
@@ -0,0 +1,252 @@
+// Package targeting выбирает адрес перенаправления по короткой ссылке в зависимости от платформы
+// и типа устройства клиента, определяемых по заголовку User-Agent, языка из заголовка Accept-Language
+// и страны клиента
+package targeting
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRules - некорректные правила таргетинга
+var ErrInvalidRules = errors.New("invalid targeting rules")
+
+// PlatformIOS - iOS и iPadOS
+// PlatformAndroid - Android
+// PlatformWindows - Windows
+// PlatformMacOS - macOS
+// PlatformLinux - Linux, кроме Android
+// PlatformChromeOS - ChromeOS
+// PlatformOther - платформа не определена
+const (
+	PlatformIOS      = "ios"
+	PlatformAndroid  = "android"
+	PlatformWindows  = "windows"
+	PlatformMacOS    = "macos"
+	PlatformLinux    = "linux"
+	PlatformChromeOS = "chromeos"
+	PlatformOther    = "other"
+)
+
+// DeviceMobile - телефон
+// DeviceTablet - планшет
+// DeviceDesktop - компьютер или устройство, тип которого не определен
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+)
+
+// RulesMaxCount - максимальное количество правил таргетинга короткой ссылки
+// URLMaxLength - максимальная длина адреса перенаправления правила
+const (
+	RulesMaxCount = 20
+	URLMaxLength  = 2048
+)
+
+var platforms = []string{PlatformIOS, PlatformAndroid, PlatformWindows, PlatformMacOS, PlatformLinux, PlatformChromeOS, PlatformOther}
+var devices = []string{DeviceMobile, DeviceTablet, DeviceDesktop}
+
+// localeRegexp - язык в виде тега BCP 47: код языка и необязательные подтеги региона, письменности и т.п.
+var localeRegexp = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// countryRegexp - код страны ISO 3166-1 alpha-2
+var countryRegexp = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Rule структура с описанием правила таргетинга: перенаправление на URL, если клиент соответствует
+// всем заданным условиям, пустое условие соответствует любому клиенту
+type Rule struct {
+	Platform string `json:"platform,omitempty"`
+	Device   string `json:"device,omitempty"`
+	Locale   string `json:"locale,omitempty"`
+	Country  string `json:"country,omitempty"`
+	URL      string `json:"url"`
+}
+
+// Client структура с описанием клиента, выполняющего переход по короткой ссылке
+type Client struct {
+	Platform string
+	Device   string
+	// Locale - предпочитаемый язык клиента, пустая строка - язык не указан
+	Locale string
+	// Country - код страны клиента ISO 3166-1 alpha-2, пустая строка - страна не определена
+	Country string
+}
+
+// NormalizeRules проверяет правила таргетинга, приводит условия к нижнему регистру, а код страны - к верхнему
+func NormalizeRules(rules []Rule) ([]Rule, error) {
+	if len(rules) > RulesMaxCount {
+		return nil, fmt.Errorf("%w: more than %d rules", ErrInvalidRules, RulesMaxCount)
+	}
+
+	var result []Rule
+	for i, rule := range rules {
+		rule.Platform = strings.ToLower(strings.TrimSpace(rule.Platform))
+		rule.Device = strings.ToLower(strings.TrimSpace(rule.Device))
+		rule.Locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(rule.Locale), "_", "-"))
+		rule.Country = strings.ToUpper(strings.TrimSpace(rule.Country))
+		rule.URL = strings.TrimSpace(rule.URL)
+
+		if rule.Platform == "" && rule.Device == "" && rule.Locale == "" && rule.Country == "" {
+			return nil, fmt.Errorf("%w: rule %d has no conditions", ErrInvalidRules, i)
+		}
+		if rule.Platform != "" && !slices.Contains(platforms, rule.Platform) {
+			return nil, fmt.Errorf("%w: rule %d has unknown platform %q", ErrInvalidRules, i, rule.Platform)
+		}
+		if rule.Device != "" && !slices.Contains(devices, rule.Device) {
+			return nil, fmt.Errorf("%w: rule %d has unknown device %q", ErrInvalidRules, i, rule.Device)
+		}
+		if rule.Locale != "" && !localeRegexp.MatchString(rule.Locale) {
+			return nil, fmt.Errorf("%w: rule %d has invalid locale %q", ErrInvalidRules, i, rule.Locale)
+		}
+		if rule.Country != "" && !countryRegexp.MatchString(rule.Country) {
+			return nil, fmt.Errorf("%w: rule %d has invalid country %q", ErrInvalidRules, i, rule.Country)
+		}
+		if err := checkURL(rule.URL); err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidRules, i, err)
+		}
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// Match возвращает URL первого правила, которому соответствует клиент
+func Match(rules []Rule, client Client) (string, bool) {
+	for _, rule := range rules {
+		if rule.Platform != "" && rule.Platform != client.Platform {
+			continue
+		}
+		if rule.Device != "" && rule.Device != client.Device {
+			continue
+		}
+		if rule.Locale != "" && client.Locale != rule.Locale && !strings.HasPrefix(client.Locale, rule.Locale+"-") {
+			continue
+		}
+		if rule.Country != "" && rule.Country != client.Country {
+			continue
+		}
+
+		return rule.URL, true
+	}
+
+	return "", false
+}
+
+// HasCountryRules проверяет, есть ли среди правил правила с условием на страну клиента
+func HasCountryRules(rules []Rule) bool {
+	for _, rule := range rules {
+		if rule.Country != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseClient определяет платформу и тип устройства клиента по заголовку User-Agent
+// и предпочитаемый язык по заголовку Accept-Language
+func ParseClient(userAgent string, acceptLanguage string) Client {
+	platform := parsePlatform(userAgent)
+
+	return Client{
+		Platform: platform,
+		Device:   parseDevice(userAgent, platform),
+		Locale:   parseLocale(acceptLanguage),
+	}
+}
+
+func parsePlatform(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"), strings.Contains(userAgent, "iPod"):
+		return PlatformIOS
+	case strings.Contains(userAgent, "Android"):
+		return PlatformAndroid
+	case strings.Contains(userAgent, "Windows"):
+		return PlatformWindows
+	case strings.Contains(userAgent, "CrOS"):
+		return PlatformChromeOS
+	case strings.Contains(userAgent, "Macintosh"), strings.Contains(userAgent, "Mac OS X"):
+		return PlatformMacOS
+	case strings.Contains(userAgent, "Linux"):
+		return PlatformLinux
+	default:
+		return PlatformOther
+	}
+}
+
+// parseDevice определяет тип устройства: планшеты Android, в отличие от телефонов, не указывают "Mobile"
+func parseDevice(userAgent string, platform string) string {
+	switch {
+	case strings.Contains(userAgent, "iPad"), strings.Contains(userAgent, "Tablet"):
+		return DeviceTablet
+	case platform == PlatformAndroid && !strings.Contains(userAgent, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(userAgent, "Mobi"), strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPod"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+// parseLocale возвращает язык с наибольшим весом из заголовка Accept-Language
+func parseLocale(acceptLanguage string) string {
+	type weightedLocale struct {
+		locale string
+		weight float64
+	}
+
+	var locales []weightedLocale
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale = strings.ToLower(strings.TrimSpace(locale))
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if weight, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		locales = append(locales, weightedLocale{locale: locale, weight: weight})
+	}
+	if len(locales) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(locales, func(i, j int) bool {
+		return locales[i].weight > locales[j].weight
+	})
+
+	return locales[0].locale
+}
+
+func checkURL(rawURL string) error {
+	if len(rawURL) > URLMaxLength {
+		return fmt.Errorf("url is longer than %d", URLMaxLength)
+	}
+
+	parsedURL, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return err
+	}
+	if (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return fmt.Errorf("url %q must be absolute http(s) url", rawURL)
+	}
+
+	return nil
+}
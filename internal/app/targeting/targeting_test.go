@@ -0,0 +1,212 @@
+package targeting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	userAgentIPhone        = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+	userAgentIPad          = "Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+	userAgentAndroidPhone  = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36"
+	userAgentAndroidTablet = "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	userAgentWindows       = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	userAgentMacOS         = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15"
+	userAgentLinux         = "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0"
+)
+
+func TestParseClient(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userAgent      string
+		acceptLanguage string
+		expectedClient Client
+	}{
+		{
+			name:           "iphone",
+			userAgent:      userAgentIPhone,
+			acceptLanguage: "ru-RU,ru;q=0.9,en;q=0.8",
+			expectedClient: Client{Platform: PlatformIOS, Device: DeviceMobile, Locale: "ru-ru"},
+		},
+		{
+			name:           "ipad",
+			userAgent:      userAgentIPad,
+			expectedClient: Client{Platform: PlatformIOS, Device: DeviceTablet},
+		},
+		{
+			name:           "android phone",
+			userAgent:      userAgentAndroidPhone,
+			acceptLanguage: "en;q=0.5, de",
+			expectedClient: Client{Platform: PlatformAndroid, Device: DeviceMobile, Locale: "de"},
+		},
+		{
+			name:           "android tablet",
+			userAgent:      userAgentAndroidTablet,
+			expectedClient: Client{Platform: PlatformAndroid, Device: DeviceTablet},
+		},
+		{
+			name:           "windows",
+			userAgent:      userAgentWindows,
+			acceptLanguage: "*",
+			expectedClient: Client{Platform: PlatformWindows, Device: DeviceDesktop},
+		},
+		{
+			name:           "macos",
+			userAgent:      userAgentMacOS,
+			acceptLanguage: "fr;q=0, en;q=invalid, es;q=0.1",
+			expectedClient: Client{Platform: PlatformMacOS, Device: DeviceDesktop, Locale: "es"},
+		},
+		{
+			name:           "linux",
+			userAgent:      userAgentLinux,
+			expectedClient: Client{Platform: PlatformLinux, Device: DeviceDesktop},
+		},
+		{
+			name:           "unknown client",
+			userAgent:      "curl/8.5.0",
+			expectedClient: Client{Platform: PlatformOther, Device: DeviceDesktop},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedClient, ParseClient(tc.userAgent, tc.acceptLanguage))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	rules := []Rule{
+		{Platform: PlatformIOS, URL: "https://apps.apple.com/app/id1"},
+		{Platform: PlatformAndroid, Device: DeviceMobile, URL: "https://play.google.com/store/apps/details?id=app"},
+		{Country: "DE", URL: "https://example.de"},
+		{Locale: "ru", URL: "https://example.ru"},
+	}
+
+	testCases := []struct {
+		name        string
+		client      Client
+		expectedURL string
+		expectedOk  bool
+	}{
+		{
+			name:        "platform rule",
+			client:      Client{Platform: PlatformIOS, Device: DeviceTablet, Locale: "ru"},
+			expectedURL: "https://apps.apple.com/app/id1",
+			expectedOk:  true,
+		},
+		{
+			name:        "all conditions of rule",
+			client:      Client{Platform: PlatformAndroid, Device: DeviceMobile},
+			expectedURL: "https://play.google.com/store/apps/details?id=app",
+			expectedOk:  true,
+		},
+		{
+			name:        "locale rule matches region",
+			client:      Client{Platform: PlatformAndroid, Device: DeviceTablet, Locale: "ru-ru"},
+			expectedURL: "https://example.ru",
+			expectedOk:  true,
+		},
+		{
+			name:        "country rule",
+			client:      Client{Platform: PlatformWindows, Device: DeviceDesktop, Locale: "ru", Country: "DE"},
+			expectedURL: "https://example.de",
+			expectedOk:  true,
+		},
+		{
+			name:   "locale rule does not match prefix of another language",
+			client: Client{Platform: PlatformWindows, Device: DeviceDesktop, Locale: "rue"},
+		},
+		{
+			name:   "no rule matched",
+			client: Client{Platform: PlatformWindows, Device: DeviceDesktop, Locale: "en-us"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ruleURL, ok := Match(rules, tc.client)
+
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedURL, ruleURL)
+		})
+	}
+}
+
+func TestNormalizeRules(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rules         []Rule
+		expectedRules []Rule
+		expectedErr   error
+	}{
+		{
+			name: "rules normalized",
+			rules: []Rule{
+				{Platform: " iOS ", URL: " https://apps.apple.com/app/id1 "},
+				{Locale: "pt_BR", URL: "https://example.com/br"},
+				{Country: " de ", URL: "https://example.de"},
+			},
+			expectedRules: []Rule{
+				{Platform: PlatformIOS, URL: "https://apps.apple.com/app/id1"},
+				{Locale: "pt-br", URL: "https://example.com/br"},
+				{Country: "DE", URL: "https://example.de"},
+			},
+		},
+		{
+			name:        "rule without conditions",
+			rules:       []Rule{{URL: "https://example.com"}},
+			expectedErr: ErrInvalidRules,
+		},
+		{
+			name:        "unknown platform",
+			rules:       []Rule{{Platform: "symbian", URL: "https://example.com"}},
+			expectedErr: ErrInvalidRules,
+		},
+		{
+			name:        "unknown device",
+			rules:       []Rule{{Device: "watch", URL: "https://example.com"}},
+			expectedErr: ErrInvalidRules,
+		},
+		{
+			name:        "invalid locale",
+			rules:       []Rule{{Locale: "русский", URL: "https://example.com"}},
+			expectedErr: ErrInvalidRules,
+		},
+		{
+			name:        "invalid country",
+			rules:       []Rule{{Country: "DEU", URL: "https://example.de"}},
+			expectedErr: ErrInvalidRules,
+		},
+		{
+			name:        "relative url",
+			rules:       []Rule{{Device: DeviceMobile, URL: "/mobile"}},
+			expectedErr: ErrInvalidRules,
+		},
+		{
+			name:        "not http url",
+			rules:       []Rule{{Device: DeviceMobile, URL: "javascript:alert(1)"}},
+			expectedErr: ErrInvalidRules,
+		},
+		{
+			name:        "too many rules",
+			rules:       make([]Rule, RulesMaxCount+1),
+			expectedErr: ErrInvalidRules,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := NormalizeRules(tc.rules)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRules, rules)
+		})
+	}
+}
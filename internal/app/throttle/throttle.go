@@ -0,0 +1,161 @@
+// Package throttle ограничивает количество неудачных попыток (например, ввода пароля) по ключу
+// в пределах временного окна
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/redisstate"
+)
+
+// sweepThreshold - количество ключей, после которого при регистрации неудачной попытки удаляются истекшие ключи
+const sweepThreshold = 1024
+
+// Limiter структура с описанием ограничителя неудачных попыток
+//
+// Окно отсчитывается от первой попытки по ключу. Попытка регистрируется до ее выполнения и считается неудачной,
+// пока не отменена, поэтому одновременные попытки не превышают maxFailures. После maxFailures неудачных попыток
+// ключ блокируется до окончания окна.
+type Limiter struct {
+	mutex       sync.Mutex
+	maxFailures int
+	window      time.Duration
+	failures    map[string]*failures
+	now         func() time.Time
+}
+
+type failures struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewLimiter создает новый экземпляр структуры Limiter
+//
+//	maxFailures - количество неудачных попыток в окне, после которого ключ блокируется
+//	window - длительность окна
+func NewLimiter(maxFailures int, window time.Duration) *Limiter {
+	return &Limiter{
+		maxFailures: maxFailures,
+		window:      window,
+		failures:    make(map[string]*failures),
+		now:         time.Now,
+	}
+}
+
+// Acquire регистрирует попытку по ключу key, если попытки по ключу не заблокированы
+func (l *Limiter) Acquire(key string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	if len(l.failures) >= sweepThreshold {
+		l.sweep(now)
+	}
+
+	keyFailures, ok := l.failures[key]
+	if !ok || !now.Before(keyFailures.resetAt) {
+		keyFailures = &failures{resetAt: now.Add(l.window)}
+		l.failures[key] = keyFailures
+	}
+	if keyFailures.count >= l.maxFailures {
+		return false
+	}
+	keyFailures.count++
+
+	return true
+}
+
+// Release отменяет удачную попытку по ключу key
+func (l *Limiter) Release(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if keyFailures, ok := l.failures[key]; ok && keyFailures.count > 0 {
+		keyFailures.count--
+	}
+}
+
+// Reset сбрасывает неудачные попытки по ключу key
+func (l *Limiter) Reset(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.failures, key)
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	for key, keyFailures := range l.failures {
+		if !now.Before(keyFailures.resetAt) {
+			delete(l.failures, key)
+		}
+	}
+}
+
+// SharedLimiter структура с описанием ограничителя неудачных попыток, общего для экземпляров сервиса
+//
+// Неудачные попытки учитываются в Redis и в локальном Limiter, поэтому при недоступности Redis ограничение
+// продолжает действовать по попыткам, зарегистрированным этим экземпляром.
+type SharedLimiter struct {
+	client      *redisstate.Client
+	name        string
+	maxFailures int
+	window      time.Duration
+	local       *Limiter
+}
+
+// NewSharedLimiter создает новый экземпляр структуры SharedLimiter
+//
+//	client - клиент Redis
+//	name - имя ограничителя, отличает его ключи в Redis от ключей других ограничителей
+//	maxFailures - количество неудачных попыток в окне, после которого ключ блокируется
+//	window - длительность окна
+func NewSharedLimiter(client *redisstate.Client, name string, maxFailures int, window time.Duration) *SharedLimiter {
+	return &SharedLimiter{
+		client:      client,
+		name:        name,
+		maxFailures: maxFailures,
+		window:      window,
+		local:       NewLimiter(maxFailures, window),
+	}
+}
+
+// Acquire регистрирует попытку по ключу key, если попытки по ключу не заблокированы
+//
+// Попытка регистрируется в Redis атомарным увеличением счетчика, поэтому одновременные попытки на разных
+// экземплярах сервиса не превышают maxFailures.
+func (l *SharedLimiter) Acquire(key string) bool {
+	if !l.local.Acquire(key) {
+		return false
+	}
+
+	failures, err := l.client.Incr(context.Background(), l.redisKey(key), l.window)
+	if err != nil {
+		return true
+	}
+	if failures > int64(l.maxFailures) {
+		// заблокированная попытка не выполняется и не учитывается
+		l.local.Release(key)
+		_, _ = l.client.Decr(context.Background(), l.redisKey(key))
+		return false
+	}
+
+	return true
+}
+
+// Release отменяет удачную попытку по ключу key
+func (l *SharedLimiter) Release(key string) {
+	l.local.Release(key)
+	_, _ = l.client.Decr(context.Background(), l.redisKey(key))
+}
+
+// Reset сбрасывает неудачные попытки по ключу key
+func (l *SharedLimiter) Reset(key string) {
+	l.local.Reset(key)
+	_ = l.client.Del(context.Background(), l.redisKey(key))
+}
+
+func (l *SharedLimiter) redisKey(key string) string {
+	return "throttle:" + l.name + ":" + key
+}
@@ -0,0 +1,127 @@
+package throttle
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vkhrushchev/urlshortener/internal/app/redisstate"
+)
+
+func TestLimiter(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failures      int
+		elapsed       time.Duration
+		reset         bool
+		release       bool
+		expectedAllow bool
+	}{
+		{name: "no failures", expectedAllow: true},
+		{name: "failures below limit", failures: 2, expectedAllow: true},
+		{name: "failures reached limit", failures: 3, elapsed: time.Minute, expectedAllow: false},
+		{name: "window expired", failures: 3, elapsed: 5 * time.Minute, expectedAllow: true},
+		{name: "failures reset", failures: 3, reset: true, expectedAllow: true},
+		{name: "successful attempts released", failures: 3, release: true, expectedAllow: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+			limiter := NewLimiter(3, 5*time.Minute)
+			limiter.now = func() time.Time { return now }
+
+			for i := 0; i < tc.failures; i++ {
+				require.True(t, limiter.Acquire("key"))
+				if tc.release {
+					limiter.Release("key")
+				}
+			}
+			if tc.reset {
+				limiter.Reset("key")
+			}
+			now = now.Add(tc.elapsed)
+
+			assert.Equal(t, tc.expectedAllow, limiter.Acquire("key"))
+			assert.True(t, limiter.Acquire("another_key"), "failures must not affect another key")
+		})
+	}
+}
+
+func TestLimiter_sweep(t *testing.T) {
+	now := time.Now()
+	limiter := NewLimiter(3, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < sweepThreshold; i++ {
+		limiter.Acquire(strconv.Itoa(i))
+	}
+	now = now.Add(time.Minute)
+	limiter.Acquire("key")
+
+	assert.Len(t, limiter.failures, 1, "expired keys must be swept")
+}
+
+func TestSharedLimiter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := redisstate.NewClient("redis://"+server.Addr(), "test:")
+	require.NoError(t, err)
+	defer client.Close()
+
+	// экземпляры сервиса с общим Redis
+	first := NewSharedLimiter(client, "password", 3, time.Minute)
+	second := NewSharedLimiter(client, "password", 3, time.Minute)
+
+	assert.True(t, first.Acquire("key"))
+	assert.True(t, second.Acquire("key"))
+	assert.True(t, first.Acquire("key"))
+	assert.False(t, second.Acquire("key"), "failures must be shared")
+	assert.True(t, second.Acquire("another_key"), "failures must not affect another key")
+
+	first.Release("key")
+	assert.True(t, second.Acquire("key"), "release must be shared")
+
+	second.Reset("key")
+	assert.True(t, first.Acquire("key"), "reset must be shared")
+
+	server.FastForward(time.Minute)
+	server.Close()
+	first.Acquire("key")
+	first.Acquire("key")
+	first.Acquire("key")
+	assert.False(t, first.Acquire("key"), "local failures must be limited without redis")
+	assert.True(t, second.Acquire("key"))
+}
+
+func TestSharedLimiter_concurrent(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := redisstate.NewClient("redis://"+server.Addr(), "test:")
+	require.NoError(t, err)
+	defer client.Close()
+
+	// одновременные попытки на нескольких экземплярах сервиса не должны превышать ограничение
+	limiters := []*SharedLimiter{
+		NewSharedLimiter(client, "password", 3, time.Minute),
+		NewSharedLimiter(client, "password", 3, time.Minute),
+	}
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(limiter *SharedLimiter) {
+			defer wg.Done()
+			if limiter.Acquire("key") {
+				allowed.Add(1)
+			}
+		}(limiters[i%len(limiters)])
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(3), allowed.Load())
+}
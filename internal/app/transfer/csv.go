@@ -0,0 +1,289 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+)
+
+// csvColumn - колонка выгрузки CSV, соответствующая полю короткой ссылки
+//
+//	name - имя колонки в строке заголовка, совпадает с именем поля в формате JSON Lines
+//	format - возвращает значение поля, "" - значение по умолчанию
+//	parse - устанавливает поле по непустому значению колонки
+type csvColumn struct {
+	name   string
+	format func(shortURLEntity *entity.ShortURLEntity) (string, error)
+	parse  func(shortURLEntity *entity.ShortURLEntity, value string) error
+}
+
+// csvColumns - колонки выгрузки CSV в порядке записи
+var csvColumns = []csvColumn{
+	stringColumn("uuid", func(e *entity.ShortURLEntity) *string { return &e.UUID }),
+	stringColumn("short_url", func(e *entity.ShortURLEntity) *string { return &e.ShortURI }),
+	stringColumn("original_url", func(e *entity.ShortURLEntity) *string { return &e.LongURL }),
+	stringColumn("user_id", func(e *entity.ShortURLEntity) *string { return &e.UserID }),
+	boolColumn("is_deleted", func(e *entity.ShortURLEntity) *bool { return &e.Deleted }),
+	timePtrColumn("deleted_at", func(e *entity.ShortURLEntity) **time.Time { return &e.DeletedAt }),
+	timeColumn("created_at", func(e *entity.ShortURLEntity) *time.Time { return &e.CreatedAt }),
+	stringColumn("title", func(e *entity.ShortURLEntity) *string { return &e.Title }),
+	stringColumn("description", func(e *entity.ShortURLEntity) *string { return &e.Description }),
+	stringColumn("notes", func(e *entity.ShortURLEntity) *string { return &e.Notes }),
+	jsonColumn("tags", func(e *entity.ShortURLEntity) any { return &e.Tags }),
+	intColumn("redirect_status", func(e *entity.ShortURLEntity) *int { return &e.RedirectStatus }),
+	stringColumn("query_passthrough", func(e *entity.ShortURLEntity) *string { return &e.QueryPassthrough }),
+	boolColumn("path_passthrough", func(e *entity.ShortURLEntity) *bool { return &e.PathPassthrough }),
+	jsonColumn("utm_params", func(e *entity.ShortURLEntity) any { return &e.UTMParams }),
+	jsonColumn("targeting_rules", func(e *entity.ShortURLEntity) any { return &e.TargetingRules }),
+	jsonColumn("split_variants", func(e *entity.ShortURLEntity) any { return &e.SplitVariants }),
+	stringColumn("password_hash", func(e *entity.ShortURLEntity) *string { return &e.PasswordHash }),
+	intColumn("max_clicks", func(e *entity.ShortURLEntity) *int { return &e.MaxClicks }),
+	int64Column("click_count", func(e *entity.ShortURLEntity) *int64 { return &e.ClickCount }),
+	timePtrColumn("not_before", func(e *entity.ShortURLEntity) **time.Time { return &e.NotBefore }),
+	timePtrColumn("not_after", func(e *entity.ShortURLEntity) **time.Time { return &e.NotAfter }),
+	boolColumn("is_disabled", func(e *entity.ShortURLEntity) *bool { return &e.Disabled }),
+	stringColumn("fallback_url", func(e *entity.ShortURLEntity) *string { return &e.FallbackURL }),
+	stringColumn("preview_title", func(e *entity.ShortURLEntity) *string { return &e.PreviewTitle }),
+	stringColumn("preview_description", func(e *entity.ShortURLEntity) *string { return &e.PreviewDescription }),
+	stringColumn("preview_image_url", func(e *entity.ShortURLEntity) *string { return &e.PreviewImageURL }),
+	timePtrColumn("preview_fetched_at", func(e *entity.ShortURLEntity) **time.Time { return &e.PreviewFetchedAt }),
+}
+
+// csvRequiredColumns - колонки, без которых строка CSV не описывает короткую ссылку
+var csvRequiredColumns = []string{"short_url", "original_url"}
+
+func stringColumn(name string, field func(e *entity.ShortURLEntity) *string) csvColumn {
+	return csvColumn{
+		name: name,
+		format: func(e *entity.ShortURLEntity) (string, error) {
+			return *field(e), nil
+		},
+		parse: func(e *entity.ShortURLEntity, value string) error {
+			*field(e) = value
+			return nil
+		},
+	}
+}
+
+func boolColumn(name string, field func(e *entity.ShortURLEntity) *bool) csvColumn {
+	return csvColumn{
+		name: name,
+		format: func(e *entity.ShortURLEntity) (string, error) {
+			return strconv.FormatBool(*field(e)), nil
+		},
+		parse: func(e *entity.ShortURLEntity, value string) (err error) {
+			*field(e), err = strconv.ParseBool(value)
+			return err
+		},
+	}
+}
+
+func intColumn(name string, field func(e *entity.ShortURLEntity) *int) csvColumn {
+	return csvColumn{
+		name: name,
+		format: func(e *entity.ShortURLEntity) (string, error) {
+			return strconv.Itoa(*field(e)), nil
+		},
+		parse: func(e *entity.ShortURLEntity, value string) (err error) {
+			*field(e), err = strconv.Atoi(value)
+			return err
+		},
+	}
+}
+
+func int64Column(name string, field func(e *entity.ShortURLEntity) *int64) csvColumn {
+	return csvColumn{
+		name: name,
+		format: func(e *entity.ShortURLEntity) (string, error) {
+			return strconv.FormatInt(*field(e), 10), nil
+		},
+		parse: func(e *entity.ShortURLEntity, value string) (err error) {
+			*field(e), err = strconv.ParseInt(value, 10, 64)
+			return err
+		},
+	}
+}
+
+func timeColumn(name string, field func(e *entity.ShortURLEntity) *time.Time) csvColumn {
+	return csvColumn{
+		name: name,
+		format: func(e *entity.ShortURLEntity) (string, error) {
+			if field(e).IsZero() {
+				return "", nil
+			}
+			return field(e).Format(time.RFC3339Nano), nil
+		},
+		parse: func(e *entity.ShortURLEntity, value string) (err error) {
+			*field(e), err = time.Parse(time.RFC3339Nano, value)
+			return err
+		},
+	}
+}
+
+func timePtrColumn(name string, field func(e *entity.ShortURLEntity) **time.Time) csvColumn {
+	return csvColumn{
+		name: name,
+		format: func(e *entity.ShortURLEntity) (string, error) {
+			if *field(e) == nil {
+				return "", nil
+			}
+			return (*field(e)).Format(time.RFC3339Nano), nil
+		},
+		parse: func(e *entity.ShortURLEntity, value string) error {
+			parsed, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return err
+			}
+
+			*field(e) = &parsed
+			return nil
+		},
+	}
+}
+
+// jsonColumn - колонка со сложным полем, записанным json-строкой, field возвращает указатель на поле
+func jsonColumn(name string, field func(e *entity.ShortURLEntity) any) csvColumn {
+	return csvColumn{
+		name: name,
+		format: func(e *entity.ShortURLEntity) (string, error) {
+			value, err := json.Marshal(field(e))
+			if err != nil || bytes.Equal(value, []byte("null")) {
+				return "", err
+			}
+			return string(value), nil
+		},
+		parse: func(e *entity.ShortURLEntity, value string) error {
+			return json.Unmarshal([]byte(value), field(e))
+		},
+	}
+}
+
+type csvEncoder struct {
+	writer        *csv.Writer
+	headerWritten bool
+	record        []string
+}
+
+func (e *csvEncoder) encode(shortURLEntity entity.ShortURLEntity) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	for i, column := range csvColumns {
+		value, err := column.format(&shortURLEntity)
+		if err != nil {
+			return fmt.Errorf("transfer: error when format column %s: %w", column.name, err)
+		}
+
+		e.record[i] = value
+	}
+
+	return e.writer.Write(e.record)
+}
+
+// flush записывает строку заголовка и в выгрузку без коротких ссылок
+func (e *csvEncoder) flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+
+	header := make([]string, 0, len(csvColumns))
+	for _, column := range csvColumns {
+		header = append(header, column.name)
+	}
+	if err := e.writer.Write(header); err != nil {
+		return err
+	}
+
+	e.headerWritten = true
+	e.record = make([]string, len(csvColumns))
+
+	return nil
+}
+
+type csvDecoder struct {
+	reader *csv.Reader
+	// columns - колонки выгрузки по номеру колонки строки CSV, nil - колонка не является полем короткой ссылки
+	columns []*csvColumn
+}
+
+func (d *csvDecoder) decode() (entity.ShortURLEntity, error) {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return entity.ShortURLEntity{}, err
+		}
+	}
+
+	record, err := d.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return entity.ShortURLEntity{}, io.EOF
+		}
+
+		return entity.ShortURLEntity{}, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+	}
+
+	var shortURLEntity entity.ShortURLEntity
+	for i, value := range record {
+		column := d.columns[i]
+		if column == nil || value == "" {
+			continue
+		}
+
+		if err := column.parse(&shortURLEntity, value); err != nil {
+			return entity.ShortURLEntity{}, fmt.Errorf("%w: column %s: %v", ErrInvalidRecord, column.name, err)
+		}
+	}
+
+	return shortURLEntity, nil
+}
+
+// readHeader читает строку заголовка и сопоставляет колонки строки CSV колонкам выгрузки
+//
+// Неизвестные колонки пропускаются, чтобы выгрузка более новой версии сервиса загружалась более старой.
+func (d *csvDecoder) readHeader() error {
+	header, err := d.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+
+		return fmt.Errorf("%w: header: %v", ErrInvalidRecord, err)
+	}
+
+	columnsByName := make(map[string]*csvColumn, len(csvColumns))
+	for i := range csvColumns {
+		columnsByName[csvColumns[i].name] = &csvColumns[i]
+	}
+
+	d.columns = make([]*csvColumn, len(header))
+	headerNames := make(map[string]bool, len(header))
+	for i, name := range header {
+		d.columns[i] = columnsByName[name]
+		headerNames[name] = true
+	}
+
+	for _, name := range csvRequiredColumns {
+		if !headerNames[name] {
+			return fmt.Errorf("%w: header: missing column %s", ErrInvalidRecord, name)
+		}
+	}
+
+	return nil
+}
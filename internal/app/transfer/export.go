@@ -0,0 +1,60 @@
+package transfer
+
+import (
+	"context"
+	"io"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+)
+
+type exportShortURLRepository interface {
+	ForEachShortURL(ctx context.Context, fn func(shortURLEntity entity.ShortURLEntity) error) error
+}
+
+// Exporter выгружает все короткие ссылки репозитория, включая удаленные
+type Exporter struct {
+	repo exportShortURLRepository
+}
+
+// NewExporter создает экземпляр структуры Exporter
+func NewExporter(repo exportShortURLRepository) *Exporter {
+	return &Exporter{repo: repo}
+}
+
+// Export записывает короткие ссылки в w в формате format и возвращает количество выгруженных коротких ссылок
+//
+// Короткие ссылки записываются потоком по мере чтения из репозитория, прогресс выводится в лог.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, format string) (int, error) {
+	encoder, err := newEncoder(w, format)
+	if err != nil {
+		return 0, err
+	}
+
+	exportedCount := 0
+	err = e.repo.ForEachShortURL(ctx, func(shortURLEntity entity.ShortURLEntity) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := encoder.encode(shortURLEntity); err != nil {
+			return err
+		}
+
+		exportedCount++
+		if exportedCount%progressInterval == 0 {
+			log.Infow("transfer: export progress", "exported", exportedCount)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return exportedCount, err
+	}
+
+	if err := encoder.flush(); err != nil {
+		return exportedCount, err
+	}
+
+	log.Infow("transfer: export finished", "exported", exportedCount, "format", format)
+
+	return exportedCount, nil
+}
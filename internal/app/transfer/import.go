@@ -0,0 +1,213 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/repository"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+)
+
+// ConflictSkip - короткая ссылка, конфликтующая с существующей, пропускается
+// ConflictFail - загрузка прерывается на первой короткой ссылке, конфликтующей с существующей
+const (
+	ConflictSkip = "skip"
+	ConflictFail = "fail"
+)
+
+// ErrUnsupportedConflictPolicy - неподдерживаемая политика разрешения конфликтов
+// ErrConflict - короткая ссылка конфликтует с существующей
+var (
+	ErrUnsupportedConflictPolicy = errors.New("transfer: unsupported conflict policy")
+	ErrConflict                  = errors.New("transfer: short url conflict")
+)
+
+type importShortURLRepository interface {
+	GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
+	SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error)
+}
+
+// ImportOptions - параметры загрузки коротких ссылок
+type ImportOptions struct {
+	// Format - формат выгрузки
+	Format string
+	// DryRun - проверить выгрузку и конфликты без сохранения коротких ссылок
+	DryRun bool
+	// Conflict - политика разрешения конфликтов, "" - ConflictSkip
+	Conflict string
+}
+
+// ImportResult - результат загрузки коротких ссылок
+type ImportResult struct {
+	// Read - количество прочитанных коротких ссылок
+	Read int
+	// Imported - количество сохраненных коротких ссылок, при DryRun - количество ссылок, которые будут сохранены
+	Imported int
+	// Existing - количество пропущенных коротких ссылок, уже загруженных ранее
+	Existing int
+	// Conflicts - количество пропущенных коротких ссылок, конфликтующих с существующими
+	Conflicts int
+}
+
+// Importer загружает короткие ссылки в репозиторий с сохранением ключей коротких ссылок
+type Importer struct {
+	repo importShortURLRepository
+}
+
+// NewImporter создает экземпляр структуры Importer
+func NewImporter(repo importShortURLRepository) *Importer {
+	return &Importer{repo: repo}
+}
+
+// Import читает короткие ссылки из r и сохраняет их в репозиторий
+//
+// Короткая ссылка с тем же ключом и UUID уже загружена ранее и пропускается, поэтому загрузку можно повторить.
+// Короткой ссылке без UUID назначается новый UUID, поэтому при повторной загрузке она считается конфликтом.
+// Конфликтом считается существующая короткая ссылка с тем же ключом и другим UUID, а также уже сокращенный
+// на домене короткой ссылки исходный URL. При DryRun конфликты по исходному URL не проверяются.
+//
+// При ошибке возвращается результат загрузки коротких ссылок, обработанных до ошибки.
+func (i *Importer) Import(ctx context.Context, r io.Reader, options ImportOptions) (ImportResult, error) {
+	if options.Conflict == "" {
+		options.Conflict = ConflictSkip
+	}
+	if options.Conflict != ConflictSkip && options.Conflict != ConflictFail {
+		return ImportResult{}, fmt.Errorf("%w: %q", ErrUnsupportedConflictPolicy, options.Conflict)
+	}
+
+	decoder, err := newDecoder(r, options.Format)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	var result ImportResult
+	// при DryRun короткие ссылки не сохраняются, поэтому повторы ключей в выгрузке отслеживаются отдельно
+	dryRunUUIDs := make(map[string]string)
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		shortURLEntity, err := decoder.decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("record %d: %w", result.Read+1, err)
+		}
+
+		result.Read++
+		if err := checkShortURL(&shortURLEntity); err != nil {
+			return result, fmt.Errorf("record %d: %w", result.Read, err)
+		}
+
+		existing, err := i.existingUUID(ctx, shortURLEntity.ShortURI, options.DryRun, dryRunUUIDs)
+		if err != nil {
+			return result, err
+		}
+
+		switch {
+		case existing == shortURLEntity.UUID:
+			result.Existing++
+		case existing != "":
+			err = i.conflict(&result, options, shortURLEntity, "short url already exists")
+		case options.DryRun:
+			dryRunUUIDs[shortURLEntity.ShortURI] = shortURLEntity.UUID
+			result.Imported++
+		default:
+			err = i.save(ctx, &result, options, shortURLEntity)
+		}
+		if err != nil {
+			return result, err
+		}
+
+		if result.Read%progressInterval == 0 {
+			logImportProgress("transfer: import progress", result, options)
+		}
+	}
+
+	logImportProgress("transfer: import finished", result, options)
+
+	return result, nil
+}
+
+// existingUUID возвращает UUID существующей короткой ссылки с ключом shortURI, "" - короткая ссылка не существует
+func (i *Importer) existingUUID(ctx context.Context, shortURI string, dryRun bool, dryRunUUIDs map[string]string) (string, error) {
+	if dryRun {
+		if existingUUID, ok := dryRunUUIDs[shortURI]; ok {
+			return existingUUID, nil
+		}
+	}
+
+	existing, err := i.repo.GetShortURLByShortURI(ctx, shortURI)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return existing.UUID, nil
+}
+
+func (i *Importer) save(ctx context.Context, result *ImportResult, options ImportOptions, shortURLEntity entity.ShortURLEntity) error {
+	// репозиторий в памяти индексирует короткие ссылки по пользователю из контекста
+	userCtx := context.WithValue(ctx, common.UserIDContextKey, shortURLEntity.UserID)
+	_, err := i.repo.SaveShortURL(userCtx, &shortURLEntity)
+	if errors.Is(err, repository.ErrConflict) {
+		return i.conflict(result, options, shortURLEntity, "original url already shortened")
+	}
+	if err != nil {
+		return err
+	}
+
+	result.Imported++
+
+	return nil
+}
+
+func (i *Importer) conflict(result *ImportResult, options ImportOptions, shortURLEntity entity.ShortURLEntity, reason string) error {
+	if options.Conflict == ConflictFail {
+		return fmt.Errorf("%w: record %d: %s: %s", ErrConflict, result.Read, shortURLEntity.ShortURI, reason)
+	}
+
+	log.Warnw("transfer: short url skipped", "shortURI", shortURLEntity.ShortURI, "reason", reason)
+	result.Conflicts++
+
+	return nil
+}
+
+// checkShortURL проверяет обязательные поля короткой ссылки и заполняет поля, отсутствующие в выгрузке
+func checkShortURL(shortURLEntity *entity.ShortURLEntity) error {
+	if shortURLEntity.ShortURI == "" {
+		return fmt.Errorf("%w: short_url is empty", ErrInvalidRecord)
+	}
+	if shortURLEntity.LongURL == "" {
+		return fmt.Errorf("%w: original_url is empty", ErrInvalidRecord)
+	}
+
+	if shortURLEntity.UUID == "" {
+		shortURLEntity.UUID = uuid.NewString()
+	}
+	// срок хранения в корзине удаленной ссылки без времени удаления отсчитывается с момента загрузки
+	if shortURLEntity.Deleted && shortURLEntity.DeletedAt == nil {
+		deletedAt := time.Now()
+		shortURLEntity.DeletedAt = &deletedAt
+	}
+
+	return nil
+}
+
+func logImportProgress(message string, result ImportResult, options ImportOptions) {
+	log.Infow(message,
+		"read", result.Read,
+		"imported", result.Imported,
+		"existing", result.Existing,
+		"conflicts", result.Conflicts,
+		"dryRun", options.DryRun)
+}
@@ -0,0 +1,566 @@
+package transfer
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vkhrushchev/urlshortener/internal/app/domain"
+	"github.com/vkhrushchev/urlshortener/internal/app/usecase"
+)
+
+// ConflictGenerate - короткой ссылке, прежний идентификатор которой нельзя сохранить, назначается новый идентификатор
+const ConflictGenerate = "generate"
+
+// LayoutGeneric - выгрузка CSV с колонками, названными как поля ColumnMapping
+// LayoutBitly - выгрузка ссылок Bitly
+// LayoutRebrandly - выгрузка ссылок Rebrandly
+// LayoutYOURLS - выгрузка ссылок YOURLS
+const (
+	LayoutGeneric   = "generic"
+	LayoutBitly     = "bitly"
+	LayoutRebrandly = "rebrandly"
+	LayoutYOURLS    = "yourls"
+)
+
+// thirdPartyBatchSize - количество коротких ссылок, создаваемых одной пачкой
+const thirdPartyBatchSize = 100
+
+// ErrUnsupportedLayout - неизвестная раскладка колонок выгрузки стороннего сервиса
+// ErrInvalidColumnMapping - некорректное сопоставление колонок выгрузки полям короткой ссылки
+var (
+	ErrUnsupportedLayout    = errors.New("transfer: unsupported layout")
+	ErrInvalidColumnMapping = errors.New("transfer: invalid column mapping")
+)
+
+// ColumnMapping - имена колонок выгрузки CSV стороннего сервиса, соответствующих полям короткой ссылки,
+// "" - колонка отсутствует
+//
+// Имена колонок сравниваются без учета регистра.
+type ColumnMapping struct {
+	// OriginalURL - исходный URL, обязательная колонка
+	OriginalURL string
+	// ShortCode - идентификатор короткой ссылки в стороннем сервисе или короткая ссылка целиком
+	ShortCode string
+	// Title - название короткой ссылки
+	Title string
+	// Description - описание короткой ссылки
+	Description string
+	// Notes - заметки к короткой ссылке
+	Notes string
+	// Tags - теги короткой ссылки, разделенные ",", ";" или "|"
+	Tags string
+}
+
+// columnMappingFields - поля ColumnMapping по именам, используемым в ParseColumnMapping
+var columnMappingFields = map[string]func(mapping *ColumnMapping) *string{
+	"original_url": func(mapping *ColumnMapping) *string { return &mapping.OriginalURL },
+	"short_code":   func(mapping *ColumnMapping) *string { return &mapping.ShortCode },
+	"title":        func(mapping *ColumnMapping) *string { return &mapping.Title },
+	"description":  func(mapping *ColumnMapping) *string { return &mapping.Description },
+	"notes":        func(mapping *ColumnMapping) *string { return &mapping.Notes },
+	"tags":         func(mapping *ColumnMapping) *string { return &mapping.Tags },
+}
+
+// layouts - сопоставление колонок распространенных выгрузок сторонних сервисов
+var layouts = map[string]ColumnMapping{
+	LayoutGeneric: {
+		OriginalURL: "original_url",
+		ShortCode:   "short_code",
+		Title:       "title",
+		Description: "description",
+		Notes:       "notes",
+		Tags:        "tags",
+	},
+	LayoutBitly: {
+		OriginalURL: "long_url",
+		ShortCode:   "link",
+		Title:       "title",
+		Tags:        "tags",
+	},
+	LayoutRebrandly: {
+		OriginalURL: "destination",
+		ShortCode:   "slashtag",
+		Title:       "title",
+		Tags:        "tags",
+	},
+	LayoutYOURLS: {
+		OriginalURL: "url",
+		ShortCode:   "keyword",
+		Title:       "title",
+	},
+}
+
+// ParseColumnMapping возвращает сопоставление колонок раскладки layout, "" - LayoutGeneric,
+// с переопределениями overrides вида "original_url=Destination,short_code=Slug"
+//
+// Пустое имя колонки в переопределении исключает поле из загрузки.
+func ParseColumnMapping(layout string, overrides string) (ColumnMapping, error) {
+	if layout == "" {
+		layout = LayoutGeneric
+	}
+
+	mapping, ok := layouts[layout]
+	if !ok {
+		return ColumnMapping{}, fmt.Errorf("%w: %q", ErrUnsupportedLayout, layout)
+	}
+
+	for _, override := range strings.Split(overrides, ",") {
+		if strings.TrimSpace(override) == "" {
+			continue
+		}
+
+		fieldName, column, ok := strings.Cut(override, "=")
+		if !ok {
+			return ColumnMapping{}, fmt.Errorf("%w: %q must be field=column", ErrInvalidColumnMapping, override)
+		}
+
+		field, ok := columnMappingFields[strings.TrimSpace(fieldName)]
+		if !ok {
+			return ColumnMapping{}, fmt.Errorf("%w: unknown field %q", ErrInvalidColumnMapping, fieldName)
+		}
+
+		*field(&mapping) = strings.TrimSpace(column)
+	}
+
+	if mapping.OriginalURL == "" {
+		return ColumnMapping{}, fmt.Errorf("%w: original_url column is required", ErrInvalidColumnMapping)
+	}
+
+	return mapping, nil
+}
+
+type shortURLBatchCreator interface {
+	IsAliasAvailable(ctx context.Context, shortDomain string, alias string) (bool, error)
+	CreateShortURLBatch(ctx context.Context, createShortURLBatchDomains []domain.CreateShortURLBatchDomain) ([]domain.CreateShortURLBatchResultDomain, error)
+}
+
+// ThirdPartyImportOptions - параметры загрузки выгрузки стороннего сервиса
+type ThirdPartyImportOptions struct {
+	// Mapping - сопоставление колонок выгрузки полям короткой ссылки
+	Mapping ColumnMapping
+	// ShortDomain - домен создаваемых коротких ссылок, "" - домен по умолчанию
+	ShortDomain string
+	// DryRun - проверить выгрузку и идентификаторы без создания коротких ссылок
+	DryRun bool
+	// Conflict - политика для коротких ссылок, прежний идентификатор которых нельзя сохранить:
+	// ConflictGenerate (по умолчанию), ConflictSkip или ConflictFail
+	Conflict string
+}
+
+// AliasConflict - короткая ссылка, прежний идентификатор которой не сохранен или исходный URL которой уже сокращен
+type AliasConflict struct {
+	// Record - номер записи выгрузки, начиная с 1
+	Record int
+	// ShortCode - прежний идентификатор короткой ссылки, "" - идентификатор не указан
+	ShortCode string
+	// Reason - причина, по которой идентификатор не сохранен
+	Reason string
+	// ShortURI - ключ созданной короткой ссылки с новым идентификатором, "" - короткая ссылка не создана
+	ShortURI string
+}
+
+// ThirdPartyImportResult - результат загрузки выгрузки стороннего сервиса
+type ThirdPartyImportResult struct {
+	// Read - количество прочитанных записей
+	Read int
+	// Imported - количество созданных коротких ссылок, при DryRun - количество ссылок, которые будут созданы
+	Imported int
+	// KeptAliases - количество коротких ссылок, созданных с прежним идентификатором
+	KeptAliases int
+	// Conflicts - короткие ссылки, прежний идентификатор которых не сохранен, и записи, исходный URL которых
+	// уже сокращен на домене или повторяется в выгрузке
+	Conflicts []AliasConflict
+}
+
+// pendingShortURL - короткая ссылка, ожидающая создания в пачке
+type pendingShortURL struct {
+	record int
+	// conflict - индекс конфликта в ThirdPartyImportResult.Conflicts, -1 - прежний идентификатор сохраняется
+	conflict int
+	batch    domain.CreateShortURLBatchDomain
+}
+
+// ThirdPartyImporter загружает короткие ссылки из выгрузок CSV сторонних сервисов
+//
+// Короткие ссылки создаются пачками по правилам создания коротких ссылок пользователем из контекста,
+// который становится их владельцем. Прежние идентификаторы сохраняются, если они свободны.
+type ThirdPartyImporter struct {
+	creator shortURLBatchCreator
+}
+
+// NewThirdPartyImporter создает экземпляр структуры ThirdPartyImporter
+func NewThirdPartyImporter(creator shortURLBatchCreator) *ThirdPartyImporter {
+	return &ThirdPartyImporter{creator: creator}
+}
+
+// Import читает выгрузку CSV стороннего сервиса из r и создает короткие ссылки от имени пользователя из ctx
+//
+// Прежний идентификатор не сохраняется, если он некорректен, занят или повторяется в выгрузке. Если пачка
+// не создана из-за конфликта, ее короткие ссылки создаются по одной: идентификатор, занятый между проверкой
+// и созданием пачки, обрабатывается по политике options.Conflict, а запись, исходный URL которой уже сокращен
+// на домене или повторяется в выгрузке, пропускается и попадает в конфликты.
+//
+// При ошибке возвращается результат загрузки коротких ссылок, созданных до ошибки.
+func (i *ThirdPartyImporter) Import(ctx context.Context, r io.Reader, options ThirdPartyImportOptions) (ThirdPartyImportResult, error) {
+	if options.Conflict == "" {
+		options.Conflict = ConflictGenerate
+	}
+	if options.Conflict != ConflictGenerate && options.Conflict != ConflictSkip && options.Conflict != ConflictFail {
+		return ThirdPartyImportResult{}, fmt.Errorf("%w: %q", ErrUnsupportedConflictPolicy, options.Conflict)
+	}
+	if options.Mapping.OriginalURL == "" {
+		return ThirdPartyImportResult{}, fmt.Errorf("%w: original_url column is required", ErrInvalidColumnMapping)
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	columns, err := readThirdPartyHeader(reader, options.Mapping)
+	if err != nil {
+		return ThirdPartyImportResult{}, err
+	}
+
+	result := ThirdPartyImportResult{Conflicts: make([]AliasConflict, 0)}
+	seenShortCodes := make(map[string]bool)
+	pending := make([]pendingShortURL, 0, thirdPartyBatchSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("%w: record %d: %v", ErrInvalidRecord, result.Read+1, err)
+		}
+
+		result.Read++
+		shortURL, err := columns.shortURL(record, options.ShortDomain)
+		if err != nil {
+			return result, fmt.Errorf("%w: record %d: %v", ErrInvalidRecord, result.Read, err)
+		}
+
+		shortCode := shortCodeOf(columns.value(record, columns.shortCode))
+		item := pendingShortURL{record: result.Read, conflict: -1, batch: shortURL}
+		if shortCode != "" {
+			reason, err := i.checkShortCode(ctx, shortCode, options.ShortDomain, seenShortCodes)
+			if err != nil {
+				return result, err
+			}
+
+			if reason == "" {
+				item.batch.Alias = shortCode
+			} else {
+				if options.Conflict == ConflictFail {
+					return result, fmt.Errorf("%w: record %d: %s: %s", ErrConflict, result.Read, shortCode, reason)
+				}
+
+				log.Warnw("transfer: short code is not kept", "record", result.Read, "shortCode", shortCode, "reason", reason)
+				result.Conflicts = append(result.Conflicts, AliasConflict{Record: result.Read, ShortCode: shortCode, Reason: reason})
+				item.conflict = len(result.Conflicts) - 1
+			}
+		}
+
+		if item.conflict < 0 || options.Conflict == ConflictGenerate {
+			pending = append(pending, item)
+		}
+		if len(pending) == thirdPartyBatchSize {
+			if err := i.createBatch(ctx, &result, pending, options); err != nil {
+				return result, err
+			}
+			pending = pending[:0]
+		}
+
+		if result.Read%progressInterval == 0 {
+			logThirdPartyImportProgress("transfer: third party import progress", result, options)
+		}
+	}
+
+	if err := i.createBatch(ctx, &result, pending, options); err != nil {
+		return result, err
+	}
+
+	// конфликты, найденные при создании пачек по одной ссылке, добавляются после конфликтов следующих записей
+	slices.SortStableFunc(result.Conflicts, func(a AliasConflict, b AliasConflict) int {
+		return cmp.Compare(a.Record, b.Record)
+	})
+
+	logThirdPartyImportProgress("transfer: third party import finished", result, options)
+
+	return result, nil
+}
+
+// checkShortCode возвращает причину, по которой прежний идентификатор shortCode нельзя сохранить, "" - идентификатор свободен
+//
+// Идентификаторы, проверенные ранее, собираются в seenShortCodes, чтобы повтор в выгрузке не занял уже выбранный идентификатор.
+func (i *ThirdPartyImporter) checkShortCode(ctx context.Context, shortCode string, shortDomain string, seenShortCodes map[string]bool) (string, error) {
+	if err := usecase.CheckAlias(shortCode); err != nil {
+		return err.Error(), nil
+	}
+	if seenShortCodes[shortCode] {
+		return "short code is repeated in import", nil
+	}
+	seenShortCodes[shortCode] = true
+
+	available, err := i.creator.IsAliasAvailable(ctx, shortDomain, shortCode)
+	if err != nil {
+		return "", err
+	}
+	if !available {
+		return "short code is already taken", nil
+	}
+
+	return "", nil
+}
+
+// createBatch создает пачку коротких ссылок pending и сохраняет новые идентификаторы в конфликтах result
+func (i *ThirdPartyImporter) createBatch(ctx context.Context, result *ThirdPartyImportResult, pending []pendingShortURL, options ThirdPartyImportOptions) error {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	keptAliases := 0
+	for _, item := range pending {
+		if item.batch.Alias != "" {
+			keptAliases++
+		}
+	}
+
+	if options.DryRun {
+		result.Imported += len(pending)
+		result.KeptAliases += keptAliases
+		return nil
+	}
+
+	batch := make([]domain.CreateShortURLBatchDomain, 0, len(pending))
+	for _, item := range pending {
+		batch = append(batch, item.batch)
+	}
+
+	batchResults, err := i.creator.CreateShortURLBatch(ctx, batch)
+	if errors.Is(err, usecase.ErrInvalidMetadata) {
+		return fmt.Errorf("%w: records %d-%d: %v", ErrInvalidRecord, pending[0].record, pending[len(pending)-1].record, err)
+	}
+	if errors.Is(err, usecase.ErrConflict) {
+		log.Infow("transfer: batch conflicts with existed short urls, creating short urls one by one",
+			"firstRecord", pending[0].record, "lastRecord", pending[len(pending)-1].record)
+		for _, item := range pending {
+			if err := i.createShortURL(ctx, result, item, options.Conflict); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	shortURIs := make(map[string]string, len(batchResults))
+	for _, batchResult := range batchResults {
+		shortURIs[batchResult.CorrelationUUID] = batchResult.ShortURI
+	}
+	for _, item := range pending {
+		if item.conflict >= 0 {
+			result.Conflicts[item.conflict].ShortURI = shortURIs[item.batch.CorrelationUUID]
+		}
+	}
+
+	result.Imported += len(pending)
+	result.KeptAliases += keptAliases
+
+	return nil
+}
+
+// createShortURL создает короткую ссылку item отдельно от пачки и записывает конфликт в result
+//
+// Идентификатор, занятый между проверкой и созданием короткой ссылки, обрабатывается по политике conflict,
+// запись, исходный URL которой уже сокращен, пропускается.
+func (i *ThirdPartyImporter) createShortURL(ctx context.Context, result *ThirdPartyImportResult, item pendingShortURL, conflict string) error {
+	batchResults, err := i.creator.CreateShortURLBatch(ctx, []domain.CreateShortURLBatchDomain{item.batch})
+	if errors.Is(err, usecase.ErrInvalidMetadata) {
+		return fmt.Errorf("%w: record %d: %v", ErrInvalidRecord, item.record, err)
+	}
+	if errors.Is(err, usecase.ErrConflict) {
+		if item.batch.Alias != "" {
+			available, err := i.creator.IsAliasAvailable(ctx, item.batch.Metadata.ShortDomain, item.batch.Alias)
+			if err != nil {
+				return err
+			}
+
+			if !available {
+				const reason = "short code was taken during import"
+				if conflict == ConflictFail {
+					return fmt.Errorf("%w: record %d: %s: %s", ErrConflict, item.record, item.batch.Alias, reason)
+				}
+
+				log.Warnw("transfer: short code is not kept", "record", item.record, "shortCode", item.batch.Alias, "reason", reason)
+				result.Conflicts = append(result.Conflicts, AliasConflict{Record: item.record, ShortCode: item.batch.Alias, Reason: reason})
+				if conflict == ConflictSkip {
+					return nil
+				}
+
+				item.conflict = len(result.Conflicts) - 1
+				item.batch.Alias = ""
+				return i.createShortURL(ctx, result, item, conflict)
+			}
+		}
+
+		const reason = "original url is already shortened"
+		log.Warnw("transfer: short url is not created", "record", item.record, "originalURL", item.batch.LongURL, "reason", reason)
+		if item.conflict >= 0 {
+			result.Conflicts[item.conflict].Reason += "; " + reason
+		} else {
+			result.Conflicts = append(result.Conflicts, AliasConflict{Record: item.record, ShortCode: item.batch.Alias, Reason: reason})
+		}
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if item.conflict >= 0 && len(batchResults) > 0 {
+		result.Conflicts[item.conflict].ShortURI = batchResults[0].ShortURI
+	}
+
+	result.Imported++
+	if item.batch.Alias != "" {
+		result.KeptAliases++
+	}
+
+	return nil
+}
+
+// thirdPartyColumns - номера колонок строки CSV, соответствующих полям короткой ссылки, -1 - колонка отсутствует
+type thirdPartyColumns struct {
+	originalURL int
+	shortCode   int
+	title       int
+	description int
+	notes       int
+	tags        int
+}
+
+// readThirdPartyHeader читает строку заголовка и находит колонки сопоставления mapping
+//
+// Отсутствующая в выгрузке необязательная колонка пропускается, чтобы раскладка подходила к выгрузкам с разным набором колонок.
+func readThirdPartyHeader(reader *csv.Reader, mapping ColumnMapping) (thirdPartyColumns, error) {
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return thirdPartyColumns{}, fmt.Errorf("%w: header: empty import", ErrInvalidRecord)
+	}
+	if err != nil {
+		return thirdPartyColumns{}, fmt.Errorf("%w: header: %v", ErrInvalidRecord, err)
+	}
+
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel добавляет метку порядка байтов в начало файла CSV в UTF-8
+		name = strings.TrimPrefix(name, "\uFEFF")
+		indexes[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	indexOf := func(column string) int {
+		if column == "" {
+			return -1
+		}
+
+		index, ok := indexes[strings.ToLower(column)]
+		if !ok {
+			log.Warnw("transfer: mapped column is missing in import", "column", column)
+			return -1
+		}
+
+		return index
+	}
+
+	columns := thirdPartyColumns{
+		originalURL: indexOf(mapping.OriginalURL),
+		shortCode:   indexOf(mapping.ShortCode),
+		title:       indexOf(mapping.Title),
+		description: indexOf(mapping.Description),
+		notes:       indexOf(mapping.Notes),
+		tags:        indexOf(mapping.Tags),
+	}
+	if columns.originalURL < 0 {
+		return thirdPartyColumns{}, fmt.Errorf("%w: header: missing column %s", ErrInvalidRecord, mapping.OriginalURL)
+	}
+
+	return columns, nil
+}
+
+func (c thirdPartyColumns) value(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[index])
+}
+
+// shortURL возвращает описание создаваемой короткой ссылки по строке CSV record
+func (c thirdPartyColumns) shortURL(record []string, shortDomain string) (domain.CreateShortURLBatchDomain, error) {
+	originalURL := c.value(record, c.originalURL)
+	if originalURL == "" {
+		return domain.CreateShortURLBatchDomain{}, errors.New("original url is empty")
+	}
+	if parsedURL, err := url.Parse(originalURL); err != nil || !parsedURL.IsAbs() {
+		return domain.CreateShortURLBatchDomain{}, fmt.Errorf("original url %q is not absolute url", originalURL)
+	}
+
+	var tags []string
+	for _, tag := range strings.FieldsFunc(c.value(record, c.tags), isTagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return domain.CreateShortURLBatchDomain{
+		CorrelationUUID: uuid.NewString(),
+		LongURL:         originalURL,
+		Metadata: domain.ShortURLMetadataDomain{
+			Title:       c.value(record, c.title),
+			Description: c.value(record, c.description),
+			Notes:       c.value(record, c.notes),
+			Tags:        tags,
+			ShortDomain: shortDomain,
+		},
+	}, nil
+}
+
+func isTagSeparator(r rune) bool {
+	return r == ',' || r == ';' || r == '|'
+}
+
+// shortCodeOf возвращает идентификатор короткой ссылки из значения колонки, которое может быть
+// идентификатором или короткой ссылкой целиком, например "https://bit.ly/abc" или "bit.ly/abc"
+func shortCodeOf(value string) string {
+	if parsedURL, err := url.Parse(value); err == nil && parsedURL.Host != "" {
+		value = parsedURL.Path
+	}
+
+	value = strings.TrimRight(value, "/")
+	if i := strings.LastIndex(value, "/"); i >= 0 {
+		value = value[i+1:]
+	}
+
+	return value
+}
+
+func logThirdPartyImportProgress(message string, result ThirdPartyImportResult, options ThirdPartyImportOptions) {
+	log.Infow(message,
+		"read", result.Read,
+		"imported", result.Imported,
+		"keptAliases", result.KeptAliases,
+		"conflicts", len(result.Conflicts),
+		"dryRun", options.DryRun)
+}
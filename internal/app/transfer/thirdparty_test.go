@@ -0,0 +1,379 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vkhrushchev/urlshortener/internal/app/db"
+	"github.com/vkhrushchev/urlshortener/internal/app/domain"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/repository"
+	"github.com/vkhrushchev/urlshortener/internal/app/usecase"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+)
+
+// testBitlyExport - выгрузка Bitly с занятым, повторяющимся и некорректным идентификаторами
+const testBitlyExport = "\uFEFFLink,Long_URL,Title,Tags,Clicks\n" +
+	"https://bit.ly/free,https://ya.ru,Yandex,\"news, search\",10\n" +
+	"bit.ly/taken,https://google.com,,,0\n" +
+	"https://bit.ly/free,https://mail.ru,,,0\n" +
+	"https://bit.ly/not.valid,https://go.dev,,,0\n" +
+	",https://example.com,,,0\n"
+
+type ThirdPartyImportTestSuite struct {
+	suite.Suite
+	repo       *repository.InMemoryShortURLRepository
+	importer   *ThirdPartyImporter
+	testUserID string
+	testCtx    context.Context
+	mapping    ColumnMapping
+}
+
+func (suite *ThirdPartyImportTestSuite) SetupTest() {
+	suite.repo = repository.NewInMemoryShortURLRepository()
+	suite.importer = NewThirdPartyImporter(usecase.NewCreateShortURLUseCase(suite.repo, nil, nil))
+	suite.testUserID = uuid.NewString()
+	suite.testCtx = context.WithValue(context.Background(), common.UserIDContextKey, suite.testUserID)
+
+	ownerCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.repo.SaveShortURL(ownerCtx, &entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: "taken", LongURL: "https://taken.ru"})
+	suite.Require().NoError(err)
+
+	suite.mapping, err = ParseColumnMapping(LayoutBitly, "")
+	suite.Require().NoError(err)
+}
+
+func (suite *ThirdPartyImportTestSuite) TestImport_generate() {
+	result, err := suite.importer.Import(suite.testCtx, strings.NewReader(testBitlyExport), ThirdPartyImportOptions{Mapping: suite.mapping})
+	suite.Require().NoError(err)
+
+	suite.Equal(5, result.Read)
+	suite.Equal(5, result.Imported)
+	suite.Equal(1, result.KeptAliases)
+	suite.Require().Len(result.Conflicts, 3)
+	for i, expected := range []AliasConflict{
+		{Record: 2, ShortCode: "taken"},
+		{Record: 3, ShortCode: "free"},
+		{Record: 4, ShortCode: "not.valid"},
+	} {
+		suite.Equal(expected.Record, result.Conflicts[i].Record)
+		suite.Equal(expected.ShortCode, result.Conflicts[i].ShortCode)
+		suite.NotEmpty(result.Conflicts[i].Reason)
+		suite.NotEmpty(result.Conflicts[i].ShortURI, "short url with conflict must be created with new short uri")
+	}
+
+	kept, err := suite.repo.GetShortURLByShortURI(context.Background(), "free")
+	suite.Require().NoError(err)
+	suite.Equal("https://ya.ru", kept.LongURL)
+	suite.Equal("Yandex", kept.Title)
+	suite.Equal([]string{"news", "search"}, kept.Tags)
+
+	taken, err := suite.repo.GetShortURLByShortURI(context.Background(), "taken")
+	suite.Require().NoError(err)
+	suite.Equal("https://taken.ru", taken.LongURL, "taken short url must not be overwritten")
+
+	userShortURLs, err := suite.repo.GetShortURLsByUserID(context.Background(), suite.testUserID)
+	suite.Require().NoError(err)
+	suite.Len(userShortURLs, 5, "imported short urls must belong to importing user")
+}
+
+func (suite *ThirdPartyImportTestSuite) TestImport_skip() {
+	result, err := suite.importer.Import(suite.testCtx, strings.NewReader(testBitlyExport), ThirdPartyImportOptions{Mapping: suite.mapping, Conflict: ConflictSkip})
+	suite.Require().NoError(err)
+
+	suite.Equal(5, result.Read)
+	suite.Equal(2, result.Imported)
+	suite.Len(result.Conflicts, 3)
+	for _, conflict := range result.Conflicts {
+		suite.Empty(conflict.ShortURI, "skipped short url must not be created")
+	}
+}
+
+func (suite *ThirdPartyImportTestSuite) TestImport_fail() {
+	result, err := suite.importer.Import(suite.testCtx, strings.NewReader(testBitlyExport), ThirdPartyImportOptions{Mapping: suite.mapping, Conflict: ConflictFail})
+	suite.ErrorIs(err, ErrConflict)
+	suite.Equal(2, result.Read)
+}
+
+func (suite *ThirdPartyImportTestSuite) TestImport_dryRun() {
+	result, err := suite.importer.Import(suite.testCtx, strings.NewReader(testBitlyExport), ThirdPartyImportOptions{Mapping: suite.mapping, DryRun: true})
+	suite.Require().NoError(err)
+
+	suite.Equal(5, result.Imported)
+	suite.Equal(1, result.KeptAliases)
+	suite.Len(result.Conflicts, 3)
+
+	_, err = suite.repo.GetShortURLByShortURI(context.Background(), "free")
+	suite.ErrorIs(err, repository.ErrNotFound, "dry run must not create short urls")
+}
+
+func (suite *ThirdPartyImportTestSuite) TestImport_invalidInput() {
+	testCases := []struct {
+		name    string
+		input   string
+		options ThirdPartyImportOptions
+		err     error
+	}{
+		{
+			name:    "unsupported conflict policy",
+			options: ThirdPartyImportOptions{Mapping: suite.mapping, Conflict: "overwrite"},
+			err:     ErrUnsupportedConflictPolicy,
+		},
+		{
+			name:    "missing original url column",
+			input:   "link,title\nhttps://bit.ly/abc,ABC\n",
+			options: ThirdPartyImportOptions{Mapping: suite.mapping},
+			err:     ErrInvalidRecord,
+		},
+		{
+			name:    "relative original url",
+			input:   "link,long_url\nhttps://bit.ly/abc,ya.ru\n",
+			options: ThirdPartyImportOptions{Mapping: suite.mapping},
+			err:     ErrInvalidRecord,
+		},
+		{
+			name:    "empty import",
+			options: ThirdPartyImportOptions{Mapping: suite.mapping},
+			err:     ErrInvalidRecord,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			_, err := suite.importer.Import(suite.testCtx, strings.NewReader(testCase.input), testCase.options)
+			suite.ErrorIs(err, testCase.err)
+		})
+	}
+}
+
+func TestThirdPartyImportTestSuite(t *testing.T) {
+	suite.Run(t, new(ThirdPartyImportTestSuite))
+}
+
+// raceShortURLBatchCreator занимает идентификатор taken перед созданием первой пачки коротких ссылок,
+// как другой запрос между проверкой идентификатора и созданием пачки
+type raceShortURLBatchCreator struct {
+	*usecase.CreateShortURLUseCase
+	repo  *repository.SQLiteShortURLRepository
+	taken string
+	done  bool
+}
+
+func (c *raceShortURLBatchCreator) CreateShortURLBatch(ctx context.Context, createShortURLBatchDomains []domain.CreateShortURLBatchDomain) ([]domain.CreateShortURLBatchResultDomain, error) {
+	if !c.done {
+		c.done = true
+		ownerCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+		if _, err := c.repo.SaveShortURL(ownerCtx, &entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: c.taken, LongURL: "https://race.ru"}); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.CreateShortURLUseCase.CreateShortURLBatch(ctx, createShortURLBatchDomains)
+}
+
+// ThirdPartyImportConflictTestSuite проверяет конфликты при создании пачек в репозитории,
+// который проверяет уникальность ключа и исходного URL короткой ссылки
+type ThirdPartyImportConflictTestSuite struct {
+	suite.Suite
+	repo       *repository.SQLiteShortURLRepository
+	testUserID string
+	testCtx    context.Context
+	mapping    ColumnMapping
+}
+
+func (suite *ThirdPartyImportConflictTestSuite) SetupTest() {
+	suite.testUserID = uuid.NewString()
+	suite.testCtx = context.WithValue(context.Background(), common.UserIDContextKey, suite.testUserID)
+	suite.resetRepository()
+
+	var err error
+	suite.mapping, err = ParseColumnMapping(LayoutBitly, "")
+	suite.Require().NoError(err)
+}
+
+// resetRepository создает пустой репозиторий SQLite с короткой ссылкой на https://ya.ru другого пользователя,
+// репозиторий закрывается по завершении текущего теста
+func (suite *ThirdPartyImportConflictTestSuite) resetRepository() {
+	dbLookup, err := db.NewDBLookup(db.SQLiteDSNScheme + filepath.Join(suite.T().TempDir(), "urlshortener.db"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(dbLookup.InitDB(context.Background()))
+	suite.T().Cleanup(func() {
+		dbLookup.GetDB().Close()
+	})
+
+	suite.repo = repository.NewSQLiteShortURLRepository(dbLookup)
+
+	ownerCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err = suite.repo.SaveShortURL(ownerCtx, &entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: "existed", LongURL: "https://ya.ru"})
+	suite.Require().NoError(err)
+}
+
+func (suite *ThirdPartyImportConflictTestSuite) TestImport_original_url_conflict() {
+	input := "link,long_url\n" +
+		"bit.ly/yandex,https://ya.ru\n" +
+		"bit.ly/go,https://go.dev\n" +
+		"bit.ly/go-again,https://go.dev\n" +
+		",https://mail.ru\n"
+	importer := NewThirdPartyImporter(usecase.NewCreateShortURLUseCase(suite.repo, nil, nil))
+
+	result, err := importer.Import(suite.testCtx, strings.NewReader(input), ThirdPartyImportOptions{Mapping: suite.mapping})
+	suite.Require().NoError(err, "original url conflict must not fail import")
+
+	suite.Equal(4, result.Read)
+	suite.Equal(2, result.Imported)
+	suite.Equal(1, result.KeptAliases)
+	suite.Require().Len(result.Conflicts, 2)
+	for i, expected := range []AliasConflict{
+		{Record: 1, ShortCode: "yandex", Reason: "original url is already shortened"},
+		{Record: 3, ShortCode: "go-again", Reason: "original url is already shortened"},
+	} {
+		suite.Equal(expected, result.Conflicts[i])
+	}
+
+	kept, err := suite.repo.GetShortURLByShortURI(context.Background(), "go")
+	suite.Require().NoError(err)
+	suite.Equal("https://go.dev", kept.LongURL)
+
+	for _, shortURI := range []string{"yandex", "go-again"} {
+		_, err = suite.repo.GetShortURLByShortURI(context.Background(), shortURI)
+		suite.ErrorIs(err, repository.ErrNotFound, "short url with conflict must not be created: %s", shortURI)
+	}
+
+	userShortURLs, err := suite.repo.GetShortURLsByUserID(context.Background(), suite.testUserID)
+	suite.Require().NoError(err)
+	suite.Len(userShortURLs, 2)
+}
+
+func (suite *ThirdPartyImportConflictTestSuite) TestImport_short_code_race() {
+	input := "link,long_url\n" +
+		"bit.ly/race,https://go.dev\n" +
+		"bit.ly/kept,https://mail.ru\n"
+	testCases := []struct {
+		conflict string
+		err      error
+		imported int
+		created  bool
+	}{
+		{conflict: ConflictGenerate, imported: 2, created: true},
+		{conflict: ConflictSkip, imported: 1},
+		{conflict: ConflictFail, err: ErrConflict},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.conflict, func() {
+			suite.resetRepository()
+			creator := &raceShortURLBatchCreator{
+				CreateShortURLUseCase: usecase.NewCreateShortURLUseCase(suite.repo, nil, nil),
+				repo:                  suite.repo,
+				taken:                 "race",
+			}
+
+			result, err := NewThirdPartyImporter(creator).Import(suite.testCtx, strings.NewReader(input), ThirdPartyImportOptions{Mapping: suite.mapping, Conflict: testCase.conflict})
+			if testCase.err != nil {
+				suite.ErrorIs(err, testCase.err)
+				return
+			}
+			suite.Require().NoError(err)
+
+			suite.Equal(testCase.imported, result.Imported)
+			suite.Equal(1, result.KeptAliases)
+			suite.Require().Len(result.Conflicts, 1)
+			suite.Equal(1, result.Conflicts[0].Record)
+			suite.Equal("race", result.Conflicts[0].ShortCode)
+			suite.Equal("short code was taken during import", result.Conflicts[0].Reason)
+			suite.Equal(testCase.created, result.Conflicts[0].ShortURI != "")
+
+			raced, err := suite.repo.GetShortURLByShortURI(context.Background(), "race")
+			suite.Require().NoError(err)
+			suite.Equal("https://race.ru", raced.LongURL, "short url created by other request must not be overwritten")
+
+			kept, err := suite.repo.GetShortURLByShortURI(context.Background(), "kept")
+			suite.Require().NoError(err)
+			suite.Equal("https://mail.ru", kept.LongURL)
+		})
+	}
+}
+
+func TestThirdPartyImportConflictTestSuite(t *testing.T) {
+	suite.Run(t, new(ThirdPartyImportConflictTestSuite))
+}
+
+func TestParseColumnMapping(t *testing.T) {
+	testCases := []struct {
+		name      string
+		layout    string
+		overrides string
+		expected  ColumnMapping
+		err       error
+	}{
+		{
+			name:     "generic layout by default",
+			expected: layouts[LayoutGeneric],
+		},
+		{
+			name:      "overrides",
+			layout:    LayoutYOURLS,
+			overrides: "original_url=Destination, short_code=Slug,title=",
+			expected:  ColumnMapping{OriginalURL: "Destination", ShortCode: "Slug"},
+		},
+		{
+			name:   "unsupported layout",
+			layout: "tinyurl",
+			err:    ErrUnsupportedLayout,
+		},
+		{
+			name:      "unknown field",
+			overrides: "clicks=Clicks",
+			err:       ErrInvalidColumnMapping,
+		},
+		{
+			name:      "override without column",
+			overrides: "title",
+			err:       ErrInvalidColumnMapping,
+		},
+		{
+			name:      "without original url",
+			overrides: "original_url=",
+			err:       ErrInvalidColumnMapping,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mapping, err := ParseColumnMapping(testCase.layout, testCase.overrides)
+			if testCase.err != nil {
+				if !errors.Is(err, testCase.err) {
+					t.Fatalf("expected error %v, got %v", testCase.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mapping != testCase.expected {
+				t.Errorf("expected mapping %+v, got %+v", testCase.expected, mapping)
+			}
+		})
+	}
+}
+
+func TestShortCodeOf(t *testing.T) {
+	for value, expected := range map[string]string{
+		"abc":                    "abc",
+		"https://bit.ly/abc":     "abc",
+		"https://bit.ly/abc/":    "abc",
+		"bit.ly/abc":             "abc",
+		"https://rebrand.ly/":    "",
+		"http://sho.rt/path/abc": "abc",
+	} {
+		if actual := shortCodeOf(value); actual != expected {
+			t.Errorf("shortCodeOf(%q) = %q, expected %q", value, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Package transfer выполняет выгрузку и загрузку всех коротких ссылок репозитория в форматах JSON Lines и CSV
+//
+// Выгрузка одного репозитория, загруженная в другой, переносит короткие ссылки между хранилищами
+// с сохранением ключей коротких ссылок, владельцев и признаков удаления.
+package transfer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"go.uber.org/zap"
+)
+
+var log = zap.Must(zap.NewDevelopment()).Sugar()
+
+// FormatJSONLines - формат "одна json-строка на короткую ссылку", поля совпадают с json-файлом хранилища
+// FormatCSV - формат CSV со строкой заголовка, сложные поля записываются json-строкой
+const (
+	FormatJSONLines = "jsonl"
+	FormatCSV       = "csv"
+)
+
+// progressInterval - количество коротких ссылок, после обработки которого в лог выводится прогресс
+const progressInterval = 1000
+
+// ErrUnsupportedFormat - неподдерживаемый формат выгрузки
+// ErrInvalidRecord - запись выгрузки не является короткой ссылкой
+var (
+	ErrUnsupportedFormat = errors.New("transfer: unsupported format")
+	ErrInvalidRecord     = errors.New("transfer: invalid record")
+)
+
+// CheckFormat проверяет, что format - поддерживаемый формат выгрузки
+func CheckFormat(format string) error {
+	if format != FormatJSONLines && format != FormatCSV {
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	return nil
+}
+
+// ContentType возвращает MIME-тип выгрузки в формате format
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+
+	return "application/x-ndjson"
+}
+
+// encoder записывает короткие ссылки в выгрузку
+type encoder interface {
+	encode(shortURLEntity entity.ShortURLEntity) error
+	flush() error
+}
+
+// decoder читает короткие ссылки из выгрузки, по окончании выгрузки возвращает io.EOF
+type decoder interface {
+	decode() (entity.ShortURLEntity, error)
+}
+
+func newEncoder(w io.Writer, format string) (encoder, error) {
+	if err := CheckFormat(format); err != nil {
+		return nil, err
+	}
+
+	if format == FormatCSV {
+		return &csvEncoder{writer: csv.NewWriter(w)}, nil
+	}
+
+	bufferedWriter := bufio.NewWriter(w)
+	return jsonLinesEncoder{writer: bufferedWriter, encoder: json.NewEncoder(bufferedWriter)}, nil
+}
+
+func newDecoder(r io.Reader, format string) (decoder, error) {
+	if err := CheckFormat(format); err != nil {
+		return nil, err
+	}
+
+	if format == FormatCSV {
+		reader := csv.NewReader(r)
+		reader.ReuseRecord = true
+		return &csvDecoder{reader: reader}, nil
+	}
+
+	return jsonLinesDecoder{decoder: json.NewDecoder(r)}, nil
+}
+
+type jsonLinesEncoder struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e jsonLinesEncoder) encode(shortURLEntity entity.ShortURLEntity) error {
+	return e.encoder.Encode(shortURLEntity)
+}
+
+func (e jsonLinesEncoder) flush() error {
+	return e.writer.Flush()
+}
+
+type jsonLinesDecoder struct {
+	decoder *json.Decoder
+}
+
+func (d jsonLinesDecoder) decode() (entity.ShortURLEntity, error) {
+	var shortURLEntity entity.ShortURLEntity
+	if err := d.decoder.Decode(&shortURLEntity); err != nil {
+		if errors.Is(err, io.EOF) {
+			return entity.ShortURLEntity{}, io.EOF
+		}
+
+		return entity.ShortURLEntity{}, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+	}
+
+	return shortURLEntity, nil
+}
@@ -0,0 +1,196 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/repository"
+	"github.com/vkhrushchev/urlshortener/internal/common"
+)
+
+type TransferTestSuite struct {
+	suite.Suite
+	source        *repository.InMemoryShortURLRepository
+	target        *repository.InMemoryShortURLRepository
+	testShortURLs []entity.ShortURLEntity
+}
+
+func (suite *TransferTestSuite) SetupTest() {
+	deletedAt := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	suite.testShortURLs = []entity.ShortURLEntity{
+		{
+			UUID:       uuid.NewString(),
+			ShortURI:   "abc",
+			LongURL:    "https://ya.ru",
+			UserID:     uuid.NewString(),
+			CreatedAt:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			Tags:       []string{"news", "search"},
+			ClickCount: 7,
+			UTMParams:  map[string]string{"utm_source": "mail"},
+		},
+		{
+			UUID:      uuid.NewString(),
+			ShortURI:  "cde",
+			LongURL:   "https://google.com",
+			UserID:    uuid.NewString(),
+			CreatedAt: time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC),
+			Deleted:   true,
+			DeletedAt: &deletedAt,
+			Notes:     "notes, with \"quotes\"\nand new line",
+		},
+	}
+
+	suite.source = repository.NewInMemoryShortURLRepository()
+	suite.target = repository.NewInMemoryShortURLRepository()
+	for i := range suite.testShortURLs {
+		shortURLEntity := suite.testShortURLs[i]
+		ctx := context.WithValue(context.Background(), common.UserIDContextKey, shortURLEntity.UserID)
+		_, err := suite.source.SaveShortURL(ctx, &shortURLEntity)
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *TransferTestSuite) export(format string) string {
+	var buffer bytes.Buffer
+	exportedCount, err := NewExporter(suite.source).Export(context.Background(), &buffer, format)
+	suite.Require().NoError(err)
+	suite.Require().Equal(len(suite.testShortURLs), exportedCount)
+
+	return buffer.String()
+}
+
+func (suite *TransferTestSuite) TestExportImport_roundTrip() {
+	for _, format := range []string{FormatJSONLines, FormatCSV} {
+		suite.Run(format, func() {
+			suite.SetupTest()
+			exported := suite.export(format)
+
+			result, err := NewImporter(suite.target).Import(context.Background(), strings.NewReader(exported), ImportOptions{Format: format})
+			suite.Require().NoError(err)
+			suite.Equal(ImportResult{Read: 2, Imported: 2}, result)
+
+			for _, expected := range suite.testShortURLs {
+				actual, err := suite.target.GetShortURLByShortURI(context.Background(), expected.ShortURI)
+				suite.Require().NoError(err)
+				suite.Equal(expected.UUID, actual.UUID)
+				suite.Equal(expected.UserID, actual.UserID)
+				suite.Equal(expected.Deleted, actual.Deleted)
+				suite.Equal(expected.Tags, actual.Tags)
+				suite.Equal(expected.UTMParams, actual.UTMParams)
+				suite.Equal(expected.ClickCount, actual.ClickCount)
+				suite.Equal(expected.Notes, actual.Notes)
+				suite.True(expected.CreatedAt.Equal(actual.CreatedAt))
+			}
+
+			userShortURLs, err := suite.target.GetShortURLsByUserID(context.Background(), suite.testShortURLs[0].UserID)
+			suite.Require().NoError(err)
+			suite.Len(userShortURLs, 1)
+
+			result, err = NewImporter(suite.target).Import(context.Background(), strings.NewReader(exported), ImportOptions{Format: format})
+			suite.Require().NoError(err)
+			suite.Equal(ImportResult{Read: 2, Existing: 2}, result, "repeated import must skip imported short urls")
+		})
+	}
+}
+
+func (suite *TransferTestSuite) TestImport_dryRun() {
+	exported := suite.export(FormatJSONLines)
+	duplicated := exported + strings.Replace(strings.SplitN(exported, "\n", 2)[0], suite.testShortURLs[0].UUID, uuid.NewString(), 1) + "\n"
+
+	result, err := NewImporter(suite.target).Import(context.Background(), strings.NewReader(duplicated), ImportOptions{Format: FormatJSONLines, DryRun: true})
+	suite.Require().NoError(err)
+	suite.Equal(ImportResult{Read: 3, Imported: 2, Conflicts: 1}, result)
+
+	_, err = suite.target.GetShortURLByShortURI(context.Background(), suite.testShortURLs[0].ShortURI)
+	suite.ErrorIs(err, repository.ErrNotFound, "dry run must not save short urls")
+}
+
+func (suite *TransferTestSuite) TestImport_conflict() {
+	exported := suite.export(FormatCSV)
+
+	conflicting := suite.testShortURLs[0]
+	conflicting.UUID = uuid.NewString()
+	ctx := context.WithValue(context.Background(), common.UserIDContextKey, conflicting.UserID)
+	_, err := suite.target.SaveShortURL(ctx, &conflicting)
+	suite.Require().NoError(err)
+
+	result, err := NewImporter(suite.target).Import(context.Background(), strings.NewReader(exported), ImportOptions{Format: FormatCSV, Conflict: ConflictSkip})
+	suite.Require().NoError(err)
+	suite.Equal(ImportResult{Read: 2, Imported: 1, Conflicts: 1}, result)
+
+	actual, err := suite.target.GetShortURLByShortURI(context.Background(), conflicting.ShortURI)
+	suite.Require().NoError(err)
+	suite.Equal(conflicting.UUID, actual.UUID, "conflicting short url must not be overwritten")
+
+	_, err = NewImporter(suite.target).Import(context.Background(), strings.NewReader(exported), ImportOptions{Format: FormatCSV, Conflict: ConflictFail})
+	suite.ErrorIs(err, ErrConflict)
+}
+
+func (suite *TransferTestSuite) TestImport_invalidInput() {
+	testCases := []struct {
+		name    string
+		input   string
+		options ImportOptions
+		err     error
+	}{
+		{
+			name:    "unsupported format",
+			options: ImportOptions{Format: "xml"},
+			err:     ErrUnsupportedFormat,
+		},
+		{
+			name:    "unsupported conflict policy",
+			options: ImportOptions{Format: FormatCSV, Conflict: "overwrite"},
+			err:     ErrUnsupportedConflictPolicy,
+		},
+		{
+			name:    "csv without required column",
+			input:   "uuid,short_url\n1,abc\n",
+			options: ImportOptions{Format: FormatCSV},
+			err:     ErrInvalidRecord,
+		},
+		{
+			name:    "csv with invalid value",
+			input:   "short_url,original_url,is_deleted\nabc,https://ya.ru,maybe\n",
+			options: ImportOptions{Format: FormatCSV},
+			err:     ErrInvalidRecord,
+		},
+		{
+			name:    "jsonl without original url",
+			input:   `{"short_url":"abc"}` + "\n",
+			options: ImportOptions{Format: FormatJSONLines},
+			err:     ErrInvalidRecord,
+		},
+		{
+			name:    "malformed jsonl",
+			input:   "{\n",
+			options: ImportOptions{Format: FormatJSONLines},
+			err:     ErrInvalidRecord,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			_, err := NewImporter(suite.target).Import(context.Background(), strings.NewReader(testCase.input), testCase.options)
+			suite.ErrorIs(err, testCase.err)
+		})
+	}
+}
+
+func (suite *TransferTestSuite) TestExport_emptyCSV() {
+	var buffer bytes.Buffer
+	exportedCount, err := NewExporter(repository.NewInMemoryShortURLRepository()).Export(context.Background(), &buffer, FormatCSV)
+	suite.Require().NoError(err)
+	suite.Equal(0, exportedCount)
+	suite.True(strings.HasPrefix(buffer.String(), "uuid,short_url,original_url,"), "empty csv export must contain header")
+}
+
+func TestTransferTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferTestSuite))
+}
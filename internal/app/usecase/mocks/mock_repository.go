@@ -7,9 +7,11 @@ package mock_usecase
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/vkhrushchev/urlshortener/internal/app/entity"
+	preview "github.com/vkhrushchev/urlshortener/internal/app/preview"
 )
 
 // MockshortURLRepository is a mock of shortURLRepository interface.
@@ -35,20 +37,65 @@ func (m *MockshortURLRepository) EXPECT() *MockshortURLRepositoryMockRecorder {
 	return m.recorder
 }
 
-// DeleteShortURLsByShortURIs mocks base method.
-func (m *MockshortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) error {
+// ConsumeShortURLClick mocks base method.
+func (m *MockshortURLRepository) ConsumeShortURLClick(ctx context.Context, shortURI string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteShortURLsByShortURIs", ctx, shortURIs)
+	ret := m.ctrl.Call(m, "ConsumeShortURLClick", ctx, shortURI)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// ConsumeShortURLClick indicates an expected call of ConsumeShortURLClick.
+func (mr *MockshortURLRepositoryMockRecorder) ConsumeShortURLClick(ctx, shortURI interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeShortURLClick", reflect.TypeOf((*MockshortURLRepository)(nil).ConsumeShortURLClick), ctx, shortURI)
+}
+
+// DeleteShortURLsByShortURIs mocks base method.
+func (m *MockshortURLRepository) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShortURLsByShortURIs", ctx, shortURIs)
+	ret0, _ := ret[0].([]entity.DeleteShortURLResultEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteShortURLsByShortURIs indicates an expected call of DeleteShortURLsByShortURIs.
 func (mr *MockshortURLRepositoryMockRecorder) DeleteShortURLsByShortURIs(ctx, shortURIs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShortURLsByShortURIs", reflect.TypeOf((*MockshortURLRepository)(nil).DeleteShortURLsByShortURIs), ctx, shortURIs)
 }
 
+// GetDeletedShortURLsByUserID mocks base method.
+func (m *MockshortURLRepository) GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedShortURLsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]entity.ShortURLEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedShortURLsByUserID indicates an expected call of GetDeletedShortURLsByUserID.
+func (mr *MockshortURLRepositoryMockRecorder) GetDeletedShortURLsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedShortURLsByUserID", reflect.TypeOf((*MockshortURLRepository)(nil).GetDeletedShortURLsByUserID), ctx, userID)
+}
+
+// GetRedirectShortURLByShortURI mocks base method.
+func (m *MockshortURLRepository) GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedirectShortURLByShortURI", ctx, shortURI)
+	ret0, _ := ret[0].(entity.ShortURLEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedirectShortURLByShortURI indicates an expected call of GetRedirectShortURLByShortURI.
+func (mr *MockshortURLRepositoryMockRecorder) GetRedirectShortURLByShortURI(ctx, shortURI interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedirectShortURLByShortURI", reflect.TypeOf((*MockshortURLRepository)(nil).GetRedirectShortURLByShortURI), ctx, shortURI)
+}
+
 // GetShortURLByShortURI mocks base method.
 func (m *MockshortURLRepository) GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +111,22 @@ func (mr *MockshortURLRepositoryMockRecorder) GetShortURLByShortURI(ctx, shortUR
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURLByShortURI", reflect.TypeOf((*MockshortURLRepository)(nil).GetShortURLByShortURI), ctx, shortURI)
 }
 
+// GetShortURLsByQuery mocks base method.
+func (m *MockshortURLRepository) GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortURLsByQuery", ctx, query)
+	ret0, _ := ret[0].([]entity.ShortURLEntity)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetShortURLsByQuery indicates an expected call of GetShortURLsByQuery.
+func (mr *MockshortURLRepositoryMockRecorder) GetShortURLsByQuery(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURLsByQuery", reflect.TypeOf((*MockshortURLRepository)(nil).GetShortURLsByQuery), ctx, query)
+}
+
 // GetShortURLsByUserID mocks base method.
 func (m *MockshortURLRepository) GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +142,21 @@ func (mr *MockshortURLRepositoryMockRecorder) GetShortURLsByUserID(ctx, userID i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURLsByUserID", reflect.TypeOf((*MockshortURLRepository)(nil).GetShortURLsByUserID), ctx, userID)
 }
 
+// RestoreShortURLsByShortURIs mocks base method.
+func (m *MockshortURLRepository) RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreShortURLsByShortURIs", ctx, shortURIs)
+	ret0, _ := ret[0].([]entity.RestoreShortURLResultEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreShortURLsByShortURIs indicates an expected call of RestoreShortURLsByShortURIs.
+func (mr *MockshortURLRepositoryMockRecorder) RestoreShortURLsByShortURIs(ctx, shortURIs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreShortURLsByShortURIs", reflect.TypeOf((*MockshortURLRepository)(nil).RestoreShortURLsByShortURIs), ctx, shortURIs)
+}
+
 // SaveShortURL mocks base method.
 func (m *MockshortURLRepository) SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +187,491 @@ func (mr *MockshortURLRepositoryMockRecorder) SaveShortURLs(ctx, shortURLEntitie
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveShortURLs", reflect.TypeOf((*MockshortURLRepository)(nil).SaveShortURLs), ctx, shortURLEntities)
 }
 
+// UpdateShortURL mocks base method.
+func (m *MockshortURLRepository) UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShortURL", ctx, shortURLEntity)
+	ret0, _ := ret[0].(entity.ShortURLEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateShortURL indicates an expected call of UpdateShortURL.
+func (mr *MockshortURLRepositoryMockRecorder) UpdateShortURL(ctx, shortURLEntity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShortURL", reflect.TypeOf((*MockshortURLRepository)(nil).UpdateShortURL), ctx, shortURLEntity)
+}
+
+// MockpurgeRepository is a mock of purgeRepository interface.
+type MockpurgeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockpurgeRepositoryMockRecorder
+}
+
+// MockpurgeRepositoryMockRecorder is the mock recorder for MockpurgeRepository.
+type MockpurgeRepositoryMockRecorder struct {
+	mock *MockpurgeRepository
+}
+
+// NewMockpurgeRepository creates a new mock instance.
+func NewMockpurgeRepository(ctrl *gomock.Controller) *MockpurgeRepository {
+	mock := &MockpurgeRepository{ctrl: ctrl}
+	mock.recorder = &MockpurgeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpurgeRepository) EXPECT() *MockpurgeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// PurgeDeletedShortURLs mocks base method.
+func (m *MockpurgeRepository) PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedShortURLs", ctx, deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedShortURLs indicates an expected call of PurgeDeletedShortURLs.
+func (mr *MockpurgeRepositoryMockRecorder) PurgeDeletedShortURLs(ctx, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedShortURLs", reflect.TypeOf((*MockpurgeRepository)(nil).PurgeDeletedShortURLs), ctx, deletedBefore)
+}
+
+// MockdeleteJobRepository is a mock of deleteJobRepository interface.
+type MockdeleteJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockdeleteJobRepositoryMockRecorder
+}
+
+// MockdeleteJobRepositoryMockRecorder is the mock recorder for MockdeleteJobRepository.
+type MockdeleteJobRepositoryMockRecorder struct {
+	mock *MockdeleteJobRepository
+}
+
+// NewMockdeleteJobRepository creates a new mock instance.
+func NewMockdeleteJobRepository(ctrl *gomock.Controller) *MockdeleteJobRepository {
+	mock := &MockdeleteJobRepository{ctrl: ctrl}
+	mock.recorder = &MockdeleteJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockdeleteJobRepository) EXPECT() *MockdeleteJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetDeleteJobByID mocks base method.
+func (m *MockdeleteJobRepository) GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleteJobByID", ctx, id)
+	ret0, _ := ret[0].(entity.DeleteJobEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleteJobByID indicates an expected call of GetDeleteJobByID.
+func (mr *MockdeleteJobRepositoryMockRecorder) GetDeleteJobByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleteJobByID", reflect.TypeOf((*MockdeleteJobRepository)(nil).GetDeleteJobByID), ctx, id)
+}
+
+// GetDeleteJobsByStatus mocks base method.
+func (m *MockdeleteJobRepository) GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleteJobsByStatus", ctx, status)
+	ret0, _ := ret[0].([]entity.DeleteJobEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleteJobsByStatus indicates an expected call of GetDeleteJobsByStatus.
+func (mr *MockdeleteJobRepositoryMockRecorder) GetDeleteJobsByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleteJobsByStatus", reflect.TypeOf((*MockdeleteJobRepository)(nil).GetDeleteJobsByStatus), ctx, status)
+}
+
+// SaveDeleteJob mocks base method.
+func (m *MockdeleteJobRepository) SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeleteJob", ctx, deleteJobEntity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeleteJob indicates an expected call of SaveDeleteJob.
+func (mr *MockdeleteJobRepositoryMockRecorder) SaveDeleteJob(ctx, deleteJobEntity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeleteJob", reflect.TypeOf((*MockdeleteJobRepository)(nil).SaveDeleteJob), ctx, deleteJobEntity)
+}
+
+// MockpreviewRepository is a mock of previewRepository interface.
+type MockpreviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockpreviewRepositoryMockRecorder
+}
+
+// MockpreviewRepositoryMockRecorder is the mock recorder for MockpreviewRepository.
+type MockpreviewRepositoryMockRecorder struct {
+	mock *MockpreviewRepository
+}
+
+// NewMockpreviewRepository creates a new mock instance.
+func NewMockpreviewRepository(ctrl *gomock.Controller) *MockpreviewRepository {
+	mock := &MockpreviewRepository{ctrl: ctrl}
+	mock.recorder = &MockpreviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpreviewRepository) EXPECT() *MockpreviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// UpdateShortURLPreview mocks base method.
+func (m *MockpreviewRepository) UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShortURLPreview", ctx, shortURI, preview)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateShortURLPreview indicates an expected call of UpdateShortURLPreview.
+func (mr *MockpreviewRepositoryMockRecorder) UpdateShortURLPreview(ctx, shortURI, preview interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShortURLPreview", reflect.TypeOf((*MockpreviewRepository)(nil).UpdateShortURLPreview), ctx, shortURI, preview)
+}
+
+// MockpreviewFetcher is a mock of previewFetcher interface.
+type MockpreviewFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockpreviewFetcherMockRecorder
+}
+
+// MockpreviewFetcherMockRecorder is the mock recorder for MockpreviewFetcher.
+type MockpreviewFetcherMockRecorder struct {
+	mock *MockpreviewFetcher
+}
+
+// NewMockpreviewFetcher creates a new mock instance.
+func NewMockpreviewFetcher(ctrl *gomock.Controller) *MockpreviewFetcher {
+	mock := &MockpreviewFetcher{ctrl: ctrl}
+	mock.recorder = &MockpreviewFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpreviewFetcher) EXPECT() *MockpreviewFetcherMockRecorder {
+	return m.recorder
+}
+
+// Fetch mocks base method.
+func (m *MockpreviewFetcher) Fetch(ctx context.Context, rawURL string) (preview.Preview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, rawURL)
+	ret0, _ := ret[0].(preview.Preview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockpreviewFetcherMockRecorder) Fetch(ctx, rawURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockpreviewFetcher)(nil).Fetch), ctx, rawURL)
+}
+
+// MockshortURLPreviewer is a mock of shortURLPreviewer interface.
+type MockshortURLPreviewer struct {
+	ctrl     *gomock.Controller
+	recorder *MockshortURLPreviewerMockRecorder
+}
+
+// MockshortURLPreviewerMockRecorder is the mock recorder for MockshortURLPreviewer.
+type MockshortURLPreviewerMockRecorder struct {
+	mock *MockshortURLPreviewer
+}
+
+// NewMockshortURLPreviewer creates a new mock instance.
+func NewMockshortURLPreviewer(ctrl *gomock.Controller) *MockshortURLPreviewer {
+	mock := &MockshortURLPreviewer{ctrl: ctrl}
+	mock.recorder = &MockshortURLPreviewerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockshortURLPreviewer) EXPECT() *MockshortURLPreviewerMockRecorder {
+	return m.recorder
+}
+
+// EnqueueShortURL mocks base method.
+func (m *MockshortURLPreviewer) EnqueueShortURL(shortURI, longURL string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EnqueueShortURL", shortURI, longURL)
+}
+
+// EnqueueShortURL indicates an expected call of EnqueueShortURL.
+func (mr *MockshortURLPreviewerMockRecorder) EnqueueShortURL(shortURI, longURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueShortURL", reflect.TypeOf((*MockshortURLPreviewer)(nil).EnqueueShortURL), shortURI, longURL)
+}
+
+// MockshortDomainResolver is a mock of shortDomainResolver interface.
+type MockshortDomainResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockshortDomainResolverMockRecorder
+}
+
+// MockshortDomainResolverMockRecorder is the mock recorder for MockshortDomainResolver.
+type MockshortDomainResolverMockRecorder struct {
+	mock *MockshortDomainResolver
+}
+
+// NewMockshortDomainResolver creates a new mock instance.
+func NewMockshortDomainResolver(ctrl *gomock.Controller) *MockshortDomainResolver {
+	mock := &MockshortDomainResolver{ctrl: ctrl}
+	mock.recorder = &MockshortDomainResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockshortDomainResolver) EXPECT() *MockshortDomainResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockshortDomainResolver) Resolve(domain string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", domain)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockshortDomainResolverMockRecorder) Resolve(domain interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockshortDomainResolver)(nil).Resolve), domain)
+}
+
+// MocksplitClickRepository is a mock of splitClickRepository interface.
+type MocksplitClickRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MocksplitClickRepositoryMockRecorder
+}
+
+// MocksplitClickRepositoryMockRecorder is the mock recorder for MocksplitClickRepository.
+type MocksplitClickRepositoryMockRecorder struct {
+	mock *MocksplitClickRepository
+}
+
+// NewMocksplitClickRepository creates a new mock instance.
+func NewMocksplitClickRepository(ctrl *gomock.Controller) *MocksplitClickRepository {
+	mock := &MocksplitClickRepository{ctrl: ctrl}
+	mock.recorder = &MocksplitClickRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksplitClickRepository) EXPECT() *MocksplitClickRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSplitClicks mocks base method.
+func (m *MocksplitClickRepository) GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSplitClicks", ctx, shortURI)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSplitClicks indicates an expected call of GetSplitClicks.
+func (mr *MocksplitClickRepositoryMockRecorder) GetSplitClicks(ctx, shortURI interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSplitClicks", reflect.TypeOf((*MocksplitClickRepository)(nil).GetSplitClicks), ctx, shortURI)
+}
+
+// IncrementSplitClicks mocks base method.
+func (m *MocksplitClickRepository) IncrementSplitClicks(ctx context.Context, shortURI, variant string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementSplitClicks", ctx, shortURI, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementSplitClicks indicates an expected call of IncrementSplitClicks.
+func (mr *MocksplitClickRepositoryMockRecorder) IncrementSplitClicks(ctx, shortURI, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementSplitClicks", reflect.TypeOf((*MocksplitClickRepository)(nil).IncrementSplitClicks), ctx, shortURI, variant)
+}
+
+// MockcountryClickRepository is a mock of countryClickRepository interface.
+type MockcountryClickRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockcountryClickRepositoryMockRecorder
+}
+
+// MockcountryClickRepositoryMockRecorder is the mock recorder for MockcountryClickRepository.
+type MockcountryClickRepositoryMockRecorder struct {
+	mock *MockcountryClickRepository
+}
+
+// NewMockcountryClickRepository creates a new mock instance.
+func NewMockcountryClickRepository(ctrl *gomock.Controller) *MockcountryClickRepository {
+	mock := &MockcountryClickRepository{ctrl: ctrl}
+	mock.recorder = &MockcountryClickRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcountryClickRepository) EXPECT() *MockcountryClickRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetCountryClicks mocks base method.
+func (m *MockcountryClickRepository) GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountryClicks", ctx, shortURI)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountryClicks indicates an expected call of GetCountryClicks.
+func (mr *MockcountryClickRepositoryMockRecorder) GetCountryClicks(ctx, shortURI interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountryClicks", reflect.TypeOf((*MockcountryClickRepository)(nil).GetCountryClicks), ctx, shortURI)
+}
+
+// IncrementCountryClicks mocks base method.
+func (m *MockcountryClickRepository) IncrementCountryClicks(ctx context.Context, shortURI, country string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementCountryClicks", ctx, shortURI, country)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementCountryClicks indicates an expected call of IncrementCountryClicks.
+func (mr *MockcountryClickRepositoryMockRecorder) IncrementCountryClicks(ctx, shortURI, country interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementCountryClicks", reflect.TypeOf((*MockcountryClickRepository)(nil).IncrementCountryClicks), ctx, shortURI, country)
+}
+
+// MockattemptLimiter is a mock of attemptLimiter interface.
+type MockattemptLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockattemptLimiterMockRecorder
+}
+
+// MockattemptLimiterMockRecorder is the mock recorder for MockattemptLimiter.
+type MockattemptLimiterMockRecorder struct {
+	mock *MockattemptLimiter
+}
+
+// NewMockattemptLimiter creates a new mock instance.
+func NewMockattemptLimiter(ctrl *gomock.Controller) *MockattemptLimiter {
+	mock := &MockattemptLimiter{ctrl: ctrl}
+	mock.recorder = &MockattemptLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockattemptLimiter) EXPECT() *MockattemptLimiterMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockattemptLimiter) Acquire(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockattemptLimiterMockRecorder) Acquire(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockattemptLimiter)(nil).Acquire), key)
+}
+
+// Release mocks base method.
+func (m *MockattemptLimiter) Release(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Release", key)
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockattemptLimiterMockRecorder) Release(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockattemptLimiter)(nil).Release), key)
+}
+
+// Reset mocks base method.
+func (m *MockattemptLimiter) Reset(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reset", key)
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockattemptLimiterMockRecorder) Reset(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockattemptLimiter)(nil).Reset), key)
+}
+
+// MockuserSettingsRepository is a mock of userSettingsRepository interface.
+type MockuserSettingsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockuserSettingsRepositoryMockRecorder
+}
+
+// MockuserSettingsRepositoryMockRecorder is the mock recorder for MockuserSettingsRepository.
+type MockuserSettingsRepositoryMockRecorder struct {
+	mock *MockuserSettingsRepository
+}
+
+// NewMockuserSettingsRepository creates a new mock instance.
+func NewMockuserSettingsRepository(ctrl *gomock.Controller) *MockuserSettingsRepository {
+	mock := &MockuserSettingsRepository{ctrl: ctrl}
+	mock.recorder = &MockuserSettingsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockuserSettingsRepository) EXPECT() *MockuserSettingsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUserSettings mocks base method.
+func (m *MockuserSettingsRepository) GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSettings", ctx, userID)
+	ret0, _ := ret[0].(entity.UserSettingsEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSettings indicates an expected call of GetUserSettings.
+func (mr *MockuserSettingsRepositoryMockRecorder) GetUserSettings(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSettings", reflect.TypeOf((*MockuserSettingsRepository)(nil).GetUserSettings), ctx, userID)
+}
+
+// SaveUserSettings mocks base method.
+func (m *MockuserSettingsRepository) SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserSettings", ctx, userSettingsEntity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserSettings indicates an expected call of SaveUserSettings.
+func (mr *MockuserSettingsRepositoryMockRecorder) SaveUserSettings(ctx, userSettingsEntity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSettings", reflect.TypeOf((*MockuserSettingsRepository)(nil).SaveUserSettings), ctx, userSettingsEntity)
+}
+
 // MockstatsRepository is a mock of statsRepository interface.
 type MockstatsRepository struct {
 	ctrl     *gomock.Controller
@@ -1,16 +1,35 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/vkhrushchev/urlshortener/internal/common"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/vkhrushchev/urlshortener/internal/app/domain"
 	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/fallback"
+	"github.com/vkhrushchev/urlshortener/internal/app/preview"
+	"github.com/vkhrushchev/urlshortener/internal/app/redirect"
 	"github.com/vkhrushchev/urlshortener/internal/app/repository"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
+	"github.com/vkhrushchev/urlshortener/internal/app/throttle"
 	"github.com/vkhrushchev/urlshortener/internal/util"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
 )
 
 //go:generate mockgen -source ./usecase.go -destination mocks/mock_repository.go
@@ -20,20 +39,126 @@ var log = zap.Must(zap.NewDevelopment()).Sugar()
 // ErrConflict - короткая ссылка уже существует
 // ErrNotFound - короткая ссылка не найдена
 // ErrUnexpected - непредвиденная ошибка
+// ErrInvalidQuery - некорректные параметры запроса
+// ErrInvalidMetadata - некорректные метаданные короткой ссылки
+// ErrPasswordRequired - для перехода по короткой ссылке требуется пароль
+// ErrInvalidPassword - неверный пароль короткой ссылки
+// ErrTooManyAttempts - превышено количество попыток ввода пароля короткой ссылки
+// ErrClicksExhausted - переходы по короткой ссылке исчерпаны
+// ErrNotYetActive - период действия короткой ссылки еще не начался
+// ErrExpired - период действия короткой ссылки закончился
+// ErrInvalidSettings - некорректные настройки пользователя
 var (
-	ErrConflict   = errors.New("conflict")
-	ErrNotFound   = errors.New("entity not found")
-	ErrUnexpected = errors.New("unexpected error")
+	ErrConflict         = errors.New("conflict")
+	ErrNotFound         = errors.New("entity not found")
+	ErrUnexpected       = errors.New("unexpected error")
+	ErrInvalidQuery     = errors.New("invalid query")
+	ErrInvalidMetadata  = errors.New("invalid metadata")
+	ErrPasswordRequired = errors.New("password required")
+	ErrInvalidPassword  = errors.New("invalid password")
+	ErrTooManyAttempts  = errors.New("too many attempts")
+	ErrClicksExhausted  = errors.New("clicks exhausted")
+	ErrNotYetActive     = errors.New("not yet active")
+	ErrExpired          = errors.New("expired")
+	ErrInvalidSettings  = errors.New("invalid settings")
 )
 
+// ShortURLTitleMaxLength - максимальная длина названия короткой ссылки
+// ShortURLDescriptionMaxLength - максимальная длина описания короткой ссылки
+// ShortURLNotesMaxLength - максимальная длина заметок к короткой ссылке
+// ShortURLTagsMaxCount - максимальное количество тегов короткой ссылки
+// ShortURLTagMaxLength - максимальная длина тега короткой ссылки
+// ShortURLPasswordMinLength - минимальная длина пароля короткой ссылки
+// ShortURLPasswordMaxLength - максимальная длина пароля короткой ссылки в байтах, ограничена bcrypt
+// ShortURLAliasMaxLength - максимальная длина идентификатора короткой ссылки, выбранного пользователем
+const (
+	ShortURLTitleMaxLength       = 256
+	ShortURLDescriptionMaxLength = 1024
+	ShortURLNotesMaxLength       = 4096
+	ShortURLTagsMaxCount         = 32
+	ShortURLTagMaxLength         = 64
+	ShortURLPasswordMinLength    = 4
+	ShortURLPasswordMaxLength    = 72
+	ShortURLAliasMaxLength       = 64
+)
+
+// shortURIGenerateAttempts - количество попыток сохранения короткой ссылки со случайным идентификатором,
+// после каждой попытки, в которой идентификатор оказался занят, генерируется новый идентификатор
+const shortURIGenerateAttempts = 5
+
+// aliasPattern - допустимые символы идентификатора короткой ссылки, выбранного пользователем
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// reservedAliases - идентификаторы, совпадающие с путями сервиса
+var reservedAliases = map[string]bool{
+	"api":  true,
+	"ping": true,
+}
+
 type shortURLRepository interface {
 	SaveShortURL(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error)
 	SaveShortURLs(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error)
 
 	GetShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
+	GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (entity.ShortURLEntity, error)
 	GetShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+	GetShortURLsByQuery(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error)
+
+	GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]entity.ShortURLEntity, error)
+
+	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.DeleteShortURLResultEntity, error)
+	RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]entity.RestoreShortURLResultEntity, error)
+
+	UpdateShortURL(ctx context.Context, shortURLEntity entity.ShortURLEntity) (entity.ShortURLEntity, error)
+
+	ConsumeShortURLClick(ctx context.Context, shortURI string) error
+}
+
+type purgeRepository interface {
+	PurgeDeletedShortURLs(ctx context.Context, deletedBefore time.Time) (int, error)
+}
+
+type deleteJobRepository interface {
+	SaveDeleteJob(ctx context.Context, deleteJobEntity *entity.DeleteJobEntity) error
+	GetDeleteJobByID(ctx context.Context, id string) (entity.DeleteJobEntity, error)
+	GetDeleteJobsByStatus(ctx context.Context, status string) ([]entity.DeleteJobEntity, error)
+}
+
+type previewRepository interface {
+	UpdateShortURLPreview(ctx context.Context, shortURI string, preview entity.ShortURLPreviewEntity) error
+}
+
+type previewFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (preview.Preview, error)
+}
+
+type shortURLPreviewer interface {
+	EnqueueShortURL(shortURI string, longURL string)
+}
+
+type shortDomainResolver interface {
+	Resolve(domain string) (string, error)
+}
+
+type splitClickRepository interface {
+	IncrementSplitClicks(ctx context.Context, shortURI string, variant string) error
+	GetSplitClicks(ctx context.Context, shortURI string) (map[string]int64, error)
+}
+
+type countryClickRepository interface {
+	IncrementCountryClicks(ctx context.Context, shortURI string, country string) error
+	GetCountryClicks(ctx context.Context, shortURI string) (map[string]int64, error)
+}
+
+type attemptLimiter interface {
+	Acquire(key string) bool
+	Release(key string)
+	Reset(key string)
+}
 
-	DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) error
+type userSettingsRepository interface {
+	GetUserSettings(ctx context.Context, userID string) (entity.UserSettingsEntity, error)
+	SaveUserSettings(ctx context.Context, userSettingsEntity entity.UserSettingsEntity) error
 }
 
 type statsRepository interface {
@@ -42,65 +167,233 @@ type statsRepository interface {
 
 // CreateShortURLUseCase реализует интерфейс ICreateShortURLUseCase
 type CreateShortURLUseCase struct {
-	repo shortURLRepository
+	repo      shortURLRepository
+	previewer shortURLPreviewer
+	domains   shortDomainResolver
 }
 
 // NewCreateShortURLUseCase создает экземпляр CreateShortURLUseCase
-func NewCreateShortURLUseCase(repo shortURLRepository) *CreateShortURLUseCase {
-	return &CreateShortURLUseCase{repo: repo}
+//
+//	previewer - получение превью страниц для созданных коротких ссылок, nil - превью не получаются
+//	domains - настроенные домены коротких ссылок, nil - короткие ссылки создаются только на домене по умолчанию
+func NewCreateShortURLUseCase(repo shortURLRepository, previewer shortURLPreviewer, domains shortDomainResolver) *CreateShortURLUseCase {
+	return &CreateShortURLUseCase{repo: repo, previewer: previewer, domains: domains}
+}
+
+// newShortURI возвращает ключ новой короткой ссылки на домене shortDomain с идентификатором alias,
+// "" - со случайным идентификатором
+func (uc *CreateShortURLUseCase) newShortURI(shortDomain string, alias string) (string, error) {
+	if shortDomain != "" {
+		if uc.domains == nil {
+			return "", fmt.Errorf("%w: %q", shortdomain.ErrUnknownDomain, shortDomain)
+		}
+
+		var err error
+		shortDomain, err = uc.domains.Resolve(shortDomain)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if alias == "" {
+		alias = util.RandStringRunes(10)
+	}
+
+	return shortdomain.Key(shortDomain, alias), nil
+}
+
+// CheckAlias проверяет, что alias можно использовать как идентификатор короткой ссылки
+func CheckAlias(alias string) error {
+	if alias == "" || len(alias) > ShortURLAliasMaxLength {
+		return fmt.Errorf("alias length must be from 1 to %d", ShortURLAliasMaxLength)
+	}
+	if !aliasPattern.MatchString(alias) {
+		return errors.New("alias must contain only latin letters, digits, '-' and '_'")
+	}
+	if reservedAliases[strings.ToLower(alias)] {
+		return fmt.Errorf("alias %q is reserved", alias)
+	}
+
+	return nil
+}
+
+// IsAliasAvailable проверяет, что идентификатор alias на домене shortDomain не занят короткой ссылкой, в том числе удаленной
+func (uc *CreateShortURLUseCase) IsAliasAvailable(ctx context.Context, shortDomain string, alias string) (bool, error) {
+	if err := CheckAlias(alias); err != nil {
+		log.Infow("use_case: invalid short url alias", "alias", alias, "error", err)
+		return false, ErrInvalidMetadata
+	}
+
+	shortURI, err := uc.newShortURI(shortDomain, alias)
+	if err != nil {
+		log.Infow("use_case: invalid short url domain", "alias", alias, "error", err)
+		return false, ErrInvalidMetadata
+	}
+
+	return uc.isShortURIFree(ctx, shortURI)
 }
 
-// CreateShortURL создает короткую ссылку
-func (uc *CreateShortURLUseCase) CreateShortURL(ctx context.Context, url string) (domain.ShortURLDomain, error) {
+// CreateShortURL создает короткую ссылку с метаданными metadata
+func (uc *CreateShortURLUseCase) CreateShortURL(ctx context.Context, url string, metadata domain.ShortURLMetadataDomain) (domain.ShortURLDomain, error) {
 	userID := ctx.Value(common.UserIDContextKey).(string)
 	log.Infow("use_case: CreateShortURL", "url", url, "userID", userID)
 
+	metadata, err := normalizeMetadata(metadata)
+	if err != nil {
+		log.Infow("use_case: invalid short url metadata", "url", url, "userID", userID, "error", err)
+		return domain.ShortURLDomain{}, ErrInvalidMetadata
+	}
+
+	shortURI, err := uc.newShortURI(metadata.ShortDomain, "")
+	if err != nil {
+		log.Infow("use_case: invalid short url domain", "url", url, "userID", userID, "error", err)
+		return domain.ShortURLDomain{}, ErrInvalidMetadata
+	}
+
 	shortURLEntity := &entity.ShortURLEntity{
-		UUID:     uuid.NewString(),
-		ShortURI: util.RandStringRunes(10),
-		LongURL:  url,
-		UserID:   userID,
-		Deleted:  false,
+		UUID:        uuid.NewString(),
+		ShortURI:    shortURI,
+		LongURL:     url,
+		UserID:      userID,
+		Deleted:     false,
+		Title:       metadata.Title,
+		Description: metadata.Description,
+		Notes:       metadata.Notes,
+		Tags:        metadata.Tags,
+
+		RedirectStatus:   metadata.RedirectStatus,
+		QueryPassthrough: metadata.QueryPassthrough,
+		PathPassthrough:  metadata.PathPassthrough,
+		UTMParams:        metadata.UTMParams,
+		TargetingRules:   metadata.TargetingRules,
+		SplitVariants:    metadata.SplitVariants,
+		MaxClicks:        metadata.MaxClicks,
+		NotBefore:        metadata.NotBefore,
+		NotAfter:         metadata.NotAfter,
+		Disabled:         metadata.Disabled,
+		FallbackURL:      metadata.FallbackURL,
 	}
 
-	shortURLEntity, err := uc.repo.SaveShortURL(ctx, shortURLEntity)
-	if err != nil && errors.Is(err, repository.ErrConflict) {
-		log.Infow("use_case: conflict with existed entity", "url", url, "userID", userID)
-		return domain.ShortURLDomain(*shortURLEntity), ErrConflict
-	} else if err != nil {
-		log.Errorw("use_case: failed to save short url", "error", err)
+	shortURLEntity.PasswordHash, err = hashPassword(metadata.Password)
+	if err != nil {
+		log.Errorw("use_case: failed to hash short url password", "error", err)
+		return domain.ShortURLDomain{}, ErrUnexpected
+	}
+
+	var savedShortURLEntity *entity.ShortURLEntity
+	for attempt := 1; ; attempt++ {
+		savedShortURLEntity, err = uc.repo.SaveShortURL(ctx, shortURLEntity)
+		if err == nil {
+			break
+		}
+
+		if errors.Is(err, repository.ErrConflict) && savedShortURLEntity != nil {
+			log.Infow("use_case: conflict with existed entity", "url", url, "userID", userID)
+			return domain.ShortURLDomain(*savedShortURLEntity), ErrConflict
+		} else if errors.Is(err, repository.ErrConflict) && attempt < shortURIGenerateAttempts {
+			// конфликт без существующей короткой ссылки - случайный идентификатор уже занят
+			log.Infow("use_case: generated short uri is taken, retrying", "shortURI", shortURLEntity.ShortURI, "attempt", attempt)
+			shortURLEntity.ShortURI, err = uc.newShortURI(metadata.ShortDomain, "")
+			if err != nil {
+				log.Errorw("use_case: failed to generate short uri", "error", err)
+				return domain.ShortURLDomain{}, ErrUnexpected
+			}
+			continue
+		}
+
+		log.Errorw("use_case: failed to save short url", "attempt", attempt, "error", err)
 		return domain.ShortURLDomain{}, ErrUnexpected
 	}
 
-	return domain.ShortURLDomain(*shortURLEntity), nil
+	uc.enqueuePreview(*savedShortURLEntity)
+
+	return domain.ShortURLDomain(*savedShortURLEntity), nil
 }
 
 // CreateShortURLBatch создает короткие ссылки пачкой
+//
+// Возвращает ErrConflict, если выбранный пользователем идентификатор короткой ссылки занят или повторяется в пачке,
+// в том числе занят другим запросом между проверкой и сохранением пачки, или если исходный URL уже сокращен
+// на домене короткой ссылки или повторяется в пачке. При ErrConflict не создается ни одна короткая ссылка пачки.
 func (uc *CreateShortURLUseCase) CreateShortURLBatch(ctx context.Context, createShortURLBatchDomains []domain.CreateShortURLBatchDomain) ([]domain.CreateShortURLBatchResultDomain, error) {
 	userID := ctx.Value(common.UserIDContextKey).(string)
 	log.Infow("use_case: create short URL batch", "userID", userID)
 
 	shortURLEntities := make([]entity.ShortURLEntity, 0, len(createShortURLBatchDomains))
+	aliasShortURIs := make(map[string]bool)
 	for _, createShortURLBatchDomain := range createShortURLBatchDomains {
+		metadata, err := normalizeMetadata(createShortURLBatchDomain.Metadata)
+		if err != nil {
+			log.Infow("use_case: invalid short url metadata", "correlationUUID", createShortURLBatchDomain.CorrelationUUID, "userID", userID, "error", err)
+			return nil, ErrInvalidMetadata
+		}
+
+		if createShortURLBatchDomain.Alias != "" {
+			if err := CheckAlias(createShortURLBatchDomain.Alias); err != nil {
+				log.Infow("use_case: invalid short url alias", "correlationUUID", createShortURLBatchDomain.CorrelationUUID, "userID", userID, "error", err)
+				return nil, ErrInvalidMetadata
+			}
+		}
+
+		shortURI, err := uc.newShortURI(metadata.ShortDomain, createShortURLBatchDomain.Alias)
+		if err != nil {
+			log.Infow("use_case: invalid short url domain", "correlationUUID", createShortURLBatchDomain.CorrelationUUID, "userID", userID, "error", err)
+			return nil, ErrInvalidMetadata
+		}
+
+		if createShortURLBatchDomain.Alias != "" {
+			if err := uc.checkAliasShortURI(ctx, shortURI, aliasShortURIs); err != nil {
+				log.Infow("use_case: short url alias is not available", "shortURI", shortURI, "userID", userID, "error", err)
+				return nil, err
+			}
+		}
+
 		shortURLEntity := entity.ShortURLEntity{
-			UUID:     createShortURLBatchDomain.CorrelationUUID,
-			ShortURI: util.RandStringRunes(10),
-			LongURL:  createShortURLBatchDomain.LongURL,
-			UserID:   userID,
-			Deleted:  false,
+			UUID:        createShortURLBatchDomain.CorrelationUUID,
+			ShortURI:    shortURI,
+			LongURL:     createShortURLBatchDomain.LongURL,
+			UserID:      userID,
+			Deleted:     false,
+			Title:       metadata.Title,
+			Description: metadata.Description,
+			Notes:       metadata.Notes,
+			Tags:        metadata.Tags,
+
+			RedirectStatus:   metadata.RedirectStatus,
+			QueryPassthrough: metadata.QueryPassthrough,
+			PathPassthrough:  metadata.PathPassthrough,
+			UTMParams:        metadata.UTMParams,
+			TargetingRules:   metadata.TargetingRules,
+			SplitVariants:    metadata.SplitVariants,
+			MaxClicks:        metadata.MaxClicks,
+			NotBefore:        metadata.NotBefore,
+			NotAfter:         metadata.NotAfter,
+			Disabled:         metadata.Disabled,
+			FallbackURL:      metadata.FallbackURL,
+		}
+
+		shortURLEntity.PasswordHash, err = hashPassword(metadata.Password)
+		if err != nil {
+			log.Errorw("use_case: failed to hash short url password", "error", err)
+			return nil, ErrUnexpected
 		}
 
 		shortURLEntities = append(shortURLEntities, shortURLEntity)
 	}
 
 	shortURLEntities, err := uc.repo.SaveShortURLs(ctx, shortURLEntities)
-	if err != nil {
+	if err != nil && errors.Is(err, repository.ErrConflict) {
+		log.Infow("use_case: short URL batch conflicts with existed entities", "userID", userID)
+		return nil, ErrConflict
+	} else if err != nil {
 		log.Errorw("use_case: failed to save short URL batch", "error", err)
 		return nil, ErrUnexpected
 	}
 
 	result := make([]domain.CreateShortURLBatchResultDomain, 0, len(createShortURLBatchDomains))
 	for _, shortURLEntity := range shortURLEntities {
+		uc.enqueuePreview(shortURLEntity)
+
 		createShortURLBatchResultDomain := domain.CreateShortURLBatchResultDomain{
 			CorrelationUUID: shortURLEntity.UUID,
 			ShortURI:        shortURLEntity.ShortURI,
@@ -112,6 +405,174 @@ func (uc *CreateShortURLUseCase) CreateShortURLBatch(ctx context.Context, create
 	return result, nil
 }
 
+// checkAliasShortURI проверяет, что ключ короткой ссылки shortURI с выбранным пользователем идентификатором
+// не занят и не повторяется в пачке, ключи которой собираются в batchShortURIs
+func (uc *CreateShortURLUseCase) checkAliasShortURI(ctx context.Context, shortURI string, batchShortURIs map[string]bool) error {
+	if batchShortURIs[shortURI] {
+		return ErrConflict
+	}
+	batchShortURIs[shortURI] = true
+
+	free, err := uc.isShortURIFree(ctx, shortURI)
+	if err != nil {
+		return err
+	}
+	if !free {
+		return ErrConflict
+	}
+
+	return nil
+}
+
+// isShortURIFree проверяет, что ключ shortURI не занят короткой ссылкой, в том числе удаленной
+func (uc *CreateShortURLUseCase) isShortURIFree(ctx context.Context, shortURI string) (bool, error) {
+	_, err := uc.repo.GetShortURLByShortURI(ctx, shortURI)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return true, nil
+	} else if err != nil {
+		log.Errorw("use_case: failed to get short url", "error", err)
+		return false, ErrUnexpected
+	}
+
+	return false, nil
+}
+
+func (uc *CreateShortURLUseCase) enqueuePreview(shortURLEntity entity.ShortURLEntity) {
+	if uc.previewer != nil {
+		uc.previewer.EnqueueShortURL(shortURLEntity.ShortURI, shortURLEntity.LongURL)
+	}
+}
+
+// normalizeMetadata проверяет ограничения на метаданные короткой ссылки и параметры перенаправления, обрезает пробелы в названии
+// и приводит теги к нижнему регистру, удаляя пустые теги и повторы
+func normalizeMetadata(metadata domain.ShortURLMetadataDomain) (domain.ShortURLMetadataDomain, error) {
+	metadata.Title = strings.TrimSpace(metadata.Title)
+	if utf8.RuneCountInString(metadata.Title) > ShortURLTitleMaxLength {
+		return domain.ShortURLMetadataDomain{}, fmt.Errorf("title is longer than %d", ShortURLTitleMaxLength)
+	}
+	if utf8.RuneCountInString(metadata.Description) > ShortURLDescriptionMaxLength {
+		return domain.ShortURLMetadataDomain{}, fmt.Errorf("description is longer than %d", ShortURLDescriptionMaxLength)
+	}
+	if utf8.RuneCountInString(metadata.Notes) > ShortURLNotesMaxLength {
+		return domain.ShortURLMetadataDomain{}, fmt.Errorf("notes are longer than %d", ShortURLNotesMaxLength)
+	}
+
+	var tags []string
+	for _, tag := range metadata.Tags {
+		tag = normalizeTag(tag)
+		if tag == "" || slices.Contains(tags, tag) {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > ShortURLTagMaxLength {
+			return domain.ShortURLMetadataDomain{}, fmt.Errorf("tag %q is longer than %d", tag, ShortURLTagMaxLength)
+		}
+
+		tags = append(tags, tag)
+	}
+	if len(tags) > ShortURLTagsMaxCount {
+		return domain.ShortURLMetadataDomain{}, fmt.Errorf("more than %d tags", ShortURLTagsMaxCount)
+	}
+	metadata.Tags = tags
+
+	if metadata.RedirectStatus != 0 && !IsRedirectStatus(metadata.RedirectStatus) {
+		return domain.ShortURLMetadataDomain{}, fmt.Errorf("redirect status %d is not supported", metadata.RedirectStatus)
+	}
+
+	redirectOptions, err := redirect.NormalizeOptions(redirect.Options{
+		QueryPassthrough: metadata.QueryPassthrough,
+		PathPassthrough:  metadata.PathPassthrough,
+		UTMParams:        metadata.UTMParams,
+	})
+	if err != nil {
+		return domain.ShortURLMetadataDomain{}, err
+	}
+	metadata.QueryPassthrough = redirectOptions.QueryPassthrough
+	metadata.UTMParams = redirectOptions.UTMParams
+
+	metadata.TargetingRules, err = targeting.NormalizeRules(metadata.TargetingRules)
+	if err != nil {
+		return domain.ShortURLMetadataDomain{}, err
+	}
+
+	metadata.SplitVariants, err = split.NormalizeVariants(metadata.SplitVariants)
+	if err != nil {
+		return domain.ShortURLMetadataDomain{}, err
+	}
+
+	if metadata.Password != "" && utf8.RuneCountInString(metadata.Password) < ShortURLPasswordMinLength {
+		return domain.ShortURLMetadataDomain{}, fmt.Errorf("password is shorter than %d", ShortURLPasswordMinLength)
+	}
+	if len(metadata.Password) > ShortURLPasswordMaxLength {
+		return domain.ShortURLMetadataDomain{}, fmt.Errorf("password is longer than %d bytes", ShortURLPasswordMaxLength)
+	}
+
+	if metadata.MaxClicks < 0 {
+		return domain.ShortURLMetadataDomain{}, errors.New("max clicks is negative")
+	}
+
+	// нулевое время снимает ограничение периода действия
+	if metadata.NotBefore != nil && metadata.NotBefore.IsZero() {
+		metadata.NotBefore = nil
+	}
+	if metadata.NotAfter != nil && metadata.NotAfter.IsZero() {
+		metadata.NotAfter = nil
+	}
+	if metadata.NotBefore != nil && metadata.NotAfter != nil && !metadata.NotAfter.After(*metadata.NotBefore) {
+		return domain.ShortURLMetadataDomain{}, errors.New("not after is not later than not before")
+	}
+
+	metadata.FallbackURL = strings.TrimSpace(metadata.FallbackURL)
+	if metadata.FallbackURL != "" {
+		if err := fallback.CheckURL(metadata.FallbackURL); err != nil {
+			return domain.ShortURLMetadataDomain{}, err
+		}
+	}
+
+	return metadata, nil
+}
+
+// hashPassword возвращает bcrypt-хэш пароля короткой ссылки, для пустого пароля - пустую строку
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(passwordHash), nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// CheckActivityWindow проверяет, что момент now входит в период действия короткой ссылки shortURLDomain
+//
+// Возвращает ErrNotYetActive до начала периода действия и ErrExpired после его окончания.
+func CheckActivityWindow(shortURLDomain domain.ShortURLDomain, now time.Time) error {
+	if shortURLDomain.NotBefore != nil && now.Before(*shortURLDomain.NotBefore) {
+		return ErrNotYetActive
+	}
+	if shortURLDomain.NotAfter != nil && !now.Before(*shortURLDomain.NotAfter) {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// IsRedirectStatus проверяет, что status - поддерживаемый код ответа перенаправления: 301, 302, 307 или 308
+func IsRedirectStatus(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
 // GetShortURLUseCase реализует интерфейс IGetShortURLUseCase
 type GetShortURLUseCase struct {
 	repo shortURLRepository
@@ -138,6 +599,43 @@ func (uc *GetShortURLUseCase) GetShortURLByShortURI(ctx context.Context, shortUR
 	return domain.ShortURLDomain(shortURLEntity), nil
 }
 
+// GetRedirectShortURLByShortURI возвращает короткую ссылку по shortURI для перенаправления
+//
+// Короткая ссылка может содержать только поля, необходимые для перенаправления, хэш пароля заполнен
+// у каждой короткой ссылки с паролем.
+func (uc *GetShortURLUseCase) GetRedirectShortURLByShortURI(ctx context.Context, shortURI string) (domain.ShortURLDomain, error) {
+	log.Infow("use_case: get short URL for redirect", "shortURI", shortURI)
+
+	shortURLEntity, err := uc.repo.GetRedirectShortURLByShortURI(ctx, shortURI)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		log.Infow("use_case: short url not found", "shortURI", shortURI)
+		return domain.ShortURLDomain{}, ErrNotFound
+	} else if err != nil {
+		log.Errorw("use_case: failed to get short url", "error", err)
+		return domain.ShortURLDomain{}, ErrUnexpected
+	}
+
+	return domain.ShortURLDomain(shortURLEntity), nil
+}
+
+// ConsumeShortURLClick учитывает переход по короткой ссылке shortURI с ограничением количества переходов
+//
+// Возвращает ErrClicksExhausted, если переходы по короткой ссылке исчерпаны.
+func (uc *GetShortURLUseCase) ConsumeShortURLClick(ctx context.Context, shortURI string) error {
+	err := uc.repo.ConsumeShortURLClick(ctx, shortURI)
+	if err != nil && errors.Is(err, repository.ErrClicksExhausted) {
+		log.Infow("use_case: short url clicks exhausted", "shortURI", shortURI)
+		return ErrClicksExhausted
+	} else if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	} else if err != nil {
+		log.Errorw("use_case: failed to consume short url click", "shortURI", shortURI, "error", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
 // GetShortURLsByUserID возвращает список коротких ссылок по userID
 func (uc *GetShortURLUseCase) GetShortURLsByUserID(ctx context.Context, userID string) ([]domain.ShortURLDomain, error) {
 	log.Infow("use_case: get short URLs by userID", "userID", userID)
@@ -158,28 +656,1060 @@ func (uc *GetShortURLUseCase) GetShortURLsByUserID(ctx context.Context, userID s
 	return result, nil
 }
 
-// DeleteShortURLUseCase реализует IDeleteShortURLUseCase
-type DeleteShortURLUseCase struct {
+// ShortURLsPageSizeDefault - размер страницы коротких ссылок по умолчанию
+// ShortURLsPageSizeMax - максимальный размер страницы коротких ссылок
+const (
+	ShortURLsPageSizeDefault = 100
+	ShortURLsPageSizeMax     = 1000
+)
+
+// shortURLCursor - позиция в списке коротких ссылок, передаваемая клиенту в закодированном виде
+type shortURLCursor struct {
+	Sort      string `json:"s"`
+	SortValue string `json:"v"`
+	UUID      string `json:"u"`
+}
+
+// GetShortURLsByQuery возвращает страницу коротких ссылок пользователя, удовлетворяющих запросу query
+//
+// Возвращает ErrInvalidQuery при некорректных статусе, сортировке или курсоре.
+func (uc *GetShortURLUseCase) GetShortURLsByQuery(ctx context.Context, query domain.ShortURLQueryDomain) (domain.ShortURLPageDomain, error) {
+	log.Infow("use_case: get short URLs by query", "userID", query.UserID, "sort", query.Sort, "limit", query.Limit)
+
+	queryEntity, err := toShortURLQueryEntity(query)
+	if err != nil {
+		log.Infow("use_case: invalid short URLs query", "userID", query.UserID, "error", err)
+		return domain.ShortURLPageDomain{}, ErrInvalidQuery
+	}
+
+	// запрашиваем на одну ссылку больше, чтобы определить наличие следующей страницы
+	pageSize := queryEntity.Limit
+	queryEntity.Limit++
+
+	shortURLEntities, totalCount, err := uc.repo.GetShortURLsByQuery(ctx, queryEntity)
+	if err != nil {
+		log.Errorw("use_case: failed to get short urls by query", "userID", query.UserID, "error", err)
+		return domain.ShortURLPageDomain{}, ErrUnexpected
+	}
+
+	result := domain.ShortURLPageDomain{
+		ShortURLs:  make([]domain.ShortURLDomain, 0, min(pageSize, len(shortURLEntities))),
+		TotalCount: totalCount,
+	}
+	if len(shortURLEntities) > pageSize {
+		shortURLEntities = shortURLEntities[:pageSize]
+		result.NextCursor = encodeShortURLCursor(shortURLEntities[pageSize-1], query.Sort, queryEntity.SortBy)
+	}
+
+	for _, shortURLEntity := range shortURLEntities {
+		result.ShortURLs = append(result.ShortURLs, domain.ShortURLDomain(shortURLEntity))
+	}
+
+	return result, nil
+}
+
+func toShortURLQueryEntity(query domain.ShortURLQueryDomain) (entity.ShortURLQueryEntity, error) {
+	queryEntity := entity.ShortURLQueryEntity{
+		UserID:      query.UserID,
+		Status:      query.Status,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+		Domain:      query.Domain,
+		Search:      query.Search,
+		Tag:         normalizeTag(query.Tag),
+		SortBy:      strings.TrimPrefix(query.Sort, "-"),
+		SortDesc:    strings.HasPrefix(query.Sort, "-"),
+		Limit:       query.Limit,
+	}
+
+	switch queryEntity.Status {
+	case "":
+		queryEntity.Status = entity.ShortURLStatusAll
+	case entity.ShortURLStatusAll, entity.ShortURLStatusActive, entity.ShortURLStatusDeleted:
+	default:
+		return entity.ShortURLQueryEntity{}, fmt.Errorf("unknown status %q", query.Status)
+	}
+
+	switch queryEntity.SortBy {
+	case "":
+		queryEntity.SortBy = entity.ShortURLSortByCreatedAt
+	case entity.ShortURLSortByCreatedAt, entity.ShortURLSortByOriginalURL, entity.ShortURLSortByShortURL:
+	default:
+		return entity.ShortURLQueryEntity{}, fmt.Errorf("unknown sort %q", query.Sort)
+	}
+
+	if queryEntity.Limit <= 0 {
+		queryEntity.Limit = ShortURLsPageSizeDefault
+	}
+	queryEntity.Limit = min(queryEntity.Limit, ShortURLsPageSizeMax)
+
+	if query.Cursor != "" {
+		after, err := decodeShortURLCursor(query.Cursor, query.Sort, queryEntity.SortBy)
+		if err != nil {
+			return entity.ShortURLQueryEntity{}, err
+		}
+		queryEntity.After = &after
+	}
+
+	return queryEntity, nil
+}
+
+func encodeShortURLCursor(shortURLEntity entity.ShortURLEntity, sort string, sortBy string) string {
+	cursor := shortURLCursor{Sort: sort, UUID: shortURLEntity.UUID}
+	switch sortBy {
+	case entity.ShortURLSortByCreatedAt:
+		cursor.SortValue = shortURLEntity.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case entity.ShortURLSortByOriginalURL:
+		cursor.SortValue = shortURLEntity.LongURL
+	case entity.ShortURLSortByShortURL:
+		cursor.SortValue = shortURLEntity.ShortURI
+	}
+
+	cursorJSON, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(cursorJSON)
+}
+
+func decodeShortURLCursor(encodedCursor string, sort string, sortBy string) (entity.ShortURLCursorEntity, error) {
+	cursorJSON, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return entity.ShortURLCursorEntity{}, fmt.Errorf("malformed cursor: %w", err)
+	}
+
+	var cursor shortURLCursor
+	if err := json.Unmarshal(cursorJSON, &cursor); err != nil {
+		return entity.ShortURLCursorEntity{}, fmt.Errorf("malformed cursor: %w", err)
+	}
+
+	// курсор действителен только для той сортировки, с которой была получена страница
+	if cursor.Sort != sort {
+		return entity.ShortURLCursorEntity{}, fmt.Errorf("cursor sort %q does not match %q", cursor.Sort, sort)
+	}
+
+	if sortBy == entity.ShortURLSortByCreatedAt {
+		if _, err := time.Parse(time.RFC3339Nano, cursor.SortValue); err != nil {
+			return entity.ShortURLCursorEntity{}, fmt.Errorf("malformed cursor: %w", err)
+		}
+	}
+
+	return entity.ShortURLCursorEntity{SortValue: cursor.SortValue, UUID: cursor.UUID}, nil
+}
+
+// GetDeletedShortURLsByUserID возвращает список удаленных коротких ссылок пользователя userID (корзину)
+func (uc *GetShortURLUseCase) GetDeletedShortURLsByUserID(ctx context.Context, userID string) ([]domain.ShortURLDomain, error) {
+	log.Infow("use_case: get deleted short URLs by userID", "userID", userID)
+
+	shortURLEntities, err := uc.repo.GetDeletedShortURLsByUserID(ctx, userID)
+	if err != nil {
+		log.Errorw("use_case: failed to get deleted short urls by userID", "userID", userID, "error", err)
+		return nil, ErrUnexpected
+	}
+
+	result := make([]domain.ShortURLDomain, 0, len(shortURLEntities))
+	for _, shortURLEntity := range shortURLEntities {
+		result = append(result, domain.ShortURLDomain(shortURLEntity))
+	}
+
+	return result, nil
+}
+
+// UpdateShortURLUseCase реализует сценарий изменения короткой ссылки ее владельцем
+type UpdateShortURLUseCase struct {
 	repo shortURLRepository
 }
 
-// NewDeleteShortURLUseCase создает экземпляр DeleteShortURLUseCase
-func NewDeleteShortURLUseCase(repo shortURLRepository) *DeleteShortURLUseCase {
-	return &DeleteShortURLUseCase{repo: repo}
+// NewUpdateShortURLUseCase создает экземпляр UpdateShortURLUseCase
+func NewUpdateShortURLUseCase(repo shortURLRepository) *UpdateShortURLUseCase {
+	return &UpdateShortURLUseCase{repo: repo}
 }
 
-// DeleteShortURLsByShortURIs удаляет короткие ссылки по списку shortURIs
-func (uc *DeleteShortURLUseCase) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) error {
+// UpdateShortURL изменяет короткую ссылку shortURI текущего пользователя согласно patch
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена или принадлежит другому пользователю.
+func (uc *UpdateShortURLUseCase) UpdateShortURL(ctx context.Context, shortURI string, patch domain.ShortURLPatchDomain) (domain.ShortURLDomain, error) {
 	userID := ctx.Value(common.UserIDContextKey).(string)
-	log.Infow("use_case: delete short URLs by shortURIs", "shortURIs", shortURIs, "userID", userID)
+	log.Infow("use_case: update short URL", "shortURI", shortURI, "userID", userID)
 
-	err := uc.repo.DeleteShortURLsByShortURIs(ctx, shortURIs)
-	if err != nil {
-		log.Errorw("use_case: failed to delete short URLs by shortURIs", "shortURIs", shortURIs, "userID", userID, "error", err)
-		return ErrUnexpected
+	shortURLEntity, err := uc.repo.GetShortURLByShortURI(ctx, shortURI)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return domain.ShortURLDomain{}, ErrNotFound
+	} else if err != nil {
+		log.Errorw("use_case: failed to get short url", "shortURI", shortURI, "error", err)
+		return domain.ShortURLDomain{}, ErrUnexpected
 	}
 
-	return nil
+	if shortURLEntity.UserID != userID {
+		log.Infow("use_case: short url belongs to another user", "shortURI", shortURI, "userID", userID)
+		return domain.ShortURLDomain{}, ErrNotFound
+	}
+
+	metadata := domain.ShortURLMetadataDomain{
+		Title:       shortURLEntity.Title,
+		Description: shortURLEntity.Description,
+		Notes:       shortURLEntity.Notes,
+		Tags:        shortURLEntity.Tags,
+
+		RedirectStatus:   shortURLEntity.RedirectStatus,
+		QueryPassthrough: shortURLEntity.QueryPassthrough,
+		PathPassthrough:  shortURLEntity.PathPassthrough,
+		UTMParams:        shortURLEntity.UTMParams,
+		TargetingRules:   shortURLEntity.TargetingRules,
+		SplitVariants:    shortURLEntity.SplitVariants,
+		MaxClicks:        shortURLEntity.MaxClicks,
+		NotBefore:        shortURLEntity.NotBefore,
+		NotAfter:         shortURLEntity.NotAfter,
+		Disabled:         shortURLEntity.Disabled,
+		FallbackURL:      shortURLEntity.FallbackURL,
+	}
+	if patch.Title != nil {
+		metadata.Title = *patch.Title
+	}
+	if patch.Description != nil {
+		metadata.Description = *patch.Description
+	}
+	if patch.Notes != nil {
+		metadata.Notes = *patch.Notes
+	}
+	if patch.Tags != nil {
+		metadata.Tags = *patch.Tags
+	}
+	if patch.RedirectStatus != nil {
+		metadata.RedirectStatus = *patch.RedirectStatus
+	}
+	if patch.QueryPassthrough != nil {
+		metadata.QueryPassthrough = *patch.QueryPassthrough
+	}
+	if patch.PathPassthrough != nil {
+		metadata.PathPassthrough = *patch.PathPassthrough
+	}
+	if patch.UTMParams != nil {
+		metadata.UTMParams = *patch.UTMParams
+	}
+	if patch.TargetingRules != nil {
+		metadata.TargetingRules = *patch.TargetingRules
+	}
+	if patch.SplitVariants != nil {
+		metadata.SplitVariants = *patch.SplitVariants
+	}
+	if patch.Password != nil {
+		metadata.Password = *patch.Password
+	}
+	if patch.MaxClicks != nil {
+		metadata.MaxClicks = *patch.MaxClicks
+	}
+	if patch.NotBefore != nil {
+		metadata.NotBefore = patch.NotBefore
+	}
+	if patch.NotAfter != nil {
+		metadata.NotAfter = patch.NotAfter
+	}
+	if patch.Disabled != nil {
+		metadata.Disabled = *patch.Disabled
+	}
+	if patch.FallbackURL != nil {
+		metadata.FallbackURL = *patch.FallbackURL
+	}
+
+	metadata, err = normalizeMetadata(metadata)
+	if err != nil {
+		log.Infow("use_case: invalid short url metadata", "shortURI", shortURI, "userID", userID, "error", err)
+		return domain.ShortURLDomain{}, ErrInvalidMetadata
+	}
+
+	shortURLEntity.Title = metadata.Title
+	shortURLEntity.Description = metadata.Description
+	shortURLEntity.Notes = metadata.Notes
+	shortURLEntity.Tags = metadata.Tags
+	shortURLEntity.RedirectStatus = metadata.RedirectStatus
+	shortURLEntity.QueryPassthrough = metadata.QueryPassthrough
+	shortURLEntity.PathPassthrough = metadata.PathPassthrough
+	shortURLEntity.UTMParams = metadata.UTMParams
+	shortURLEntity.TargetingRules = metadata.TargetingRules
+	shortURLEntity.SplitVariants = metadata.SplitVariants
+	shortURLEntity.MaxClicks = metadata.MaxClicks
+	shortURLEntity.NotBefore = metadata.NotBefore
+	shortURLEntity.NotAfter = metadata.NotAfter
+	shortURLEntity.Disabled = metadata.Disabled
+	shortURLEntity.FallbackURL = metadata.FallbackURL
+	if patch.Password != nil {
+		shortURLEntity.PasswordHash, err = hashPassword(metadata.Password)
+		if err != nil {
+			log.Errorw("use_case: failed to hash short url password", "shortURI", shortURI, "error", err)
+			return domain.ShortURLDomain{}, ErrUnexpected
+		}
+	}
+
+	shortURLEntity, err = uc.repo.UpdateShortURL(ctx, shortURLEntity)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return domain.ShortURLDomain{}, ErrNotFound
+	} else if err != nil {
+		log.Errorw("use_case: failed to update short url", "shortURI", shortURI, "error", err)
+		return domain.ShortURLDomain{}, ErrUnexpected
+	}
+
+	return domain.ShortURLDomain(shortURLEntity), nil
+}
+
+// PasswordClientAttemptsMax - количество неудачных попыток ввода пароля короткой ссылки с одного адреса,
+// после которого попытки с этого адреса блокируются
+// PasswordShortURLAttemptsMax - количество неудачных попыток ввода пароля короткой ссылки со всех адресов,
+// после которого блокируются все попытки
+// PasswordAttemptsWindow - окно, в котором считаются неудачные попытки ввода пароля
+const (
+	PasswordClientAttemptsMax   = 5
+	PasswordShortURLAttemptsMax = 100
+	PasswordAttemptsWindow      = 15 * time.Minute
+)
+
+// UnlockShortURLUseCase реализует сценарий перехода по короткой ссылке, защищенной паролем
+type UnlockShortURLUseCase struct {
+	repo            shortURLRepository
+	clientLimiter   attemptLimiter
+	shortURLLimiter attemptLimiter
+}
+
+// NewUnlockShortURLUseCase создает экземпляр UnlockShortURLUseCase, ограничивающий неудачные попытки
+// ввода пароля в памяти процесса
+func NewUnlockShortURLUseCase(repo shortURLRepository) *UnlockShortURLUseCase {
+	return NewUnlockShortURLUseCaseWithLimiters(
+		repo,
+		throttle.NewLimiter(PasswordClientAttemptsMax, PasswordAttemptsWindow),
+		throttle.NewLimiter(PasswordShortURLAttemptsMax, PasswordAttemptsWindow),
+	)
+}
+
+// NewUnlockShortURLUseCaseWithLimiters создает экземпляр UnlockShortURLUseCase с ограничителями неудачных попыток
+// ввода пароля с одного адреса clientLimiter и со всех адресов shortURLLimiter
+func NewUnlockShortURLUseCaseWithLimiters(repo shortURLRepository, clientLimiter attemptLimiter, shortURLLimiter attemptLimiter) *UnlockShortURLUseCase {
+	return &UnlockShortURLUseCase{
+		repo:            repo,
+		clientLimiter:   clientLimiter,
+		shortURLLimiter: shortURLLimiter,
+	}
+}
+
+// UnlockShortURL возвращает короткую ссылку shortURI, если она не защищена паролем или password совпадает с ее паролем
+//
+// client - адрес клиента, неудачные попытки ограничиваются для адреса и для короткой ссылки в целом.
+// Возвращает ErrPasswordRequired, если пароль не передан, ErrInvalidPassword, если пароль неверный,
+// ErrTooManyAttempts, если попытки ввода пароля заблокированы.
+func (uc *UnlockShortURLUseCase) UnlockShortURL(ctx context.Context, shortURI string, password string, client string) (domain.ShortURLDomain, error) {
+	log.Infow("use_case: unlock short URL", "shortURI", shortURI, "client", client)
+
+	shortURLEntity, err := uc.repo.GetShortURLByShortURI(ctx, shortURI)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return domain.ShortURLDomain{}, ErrNotFound
+	} else if err != nil {
+		log.Errorw("use_case: failed to get short url", "shortURI", shortURI, "error", err)
+		return domain.ShortURLDomain{}, ErrUnexpected
+	}
+
+	// пароль не запрашивается для недоступной короткой ссылки, переход по ней все равно не выполняется
+	if shortURLEntity.PasswordHash == "" || shortURLEntity.Deleted || shortURLEntity.Disabled {
+		return domain.ShortURLDomain(shortURLEntity), nil
+	}
+	if password == "" {
+		return domain.ShortURLDomain{}, ErrPasswordRequired
+	}
+
+	clientKey := shortURI + "|" + client
+	// попытка регистрируется до проверки пароля, чтобы одновременные попытки не обходили ограничение,
+	// и отменяется, если пароль верный
+	if !uc.clientLimiter.Acquire(clientKey) {
+		log.Infow("use_case: password attempts from client are blocked", "shortURI", shortURI, "client", client)
+		return domain.ShortURLDomain{}, ErrTooManyAttempts
+	}
+	if !uc.shortURLLimiter.Acquire(shortURI) {
+		log.Infow("use_case: password attempts for short url are blocked", "shortURI", shortURI)
+		uc.clientLimiter.Release(clientKey)
+		return domain.ShortURLDomain{}, ErrTooManyAttempts
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(shortURLEntity.PasswordHash), []byte(password))
+	if err != nil && errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		log.Infow("use_case: invalid short url password", "shortURI", shortURI, "client", client)
+		return domain.ShortURLDomain{}, ErrInvalidPassword
+	} else if err != nil {
+		log.Errorw("use_case: failed to compare short url password", "shortURI", shortURI, "error", err)
+		uc.clientLimiter.Release(clientKey)
+		uc.shortURLLimiter.Release(shortURI)
+		return domain.ShortURLDomain{}, ErrUnexpected
+	}
+
+	uc.clientLimiter.Reset(clientKey)
+	uc.shortURLLimiter.Release(shortURI)
+
+	return domain.ShortURLDomain(shortURLEntity), nil
+}
+
+// deleteJobWorkerCount - количество обработчиков задач на удаление коротких ссылок
+// deleteJobQueueSize - размер очереди задач на удаление коротких ссылок
+const (
+	deleteJobWorkerCount = 10
+	deleteJobQueueSize   = 1024
+)
+
+// DeleteShortURLUseCase реализует IDeleteShortURLUseCase
+//
+// Удаление коротких ссылок выполняется асинхронно: на каждый запрос создается задача, которая сохраняется
+// в репозитории и выполняется обработчиками в фоне. Задачи, не выполненные до остановки сервиса,
+// остаются в статусе entity.DeleteJobStatusPending и ставятся в очередь при следующем запуске.
+type DeleteShortURLUseCase struct {
+	repo         shortURLRepository
+	jobRepo      deleteJobRepository
+	jobCh        chan entity.DeleteJobEntity
+	stopCh       chan struct{}
+	stopOnce     sync.Once
+	workersGroup sync.WaitGroup
+}
+
+// NewDeleteShortURLUseCase создает экземпляр DeleteShortURLUseCase
+func NewDeleteShortURLUseCase(repo shortURLRepository, jobRepo deleteJobRepository) *DeleteShortURLUseCase {
+	return &DeleteShortURLUseCase{
+		repo:    repo,
+		jobRepo: jobRepo,
+		jobCh:   make(chan entity.DeleteJobEntity, deleteJobQueueSize),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start запускает обработчики задач на удаление коротких ссылок
+// и ставит в очередь задачи, не выполненные до предыдущей остановки сервиса
+func (uc *DeleteShortURLUseCase) Start(ctx context.Context) error {
+	for i := 0; i < deleteJobWorkerCount; i++ {
+		uc.workersGroup.Add(1)
+		go uc.runDeleteJobWorker()
+	}
+
+	deleteJobEntities, err := uc.jobRepo.GetDeleteJobsByStatus(ctx, entity.DeleteJobStatusPending)
+	if err != nil {
+		log.Errorw("use_case: failed to get pending delete jobs", "error", err)
+		return ErrUnexpected
+	}
+
+	log.Infow("use_case: resume pending delete jobs", "count", len(deleteJobEntities))
+	for _, deleteJobEntity := range deleteJobEntities {
+		uc.enqueueDeleteJob(deleteJobEntity)
+	}
+
+	return nil
+}
+
+// Shutdown прекращает прием задач на удаление коротких ссылок и ожидает выполнения задач из очереди.
+// Если ctx завершается раньше, невыполненные задачи остаются в репозитории в статусе entity.DeleteJobStatusPending
+func (uc *DeleteShortURLUseCase) Shutdown(ctx context.Context) error {
+	uc.stopOnce.Do(func() {
+		close(uc.stopCh)
+	})
+
+	workersDoneCh := make(chan struct{})
+	go func() {
+		uc.workersGroup.Wait()
+		close(workersDoneCh)
+	}()
+
+	select {
+	case <-workersDoneCh:
+		log.Infow("use_case: all delete jobs are done")
+		return nil
+	case <-ctx.Done():
+		log.Warnw("use_case: delete jobs are not done before shutdown, they will be resumed on next start")
+		return ctx.Err()
+	}
+}
+
+// DeleteShortURLsByShortURIs создает задачу на удаление коротких ссылок по списку shortURIs
+func (uc *DeleteShortURLUseCase) DeleteShortURLsByShortURIs(ctx context.Context, shortURIs []string) (domain.DeleteJobDomain, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	log.Infow("use_case: delete short URLs by shortURIs", "shortURIs", shortURIs, "userID", userID)
+
+	now := time.Now()
+	deleteJobEntity := entity.DeleteJobEntity{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Status:    entity.DeleteJobStatusPending,
+		ShortURIs: shortURIs,
+		Results:   make([]entity.DeleteShortURLResultEntity, 0),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err := uc.jobRepo.SaveDeleteJob(ctx, &deleteJobEntity)
+	if err != nil {
+		log.Errorw("use_case: failed to save delete job", "shortURIs", shortURIs, "userID", userID, "error", err)
+		return domain.DeleteJobDomain{}, ErrUnexpected
+	}
+
+	uc.enqueueDeleteJob(deleteJobEntity)
+
+	return toDeleteJobDomain(deleteJobEntity), nil
+}
+
+// GetDeleteJob возвращает задачу на удаление коротких ссылок по ее идентификатору.
+// Задачи других пользователей не возвращаются
+func (uc *DeleteShortURLUseCase) GetDeleteJob(ctx context.Context, jobID string) (domain.DeleteJobDomain, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	log.Infow("use_case: get delete job", "jobID", jobID, "userID", userID)
+
+	deleteJobEntity, err := uc.jobRepo.GetDeleteJobByID(ctx, jobID)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		log.Infow("use_case: delete job not found", "jobID", jobID)
+		return domain.DeleteJobDomain{}, ErrNotFound
+	} else if err != nil {
+		log.Errorw("use_case: failed to get delete job", "jobID", jobID, "error", err)
+		return domain.DeleteJobDomain{}, ErrUnexpected
+	}
+
+	if deleteJobEntity.UserID != userID {
+		log.Infow("use_case: delete job belongs to another user", "jobID", jobID, "userID", userID)
+		return domain.DeleteJobDomain{}, ErrNotFound
+	}
+
+	return toDeleteJobDomain(deleteJobEntity), nil
+}
+
+// RestoreShortURLsByShortURIs восстанавливает удаленные короткие ссылки по списку shortURIs
+func (uc *DeleteShortURLUseCase) RestoreShortURLsByShortURIs(ctx context.Context, shortURIs []string) ([]domain.RestoreShortURLResultDomain, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	log.Infow("use_case: restore short URLs by shortURIs", "shortURIs", shortURIs, "userID", userID)
+
+	restoreShortURLResultEntities, err := uc.repo.RestoreShortURLsByShortURIs(ctx, shortURIs)
+	if err != nil {
+		log.Errorw("use_case: failed to restore short URLs by shortURIs", "shortURIs", shortURIs, "userID", userID, "error", err)
+		return nil, ErrUnexpected
+	}
+
+	result := make([]domain.RestoreShortURLResultDomain, 0, len(restoreShortURLResultEntities))
+	for _, restoreShortURLResultEntity := range restoreShortURLResultEntities {
+		result = append(result, domain.RestoreShortURLResultDomain(restoreShortURLResultEntity))
+	}
+
+	return result, nil
+}
+
+// enqueueDeleteJob ставит задачу в очередь без ожидания. Если очередь заполнена, задача ставится в очередь в фоне
+// до вызова Shutdown: она уже сохранена и принята клиентом, поэтому не должна зависеть от контекста запроса.
+// Очередь не закрывается, поэтому задача, поставленная после остановки обработчиков, не будет выполнена
+// и останется в репозитории в статусе entity.DeleteJobStatusPending до следующего запуска
+func (uc *DeleteShortURLUseCase) enqueueDeleteJob(deleteJobEntity entity.DeleteJobEntity) {
+	select {
+	case <-uc.stopCh:
+		log.Infow("use_case: delete job queue is closed, job will be resumed on next start", "jobID", deleteJobEntity.ID)
+		return
+	default:
+	}
+
+	select {
+	case uc.jobCh <- deleteJobEntity:
+		return
+	default:
+	}
+
+	log.Warnw("use_case: delete job queue is full, job will be enqueued in background", "jobID", deleteJobEntity.ID)
+	go func() {
+		select {
+		case uc.jobCh <- deleteJobEntity:
+		case <-uc.stopCh:
+			log.Infow("use_case: delete job queue is closed, job will be resumed on next start", "jobID", deleteJobEntity.ID)
+		}
+	}()
+}
+
+// runDeleteJobWorker выполняет задачи из очереди, после вызова Shutdown - оставшиеся в очереди задачи
+func (uc *DeleteShortURLUseCase) runDeleteJobWorker() {
+	defer uc.workersGroup.Done()
+
+	for {
+		select {
+		case deleteJobEntity := <-uc.jobCh:
+			uc.processDeleteJob(deleteJobEntity)
+		case <-uc.stopCh:
+			for {
+				select {
+				case deleteJobEntity := <-uc.jobCh:
+					uc.processDeleteJob(deleteJobEntity)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (uc *DeleteShortURLUseCase) processDeleteJob(deleteJobEntity entity.DeleteJobEntity) {
+	ctx := context.WithValue(context.Background(), common.UserIDContextKey, deleteJobEntity.UserID)
+
+	deleteShortURLResultEntities, err := uc.repo.DeleteShortURLsByShortURIs(ctx, deleteJobEntity.ShortURIs)
+	if err != nil {
+		log.Errorw("use_case: failed to delete short URLs by shortURIs", "jobID", deleteJobEntity.ID, "error", err)
+		deleteJobEntity.Status = entity.DeleteJobStatusFailed
+	} else {
+		deleteJobEntity.Status = entity.DeleteJobStatusDone
+		deleteJobEntity.Results = deleteShortURLResultEntities
+	}
+	deleteJobEntity.UpdatedAt = time.Now()
+
+	if err := uc.jobRepo.SaveDeleteJob(ctx, &deleteJobEntity); err != nil {
+		log.Errorw("use_case: failed to save delete job", "jobID", deleteJobEntity.ID, "error", err)
+		return
+	}
+
+	log.Infow("use_case: delete job processed", "jobID", deleteJobEntity.ID, "status", deleteJobEntity.Status)
+}
+
+func toDeleteJobDomain(deleteJobEntity entity.DeleteJobEntity) domain.DeleteJobDomain {
+	results := make([]domain.DeleteShortURLResultDomain, 0, len(deleteJobEntity.Results))
+	for _, deleteShortURLResultEntity := range deleteJobEntity.Results {
+		results = append(results, domain.DeleteShortURLResultDomain(deleteShortURLResultEntity))
+	}
+
+	return domain.DeleteJobDomain{
+		ID:        deleteJobEntity.ID,
+		UserID:    deleteJobEntity.UserID,
+		Status:    deleteJobEntity.Status,
+		ShortURIs: deleteJobEntity.ShortURIs,
+		Results:   results,
+		CreatedAt: deleteJobEntity.CreatedAt,
+		UpdatedAt: deleteJobEntity.UpdatedAt,
+	}
+}
+
+// previewWorkerCount - количество обработчиков задач на получение превью страниц
+// previewQueueSize - размер очереди задач на получение превью страниц
+// previewFetchAttempts - количество попыток получения превью страницы при временных ошибках
+// previewRetryDelay - задержка перед первой повторной попыткой, удваивается с каждой попыткой
+// previewImageURLMaxLength - максимальная длина сохраняемого адреса изображения превью
+const (
+	previewWorkerCount       = 4
+	previewQueueSize         = 1024
+	previewFetchAttempts     = 3
+	previewRetryDelay        = time.Second
+	previewImageURLMaxLength = 2048
+)
+
+type previewTask struct {
+	shortURI string
+	longURL  string
+}
+
+// PreviewShortURLUseCase получает в фоне превью страниц исходных URL коротких ссылок:
+// заголовок, описание и изображение, и сохраняет их в репозитории
+//
+// Получение превью не гарантируется: задачи, не поместившиеся в очередь или не выполненные
+// до остановки сервиса, отбрасываются.
+type PreviewShortURLUseCase struct {
+	repo         previewRepository
+	fetcher      previewFetcher
+	retryDelay   time.Duration
+	taskCh       chan previewTask
+	taskChMutex  sync.RWMutex
+	taskChClosed bool
+	workersGroup sync.WaitGroup
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+// NewPreviewShortURLUseCase создает экземпляр PreviewShortURLUseCase
+func NewPreviewShortURLUseCase(repo previewRepository, fetcher previewFetcher) *PreviewShortURLUseCase {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &PreviewShortURLUseCase{
+		repo:       repo,
+		fetcher:    fetcher,
+		retryDelay: previewRetryDelay,
+		taskCh:     make(chan previewTask, previewQueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start запускает обработчики задач на получение превью страниц
+func (uc *PreviewShortURLUseCase) Start() {
+	for i := 0; i < previewWorkerCount; i++ {
+		uc.workersGroup.Add(1)
+		go uc.runPreviewWorker()
+	}
+}
+
+// Shutdown прекращает прием задач на получение превью страниц, прерывает выполняемые задачи
+// и ожидает завершения обработчиков
+func (uc *PreviewShortURLUseCase) Shutdown(ctx context.Context) error {
+	uc.taskChMutex.Lock()
+	if !uc.taskChClosed {
+		uc.taskChClosed = true
+		close(uc.taskCh)
+	}
+	uc.taskChMutex.Unlock()
+	uc.cancel()
+
+	workersDoneCh := make(chan struct{})
+	go func() {
+		uc.workersGroup.Wait()
+		close(workersDoneCh)
+	}()
+
+	select {
+	case <-workersDoneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// EnqueueShortURL ставит в очередь задачу на получение превью страницы longURL короткой ссылки shortURI,
+// если очередь заполнена, задача отбрасывается
+func (uc *PreviewShortURLUseCase) EnqueueShortURL(shortURI string, longURL string) {
+	uc.taskChMutex.RLock()
+	defer uc.taskChMutex.RUnlock()
+
+	if uc.taskChClosed {
+		return
+	}
+
+	select {
+	case uc.taskCh <- previewTask{shortURI: shortURI, longURL: longURL}:
+	default:
+		log.Warnw("use_case: preview queue is full, task is dropped", "shortURI", shortURI)
+	}
+}
+
+func (uc *PreviewShortURLUseCase) runPreviewWorker() {
+	defer uc.workersGroup.Done()
+
+	for task := range uc.taskCh {
+		uc.processPreviewTask(uc.ctx, task)
+	}
+}
+
+func (uc *PreviewShortURLUseCase) processPreviewTask(ctx context.Context, task previewTask) {
+	var pagePreview preview.Preview
+	var err error
+
+	retryDelay := uc.retryDelay
+	for attempt := 1; attempt <= previewFetchAttempts; attempt++ {
+		pagePreview, err = uc.fetcher.Fetch(ctx, task.longURL)
+		if err == nil || !errors.Is(err, preview.ErrTemporary) || attempt == previewFetchAttempts {
+			break
+		}
+
+		log.Infow("use_case: failed to fetch preview, retrying", "shortURI", task.shortURI, "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+		retryDelay *= 2
+	}
+	if err != nil {
+		log.Infow("use_case: failed to fetch preview", "shortURI", task.shortURI, "error", err)
+		return
+	}
+
+	previewEntity := entity.ShortURLPreviewEntity{
+		Title:       truncateRunes(pagePreview.Title, ShortURLTitleMaxLength),
+		Description: truncateRunes(pagePreview.Description, ShortURLDescriptionMaxLength),
+		ImageURL:    pagePreview.ImageURL,
+		FetchedAt:   time.Now(),
+	}
+	if len(previewEntity.ImageURL) > previewImageURLMaxLength {
+		previewEntity.ImageURL = ""
+	}
+
+	if err := uc.repo.UpdateShortURLPreview(ctx, task.shortURI, previewEntity); err != nil {
+		log.Errorw("use_case: failed to save preview", "shortURI", task.shortURI, "error", err)
+		return
+	}
+
+	log.Infow("use_case: preview saved", "shortURI", task.shortURI)
+}
+
+// truncateRunes обрезает строку s до maxLength символов
+func truncateRunes(s string, maxLength int) string {
+	if utf8.RuneCountInString(s) <= maxLength {
+		return s
+	}
+
+	return string([]rune(s)[:maxLength])
+}
+
+// PurgeShortURLUseCase окончательно удаляет короткие ссылки, находящиеся в корзине дольше срока хранения
+type PurgeShortURLUseCase struct {
+	repo      purgeRepository
+	retention time.Duration
+}
+
+// NewPurgeShortURLUseCase создает экземпляр PurgeShortURLUseCase
+//
+//	retention - срок хранения удаленных коротких ссылок в корзине
+func NewPurgeShortURLUseCase(repo purgeRepository, retention time.Duration) *PurgeShortURLUseCase {
+	return &PurgeShortURLUseCase{repo: repo, retention: retention}
+}
+
+// PurgeDeletedShortURLs окончательно удаляет короткие ссылки, удаленные раньше чем retention назад,
+// и возвращает количество удаленных ссылок
+func (uc *PurgeShortURLUseCase) PurgeDeletedShortURLs(ctx context.Context) (int, error) {
+	deletedBefore := time.Now().Add(-uc.retention)
+	log.Infow("use_case: purge deleted short URLs", "deletedBefore", deletedBefore)
+
+	purgedCount, err := uc.repo.PurgeDeletedShortURLs(ctx, deletedBefore)
+	if err != nil {
+		log.Errorw("use_case: failed to purge deleted short URLs", "error", err)
+		return 0, ErrUnexpected
+	}
+
+	log.Infow("use_case: deleted short URLs purged", "count", purgedCount)
+
+	return purgedCount, nil
+}
+
+// Run периодически с интервалом interval удаляет короткие ссылки из корзины до завершения ctx
+func (uc *PurgeShortURLUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// ошибка уже залогирована, следующая попытка будет выполнена по таймеру
+			_, _ = uc.PurgeDeletedShortURLs(ctx)
+		}
+	}
+}
+
+// SplitUseCase реализует учет переходов по вариантам перенаправления A/B-теста
+type SplitUseCase struct {
+	repo      shortURLRepository
+	clickRepo splitClickRepository
+}
+
+// NewSplitUseCase создает экземпляр SplitUseCase
+func NewSplitUseCase(repo shortURLRepository, clickRepo splitClickRepository) *SplitUseCase {
+	return &SplitUseCase{repo: repo, clickRepo: clickRepo}
+}
+
+// RecordSplitClick учитывает переход по короткой ссылке shortURI на вариант variant
+func (uc *SplitUseCase) RecordSplitClick(ctx context.Context, shortURI string, variant string) error {
+	if err := uc.clickRepo.IncrementSplitClicks(ctx, shortURI, variant); err != nil {
+		log.Errorw("use_case: failed to record split click", "shortURI", shortURI, "variant", variant, "error", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// GetSplitStats возвращает количество переходов по вариантам перенаправления короткой ссылки shortURI
+// текущего пользователя: сначала текущие варианты в порядке их задания, затем удаленные варианты по имени
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена или принадлежит другому пользователю.
+func (uc *SplitUseCase) GetSplitStats(ctx context.Context, shortURI string) ([]domain.SplitVariantStatsDomain, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	log.Infow("use_case: get split stats", "shortURI", shortURI, "userID", userID)
+
+	shortURLEntity, err := uc.repo.GetShortURLByShortURI(ctx, shortURI)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		log.Errorw("use_case: failed to get short url", "shortURI", shortURI, "error", err)
+		return nil, ErrUnexpected
+	}
+
+	if shortURLEntity.UserID != userID {
+		log.Infow("use_case: short url belongs to another user", "shortURI", shortURI, "userID", userID)
+		return nil, ErrNotFound
+	}
+
+	clicks, err := uc.clickRepo.GetSplitClicks(ctx, shortURI)
+	if err != nil {
+		log.Errorw("use_case: failed to get split clicks", "shortURI", shortURI, "error", err)
+		return nil, ErrUnexpected
+	}
+
+	result := make([]domain.SplitVariantStatsDomain, 0, len(shortURLEntity.SplitVariants))
+	for _, variant := range shortURLEntity.SplitVariants {
+		result = append(result, domain.SplitVariantStatsDomain{
+			Name:   variant.Name,
+			URL:    variant.URL,
+			Weight: variant.Weight,
+			Clicks: clicks[variant.Name],
+		})
+		delete(clicks, variant.Name)
+	}
+
+	removedVariants := make([]string, 0, len(clicks))
+	for variant := range clicks {
+		removedVariants = append(removedVariants, variant)
+	}
+	slices.Sort(removedVariants)
+	for _, variant := range removedVariants {
+		result = append(result, domain.SplitVariantStatsDomain{Name: variant, Clicks: clicks[variant]})
+	}
+
+	return result, nil
+}
+
+// UnknownCountry - код страны для переходов, страна которых не определена
+const UnknownCountry = "ZZ"
+
+// CountryStatsUseCase реализует учет переходов по коротким ссылкам по странам клиентов
+type CountryStatsUseCase struct {
+	repo      shortURLRepository
+	clickRepo countryClickRepository
+}
+
+// NewCountryStatsUseCase создает экземпляр CountryStatsUseCase
+func NewCountryStatsUseCase(repo shortURLRepository, clickRepo countryClickRepository) *CountryStatsUseCase {
+	return &CountryStatsUseCase{repo: repo, clickRepo: clickRepo}
+}
+
+// RecordCountryClick учитывает переход по короткой ссылке shortURI из страны country,
+// переход из неопределенной страны учитывается под кодом UnknownCountry
+func (uc *CountryStatsUseCase) RecordCountryClick(ctx context.Context, shortURI string, country string) error {
+	if country == "" {
+		country = UnknownCountry
+	}
+
+	if err := uc.clickRepo.IncrementCountryClicks(ctx, shortURI, country); err != nil {
+		log.Errorw("use_case: failed to record country click", "shortURI", shortURI, "country", country, "error", err)
+		return ErrUnexpected
+	}
+
+	return nil
+}
+
+// GetCountryStats возвращает количество переходов по короткой ссылке shortURI текущего пользователя по странам
+// в порядке убывания количества переходов
+//
+// Возвращает ErrNotFound, если короткая ссылка не найдена или принадлежит другому пользователю.
+func (uc *CountryStatsUseCase) GetCountryStats(ctx context.Context, shortURI string) ([]domain.CountryStatsDomain, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	log.Infow("use_case: get country stats", "shortURI", shortURI, "userID", userID)
+
+	shortURLEntity, err := uc.repo.GetShortURLByShortURI(ctx, shortURI)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		log.Errorw("use_case: failed to get short url", "shortURI", shortURI, "error", err)
+		return nil, ErrUnexpected
+	}
+
+	if shortURLEntity.UserID != userID {
+		log.Infow("use_case: short url belongs to another user", "shortURI", shortURI, "userID", userID)
+		return nil, ErrNotFound
+	}
+
+	clicks, err := uc.clickRepo.GetCountryClicks(ctx, shortURI)
+	if err != nil {
+		log.Errorw("use_case: failed to get country clicks", "shortURI", shortURI, "error", err)
+		return nil, ErrUnexpected
+	}
+
+	result := make([]domain.CountryStatsDomain, 0, len(clicks))
+	for country, countryClicks := range clicks {
+		result = append(result, domain.CountryStatsDomain{Country: country, Clicks: countryClicks})
+	}
+	slices.SortFunc(result, func(a, b domain.CountryStatsDomain) int {
+		if c := cmp.Compare(b.Clicks, a.Clicks); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Country, b.Country)
+	})
+
+	return result, nil
+}
+
+// UserSettingsUseCase реализует сценарии получения и изменения настроек пользователя
+type UserSettingsUseCase struct {
+	repo userSettingsRepository
+}
+
+// NewUserSettingsUseCase создает экземпляр UserSettingsUseCase
+func NewUserSettingsUseCase(repo userSettingsRepository) *UserSettingsUseCase {
+	return &UserSettingsUseCase{repo: repo}
+}
+
+// GetUserSettings возвращает настройки текущего пользователя
+func (uc *UserSettingsUseCase) GetUserSettings(ctx context.Context) (domain.UserSettingsDomain, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	log.Infow("use_case: get user settings", "userID", userID)
+
+	userSettingsEntity, err := uc.repo.GetUserSettings(ctx, userID)
+	if err != nil {
+		log.Errorw("use_case: failed to get user settings", "userID", userID, "error", err)
+		return domain.UserSettingsDomain{}, ErrUnexpected
+	}
+
+	return domain.UserSettingsDomain(userSettingsEntity), nil
+}
+
+// UpdateUserSettings сохраняет настройки текущего пользователя
+//
+// Возвращает ErrInvalidSettings, если резервный адрес перенаправления не является абсолютным http(s) адресом.
+func (uc *UserSettingsUseCase) UpdateUserSettings(ctx context.Context, userSettings domain.UserSettingsDomain) (domain.UserSettingsDomain, error) {
+	userID := ctx.Value(common.UserIDContextKey).(string)
+	log.Infow("use_case: update user settings", "userID", userID)
+
+	userSettings.UserID = userID
+	userSettings.FallbackURL = strings.TrimSpace(userSettings.FallbackURL)
+	if userSettings.FallbackURL != "" {
+		if err := fallback.CheckURL(userSettings.FallbackURL); err != nil {
+			log.Infow("use_case: invalid user settings", "userID", userID, "error", err)
+			return domain.UserSettingsDomain{}, ErrInvalidSettings
+		}
+	}
+
+	if err := uc.repo.SaveUserSettings(ctx, entity.UserSettingsEntity(userSettings)); err != nil {
+		log.Errorw("use_case: failed to save user settings", "userID", userID, "error", err)
+		return domain.UserSettingsDomain{}, ErrUnexpected
+	}
+
+	return userSettings, nil
+}
+
+// FallbackUseCase реализует выбор резервного адреса перенаправления для недоступной короткой ссылки
+type FallbackUseCase struct {
+	repo       userSettingsRepository
+	defaultURL string
+}
+
+// NewFallbackUseCase создает экземпляр FallbackUseCase
+//
+//	defaultURL - резервный адрес перенаправления из конфигурации, "" - адрес не задан
+func NewFallbackUseCase(repo userSettingsRepository, defaultURL string) *FallbackUseCase {
+	return &FallbackUseCase{repo: repo, defaultURL: defaultURL}
+}
+
+// GetFallbackURL возвращает резервный адрес перенаправления для короткой ссылки shortURLDomain, недоступной
+// по причине reason, с причиной в параметре запроса fallback.ReasonParam
+//
+// Адрес выбирается в порядке: адрес короткой ссылки, адрес из настроек ее владельца, адрес из конфигурации.
+// Если резервный адрес не задан, возвращается пустая строка.
+func (uc *FallbackUseCase) GetFallbackURL(ctx context.Context, shortURLDomain domain.ShortURLDomain, reason string) (string, error) {
+	fallbackURL := shortURLDomain.FallbackURL
+	if fallbackURL == "" {
+		userSettingsEntity, err := uc.repo.GetUserSettings(ctx, shortURLDomain.UserID)
+		if err != nil {
+			log.Errorw("use_case: failed to get user settings", "userID", shortURLDomain.UserID, "error", err)
+			return "", ErrUnexpected
+		}
+
+		fallbackURL = userSettingsEntity.FallbackURL
+	}
+	if fallbackURL == "" {
+		fallbackURL = uc.defaultURL
+	}
+	if fallbackURL == "" {
+		return "", nil
+	}
+
+	result, err := fallback.BuildURL(fallbackURL, reason)
+	if err != nil {
+		log.Errorw("use_case: failed to build fallback url", "shortURI", shortURLDomain.ShortURI, "error", err)
+		return "", ErrUnexpected
+	}
+
+	return result, nil
 }
 
 // StatsUseCase структура реализующая интерфейс IStatsUseCase
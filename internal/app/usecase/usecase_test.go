@@ -4,16 +4,26 @@ import (
 	"context"
 	"errors"
 	"github.com/vkhrushchev/urlshortener/internal/common"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/vkhrushchev/urlshortener/internal/app/domain"
 	"github.com/vkhrushchev/urlshortener/internal/app/entity"
+	"github.com/vkhrushchev/urlshortener/internal/app/preview"
 	"github.com/vkhrushchev/urlshortener/internal/app/repository"
+	"github.com/vkhrushchev/urlshortener/internal/app/shortdomain"
+	"github.com/vkhrushchev/urlshortener/internal/app/split"
+	"github.com/vkhrushchev/urlshortener/internal/app/targeting"
 	mock_usecase "github.com/vkhrushchev/urlshortener/internal/app/usecase/mocks"
 	"github.com/vkhrushchev/urlshortener/internal/util"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type CreateShortURLUseCaseTestSuite struct {
@@ -26,7 +36,7 @@ func (suite *CreateShortURLUseCaseTestSuite) SetupTest() {
 	mockCtrl := gomock.NewController(suite.T())
 	suite.repositoryMock = mock_usecase.NewMockshortURLRepository(mockCtrl)
 
-	suite.useCase = NewCreateShortURLUseCase(suite.repositoryMock)
+	suite.useCase = NewCreateShortURLUseCase(suite.repositoryMock, nil, nil)
 }
 
 func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_success() {
@@ -44,7 +54,7 @@ func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_success() {
 		Return(testShortURLEntity, nil)
 
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
-	shortURLDomain, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru")
+	shortURLDomain, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
 	if err != nil {
 		log.Errorw("use_case: error when create shortURL", "error", err)
 	}
@@ -67,7 +77,7 @@ func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_conflict() {
 		Return(testShortURLEntity, repository.ErrConflict)
 
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
-	shortURLDomain, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru")
+	shortURLDomain, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
 
 	suite.NotNilf(err, "err cannot be nil")
 	suite.True(errors.Is(err, ErrConflict), "err should be ErrConflict")
@@ -81,12 +91,157 @@ func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_unexpected_error
 		Return(nil, repository.ErrUnexpected)
 
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
-	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru")
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
 
 	suite.NotNilf(err, "err cannot be nil")
 	suite.True(errors.Is(err, ErrUnexpected), "err should be ErrUnexpected")
 }
 
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_invalid_metadata() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	testMetadata := domain.ShortURLMetadataDomain{Tags: []string{util.RandStringRunes(ShortURLTagMaxLength + 1)}}
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", testMetadata)
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_invalid_redirect_status() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	testMetadata := domain.ShortURLMetadataDomain{RedirectStatus: http.StatusOK}
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", testMetadata)
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_invalid_redirect_options() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	testMetadata := domain.ShortURLMetadataDomain{UTMParams: map[string]string{"ref": "mail"}}
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", testMetadata)
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_invalid_targeting_rules() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	testMetadata := domain.ShortURLMetadataDomain{TargetingRules: []targeting.Rule{{Platform: "symbian", URL: "https://ya.ru"}}}
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", testMetadata)
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_invalid_split_variants() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	testMetadata := domain.ShortURLMetadataDomain{SplitVariants: []split.Variant{{Name: "a", URL: "https://ya.ru", Weight: 1}}}
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", testMetadata)
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_password() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	suite.repositoryMock.EXPECT().
+		SaveShortURL(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+			return shortURLEntity, nil
+		})
+
+	shortURLDomain, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{Password: "secret"})
+
+	suite.NoError(err, "unexpected error when create shortURL")
+	suite.NoError(bcrypt.CompareHashAndPassword([]byte(shortURLDomain.PasswordHash), []byte("secret")), "password must be stored as bcrypt hash")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_invalid_password() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	for _, testPassword := range []string{"abc", strings.Repeat("a", ShortURLPasswordMaxLength+1)} {
+		_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{Password: testPassword})
+
+		suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+	}
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_invalid_max_clicks() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{MaxClicks: -1})
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_invalid_activity_window() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	testNotBefore := time.Now().Add(time.Hour)
+	testNotAfter := testNotBefore.Add(-time.Minute)
+	_, err := suite.useCase.CreateShortURL(
+		testCtx,
+		"https://ya.ru",
+		domain.ShortURLMetadataDomain{NotBefore: &testNotBefore, NotAfter: &testNotAfter},
+	)
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_short_uri_conflict() {
+	takenShortURIs := make([]string, 0, 2)
+	gomock.InOrder(
+		suite.repositoryMock.EXPECT().
+			SaveShortURL(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+				takenShortURIs = append(takenShortURIs, shortURLEntity.ShortURI)
+				return nil, repository.ErrConflict
+			}).
+			Times(2),
+		suite.repositoryMock.EXPECT().
+			SaveShortURL(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+				return shortURLEntity, nil
+			}),
+	)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	shortURLDomain, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
+
+	suite.NoError(err, "taken random short uri must be regenerated")
+	suite.NotContains(takenShortURIs, shortURLDomain.ShortURI, "short url must be saved with new short uri")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_short_uri_conflict_attempts_exhausted() {
+	suite.repositoryMock.EXPECT().
+		SaveShortURL(gomock.Any(), gomock.Any()).
+		Return(nil, repository.ErrConflict).
+		Times(shortURIGenerateAttempts)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
+
+	suite.ErrorIs(err, ErrUnexpected, "short uri conflicts after all attempts should be ErrUnexpected")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_short_domain() {
+	useCase := NewCreateShortURLUseCase(
+		suite.repositoryMock,
+		nil,
+		shortdomain.NewDomains("http://localhost:8080", []string{"brand-b.link"}),
+	)
+	suite.repositoryMock.EXPECT().
+		SaveShortURL(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, shortURLEntity *entity.ShortURLEntity) (*entity.ShortURLEntity, error) {
+			return shortURLEntity, nil
+		})
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	shortURLDomain, err := useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{ShortDomain: "Brand-B.link"})
+
+	suite.NoError(err, "unexpected error when create shortURL")
+	suite.True(strings.HasSuffix(shortURLDomain.ShortURI, "@brand-b.link"), "shortURI must be scoped by short domain")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURL_unknown_short_domain() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{ShortDomain: "brand-c.io"})
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
 func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURLBatch_success() {
 	testUserID := uuid.NewString()
 
@@ -161,6 +316,88 @@ func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURLBatch_unexpected_
 	suite.True(errors.Is(err, ErrUnexpected), "err should be ErrUnexpected")
 }
 
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURLBatch_conflict() {
+	suite.repositoryMock.EXPECT().
+		SaveShortURLs(gomock.Any(), gomock.Any()).
+		Return(nil, repository.ErrConflict)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	createShortURLBatchResultDomains, err := suite.useCase.CreateShortURLBatch(testCtx, []domain.CreateShortURLBatchDomain{
+		{CorrelationUUID: uuid.NewString(), LongURL: "https://ya.ru"},
+	})
+
+	suite.ErrorIs(err, ErrConflict, "err should be ErrConflict")
+	suite.Nil(createShortURLBatchResultDomains, "createShortURLBatchResultDomains must be nil")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURLBatch_alias() {
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), "old-code").
+		Return(entity.ShortURLEntity{}, repository.ErrNotFound)
+	suite.repositoryMock.EXPECT().
+		SaveShortURLs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, shortURLEntities []entity.ShortURLEntity) ([]entity.ShortURLEntity, error) {
+			return shortURLEntities, nil
+		})
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	createShortURLBatchResultDomains, err := suite.useCase.CreateShortURLBatch(testCtx, []domain.CreateShortURLBatchDomain{
+		{CorrelationUUID: uuid.NewString(), LongURL: "https://ya.ru", Alias: "old-code"},
+		{CorrelationUUID: uuid.NewString(), LongURL: "https://mail.ru"},
+	})
+
+	suite.Require().NoError(err)
+	suite.Equal("old-code", createShortURLBatchResultDomains[0].ShortURI)
+	suite.NotEqual("old-code", createShortURLBatchResultDomains[1].ShortURI)
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestCreateShortURLBatch_alias_conflict() {
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), "taken").
+		Return(entity.ShortURLEntity{ShortURI: "taken"}, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.CreateShortURLBatch(testCtx, []domain.CreateShortURLBatchDomain{
+		{CorrelationUUID: uuid.NewString(), LongURL: "https://ya.ru", Alias: "taken"},
+	})
+	suite.ErrorIs(err, ErrConflict, "taken alias must be conflict")
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), "twice").
+		Return(entity.ShortURLEntity{}, repository.ErrNotFound)
+
+	_, err = suite.useCase.CreateShortURLBatch(testCtx, []domain.CreateShortURLBatchDomain{
+		{CorrelationUUID: uuid.NewString(), LongURL: "https://ya.ru", Alias: "twice"},
+		{CorrelationUUID: uuid.NewString(), LongURL: "https://mail.ru", Alias: "twice"},
+	})
+	suite.ErrorIs(err, ErrConflict, "alias repeated in batch must be conflict")
+
+	_, err = suite.useCase.CreateShortURLBatch(testCtx, []domain.CreateShortURLBatchDomain{
+		{CorrelationUUID: uuid.NewString(), LongURL: "https://ya.ru", Alias: "not/valid"},
+	})
+	suite.ErrorIs(err, ErrInvalidMetadata, "invalid alias must be invalid metadata")
+}
+
+func (suite *CreateShortURLUseCaseTestSuite) TestIsAliasAvailable() {
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), "free").
+		Return(entity.ShortURLEntity{}, repository.ErrNotFound)
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), "taken").
+		Return(entity.ShortURLEntity{ShortURI: "taken", Deleted: true}, nil)
+
+	available, err := suite.useCase.IsAliasAvailable(context.Background(), "", "free")
+	suite.Require().NoError(err)
+	suite.True(available)
+
+	available, err = suite.useCase.IsAliasAvailable(context.Background(), "", "taken")
+	suite.Require().NoError(err)
+	suite.False(available, "alias of deleted short url must not be available")
+
+	_, err = suite.useCase.IsAliasAvailable(context.Background(), "", "api")
+	suite.ErrorIs(err, ErrInvalidMetadata, "reserved alias must be invalid")
+}
+
 func TestCreateShortURLUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(CreateShortURLUseCaseTestSuite))
 }
@@ -234,6 +471,35 @@ func (suite *GetShortURLUseCaseTestSuite) TestGetShortURL_unexpected_error() {
 	suite.True(errors.Is(err, ErrUnexpected), "err should be ErrUnexpected")
 }
 
+func (suite *GetShortURLUseCaseTestSuite) TestConsumeShortURLClick() {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "consumed"},
+		{name: "exhausted", repoErr: repository.ErrClicksExhausted, expectedErr: ErrClicksExhausted},
+		{name: "not found", repoErr: repository.ErrNotFound, expectedErr: ErrNotFound},
+		{name: "unexpected error", repoErr: repository.ErrUnexpected, expectedErr: ErrUnexpected},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.repositoryMock.EXPECT().
+				ConsumeShortURLClick(gomock.Any(), "abc").
+				Return(tc.repoErr)
+
+			err := suite.useCase.ConsumeShortURLClick(context.Background(), "abc")
+
+			if tc.expectedErr == nil {
+				suite.NoError(err)
+			} else {
+				suite.ErrorIs(err, tc.expectedErr)
+			}
+		})
+	}
+}
+
 func (suite *GetShortURLUseCaseTestSuite) TestGetShortURLsByUserID_success() {
 	testUserID := uuid.NewString()
 	testShortURLEntity := entity.ShortURLEntity{
@@ -275,68 +541,631 @@ func (suite *GetShortURLUseCaseTestSuite) TestGetShortURLsByUserID_unexpected_er
 	suite.True(errors.Is(err, ErrUnexpected), "err should be ErrUnexpected")
 }
 
+func (suite *GetShortURLUseCaseTestSuite) TestGetDeletedShortURLsByUserID_success() {
+	testUserID := uuid.NewString()
+	testDeletedAt := time.Now()
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:      uuid.NewString(),
+		ShortURI:  "abc",
+		LongURL:   "https://ya.ru",
+		UserID:    testUserID,
+		Deleted:   true,
+		DeletedAt: &testDeletedAt,
+	}
+
+	suite.repositoryMock.EXPECT().
+		GetDeletedShortURLsByUserID(gomock.Any(), testUserID).
+		Return([]entity.ShortURLEntity{testShortURLEntity}, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomains, err := suite.useCase.GetDeletedShortURLsByUserID(testCtx, testUserID)
+	if err != nil {
+		suite.Errorf(err, "use_case: error when get deleted short urls by userID")
+	}
+
+	suite.Equal([]domain.ShortURLDomain{domain.ShortURLDomain(testShortURLEntity)}, shortURLDomains)
+}
+
+func (suite *GetShortURLUseCaseTestSuite) TestGetDeletedShortURLsByUserID_unexpected_error() {
+	suite.repositoryMock.EXPECT().
+		GetDeletedShortURLsByUserID(gomock.Any(), gomock.Any()).
+		Return(nil, repository.ErrUnexpected)
+
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomains, err := suite.useCase.GetDeletedShortURLsByUserID(testCtx, testUserID)
+
+	suite.Nilf(shortURLDomains, "shortURLDomains must be nil")
+	suite.True(errors.Is(err, ErrUnexpected), "err should be ErrUnexpected")
+}
+
+func (suite *GetShortURLUseCaseTestSuite) TestGetShortURLsByQuery_next_page() {
+	testUserID := uuid.NewString()
+	testShortURLEntities := []entity.ShortURLEntity{
+		{UUID: uuid.NewString(), ShortURI: "abc", LongURL: "https://ya.ru", UserID: testUserID},
+		{UUID: uuid.NewString(), ShortURI: "cde", LongURL: "https://google.com", UserID: testUserID},
+	}
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLsByQuery(gomock.Any(), entity.ShortURLQueryEntity{
+			UserID:   testUserID,
+			Status:   entity.ShortURLStatusAll,
+			SortBy:   entity.ShortURLSortByOriginalURL,
+			SortDesc: true,
+			Limit:    2,
+		}).
+		Return(testShortURLEntities, 5, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLPageDomain, err := suite.useCase.GetShortURLsByQuery(testCtx, domain.ShortURLQueryDomain{
+		UserID: testUserID,
+		Sort:   "-original_url",
+		Limit:  1,
+	})
+	if err != nil {
+		suite.Errorf(err, "use_case: error when get short urls by query")
+	}
+
+	suite.Equal([]domain.ShortURLDomain{domain.ShortURLDomain(testShortURLEntities[0])}, shortURLPageDomain.ShortURLs)
+	suite.Equal(5, shortURLPageDomain.TotalCount)
+	suite.NotEmpty(shortURLPageDomain.NextCursor, "NextCursor must be set")
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLsByQuery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, query entity.ShortURLQueryEntity) ([]entity.ShortURLEntity, int, error) {
+			suite.Equal(&entity.ShortURLCursorEntity{SortValue: "https://ya.ru", UUID: testShortURLEntities[0].UUID}, query.After)
+			return testShortURLEntities[1:], 5, nil
+		})
+
+	shortURLPageDomain, err = suite.useCase.GetShortURLsByQuery(testCtx, domain.ShortURLQueryDomain{
+		UserID: testUserID,
+		Sort:   "-original_url",
+		Cursor: shortURLPageDomain.NextCursor,
+		Limit:  1,
+	})
+	if err != nil {
+		suite.Errorf(err, "use_case: error when get short urls by query")
+	}
+
+	suite.Equal(1, len(shortURLPageDomain.ShortURLs), "len of ShortURLs must be equal 1")
+	suite.Empty(shortURLPageDomain.NextCursor, "NextCursor must be empty on last page")
+}
+
+func (suite *GetShortURLUseCaseTestSuite) TestGetShortURLsByQuery_invalid_query() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+
+	testCases := []domain.ShortURLQueryDomain{
+		{UserID: testUserID, Sort: "user_id"},
+		{UserID: testUserID, Status: "unknown"},
+		{UserID: testUserID, Cursor: "not a cursor"},
+		{UserID: testUserID, Sort: "short_url", Cursor: encodeShortURLCursor(entity.ShortURLEntity{}, "-short_url", entity.ShortURLSortByShortURL)},
+	}
+
+	for _, tc := range testCases {
+		_, err := suite.useCase.GetShortURLsByQuery(testCtx, tc)
+		suite.True(errors.Is(err, ErrInvalidQuery), "err should be ErrInvalidQuery")
+	}
+}
+
 func TestGetShortURLUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(GetShortURLUseCaseTestSuite))
 }
 
-type DeleteShortURLUseCaseTestSuite struct {
+type UpdateShortURLUseCaseTestSuite struct {
 	suite.Suite
 	repositoryMock *mock_usecase.MockshortURLRepository
-	useCase        *DeleteShortURLUseCase
+	useCase        *UpdateShortURLUseCase
+}
+
+func (suite *UpdateShortURLUseCaseTestSuite) SetupTest() {
+	mockCtrl := gomock.NewController(suite.T())
+	suite.repositoryMock = mock_usecase.NewMockshortURLRepository(mockCtrl)
+
+	suite.useCase = NewUpdateShortURLUseCase(suite.repositoryMock)
+}
+
+func (suite *UpdateShortURLUseCaseTestSuite) TestUpdateShortURL_success() {
+	testUserID := uuid.NewString()
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   testUserID,
+		Title:    "Яндекс",
+		Notes:    "поисковик",
+	}
+	testTitle := "  Яндекс Поиск  "
+	testTags := []string{" Search ", "search", "", "Work"}
+	testRedirectStatus := http.StatusMovedPermanently
+
+	expectedShortURLEntity := testShortURLEntity
+	expectedShortURLEntity.Title = "Яндекс Поиск"
+	expectedShortURLEntity.Tags = []string{"search", "work"}
+	expectedShortURLEntity.RedirectStatus = http.StatusMovedPermanently
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+	suite.repositoryMock.EXPECT().
+		UpdateShortURL(gomock.Any(), gomock.Eq(expectedShortURLEntity)).
+		Return(expectedShortURLEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomain, err := suite.useCase.UpdateShortURL(testCtx, "abc", domain.ShortURLPatchDomain{
+		Title:          &testTitle,
+		Tags:           &testTags,
+		RedirectStatus: &testRedirectStatus,
+	})
+
+	suite.NoError(err, "unexpected error when update shortURL")
+	suite.Equal(domain.ShortURLDomain(expectedShortURLEntity), shortURLDomain)
+}
+
+func (suite *UpdateShortURLUseCaseTestSuite) TestUpdateShortURL_remove_password() {
+	testUserID := uuid.NewString()
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:         uuid.NewString(),
+		ShortURI:     "abc",
+		LongURL:      "https://ya.ru",
+		UserID:       testUserID,
+		PasswordHash: "$2a$10$hash",
+	}
+	testPassword := ""
+
+	expectedShortURLEntity := testShortURLEntity
+	expectedShortURLEntity.PasswordHash = ""
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+	suite.repositoryMock.EXPECT().
+		UpdateShortURL(gomock.Any(), gomock.Eq(expectedShortURLEntity)).
+		Return(expectedShortURLEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomain, err := suite.useCase.UpdateShortURL(testCtx, "abc", domain.ShortURLPatchDomain{Password: &testPassword})
+
+	suite.NoError(err, "unexpected error when update shortURL")
+	suite.Empty(shortURLDomain.PasswordHash, "password must be removed")
+}
+
+func (suite *UpdateShortURLUseCaseTestSuite) TestUpdateShortURL_remove_activity_window() {
+	testUserID := uuid.NewString()
+	testNotBefore := time.Now().Add(time.Hour)
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:      uuid.NewString(),
+		ShortURI:  "abc",
+		LongURL:   "https://ya.ru",
+		UserID:    testUserID,
+		NotBefore: &testNotBefore,
+	}
+
+	expectedShortURLEntity := testShortURLEntity
+	expectedShortURLEntity.NotBefore = nil
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+	suite.repositoryMock.EXPECT().
+		UpdateShortURL(gomock.Any(), gomock.Eq(expectedShortURLEntity)).
+		Return(expectedShortURLEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomain, err := suite.useCase.UpdateShortURL(testCtx, "abc", domain.ShortURLPatchDomain{NotBefore: &time.Time{}})
+
+	suite.NoError(err, "unexpected error when update shortURL")
+	suite.Nil(shortURLDomain.NotBefore, "zero time must remove not before")
+}
+
+func (suite *UpdateShortURLUseCaseTestSuite) TestUpdateShortURL_another_user() {
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   uuid.NewString(),
+	}
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.UpdateShortURL(testCtx, "abc", domain.ShortURLPatchDomain{})
+
+	suite.ErrorIs(err, ErrNotFound, "err should be ErrNotFound")
+}
+
+func (suite *UpdateShortURLUseCaseTestSuite) TestUpdateShortURL_invalid_metadata() {
+	testUserID := uuid.NewString()
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   testUserID,
+	}
+	testTitle := util.RandStringRunes(ShortURLTitleMaxLength + 1)
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	_, err := suite.useCase.UpdateShortURL(testCtx, "abc", domain.ShortURLPatchDomain{Title: &testTitle})
+
+	suite.ErrorIs(err, ErrInvalidMetadata, "err should be ErrInvalidMetadata")
+}
+
+func TestUpdateShortURLUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(UpdateShortURLUseCaseTestSuite))
+}
+
+type DeleteShortURLUseCaseTestSuite struct {
+	suite.Suite
+	repositoryMock          *mock_usecase.MockshortURLRepository
+	deleteJobRepositoryMock *mock_usecase.MockdeleteJobRepository
+	useCase                 *DeleteShortURLUseCase
 }
 
 func (suite *DeleteShortURLUseCaseTestSuite) SetupTest() {
 	mockCtrl := gomock.NewController(suite.T())
 	suite.repositoryMock = mock_usecase.NewMockshortURLRepository(mockCtrl)
+	suite.deleteJobRepositoryMock = mock_usecase.NewMockdeleteJobRepository(mockCtrl)
 
-	suite.useCase = NewDeleteShortURLUseCase(suite.repositoryMock)
+	suite.useCase = NewDeleteShortURLUseCase(suite.repositoryMock, suite.deleteJobRepositoryMock)
 }
 
 func (suite *DeleteShortURLUseCaseTestSuite) TestDeleteShortURLsByShortURIs_success() {
-	suite.repositoryMock.EXPECT().
-		DeleteShortURLsByShortURIs(gomock.Any(), gomock.Any()).
+	suite.deleteJobRepositoryMock.EXPECT().
+		SaveDeleteJob(gomock.Any(), gomock.Any()).
 		Return(nil)
 
 	testUserID := uuid.NewString()
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
-	err := suite.useCase.DeleteShortURLsByShortURIs(testCtx, []string{"abc"})
+	deleteJobDomain, err := suite.useCase.DeleteShortURLsByShortURIs(testCtx, []string{"abc"})
 	if err != nil {
 		suite.Errorf(err, "use_case: error when delete shortURLs by shortURIs")
 	}
+
+	suite.NotEmpty(deleteJobDomain.ID, "deleteJobDomain.ID can not be empty")
+	suite.Equal(testUserID, deleteJobDomain.UserID)
+	suite.Equal(entity.DeleteJobStatusPending, deleteJobDomain.Status)
+	suite.Equal([]string{"abc"}, deleteJobDomain.ShortURIs)
 }
 
 func (suite *DeleteShortURLUseCaseTestSuite) TestDeleteShortURLsByShortURIs_unexpected_error() {
-	suite.repositoryMock.EXPECT().
-		DeleteShortURLsByShortURIs(gomock.Any(), gomock.Any()).
+	suite.deleteJobRepositoryMock.EXPECT().
+		SaveDeleteJob(gomock.Any(), gomock.Any()).
 		Return(repository.ErrUnexpected)
 
 	testUserID := uuid.NewString()
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
-	err := suite.useCase.DeleteShortURLsByShortURIs(testCtx, []string{"abc"})
-	if err != nil && !errors.Is(err, ErrUnexpected) {
-		suite.Errorf(err, "use_case: error when delete shortURLs by shortURIs")
+	_, err := suite.useCase.DeleteShortURLsByShortURIs(testCtx, []string{"abc"})
+
+	suite.NotNilf(err, "err cannot be nil")
+	suite.True(errors.Is(err, ErrUnexpected), "err should be ErrUnexpected")
+}
+
+func (suite *DeleteShortURLUseCaseTestSuite) TestGetDeleteJob_success() {
+	testUserID := uuid.NewString()
+	testDeleteJobEntity := entity.DeleteJobEntity{
+		ID:        uuid.NewString(),
+		UserID:    testUserID,
+		Status:    entity.DeleteJobStatusDone,
+		ShortURIs: []string{"abc"},
+		Results: []entity.DeleteShortURLResultEntity{
+			{ShortURI: "abc", Status: entity.DeleteStatusNotOwner},
+		},
 	}
+
+	suite.deleteJobRepositoryMock.EXPECT().
+		GetDeleteJobByID(gomock.Any(), testDeleteJobEntity.ID).
+		Return(testDeleteJobEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	deleteJobDomain, err := suite.useCase.GetDeleteJob(testCtx, testDeleteJobEntity.ID)
+	if err != nil {
+		suite.Errorf(err, "use_case: error when get delete job")
+	}
+
+	suite.Equal(entity.DeleteJobStatusDone, deleteJobDomain.Status)
+	suite.Equal(
+		[]domain.DeleteShortURLResultDomain{{ShortURI: "abc", Status: entity.DeleteStatusNotOwner}},
+		deleteJobDomain.Results,
+	)
+}
+
+func (suite *DeleteShortURLUseCaseTestSuite) TestGetDeleteJob_another_user() {
+	testDeleteJobEntity := entity.DeleteJobEntity{
+		ID:     uuid.NewString(),
+		UserID: uuid.NewString(),
+		Status: entity.DeleteJobStatusPending,
+	}
+
+	suite.deleteJobRepositoryMock.EXPECT().
+		GetDeleteJobByID(gomock.Any(), testDeleteJobEntity.ID).
+		Return(testDeleteJobEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.GetDeleteJob(testCtx, testDeleteJobEntity.ID)
+
+	suite.NotNilf(err, "err cannot be nil")
+	suite.True(errors.Is(err, ErrNotFound), "err should be ErrNotFound")
+}
+
+func (suite *DeleteShortURLUseCaseTestSuite) TestGetDeleteJob_not_found() {
+	suite.deleteJobRepositoryMock.EXPECT().
+		GetDeleteJobByID(gomock.Any(), gomock.Any()).
+		Return(entity.DeleteJobEntity{}, repository.ErrNotFound)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.GetDeleteJob(testCtx, uuid.NewString())
+
+	suite.NotNilf(err, "err cannot be nil")
+	suite.True(errors.Is(err, ErrNotFound), "err should be ErrNotFound")
+}
+
+func (suite *DeleteShortURLUseCaseTestSuite) TestRestoreShortURLsByShortURIs_success() {
+	suite.repositoryMock.EXPECT().
+		RestoreShortURLsByShortURIs(gomock.Any(), []string{"abc", "cde"}).
+		Return([]entity.RestoreShortURLResultEntity{
+			{ShortURI: "abc", Status: entity.RestoreStatusRestored},
+			{ShortURI: "cde", Status: entity.RestoreStatusNotDeleted},
+		}, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	restoreShortURLResultDomains, err := suite.useCase.RestoreShortURLsByShortURIs(testCtx, []string{"abc", "cde"})
+	if err != nil {
+		suite.Errorf(err, "use_case: error when restore shortURLs by shortURIs")
+	}
+
+	suite.Equal(
+		[]domain.RestoreShortURLResultDomain{
+			{ShortURI: "abc", Status: entity.RestoreStatusRestored},
+			{ShortURI: "cde", Status: entity.RestoreStatusNotDeleted},
+		},
+		restoreShortURLResultDomains,
+	)
+}
+
+func (suite *DeleteShortURLUseCaseTestSuite) TestRestoreShortURLsByShortURIs_unexpected_error() {
+	suite.repositoryMock.EXPECT().
+		RestoreShortURLsByShortURIs(gomock.Any(), gomock.Any()).
+		Return(nil, repository.ErrUnexpected)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.RestoreShortURLsByShortURIs(testCtx, []string{"abc"})
+
+	suite.NotNilf(err, "err cannot be nil")
+	suite.True(errors.Is(err, ErrUnexpected), "err should be ErrUnexpected")
 }
 
 func TestDeleteShortURLUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(DeleteShortURLUseCaseTestSuite))
 }
 
+func TestCheckActivityWindow(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		notBefore   *time.Time
+		notAfter    *time.Time
+		expectedErr error
+	}{
+		{name: "no window"},
+		{name: "started", notBefore: &past},
+		{name: "not yet active", notBefore: &future, expectedErr: ErrNotYetActive},
+		{name: "not expired", notAfter: &future},
+		{name: "expired", notAfter: &past, expectedErr: ErrExpired},
+		{name: "expired at not after", notAfter: &now, expectedErr: ErrExpired},
+		{name: "inside window", notBefore: &past, notAfter: &future},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckActivityWindow(domain.ShortURLDomain{NotBefore: tc.notBefore, NotAfter: tc.notAfter}, now)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestDeleteShortURLUseCase_jobs(t *testing.T) {
+	repo := repository.NewInMemoryShortURLRepository()
+	createShortURLUseCase := NewCreateShortURLUseCase(repo, nil, nil)
+
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	shortURLDomain, err := createShortURLUseCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
+	require.NoError(t, err)
+
+	// задача, созданная до запуска, должна быть выполнена после вызова Start
+	useCase := NewDeleteShortURLUseCase(repo, repo)
+	deleteJobDomain, err := useCase.DeleteShortURLsByShortURIs(testCtx, []string{shortURLDomain.ShortURI, "not_existed"})
+	require.NoError(t, err)
+
+	require.NoError(t, useCase.Start(context.Background()))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, useCase.Shutdown(shutdownCtx))
+
+	deleteJobDomain, err = useCase.GetDeleteJob(testCtx, deleteJobDomain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DeleteJobStatusDone, deleteJobDomain.Status)
+	assert.Equal(
+		t,
+		[]domain.DeleteShortURLResultDomain{
+			{ShortURI: shortURLDomain.ShortURI, Status: entity.DeleteStatusDeleted},
+			{ShortURI: "not_existed", Status: entity.DeleteStatusNotFound},
+		},
+		deleteJobDomain.Results,
+	)
+
+	shortURLEntity, err := repo.GetShortURLByShortURI(testCtx, shortURLDomain.ShortURI)
+	require.NoError(t, err)
+	assert.True(t, shortURLEntity.Deleted, "short URL must be deleted")
+}
+
+func TestDeleteShortURLUseCase_shutdown_full_queue(t *testing.T) {
+	repo := repository.NewInMemoryShortURLRepository()
+	useCase := NewDeleteShortURLUseCase(repo, repo)
+	for i := 0; i < deleteJobQueueSize; i++ {
+		useCase.enqueueDeleteJob(entity.DeleteJobEntity{ID: uuid.NewString()})
+	}
+
+	// постановка в полную очередь не должна блокировать остановку сервиса
+	enqueuedCh := make(chan struct{})
+	go func() {
+		useCase.enqueueDeleteJob(entity.DeleteJobEntity{ID: uuid.NewString()})
+		close(enqueuedCh)
+	}()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, useCase.Shutdown(shutdownCtx))
+
+	select {
+	case <-enqueuedCh:
+	case <-time.After(time.Second):
+		t.Fatal("enqueue into full queue must return after shutdown")
+	}
+}
+
+func TestDeleteShortURLUseCase_full_queue_cancelled_request(t *testing.T) {
+	repo := repository.NewInMemoryShortURLRepository()
+	useCase := NewDeleteShortURLUseCase(repo, repo)
+	for i := 0; i < deleteJobQueueSize; i++ {
+		useCase.enqueueDeleteJob(entity.DeleteJobEntity{ID: uuid.NewString()})
+	}
+
+	// задача сохранена и принята, поэтому отмена запроса при полной очереди не должна ее терять
+	testCtx, cancel := context.WithCancel(context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString()))
+	cancel()
+	deleteJobDomain, err := useCase.DeleteShortURLsByShortURIs(testCtx, []string{"abc"})
+	require.NoError(t, err)
+
+	var lastJobID string
+	for i := 0; i <= deleteJobQueueSize; i++ {
+		select {
+		case deleteJobEntity := <-useCase.jobCh:
+			lastJobID = deleteJobEntity.ID
+		case <-time.After(time.Second):
+			t.Fatal("delete job must be enqueued after queue has free space")
+		}
+	}
+	assert.Equal(t, deleteJobDomain.ID, lastJobID)
+}
+
+func TestPreviewShortURLUseCase(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	fetcherMock := mock_usecase.NewMockpreviewFetcher(mockCtrl)
+
+	repo := repository.NewInMemoryShortURLRepository()
+	previewUseCase := NewPreviewShortURLUseCase(repo, fetcherMock)
+	previewUseCase.retryDelay = time.Millisecond
+	previewUseCase.Start()
+	defer previewUseCase.Shutdown(context.Background())
+
+	testLongTitle := strings.Repeat("я", ShortURLTitleMaxLength+1)
+	gomock.InOrder(
+		fetcherMock.EXPECT().
+			Fetch(gomock.Any(), gomock.Eq("https://ya.ru")).
+			Return(preview.Preview{}, preview.ErrTemporary),
+		fetcherMock.EXPECT().
+			Fetch(gomock.Any(), gomock.Eq("https://ya.ru")).
+			Return(preview.Preview{Title: testLongTitle, Description: "Поиск", ImageURL: "https://ya.ru/logo.png"}, nil),
+	)
+	fetcherMock.EXPECT().
+		Fetch(gomock.Any(), gomock.Eq("https://mail.ru")).
+		Return(preview.Preview{}, preview.ErrNotHTML).
+		Times(1)
+
+	createShortURLUseCase := NewCreateShortURLUseCase(repo, previewUseCase, nil)
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	shortURLDomain, err := createShortURLUseCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
+	require.NoError(t, err)
+	notHTMLShortURLDomain, err := createShortURLUseCase.CreateShortURL(testCtx, "https://mail.ru", domain.ShortURLMetadataDomain{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		shortURLEntity, err := repo.GetShortURLByShortURI(testCtx, shortURLDomain.ShortURI)
+		return err == nil && shortURLEntity.PreviewFetchedAt != nil
+	}, time.Second, 10*time.Millisecond, "preview must be fetched after retry")
+
+	shortURLEntity, err := repo.GetShortURLByShortURI(testCtx, shortURLDomain.ShortURI)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("я", ShortURLTitleMaxLength), shortURLEntity.PreviewTitle, "preview title must be truncated")
+	assert.Equal(t, "Поиск", shortURLEntity.PreviewDescription)
+	assert.Equal(t, "https://ya.ru/logo.png", shortURLEntity.PreviewImageURL)
+
+	require.NoError(t, previewUseCase.Shutdown(context.Background()))
+	notHTMLShortURLEntity, err := repo.GetShortURLByShortURI(testCtx, notHTMLShortURLDomain.ShortURI)
+	require.NoError(t, err)
+	assert.Nil(t, notHTMLShortURLEntity.PreviewFetchedAt, "preview must not be saved after permanent error")
+}
+
 func BenchmarkCreateShortURLUseCase_CreateShortURL(b *testing.B) {
 	repo := repository.NewInMemoryShortURLRepository()
-	useCase := NewCreateShortURLUseCase(repo)
+	useCase := NewCreateShortURLUseCase(repo, nil, nil)
 
 	testUserID := uuid.NewString()
 	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
 
 	for i := 0; i < b.N; i++ {
-		_, err := useCase.CreateShortURL(testCtx, "https://ya.ru")
+		_, err := useCase.CreateShortURL(testCtx, "https://ya.ru", domain.ShortURLMetadataDomain{})
 		if err != nil {
 			b.Fatal(err)
 		}
 	}
 }
 
+type PurgeShortURLUseCaseTestSuite struct {
+	suite.Suite
+	repositoryMock *mock_usecase.MockpurgeRepository
+	useCase        *PurgeShortURLUseCase
+}
+
+func (suite *PurgeShortURLUseCaseTestSuite) SetupTest() {
+	mockCtrl := gomock.NewController(suite.T())
+	suite.repositoryMock = mock_usecase.NewMockpurgeRepository(mockCtrl)
+
+	suite.useCase = NewPurgeShortURLUseCase(suite.repositoryMock, time.Hour)
+}
+
+func (suite *PurgeShortURLUseCaseTestSuite) TestPurgeDeletedShortURLs_success() {
+	suite.repositoryMock.EXPECT().
+		PurgeDeletedShortURLs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, deletedBefore time.Time) (int, error) {
+			suite.WithinDuration(time.Now().Add(-time.Hour), deletedBefore, time.Minute)
+			return 3, nil
+		})
+
+	purgedCount, err := suite.useCase.PurgeDeletedShortURLs(context.Background())
+	if err != nil {
+		suite.Errorf(err, "use_case: error when purge deleted shortURLs")
+	}
+
+	suite.Equal(3, purgedCount, "purgedCount must be equal 3")
+}
+
+func (suite *PurgeShortURLUseCaseTestSuite) TestPurgeDeletedShortURLs_unexpected_error() {
+	suite.repositoryMock.EXPECT().
+		PurgeDeletedShortURLs(gomock.Any(), gomock.Any()).
+		Return(0, repository.ErrUnexpected)
+
+	_, err := suite.useCase.PurgeDeletedShortURLs(context.Background())
+
+	suite.True(errors.Is(err, ErrUnexpected), "err should be ErrUnexpected")
+}
+
+func TestPurgeShortURLUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PurgeShortURLUseCaseTestSuite))
+}
+
 type StatsUseCaseTestSuite struct {
 	suite.Suite
 	repositoryMock *mock_usecase.MockstatsRepository
@@ -386,3 +1215,372 @@ func (suite *StatsUseCaseTestSuite) TestGetStats_unexpected_error() {
 func TestStatsUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(StatsUseCaseTestSuite))
 }
+
+type SplitUseCaseTestSuite struct {
+	suite.Suite
+	repositoryMock      *mock_usecase.MockshortURLRepository
+	clickRepositoryMock *mock_usecase.MocksplitClickRepository
+	useCase             *SplitUseCase
+}
+
+func (suite *SplitUseCaseTestSuite) SetupTest() {
+	mockCtrl := gomock.NewController(suite.T())
+	suite.repositoryMock = mock_usecase.NewMockshortURLRepository(mockCtrl)
+	suite.clickRepositoryMock = mock_usecase.NewMocksplitClickRepository(mockCtrl)
+
+	suite.useCase = NewSplitUseCase(suite.repositoryMock, suite.clickRepositoryMock)
+}
+
+func (suite *SplitUseCaseTestSuite) TestRecordSplitClick_unexpected_error() {
+	suite.clickRepositoryMock.EXPECT().
+		IncrementSplitClicks(gomock.Any(), gomock.Eq("abc"), gomock.Eq("a")).
+		Return(repository.ErrUnexpected)
+
+	err := suite.useCase.RecordSplitClick(context.Background(), "abc", "a")
+
+	suite.ErrorIs(err, ErrUnexpected, "err should be ErrUnexpected")
+}
+
+func (suite *SplitUseCaseTestSuite) TestGetSplitStats_success() {
+	testUserID := uuid.NewString()
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   testUserID,
+		SplitVariants: []split.Variant{
+			{Name: "b", URL: "https://ya.ru/b", Weight: 30},
+			{Name: "a", URL: "https://ya.ru/a", Weight: 70},
+		},
+	}
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+	suite.clickRepositoryMock.EXPECT().
+		GetSplitClicks(gomock.Any(), gomock.Eq("abc")).
+		Return(map[string]int64{"a": 5, "d": 2, "c": 1}, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	splitStats, err := suite.useCase.GetSplitStats(testCtx, "abc")
+
+	suite.NoError(err, "unexpected error when get split stats")
+	suite.Equal(
+		[]domain.SplitVariantStatsDomain{
+			{Name: "b", URL: "https://ya.ru/b", Weight: 30, Clicks: 0},
+			{Name: "a", URL: "https://ya.ru/a", Weight: 70, Clicks: 5},
+			{Name: "c", Clicks: 1},
+			{Name: "d", Clicks: 2},
+		},
+		splitStats,
+	)
+}
+
+func (suite *SplitUseCaseTestSuite) TestGetSplitStats_another_user() {
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   uuid.NewString(),
+	}
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.GetSplitStats(testCtx, "abc")
+
+	suite.ErrorIs(err, ErrNotFound, "err should be ErrNotFound")
+}
+
+func TestSplitUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SplitUseCaseTestSuite))
+}
+
+type CountryStatsUseCaseTestSuite struct {
+	suite.Suite
+	repositoryMock      *mock_usecase.MockshortURLRepository
+	clickRepositoryMock *mock_usecase.MockcountryClickRepository
+	useCase             *CountryStatsUseCase
+}
+
+func (suite *CountryStatsUseCaseTestSuite) SetupTest() {
+	mockCtrl := gomock.NewController(suite.T())
+	suite.repositoryMock = mock_usecase.NewMockshortURLRepository(mockCtrl)
+	suite.clickRepositoryMock = mock_usecase.NewMockcountryClickRepository(mockCtrl)
+
+	suite.useCase = NewCountryStatsUseCase(suite.repositoryMock, suite.clickRepositoryMock)
+}
+
+func (suite *CountryStatsUseCaseTestSuite) TestRecordCountryClick_unknown_country() {
+	suite.clickRepositoryMock.EXPECT().
+		IncrementCountryClicks(gomock.Any(), gomock.Eq("abc"), gomock.Eq(UnknownCountry)).
+		Return(nil)
+
+	err := suite.useCase.RecordCountryClick(context.Background(), "abc", "")
+
+	suite.NoError(err, "unexpected error when record country click")
+}
+
+func (suite *CountryStatsUseCaseTestSuite) TestRecordCountryClick_unexpected_error() {
+	suite.clickRepositoryMock.EXPECT().
+		IncrementCountryClicks(gomock.Any(), gomock.Eq("abc"), gomock.Eq("SE")).
+		Return(repository.ErrUnexpected)
+
+	err := suite.useCase.RecordCountryClick(context.Background(), "abc", "SE")
+
+	suite.ErrorIs(err, ErrUnexpected, "err should be ErrUnexpected")
+}
+
+func (suite *CountryStatsUseCaseTestSuite) TestGetCountryStats_success() {
+	testUserID := uuid.NewString()
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   testUserID,
+	}
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+	suite.clickRepositoryMock.EXPECT().
+		GetCountryClicks(gomock.Any(), gomock.Eq("abc")).
+		Return(map[string]int64{"GB": 2, "SE": 5, UnknownCountry: 2}, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	countryStats, err := suite.useCase.GetCountryStats(testCtx, "abc")
+
+	suite.NoError(err, "unexpected error when get country stats")
+	suite.Equal(
+		[]domain.CountryStatsDomain{
+			{Country: "SE", Clicks: 5},
+			{Country: "GB", Clicks: 2},
+			{Country: UnknownCountry, Clicks: 2},
+		},
+		countryStats,
+	)
+}
+
+func (suite *CountryStatsUseCaseTestSuite) TestGetCountryStats_another_user() {
+	testShortURLEntity := entity.ShortURLEntity{
+		UUID:     uuid.NewString(),
+		ShortURI: "abc",
+		LongURL:  "https://ya.ru",
+		UserID:   uuid.NewString(),
+	}
+
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(testShortURLEntity, nil)
+
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	_, err := suite.useCase.GetCountryStats(testCtx, "abc")
+
+	suite.ErrorIs(err, ErrNotFound, "err should be ErrNotFound")
+}
+
+func TestCountryStatsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CountryStatsUseCaseTestSuite))
+}
+
+type UnlockShortURLUseCaseTestSuite struct {
+	suite.Suite
+	repositoryMock     *mock_usecase.MockshortURLRepository
+	useCase            *UnlockShortURLUseCase
+	testShortURLEntity entity.ShortURLEntity
+}
+
+func (suite *UnlockShortURLUseCaseTestSuite) SetupTest() {
+	mockCtrl := gomock.NewController(suite.T())
+	suite.repositoryMock = mock_usecase.NewMockshortURLRepository(mockCtrl)
+
+	suite.useCase = NewUnlockShortURLUseCase(suite.repositoryMock)
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	suite.Require().NoError(err, "unexpected error when hash password")
+	suite.testShortURLEntity = entity.ShortURLEntity{
+		UUID:         uuid.NewString(),
+		ShortURI:     "abc",
+		LongURL:      "https://ya.ru",
+		UserID:       uuid.NewString(),
+		PasswordHash: string(passwordHash),
+	}
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("abc")).
+		Return(suite.testShortURLEntity, nil).
+		AnyTimes()
+}
+
+func (suite *UnlockShortURLUseCaseTestSuite) TestUnlockShortURL_success() {
+	shortURLDomain, err := suite.useCase.UnlockShortURL(context.Background(), "abc", "secret", "127.0.0.1")
+
+	suite.NoError(err, "unexpected error when unlock shortURL")
+	suite.Equal(domain.ShortURLDomain(suite.testShortURLEntity), shortURLDomain)
+}
+
+func (suite *UnlockShortURLUseCaseTestSuite) TestUnlockShortURL_not_protected() {
+	testShortURLEntity := entity.ShortURLEntity{UUID: uuid.NewString(), ShortURI: "cde", LongURL: "https://ya.ru"}
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("cde")).
+		Return(testShortURLEntity, nil)
+
+	shortURLDomain, err := suite.useCase.UnlockShortURL(context.Background(), "cde", "", "127.0.0.1")
+
+	suite.NoError(err, "unexpected error when unlock shortURL")
+	suite.Equal(domain.ShortURLDomain(testShortURLEntity), shortURLDomain)
+}
+
+func (suite *UnlockShortURLUseCaseTestSuite) TestUnlockShortURL_password_required() {
+	_, err := suite.useCase.UnlockShortURL(context.Background(), "abc", "", "127.0.0.1")
+
+	suite.ErrorIs(err, ErrPasswordRequired, "err should be ErrPasswordRequired")
+}
+
+func (suite *UnlockShortURLUseCaseTestSuite) TestUnlockShortURL_too_many_attempts() {
+	for i := 0; i < PasswordClientAttemptsMax; i++ {
+		_, err := suite.useCase.UnlockShortURL(context.Background(), "abc", "wrong", "127.0.0.1")
+		suite.ErrorIs(err, ErrInvalidPassword, "err should be ErrInvalidPassword")
+	}
+
+	_, err := suite.useCase.UnlockShortURL(context.Background(), "abc", "secret", "127.0.0.1")
+	suite.ErrorIs(err, ErrTooManyAttempts, "attempts from blocked client must be rejected even with valid password")
+
+	_, err = suite.useCase.UnlockShortURL(context.Background(), "abc", "secret", "127.0.0.2")
+	suite.NoError(err, "attempts from another client must not be blocked")
+}
+
+func (suite *UnlockShortURLUseCaseTestSuite) TestUnlockShortURL_not_found() {
+	suite.repositoryMock.EXPECT().
+		GetShortURLByShortURI(gomock.Any(), gomock.Eq("not_existed_shortURL")).
+		Return(entity.ShortURLEntity{}, repository.ErrNotFound)
+
+	_, err := suite.useCase.UnlockShortURL(context.Background(), "not_existed_shortURL", "secret", "127.0.0.1")
+
+	suite.ErrorIs(err, ErrNotFound, "err should be ErrNotFound")
+}
+
+func TestUnlockShortURLUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(UnlockShortURLUseCaseTestSuite))
+}
+
+type UserSettingsUseCaseTestSuite struct {
+	suite.Suite
+	repositoryMock *mock_usecase.MockuserSettingsRepository
+	useCase        *UserSettingsUseCase
+}
+
+func (suite *UserSettingsUseCaseTestSuite) SetupTest() {
+	mockCtrl := gomock.NewController(suite.T())
+	suite.repositoryMock = mock_usecase.NewMockuserSettingsRepository(mockCtrl)
+
+	suite.useCase = NewUserSettingsUseCase(suite.repositoryMock)
+}
+
+func (suite *UserSettingsUseCaseTestSuite) TestUpdateUserSettings_success() {
+	testUserID := uuid.NewString()
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, testUserID)
+	suite.repositoryMock.EXPECT().
+		SaveUserSettings(gomock.Any(), gomock.Eq(entity.UserSettingsEntity{UserID: testUserID, FallbackURL: "https://example.com"})).
+		Return(nil)
+
+	userSettings, err := suite.useCase.UpdateUserSettings(testCtx, domain.UserSettingsDomain{FallbackURL: " https://example.com "})
+
+	suite.NoError(err, "unexpected error when update user settings")
+	suite.Equal(testUserID, userSettings.UserID)
+	suite.Equal("https://example.com", userSettings.FallbackURL)
+}
+
+func (suite *UserSettingsUseCaseTestSuite) TestUpdateUserSettings_invalid_fallback_url() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+
+	_, err := suite.useCase.UpdateUserSettings(testCtx, domain.UserSettingsDomain{FallbackURL: "ftp://example.com"})
+
+	suite.ErrorIs(err, ErrInvalidSettings, "err should be ErrInvalidSettings")
+}
+
+func (suite *UserSettingsUseCaseTestSuite) TestGetUserSettings_unexpected_error() {
+	testCtx := context.WithValue(context.Background(), common.UserIDContextKey, uuid.NewString())
+	suite.repositoryMock.EXPECT().
+		GetUserSettings(gomock.Any(), gomock.Any()).
+		Return(entity.UserSettingsEntity{}, repository.ErrUnexpected)
+
+	_, err := suite.useCase.GetUserSettings(testCtx)
+
+	suite.ErrorIs(err, ErrUnexpected, "err should be ErrUnexpected")
+}
+
+func TestUserSettingsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(UserSettingsUseCaseTestSuite))
+}
+
+func TestFallbackUseCase_GetFallbackURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		shortURL     domain.ShortURLDomain
+		userSettings *entity.UserSettingsEntity
+		repoErr      error
+		defaultURL   string
+		want         string
+		wantErr      error
+	}{
+		{
+			name:       "short url fallback",
+			shortURL:   domain.ShortURLDomain{UserID: "user", FallbackURL: "https://link.example.com"},
+			defaultURL: "https://default.example.com",
+			want:       "https://link.example.com?reason=deleted",
+		},
+		{
+			name:         "user fallback",
+			shortURL:     domain.ShortURLDomain{UserID: "user"},
+			userSettings: &entity.UserSettingsEntity{UserID: "user", FallbackURL: "https://user.example.com"},
+			defaultURL:   "https://default.example.com",
+			want:         "https://user.example.com?reason=deleted",
+		},
+		{
+			name:         "default fallback",
+			shortURL:     domain.ShortURLDomain{UserID: "user"},
+			userSettings: &entity.UserSettingsEntity{UserID: "user"},
+			defaultURL:   "https://default.example.com",
+			want:         "https://default.example.com?reason=deleted",
+		},
+		{
+			name:         "no fallback",
+			shortURL:     domain.ShortURLDomain{UserID: "user"},
+			userSettings: &entity.UserSettingsEntity{UserID: "user"},
+			want:         "",
+		},
+		{
+			name:       "repository error",
+			shortURL:   domain.ShortURLDomain{UserID: "user"},
+			repoErr:    repository.ErrUnexpected,
+			defaultURL: "https://default.example.com",
+			wantErr:    ErrUnexpected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := mock_usecase.NewMockuserSettingsRepository(gomock.NewController(t))
+			if tt.userSettings != nil || tt.repoErr != nil {
+				userSettings := entity.UserSettingsEntity{}
+				if tt.userSettings != nil {
+					userSettings = *tt.userSettings
+				}
+				repositoryMock.EXPECT().
+					GetUserSettings(gomock.Any(), gomock.Eq("user")).
+					Return(userSettings, tt.repoErr)
+			}
+			useCase := NewFallbackUseCase(repositoryMock, tt.defaultURL)
+
+			got, err := useCase.GetFallbackURL(context.Background(), tt.shortURL, "deleted")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,14 +3,18 @@ package common
 import (
 	"crypto/md5"
 	"encoding/hex"
+	"net"
+	"net/netip"
 )
 
 // StringContextKey тип для ключей контекста приложения Shortener
 type StringContextKey string
 
 // UserIDContextKey - ключ для хранения идентификатора пользователя
+// ClientAddrContextKey - ключ для хранения IP-адреса клиента (netip.Addr)
 const (
-	UserIDContextKey StringContextKey = "userID"
+	UserIDContextKey     StringContextKey = "userID"
+	ClientAddrContextKey StringContextKey = "clientAddr"
 )
 
 func CheckSignature(toCheck, toCheckSignature, salt string) bool {
@@ -19,3 +23,20 @@ func CheckSignature(toCheck, toCheckSignature, salt string) bool {
 
 	return toCheckExpectedSignature == toCheckSignature
 }
+
+// ResolveClientAddr возвращает IP-адрес клиента
+//
+// Адрес realIP из заголовка X-Real-IP учитывается, только если соединение remoteAddr установлено
+// из доверенной подсети trustedSubnet (с прокси), иначе возвращается адрес соединения.
+func ResolveClientAddr(remoteAddr netip.Addr, realIP string, trustedSubnet *net.IPNet) netip.Addr {
+	remoteAddr = remoteAddr.Unmap()
+	if trustedSubnet == nil || !remoteAddr.IsValid() || !trustedSubnet.Contains(remoteAddr.AsSlice()) {
+		return remoteAddr
+	}
+
+	if addr, err := netip.ParseAddr(realIP); err == nil {
+		return addr.Unmap()
+	}
+
+	return remoteAddr
+}
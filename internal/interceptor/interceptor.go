@@ -10,8 +10,10 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"net"
+	"net/netip"
 	"slices"
 	"strings"
 )
@@ -24,26 +26,7 @@ func UserIDInterceptor(salt string, acceptedMethods []string) grpc.UnaryServerIn
 		if slices.Contains(acceptedMethods, method) {
 			log.Infow("interceptor: calling UserIDInterceptor", "method", method)
 
-			var userID, userIDSignature string
-			var isValidSignature bool
-
-			userIDMetadata := metadata.ValueFromIncomingContext(ctx, "user-id")
-			userIDSignatureMetadata := metadata.ValueFromIncomingContext(ctx, "user-id-signature")
-
-			if len(userIDMetadata) == 1 && len(userIDSignatureMetadata) == 1 {
-				userID = userIDMetadata[0]
-				userIDSignature = userIDSignatureMetadata[0]
-				isValidSignature = common.CheckSignature(userID, userIDSignature, salt)
-			}
-
-			if len(userIDMetadata) != 1 || len(userIDSignatureMetadata) != 1 || !isValidSignature {
-				log.Infow("interceptor: 'user-id' metadata not found or not valid")
-
-				userID = uuid.NewString()
-				userIDSignatureBytes := md5.Sum([]byte(userID + salt))
-				userIDSignature = hex.EncodeToString(userIDSignatureBytes[:])
-
-			}
+			userID, userIDSignature := resolveUserID(ctx, salt)
 
 			ctx = context.WithValue(ctx, common.UserIDContextKey, userID)
 			res, err := handler(ctx, req)
@@ -57,6 +40,65 @@ func UserIDInterceptor(salt string, acceptedMethods []string) grpc.UnaryServerIn
 	}
 }
 
+// UserIDStreamInterceptor - UserIDInterceptor для потоковых методов
+//
+// Метаданные "user-id" и "user-id-signature" отправляются клиенту до первого сообщения потока
+func UserIDStreamInterceptor(salt string, acceptedMethods []string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		method := info.FullMethod[strings.LastIndexByte(info.FullMethod, '/')+1:]
+		if slices.Contains(acceptedMethods, method) {
+			log.Infow("interceptor: calling UserIDStreamInterceptor", "method", method)
+
+			userID, userIDSignature := resolveUserID(ss.Context(), salt)
+			if err := ss.SetHeader(metadata.Pairs("user-id", userID, "user-id-signature", userIDSignature)); err != nil {
+				log.Warnw("interceptor: failed to set 'user-id' header", "error", err)
+			}
+
+			return handler(srv, &userIDServerStream{
+				ServerStream: ss,
+				ctx:          context.WithValue(ss.Context(), common.UserIDContextKey, userID),
+			})
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// userIDServerStream - поток с идентификатором пользователя в контексте
+type userIDServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *userIDServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// resolveUserID возвращает идентификатор пользователя и подпись из метаданных запроса,
+// при отсутствии или неверной подписи - нового пользователя
+func resolveUserID(ctx context.Context, salt string) (userID string, userIDSignature string) {
+	var isValidSignature bool
+
+	userIDMetadata := metadata.ValueFromIncomingContext(ctx, "user-id")
+	userIDSignatureMetadata := metadata.ValueFromIncomingContext(ctx, "user-id-signature")
+
+	if len(userIDMetadata) == 1 && len(userIDSignatureMetadata) == 1 {
+		userID = userIDMetadata[0]
+		userIDSignature = userIDSignatureMetadata[0]
+		isValidSignature = common.CheckSignature(userID, userIDSignature, salt)
+	}
+
+	if !isValidSignature {
+		log.Infow("interceptor: 'user-id' metadata not found or not valid")
+
+		userID = uuid.NewString()
+		userIDSignatureBytes := md5.Sum([]byte(userID + salt))
+		userIDSignature = hex.EncodeToString(userIDSignatureBytes[:])
+	}
+
+	return userID, userIDSignature
+}
+
 func AuthByUserIDInterceptor(salt string, acceptedMethods []string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
 		method := info.FullMethod[strings.LastIndexByte(info.FullMethod, '/')+1:]
@@ -110,3 +152,30 @@ func CheckSubnetInterceptor(trustedSubnet *net.IPNet, acceptedMethods []string)
 		return handler(ctx, req)
 	}
 }
+
+// ClientAddrInterceptor сохраняет в контексте IP-адрес клиента по ключу common.ClientAddrContextKey
+//
+// Метаданные x-real-ip учитываются только для запросов из доверенной подсети trustedSubnet,
+// иначе клиент мог бы подменить свой адрес.
+func ClientAddrInterceptor(trustedSubnet *net.IPNet, acceptedMethods []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		method := info.FullMethod[strings.LastIndexByte(info.FullMethod, '/')+1:]
+		if slices.Contains(acceptedMethods, method) {
+			var remoteAddr netip.Addr
+			if p, ok := peer.FromContext(ctx); ok {
+				if addrPort, err := netip.ParseAddrPort(p.Addr.String()); err == nil {
+					remoteAddr = addrPort.Addr()
+				}
+			}
+
+			var xRealIP string
+			if xRealIPMetadata := metadata.ValueFromIncomingContext(ctx, "x-real-ip"); len(xRealIPMetadata) == 1 {
+				xRealIP = xRealIPMetadata[0]
+			}
+
+			ctx = context.WithValue(ctx, common.ClientAddrContextKey, common.ResolveClientAddr(remoteAddr, xRealIP, trustedSubnet))
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/netip"
 	"strings"
 	"time"
 
@@ -285,3 +286,21 @@ func CheckSubnetMiddleware(trustedSubnet *net.IPNet, next func(w http.ResponseWr
 		next(w, r)
 	}
 }
+
+// ClientAddrMiddleware возвращает middleware, сохраняющий в контексте запроса IP-адрес клиента
+// по ключу common.ClientAddrContextKey
+//
+// Заголовок X-Real-IP учитывается только для запросов из доверенной подсети trustedSubnet,
+// иначе клиент мог бы подменить свой адрес.
+func ClientAddrMiddleware(trustedSubnet *net.IPNet, next func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var remoteAddr netip.Addr
+		if addrPort, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+			remoteAddr = addrPort.Addr()
+		}
+
+		clientAddr := common.ResolveClientAddr(remoteAddr, r.Header.Get("X-Real-IP"), trustedSubnet)
+		r = r.WithContext(context.WithValue(r.Context(), common.ClientAddrContextKey, clientAddr))
+		next(w, r)
+	}
+}